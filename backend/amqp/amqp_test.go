@@ -0,0 +1,223 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// broker is an in-memory driver with a single queue
+type broker struct {
+	mu         sync.Mutex
+	dials      int
+	failDials  int
+	nack       bool
+	published  []string
+	conn       *conn
+	deliveries chan Delivery
+	acks       map[uint64]string
+}
+
+func newBroker() *broker {
+	return &broker{acks: make(map[uint64]string)}
+}
+
+func (b *broker) dial(url string) (Connection, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dials++
+	if b.dials <= b.failDials {
+		return nil, errors.New("connection refused")
+	}
+	b.conn = &conn{b: b}
+	return b.conn, nil
+}
+
+// lose closes the connection with an error
+func (b *broker) lose() {
+	b.mu.Lock()
+	c := b.conn
+	c.lost = true
+	b.mu.Unlock()
+	c.notify <- errors.New("connection reset")
+	close(c.notify)
+}
+
+func (b *broker) Ack(tag uint64, multiple bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.acks[tag] = "ack"
+	return nil
+}
+
+func (b *broker) Nack(tag uint64, multiple, requeue bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if requeue {
+		b.acks[tag] = "requeue"
+	} else {
+		b.acks[tag] = "reject"
+	}
+	return nil
+}
+
+type conn struct {
+	b      *broker
+	lost   bool
+	notify chan error
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Channel() (Channel, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	if c.lost {
+		return nil, errors.New("connection closed")
+	}
+	return &channel{b: c.b, conn: c}, nil
+}
+
+func (c *conn) NotifyClose(ch chan error) chan error {
+	c.notify = ch
+	return ch
+}
+
+type channel struct {
+	b        *broker
+	conn     *conn
+	confirms chan Confirmation
+	tag      uint64
+}
+
+func (ch *channel) Qos(prefetchCount int) error { return nil }
+func (ch *channel) Confirm() error              { return nil }
+func (ch *channel) Close() error                { return nil }
+
+func (ch *channel) NotifyPublish(c chan Confirmation) chan Confirmation {
+	ch.confirms = c
+	return c
+}
+
+func (ch *channel) Publish(exchange, key string, msg Publishing) error {
+	ch.b.mu.Lock()
+	defer ch.b.mu.Unlock()
+	if ch.conn.lost {
+		return errors.New("channel closed")
+	}
+	ch.b.published = append(ch.b.published, string(msg.Body))
+	ch.tag++
+	go func(c Confirmation) { ch.confirms <- c }(Confirmation{DeliveryTag: ch.tag, Ack: !ch.b.nack})
+	return nil
+}
+
+func (ch *channel) Consume(queue, consumer string) (<-chan Delivery, error) {
+	ch.b.mu.Lock()
+	defer ch.b.mu.Unlock()
+	ch.b.deliveries = make(chan Delivery)
+	return ch.b.deliveries, nil
+}
+
+func TestPublish(t *testing.T) {
+	b := newBroker()
+	b.failDials = 1
+	c := NewClient(b.dial)
+	p := NewPublisher(c)
+	ctx := context.Background()
+
+	// the failed dial is retried
+	assert.NoError(t, p.Publish(ctx, "events", "a", Publishing{Body: []byte("a")}))
+
+	b.mu.Lock()
+	b.nack = true
+	b.mu.Unlock()
+	assert.Equal(t, ErrNacked, p.Publish(ctx, "events", "b", Publishing{Body: []byte("b")}))
+
+	// the lost connection is dialed again
+	b.mu.Lock()
+	b.nack = false
+	b.mu.Unlock()
+	b.lose()
+	assert.NoError(t, p.Publish(ctx, "events", "c", Publishing{Body: []byte("c")}))
+	assert.NoError(t, p.Close())
+	assert.NoError(t, c.Close())
+
+	assert.Equal(t, 3, b.dials)
+	assert.Equal(t, []string{"a", "b", "c"}, b.published)
+	assert.Equal(t, ErrClosed, c.HealthCheck(ctx))
+}
+
+func TestConsume(t *testing.T) {
+	b := newBroker()
+	c := NewClient(b.dial)
+	cons := NewConsumer(c, "jobs", 2)
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cons.Run(ctx, func(ctx context.Context, d Delivery) error {
+			mu.Lock()
+			handled = append(handled, string(d.Body))
+			mu.Unlock()
+			switch string(d.Body) {
+			case "fail":
+				return errors.New("failed")
+			case "panic":
+				panic("panic")
+			}
+			return nil
+		})
+	}()
+
+	deliver := func(tag uint64, body string, redelivered bool) {
+		for {
+			b.mu.Lock()
+			deliveries := b.deliveries
+			b.mu.Unlock()
+			if deliveries != nil {
+				deliveries <- Delivery{Acknowledger: b, DeliveryTag: tag, Body: []byte(body), Redelivered: redelivered}
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	deliver(1, "ok", false)
+	deliver(2, "fail", false)
+	deliver(3, "panic", true)
+	deliver(4, "ok", false)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected consumer to shut down")
+	}
+
+	assert.Len(t, handled, 4)
+	assert.Equal(t, map[uint64]string{1: "ack", 2: "requeue", 3: "reject", 4: "ack"}, b.acks)
+}
+
+func TestHeaderCarrier(t *testing.T) {
+	h := headerCarrier{"content-encoding": "gzip", "x-retries": int64(2)}
+	h.Set("uber-trace-id", "1")
+
+	read := make(map[string]string)
+	assert.NoError(t, h.ForeachKey(func(key, val string) error {
+		read[key] = val
+		return nil
+	}))
+	assert.Equal(t, map[string]string{"content-encoding": "gzip", "uber-trace-id": "1"}, read)
+}
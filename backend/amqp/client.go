@@ -0,0 +1,131 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package amqp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// Client manages the connection to the broker. The connection is dialed
+// on first use and dialed again after it was lost.
+type Client struct {
+	// URL of the broker, defaults to AMQP_URL
+	URL string
+
+	dial   Dialer
+	mu     sync.Mutex
+	conn   Connection
+	closed bool
+}
+
+// NewClient returns a client for the broker at AMQP_URL,
+// connections are opened using the passed dialer
+func NewClient(dial Dialer) *Client {
+	mustSetup()
+	return &Client{URL: cfg.URL, dial: dial}
+}
+
+// Channel opens a new channel, the connection is dialed if needed
+func (c *Client) Channel() (Channel, error) {
+	conn, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		// the connection might be broken without being closed yet
+		c.reset(conn)
+		return nil, err
+	}
+	return ch, nil
+}
+
+// HealthCheck opens and closes a channel, use it
+// as readiness check (see health.RegisterCheck)
+func (c *Client) HealthCheck(ctx context.Context) error {
+	ch, err := c.Channel()
+	if err != nil {
+		return err
+	}
+	return ch.Close()
+}
+
+// Close closes the connection, the client can't be used afterwards
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn.Close()
+}
+
+// openChannel opens a channel, if that fails it is retried with an
+// exponential backoff of at most AMQP_RECONNECT_INTERVAL until ctx is done
+func (c *Client) openChannel(ctx context.Context) (Channel, error) {
+	backoff := 100 * time.Millisecond
+	for {
+		ch, err := c.Channel()
+		if err == nil || err == ErrClosed {
+			return ch, err
+		}
+		log.Ctx(ctx).Warn().Err(err).Dur("retry_in", backoff).Msg("Failed to open amqp channel")
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > cfg.ReconnectInterval {
+			backoff = cfg.ReconnectInterval
+		}
+	}
+}
+
+func (c *Client) connection() (Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.conn != nil {
+		return c.conn, nil
+	}
+
+	conn, err := c.dial(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	go c.watch(conn, conn.NotifyClose(make(chan error, 1)))
+	return conn, nil
+}
+
+// watch drops the connection once it was closed, so
+// that it is dialed again on the next use
+func (c *Client) watch(conn Connection, closed chan error) {
+	err := <-closed
+	if err != nil {
+		paceAMQPReconnectsTotal.Inc()
+		log.Logger().Warn().Err(err).Msg("AMQP connection lost")
+	}
+	c.reset(conn)
+}
+
+func (c *Client) reset(conn Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+		conn.Close() // nolint: errcheck
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Handler handles a delivery, the delivery is acknowledged
+// by the consumer if no error is returned
+type Handler func(ctx context.Context, d Delivery) error
+
+// Consumer consumes the deliveries of a queue with a pool of workers
+type Consumer struct {
+	Queue string
+	// Name is the consumer tag, the broker generates one if empty
+	Name string
+	// Workers is the number of deliveries handled concurrently
+	Workers int
+
+	client *Client
+}
+
+// NewConsumer returns a consumer of the queue using the connection of the
+// client, at least one worker is used
+func NewConsumer(client *Client, queue string, workers int) *Consumer {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Consumer{Queue: queue, Workers: workers, client: client}
+}
+
+// Run passes the deliveries to h until ctx is done. Deliveries are
+// acknowledged if h succeeded. Failed deliveries are requeued once, if
+// they fail again they are rejected so that the broker routes them to
+// the dead letter exchange of the queue (if configured). If the channel
+// or connection is lost, consuming is resumed after the reconnect.
+// On shutdown the current deliveries are handled before Run returns,
+// deliveries that were prefetched but not handled are requeued.
+func (c *Consumer) Run(ctx context.Context, h Handler) error {
+	for {
+		ch, err := c.client.openChannel(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = c.consume(ctx, ch, h)
+		ch.Close() // nolint: errcheck
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Ctx(ctx).Warn().Err(err).Str("queue", c.Queue).Msg("AMQP consumer stopped, resuming")
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cfg.ReconnectInterval):
+		}
+	}
+}
+
+func (c *Consumer) consume(ctx context.Context, ch Channel, h Handler) error {
+	if err := ch.Qos(cfg.Prefetch); err != nil {
+		return err
+	}
+	deliveries, err := ch.Consume(c.Queue, c.Name)
+	if err != nil {
+		return err
+	}
+
+	// handlers are not canceled on shutdown
+	hctx := ctxutil.Detached(ctx)
+	var wg sync.WaitGroup
+	for i := 0; i < c.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d, ok := <-deliveries:
+					if !ok {
+						return
+					}
+					c.handle(hctx, h, d)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		return errDeliveriesClosed
+	}
+	return nil
+}
+
+// handle passes the delivery to the handler with a span following the
+// span of the publisher and acknowledges it, panics of the handler
+// are recovered
+func (c *Consumer) handle(ctx context.Context, h Handler, d Delivery) {
+	var opts []opentracing.StartSpanOption
+	sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, headerCarrier(d.Headers))
+	if err == nil {
+		opts = append(opts, opentracing.FollowsFrom(sc))
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("AMQP: consume %s", c.Queue), opts...)
+	defer span.Finish()
+	ext.SpanKindConsumer.Set(span)
+	ext.MessageBusDestination.Set(span, c.Queue)
+	span.LogFields(olog.String("exchange", d.Exchange), olog.String("routing_key", d.RoutingKey),
+		olog.Bool("redelivered", d.Redelivered))
+
+	start := time.Now()
+	failed := true
+	defer func() {
+		paceAMQPHandleDurationSeconds.With(prometheus.Labels{"queue": c.Queue}).Observe(time.Since(start).Seconds())
+		c.acknowledge(ctx, d, failed)
+	}()
+	defer errors.HandleWithCtx(ctx, "amqp consumer "+c.Queue)
+
+	err = h(ctx, d)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("queue", c.Queue).Str("message_id", d.MessageID).
+			Bool("redelivered", d.Redelivered).Msg("Failed to handle amqp delivery")
+		return
+	}
+	failed = false
+}
+
+func (c *Consumer) acknowledge(ctx context.Context, d Delivery, failed bool) {
+	var (
+		result string
+		err    error
+	)
+	switch {
+	case !failed:
+		result = resultAcked
+		err = d.Acknowledger.Ack(d.DeliveryTag, false)
+	case !d.Redelivered:
+		result = resultRequeued
+		err = d.Acknowledger.Nack(d.DeliveryTag, false, true)
+	default:
+		result = resultRejected
+		err = d.Acknowledger.Nack(d.DeliveryTag, false, false)
+	}
+	if err != nil {
+		result = resultError
+		log.Ctx(ctx).Warn().Err(err).Str("queue", c.Queue).Msg("Failed to acknowledge amqp delivery")
+	}
+	paceAMQPConsumedTotal.With(prometheus.Labels{"queue": c.Queue, "result": result}).Inc()
+}
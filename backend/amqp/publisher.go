@@ -0,0 +1,142 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Publisher publishes messages on a channel in confirm mode and waits
+// for the confirmation of the broker. The span context of the publisher
+// is propagated to the consumers using the message headers.
+type Publisher struct {
+	client   *Client
+	mu       sync.Mutex
+	ch       Channel
+	confirms chan Confirmation
+}
+
+// NewPublisher returns a publisher using the connection of the client
+func NewPublisher(client *Client) *Publisher {
+	return &Publisher{client: client}
+}
+
+// Publish sends the message to the exchange and blocks until the broker
+// confirmed it. If the broker nacked the message ErrNacked is returned.
+// The channel is opened again after it failed, publishings wait for
+// the reconnect until ctx is done.
+func (p *Publisher) Publish(ctx context.Context, exchange, key string, msg Publishing) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("AMQP: publish %s", exchange))
+	defer span.Finish()
+	ext.SpanKindProducer.Set(span)
+	ext.MessageBusDestination.Set(span, exchange)
+	span.LogFields(olog.String("routing_key", key))
+
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]interface{})
+	}
+	err := span.Tracer().Inject(span.Context(), opentracing.TextMap, headerCarrier(msg.Headers))
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Failed to inject span context into amqp message")
+	}
+
+	result := resultConfirmed
+	err = p.publish(ctx, exchange, key, msg)
+	if err == ErrNacked {
+		result = resultNacked
+	} else if err != nil {
+		result = resultError
+	}
+	paceAMQPPublishedTotal.With(prometheus.Labels{"exchange": exchange, "result": result}).Inc()
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		return err
+	}
+	return nil
+}
+
+// publish is serialized, so that the confirmations
+// are received in the order of the publishings
+func (p *Publisher) publish(ctx context.Context, exchange, key string, msg Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the channel might have been closed with the connection,
+	// the message is published once more on a new channel
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if p.ch == nil {
+			ch, err := p.client.openChannel(ctx)
+			if err != nil {
+				return err
+			}
+			if err := ch.Confirm(); err != nil {
+				ch.Close() // nolint: errcheck
+				return err
+			}
+			p.ch = ch
+			p.confirms = ch.NotifyPublish(make(chan Confirmation, 1))
+		}
+
+		err = p.ch.Publish(exchange, key, msg)
+		if err == nil {
+			break
+		}
+		p.reset()
+	}
+	if err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(cfg.ConfirmTimeout)
+	defer timeout.Stop()
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			p.reset()
+			return fmt.Errorf("amqp: channel closed before the publishing was confirmed")
+		}
+		if !confirm.Ack {
+			return ErrNacked
+		}
+		return nil
+	case <-timeout.C:
+	case <-ctx.Done():
+	}
+	// the confirmation might still be received, the channel
+	// can't be used anymore as confirmations would be mixed up
+	p.reset()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("amqp: publishing was not confirmed within %v", cfg.ConfirmTimeout)
+}
+
+// Close closes the channel of the publisher
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ch == nil {
+		return nil
+	}
+	ch := p.ch
+	p.ch = nil
+	return ch.Close()
+}
+
+func (p *Publisher) reset() {
+	p.ch.Close() // nolint: errcheck
+	p.ch = nil
+	p.confirms = nil
+}
@@ -0,0 +1,112 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Actions of bulk operations
+const (
+	ActionIndex  = "index"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// BulkOp is an operation of a bulk request
+type BulkOp struct {
+	// Action is one of ActionIndex, ActionCreate, ActionUpdate and ActionDelete
+	Action string
+	Index  string
+	// ID of the document, optional for ActionIndex
+	ID string
+	// Doc is the document of index and create actions and the
+	// partial document or script of update actions (e.g.
+	// map[string]interface{}{"doc": ...}), nil for delete actions
+	Doc interface{}
+}
+
+// BulkItemError is the error of a failed operation of a bulk request
+type BulkItemError struct {
+	// Op is the position of the operation in the request
+	Op     int
+	Action string
+	ID     string
+	Error  *Error
+}
+
+// BulkResult is the result of a bulk request
+type BulkResult struct {
+	Took   int
+	Failed []BulkItemError
+}
+
+// Bulk executes the operations in a single request. Operations fail
+// independently, the failed operations are returned in the result.
+func (c *Client) Bulk(ctx context.Context, ops ...BulkOp) (*BulkResult, error) {
+	span, ctx := c.startSpan(ctx, "bulk", "")
+	defer span.Finish()
+	span.LogFields(olog.Int("operations", len(ops)))
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, op := range ops {
+		meta := map[string]string{"_index": op.Index}
+		if op.ID != "" {
+			meta["_id"] = op.ID
+		}
+		if err := enc.Encode(map[string]interface{}{op.Action: meta}); err != nil {
+			return nil, err
+		}
+		switch op.Action {
+		case ActionDelete:
+		case ActionIndex, ActionCreate, ActionUpdate:
+			if err := enc.Encode(op.Doc); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("elasticsearch: unknown bulk action %q", op.Action)
+		}
+	}
+
+	var resp struct {
+		Took   int                           `json:"took"`
+		Errors bool                          `json:"errors"`
+		Items  []map[string]bulkItemResponse `json:"items"`
+	}
+	start := time.Now()
+	err := c.request(ctx, "POST", "/_bulk", nil, body.Bytes(), "application/x-ndjson", &resp)
+	if err != nil {
+		return nil, c.finish(span, "bulk", start, err)
+	}
+
+	result := &BulkResult{Took: resp.Took}
+	for i, item := range resp.Items {
+		for action, r := range item {
+			if r.Error == nil {
+				paceElasticsearchBulkItemsTotal.With(prometheus.Labels{"action": action, "result": resultSuccess}).Inc()
+				continue
+			}
+			paceElasticsearchBulkItemsTotal.With(prometheus.Labels{"action": action, "result": resultError}).Inc()
+			r.Error.StatusCode = r.Status
+			result.Failed = append(result.Failed, BulkItemError{Op: i, Action: action, ID: r.ID, Error: r.Error})
+		}
+	}
+	span.LogFields(olog.Int("failed", len(result.Failed)))
+	return result, c.finish(span, "bulk", start, nil)
+}
+
+type bulkItemResponse struct {
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  *Error `json:"error"`
+}
@@ -0,0 +1,363 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/transport"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options of a client
+type Options struct {
+	// URLs of the nodes of the cluster, requests are distributed round
+	// robin and sent to the next node if a node is not reachable
+	URLs     []string
+	Username string
+	Password string
+	// Timeout is the maximum duration of a request
+	Timeout time.Duration
+	// TraceQuerySize is the maximum number of bytes of the
+	// query DSL added to the spans, 0 disables it
+	TraceQuerySize int
+	// HTTPClient is used for the requests, the default
+	// client traces and logs the requests
+	HTTPClient *http.Client
+}
+
+// Client of a cluster
+type Client struct {
+	nodes          []*url.URL
+	next           uint32
+	username       string
+	password       string
+	timeout        time.Duration
+	traceQuerySize int
+	httpClient     *http.Client
+}
+
+// NewClient returns a client of the cluster configured by the environment
+func NewClient() (*Client, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	return CustomClient(Options{
+		URLs:           cfg.URLs,
+		Username:       cfg.Username,
+		Password:       cfg.Password,
+		Timeout:        cfg.Timeout,
+		TraceQuerySize: cfg.TraceQuerySize,
+	})
+}
+
+// CustomClient returns a client with the passed options
+func CustomClient(opts Options) (*Client, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errors.New("elasticsearch: at least one URL is required")
+	}
+	c := &Client{
+		username:       opts.Username,
+		password:       opts.Password,
+		timeout:        opts.Timeout,
+		traceQuerySize: opts.TraceQuerySize,
+		httpClient:     opts.HTTPClient,
+	}
+	for _, raw := range opts.URLs {
+		u, err := url.Parse(strings.TrimSuffix(raw, "/"))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("elasticsearch: invalid URL %q", raw)
+		}
+		c.nodes = append(c.nodes, u)
+	}
+	if c.timeout <= 0 {
+		c.timeout = 10 * time.Second
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{
+			Transport: transport.Chain(&transport.JaegerRoundTripper{}, &transport.LoggingRoundTripper{}),
+		}
+	}
+	return c, nil
+}
+
+// Index stores the document in the index and returns its ID. If id is
+// empty the ID is generated by the cluster, an existing document with
+// the ID is replaced.
+func (c *Client) Index(ctx context.Context, index, id string, doc interface{}) (string, error) {
+	span, ctx := c.startSpan(ctx, "index", index)
+	defer span.Finish()
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	method, path := "POST", "/"+url.PathEscape(index)+"/_doc"
+	if id != "" {
+		method, path = "PUT", path+"/"+url.PathEscape(id)
+	}
+
+	var resp struct {
+		ID string `json:"_id"`
+	}
+	start := time.Now()
+	err = c.request(ctx, method, path, nil, body, "application/json", &resp)
+	return resp.ID, c.finish(span, "index", start, err)
+}
+
+// Get decodes the source of the document into v,
+// ErrNotFound is returned if it doesn't exist
+func (c *Client) Get(ctx context.Context, index, id string, v interface{}) error {
+	span, ctx := c.startSpan(ctx, "get", index)
+	defer span.Finish()
+
+	var resp struct {
+		Found  bool            `json:"found"`
+		Source json.RawMessage `json:"_source"`
+	}
+	start := time.Now()
+	err := c.request(ctx, "GET", "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), nil, nil, "", &resp)
+	if err == nil && !resp.Found {
+		err = ErrNotFound
+	}
+	if err == nil {
+		err = json.Unmarshal(resp.Source, v)
+	}
+	return c.finish(span, "get", start, err)
+}
+
+// Delete deletes the document, deleting documents
+// that don't exist is not an error
+func (c *Client) Delete(ctx context.Context, index, id string) error {
+	span, ctx := c.startSpan(ctx, "delete", index)
+	defer span.Finish()
+
+	start := time.Now()
+	err := c.request(ctx, "DELETE", "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), nil, nil, "", nil)
+	if err == ErrNotFound {
+		err = nil
+	}
+	return c.finish(span, "delete", start, err)
+}
+
+// Hit is a document found by a search
+type Hit struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Score  *float64        `json:"_score"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// Decode decodes the source of the document into v
+func (h Hit) Decode(v interface{}) error {
+	return json.Unmarshal(h.Source, v)
+}
+
+// SearchResult is the result of a search
+type SearchResult struct {
+	// Took is the duration of the search in milliseconds
+	Took     int  `json:"took"`
+	TimedOut bool `json:"timed_out"`
+	// Total is the number of matching documents, it is a lower
+	// bound if the cluster doesn't track the total hits
+	Total        int64                      `json:"-"`
+	Hits         []Hit                      `json:"-"`
+	Aggregations map[string]json.RawMessage `json:"aggregations"`
+}
+
+// Search searches the index (or comma separated indexes, aliases and
+// patterns) using the query DSL, e.g. map[string]interface{}{"query": ...}
+func (c *Client) Search(ctx context.Context, index string, query interface{}) (*SearchResult, error) {
+	span, ctx := c.startSpan(ctx, "search", index)
+	defer span.Finish()
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	if c.traceQuerySize > 0 {
+		statement := string(body)
+		if len(statement) > c.traceQuerySize {
+			statement = statement[:c.traceQuerySize] + "..."
+		}
+		ext.DBStatement.Set(span, statement)
+	}
+
+	var resp struct {
+		SearchResult
+		Hits struct {
+			Total json.RawMessage `json:"total"`
+			Hits  []Hit           `json:"hits"`
+		} `json:"hits"`
+	}
+	start := time.Now()
+	err = c.request(ctx, "POST", "/"+index+"/_search", nil, body, "application/json", &resp)
+	if err != nil {
+		return nil, c.finish(span, "search", start, err)
+	}
+
+	result := resp.SearchResult
+	result.Hits = resp.Hits.Hits
+	result.Total = parseTotal(resp.Hits.Total)
+	span.LogFields(olog.Int64("total", result.Total), olog.Int("took_ms", result.Took))
+	return &result, c.finish(span, "search", start, nil)
+}
+
+// parseTotal parses the total hits of Elasticsearch 7+ and OpenSearch
+// ({"value": n, "relation": "eq"}) and earlier versions (n)
+func parseTotal(raw json.RawMessage) int64 {
+	var total struct {
+		Value int64 `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &total); err == nil {
+		return total.Value
+	}
+	var n int64
+	json.Unmarshal(raw, &n) // nolint: errcheck
+	return n
+}
+
+// HealthCheck checks that the cluster is reachable and its status is not red
+func (c *Client) HealthCheck(ctx context.Context) error {
+	var resp struct {
+		Status string `json:"status"`
+	}
+	if err := c.request(ctx, "GET", "/_cluster/health", nil, nil, "", &resp); err != nil {
+		return err
+	}
+	if resp.Status == "red" {
+		return errors.New("elasticsearch: cluster status is red")
+	}
+	return nil
+}
+
+// request sends the request to the next node of the cluster and decodes
+// the response into v (if not nil). Nodes that can't be connected to are
+// skipped, other network errors are only retried with the next node for
+// idempotent requests, so that e.g. documents are not indexed twice. A
+// response with status 404 is returned as ErrNotFound unless it is an
+// error of the cluster (e.g. a missing index).
+func (c *Client) request(ctx context.Context, method, path string, query url.Values, body []byte, contentType string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	first := atomic.AddUint32(&c.next, 1)
+	var lastErr error
+	for i := 0; i < len(c.nodes); i++ {
+		node := c.nodes[(int(first)+i)%len(c.nodes)]
+		u := *node
+		u.Path += path
+		u.RawQuery = query.Encode()
+
+		req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil || !(isDialError(err) || idempotent(method, path)) {
+				break
+			}
+			continue // next node
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close() // nolint: errcheck
+		if err != nil {
+			return err
+		}
+		return decodeResponse(resp.StatusCode, data, v)
+	}
+	return lastErr
+}
+
+// idempotent returns true if the request can be sent again without
+// changing the result, searches are sent using POST but only read
+func idempotent(method, path string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE":
+		return true
+	}
+	return strings.HasSuffix(path, "/_search")
+}
+
+// isDialError returns true if the connection to the node failed,
+// so that the request wasn't sent
+func isDialError(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	oe, ok := err.(*net.OpError)
+	return ok && oe.Op == "dial"
+}
+
+func decodeResponse(statusCode int, data []byte, v interface{}) error {
+	if statusCode >= 200 && statusCode < 300 {
+		if v == nil {
+			return nil
+		}
+		return json.Unmarshal(data, v)
+	}
+
+	var resp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	json.Unmarshal(data, &resp) // nolint: errcheck
+	e := &Error{StatusCode: statusCode}
+	if len(resp.Error) > 0 && resp.Error[0] == '{' {
+		json.Unmarshal(resp.Error, e) // nolint: errcheck
+	} else if len(resp.Error) > 0 {
+		json.Unmarshal(resp.Error, &e.Reason) // nolint: errcheck
+	}
+	if statusCode == http.StatusNotFound && e.Type == "" {
+		return ErrNotFound
+	}
+	return e
+}
+
+func (c *Client) startSpan(ctx context.Context, op, index string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Elasticsearch: "+op)
+	ext.SpanKindRPCClient.Set(span)
+	ext.DBType.Set(span, "elasticsearch")
+	ext.DBInstance.Set(span, index)
+	return span, ctx
+}
+
+// finish records the metrics of the operation and returns err
+func (c *Client) finish(span opentracing.Span, op string, start time.Time, err error) error {
+	result := resultSuccess
+	switch {
+	case err == ErrNotFound:
+		result = resultNotFound
+	case err != nil:
+		result = resultError
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+	}
+	paceElasticsearchRequestsTotal.With(prometheus.Labels{"op": op, "result": result}).Inc()
+	paceElasticsearchRequestDurationSeconds.With(prometheus.Labels{"op": op}).Observe(time.Since(start).Seconds())
+	return err
+}
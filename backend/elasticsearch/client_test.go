@@ -0,0 +1,210 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cluster is an in-memory cluster of a single index "articles",
+// searches return all documents
+type cluster struct {
+	mu   sync.Mutex
+	docs map[string]json.RawMessage
+	ids  int
+}
+
+func (c *cluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/_cluster/health":
+		fmt.Fprint(w, `{"status":"yellow"}`) // nolint: errcheck
+	case r.URL.Path == "/_bulk":
+		c.bulk(w, r)
+	case parts[0] != "articles":
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"type":"index_not_found_exception","reason":"no such index"},"status":404}`) // nolint: errcheck
+	case parts[1] == "_search":
+		var hits []string
+		for id, doc := range c.docs {
+			hits = append(hits, fmt.Sprintf(`{"_index":"articles","_id":%q,"_score":1,"_source":%s}`, id, doc))
+		}
+		fmt.Fprintf(w, `{"took":3,"hits":{"total":{"value":%d,"relation":"eq"},"hits":[%s]}}`, len(hits), strings.Join(hits, ",")) // nolint: errcheck
+	case r.Method == "POST" || r.Method == "PUT":
+		id := ""
+		if len(parts) > 2 {
+			id = parts[2]
+		} else {
+			c.ids++
+			id = fmt.Sprintf("gen-%d", c.ids)
+		}
+		var doc json.RawMessage
+		json.NewDecoder(r.Body).Decode(&doc) // nolint: errcheck
+		c.docs[id] = doc
+		fmt.Fprintf(w, `{"_id":%q,"result":"created"}`, id) // nolint: errcheck
+	case r.Method == "GET":
+		doc, ok := c.docs[parts[2]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"_id":%q,"found":false}`, parts[2]) // nolint: errcheck
+			return
+		}
+		fmt.Fprintf(w, `{"_id":%q,"found":true,"_source":%s}`, parts[2], doc) // nolint: errcheck
+	case r.Method == "DELETE":
+		if _, ok := c.docs[parts[2]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"result":"not_found"}`) // nolint: errcheck
+			return
+		}
+		delete(c.docs, parts[2])
+		fmt.Fprint(w, `{"result":"deleted"}`) // nolint: errcheck
+	}
+}
+
+func (c *cluster) bulk(w http.ResponseWriter, r *http.Request) {
+	var items []string
+	s := bufio.NewScanner(r.Body)
+	for s.Scan() {
+		var meta map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		json.Unmarshal(s.Bytes(), &meta) // nolint: errcheck
+		for action, m := range meta {
+			if action != "delete" {
+				s.Scan()
+			}
+			if m.Index != "articles" {
+				items = append(items, fmt.Sprintf(`{%q:{"_id":%q,"status":404,"error":{"type":"index_not_found_exception","reason":"no such index"}}}`, action, m.ID))
+				continue
+			}
+			if action == "delete" {
+				delete(c.docs, m.ID)
+			} else {
+				c.docs[m.ID] = json.RawMessage(append([]byte(nil), s.Bytes()...))
+			}
+			items = append(items, fmt.Sprintf(`{%q:{"_id":%q,"status":200}}`, action, m.ID))
+		}
+	}
+	fmt.Fprintf(w, `{"took":5,"errors":true,"items":[%s]}`, strings.Join(items, ",")) // nolint: errcheck
+}
+
+type article struct {
+	Title string `json:"title"`
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(&cluster{docs: make(map[string]json.RawMessage)})
+	defer srv.Close()
+
+	// the first node is not reachable
+	c, err := CustomClient(Options{URLs: []string{"http://127.0.0.1:1", srv.URL}, TraceQuerySize: 100})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, c.HealthCheck(ctx))
+
+	id, err := c.Index(ctx, "articles", "1", article{Title: "first"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	id, err = c.Index(ctx, "articles", "", article{Title: "second"})
+	assert.NoError(t, err)
+	assert.Equal(t, "gen-1", id)
+
+	var a article
+	assert.NoError(t, c.Get(ctx, "articles", "1", &a))
+	assert.Equal(t, "first", a.Title)
+	assert.Equal(t, ErrNotFound, c.Get(ctx, "articles", "unknown", &a))
+
+	_, err = c.Index(ctx, "missing", "1", article{})
+	if assert.IsType(t, &Error{}, err) {
+		assert.Equal(t, "index_not_found_exception", err.(*Error).Type)
+	}
+
+	result, err := c.Search(ctx, "articles", map[string]interface{}{
+		"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, 3, result.Took)
+	if assert.Len(t, result.Hits, 2) {
+		assert.NoError(t, result.Hits[0].Decode(&a))
+		assert.NotEmpty(t, a.Title)
+	}
+
+	bulk, err := c.Bulk(ctx,
+		BulkOp{Action: ActionIndex, Index: "articles", ID: "3", Doc: article{Title: "third"}},
+		BulkOp{Action: ActionDelete, Index: "articles", ID: "1"},
+		BulkOp{Action: ActionCreate, Index: "missing", ID: "4", Doc: article{}},
+	)
+	assert.NoError(t, err)
+	if assert.Len(t, bulk.Failed, 1) {
+		assert.Equal(t, 2, bulk.Failed[0].Op)
+		assert.Equal(t, ActionCreate, bulk.Failed[0].Action)
+		assert.Equal(t, 404, bulk.Failed[0].Error.StatusCode)
+	}
+	assert.Equal(t, ErrNotFound, c.Get(ctx, "articles", "1", &a))
+	assert.NoError(t, c.Get(ctx, "articles", "3", &a))
+	assert.Equal(t, "third", a.Title)
+
+	assert.NoError(t, c.Delete(ctx, "articles", "3"))
+	assert.NoError(t, c.Delete(ctx, "articles", "3"))
+
+	_, err = c.Bulk(ctx, BulkOp{Action: "upsert"})
+	assert.Error(t, err)
+}
+
+func TestParseTotal(t *testing.T) {
+	assert.Equal(t, int64(10), parseTotal(json.RawMessage(`{"value":10,"relation":"gte"}`)))
+	assert.Equal(t, int64(7), parseTotal(json.RawMessage(`7`)))
+}
+
+func TestFailover(t *testing.T) {
+	// the broken node closes the connections after reading the request
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close() // nolint: errcheck
+		}
+	}))
+	defer broken.Close()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprint(w, `{"_id":"1","took":1,"hits":{"total":0,"hits":[]}}`) // nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c, err := CustomClient(Options{URLs: []string{broken.URL, srv.URL}})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// documents with generated IDs would be indexed twice
+	atomic.StoreUint32(&c.next, 1)
+	_, err = c.Index(ctx, "articles", "", article{Title: "once"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+	atomic.StoreUint32(&c.next, 1)
+	_, err = c.Index(ctx, "articles", "1", article{Title: "once"})
+	assert.NoError(t, err)
+	atomic.StoreUint32(&c.next, 1)
+	_, err = c.Search(ctx, "articles", map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,102 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package elasticsearch is a client of Elasticsearch and OpenSearch
+// clusters offering index, search and bulk helpers. Requests are
+// instrumented with metrics, logging and tracing, the spans contain
+// the query DSL of the searches.
+package elasticsearch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	URLs     []string `env:"ELASTICSEARCH_URLS" envSeparator:"," envDefault:"http://elasticsearch:9200"`
+	Username string   `env:"ELASTICSEARCH_USERNAME"`
+	Password string   `env:"ELASTICSEARCH_PASSWORD"`
+	// Maximum duration of a request
+	Timeout time.Duration `env:"ELASTICSEARCH_TIMEOUT" envDefault:"10s"`
+	// Maximum number of bytes of the query DSL added to the spans,
+	// 0 disables tracing of the queries
+	TraceQuerySize int `env:"ELASTICSEARCH_TRACE_QUERY_SIZE" envDefault:"4096"`
+}
+
+var (
+	cfg      config
+	setupErr error
+	once     sync.Once
+)
+
+// Setup parses the environment, it is called by NewClient. Call it
+// to handle malformed environments.
+func Setup() error {
+	once.Do(func() {
+		setupErr = env.Parse(&cfg)
+	})
+	return setupErr
+}
+
+// results of requests and bulk items, used as metric label
+const (
+	resultSuccess  = "success"
+	resultNotFound = "not_found"
+	resultError    = "error"
+)
+
+var (
+	paceElasticsearchRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("elasticsearch_requests_total"),
+			Help: "Collects stats about the number of requests partitioned by operation and result",
+		},
+		[]string{"op", "result"},
+	)
+	paceElasticsearchRequestDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("elasticsearch_request_duration_seconds"),
+			Help:    "Collect performance metrics for each operation",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"op"},
+	)
+	paceElasticsearchBulkItemsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("elasticsearch_bulk_items_total"),
+			Help: "Collects stats about the number of items of bulk requests partitioned by action and result",
+		},
+		[]string{"action", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceElasticsearchRequestsTotal)
+	prometheus.MustRegister(paceElasticsearchRequestDurationSeconds)
+	prometheus.MustRegister(paceElasticsearchBulkItemsTotal)
+	configcheck.Register("elasticsearch", Setup)
+}
+
+// ErrNotFound is returned if the document doesn't exist
+var ErrNotFound = errors.New("elasticsearch: document not found")
+
+// Error is returned if the cluster responds with an error
+type Error struct {
+	StatusCode int
+	Type       string `json:"type"`
+	Reason     string `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("elasticsearch: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("elasticsearch: %s: %s (status %d)", e.Type, e.Reason, e.StatusCode)
+}
@@ -0,0 +1,153 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Handler handles a consumed message
+type Handler func(ctx context.Context, msg Message) error
+
+// Consumer consumes the messages of a consumer group member one after
+// another. The offset of a message is committed after it was handled,
+// so messages are delivered at least once.
+type Consumer struct {
+	// Group of the consumer, used as metric label
+	Group string
+	// RetryFailed handles failed messages again after KAFKA_RETRY_INTERVAL
+	// until they succeed, their offsets are only committed afterwards. The
+	// partition is blocked until then. If false, failed messages are
+	// logged and committed.
+	RetryFailed bool
+
+	reader Reader
+}
+
+// NewConsumer returns a consumer using the passed reader of a member
+// of the group, if the group is empty KAFKA_GROUP_ID is used
+func NewConsumer(r Reader, group string) *Consumer {
+	mustSetup()
+	if group == "" {
+		group = cfg.GroupID
+	}
+	return &Consumer{Group: group, reader: r}
+}
+
+// Run passes the messages to h until ctx is done, then the reader is
+// closed so that the partitions are reassigned to the other members. The
+// current message is handled before, but at most KAFKA_SHUTDOWN_TIMEOUT.
+// Messages that failed are logged and committed, so that they don't
+// block the partition, unless RetryFailed is set.
+func (c *Consumer) Run(ctx context.Context, h Handler) error {
+	// the handler is canceled after the shutdown timeout
+	hctx, cancel := context.WithCancel(ctxutil.Detached(ctx))
+	defer cancel()
+	go func() {
+		select {
+		case <-hctx.Done():
+		case <-ctx.Done():
+			select {
+			case <-hctx.Done():
+			case <-time.After(cfg.ShutdownTimeout):
+				cancel()
+			}
+		}
+	}()
+
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "fetch"}).Inc()
+			log.Ctx(ctx).Warn().Err(err).Str("group", c.Group).Msg("Failed to fetch kafka message")
+			select {
+			case <-ctx.Done():
+			case <-time.After(cfg.RetryInterval):
+			}
+			continue
+		}
+
+		handled := c.handle(hctx, h, msg)
+		for !handled && c.RetryFailed {
+			select {
+			case <-ctx.Done():
+				// not committed, the message is delivered again to the
+				// member the partition is reassigned to
+				return c.reader.Close()
+			case <-time.After(cfg.RetryInterval):
+			}
+			handled = c.handle(hctx, h, msg)
+		}
+
+		err = c.reader.CommitMessages(hctx, msg)
+		if err != nil {
+			paceKafkaErrorsTotal.With(prometheus.Labels{"topic": msg.Topic, "op": "commit"}).Inc()
+			log.Ctx(ctx).Warn().Err(err).Str("topic", msg.Topic).Int("partition", msg.Partition).
+				Int64("offset", msg.Offset).Msg("Failed to commit kafka message")
+		}
+		if msg.HighWaterMark > 0 {
+			paceKafkaConsumerLag.With(prometheus.Labels{
+				"topic":     msg.Topic,
+				"group":     c.Group,
+				"partition": strconv.Itoa(msg.Partition),
+			}).Set(float64(msg.HighWaterMark - msg.Offset - 1))
+		}
+	}
+
+	return c.reader.Close()
+}
+
+// handle passes the message to the handler with a span following the span
+// of the producer, panics of the handler are recovered. It returns false if
+// the handler failed.
+func (c *Consumer) handle(ctx context.Context, h Handler, msg Message) (handled bool) {
+	var opts []opentracing.StartSpanOption
+	sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, headerCarrier{&msg})
+	if err == nil {
+		opts = append(opts, opentracing.FollowsFrom(sc))
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Kafka: consume %s", msg.Topic), opts...)
+	defer span.Finish()
+	ext.SpanKindConsumer.Set(span)
+	ext.MessageBusDestination.Set(span, msg.Topic)
+	span.LogFields(olog.String("group", c.Group), olog.Int("partition", msg.Partition), olog.Int64("offset", msg.Offset))
+
+	start := time.Now()
+	failed := true
+	defer func() {
+		labels := prometheus.Labels{"topic": msg.Topic, "group": c.Group}
+		paceKafkaHandleDurationSeconds.With(labels).Observe(time.Since(start).Seconds())
+		paceKafkaConsumedTotal.With(labels).Inc()
+		if failed {
+			paceKafkaErrorsTotal.With(prometheus.Labels{"topic": msg.Topic, "op": "handle"}).Inc()
+		}
+		handled = !failed
+	}()
+	defer errors.HandleWithCtx(ctx, "kafka consumer "+msg.Topic)
+
+	err = h(ctx, msg)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("topic", msg.Topic).Int("partition", msg.Partition).
+			Int64("offset", msg.Offset).Msg("Failed to handle kafka message")
+		return false
+	}
+	failed = false
+	return true
+}
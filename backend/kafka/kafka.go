@@ -0,0 +1,194 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package kafka instruments kafka producers and consumer groups with
+// metrics, logging and tracing. The protocol is implemented by a driver
+// (e.g. github.com/segmentio/kafka-go) that is adapted to the Writer and
+// Reader interfaces by the service.
+package kafka
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	Brokers  []string `env:"KAFKA_BROKERS" envSeparator:"," envDefault:"kafka:9092"`
+	ClientID string   `env:"KAFKA_CLIENT_ID"`
+	GroupID  string   `env:"KAFKA_GROUP_ID"`
+	// Maximum duration a consumer waits for the handler of the current
+	// message on shutdown before the reader is closed
+	ShutdownTimeout time.Duration `env:"KAFKA_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	// Interval in which failed fetches are retried
+	RetryInterval time.Duration `env:"KAFKA_RETRY_INTERVAL" envDefault:"1s"`
+	// Interval in which the lag of the consumer groups is exported
+	LagInterval time.Duration `env:"KAFKA_LAG_INTERVAL" envDefault:"30s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the KAFKA_* environment of the producers and
+// consumers, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse Kafka environment: %v", err)
+	}
+}
+
+// Settings of the environment to configure the driver
+type Settings struct {
+	Brokers  []string
+	ClientID string
+	GroupID  string
+}
+
+// EnvSettings returns the settings of the environment
+func EnvSettings() Settings {
+	mustSetup()
+	return Settings{Brokers: cfg.Brokers, ClientID: cfg.ClientID, GroupID: cfg.GroupID}
+}
+
+var (
+	paceKafkaProducedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("kafka_produced_total"),
+			Help: "Collects stats about the number of produced messages partitioned by topic",
+		},
+		[]string{"topic"},
+	)
+	paceKafkaConsumedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("kafka_consumed_total"),
+			Help: "Collects stats about the number of consumed messages partitioned by topic and group",
+		},
+		[]string{"topic", "group"},
+	)
+	paceKafkaErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("kafka_errors_total"),
+			Help: "Collects stats about the number of errors partitioned by topic and operation (produce, fetch, handle, commit, lag)",
+		},
+		[]string{"topic", "op"},
+	)
+	paceKafkaConsumerLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("kafka_consumer_lag"),
+			Help: "Number of messages of the partition that were not consumed yet by the group",
+		},
+		[]string{"topic", "group", "partition"},
+	)
+	paceKafkaConsumerGroupLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("kafka_consumer_group_lag"),
+			Help: "Number of messages of the partition that were not consumed yet by the group, computed from the offsets of the brokers",
+		},
+		[]string{"topic", "group", "partition"},
+	)
+	paceKafkaHandleDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("kafka_handle_duration_seconds"),
+			Help:    "Collect performance metrics for the handling of consumed messages",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"topic", "group"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceKafkaProducedTotal)
+	prometheus.MustRegister(paceKafkaConsumedTotal)
+	prometheus.MustRegister(paceKafkaErrorsTotal)
+	prometheus.MustRegister(paceKafkaConsumerLag)
+	prometheus.MustRegister(paceKafkaConsumerGroupLag)
+	prometheus.MustRegister(paceKafkaHandleDurationSeconds)
+	configcheck.Register("kafka", Setup)
+}
+
+// Header of a message
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message of a topic
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	// HighWaterMark is the offset of the next message of the partition,
+	// it is used to calculate the lag of the consumer (optional)
+	HighWaterMark int64
+	Key           []byte
+	Value         []byte
+	Headers       []Header
+	Time          time.Time
+}
+
+// Writer sends messages to the brokers, implemented by an adapter of the
+// driver (e.g. kafka.Writer of kafka-go)
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Reader reads the messages of the partitions assigned to the consumer
+// group member, implemented by an adapter of the driver (e.g. kafka.Reader
+// of kafka-go). Rebalancing is handled by the driver.
+type Reader interface {
+	// FetchMessage blocks until the next message is available
+	// or ctx is done
+	FetchMessage(ctx context.Context) (Message, error)
+	// CommitMessages commits the offsets of the passed messages
+	CommitMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// headerCarrier propagates the span context using the message headers
+type headerCarrier struct {
+	msg *Message
+}
+
+// Set implements opentracing.TextMapWriter
+func (c headerCarrier) Set(key, val string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(val)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, Header{Key: key, Value: []byte(val)})
+}
+
+// ForeachKey implements opentracing.TextMapReader
+func (c headerCarrier) ForeachKey(handler func(key, val string) error) error {
+	for _, h := range c.msg.Headers {
+		if err := handler(h.Key, string(h.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ opentracing.TextMapWriter = headerCarrier{}
+	_ opentracing.TextMapReader = headerCarrier{}
+)
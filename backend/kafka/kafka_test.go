@@ -0,0 +1,238 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// queue is an in-memory writer and reader of a single partition
+type queue struct {
+	mu        sync.Mutex
+	msgs      chan Message
+	offset    int64
+	committed []int64
+	closed    bool
+}
+
+func newQueue() *queue {
+	return &queue{msgs: make(chan Message, 10)}
+}
+
+func (q *queue) WriteMessages(ctx context.Context, msgs ...Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, msg := range msgs {
+		msg.Offset = q.offset
+		msg.HighWaterMark = q.offset + 1
+		q.offset++
+		q.msgs <- msg
+	}
+	return nil
+}
+
+func (q *queue) FetchMessage(ctx context.Context) (Message, error) {
+	select {
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	case msg := <-q.msgs:
+		return msg, nil
+	}
+}
+
+func (q *queue) CommitMessages(ctx context.Context, msgs ...Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, msg := range msgs {
+		q.committed = append(q.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (q *queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	return nil
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestProduceConsume(t *testing.T) {
+	q := newQueue()
+	p := NewProducer(q)
+	c := NewConsumer(q, "test")
+
+	produced := counterValue(t, paceKafkaProducedTotal.With(prometheus.Labels{"topic": "events"}))
+	failed := counterValue(t, paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "events", "op": "handle"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := p.Produce(ctx,
+		Message{Topic: "events", Value: []byte("a")},
+		Message{Topic: "events", Value: []byte("fail")},
+		Message{Topic: "events", Value: []byte("panic")},
+		Message{Topic: "events", Value: []byte("b")},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, produced+4, counterValue(t, paceKafkaProducedTotal.With(prometheus.Labels{"topic": "events"})))
+
+	var handled []string
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx, func(ctx context.Context, msg Message) error {
+			handled = append(handled, string(msg.Value))
+			switch string(msg.Value) {
+			case "fail":
+				return errors.New("failed")
+			case "panic":
+				panic("panic")
+			case "b":
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected consumer to shut down")
+	}
+
+	assert.Equal(t, []string{"a", "fail", "panic", "b"}, handled)
+	assert.Equal(t, []int64{0, 1, 2, 3}, q.committed)
+	assert.True(t, q.closed)
+	assert.Equal(t, failed+2, counterValue(t, paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "events", "op": "handle"})))
+}
+
+func TestRetryFailed(t *testing.T) {
+	q := newQueue()
+	c := NewConsumer(q, "test")
+	c.RetryFailed = true
+	defer func(interval time.Duration) { cfg.RetryInterval = interval }(cfg.RetryInterval)
+	cfg.RetryInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := NewProducer(q).Produce(ctx,
+		Message{Topic: "events", Value: []byte("a")},
+		Message{Topic: "events", Value: []byte("b")},
+	)
+	assert.NoError(t, err)
+
+	var handled []string
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx, func(ctx context.Context, msg Message) error {
+			handled = append(handled, string(msg.Value))
+			switch {
+			case string(msg.Value) == "a" && len(handled) < 3:
+				return errors.New("failed")
+			case string(msg.Value) == "b":
+				// not committed if the consumer is stopped
+				cancel()
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected consumer to shut down")
+	}
+
+	assert.Equal(t, []string{"a", "a", "a", "b"}, handled)
+	assert.Equal(t, []int64{0}, q.committed)
+	assert.True(t, q.closed)
+}
+
+func TestHeaderCarrier(t *testing.T) {
+	msg := &Message{Headers: []Header{{Key: "content-type", Value: []byte("json")}}}
+	c := headerCarrier{msg}
+	c.Set("uber-trace-id", "1")
+	c.Set("uber-trace-id", "2")
+
+	read := make(map[string]string)
+	assert.NoError(t, c.ForeachKey(func(key, val string) error {
+		read[key] = val
+		return nil
+	}))
+	assert.Equal(t, map[string]string{"content-type": "json", "uber-trace-id": "2"}, read)
+}
+
+type offsets struct {
+	committed, hwms, lwms map[Partition]int64
+	err                   error
+}
+
+func (o *offsets) CommittedOffsets(ctx context.Context, group string, topics []string) (map[Partition]int64, error) {
+	return o.committed, o.err
+}
+
+func (o *offsets) HighWaterMarks(ctx context.Context, topics []string) (map[Partition]int64, error) {
+	return o.hwms, o.err
+}
+
+func (o *offsets) LowWaterMarks(ctx context.Context, topics []string) (map[Partition]int64, error) {
+	return o.lwms, o.err
+}
+
+func lagValue(t *testing.T, p Partition) float64 {
+	var m dto.Metric
+	g, err := paceKafkaConsumerGroupLag.GetMetricWith(lagLabels(p, "lag"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestLagExporter(t *testing.T) {
+	p0, p1, p2 := Partition{"events", 0}, Partition{"events", 1}, Partition{"events", 2}
+	o := &offsets{
+		committed: map[Partition]int64{p0: 10, p1: 5},
+		hwms:      map[Partition]int64{p0: 12, p1: 5, p2: 7},
+		lwms:      map[Partition]int64{p0: 0, p1: 0, p2: 3},
+	}
+	e := &LagExporter{Group: "lag", Topics: []string{"events"}, Offsets: o}
+
+	assert.NoError(t, e.Export(context.Background()))
+	assert.Equal(t, 2.0, lagValue(t, p0))
+	assert.Equal(t, 0.0, lagValue(t, p1))
+	assert.Equal(t, 4.0, lagValue(t, p2), "partitions without committed offset lag from the oldest message")
+	assert.Equal(t, map[Partition]bool{p0: true, p1: true, p2: true}, e.exported)
+
+	// failed exports keep the previous lag
+	failed := counterValue(t, paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "lag"}))
+	o.err = errors.New("broker unavailable")
+	assert.Error(t, e.Export(context.Background()))
+	assert.Equal(t, failed+1, counterValue(t, paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "lag"})))
+	assert.Len(t, e.exported, 3)
+
+	// partitions that are gone are removed
+	o.err = nil
+	delete(o.hwms, p1)
+	assert.NoError(t, e.Export(context.Background()))
+	assert.Equal(t, map[Partition]bool{p0: true, p2: true}, e.exported)
+	assert.False(t, paceKafkaConsumerGroupLag.Delete(lagLabels(p1, "lag")))
+}
@@ -0,0 +1,132 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Partition of a topic
+type Partition struct {
+	Topic     string
+	Partition int
+}
+
+// Offsets returns the offsets of the partitions, implemented by an
+// adapter of the driver (e.g. kafka.Client of kafka-go)
+type Offsets interface {
+	// CommittedOffsets returns the offsets committed by the group, that
+	// is the offset of the next message the group consumes. Partitions
+	// without committed offset are omitted.
+	CommittedOffsets(ctx context.Context, group string, topics []string) (map[Partition]int64, error)
+	// HighWaterMarks returns the offset of the next message
+	// of the partitions of the topics
+	HighWaterMarks(ctx context.Context, topics []string) (map[Partition]int64, error)
+	// LowWaterMarks returns the offset of the oldest message
+	// of the partitions of the topics
+	LowWaterMarks(ctx context.Context, topics []string) (map[Partition]int64, error)
+}
+
+// LagExporter exports the lag of a consumer group in intervals, unlike
+// the lag updated by the consumer it is also exported if the consumers
+// are stuck or down
+type LagExporter struct {
+	// Group whose lag is exported, defaults to KAFKA_GROUP_ID
+	Group string
+	// Topics consumed by the group
+	Topics []string
+	// Offsets of the partitions
+	Offsets Offsets
+	// Interval of the exports, defaults to KAFKA_LAG_INTERVAL
+	Interval time.Duration
+
+	// partitions exported by the previous export
+	exported map[Partition]bool
+}
+
+// Run exports the lag until ctx is done, failed exports are logged and
+// the previous lag is kept. Run returns ctx.Err() once ctx is done.
+func (e *LagExporter) Run(ctx context.Context) error {
+	mustSetup()
+	interval := e.Interval
+	if interval <= 0 {
+		interval = cfg.LagInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Export(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("group", e.group()).Msg("Failed to export kafka consumer lag")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Export fetches the offsets and exports the lag of the partitions
+// once. Partitions without committed offset lag from their oldest
+// message. The lag of partitions that are gone (e.g. deleted topics)
+// is removed.
+func (e *LagExporter) Export(ctx context.Context) error {
+	group := e.group()
+	committed, err := e.Offsets.CommittedOffsets(ctx, group, e.Topics)
+	if err != nil {
+		paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "lag"}).Inc()
+		return err
+	}
+	hwms, err := e.Offsets.HighWaterMarks(ctx, e.Topics)
+	if err != nil {
+		paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "lag"}).Inc()
+		return err
+	}
+	lwms, err := e.Offsets.LowWaterMarks(ctx, e.Topics)
+	if err != nil {
+		paceKafkaErrorsTotal.With(prometheus.Labels{"topic": "", "op": "lag"}).Inc()
+		return err
+	}
+
+	exported := make(map[Partition]bool, len(hwms))
+	for p, hwm := range hwms {
+		offset, ok := committed[p]
+		if !ok || offset < 0 {
+			// the group consumes from the oldest message
+			offset = lwms[p]
+		}
+		lag := hwm - offset
+		if lag < 0 {
+			// the high water mark is fetched after the offsets
+			lag = 0
+		}
+		paceKafkaConsumerGroupLag.With(lagLabels(p, group)).Set(float64(lag))
+		exported[p] = true
+	}
+	for p := range e.exported {
+		if !exported[p] {
+			paceKafkaConsumerGroupLag.Delete(lagLabels(p, group))
+		}
+	}
+	e.exported = exported
+	return nil
+}
+
+func (e *LagExporter) group() string {
+	if e.Group == "" {
+		mustSetup()
+		return cfg.GroupID
+	}
+	return e.Group
+}
+
+func lagLabels(p Partition, group string) prometheus.Labels {
+	return prometheus.Labels{"topic": p.Topic, "group": group, "partition": strconv.Itoa(p.Partition)}
+}
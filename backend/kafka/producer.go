@@ -0,0 +1,66 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Producer sends messages using a writer, the span context of the
+// producer is propagated to the consumers using the message headers
+type Producer struct {
+	writer Writer
+}
+
+// NewProducer returns a producer using the passed writer
+func NewProducer(w Writer) *Producer {
+	return &Producer{writer: w}
+}
+
+// Produce sends the messages, all messages need to have a topic
+func (p *Producer) Produce(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Kafka: produce %s", msgs[0].Topic))
+	defer span.Finish()
+	ext.SpanKindProducer.Set(span)
+	ext.MessageBusDestination.Set(span, msgs[0].Topic)
+	span.LogFields(olog.Int("messages", len(msgs)))
+
+	for i := range msgs {
+		err := span.Tracer().Inject(span.Context(), opentracing.TextMap, headerCarrier{&msgs[i]})
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("Failed to inject span context into kafka message")
+		}
+	}
+
+	err := p.writer.WriteMessages(ctx, msgs...)
+	for _, msg := range msgs {
+		if err != nil {
+			paceKafkaErrorsTotal.With(prometheus.Labels{"topic": msg.Topic, "op": "produce"}).Inc()
+			continue
+		}
+		paceKafkaProducedTotal.With(prometheus.Labels{"topic": msg.Topic}).Inc()
+	}
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Close closes the writer
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
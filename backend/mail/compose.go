@@ -0,0 +1,258 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reservedHeaders are set from the fields of the message,
+// they can't be passed as additional headers
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"Sender":                    true,
+	"Reply-To":                  true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// envelope is the sender and recipients of a message
+type envelope struct {
+	from string
+	to   []string
+}
+
+// compose returns the envelope and the MIME encoded message
+func (m *Message) compose(id string, now time.Time) (envelope, []byte, error) {
+	var env envelope
+	if m.Text == "" && m.HTML == "" {
+		return env, nil, errors.New("mail: message has no content")
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return env, nil, fmt.Errorf("mail: invalid sender %q: %v", m.From, err)
+	}
+	env.from = from.Address
+
+	h := make(textproto.MIMEHeader)
+	h.Set("From", from.String())
+	for _, field := range []struct {
+		name  string
+		addrs []string
+	}{{"To", m.To}, {"Cc", m.Cc}, {"Bcc", m.Bcc}} {
+		var list []string
+		for _, a := range field.addrs {
+			addr, err := mail.ParseAddress(a)
+			if err != nil {
+				return env, nil, fmt.Errorf("mail: invalid recipient %q: %v", a, err)
+			}
+			env.to = append(env.to, addr.Address)
+			list = append(list, addr.String())
+		}
+		if len(list) > 0 && field.name != "Bcc" {
+			h.Set(field.name, strings.Join(list, ", "))
+		}
+	}
+	if len(env.to) == 0 {
+		return env, nil, ErrNoRecipients
+	}
+	if m.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(m.ReplyTo)
+		if err != nil {
+			return env, nil, fmt.Errorf("mail: invalid reply-to %q: %v", m.ReplyTo, err)
+		}
+		h.Set("Reply-To", replyTo.String())
+	}
+	for name, value := range m.Headers {
+		if strings.ContainsAny(name+value, "\r\n") {
+			return env, nil, fmt.Errorf("mail: header %q contains a line break", name)
+		}
+		if reservedHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			return env, nil, fmt.Errorf("mail: header %q is set from the message and can't be passed as header", name)
+		}
+		h.Set(name, value)
+	}
+	h.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	h.Set("Date", now.Format(time.RFC1123Z))
+	h.Set("Message-Id", id)
+	h.Set("MIME-Version", "1.0")
+
+	var buf bytes.Buffer
+	switch {
+	case len(m.Attachments) > 0:
+		w := multipart.NewWriter(&buf)
+		h.Set("Content-Type", "multipart/mixed; boundary="+w.Boundary())
+		writeHeader(&buf, h)
+		if err := m.writeBody(w); err != nil {
+			return env, nil, err
+		}
+		for _, a := range m.Attachments {
+			if err := writeAttachment(w, a); err != nil {
+				return env, nil, err
+			}
+		}
+		err = w.Close()
+	case m.Text != "" && m.HTML != "":
+		w := multipart.NewWriter(&buf)
+		h.Set("Content-Type", "multipart/alternative; boundary="+w.Boundary())
+		writeHeader(&buf, h)
+		err = m.writeAlternatives(w)
+	default:
+		contentType, content := "text/plain; charset=utf-8", m.Text
+		if m.HTML != "" {
+			contentType, content = "text/html; charset=utf-8", m.HTML
+		}
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, h)
+		err = writeQuotedPrintable(&buf, content)
+	}
+	if err != nil {
+		return env, nil, err
+	}
+	return env, buf.Bytes(), nil
+}
+
+// writeBody writes the text and HTML content as part of a multipart message
+func (m *Message) writeBody(w *multipart.Writer) error {
+	if m.Text != "" && m.HTML != "" {
+		var alt bytes.Buffer
+		nested := multipart.NewWriter(&alt)
+		if err := m.writeAlternatives(nested); err != nil {
+			return err
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Type", "multipart/alternative; boundary="+nested.Boundary())
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		_, err = part.Write(alt.Bytes())
+		return err
+	}
+
+	contentType, content := "text/plain; charset=utf-8", m.Text
+	if m.HTML != "" {
+		contentType, content = "text/html; charset=utf-8", m.HTML
+	}
+	return writeTextPart(w, contentType, content)
+}
+
+// writeAlternatives writes the text and HTML alternatives, clients
+// display the last alternative they support
+func (m *Message) writeAlternatives(w *multipart.Writer) error {
+	if err := writeTextPart(w, "text/plain; charset=utf-8", m.Text); err != nil {
+		return err
+	}
+	if err := writeTextPart(w, "text/html; charset=utf-8", m.HTML); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func writeTextPart(w *multipart.Writer, contentType, content string) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	return writeQuotedPrintable(part, content)
+}
+
+func writeAttachment(w *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Transfer-Encoding", "base64")
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+
+	// base64 lines must not be longer than 76 characters
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(part, encoded+"\r\n")
+	return err
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qp, content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeHeader writes the header in a stable order followed by an empty line
+func writeHeader(buf *bytes.Buffer, h textproto.MIMEHeader) {
+	order := []string{"From", "Reply-To", "To", "Cc", "Subject", "Date", "Message-Id", "MIME-Version"}
+	written := make(map[string]bool)
+	for _, name := range order {
+		if v := h.Get(name); v != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", name, v)
+		}
+		written[textproto.CanonicalMIMEHeaderKey(name)] = true
+	}
+	var names []string
+	for name := range h {
+		if !written[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s: %s\r\n", name, h.Get(name))
+	}
+	buf.WriteString("\r\n")
+}
+
+// newMessageID returns a unique message ID of the domain of the sender
+func newMessageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = strings.TrimRight(from[i+1:], ">")
+	}
+	var b [16]byte
+	rand.Read(b[:]) // nolint: errcheck
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b[:]), domain)
+}
@@ -0,0 +1,138 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package mail sends emails using SMTP. Messages are composed as MIME
+// messages with plain text and HTML alternatives and attachments. Deliveries
+// are instrumented with metrics, logging and tracing; only the message IDs
+// are logged, never the content of the messages.
+package mail
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TLS modes of the connection to the SMTP server
+const (
+	// TLSNone uses an unencrypted connection
+	TLSNone = "none"
+	// TLSStartTLS upgrades the connection using STARTTLS, the message is
+	// not sent if the server doesn't support it
+	TLSStartTLS = "starttls"
+	// TLSImplicit connects using TLS (usually port 465)
+	TLSImplicit = "tls"
+)
+
+type config struct {
+	Host     string `env:"MAIL_SMTP_HOST" envDefault:"smtp"`
+	Port     int    `env:"MAIL_SMTP_PORT" envDefault:"587"`
+	TLS      string `env:"MAIL_SMTP_TLS" envDefault:"starttls"`
+	Username string `env:"MAIL_SMTP_USERNAME"`
+	Password string `env:"MAIL_SMTP_PASSWORD"`
+	// Default sender of messages without From
+	From string `env:"MAIL_FROM"`
+	// Maximum duration to deliver a message to the SMTP server
+	Timeout time.Duration `env:"MAIL_TIMEOUT" envDefault:"30s"`
+}
+
+var (
+	cfg      config
+	setupErr error
+	once     sync.Once
+)
+
+// Setup parses the environment, it is called by NewSender. Call it
+// to handle malformed environments.
+func Setup() error {
+	once.Do(func() {
+		setupErr = env.Parse(&cfg)
+		if setupErr == nil {
+			setupErr = validTLS(cfg.TLS)
+		}
+	})
+	return setupErr
+}
+
+// results of deliveries, used as metric label
+const (
+	resultSent     = "sent"
+	resultRejected = "rejected"
+	resultError    = "error"
+)
+
+var (
+	paceMailSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("mail_sent_total"),
+			Help: "Collects stats about the number of delivered messages partitioned by result",
+		},
+		[]string{"result"},
+	)
+	paceMailRecipientsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: metric.Name("mail_recipients_total"),
+			Help: "Collects stats about the number of recipients of delivered messages",
+		},
+	)
+	paceMailSendDurationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("mail_send_duration_seconds"),
+			Help:    "Collect performance metrics for the delivery of messages to the SMTP server",
+			Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceMailSentTotal)
+	prometheus.MustRegister(paceMailRecipientsTotal)
+	prometheus.MustRegister(paceMailSendDurationSeconds)
+	configcheck.Register("mail", Setup)
+}
+
+// ErrNoRecipients is returned for messages without recipients
+var ErrNoRecipients = errors.New("mail: message has no recipients")
+
+// ErrNoSender is returned for messages without sender if MAIL_FROM is empty
+var ErrNoSender = errors.New("mail: message has no sender")
+
+// Attachment of a message
+type Attachment struct {
+	Filename string
+	// ContentType defaults to the type of the file extension
+	// or application/octet-stream
+	ContentType string
+	Data        []byte
+}
+
+// Message is an email, at least one of Text and HTML is required.
+// Addresses are formatted as in RFC 5322, e.g. "Gopher <gopher@example.com>".
+type Message struct {
+	From    string
+	ReplyTo string
+	To      []string
+	Cc      []string
+	// Bcc recipients receive the message without being listed in the headers
+	Bcc     []string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are additional headers, e.g. List-Unsubscribe. Headers set
+	// from the fields of the message (e.g. From, To, Subject) are rejected.
+	Headers     map[string]string
+	Attachments []Attachment
+}
+
+func validTLS(mode string) error {
+	switch mode {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+		return nil
+	}
+	return errors.New("mail: MAIL_SMTP_TLS needs to be one of none, starttls or tls")
+}
@@ -0,0 +1,191 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpServer accepts a single session and records the envelope and data,
+// recipients containing "reject" are rejected
+type smtpServer struct {
+	ln   net.Listener
+	from string
+	to   []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln, done: make(chan struct{})}
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close() // nolint: errcheck
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP") // nolint: errcheck
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case cmd == "EHLO":
+			tp.PrintfLine("250-localhost") // nolint: errcheck
+			tp.PrintfLine("250 8BITMIME")  // nolint: errcheck
+		case strings.HasPrefix(line, "MAIL FROM:"):
+			s.from = strings.Trim(strings.Fields(line[len("MAIL FROM:"):])[0], "<>")
+			tp.PrintfLine("250 OK") // nolint: errcheck
+		case strings.HasPrefix(line, "RCPT TO:"):
+			to := strings.Trim(line[len("RCPT TO:"):], "<>")
+			if strings.Contains(to, "reject") {
+				tp.PrintfLine("550 mailbox unavailable") // nolint: errcheck
+				continue
+			}
+			s.to = append(s.to, to)
+			tp.PrintfLine("250 OK") // nolint: errcheck
+		case cmd == "DATA":
+			tp.PrintfLine("354 go ahead") // nolint: errcheck
+			data, _ := ioutil.ReadAll(tp.DotReader())
+			s.data = string(data)
+			tp.PrintfLine("250 OK") // nolint: errcheck
+		case cmd == "QUIT":
+			tp.PrintfLine("221 bye") // nolint: errcheck
+			return
+		default:
+			tp.PrintfLine("250 OK") // nolint: errcheck
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	srv := newSMTPServer(t)
+	defer srv.ln.Close() // nolint: errcheck
+
+	s, err := CustomSender(Options{Host: "127.0.0.1", Port: srv.port(), TLS: TLSNone, From: "Service <service@example.com>"})
+	assert.NoError(t, err)
+
+	id, err := s.Send(context.Background(), &Message{
+		To:      []string{"Gopher <gopher@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "Grüße",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+		Attachments: []Attachment{
+			{Filename: "invoice.pdf", Data: []byte(strings.Repeat("pdf", 100))},
+		},
+	})
+	assert.NoError(t, err)
+	<-srv.done
+
+	assert.True(t, strings.HasSuffix(id, "@example.com>"), id)
+	assert.Equal(t, "service@example.com", srv.from)
+	assert.Equal(t, []string{"gopher@example.com", "audit@example.com"}, srv.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	assert.NoError(t, err)
+	assert.Equal(t, id, msg.Header.Get("Message-Id"))
+	assert.Equal(t, `"Gopher" <gopher@example.com>`, msg.Header.Get("To"))
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Grüße", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := mr.NextPart()
+	assert.NoError(t, err)
+	mediaType, params, err = mime.ParseMediaType(body.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	alt := multipart.NewReader(body, params["boundary"])
+	for _, expected := range []string{"Hello", "<p>Hello</p>"} {
+		part, err := alt.NextPart()
+		if !assert.NoError(t, err) {
+			return
+		}
+		content, _ := ioutil.ReadAll(part)
+		assert.Equal(t, expected, string(content))
+	}
+
+	attachment, err := mr.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "invoice.pdf", attachment.FileName())
+	assert.Equal(t, "application/pdf", attachment.Header.Get("Content-Type"))
+}
+
+func TestSendRejected(t *testing.T) {
+	srv := newSMTPServer(t)
+	defer srv.ln.Close() // nolint: errcheck
+
+	s, err := CustomSender(Options{Host: "127.0.0.1", Port: srv.port(), TLS: TLSNone, Timeout: time.Second})
+	assert.NoError(t, err)
+
+	_, err = s.Send(context.Background(), &Message{From: "service@example.com", To: []string{"reject@example.com"}, Text: "Hello"})
+	if assert.IsType(t, &textproto.Error{}, err) {
+		assert.Equal(t, 550, err.(*textproto.Error).Code)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	msg := &Message{From: "service@example.com", To: []string{"gopher@example.com"}, Text: "Hello"}
+	_, data, err := msg.compose("<1@example.com>", time.Now())
+	assert.NoError(t, err)
+	parsed, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", parsed.Header.Get("Content-Type"))
+
+	cases := []*Message{
+		{From: "service@example.com", Text: "no recipients"},
+		{From: "invalid", To: []string{"gopher@example.com"}, Text: "invalid sender"},
+		{From: "service@example.com", To: []string{"gopher@example.com"}},
+		{From: "service@example.com", To: []string{"gopher@example.com"}, Text: "injection",
+			Headers: map[string]string{"X-Campaign": "a\r\nBcc: victim@example.com"}},
+		{From: "service@example.com", To: []string{"gopher@example.com"}, Text: "sender override",
+			Headers: map[string]string{"from": "ceo@example.com"}},
+		{From: "service@example.com", To: []string{"gopher@example.com"}, Text: "recipient override",
+			Headers: map[string]string{"To": "victim@example.com"}},
+	}
+	for _, c := range cases {
+		_, _, err := c.compose("<1@example.com>", time.Now())
+		assert.Error(t, err, c.Text)
+	}
+
+	s, err := CustomSender(Options{Host: "localhost"})
+	assert.NoError(t, err)
+	_, err = s.Send(context.Background(), &Message{To: []string{"gopher@example.com"}, Text: "Hello"})
+	assert.Equal(t, ErrNoSender, err)
+}
@@ -0,0 +1,188 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options of a sender
+type Options struct {
+	Host string
+	Port int
+	// TLS is one of TLSNone, TLSStartTLS and TLSImplicit
+	TLS      string
+	Username string
+	Password string
+	// From is the default sender of messages without From
+	From string
+	// Timeout is the maximum duration to deliver a message
+	Timeout time.Duration
+	// TLSConfig of the connection, defaults to the config
+	// verifying the certificate of Host
+	TLSConfig *tls.Config
+}
+
+// Sender delivers messages to an SMTP server, a connection
+// is established per message
+type Sender struct {
+	opts Options
+}
+
+// NewSender returns a sender configured by the environment
+func NewSender() (*Sender, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	return CustomSender(Options{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		TLS:      cfg.TLS,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+		Timeout:  cfg.Timeout,
+	})
+}
+
+// CustomSender returns a sender with the passed options
+func CustomSender(opts Options) (*Sender, error) {
+	if opts.Host == "" {
+		return nil, errors.New("mail: SMTP host is required")
+	}
+	if opts.TLS == "" {
+		opts.TLS = TLSStartTLS
+	}
+	if err := validTLS(opts.TLS); err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.TLSConfig == nil {
+		opts.TLSConfig = &tls.Config{ServerName: opts.Host}
+	}
+	return &Sender{opts: opts}, nil
+}
+
+// Send delivers the message and returns its message ID
+func (s *Sender) Send(ctx context.Context, msg *Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Mail: send")
+	defer span.Finish()
+	ext.SpanKindRPCClient.Set(span)
+
+	if msg.From == "" {
+		if s.opts.From == "" {
+			return "", ErrNoSender
+		}
+		copied := *msg
+		copied.From = s.opts.From
+		msg = &copied
+	}
+
+	id := newMessageID(msg.From)
+	span.LogFields(olog.String("message_id", id))
+	env, data, err := msg.compose(id, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	err = s.deliver(ctx, env, data)
+	paceMailSendDurationSeconds.Observe(time.Since(start).Seconds())
+	if err != nil {
+		result := resultError
+		if tpErr, ok := err.(*textproto.Error); ok && tpErr.Code >= 500 {
+			result = resultRejected
+		}
+		paceMailSentTotal.With(prometheus.Labels{"result": result}).Inc()
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("message_id", id).Int("recipients", len(env.to)).Msg("Failed to send mail")
+		return "", err
+	}
+
+	paceMailSentTotal.With(prometheus.Labels{"result": resultSent}).Inc()
+	paceMailRecipientsTotal.Add(float64(len(env.to)))
+	log.Ctx(ctx).Info().Str("message_id", id).Int("recipients", len(env.to)).
+		Int("size", len(data)).Msg("Mail sent")
+	return id, nil
+}
+
+// deliver sends the message to the SMTP server
+func (s *Sender) deliver(ctx context.Context, env envelope, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) // nolint: errcheck
+	}
+	if s.opts.TLS == TLSImplicit {
+		conn = tls.Client(conn, s.opts.TLSConfig)
+	}
+
+	c, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		conn.Close() // nolint: errcheck
+		return err
+	}
+	defer c.Close() // nolint: errcheck
+
+	if s.opts.TLS == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("mail: %s doesn't support STARTTLS", addr)
+		}
+		if err := c.StartTLS(s.opts.TLSConfig); err != nil {
+			return err
+		}
+	}
+	if s.opts.Username != "" {
+		// PLAIN authentication is refused by net/smtp for unencrypted
+		// connections to hosts other than localhost
+		err := c.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(env.from); err != nil {
+		return err
+	}
+	for _, to := range env.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,89 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package memcached
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// CacheStore is the shared tier of tiered caches (see cache.NewTiered)
+type CacheStore struct {
+	client *Client
+	prefix string
+}
+
+// NewCacheStore creates a store using the passed client, the keys
+// are prefixed with prefix (e.g. "<service>:cache:<name>:")
+func NewCacheStore(client *Client, prefix string) *CacheStore {
+	return &CacheStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of key, false if it doesn't exist
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.client.Get(ctx, s.prefix+key)
+}
+
+// Set stores the value of key that expires after ttl (never if 0)
+func (s *CacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, ttl)
+}
+
+// Delete removes the passed keys
+func (s *CacheStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	return s.client.Delete(ctx, prefixed...)
+}
+
+// CacheLocker makes sure that only one replica computes the value of an
+// uncached key of a tiered cache (see cache.TieredOptions). The lock is
+// acquired by adding the key with a random token, it is released by
+// deleting the key only if it still holds the token or expires after
+// the ttl.
+type CacheLocker struct {
+	client *Client
+	prefix string
+}
+
+// NewCacheLocker creates a cache locker using the passed client, the
+// locks are prefixed with prefix (e.g. "<service>:cache:<name>:lock:")
+func NewCacheLocker(client *Client, prefix string) *CacheLocker {
+	return &CacheLocker{client: client, prefix: prefix}
+}
+
+// TryLock acquires the lock of key that expires after ttl, false is
+// returned if the lock is held by another replica
+func (l *CacheLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	lockKey := l.prefix + key
+	token, err := lockToken()
+	if err != nil {
+		return nil, false, err
+	}
+	ok, err := l.client.Add(ctx, lockKey, token, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return func() {
+		// the lock may have expired and been acquired by another replica
+		if _, err := l.client.CompareAndDelete(ctxutil.Detached(ctx), lockKey, token); err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("key", lockKey).Msg("Failed to release cache lock")
+		}
+	}, true, nil
+}
+
+func lockToken() ([]byte, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,486 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/pkg/hashring"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// Options of a client
+type Options struct {
+	// Servers are the addresses (host:port) of the memcached servers,
+	// the keys are distributed by a consistent hash ring
+	Servers []string
+	// Timeout is the maximum duration of the dial, write
+	// and read of a command
+	Timeout time.Duration
+	// MaxIdleConns is the maximum number of idle connections per server
+	MaxIdleConns int
+}
+
+// Client of memcached servers, it is safe for concurrent use
+type Client struct {
+	servers      []string
+	ring         *hashring.Ring
+	timeout      time.Duration
+	maxIdleConns int
+
+	mu   sync.Mutex
+	idle map[string][]*conn
+}
+
+type conn struct {
+	nc   net.Conn
+	rw   *bufio.ReadWriter
+	addr string
+}
+
+// NewClient returns a client of the servers configured by the environment
+func NewClient() *Client {
+	mustSetup()
+	return CustomClient(Options{
+		Servers:      cfg.Servers,
+		Timeout:      cfg.Timeout,
+		MaxIdleConns: cfg.MaxIdleConns,
+	})
+}
+
+// CustomClient returns a client with the passed options
+func CustomClient(opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 500 * time.Millisecond
+	}
+	if opts.MaxIdleConns <= 0 {
+		opts.MaxIdleConns = 2
+	}
+	log.Logger().Info().Strs("servers", opts.Servers).
+		Msg("Memcached connection pool created")
+	ring := hashring.New(0)
+	ring.Set(opts.Servers...)
+	return &Client{
+		servers:      opts.Servers,
+		ring:         ring,
+		timeout:      opts.Timeout,
+		maxIdleConns: opts.MaxIdleConns,
+		idle:         make(map[string][]*conn),
+	}
+}
+
+// Servers returns the addresses of the servers
+func (c *Client) Servers() []string {
+	return c.servers
+}
+
+// Get returns the value of key, false if it doesn't exist
+func (c *Client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var (
+		value []byte
+		found bool
+	)
+	err := c.do(ctx, "get", key, func(cn *conn) error {
+		if _, err := fmt.Fprintf(cn.rw, "get %s\r\n", key); err != nil {
+			return err
+		}
+		if err := cn.rw.Flush(); err != nil {
+			return err
+		}
+		var err error
+		value, found, err = cn.readValue(key)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return value, found, nil
+}
+
+// Set stores the value of key that expires after ttl (never if 0)
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.do(ctx, "set", key, func(cn *conn) error {
+		return cn.store("set", key, value, ttl)
+	})
+}
+
+// Add stores the value of key that expires after ttl (never if 0)
+// only if the key doesn't exist, false is returned if it exists
+func (c *Client) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	err := c.do(ctx, "add", key, func(cn *conn) error {
+		return cn.store("add", key, value, ttl)
+	})
+	if err == errNotStored {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndDelete removes key only if its value equals value, false
+// is returned if the key doesn't exist or has a different value
+func (c *Client) CompareAndDelete(ctx context.Context, key string, value []byte) (bool, error) {
+	var deleted bool
+	err := c.do(ctx, "compare_and_delete", key, func(cn *conn) error {
+		if _, err := fmt.Fprintf(cn.rw, "gets %s\r\n", key); err != nil {
+			return err
+		}
+		if err := cn.rw.Flush(); err != nil {
+			return err
+		}
+		cur, cas, found, err := cn.readValueCAS(key)
+		if err != nil || !found || !bytes.Equal(cur, value) {
+			return err
+		}
+
+		// memcached has no delete with cas unique, replace the
+		// value with one that expired already if it is unchanged
+		if _, err := fmt.Fprintf(cn.rw, "cas %s 0 -1 0 %d\r\n\r\n", key, cas); err != nil {
+			return err
+		}
+		if err := cn.rw.Flush(); err != nil {
+			return err
+		}
+		line, err := cn.readLine()
+		if err != nil {
+			return err
+		}
+		switch line {
+		case "STORED":
+			deleted = true
+			return nil
+		case "EXISTS", "NOT_FOUND":
+			return nil
+		}
+		return fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+	})
+	return deleted, err
+}
+
+// Delete removes the passed keys, missing keys are ignored
+func (c *Client) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		err := c.do(ctx, "delete", key, func(cn *conn) error {
+			if _, err := fmt.Fprintf(cn.rw, "delete %s\r\n", key); err != nil {
+				return err
+			}
+			if err := cn.rw.Flush(); err != nil {
+				return err
+			}
+			line, err := cn.readLine()
+			if err != nil {
+				return err
+			}
+			if line != "DELETED" && line != "NOT_FOUND" {
+				return fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Version returns the version of the server with the passed address
+func (c *Client) Version(ctx context.Context, addr string) (string, error) {
+	var version string
+	err := c.doAddr(ctx, "version", addr, "", func(cn *conn) error {
+		if _, err := io.WriteString(cn.rw, "version\r\n"); err != nil {
+			return err
+		}
+		if err := cn.rw.Flush(); err != nil {
+			return err
+		}
+		line, err := cn.readLine()
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(line, "VERSION ") {
+			return fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+		}
+		version = strings.TrimPrefix(line, "VERSION ")
+		return nil
+	})
+	return version, err
+}
+
+// Close closes the idle connections
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for addr, conns := range c.idle {
+		for _, cn := range conns {
+			cn.nc.Close() // nolint: errcheck
+		}
+		delete(c.idle, addr)
+	}
+	return nil
+}
+
+// server returns the address of the server of key
+func (c *Client) server(key string) (string, error) {
+	addr, ok := c.ring.Get(key)
+	if !ok {
+		return "", errors.New("memcached: no servers configured")
+	}
+	return addr, nil
+}
+
+// do executes the command on the server of key
+func (c *Client) do(ctx context.Context, cmd, key string, fn func(cn *conn) error) error {
+	if !validKey(key) {
+		return ErrMalformedKey
+	}
+	addr, err := c.server(key)
+	if err != nil {
+		return err
+	}
+	return c.doAddr(ctx, cmd, addr, key, fn)
+}
+
+// doAddr executes the command on the server with the passed address
+// with logging, tracing and metrics
+func (c *Client) doAddr(ctx context.Context, cmd, addr, key string, fn func(cn *conn) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Memcached: "+cmd)
+	defer span.Finish()
+	ext.DBType.Set(span, "memcached")
+	ext.PeerAddress.Set(span, addr)
+	span.LogFields(olog.String("cmd", cmd), olog.String("key", key))
+
+	startTime := time.Now()
+	err := c.withConn(ctx, addr, fn)
+	elapsed := time.Since(startTime)
+
+	paceMemcachedCmdTotal.With(prometheus.Labels{"method": cmd}).Inc()
+	paceMemcachedCmdDurationSeconds.With(prometheus.Labels{"method": cmd}).Observe(elapsed.Seconds())
+	if failed(err) {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		paceMemcachedCmdFailed.With(prometheus.Labels{"method": cmd}).Inc()
+	}
+
+	level := zerolog.DebugLevel
+	switch {
+	case failed(err):
+		level = zerolog.ErrorLevel
+	case cfg.SlowCommandThreshold > 0 && elapsed >= cfg.SlowCommandThreshold:
+		level = zerolog.WarnLevel
+	}
+	le := log.Ctx(ctx).WithLevel(level).
+		Float64("duration", float64(elapsed)/float64(time.Millisecond)).
+		Str("cmd", cmd).Str("addr", addr)
+	if failed(err) {
+		le = le.Err(err)
+	}
+	le.Msg("Memcached command")
+
+	return err
+}
+
+// withConn passes a connection to the server to fn, the connection
+// is reused unless fn failed with an I/O, protocol or client error
+func (c *Client) withConn(ctx context.Context, addr string, fn func(cn *conn) error) error {
+	cn, err := c.getConn(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.nc.SetDeadline(deadline); err != nil {
+		cn.nc.Close() // nolint: errcheck
+		return err
+	}
+
+	err = fn(cn)
+	if reply, ok := err.(*Error); err == nil || err == errNotStored || (ok && reply.reusable()) {
+		c.putConn(cn)
+	} else {
+		cn.nc.Close() // nolint: errcheck
+	}
+	return err
+}
+
+func (c *Client) getConn(ctx context.Context, addr string) (*conn, error) {
+	c.mu.Lock()
+	if conns := c.idle[addr]; len(conns) > 0 {
+		cn := conns[len(conns)-1]
+		c.idle[addr] = conns[:len(conns)-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("memcached %s: %v", addr, err)
+	}
+	return &conn{
+		nc:   nc,
+		rw:   bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc)),
+		addr: addr,
+	}, nil
+}
+
+func (c *Client) putConn(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle[cn.addr]) >= c.maxIdleConns {
+		cn.nc.Close() // nolint: errcheck
+		return
+	}
+	c.idle[cn.addr] = append(c.idle[cn.addr], cn)
+}
+
+// store sends a storage command (set, add)
+func (cn *conn) store(cmd, key string, value []byte, ttl time.Duration) error {
+	_, err := fmt.Fprintf(cn.rw, "%s %s 0 %d %d\r\n", cmd, key, expiration(ttl), len(value))
+	if err != nil {
+		return err
+	}
+	if _, err := cn.rw.Write(value); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(cn.rw, "\r\n"); err != nil {
+		return err
+	}
+	if err := cn.rw.Flush(); err != nil {
+		return err
+	}
+
+	line, err := cn.readLine()
+	if err != nil {
+		return err
+	}
+	switch line {
+	case "STORED":
+		return nil
+	case "NOT_STORED":
+		return errNotStored
+	}
+	return fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+}
+
+// readValue reads the reply of a get command of a single key
+func (cn *conn) readValue(key string) ([]byte, bool, error) {
+	value, _, found, err := cn.readValueCAS(key)
+	return value, found, err
+}
+
+// readValueCAS reads the reply of a get or gets command of a
+// single key, the cas unique is only returned for gets
+func (cn *conn) readValueCAS(key string) ([]byte, uint64, bool, error) {
+	line, err := cn.readLine()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if line == "END" {
+		return nil, 0, false, nil
+	}
+
+	// VALUE <key> <flags> <bytes> [<cas unique>]
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "VALUE" || fields[1] != key {
+		return nil, 0, false, fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+	}
+	size, err := strconv.Atoi(fields[3])
+	if err != nil || size < 0 {
+		return nil, 0, false, fmt.Errorf("memcached %s: invalid value size %q", cn.addr, fields[3])
+	}
+	var cas uint64
+	if len(fields) > 4 {
+		cas, err = strconv.ParseUint(fields[4], 10, 64)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("memcached %s: invalid cas unique %q", cn.addr, fields[4])
+		}
+	}
+	value := make([]byte, size+2)
+	if _, err := io.ReadFull(cn.rw, value); err != nil {
+		return nil, 0, false, err
+	}
+	if !bytes.HasSuffix(value, []byte("\r\n")) {
+		return nil, 0, false, fmt.Errorf("memcached %s: corrupt value", cn.addr)
+	}
+
+	line, err = cn.readLine()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if line != "END" {
+		return nil, 0, false, fmt.Errorf("memcached %s: unexpected reply %q", cn.addr, line)
+	}
+	return value[:size], cas, true, nil
+}
+
+// readLine reads a reply line, error replies are returned as *Error
+func (cn *conn) readLine() (string, error) {
+	line, err := cn.rw.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "ERROR" || strings.HasPrefix(line, "CLIENT_ERROR ") || strings.HasPrefix(line, "SERVER_ERROR ") {
+		return "", &Error{Addr: cn.addr, Reply: line}
+	}
+	return line, nil
+}
+
+// maxRelativeExpiration is the maximum expiration in seconds that is
+// interpreted as relative by the servers, longer expirations need to
+// be passed as unix timestamp
+const maxRelativeExpiration = 60 * 60 * 24 * 30
+
+// expiration returns the exptime of the ttl, ttls
+// below a second are rounded up to not expire never
+func expiration(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+	if seconds > maxRelativeExpiration {
+		return time.Now().Add(ttl).Unix()
+	}
+	return seconds
+}
+
+// validKey returns false for keys that are rejected by the servers
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > 250 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// failed returns true if err is a failure of the command, keys
+// that were not stored by add are a result and not a failure
+func failed(err error) bool {
+	return err != nil && err != errNotStored
+}
@@ -0,0 +1,97 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package memcached
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceMemcachedHealthCheckDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("memcached_health_check_duration_seconds"),
+			Help:    "Collect performance metrics for each health check (version) of the memcached servers",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"addr"},
+	)
+	paceMemcachedHealthCheckUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("memcached_health_check_up"),
+			Help: "Result of the last health check of the memcached servers, 1 if healthy 0 otherwise",
+		},
+		[]string{"addr"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceMemcachedHealthCheckDurationSeconds)
+	prometheus.MustRegister(paceMemcachedHealthCheckUp)
+}
+
+var (
+	healthClientOnce sync.Once
+	healthClient     *Client
+)
+
+// HealthCheck checks the memcached servers configured using the
+// environment (see NewClient), it can be registered at the readiness
+// endpoint using health.RegisterCheck("memcached", memcached.HealthCheck)
+func HealthCheck(ctx context.Context) error {
+	if err := Setup(); err != nil {
+		return err
+	}
+	healthClientOnce.Do(func() {
+		healthClient = NewClient()
+	})
+	return check(ctx, healthClient)
+}
+
+// ClientHealthCheck returns a health check of the servers of the client
+func ClientHealthCheck(client *Client) health.Check {
+	return func(ctx context.Context) error {
+		return check(ctx, client)
+	}
+}
+
+// RegisterHealthCheck registers the health check of the passed client
+// at the readiness endpoint (see health.RegisterCheck)
+func RegisterHealthCheck(client *Client) {
+	health.RegisterCheck("memcached version", ClientHealthCheck(client))
+}
+
+// check requests the version of all servers, as each server holds a
+// part of the keys the check fails if one of them fails. It fails after
+// MEMCACHED_HEALTH_CHECK_TIMEOUT or when ctx is done.
+func check(ctx context.Context, client *Client) error {
+	if cfg.HealthCheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.HealthCheckTimeout)
+		defer cancel()
+	}
+
+	var firstErr error
+	for _, addr := range client.Servers() {
+		start := time.Now()
+		_, err := client.Version(ctx, addr)
+
+		paceMemcachedHealthCheckDurationSeconds.With(prometheus.Labels{"addr": addr}).
+			Observe(time.Since(start).Seconds())
+		up := 1.0
+		if err != nil {
+			up = 0
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		paceMemcachedHealthCheckUp.With(prometheus.Labels{"addr": addr}).Set(up)
+	}
+	return firstErr
+}
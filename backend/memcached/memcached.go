@@ -0,0 +1,110 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package memcached is a client of memcached servers, it implements the
+// shared store and locker of the tiered caches of pkg/cache for
+// deployments that use memcached rather than redis. The commands are
+// instrumented with metrics, logging and tracing.
+package memcached
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	Servers []string `env:"MEMCACHED_SERVERS" envSeparator:"," envDefault:"memcached:11211"`
+	// Maximum duration of the dial, write and read of a command
+	Timeout time.Duration `env:"MEMCACHED_TIMEOUT" envDefault:"500ms"`
+	// Maximum number of idle connections per server
+	MaxIdleConns int `env:"MEMCACHED_MAX_IDLE_CONNS" envDefault:"10"`
+	// Commands that take longer are logged with warn level.
+	// 0 disables slow command logging.
+	SlowCommandThreshold time.Duration `env:"MEMCACHED_SLOW_COMMAND_THRESHOLD" envDefault:"100ms"`
+	// Maximum duration of the health check (version).
+	HealthCheckTimeout time.Duration `env:"MEMCACHED_HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+}
+
+var (
+	paceMemcachedCmdTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("memcached_cmd_total"),
+			Help: "Collects stats about the number of memcached commands made",
+		},
+		[]string{"method"},
+	)
+	paceMemcachedCmdFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("memcached_cmd_failed"),
+			Help: "Collects stats about the number of memcached commands failed",
+		},
+		[]string{"method"},
+	)
+	paceMemcachedCmdDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("memcached_cmd_duration_seconds"),
+			Help:    "Collect performance metrics for each method",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		[]string{"method"},
+	)
+)
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+func init() {
+	prometheus.MustRegister(paceMemcachedCmdTotal)
+	prometheus.MustRegister(paceMemcachedCmdFailed)
+	prometheus.MustRegister(paceMemcachedCmdDurationSeconds)
+	configcheck.Register("memcached", Setup)
+}
+
+// Setup parses the MEMCACHED_* environment of the client, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse memcached environment: %v", err)
+	}
+}
+
+// ErrMalformedKey is returned for keys that are longer than 250 bytes
+// or contain whitespace or control characters
+var ErrMalformedKey = errors.New("memcached: malformed key")
+
+// errNotStored is the reply of add if the key exists
+var errNotStored = errors.New("memcached: not stored")
+
+// Error is a CLIENT_ERROR, SERVER_ERROR or ERROR reply of the server
+type Error struct {
+	Addr  string
+	Reply string
+}
+
+func (e *Error) Error() string {
+	return "memcached " + e.Addr + ": " + e.Reply
+}
+
+// reusable returns true if the connection can be used for further
+// commands. After CLIENT_ERROR and ERROR the server may still read
+// the remainder of the command (e.g. the data block) as commands.
+func (e *Error) reusable() bool {
+	return strings.HasPrefix(e.Reply, "SERVER_ERROR ")
+}
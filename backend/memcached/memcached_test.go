@@ -0,0 +1,343 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/pkg/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ cache.Store  = (*CacheStore)(nil)
+	_ cache.Locker = (*CacheLocker)(nil)
+)
+
+// server is an in-memory memcached server implementing the text
+// protocol commands used by the client
+type server struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	items map[string][]byte
+	cas   map[string]uint64
+	next  uint64
+	conns int
+}
+
+func newServer(t *testing.T) *server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{ln: ln, items: make(map[string][]byte), cas: make(map[string]uint64)}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *server) addr() string { return s.ln.Addr().String() }
+
+func (s *server) serve(c net.Conn) {
+	defer c.Close()
+	s.mu.Lock()
+	s.conns++
+	s.mu.Unlock()
+	r := bufio.NewReader(c)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		switch fields[0] {
+		case "get":
+			if v, ok := s.items[fields[1]]; ok {
+				fmt.Fprintf(c, "VALUE %s 0 %d\r\n%s\r\n", fields[1], len(v), v)
+			}
+			io.WriteString(c, "END\r\n") // nolint: errcheck
+		case "gets":
+			if v, ok := s.items[fields[1]]; ok {
+				fmt.Fprintf(c, "VALUE %s 0 %d %d\r\n%s\r\n", fields[1], len(v), s.cas[fields[1]], v)
+			}
+			io.WriteString(c, "END\r\n") // nolint: errcheck
+		case "set", "add", "cas":
+			size, _ := strconv.Atoi(fields[4])
+			data := make([]byte, size+2)
+			io.ReadFull(r, data) // nolint: errcheck
+			_, exists := s.items[fields[1]]
+			if fields[0] == "add" && exists {
+				io.WriteString(c, "NOT_STORED\r\n") // nolint: errcheck
+				break
+			}
+			if fields[0] == "cas" {
+				if !exists {
+					io.WriteString(c, "NOT_FOUND\r\n") // nolint: errcheck
+					break
+				}
+				if fields[5] != strconv.FormatUint(s.cas[fields[1]], 10) {
+					io.WriteString(c, "EXISTS\r\n") // nolint: errcheck
+					break
+				}
+			}
+			if fields[3] == "-1" { // expired immediately
+				delete(s.items, fields[1])
+			} else {
+				s.next++
+				s.items[fields[1]] = data[:size]
+				s.cas[fields[1]] = s.next
+			}
+			io.WriteString(c, "STORED\r\n") // nolint: errcheck
+		case "delete":
+			if _, ok := s.items[fields[1]]; ok {
+				delete(s.items, fields[1])
+				io.WriteString(c, "DELETED\r\n") // nolint: errcheck
+			} else {
+				io.WriteString(c, "NOT_FOUND\r\n") // nolint: errcheck
+			}
+		case "version":
+			io.WriteString(c, "VERSION 1.6.21\r\n") // nolint: errcheck
+		case "client_error":
+			io.WriteString(c, "CLIENT_ERROR bad command line format\r\n") // nolint: errcheck
+		case "server_error":
+			io.WriteString(c, "SERVER_ERROR out of memory\r\n") // nolint: errcheck
+		default:
+			io.WriteString(c, "ERROR\r\n") // nolint: errcheck
+		}
+		s.mu.Unlock()
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestClient(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	ctx := context.Background()
+
+	gets := counterValue(t, paceMemcachedCmdTotal.With(prometheus.Labels{"method": "get"}))
+
+	_, found, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("line\r\nbreak"), time.Minute))
+	value, found, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "line\r\nbreak", string(value))
+
+	added, err := c.Add(ctx, "a", []byte("other"), 0)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	assert.NoError(t, c.Delete(ctx, "a", "missing"))
+	added, err = c.Add(ctx, "a", []byte("other"), 0)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	_, _, err = c.Get(ctx, "has space")
+	assert.Equal(t, ErrMalformedKey, err)
+
+	assert.Equal(t, gets+2, counterValue(t, paceMemcachedCmdTotal.With(prometheus.Labels{"method": "get"})))
+
+	// all commands used a single connection
+	assert.Len(t, c.idle[s.addr()], 1)
+}
+
+func TestClientErrorClosesConn(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	ctx := context.Background()
+
+	reply := func(cmd string) error {
+		return c.do(ctx, cmd, "key", func(cn *conn) error {
+			if _, err := fmt.Fprintf(cn.rw, "%s\r\n", cmd); err != nil {
+				return err
+			}
+			if err := cn.rw.Flush(); err != nil {
+				return err
+			}
+			_, err := cn.readLine()
+			return err
+		})
+	}
+
+	// the connection is reused after a server error
+	assert.IsType(t, &Error{}, reply("server_error"))
+	assert.Len(t, c.idle[s.addr()], 1)
+
+	// but not after a client error
+	assert.IsType(t, &Error{}, reply("client_error"))
+	assert.Len(t, c.idle[s.addr()], 0)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("b"), 0))
+	s.mu.Lock()
+	assert.Equal(t, 2, s.conns)
+	s.mu.Unlock()
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	ctx := context.Background()
+
+	deleted, err := c.CompareAndDelete(ctx, "a", []byte("1"))
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("2"), 0))
+	deleted, err = c.CompareAndDelete(ctx, "a", []byte("1"))
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = c.CompareAndDelete(ctx, "a", []byte("2"))
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, found, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCacheLockerUnlock(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	ctx := context.Background()
+	l := NewCacheLocker(c, "lock:")
+
+	unlock, ok, err := l.TryLock(ctx, "key", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	_, ok, err = l.TryLock(ctx, "key", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the lock expired and was acquired by another replica
+	s.mu.Lock()
+	delete(s.items, "lock:key")
+	s.mu.Unlock()
+	_, ok, err = l.TryLock(ctx, "key", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// which keeps its lock when the first one unlocks
+	unlock()
+	s.mu.Lock()
+	_, locked := s.items["lock:key"]
+	s.mu.Unlock()
+	assert.True(t, locked)
+}
+
+func TestSharding(t *testing.T) {
+	servers := []string{"a:11211", "b:11211", "c:11211"}
+	c := CustomClient(Options{Servers: servers})
+	moved := CustomClient(Options{Servers: servers[:2]})
+
+	// keys of the remaining servers stay on them
+	for i := 0; i < 1000; i++ {
+		key := "key" + strconv.Itoa(i)
+		addr, err := c.server(key)
+		assert.NoError(t, err)
+		if addr == servers[2] {
+			continue
+		}
+		other, err := moved.server(key)
+		assert.NoError(t, err)
+		assert.Equal(t, addr, other)
+	}
+
+	_, err := CustomClient(Options{}).server("key")
+	assert.Error(t, err)
+}
+
+func TestTieredCache(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	ctx := context.Background()
+
+	tiered := cache.NewTiered("memcached-test", NewCacheStore(c, "test:"), cache.TieredOptions{
+		TTL:    time.Minute,
+		Locker: NewCacheLocker(c, "test:lock:"),
+	})
+
+	computed := 0
+	for i := 0; i < 2; i++ {
+		var v string
+		err := tiered.GetOrCompute(ctx, "key", &v, func(ctx context.Context) (interface{}, error) {
+			computed++
+			return "value", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+	}
+	assert.Equal(t, 1, computed)
+
+	s.mu.Lock()
+	assert.Equal(t, `"value"`, string(s.items["test:key"]))
+	_, locked := s.items["test:lock:key"]
+	s.mu.Unlock()
+	assert.False(t, locked)
+}
+
+func TestHealthCheck(t *testing.T) {
+	assert.NoError(t, Setup())
+	s := newServer(t)
+	c := CustomClient(Options{Servers: []string{s.addr()}})
+	defer c.Close()
+	check := ClientHealthCheck(c)
+
+	assert.NoError(t, check(context.Background()))
+
+	s.ln.Close()
+	c.Close() // nolint: errcheck
+	assert.Error(t, check(context.Background()))
+}
+
+func TestExpiration(t *testing.T) {
+	assert.Equal(t, int64(0), expiration(0))
+	assert.Equal(t, int64(1), expiration(time.Millisecond))
+	assert.Equal(t, int64(60), expiration(time.Minute))
+
+	abs := expiration(31 * 24 * time.Hour)
+	assert.InDelta(t, time.Now().Add(31*24*time.Hour).Unix(), abs, 2)
+}
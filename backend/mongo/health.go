@@ -0,0 +1,59 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceMongoHealthCheckDurationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("mongo_health_check_duration_seconds"),
+			Help:    "Collect performance metrics for each health check (ping) of the mongo servers",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+	)
+	paceMongoHealthCheckUp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: metric.Name("mongo_health_check_up"),
+			Help: "Result of the last health check of the mongo servers, 1 if healthy 0 otherwise",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceMongoHealthCheckDurationSeconds)
+	prometheus.MustRegister(paceMongoHealthCheckUp)
+}
+
+// Pinger pings the primary, e.g. a function calling
+// client.Ping(ctx, readpref.Primary()) of the driver
+type Pinger func(ctx context.Context) error
+
+// HealthCheck returns a health check using the pinger, it can be
+// registered at the readiness endpoint using
+// health.RegisterCheck("mongo", mongo.HealthCheck(ping))
+func HealthCheck(ping Pinger) health.Check {
+	return func(ctx context.Context) error {
+		mustSetup()
+		ctx, cancel := context.WithTimeout(ctx, cfg.HealthCheckTimeout)
+		defer cancel()
+
+		start := time.Now()
+		err := ping(ctx)
+		paceMongoHealthCheckDurationSeconds.Observe(time.Since(start).Seconds())
+		if err != nil {
+			paceMongoHealthCheckUp.Set(0)
+			return err
+		}
+		paceMongoHealthCheckUp.Set(1)
+		return nil
+	}
+}
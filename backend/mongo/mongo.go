@@ -0,0 +1,134 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package mongo configures MongoDB clients using the environment and
+// instruments them with metrics, logging and tracing per command. The
+// protocol is implemented by the driver (go.mongodb.org/mongo-driver), its
+// command and pool monitor hooks are adapted to the CommandMonitor and
+// PoolMonitor of this package by the service.
+package mongo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	URI      string `env:"MONGO_URI" envDefault:"mongodb://mongo:27017"`
+	Database string `env:"MONGO_DATABASE"`
+	AppName  string `env:"MONGO_APP_NAME"`
+	// Connection pool of each server
+	MaxPoolSize     uint64        `env:"MONGO_MAX_POOL_SIZE" envDefault:"100"`
+	MinPoolSize     uint64        `env:"MONGO_MIN_POOL_SIZE" envDefault:"0"`
+	MaxConnIdleTime time.Duration `env:"MONGO_MAX_CONN_IDLE_TIME" envDefault:"0s"`
+	ConnectTimeout  time.Duration `env:"MONGO_CONNECT_TIMEOUT" envDefault:"10s"`
+	// Maximum duration to select a server for an operation
+	ServerSelectionTimeout time.Duration `env:"MONGO_SERVER_SELECTION_TIMEOUT" envDefault:"30s"`
+	// Commands that take longer are logged with warn level.
+	// 0 disables slow command logging.
+	SlowCommandThreshold time.Duration `env:"MONGO_SLOW_COMMAND_THRESHOLD" envDefault:"100ms"`
+	// Maximum duration of the health check (ping)
+	HealthCheckTimeout time.Duration `env:"MONGO_HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the MONGO_* environment of the client and the
+// monitors, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse mongo environment: %v", err)
+	}
+}
+
+var (
+	paceMongoCmdTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("mongo_cmd_total"),
+			Help: "Collects stats about the number of mongo commands partitioned by database and command",
+		},
+		[]string{"database", "command"},
+	)
+	paceMongoCmdFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("mongo_cmd_failed"),
+			Help: "Collects stats about the number of failed mongo commands partitioned by database and command",
+		},
+		[]string{"database", "command"},
+	)
+	paceMongoCmdDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("mongo_cmd_duration_seconds"),
+			Help:    "Collect performance metrics for each command",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database", "command"},
+	)
+	paceMongoPoolConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("mongo_pool_connections"),
+			Help: "Number of open connections of the pools partitioned by state (idle, in_use)",
+		},
+		[]string{"state"},
+	)
+	paceMongoPoolCheckoutFailedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: metric.Name("mongo_pool_checkout_failed_total"),
+			Help: "Collects stats about the number of failed checkouts of connections, e.g. because the pool was exhausted",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceMongoCmdTotal)
+	prometheus.MustRegister(paceMongoCmdFailed)
+	prometheus.MustRegister(paceMongoCmdDurationSeconds)
+	prometheus.MustRegister(paceMongoPoolConnections)
+	prometheus.MustRegister(paceMongoPoolCheckoutFailedTotal)
+	configcheck.Register("mongo", Setup)
+}
+
+// Settings of the environment to configure the client options of the
+// driver, e.g. options.Client().ApplyURI(s.URI).SetMaxPoolSize(s.MaxPoolSize)
+type Settings struct {
+	URI                    string
+	Database               string
+	AppName                string
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+}
+
+// EnvSettings returns the settings of the environment
+func EnvSettings() Settings {
+	mustSetup()
+	return Settings{
+		URI:                    cfg.URI,
+		Database:               cfg.Database,
+		AppName:                cfg.AppName,
+		MaxPoolSize:            cfg.MaxPoolSize,
+		MinPoolSize:            cfg.MinPoolSize,
+		MaxConnIdleTime:        cfg.MaxConnIdleTime,
+		ConnectTimeout:         cfg.ConnectTimeout,
+		ServerSelectionTimeout: cfg.ServerSelectionTimeout,
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestCommandMonitor(t *testing.T) {
+	m := NewCommandMonitor()
+	ctx := context.Background()
+	labels := prometheus.Labels{"database": "shop", "command": "find"}
+	total := counterValue(t, paceMongoCmdTotal.With(labels))
+	failed := counterValue(t, paceMongoCmdFailed.With(labels))
+
+	m.Started(ctx, CommandStartedEvent{DatabaseName: "shop", CommandName: "find", RequestID: 1})
+	m.Started(ctx, CommandStartedEvent{DatabaseName: "shop", CommandName: "find", RequestID: 2})
+	m.Succeeded(ctx, CommandFinishedEvent{CommandName: "find", RequestID: 1, Duration: time.Millisecond})
+	m.Failed(ctx, CommandFinishedEvent{CommandName: "find", RequestID: 2, Duration: time.Second, Failure: "timeout"})
+	assert.Empty(t, m.inflight)
+
+	assert.Equal(t, total+2, counterValue(t, paceMongoCmdTotal.With(labels)))
+	assert.Equal(t, failed+1, counterValue(t, paceMongoCmdFailed.With(labels)))
+
+	// commands started before the monitor are counted without database
+	m.Succeeded(ctx, CommandFinishedEvent{CommandName: "ping", RequestID: 3})
+	assert.Equal(t, 1.0, counterValue(t, paceMongoCmdTotal.With(prometheus.Labels{"database": "", "command": "ping"})))
+}
+
+func TestPoolMonitor(t *testing.T) {
+	idle := paceMongoPoolConnections.With(prometheus.Labels{"state": "idle"})
+	inUse := paceMongoPoolConnections.With(prometheus.Labels{"state": "in_use"})
+	idleBefore, inUseBefore := gaugeValue(t, idle), gaugeValue(t, inUse)
+
+	PoolMonitor(PoolEvent{Type: ConnectionCreated, Address: "mongo:27017", ConnectionID: 1})
+	PoolMonitor(PoolEvent{Type: ConnectionCreated, Address: "mongo:27017", ConnectionID: 2})
+	PoolMonitor(PoolEvent{Type: ConnectionCheckedOut, Address: "mongo:27017", ConnectionID: 1})
+	assert.Equal(t, idleBefore+1, gaugeValue(t, idle))
+	assert.Equal(t, inUseBefore+1, gaugeValue(t, inUse))
+
+	PoolMonitor(PoolEvent{Type: ConnectionCheckedIn, Address: "mongo:27017", ConnectionID: 1})
+	PoolMonitor(PoolEvent{Type: ConnectionClosed, Address: "mongo:27017", ConnectionID: 1})
+	assert.Equal(t, idleBefore+1, gaugeValue(t, idle))
+	assert.Equal(t, inUseBefore, gaugeValue(t, inUse))
+
+	// connections closed while in use are not idle
+	PoolMonitor(PoolEvent{Type: ConnectionCheckedOut, Address: "mongo:27017", ConnectionID: 2})
+	PoolMonitor(PoolEvent{Type: ConnectionClosed, Address: "mongo:27017", ConnectionID: 2})
+	assert.Equal(t, idleBefore, gaugeValue(t, idle))
+	assert.Equal(t, inUseBefore, gaugeValue(t, inUse))
+
+	failed := counterValue(t, paceMongoPoolCheckoutFailedTotal)
+	PoolMonitor(PoolEvent{Type: ConnectionCheckOutFailed, Reason: "timeout"})
+	assert.Equal(t, failed+1, counterValue(t, paceMongoPoolCheckoutFailedTotal))
+}
+
+func TestHealthCheck(t *testing.T) {
+	var err error
+	check := HealthCheck(func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return err
+	})
+
+	assert.NoError(t, check(context.Background()))
+	assert.Equal(t, 1.0, gaugeValue(t, paceMongoHealthCheckUp))
+
+	err = errors.New("server selection timeout")
+	assert.Equal(t, err, check(context.Background()))
+	assert.Equal(t, 0.0, gaugeValue(t, paceMongoHealthCheckUp))
+}
@@ -0,0 +1,195 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// CommandStartedEvent is passed to the monitor when a command is sent,
+// the fields equal the fields of event.CommandStartedEvent of the driver
+type CommandStartedEvent struct {
+	DatabaseName string
+	CommandName  string
+	RequestID    int64
+	ConnectionID string
+}
+
+// CommandFinishedEvent is passed to the monitor when a command succeeded
+// or failed, the fields equal the fields of event.CommandSucceededEvent
+// and event.CommandFailedEvent of the driver
+type CommandFinishedEvent struct {
+	CommandName  string
+	RequestID    int64
+	ConnectionID string
+	Duration     time.Duration
+	// Failure of failed commands
+	Failure string
+}
+
+// CommandMonitor traces, logs and counts the commands of a client. The
+// command documents are not traced or logged, as they may contain
+// personal data.
+type CommandMonitor struct {
+	mu       sync.Mutex
+	inflight map[int64]*command
+}
+
+type command struct {
+	span     opentracing.Span
+	ctx      context.Context
+	database string
+}
+
+// NewCommandMonitor returns a monitor for a client
+func NewCommandMonitor() *CommandMonitor {
+	mustSetup()
+	return &CommandMonitor{inflight: make(map[int64]*command)}
+}
+
+// Started starts the span of the command, ctx is the
+// context of the operation passed by the driver
+func (m *CommandMonitor) Started(ctx context.Context, e CommandStartedEvent) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Mongo: "+e.CommandName)
+	ext.DBType.Set(span, "mongo")
+	ext.DBInstance.Set(span, e.DatabaseName)
+	if e.ConnectionID != "" {
+		ext.PeerAddress.Set(span, e.ConnectionID)
+	}
+	span.LogFields(olog.String("cmd", e.CommandName))
+
+	m.mu.Lock()
+	m.inflight[e.RequestID] = &command{span: span, ctx: ctx, database: e.DatabaseName}
+	m.mu.Unlock()
+}
+
+// Succeeded finishes the span of the command and collects the metrics
+func (m *CommandMonitor) Succeeded(ctx context.Context, e CommandFinishedEvent) {
+	m.finish(ctx, e, nil)
+}
+
+// Failed finishes the span of the command and collects the metrics
+func (m *CommandMonitor) Failed(ctx context.Context, e CommandFinishedEvent) {
+	failure := e.Failure
+	if failure == "" {
+		failure = "unknown failure"
+	}
+	m.finish(ctx, e, errors.New(failure))
+}
+
+func (m *CommandMonitor) finish(ctx context.Context, e CommandFinishedEvent, err error) {
+	m.mu.Lock()
+	cmd, ok := m.inflight[e.RequestID]
+	delete(m.inflight, e.RequestID)
+	m.mu.Unlock()
+	if !ok {
+		// started before the monitor was registered
+		cmd = &command{ctx: ctx}
+	}
+
+	labels := prometheus.Labels{"database": cmd.database, "command": e.CommandName}
+	paceMongoCmdTotal.With(labels).Inc()
+	paceMongoCmdDurationSeconds.With(labels).Observe(e.Duration.Seconds())
+	if err != nil {
+		paceMongoCmdFailed.With(labels).Inc()
+	}
+
+	if cmd.span != nil {
+		if err != nil {
+			ext.Error.Set(cmd.span, true)
+			cmd.span.LogFields(olog.Error(err))
+		}
+		cmd.span.Finish()
+	}
+
+	level := zerolog.DebugLevel
+	switch {
+	case err != nil:
+		level = zerolog.ErrorLevel
+	case cfg.SlowCommandThreshold > 0 && e.Duration >= cfg.SlowCommandThreshold:
+		level = zerolog.WarnLevel
+	}
+	le := log.Ctx(cmd.ctx).WithLevel(level).
+		Float64("duration", float64(e.Duration)/float64(time.Millisecond)).
+		Str("database", cmd.database).Str("cmd", e.CommandName)
+	if err != nil {
+		le = le.Err(err)
+	}
+	le.Msg("Mongo command")
+}
+
+// Types of pool events, equal to the types of event.PoolEvent of the driver
+const (
+	ConnectionCreated        = "ConnectionCreated"
+	ConnectionClosed         = "ConnectionClosed"
+	ConnectionCheckedOut     = "ConnectionCheckedOut"
+	ConnectionCheckedIn      = "ConnectionCheckedIn"
+	ConnectionCheckOutFailed = "ConnectionCheckOutFailed"
+)
+
+// PoolEvent is passed to the pool monitor, the fields equal
+// the fields of event.PoolEvent of the driver
+type PoolEvent struct {
+	Type         string
+	Address      string
+	ConnectionID uint64
+	Reason       string
+}
+
+// poolConn identifies a connection of the pools
+type poolConn struct {
+	address string
+	id      uint64
+}
+
+var (
+	checkedOutMu sync.Mutex
+	checkedOut   = make(map[poolConn]bool)
+)
+
+// PoolMonitor collects the metrics of the connection pools of a client,
+// it is passed the events of event.PoolMonitor of the driver. The checked
+// out connections are tracked, so that connections closed while in use
+// are not counted as idle.
+func PoolMonitor(e PoolEvent) {
+	idle := paceMongoPoolConnections.With(prometheus.Labels{"state": "idle"})
+	inUse := paceMongoPoolConnections.With(prometheus.Labels{"state": "in_use"})
+	conn := poolConn{address: e.Address, id: e.ConnectionID}
+
+	checkedOutMu.Lock()
+	defer checkedOutMu.Unlock()
+	switch e.Type {
+	case ConnectionCreated:
+		idle.Inc()
+	case ConnectionClosed:
+		if checkedOut[conn] {
+			delete(checkedOut, conn)
+			inUse.Dec()
+		} else {
+			idle.Dec()
+		}
+	case ConnectionCheckedOut:
+		checkedOut[conn] = true
+		idle.Dec()
+		inUse.Inc()
+	case ConnectionCheckedIn:
+		delete(checkedOut, conn)
+		inUse.Dec()
+		idle.Inc()
+	case ConnectionCheckOutFailed:
+		paceMongoPoolCheckoutFailedTotal.Inc()
+		log.Logger().Warn().Str("addr", e.Address).Str("reason", e.Reason).
+			Msg("Failed to check out mongo connection")
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package objstore
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/transport"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options of a client
+type Options struct {
+	// Endpoint is the URL of the storage, e.g. https://s3.eu-central-1.amazonaws.com
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket with the path instead of the host name
+	PathStyle bool
+	// PresignExpiry is the default duration presigned URLs are valid
+	PresignExpiry time.Duration
+	// HTTPClient is used for the requests, the default client traces and
+	// logs the requests. Requests are not retried, as uploads are streamed.
+	HTTPClient *http.Client
+}
+
+// Client of a bucket
+type Client struct {
+	bucket        string
+	endpoint      *url.URL
+	pathStyle     bool
+	presignExpiry time.Duration
+	signer        signer
+	httpClient    *http.Client
+}
+
+// NewClient returns a client of the bucket configured by the environment
+func NewClient() (*Client, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	return CustomClient(Options{
+		Endpoint:        cfg.Endpoint,
+		Region:          cfg.Region,
+		Bucket:          cfg.Bucket,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+		PathStyle:       cfg.PathStyle,
+		PresignExpiry:   cfg.PresignExpiry,
+	})
+}
+
+// CustomClient returns a client with the passed options
+func CustomClient(opts Options) (*Client, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("objstore: bucket is required")
+	}
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("objstore: invalid endpoint: %v", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("objstore: invalid endpoint %q", opts.Endpoint)
+	}
+	if opts.PresignExpiry <= 0 {
+		opts.PresignExpiry = 15 * time.Minute
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{
+			Transport: transport.Chain(&transport.JaegerRoundTripper{}, &transport.LoggingRoundTripper{}),
+		}
+	}
+	return &Client{
+		bucket:        opts.Bucket,
+		endpoint:      endpoint,
+		pathStyle:     opts.PathStyle,
+		presignExpiry: opts.PresignExpiry,
+		signer: signer{
+			accessKeyID:     opts.AccessKeyID,
+			secretAccessKey: opts.SecretAccessKey,
+			region:          opts.Region,
+		},
+		httpClient: opts.HTTPClient,
+	}, nil
+}
+
+// Bucket returns the name of the bucket
+func (c *Client) Bucket() string {
+	return c.bucket
+}
+
+// Put uploads the object, the content of r is streamed and needs to have
+// the passed size. Existing objects are replaced.
+func (c *Client) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	span, ctx := c.startSpan(ctx, "put", key)
+	defer span.Finish()
+
+	body := &countingReader{r: r}
+	req, err := c.newRequest(ctx, "PUT", key, nil, ioutil.NopCloser(body))
+	if err != nil {
+		return c.finish(span, "put", time.Now(), err)
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	start := time.Now()
+	resp, err := c.do(req, unsignedPayload)
+	c.countBytes("put", body.n)
+	if err != nil {
+		return c.finish(span, "put", start, err)
+	}
+	resp.Body.Close() // nolint: errcheck
+	return c.finish(span, "put", start, nil)
+}
+
+// Get downloads the object, the content is streamed and the returned
+// reader needs to be closed. ErrNotFound is returned if the object
+// doesn't exist.
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	span, ctx := c.startSpan(ctx, "get", key)
+	defer span.Finish()
+
+	start := time.Now()
+	req, err := c.newRequest(ctx, "GET", key, nil, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, c.finish(span, "get", start, err)
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, ObjectInfo{}, c.finish(span, "get", start, err)
+	}
+	c.finish(span, "get", start, nil) // nolint: errcheck
+	return &countingReadCloser{ReadCloser: resp.Body, client: c}, objectInfo(key, resp), nil
+}
+
+// Stat returns the info of the object, ErrNotFound is
+// returned if the object doesn't exist
+func (c *Client) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	span, ctx := c.startSpan(ctx, "stat", key)
+	defer span.Finish()
+
+	start := time.Now()
+	req, err := c.newRequest(ctx, "HEAD", key, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, c.finish(span, "stat", start, err)
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return ObjectInfo{}, c.finish(span, "stat", start, err)
+	}
+	resp.Body.Close() // nolint: errcheck
+	return objectInfo(key, resp), c.finish(span, "stat", start, nil)
+}
+
+// Delete deletes the object, deleting objects that
+// don't exist is not an error
+func (c *Client) Delete(ctx context.Context, key string) error {
+	span, ctx := c.startSpan(ctx, "delete", key)
+	defer span.Finish()
+
+	start := time.Now()
+	req, err := c.newRequest(ctx, "DELETE", key, nil, nil)
+	if err != nil {
+		return c.finish(span, "delete", start, err)
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err == ErrNotFound {
+		err = nil
+	}
+	if err != nil {
+		return c.finish(span, "delete", start, err)
+	}
+	if resp != nil {
+		resp.Body.Close() // nolint: errcheck
+	}
+	return c.finish(span, "delete", start, nil)
+}
+
+type listResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		ETag         string    `xml:"ETag"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List passes the infos of the objects with the prefix to fn in
+// lexicographical order of the keys. The objects are requested page
+// by page, listing stops if fn returns an error.
+func (c *Client) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	span, ctx := c.startSpan(ctx, "list", prefix)
+	defer span.Finish()
+
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		start := time.Now()
+		req, err := c.newRequest(ctx, "GET", "", query, nil)
+		if err != nil {
+			return c.finish(span, "list", start, err)
+		}
+		resp, err := c.do(req, emptyPayloadHash)
+		if err != nil {
+			return c.finish(span, "list", start, err)
+		}
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close() // nolint: errcheck
+		if err != nil {
+			return c.finish(span, "list", start, fmt.Errorf("objstore: invalid list response: %v", err))
+		}
+		c.finish(span, "list", start, nil) // nolint: errcheck
+
+		for _, o := range result.Contents {
+			err := fn(ObjectInfo{Key: o.Key, Size: o.Size, ETag: strings.Trim(o.ETag, `"`), LastModified: o.LastModified})
+			if err != nil {
+				return err
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// PresignGet returns a URL to download the object without credentials,
+// valid for the passed duration or OBJSTORE_PRESIGN_EXPIRY if it is 0
+func (c *Client) PresignGet(key string, expires time.Duration) (string, error) {
+	return c.presign("GET", key, expires)
+}
+
+// PresignPut returns a URL to upload the object without credentials,
+// valid for the passed duration or OBJSTORE_PRESIGN_EXPIRY if it is 0
+func (c *Client) PresignPut(key string, expires time.Duration) (string, error) {
+	return c.presign("PUT", key, expires)
+}
+
+func (c *Client) presign(method, key string, expires time.Duration) (string, error) {
+	if expires <= 0 {
+		expires = c.presignExpiry
+	}
+	// maximum duration of signature version 4
+	if expires > 7*24*time.Hour {
+		return "", fmt.Errorf("objstore: presigned URLs expire after at most 7 days, got %v", expires)
+	}
+	u := c.objectURL(key, nil)
+	c.signer.presign(method, u, expires, time.Now())
+	return u.String(), nil
+}
+
+// HealthCheck checks that the bucket is accessible
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if err := Setup(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.HealthCheckTimeout)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, "HEAD", "", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// objectURL returns the URL of the object or the bucket if key is empty
+func (c *Client) objectURL(key string, query url.Values) *url.URL {
+	u := *c.endpoint
+	path := strings.TrimSuffix(u.Path, "/")
+	if c.pathStyle {
+		path += "/" + c.bucket
+	} else {
+		u.Host = c.bucket + "." + u.Host
+	}
+	u.Path = path + "/" + key
+	u.RawPath = ""
+	u.RawQuery = canonicalQuery(query)
+	return &u
+}
+
+func (c *Client) newRequest(ctx context.Context, method, key string, query url.Values, body io.ReadCloser) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.objectURL(key, query).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Body = body
+	}
+	return req.WithContext(ctx), nil
+}
+
+// do signs and sends the request, responses with status codes
+// other than 2xx are returned as error and closed
+func (c *Client) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	c.signer.sign(req, payloadHash, time.Now())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode == http.StatusNotFound && req.URL.Path != c.objectURL("", nil).Path {
+		return nil, ErrNotFound
+	}
+	e := &Error{StatusCode: resp.StatusCode}
+	if req.Method != "HEAD" {
+		xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(e) // nolint: errcheck
+	}
+	return nil, e
+}
+
+func (c *Client) startSpan(ctx context.Context, op, key string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Objstore: "+op)
+	ext.SpanKindRPCClient.Set(span)
+	span.LogFields(olog.String("bucket", c.bucket), olog.String("key", key))
+	return span, ctx
+}
+
+// finish records the metrics of the operation and returns err
+func (c *Client) finish(span opentracing.Span, op string, start time.Time, err error) error {
+	result := resultSuccess
+	switch {
+	case err == ErrNotFound:
+		result = resultNotFound
+	case err != nil:
+		result = resultError
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+	}
+	paceObjstoreRequestsTotal.With(prometheus.Labels{"bucket": c.bucket, "op": op, "result": result}).Inc()
+	paceObjstoreRequestDurationSeconds.With(prometheus.Labels{"bucket": c.bucket, "op": op}).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (c *Client) countBytes(op string, n int64) {
+	paceObjstoreBytesTotal.With(prometheus.Labels{"bucket": c.bucket, "op": op}).Add(float64(n))
+}
+
+func objectInfo(key string, resp *http.Response) ObjectInfo {
+	info := ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = lm
+	}
+	return info
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// countingReadCloser counts the downloaded bytes
+type countingReadCloser struct {
+	io.ReadCloser
+	client *Client
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.client.countBytes("get", int64(n))
+	return n, err
+}
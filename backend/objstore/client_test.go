@@ -0,0 +1,182 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// storage is an in-memory S3 compatible storage of the bucket "test"
+// using path style, listings return pages of two objects
+type storage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/test") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/test"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == "HEAD" && key == "":
+	case r.Method == "PUT":
+		data, _ := ioutil.ReadAll(r.Body)
+		s.objects[key] = data
+	case r.Method == "DELETE":
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && key == "":
+		s.list(w, r)
+	case r.Method == "GET" || r.Method == "HEAD":
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")) // nolint: errcheck
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Write(data) // nolint: errcheck
+	}
+}
+
+func (s *storage) list(w http.ResponseWriter, r *http.Request) {
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) && key > r.URL.Query().Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result listResult
+	if len(keys) > 2 {
+		keys = keys[:2]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[1]
+	}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, struct {
+			Key          string    `xml:"Key"`
+			Size         int64     `xml:"Size"`
+			ETag         string    `xml:"ETag"`
+			LastModified time.Time `xml:"LastModified"`
+		}{Key: key, Size: int64(len(s.objects[key]))})
+	}
+	xml.NewEncoder(w).Encode(result) // nolint: errcheck
+}
+
+func newTestClient(t *testing.T) (*Client, *httptest.Server) {
+	srv := httptest.NewServer(&storage{objects: make(map[string][]byte)})
+	c, err := CustomClient(Options{
+		Endpoint:        srv.URL,
+		Region:          "eu-central-1",
+		Bucket:          "test",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	assert.NoError(t, err)
+	return c, srv
+}
+
+func TestClient(t *testing.T) {
+	c, srv := newTestClient(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	assert.NoError(t, c.HealthCheck(ctx))
+
+	for _, key := range []string{"docs/a.txt", "docs/b c.txt", "docs/c.txt", "img/d.png"} {
+		assert.NoError(t, c.Put(ctx, key, strings.NewReader("content of "+key), int64(len("content of "+key)), "text/plain"))
+	}
+	assert.NoError(t, c.Put(ctx, "empty", bytes.NewReader(nil), 0, ""))
+
+	r, info, err := c.Get(ctx, "docs/b c.txt")
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+	assert.Equal(t, "content of docs/b c.txt", string(data))
+	assert.Equal(t, "etag", info.ETag)
+
+	var keys []string
+	err = c.List(ctx, "docs/", func(info ObjectInfo) error {
+		keys = append(keys, info.Key)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docs/a.txt", "docs/b c.txt", "docs/c.txt"}, keys)
+
+	stop := errors.New("stop")
+	err = c.List(ctx, "", func(info ObjectInfo) error { return stop })
+	assert.Equal(t, stop, err)
+
+	assert.NoError(t, c.Delete(ctx, "docs/a.txt"))
+	_, _, err = c.Get(ctx, "docs/a.txt")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = c.Stat(ctx, "docs/a.txt")
+	assert.Equal(t, ErrNotFound, err)
+
+	info, err = c.Stat(ctx, "docs/c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len("content of docs/c.txt")), info.Size)
+}
+
+func TestClientErrors(t *testing.T) {
+	_, err := CustomClient(Options{Endpoint: "http://localhost"})
+	assert.Error(t, err)
+	_, err = CustomClient(Options{Endpoint: "localhost", Bucket: "test"})
+	assert.Error(t, err)
+
+	c, srv := newTestClient(t)
+	defer srv.Close()
+	c.signer.accessKeyID = "unknown"
+
+	err = c.Put(context.Background(), "a", strings.NewReader("a"), 1, "")
+	if assert.IsType(t, &Error{}, err) {
+		assert.Equal(t, http.StatusForbidden, err.(*Error).StatusCode)
+	}
+}
+
+func TestPresignURL(t *testing.T) {
+	c, err := CustomClient(Options{
+		Endpoint:      "https://s3.eu-central-1.amazonaws.com",
+		Region:        "eu-central-1",
+		Bucket:        "test",
+		AccessKeyID:   "key",
+		PresignExpiry: time.Hour,
+	})
+	assert.NoError(t, err)
+
+	u, err := c.PresignGet("docs/a b.txt", 0)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u, "https://test.s3.eu-central-1.amazonaws.com/docs/a%20b.txt?X-Amz-Algorithm=AWS4-HMAC-SHA256&"), u)
+	assert.Contains(t, u, "X-Amz-Expires=3600")
+	assert.Contains(t, u, "X-Amz-Signature=")
+
+	_, err = c.PresignPut("a", 8*24*time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package objstore stores objects in S3 compatible object storages (AWS
+// S3, MinIO, Ceph, ...). Requests are signed with AWS signature version 4
+// and instrumented with metrics, logging and tracing.
+package objstore
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	Endpoint        string `env:"OBJSTORE_ENDPOINT" envDefault:"https://s3.amazonaws.com"`
+	Region          string `env:"OBJSTORE_REGION" envDefault:"us-east-1"`
+	Bucket          string `env:"OBJSTORE_BUCKET"`
+	AccessKeyID     string `env:"OBJSTORE_ACCESS_KEY_ID"`
+	SecretAccessKey string `env:"OBJSTORE_SECRET_ACCESS_KEY"`
+	// Address the bucket with the path instead of the host name,
+	// required by most S3 compatible storages (e.g. MinIO)
+	PathStyle bool `env:"OBJSTORE_PATH_STYLE" envDefault:"false"`
+	// Default duration presigned URLs are valid
+	PresignExpiry time.Duration `env:"OBJSTORE_PRESIGN_EXPIRY" envDefault:"15m"`
+	// Maximum duration of the health check
+	HealthCheckTimeout time.Duration `env:"OBJSTORE_HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+}
+
+var (
+	cfg      config
+	setupErr error
+	once     sync.Once
+)
+
+// Setup parses the environment, it is called by NewClient. Call it
+// to handle malformed environments.
+func Setup() error {
+	once.Do(func() {
+		setupErr = env.Parse(&cfg)
+	})
+	return setupErr
+}
+
+// results of operations, used as metric label
+const (
+	resultSuccess  = "success"
+	resultNotFound = "not_found"
+	resultError    = "error"
+)
+
+var (
+	paceObjstoreRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("objstore_requests_total"),
+			Help: "Collects stats about the number of requests partitioned by bucket, operation and result",
+		},
+		[]string{"bucket", "op", "result"},
+	)
+	paceObjstoreRequestDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("objstore_request_duration_seconds"),
+			Help:    "Collect performance metrics for each operation until the response headers are received",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"bucket", "op"},
+	)
+	paceObjstoreBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("objstore_bytes_total"),
+			Help: "Collects stats about the number of uploaded (put) and downloaded (get) bytes",
+		},
+		[]string{"bucket", "op"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceObjstoreRequestsTotal)
+	prometheus.MustRegister(paceObjstoreRequestDurationSeconds)
+	prometheus.MustRegister(paceObjstoreBytesTotal)
+	configcheck.Register("objstore", Setup)
+}
+
+// ErrNotFound is returned if the object doesn't exist
+var ErrNotFound = errors.New("objstore: object not found")
+
+// Error is returned if the storage responds with an error
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("objstore: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("objstore: %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+}
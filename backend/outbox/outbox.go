@@ -0,0 +1,313 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package outbox implements the transactional outbox pattern on top of
+// postgres. Events are written to the outbox table in the transaction of
+// the business data, a relay publishes them afterwards using the queue
+// abstraction. Events are published if and only if the transaction was
+// committed, at least once. They are published in the order of their ids,
+// which is not strictly the order of the commits and concurrent relays can
+// publish events out of order, so consumers must not rely on the order.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/backend/postgres"
+	"github.com/pace/bricks/backend/queue"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Maximum number of events published per transaction of the relay
+	BatchSize int `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	// Interval in which the relay polls for new events
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	// Duration published events are kept before they are deleted
+	Retention time.Duration `env:"OUTBOX_RETENTION" envDefault:"24h"`
+	// Number of attempts to publish an event before it is marked as
+	// failed and skipped
+	MaxAttempts int `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"10"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses and validates the OUTBOX_* environment of the relay, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+		if errSetup == nil && cfg.MaxAttempts <= 0 {
+			errSetup = fmt.Errorf("OUTBOX_MAX_ATTEMPTS needs to be positive, got %d", cfg.MaxAttempts)
+		}
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse outbox environment: %v", err)
+	}
+}
+
+var (
+	paceOutboxPublishedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("outbox_published_total"),
+			Help: "Collects stats about the number of relayed events partitioned by topic and result",
+		},
+		[]string{"topic", "result"},
+	)
+	paceOutboxLagSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("outbox_lag_seconds"),
+			Help:    "Collect the delay between writing and publishing of the events",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		},
+	)
+	paceOutboxPending = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: metric.Name("outbox_pending"),
+			Help: "Number of events in the outbox that were not published yet",
+		},
+	)
+	paceOutboxFailed = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: metric.Name("outbox_failed"),
+			Help: "Number of events in the outbox that failed all attempts to publish them",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceOutboxPublishedTotal)
+	prometheus.MustRegister(paceOutboxLagSeconds)
+	prometheus.MustRegister(paceOutboxPending)
+	prometheus.MustRegister(paceOutboxFailed)
+	configcheck.Register("outbox", Setup)
+}
+
+// Table creates the outbox table, services add it to their migrations
+const Table = `CREATE TABLE IF NOT EXISTS bricks_outbox (
+	id bigserial PRIMARY KEY,
+	topic text NOT NULL,
+	key text,
+	headers jsonb,
+	body bytea NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	published_at timestamptz,
+	attempts int NOT NULL DEFAULT 0,
+	last_error text
+);
+ALTER TABLE bricks_outbox ADD COLUMN IF NOT EXISTS failed_at timestamptz;
+CREATE INDEX IF NOT EXISTS bricks_outbox_pending ON bricks_outbox (id) WHERE published_at IS NULL`
+
+// Event is a message in the outbox
+type Event struct {
+	tableName struct{} `sql:"bricks_outbox"` // nolint: structcheck,unused
+
+	ID          int64
+	Topic       string `sql:",notnull"`
+	Key         string
+	Headers     map[string]string
+	Body        []byte    `sql:",notnull"`
+	CreatedAt   time.Time `sql:"default:now()"`
+	PublishedAt time.Time
+	Attempts    int `sql:",notnull"`
+	LastError   string
+	// FailedAt is set once the event failed OUTBOX_MAX_ATTEMPTS times
+	FailedAt time.Time
+}
+
+// Add writes the messages to the outbox using the transaction (or
+// database) db, they are published by the relay after the commit
+func Add(db orm.DB, msgs ...queue.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	events := make([]*Event, len(msgs))
+	for i, msg := range msgs {
+		events[i] = &Event{Topic: msg.Topic, Key: msg.Key, Headers: msg.Headers, Body: msg.Body}
+	}
+	_, err := db.Model(&events).Insert()
+	return err
+}
+
+// Relay publishes the events of the outbox. Multiple replicas can run a
+// relay, each event is locked by one relay until it was published. If the
+// publishing of an event fails, the remaining events of the batch are
+// published with the next poll. Events that failed OUTBOX_MAX_ATTEMPTS
+// times are marked as failed and skipped, so they don't block the outbox,
+// see RetryFailed.
+type Relay struct {
+	db        *pg.DB
+	publisher queue.Publisher
+}
+
+// NewRelay returns a relay publishing the events of db using the publisher
+func NewRelay(db *pg.DB, publisher queue.Publisher) *Relay {
+	mustSetup()
+	return &Relay{db: db, publisher: publisher}
+}
+
+// Run relays the events until ctx is done, the published
+// events are deleted after OUTBOX_RETENTION
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		n, err := r.Relay(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Failed to relay outbox events")
+		}
+		if time.Since(lastCleanup) > cfg.Retention/10 {
+			lastCleanup = time.Now()
+			if err := r.cleanup(ctx); err != nil && ctx.Err() == nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("Failed to delete published outbox events")
+			}
+		}
+		if err := r.updatePending(ctx); err != nil && ctx.Err() == nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("Failed to count pending outbox events")
+		}
+
+		// continue immediately with the next batch
+		if n == cfg.BatchSize {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes the next batch of pending events and returns the number
+// of published events. The published events are marked in the transaction
+// that locks them; if the commit fails, they are published again.
+func (r *Relay) Relay(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Outbox: relay")
+	defer span.Finish()
+
+	published := 0
+	err := postgres.Transaction(ctx, r.db, func(tx *pg.Tx) error {
+		published = 0 // the transaction may be retried
+		var events []*Event
+		_, err := tx.Query(&events, `SELECT * FROM bricks_outbox
+			WHERE published_at IS NULL AND failed_at IS NULL
+			ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED`, cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		var ids []int64
+		for _, e := range events {
+			err := r.publisher.Publish(ctx, queue.Message{Topic: e.Topic, Key: e.Key, Headers: e.Headers, Body: e.Body})
+			if err != nil {
+				paceOutboxPublishedTotal.With(prometheus.Labels{"topic": e.Topic, "result": "error"}).Inc()
+				log.Ctx(ctx).Warn().Err(err).Int64("id", e.ID).Str("topic", e.Topic).Msg("Failed to publish outbox event")
+				if e.Attempts+1 >= cfg.MaxAttempts {
+					// skip the event, so that it doesn't block the outbox
+					log.Ctx(ctx).Error().Err(err).Int64("id", e.ID).Str("topic", e.Topic).Int("attempts", e.Attempts+1).
+						Msg("Outbox event failed all attempts, it is skipped")
+					_, uerr := tx.Exec(`UPDATE bricks_outbox SET attempts = attempts + 1, last_error = ?, failed_at = now()
+						WHERE id = ?`, err.Error(), e.ID)
+					if uerr != nil {
+						return uerr
+					}
+					continue
+				}
+				_, uerr := tx.Exec(`UPDATE bricks_outbox SET attempts = attempts + 1, last_error = ? WHERE id = ?`, err.Error(), e.ID)
+				if uerr != nil {
+					return uerr
+				}
+				break
+			}
+			paceOutboxPublishedTotal.With(prometheus.Labels{"topic": e.Topic, "result": "success"}).Inc()
+			paceOutboxLagSeconds.Observe(time.Since(e.CreatedAt).Seconds())
+			ids = append(ids, e.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		_, err = tx.Exec(`UPDATE bricks_outbox SET published_at = now() WHERE id IN (?)`, pg.In(ids))
+		if err != nil {
+			return err
+		}
+		published = len(ids)
+		return nil
+	})
+	span.LogFields(olog.Int("published", published))
+	if err != nil {
+		span.LogFields(olog.Error(err))
+		return 0, err
+	}
+	return published, nil
+}
+
+// Pending returns the number of events that were not published yet,
+// failed events are not included
+func (r *Relay) Pending(ctx context.Context) (int, error) {
+	return postgres.WithContext(ctx, r.db).Model((*Event)(nil)).
+		Where("published_at IS NULL AND failed_at IS NULL").Count()
+}
+
+// Failed returns the number of events that failed all attempts
+func (r *Relay) Failed(ctx context.Context) (int, error) {
+	return postgres.WithContext(ctx, r.db).Model((*Event)(nil)).
+		Where("published_at IS NULL AND failed_at IS NOT NULL").Count()
+}
+
+// RetryFailed resets the attempts of the failed events, so that they are
+// published again, e.g. after the cause of the failures was fixed. It
+// returns the number of reset events.
+func (r *Relay) RetryFailed(ctx context.Context) (int, error) {
+	res, err := postgres.WithContext(ctx, r.db).Exec(`UPDATE bricks_outbox
+		SET attempts = 0, failed_at = NULL WHERE published_at IS NULL AND failed_at IS NOT NULL`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+func (r *Relay) updatePending(ctx context.Context) error {
+	n, err := r.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	paceOutboxPending.Set(float64(n))
+	n, err = r.Failed(ctx)
+	if err != nil {
+		return err
+	}
+	paceOutboxFailed.Set(float64(n))
+	return nil
+}
+
+func (r *Relay) cleanup(ctx context.Context) error {
+	_, err := postgres.WithContext(ctx, r.db).Exec(`DELETE FROM bricks_outbox
+		WHERE published_at < now() - ? * interval '1 millisecond'`, int64(cfg.Retention/time.Millisecond))
+	return err
+}
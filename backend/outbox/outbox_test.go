@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/backend/postgres"
+	"github.com/pace/bricks/backend/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+type publisher struct {
+	mu   sync.Mutex
+	msgs []queue.Message
+	fail map[string]bool
+}
+
+func (p *publisher) Publish(ctx context.Context, msg queue.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail[string(msg.Body)] {
+		return errors.New("unavailable")
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *publisher) bodies() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var bodies []string
+	for _, msg := range p.msgs {
+		bodies = append(bodies, string(msg.Body))
+	}
+	return bodies
+}
+
+func TestRelay(t *testing.T) {
+	db := postgres.ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+	if _, err := db.Exec(Table); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE bricks_outbox") // nolint: errcheck
+
+	ctx := context.Background()
+	p := &publisher{fail: map[string]bool{"c": true}}
+	relay := NewRelay(db, p)
+
+	// events of rolled back transactions are never published
+	err := postgres.Transaction(ctx, db, func(tx *pg.Tx) error {
+		assert.NoError(t, Add(tx, queue.Message{Topic: "events", Body: []byte("x")}))
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	err = postgres.Transaction(ctx, db, func(tx *pg.Tx) error {
+		return Add(tx,
+			queue.Message{Topic: "events", Key: "1", Body: []byte("a"), Headers: map[string]string{"version": "1"}},
+			queue.Message{Topic: "events", Body: []byte("b")},
+			queue.Message{Topic: "events", Body: []byte("c")},
+			queue.Message{Topic: "events", Body: []byte("d")},
+		)
+	})
+	assert.NoError(t, err)
+
+	// publishing stops at the failed event to keep the order
+	n, err := relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b"}, p.bodies())
+	assert.Equal(t, "1", p.msgs[0].Key)
+	assert.Equal(t, map[string]string{"version": "1"}, p.msgs[0].Headers)
+
+	pending, err := relay.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pending)
+
+	var failed Event
+	assert.NoError(t, db.Model(&failed).Where("body = ?", []byte("c")).Select())
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "unavailable", failed.LastError)
+
+	p.mu.Lock()
+	p.fail = nil
+	p.mu.Unlock()
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, p.bodies())
+
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// events that failed all attempts don't block the outbox
+	defer func(attempts int) { cfg.MaxAttempts = attempts }(cfg.MaxAttempts)
+	cfg.MaxAttempts = 2
+	p.mu.Lock()
+	p.fail = map[string]bool{"e": true}
+	p.mu.Unlock()
+	err = Add(db, queue.Message{Topic: "events", Body: []byte("e")}, queue.Message{Topic: "events", Body: []byte("f")})
+	assert.NoError(t, err)
+
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"a", "b", "c", "d", "f"}, p.bodies())
+
+	failedEvents, err := relay.Failed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failedEvents)
+	pending, err = relay.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pending)
+
+	p.mu.Lock()
+	p.fail = nil
+	p.mu.Unlock()
+	retried, err := relay.RetryFailed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"a", "b", "c", "d", "f", "e"}, p.bodies())
+}
@@ -0,0 +1,90 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"github.com/rs/zerolog"
+)
+
+// hooksAllocBudget is the maximum number of allocations of the query
+// hooks (logging, tracing and metrics), raise it only deliberately
+const hooksAllocBudget = 75
+
+// benchResult is the result of a query returning a single row
+type benchResult struct{}
+
+func (benchResult) Model() orm.Model  { return nil }
+func (benchResult) RowsAffected() int { return 1 }
+func (benchResult) RowsReturned() int { return 1 }
+
+// benchHooks returns a function executing all query hooks, the pool
+// is not connected to a database
+func benchHooks() func() {
+	opts := &pg.Options{Addr: "localhost:5432", Database: "bench"}
+	db := pg.Connect(opts)
+	event := &queryEvent{
+		QueryProcessedEvent: &pg.QueryProcessedEvent{
+			StartTime: time.Now(),
+			DB:        db,
+			Query:     "SELECT * FROM articles WHERE id = ?",
+			Params:    []interface{}{1},
+			Result:    benchResult{},
+		},
+		elapsed: time.Millisecond,
+	}
+
+	return func() {
+		queryLogger(event)
+		openTracingAdapter(event)
+		metricsAdapter(event, opts)
+	}
+}
+
+// withoutLogging disables the log output, it would dominate the results
+func withoutLogging() func() {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	return func() { zerolog.SetGlobalLevel(level) }
+}
+
+func BenchmarkQueryHooks(b *testing.B) {
+	defer withoutLogging()()
+	hooks := benchHooks()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hooks()
+	}
+}
+
+func BenchmarkQueryHooksParallel(b *testing.B) {
+	defer withoutLogging()()
+	hooks := benchHooks()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			hooks()
+		}
+	})
+}
+
+func TestQueryHooksAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation budgets are not checked in short mode")
+	}
+	defer withoutLogging()()
+	hooks := benchHooks()
+
+	if allocs := testing.AllocsPerRun(100, hooks); allocs > hooksAllocBudget {
+		t.Errorf("Expected at most %d allocations per query, got: %v", hooksAllocBudget, allocs)
+	}
+}
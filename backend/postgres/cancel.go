@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// cancelTimeout is the timeout of the cancel requests
+const cancelTimeout = 5 * time.Second
+
+// canceler cancels the queries of a go-pg pool on the server once the
+// read of the result times out, e.g. because the deadline of the context
+// expired (see WithContext). go-pg closes the connection after the
+// timeout, but the server keeps executing the query until it sends the
+// result. The cancel request of the postgres protocol needs the secret
+// key of the connection which go-pg doesn't expose, instead the query is
+// canceled with pg_cancel_backend.
+type canceler struct {
+	mu    sync.Mutex
+	db    *pg.DB                 // pool sending the cancel requests
+	conns map[string]*cancelConn // by local address
+}
+
+func newCanceler() *canceler {
+	return &canceler{conns: make(map[string]*cancelConn)}
+}
+
+// setPool sets the pool sending the cancel requests
+func (c *canceler) setPool(db *pg.DB) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.db = db
+}
+
+// dialer returns a dialer whose connections cancel the running query
+// once a read times out
+func (c *canceler) dialer(opts *pg.Options) func(network, addr string) (net.Conn, error) {
+	next := opts.Dialer
+	if next == nil {
+		dialer := &net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 5 * time.Minute}
+		next = dialer.Dial
+	}
+
+	return func(network, addr string) (net.Conn, error) {
+		conn, err := next(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cn := &cancelConn{Conn: conn, c: c, cancel: c.cancel}
+		c.mu.Lock()
+		c.conns[conn.LocalAddr().String()] = cn
+		c.mu.Unlock()
+		return cn, nil
+	}
+}
+
+// onConnect looks up the backend of new connections. The connection is
+// identified by the client address the server sees, so connections
+// through proxies or NAT aren't canceled.
+func (c *canceler) onConnect(next func(*pg.DB) error) func(*pg.DB) error {
+	return func(db *pg.DB) error {
+		var (
+			pid  int32
+			host string
+			port int
+		)
+		_, err := db.QueryOne(pg.Scan(&pid, &host, &port),
+			"SELECT pg_backend_pid(), coalesce(host(inet_client_addr()), ''), coalesce(inet_client_port(), 0)")
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		if cn, ok := c.conns[net.JoinHostPort(host, strconv.Itoa(port))]; ok {
+			atomic.StoreInt32(&cn.pid, pid)
+		}
+		c.mu.Unlock()
+
+		if next != nil {
+			return next(db)
+		}
+		return nil
+	}
+}
+
+// cancel cancels the running query of the backend
+func (c *canceler) cancel(pid int32) {
+	c.mu.Lock()
+	db := c.db
+	c.mu.Unlock()
+	if db == nil {
+		return
+	}
+
+	_, err := withTimeout(withoutRetries(db), cancelTimeout).Exec("SELECT pg_cancel_backend(?)", pid)
+	if err != nil {
+		log.Logger().Warn().Err(err).Int32("pid", pid).Msg("Failed to cancel postgres query after read timeout")
+		return
+	}
+	log.Logger().Debug().Int32("pid", pid).Msg("Canceled postgres query after read timeout")
+}
+
+// cancelConn cancels the running query on the server once a read times
+// out. go-pg removes connections after a timeout, so the query is only
+// canceled once.
+type cancelConn struct {
+	net.Conn
+	c        *canceler
+	cancel   func(pid int32)
+	pid      int32 // backend, 0 if unknown; accessed atomically
+	canceled int32 // accessed atomically
+}
+
+func (cn *cancelConn) Read(b []byte) (int, error) {
+	n, err := cn.Conn.Read(b)
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		if pid := atomic.LoadInt32(&cn.pid); pid != 0 && atomic.CompareAndSwapInt32(&cn.canceled, 0, 1) {
+			go cn.cancel(pid)
+		}
+	}
+	return n, err
+}
+
+func (cn *cancelConn) Close() error {
+	cn.c.mu.Lock()
+	if cn.c.conns[cn.LocalAddr().String()] == cn {
+		delete(cn.c.conns, cn.LocalAddr().String())
+	}
+	cn.c.mu.Unlock()
+	return cn.Conn.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+func TestCancelConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close() // nolint: errcheck
+
+	c := newCanceler()
+	dial := c.dialer(&pg.Options{Dialer: func(network, addr string) (net.Conn, error) {
+		return client, nil
+	}})
+	conn, err := dial("tcp", "db:5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.conns) != 1 {
+		t.Fatalf("Expected connection to be tracked, got: %d", len(c.conns))
+	}
+
+	canceled := make(chan int32, 2)
+	cn := conn.(*cancelConn)
+	cn.cancel = func(pid int32) { canceled <- pid }
+
+	// connections without known backend aren't canceled
+	buf := make([]byte, 1)
+	conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond)) // nolint: errcheck
+	if _, err := conn.Read(buf); err == nil {
+		t.Fatal("Expected read to time out")
+	}
+
+	// the query is canceled once after the read timed out
+	atomic.StoreInt32(&cn.pid, 42)
+	for i := 0; i < 2; i++ {
+		conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond)) // nolint: errcheck
+		if _, err := conn.Read(buf); err == nil {
+			t.Fatal("Expected read to time out")
+		}
+	}
+	select {
+	case pid := <-canceled:
+		if pid != 42 {
+			t.Errorf("Expected backend 42 to be canceled, got: %d", pid)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected query to be canceled")
+	}
+	select {
+	case <-canceled:
+		t.Error("Expected query to be canceled once")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	conn.Close() // nolint: errcheck
+	if len(c.conns) != 0 {
+		t.Errorf("Expected closed connection to be removed, got: %d", len(c.conns))
+	}
+}
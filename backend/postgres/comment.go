@@ -0,0 +1,100 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/pkg/reqcontext"
+	jaeger "github.com/uber/jaeger-client-go"
+)
+
+// Comment appends a sqlcommenter comment to the query that contains the
+// trace (traceparent), request id, route and query name (action) of ctx,
+// e.g. "SELECT 1 /*request_id='...',traceparent='00-...-...-01'*/". This way
+// entries of pg_stat_activity and the server logs can be correlated with
+// the traces of the application. The query is returned unchanged if ctx
+// contains none of the values.
+func Comment(ctx context.Context, query string) string {
+	return withComment(query, queryComment(ctx, false))
+}
+
+// commentStatement appends the sqlcommenter comment with the values of ctx
+// that are the same for all requests of a route (action and route) to the
+// query, so that the prepared statement can be reused by these requests
+func commentStatement(ctx context.Context, query string) string {
+	return withComment(query, queryComment(ctx, true))
+}
+
+func withComment(query, comment string) string {
+	if comment == "" {
+		return query
+	}
+	return query + " " + comment
+}
+
+// queryComment returns the sqlcommenter comment for ctx, the keys are
+// sorted and the values url escaped (this also escapes quotes and the
+// end of the comment). The static comment omits the request id and trace.
+func queryComment(ctx context.Context, static bool) string {
+	if ctx == nil {
+		return ""
+	}
+
+	tags := make(map[string]string)
+	if name := queryNameFromContext(ctx); name != "" {
+		tags["action"] = name
+	}
+	if route, ok := reqcontext.Route(ctx); ok {
+		tags["route"] = route
+	}
+	if !static {
+		if id := log.RequestIDFromContext(ctx); id != "" {
+			tags["request_id"] = id
+		}
+		if tp := traceparent(ctx); tp != "" {
+			tags["traceparent"] = tp
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s='%s'", key, url.PathEscape(tags[key]))
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// traceparent returns the W3C trace context of the span in ctx
+// or an empty string if there is no jaeger span
+func traceparent(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	sc, ok := span.Context().(jaeger.SpanContext)
+	if !ok || !sc.IsValid() {
+		return ""
+	}
+
+	flags := 0
+	if sc.IsSampled() {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%016x%016x-%016x-%02x", sc.TraceID().High, sc.TraceID().Low, uint64(sc.SpanID()), flags)
+}
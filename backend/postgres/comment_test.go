@@ -0,0 +1,53 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/pkg/reqcontext"
+	jaeger "github.com/uber/jaeger-client-go"
+)
+
+func TestComment(t *testing.T) {
+	ctx := context.Background()
+	if q := Comment(ctx, "SELECT 1"); q != "SELECT 1" {
+		t.Errorf("expected query without comment, got %q", q)
+	}
+
+	ctx = reqcontext.WithRoute(WithQueryName(ctx, "it's */ here"), "/api/articles/{id}")
+	expected := `SELECT 1 /*action='it%27s%20%2A%2F%20here',route='%2Fapi%2Farticles%2F%7Bid%7D'*/`
+	if q := Comment(ctx, "SELECT 1"); q != expected {
+		t.Errorf("expected %q, got %q", expected, q)
+	}
+
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close() // nolint: errcheck
+	span := tracer.StartSpan("test")
+	defer span.Finish()
+
+	q := Comment(opentracing.ContextWithSpan(context.Background(), span), "SELECT 1")
+	if !regexp.MustCompile(`^SELECT 1 /\*traceparent='00-[0-9a-f]{32}-[0-9a-f]{16}-01'\*/$`).MatchString(q) {
+		t.Errorf("expected traceparent comment, got %q", q)
+	}
+}
+
+func TestCommentStatement(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close() // nolint: errcheck
+	span := tracer.StartSpan("test")
+	defer span.Finish()
+
+	ctx := reqcontext.WithRoute(opentracing.ContextWithSpan(context.Background(), span), "GetArticles")
+	expected := `SELECT $1 /*route='GetArticles'*/`
+	if q := commentStatement(ctx, "SELECT $1"); q != expected {
+		t.Errorf("expected statement comment without trace %q, got %q", expected, q)
+	}
+	if q := commentStatement(context.Background(), "SELECT $1"); q != "SELECT $1" {
+		t.Errorf("expected statement without comment, got %q", q)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/health"
+)
+
+// Requirements of a service on the postgres server
+type Requirements struct {
+	// MinVersion is the minimum server version, e.g. "11" or "9.6"
+	MinVersion string
+	// Extensions that need to be installed, e.g. "uuid-ossp" or "postgis"
+	Extensions []string
+}
+
+// CheckRequirements returns an error that describes all requirements
+// the server of db doesn't fulfill
+func CheckRequirements(ctx context.Context, db *pg.DB, req Requirements) error {
+	cdb := WithContext(ctx, db)
+	var problems []string
+
+	if req.MinVersion != "" {
+		min, err := versionNum(req.MinVersion)
+		if err != nil {
+			return err
+		}
+		var version int
+		_, err = cdb.QueryOne(pg.Scan(&version), "SHOW server_version_num")
+		if err != nil {
+			return err
+		}
+		if version < min {
+			problems = append(problems, fmt.Sprintf("server version %s is older than %s", formatVersionNum(version), req.MinVersion))
+		}
+	}
+
+	if len(req.Extensions) > 0 {
+		var installed []string
+		_, err := cdb.Query(&installed, "SELECT extname FROM pg_extension")
+		if err != nil {
+			return err
+		}
+		for _, ext := range req.Extensions {
+			if !contains(installed, ext) {
+				problems = append(problems, fmt.Sprintf("extension %q is not installed", ext))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("postgres %s: %s", databaseLabel(db.Options()), strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// RegisterRequirements registers a readiness check (see health.RegisterCheck)
+// that fails until the server of db fulfills the requirements. Once they
+// are fulfilled, the check isn't executed anymore.
+func RegisterRequirements(db *pg.DB, req Requirements) {
+	var fulfilled int32
+	health.RegisterCheck("postgres "+databaseLabel(db.Options()), func(ctx context.Context) error {
+		if atomic.LoadInt32(&fulfilled) == 1 {
+			return nil
+		}
+		err := CheckRequirements(ctx, db, req)
+		if err == nil {
+			atomic.StoreInt32(&fulfilled, 1)
+		}
+		return err
+	})
+}
+
+// requirements returns the requirements of the passed config
+func requirements(c *config) (Requirements, bool) {
+	req := Requirements{MinVersion: c.MinVersion, Extensions: c.RequiredExtensions}
+	return req, req.MinVersion != "" || len(req.Extensions) > 0
+}
+
+// versionNum converts a version (e.g. "9.6.1" or "12.3") to the
+// format of server_version_num (90601 or 120003)
+func versionNum(version string) (int, error) {
+	parts := strings.Split(version, ".")
+	nums := make([]int, 3)
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid postgres version %q", version)
+		}
+		nums[i] = n
+	}
+	if nums[0] >= 10 {
+		// since 10 the second part is the minor version
+		return nums[0]*10000 + nums[1], nil
+	}
+	return nums[0]*10000 + nums[1]*100 + nums[2], nil
+}
+
+// formatVersionNum converts a server_version_num to a version
+func formatVersionNum(num int) string {
+	major := num / 10000
+	if major >= 10 {
+		return fmt.Sprintf("%d.%d", major, num%10000)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, num/100%100, num%100)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVersionNum(t *testing.T) {
+	cases := map[string]int{
+		"9.6":   90600,
+		"9.6.1": 90601,
+		"11":    110000,
+		"12.3":  120003,
+		"14.10": 140010,
+	}
+	for version, expected := range cases {
+		num, err := versionNum(version)
+		if err != nil || num != expected {
+			t.Errorf("expected versionNum(%q) to be %d, got %d (%v)", version, expected, num, err)
+		}
+	}
+	if _, err := versionNum("latest"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+	if v := formatVersionNum(90601); v != "9.6.1" {
+		t.Errorf("expected 9.6.1, got %q", v)
+	}
+	if v := formatVersionNum(120003); v != "12.3" {
+		t.Errorf("expected 12.3, got %q", v)
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := CheckRequirements(ctx, db, Requirements{MinVersion: "9.0", Extensions: []string{"plpgsql"}}); err != nil {
+		t.Errorf("expected requirements to be fulfilled, got %v", err)
+	}
+
+	err := CheckRequirements(ctx, db, Requirements{MinVersion: "99", Extensions: []string{"not_installed"}})
+	if err == nil || !strings.Contains(err.Error(), "older than 99") || !strings.Contains(err.Error(), `"not_installed"`) {
+		t.Errorf("expected version and extension to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/maintenance/readonly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of copied batches, used as metric label
+const (
+	copyBatchSuccess = "success"
+	copyBatchFailed  = "failed"
+)
+
+var (
+	pacePostgresCopyRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_copy_rows_total"),
+			Help: "Collects stats about the number of rows copied into postgres tables",
+		},
+		[]string{"database", "table"},
+	)
+	pacePostgresCopyBatchTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_copy_batch_total"),
+			Help: "Collects stats about the number of batches copied into postgres tables partitioned by result",
+		},
+		[]string{"database", "table", "result"},
+	)
+	pacePostgresCopyBatchDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_copy_batch_duration_seconds"),
+			Help:    "Collect performance metrics for each batch copied into postgres tables",
+			Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database", "table"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresCopyRowsTotal,
+		pacePostgresCopyBatchTotal,
+		pacePostgresCopyBatchDurationSeconds,
+	)
+}
+
+// RowSource returns the next row to copy or io.EOF if there are no more rows
+type RowSource func() ([]interface{}, error)
+
+// RowsSource returns a RowSource for the passed rows
+func RowsSource(rows [][]interface{}) RowSource {
+	i := 0
+	return func() ([]interface{}, error) {
+		if i >= len(rows) {
+			return nil, io.EOF
+		}
+		i++
+		return rows[i-1], nil
+	}
+}
+
+// CopyOptions configure the bulk load of CopyFrom
+type CopyOptions struct {
+	// Table to copy the rows to, may be schema qualified (schema.table)
+	Table string
+	// Columns of the rows
+	Columns []string
+	// BatchSize is the number of rows copied per COPY statement,
+	// defaults to POSTGRES_COPY_BATCH_SIZE
+	BatchSize int
+	// ContinueOnError continues with the next batch if a batch failed,
+	// otherwise CopyFrom returns after the first failed batch
+	ContinueOnError bool
+}
+
+// BatchError is the error of a batch that failed to be copied,
+// none of the rows of the batch were copied
+type BatchError struct {
+	Batch  int // number of the batch starting with 0
+	Offset int // offset of the first row of the batch
+	Rows   int // number of rows of the batch
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch %d (rows %d-%d): %v", e.Batch, e.Offset, e.Offset+e.Rows-1, e.Err)
+}
+
+// CopyErrors of all failed batches
+type CopyErrors []*BatchError
+
+func (e CopyErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// CopyFrom bulk loads the rows of src into the table using COPY FROM STDIN,
+// which is much faster than inserting the rows one by one. The rows are
+// copied in batches, each batch is copied atomically. Returns the number of
+// copied rows. Errors of batches are returned as CopyErrors, errors of src
+// are returned as they are. Progress is logged and collected in
+// pace_postgres_copy_rows_total.
+func CopyFrom(ctx context.Context, db *pg.DB, opts CopyOptions, src RowSource) (int, error) {
+	if err := Setup(); err != nil {
+		return 0, err
+	}
+	if readonly.Enabled() {
+		return 0, ErrReadOnly
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: COPY %s", opts.Table))
+	defer span.Finish()
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = cfg.CopyBatchSize
+	}
+
+	labels := prometheus.Labels{"database": databaseLabel(db.Options()), "table": opts.Table}
+	query := copyQuery(opts.Table, opts.Columns)
+	logger := log.Ctx(ctx).With().Str("table", opts.Table).Logger()
+
+	var (
+		buf    bytes.Buffer
+		copied int
+		errs   CopyErrors
+	)
+	for batch, offset := 0, 0; ; batch++ {
+		buf.Reset()
+		rows, srcErr := encodeBatch(&buf, src, batchSize, len(opts.Columns))
+		if rows > 0 {
+			start := time.Now()
+			_, err := db.WithContext(ctx).CopyFrom(&buf, query)
+			dur := time.Since(start)
+			pacePostgresCopyBatchDurationSeconds.With(labels).Observe(dur.Seconds())
+
+			fields := []olog.Field{olog.Int("batch", batch), olog.Int("rows", rows)}
+			if err != nil {
+				berr := &BatchError{Batch: batch, Offset: offset, Rows: rows, Err: err}
+				errs = append(errs, berr)
+				pacePostgresCopyBatchTotal.With(batchLabels(labels, copyBatchFailed)).Inc()
+				span.LogFields(append(fields, olog.Error(err))...)
+				logger.Warn().Err(berr).Msg("PostgreSQL COPY batch failed")
+				if !opts.ContinueOnError {
+					return copied, errs
+				}
+			} else {
+				copied += rows
+				pacePostgresCopyBatchTotal.With(batchLabels(labels, copyBatchSuccess)).Inc()
+				pacePostgresCopyRowsTotal.With(labels).Add(float64(rows))
+				span.LogFields(fields...)
+				logger.Debug().Int("batch", batch).Int("rows", rows).Int("copied", copied).
+					Float64("duration", float64(dur)/float64(time.Millisecond)).
+					Msg("PostgreSQL COPY batch copied")
+			}
+			offset += rows
+		}
+
+		if srcErr == io.EOF {
+			break
+		}
+		if srcErr != nil {
+			span.LogFields(olog.Error(srcErr))
+			return copied, srcErr
+		}
+	}
+
+	span.LogFields(olog.Int("copied", copied))
+	logger.Info().Int("copied", copied).Int("failed_batches", len(errs)).Msg("PostgreSQL COPY finished")
+	if len(errs) > 0 {
+		return copied, errs
+	}
+	return copied, nil
+}
+
+func batchLabels(labels prometheus.Labels, result string) prometheus.Labels {
+	return prometheus.Labels{"database": labels["database"], "table": labels["table"], "result": result}
+}
+
+// copyQuery returns the COPY statement for the table and columns
+func copyQuery(table string, columns []string) string {
+	cols := make([]string, len(columns))
+	for i, column := range columns {
+		cols[i] = quoteIdent(column)
+	}
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", quoteTable(table), strings.Join(cols, ", "))
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// encodeBatch encodes up to size rows of src in the COPY text format,
+// returns the number of encoded rows and the error of src (io.EOF
+// if src has no more rows)
+func encodeBatch(buf *bytes.Buffer, src RowSource, size, columns int) (int, error) {
+	for rows := 0; rows < size; rows++ {
+		row, err := src()
+		if err != nil {
+			return rows, err
+		}
+		if len(row) != columns {
+			return rows, fmt.Errorf("row has %d values, expected %d", len(row), columns)
+		}
+
+		for i, v := range row {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			encodeCopyValue(buf, v)
+		}
+		buf.WriteByte('\n')
+	}
+	return size, nil
+}
+
+// copyEscaper escapes the special characters of the COPY text format
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// encodeCopyValue writes v in the COPY text format
+func encodeCopyValue(buf *bytes.Buffer, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString(`\N`)
+	case string:
+		copyEscaper.WriteString(buf, v) // nolint: errcheck
+	case []byte:
+		if v == nil {
+			buf.WriteString(`\N`)
+			return
+		}
+		buf.WriteString(`\\x`)
+		buf.WriteString(hex.EncodeToString(v))
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		buf.WriteString(v.Format(time.RFC3339Nano))
+	case *time.Time:
+		if v == nil {
+			buf.WriteString(`\N`)
+			return
+		}
+		buf.WriteString(v.Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		copyEscaper.WriteString(buf, v.String()) // nolint: errcheck
+	default:
+		copyEscaper.WriteString(buf, fmt.Sprint(v)) // nolint: errcheck
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestEncodeCopyValue(t *testing.T) {
+	ts := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, `\N`},
+		{"plain", "plain"},
+		{"a\tb\nc\\d", `a\tb\nc\\d`},
+		{[]byte{0xde, 0xad}, `\\xdead`},
+		{true, "true"},
+		{42, "42"},
+		{int64(-7), "-7"},
+		{1.5, "1.5"},
+		{ts, "2026-10-15T12:00:00Z"},
+		{(*time.Time)(nil), `\N`},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		encodeCopyValue(&buf, c.value)
+		if buf.String() != c.expected {
+			t.Errorf("Expected %#v to be encoded as %q, got: %q", c.value, c.expected, buf.String())
+		}
+	}
+}
+
+func TestCopyQuery(t *testing.T) {
+	q := copyQuery("public.users", []string{"id", `na"me`})
+	expected := `COPY "public"."users" ("id", "na""me") FROM STDIN`
+	if q != expected {
+		t.Errorf("Expected %q, got: %q", expected, q)
+	}
+}
+
+func TestEncodeBatch(t *testing.T) {
+	src := RowsSource([][]interface{}{{1, "a"}, {2, "b"}, {3, "c"}})
+
+	var buf bytes.Buffer
+	rows, err := encodeBatch(&buf, src, 2, 2)
+	if err != nil || rows != 2 || buf.String() != "1\ta\n2\tb\n" {
+		t.Errorf("Unexpected first batch %d %q: %v", rows, buf.String(), err)
+	}
+
+	buf.Reset()
+	rows, err = encodeBatch(&buf, src, 2, 2)
+	if err != io.EOF || rows != 1 || buf.String() != "3\tc\n" {
+		t.Errorf("Unexpected last batch %d %q: %v", rows, buf.String(), err)
+	}
+
+	_, err = encodeBatch(&buf, RowsSource([][]interface{}{{1}}), 2, 2)
+	if err == nil || err == io.EOF {
+		t.Errorf("Expected error for wrong number of values, got: %v", err)
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec("DROP TABLE IF EXISTS copy_test; CREATE TABLE copy_test (id int PRIMARY KEY, name text)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE copy_test") // nolint: errcheck
+
+	opts := CopyOptions{Table: "copy_test", Columns: []string{"id", "name"}, BatchSize: 2, ContinueOnError: true}
+	n, err := CopyFrom(context.Background(), db, opts, RowsSource([][]interface{}{
+		{1, "a"}, {2, nil}, {2, "duplicate"}, {3, "c"}, {4, "d"},
+	}))
+
+	errs, ok := err.(CopyErrors)
+	if !ok || len(errs) != 1 || errs[0].Offset != 2 {
+		t.Errorf("Expected second batch to fail, got: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 copied rows, got: %d", n)
+	}
+}
@@ -0,0 +1,427 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Credentials to authenticate against the database
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// CredentialProvider provides the (potentially rotating) credentials
+// of a database
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc is a function that implements the CredentialProvider
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls fn
+func (fn CredentialProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return fn(ctx)
+}
+
+// FileCredentialProvider reads the credentials from files, e.g. mounted
+// secrets or files rendered by the vault agent. The files are read on
+// every call, so that changes are picked up with the next refresh.
+type FileCredentialProvider struct {
+	UserFile     string
+	PasswordFile string
+}
+
+// Credentials reads the user and password files
+func (p *FileCredentialProvider) Credentials(ctx context.Context) (Credentials, error) {
+	user, err := ioutil.ReadFile(p.UserFile)
+	if err != nil {
+		return Credentials{}, err
+	}
+	password, err := ioutil.ReadFile(p.PasswordFile)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{
+		User:     strings.TrimSpace(string(user)),
+		Password: strings.TrimSpace(string(password)),
+	}, nil
+}
+
+// VaultCredentialProvider requests dynamic database credentials from the
+// HashiCorp Vault database secrets engine (e.g. "database/creds/my-role").
+// The lease of the credentials is renewed as long as it lasts at least
+// MinTTL, then new credentials are requested. Superseded leases are
+// revoked after RevokeDelay, it should cover the lifetime of the
+// connections (POSTGRES_MAX_CONN_AGE).
+type VaultCredentialProvider struct {
+	Addr        string // address of vault, e.g. https://vault:8200
+	Token       string // vault token used for the requests
+	Path        string // path of the credentials endpoint, e.g. database/creds/my-role
+	MinTTL      time.Duration
+	RevokeDelay time.Duration
+	Client      *http.Client
+
+	mu    sync.Mutex
+	lease *vaultLease
+}
+
+// vaultLease are the credentials of a vault lease
+type vaultLease struct {
+	id      string
+	creds   Credentials
+	expires time.Time
+	renew   bool
+}
+
+// vaultSecret is the relevant part of the vault response
+type vaultSecret struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+	Data          struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"data"`
+}
+
+// expires returns the expiry of the lease of the secret
+func (s *vaultSecret) expires() time.Time {
+	return time.Now().Add(time.Duration(s.LeaseDuration) * time.Second)
+}
+
+// Credentials returns the credentials of the current lease if it can be
+// renewed to last at least MinTTL, otherwise new credentials are requested
+func (p *VaultCredentialProvider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lease != nil {
+		if p.lease.renew {
+			var secret vaultSecret
+			err := p.request(ctx, "PUT", "sys/leases/renew", map[string]string{"lease_id": p.lease.id}, &secret)
+			if err == nil {
+				p.lease.expires, p.lease.renew = secret.expires(), secret.Renewable
+			} else {
+				log.Ctx(ctx).Debug().Err(err).Str("path", p.Path).Msg("Failed to renew vault lease")
+			}
+		}
+		if time.Until(p.lease.expires) >= p.MinTTL {
+			return p.lease.creds, nil
+		}
+	}
+
+	var secret vaultSecret
+	err := p.request(ctx, "GET", p.Path, nil, &secret)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if secret.Data.Username == "" {
+		return Credentials{}, fmt.Errorf("vault returned no username for %s", p.Path)
+	}
+
+	if p.lease != nil && p.lease.id != "" {
+		id := p.lease.id
+		time.AfterFunc(p.RevokeDelay, func() { p.revoke(id) })
+	}
+	p.lease = &vaultLease{
+		id:      secret.LeaseID,
+		creds:   Credentials{User: secret.Data.Username, Password: secret.Data.Password},
+		expires: secret.expires(),
+		renew:   secret.Renewable,
+	}
+	return p.lease.creds, nil
+}
+
+// Revoke revokes the lease of the current credentials, e.g. when
+// the connection pool is closed
+func (p *VaultCredentialProvider) Revoke(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lease == nil || p.lease.id == "" {
+		return nil
+	}
+	err := p.request(ctx, "PUT", "sys/leases/revoke", map[string]string{"lease_id": p.lease.id}, nil)
+	if err != nil {
+		return err
+	}
+	p.lease = nil
+	return nil
+}
+
+// revoke revokes a superseded lease
+func (p *VaultCredentialProvider) revoke(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := p.request(ctx, "PUT", "sys/leases/revoke", map[string]string{"lease_id": id}, nil)
+	if err != nil {
+		log.Logger().Warn().Err(err).Str("path", p.Path).Msg("Failed to revoke superseded vault lease")
+	}
+}
+
+// request sends a request with the optional JSON body to the vault API
+// and decodes the response into v (if not nil)
+func (p *VaultCredentialProvider) request(ctx context.Context, method, path string, body, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	url := strings.TrimSuffix(p.Addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("vault responded with %d for %s", resp.StatusCode, path)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+var pacePostgresCredentialsRefreshTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("postgres_credentials_refresh_total"),
+		Help: "Collects stats about the number of postgres credential refreshes",
+	},
+	[]string{"database", "result"},
+)
+
+func init() {
+	addMetrics(pacePostgresCredentialsRefreshTotal)
+}
+
+// credentialsMu guards the user and password of the options of connection
+// pools with refreshed credentials. go-pg reads them without synchronization
+// when new connections are started, therefore connections hold a read lock
+// from dialing until the startup message is written (see credentialsDialer).
+// Copies of the options (e.g. WithTimeout) are made holding a read lock.
+var credentialsMu sync.RWMutex
+
+// credentialsDialer returns a dialer that holds a read lock of the
+// credentials until the startup message of the connection is written
+func credentialsDialer(opts *pg.Options) func(network, addr string) (net.Conn, error) {
+	next := opts.Dialer
+	if next == nil {
+		dialer := &net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 5 * time.Minute}
+		next = dialer.Dial
+	}
+	// with TLS the SSL request is written before the startup message
+	writes := int32(1)
+	if opts.TLSConfig != nil {
+		writes = 2
+	}
+
+	return func(network, addr string) (net.Conn, error) {
+		credentialsMu.RLock()
+		conn, err := next(network, addr)
+		if err != nil {
+			credentialsMu.RUnlock()
+			return nil, err
+		}
+		return &credentialsConn{Conn: conn, writes: writes}, nil
+	}
+}
+
+// credentialsConn releases the read lock of the credentials
+// after the startup message was written or if it is closed before
+type credentialsConn struct {
+	net.Conn
+	writes int32
+	once   sync.Once
+}
+
+func (c *credentialsConn) Write(b []byte) (int, error) {
+	if atomic.AddInt32(&c.writes, -1) == 0 {
+		c.unlock()
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *credentialsConn) Close() error {
+	c.unlock()
+	return c.Conn.Close()
+}
+
+func (c *credentialsConn) unlock() {
+	c.once.Do(credentialsMu.RUnlock)
+}
+
+// withTimeout returns a copy of db that uses d as the read/write
+// timeout, the options are copied holding the credentials lock
+func withTimeout(db *pg.DB, d time.Duration) *pg.DB {
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+	return db.WithTimeout(d)
+}
+
+// CredentialsConnectionPool returns a new connection pool (see
+// CustomConnectionPool) that uses the credentials of the provider. The
+// credentials are refreshed every POSTGRES_CREDENTIALS_REFRESH_INTERVAL,
+// new connections authenticate with the refreshed credentials. Existing
+// connections are retired after POSTGRES_MAX_CONN_AGE, it should be less
+// than the lifetime of the credentials. Idle connections are not
+// established in advance (MinIdleConns), they would block the refresh.
+// The returned stop function ends the refresh and revokes the credentials
+// if the provider supports it, it is called by CloseAll after the pool
+// is closed.
+func CredentialsConnectionPool(opts *pg.Options, provider CredentialProvider) (*pg.DB, func(), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	creds, err := provider.Credentials(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.User, opts.Password = creds.User, creds.Password
+	opts.Dialer = credentialsDialer(opts)
+	opts.MinIdleConns = 0
+
+	db := CustomConnectionPool(opts)
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			if r, ok := provider.(interface{ Revoke(context.Context) error }); ok {
+				ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+				defer cancel()
+				if err := r.Revoke(ctx); err != nil {
+					log.Logger().Warn().Err(err).Str("database", databaseLabel(opts)).
+						Msg("Failed to revoke PostgreSQL credentials")
+				}
+			}
+		})
+	}
+
+	if cfg.CredentialsRefreshInterval > 0 {
+		ticker := time.NewTicker(cfg.CredentialsRefreshInterval)
+		go func() {
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					refreshCredentials(db.Options(), provider)
+				}
+			}
+		}()
+	}
+
+	poolsMu.Lock()
+	closePool := pools[db]
+	pools[db] = func(ctx context.Context) error {
+		defer stop()
+		return closePool(ctx)
+	}
+	poolsMu.Unlock()
+
+	return db, stop, nil
+}
+
+// refreshCredentials updates the credentials of the connection pool
+// options if they changed, the options are read by the pool when
+// establishing new connections
+func refreshCredentials(opts *pg.Options, provider CredentialProvider) {
+	database := databaseLabel(opts)
+	logger := log.Logger().With().Str("database", database).Logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	creds, err := provider.Credentials(ctx)
+	if err != nil {
+		pacePostgresCredentialsRefreshTotal.With(prometheus.Labels{"database": database, "result": "failed"}).Inc()
+		logger.Warn().Err(err).Msg("PostgreSQL credentials refresh failed")
+		return
+	}
+
+	credentialsMu.Lock()
+	changed := opts.User != creds.User || opts.Password != creds.Password
+	opts.User, opts.Password = creds.User, creds.Password
+	credentialsMu.Unlock()
+
+	if !changed {
+		pacePostgresCredentialsRefreshTotal.With(prometheus.Labels{"database": database, "result": "unchanged"}).Inc()
+		return
+	}
+	pacePostgresCredentialsRefreshTotal.With(prometheus.Labels{"database": database, "result": "changed"}).Inc()
+	logger.Info().Str("user", creds.User).Msg("PostgreSQL credentials changed")
+}
+
+// credentialProvider returns the provider configured for the passed
+// config or nil if the static credentials should be used
+func credentialProvider(c *config) CredentialProvider {
+	switch {
+	case c.VaultPath != "":
+		return &VaultCredentialProvider{
+			Addr:        c.VaultAddr,
+			Token:       c.VaultToken,
+			Path:        c.VaultPath,
+			MinTTL:      2 * c.CredentialsRefreshInterval,
+			RevokeDelay: c.MaxConnAge,
+		}
+	case c.UserFile != "" && c.PasswordFile != "":
+		return &FileCredentialProvider{UserFile: c.UserFile, PasswordFile: c.PasswordFile}
+	}
+	return nil
+}
+
+// connectionPool creates the connection pool for the passed config
+// using the configured credential provider, the requirements of the
+// config are checked by the readiness check
+func connectionPool(c *config, opts *pg.Options) *pg.DB {
+	var db *pg.DB
+	if provider := credentialProvider(c); provider != nil {
+		var err error
+		db, _, err = CredentialsConnectionPool(opts, provider)
+		if err != nil {
+			log.Fatalf("Failed to get postgres credentials: %v", err)
+		}
+	} else {
+		db = CustomConnectionPool(opts)
+	}
+
+	if req, ok := requirements(c); ok {
+		RegisterRequirements(db, req)
+	}
+	return db
+}
@@ -0,0 +1,203 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+func TestFileCredentialProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	p := &FileCredentialProvider{
+		UserFile:     filepath.Join(dir, "user"),
+		PasswordFile: filepath.Join(dir, "password"),
+	}
+
+	if _, err := p.Credentials(context.Background()); err == nil {
+		t.Error("expected error for missing files")
+	}
+
+	for file, content := range map[string]string{p.UserFile: "app\n", p.PasswordFile: "secret\n"} {
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	creds, err := p.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds != (Credentials{User: "app", Password: "secret"}) {
+		t.Errorf("unexpected credentials %#v", creds)
+	}
+}
+
+func TestVaultCredentialProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/database/creds/app" || r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"lease_duration":3600,"data":{"username":"v-app-1","password":"secret"}}`)
+	}))
+	defer srv.Close()
+
+	p := &VaultCredentialProvider{Addr: srv.URL + "/", Token: "token", Path: "database/creds/app"}
+	creds, err := p.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds != (Credentials{User: "v-app-1", Password: "secret"}) {
+		t.Errorf("unexpected credentials %#v", creds)
+	}
+
+	p = &VaultCredentialProvider{Addr: srv.URL, Token: "invalid", Path: "database/creds/app"}
+	if _, err := p.Credentials(context.Background()); err == nil {
+		t.Error("expected error for invalid token")
+	}
+}
+
+func TestVaultCredentialProviderLease(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		issued   int
+		ttl      = 3600
+		renewed  []string
+		revoked  = make(chan string, 2)
+		leaseIDs = func() string { return fmt.Sprintf("database/creds/app/%d", issued) }
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body struct {
+			LeaseID string `json:"lease_id"`
+		}
+		switch r.URL.Path {
+		case "/v1/database/creds/app":
+			issued++
+			fmt.Fprintf(w, `{"lease_id":%q,"lease_duration":3600,"renewable":true,"data":{"username":"v-app-%d","password":"secret"}}`, leaseIDs(), issued)
+		case "/v1/sys/leases/renew":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			renewed = append(renewed, body.LeaseID)
+			fmt.Fprintf(w, `{"lease_id":%q,"lease_duration":%d,"renewable":true}`, body.LeaseID, ttl)
+		case "/v1/sys/leases/revoke":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			revoked <- body.LeaseID
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := &VaultCredentialProvider{Addr: srv.URL, Token: "token", Path: "database/creds/app", MinTTL: time.Minute}
+	creds, err := p.Credentials(context.Background())
+	if err != nil || creds.User != "v-app-1" {
+		t.Fatalf("expected first credentials, got %#v (%v)", creds, err)
+	}
+
+	// the lease is renewed as long as it lasts long enough
+	creds, err = p.Credentials(context.Background())
+	if err != nil || creds.User != "v-app-1" {
+		t.Fatalf("expected renewed credentials, got %#v (%v)", creds, err)
+	}
+	mu.Lock()
+	if len(renewed) != 1 || renewed[0] != "database/creds/app/1" {
+		t.Errorf("expected lease to be renewed, got %v", renewed)
+	}
+	ttl = 30 // less than MinTTL
+	mu.Unlock()
+
+	// new credentials are requested and the superseded lease is revoked
+	creds, err = p.Credentials(context.Background())
+	if err != nil || creds.User != "v-app-2" {
+		t.Fatalf("expected new credentials, got %#v (%v)", creds, err)
+	}
+	select {
+	case id := <-revoked:
+		if id != "database/creds/app/1" {
+			t.Errorf("expected superseded lease to be revoked, got %q", id)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected superseded lease to be revoked")
+	}
+
+	if err := p.Revoke(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if id := <-revoked; id != "database/creds/app/2" {
+		t.Errorf("expected current lease to be revoked, got %q", id)
+	}
+}
+
+func TestCredentialsDialer(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()               // nolint: errcheck
+	go io.Copy(ioutil.Discard, server) // nolint: errcheck
+
+	dial := credentialsDialer(&pg.Options{Dialer: func(network, addr string) (net.Conn, error) {
+		return client, nil
+	}})
+	conn, err := dial("tcp", "db:5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		credentialsMu.Lock()
+		credentialsMu.Unlock() // nolint: staticcheck
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("expected credentials to be locked until the startup message is written")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if _, err := conn.Write([]byte("startup")); err != nil {
+		t.Fatal(err)
+	}
+	<-locked
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshCredentials(t *testing.T) {
+	opts := &pg.Options{User: "old", Password: "old"}
+
+	refreshCredentials(opts, CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, fmt.Errorf("unavailable")
+	}))
+	if opts.User != "old" || opts.Password != "old" {
+		t.Errorf("expected credentials to be kept on error, got %q/%q", opts.User, opts.Password)
+	}
+
+	refreshCredentials(opts, CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{User: "new", Password: "secret"}, nil
+	}))
+	if opts.User != "new" || opts.Password != "secret" {
+		t.Errorf("expected credentials to be refreshed, got %q/%q", opts.User, opts.Password)
+	}
+}
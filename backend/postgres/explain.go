@@ -0,0 +1,86 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/types"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var pacePostgresExplainTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("postgres_explain_total"),
+		Help: "Collects stats about the number of query plans captured for slow postgres queries",
+	},
+	[]string{"database", "result"},
+)
+
+func init() {
+	addMetrics(pacePostgresExplainTotal)
+}
+
+// explainable contains the statements that can be explained
+var explainable = map[string]bool{
+	"SELECT": true,
+	"INSERT": true,
+	"UPDATE": true,
+	"DELETE": true,
+	"WITH":   true,
+	"VALUES": true,
+}
+
+// explainSlowQuery returns the plan of the processed query if it took longer
+// than POSTGRES_EXPLAIN_THRESHOLD. The query is explained without ANALYZE,
+// so it is not executed again. An empty string is returned if the query was
+// fast, can't be explained or the EXPLAIN failed. Queries of transactions
+// are not explained, the hook is called while go-pg holds the transaction.
+func explainSlowQuery(event *queryEvent, opts *pg.Options) string {
+	if !isExplainQuery(event.elapsed) {
+		return ""
+	}
+
+	d, ok := event.DB.(*pg.DB)
+	if !ok {
+		pacePostgresExplainTotal.With(prometheus.Labels{"database": databaseLabel(opts), "result": "skipped"}).Inc()
+		return ""
+	}
+
+	q, err := event.FormattedQuery()
+	if err != nil || !explainable[queryOperation(q)] {
+		return ""
+	}
+
+	database := databaseLabel(opts)
+	ctx := event.DB.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// the context of the query may already be expired
+	ctx, cancel := context.WithTimeout(ctxutil.Detached(ctx), cfg.ExplainTimeout)
+	defer cancel()
+
+	var lines []string
+	_, err = WithContext(ctx, d).Query(&lines, "EXPLAIN ?", types.Q(q))
+	if err != nil {
+		pacePostgresExplainTotal.With(prometheus.Labels{"database": database, "result": "failed"}).Inc()
+		log.Ctx(ctx).Debug().Err(err).Msg("PostgreSQL failed to explain slow query")
+		return ""
+	}
+
+	pacePostgresExplainTotal.With(prometheus.Labels{"database": database, "result": "captured"}).Inc()
+	return strings.Join(lines, "\n")
+}
+
+func isExplainQuery(dur time.Duration) bool {
+	return cfg.ExplainThreshold > 0 && dur >= cfg.ExplainThreshold
+}
@@ -0,0 +1,74 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+func TestExplainSlowQuery(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	threshold := cfg.ExplainThreshold
+	cfg.ExplainThreshold = time.Second
+	defer func() { cfg.ExplainThreshold = threshold }()
+
+	event := func(query string, elapsed time.Duration) *queryEvent {
+		return &queryEvent{
+			QueryProcessedEvent: &pg.QueryProcessedEvent{StartTime: time.Now(), DB: db, Query: query},
+			elapsed:             elapsed,
+		}
+	}
+
+	plan := explainSlowQuery(event("SELECT * FROM pg_class WHERE relname = 'a?b'", 2*time.Second), db.Options())
+	if !strings.Contains(plan, "Scan") {
+		t.Errorf("expected plan of slow query, got %q", plan)
+	}
+
+	if plan := explainSlowQuery(event("SELECT 1", time.Millisecond), db.Options()); plan != "" {
+		t.Errorf("expected fast query not to be explained, got %q", plan)
+	}
+	if plan := explainSlowQuery(event("VACUUM", 2*time.Second), db.Options()); plan != "" {
+		t.Errorf("expected VACUUM not to be explained, got %q", plan)
+	}
+}
+
+func TestExplainSlowQueryTx(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	threshold := cfg.ExplainThreshold
+	cfg.ExplainThreshold = time.Millisecond
+	defer func() { cfg.ExplainThreshold = threshold }()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- db.RunInTransaction(func(tx *pg.Tx) error {
+			_, err := tx.Exec("SELECT pg_sleep(0.01)")
+			return err
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected slow query of transaction not to block")
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package generator generates go-pg models from the tables of an existing
+// database schema, so that the models don't drift from the schema.
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Generate returns the go source of the models of the passed tables in a
+// package with the passed name. Nullable columns are pointers, relations
+// are generated for foreign keys (<name>_id) to tables that are generated
+// as well. Tables with a nullable deleted_at column are soft deleted (see
+// postgres.Purge).
+func Generate(packageName string, tables []Table) (string, error) {
+	f := jen.NewFile(packageName)
+	f.PackageComment("// Code generated by github.com/pace/bricks DO NOT EDIT.")
+
+	models := make(map[string]string, len(tables))
+	for _, t := range tables {
+		models[t.Name] = ModelName(t.Name)
+	}
+
+	for _, t := range tables {
+		if len(t.Columns) == 0 {
+			return "", fmt.Errorf("table %q has no columns", t.Name)
+		}
+		name := models[t.Name]
+
+		tableTag := t.Name
+		if t.Schema != "" && t.Schema != "public" {
+			tableTag = t.Schema + "." + t.Name
+		}
+		fields := []jen.Code{
+			jen.Id("tableName").Struct().Tag(map[string]string{"sql": tableTag}).
+				Comment("nolint: structcheck,unused"),
+			jen.Line(),
+		}
+
+		for _, c := range t.Columns {
+			field := jen.Id(FieldName(c.Name)).Add(columnType(c)).Tag(columnTags(c))
+			if c.Default != "" && !c.PrimaryKey && !isDefaultTag(c.Default) {
+				field = field.Comment("default " + c.Default)
+			}
+			fields = append(fields, field)
+		}
+
+		relations := relationFields(t, models)
+		if len(relations) > 0 {
+			fields = append(fields, jen.Line())
+			fields = append(fields, relations...)
+		}
+
+		f.Commentf("%s is the model of the table %s", name, t.Name)
+		f.Type().Id(name).Struct(fields...)
+	}
+
+	return fmt.Sprintf("%#v", f), nil
+}
+
+// relationFields returns the belongs to relations of the table
+func relationFields(t Table, models map[string]string) []jen.Code {
+	var fields []jen.Code
+	for _, fk := range t.ForeignKeys {
+		model, ok := models[fk.RefTable]
+		if !ok || !strings.HasSuffix(fk.Column, "_id") || fk.RefColumn != "id" {
+			continue // go-pg can't join it by convention
+		}
+		name := FieldName(strings.TrimSuffix(fk.Column, "_id"))
+		fields = append(fields, jen.Id(name).Op("*").Id(model))
+	}
+	return fields
+}
+
+// columnTags returns the struct tags of the column. The default of not
+// null columns is added as tag if possible, so that zero values are
+// inserted as DEFAULT by go-pg.
+func columnTags(c Column) map[string]string {
+	if isSoftDelete(c) {
+		return map[string]string{"sql": c.Name, "pg": ",soft_delete"}
+	}
+
+	tag := c.Name
+	if c.PrimaryKey {
+		tag += ",pk"
+	} else if !c.Nullable {
+		tag += ",notnull"
+		if isDefaultTag(c.Default) {
+			tag += ",default:" + c.Default
+		}
+	}
+	if isArray(c) {
+		tag += ",array"
+	}
+	if c.UDTName == "uuid" || c.UDTName == "jsonb" {
+		tag += ",type:" + c.UDTName
+	}
+	return map[string]string{"sql": tag}
+}
+
+// isDefaultTag returns true if the default expression can be
+// used as value of a struct tag option
+func isDefaultTag(def string) bool {
+	return def != "" && !strings.ContainsAny(def, ",'\"`: ")
+}
+
+func isSoftDelete(c Column) bool {
+	return c.Name == "deleted_at" && c.Nullable && strings.HasPrefix(c.UDTName, "timestamp")
+}
+
+func isArray(c Column) bool {
+	return strings.HasPrefix(c.UDTName, "_")
+}
+
+// columnType returns the go type of the column
+func columnType(c Column) *jen.Statement {
+	if isSoftDelete(c) {
+		return jen.Qual("time", "Time")
+	}
+	if isArray(c) {
+		return jen.Index().Add(scalarType(strings.TrimPrefix(c.UDTName, "_")))
+	}
+
+	typ := scalarType(c.UDTName)
+	switch c.UDTName {
+	case "json", "jsonb", "bytea":
+		return typ // nil is NULL
+	}
+	if c.Nullable && !c.PrimaryKey {
+		return jen.Op("*").Add(typ)
+	}
+	return typ
+}
+
+// scalarType maps the postgres type to a go type, unknown types (e.g.
+// enums) are mapped to string
+func scalarType(udt string) *jen.Statement {
+	switch udt {
+	case "bool":
+		return jen.Bool()
+	case "int2":
+		return jen.Int16()
+	case "int4":
+		return jen.Int()
+	case "int8":
+		return jen.Int64()
+	case "float4":
+		return jen.Float32()
+	case "float8":
+		return jen.Float64()
+	case "timestamp", "timestamptz", "date":
+		return jen.Qual("time", "Time")
+	case "json", "jsonb":
+		return jen.Map(jen.String()).Interface()
+	case "bytea":
+		return jen.Index().Byte()
+	}
+	// text, varchar, uuid, numeric (arbitrary precision), ...
+	return jen.String()
+}
+
+// commonInitialisms are written in upper case in go names
+var commonInitialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uri": true, "url": true, "uuid": true, "utc": true,
+}
+
+// FieldName returns the go name of a column, e.g. "author_id" is AuthorID
+func FieldName(column string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if commonInitialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// ModelName returns the go name of the model of a table, the
+// singular of the table name, e.g. "order_items" is OrderItem
+func ModelName(table string) string {
+	return FieldName(singular(table))
+}
+
+// singular returns the singular of plural english nouns
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
@@ -0,0 +1,131 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pace/bricks/backend/postgres"
+)
+
+var testTables = []Table{
+	{
+		Schema: "public",
+		Name:   "authors",
+		Columns: []Column{
+			{Name: "id", UDTName: "int4", Default: "nextval('authors_id_seq'::regclass)", PrimaryKey: true},
+			{Name: "name", UDTName: "text"},
+		},
+	},
+	{
+		Schema: "public",
+		Name:   "articles",
+		Columns: []Column{
+			{Name: "id", UDTName: "uuid", Default: "gen_random_uuid()", PrimaryKey: true},
+			{Name: "author_id", UDTName: "int4"},
+			{Name: "title", UDTName: "varchar"},
+			{Name: "summary", UDTName: "text", Nullable: true},
+			{Name: "tags", UDTName: "_text", Nullable: true},
+			{Name: "attributes", UDTName: "jsonb", Nullable: true},
+			{Name: "version", UDTName: "int4", Default: "0"},
+			{Name: "status", UDTName: "text", Default: "'draft'::text"},
+			{Name: "published_at", UDTName: "timestamptz", Nullable: true},
+			{Name: "deleted_at", UDTName: "timestamptz", Nullable: true},
+		},
+		ForeignKeys: []ForeignKey{{Column: "author_id", RefTable: "authors", RefColumn: "id"}},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate("models", testTables)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"// Code generated by github.com/pace/bricks DO NOT EDIT.",
+		"package models",
+		"// Author is the model of the table authors",
+		"type Author struct {",
+		"tableName struct{} `sql:\"authors\"` // nolint: structcheck,unused",
+		"ID   int    `sql:\"id,pk\"`",
+		"Name string `sql:\"name,notnull\"`",
+		"type Article struct {",
+		"ID          string                 `sql:\"id,pk,type:uuid\"`",
+		"AuthorID    int                    `sql:\"author_id,notnull\"`",
+		"Summary     *string                `sql:\"summary\"`",
+		"Tags        []string               `sql:\"tags,array\"`",
+		"Attributes  map[string]interface{} `sql:\"attributes,type:jsonb\"`",
+		"Version     int                    `sql:\"version,notnull,default:0\"`",
+		"Status      string                 `sql:\"status,notnull\"` // default 'draft'::text",
+		"PublishedAt *time.Time             `sql:\"published_at\"`",
+		"DeletedAt   time.Time              `pg:\",soft_delete\" sql:\"deleted_at\"`",
+		"Author *Author",
+	}
+	for _, line := range expected {
+		if !strings.Contains(src, line) {
+			t.Errorf("expected generated source to contain %q, got:\n%s", line, src)
+		}
+	}
+
+	if _, err := Generate("models", []Table{{Name: "empty"}}); err == nil {
+		t.Error("expected error for table without columns")
+	}
+}
+
+func TestNames(t *testing.T) {
+	cases := map[string]string{
+		"order_items": "OrderItem",
+		"categories":  "Category",
+		"addresses":   "Address",
+		"status":      "Status",
+		"api_keys":    "APIKey",
+	}
+	for table, expected := range cases {
+		if name := ModelName(table); name != expected {
+			t.Errorf("expected model name of %s to be %s, got %s", table, expected, name)
+		}
+	}
+	if name := FieldName("external_url"); name != "ExternalURL" {
+		t.Errorf("expected ExternalURL, got %s", name)
+	}
+	if name := FieldName("2fa"); name != "X2fa" {
+		t.Errorf("expected X2fa, got %s", name)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	db := postgres.ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec(`DROP TABLE IF EXISTS generator_articles, generator_authors;
+		CREATE TABLE generator_authors (id serial PRIMARY KEY, name text NOT NULL);
+		CREATE TABLE generator_articles (id serial PRIMARY KEY,
+			author_id int NOT NULL REFERENCES generator_authors (id), summary text)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE generator_articles, generator_authors") // nolint: errcheck
+
+	tables, err := Inspect(context.Background(), db, "public", "generator_articles", "generator_authors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0].Name != "generator_articles" {
+		t.Fatalf("expected the 2 tables, got %+v", tables)
+	}
+	articles := tables[0]
+	if !articles.Columns[0].PrimaryKey || !articles.Columns[2].Nullable || articles.Columns[1].Nullable {
+		t.Errorf("expected primary key and nullable columns, got %+v", articles.Columns)
+	}
+	if len(articles.ForeignKeys) != 1 || articles.ForeignKeys[0].RefTable != "generator_authors" {
+		t.Errorf("expected foreign key, got %+v", articles.ForeignKeys)
+	}
+}
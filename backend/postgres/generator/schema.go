@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/backend/postgres"
+)
+
+// Table of the database schema
+type Table struct {
+	Schema      string
+	Name        string
+	Columns     []Column
+	ForeignKeys []ForeignKey
+}
+
+// Column of a table
+type Column struct {
+	Name string
+	// UDTName is the postgres type name, e.g. int4, timestamptz
+	// or _text for text arrays
+	UDTName    string
+	Nullable   bool
+	Default    string
+	PrimaryKey bool
+}
+
+// ForeignKey of a table referencing the column of another table
+type ForeignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// Inspect reads the tables of the schema (e.g. "public") from the database,
+// if tables are passed only these tables are returned
+func Inspect(ctx context.Context, db *pg.DB, schema string, tables ...string) ([]Table, error) {
+	db = postgres.WithContext(ctx, db)
+
+	var columns []struct {
+		TableName  string
+		ColumnName string
+		UDTName    string `sql:"udt_name"`
+		Nullable   bool
+		Default    string
+	}
+	_, err := db.Query(&columns, `SELECT c.table_name, c.column_name, c.udt_name,
+		c.is_nullable = 'YES' AS nullable, coalesce(c.column_default, '') AS default
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = ? AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position`, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var pks []struct {
+		TableName  string
+		ColumnName string
+	}
+	_, err = db.Query(&pks, `SELECT kcu.table_name, kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+		WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = ?`, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var fks []struct {
+		TableName  string
+		ColumnName string
+		RefTable   string
+		RefColumn  string
+	}
+	_, err = db.Query(&fks, `SELECT kcu.table_name, kcu.column_name,
+		ccu.table_name AS ref_table, ccu.column_name AS ref_column
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+		JOIN information_schema.constraint_column_usage ccu
+			ON ccu.constraint_schema = tc.constraint_schema AND ccu.constraint_name = tc.constraint_name
+		WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = ?
+		ORDER BY kcu.table_name, kcu.column_name`, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	include := make(map[string]bool)
+	for _, t := range tables {
+		include[t] = true
+	}
+	byName := make(map[string]*Table)
+	for _, c := range columns {
+		if len(include) > 0 && !include[c.TableName] {
+			continue
+		}
+		t, ok := byName[c.TableName]
+		if !ok {
+			t = &Table{Schema: schema, Name: c.TableName}
+			byName[c.TableName] = t
+		}
+		t.Columns = append(t.Columns, Column{
+			Name:     c.ColumnName,
+			UDTName:  c.UDTName,
+			Nullable: c.Nullable,
+			Default:  c.Default,
+		})
+	}
+	for _, pk := range pks {
+		if t, ok := byName[pk.TableName]; ok {
+			for i := range t.Columns {
+				if t.Columns[i].Name == pk.ColumnName {
+					t.Columns[i].PrimaryKey = true
+				}
+			}
+		}
+	}
+	for _, fk := range fks {
+		if t, ok := byName[fk.TableName]; ok {
+			t.ForeignKeys = append(t.ForeignKeys, ForeignKey{
+				Column:    fk.ColumnName,
+				RefTable:  fk.RefTable,
+				RefColumn: fk.RefColumn,
+			})
+		}
+	}
+
+	result := make([]Table, 0, len(byName))
+	for _, t := range byName {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
@@ -0,0 +1,221 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/maintenance/readonly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pacePostgresInsertChunkTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_insert_chunk_total"),
+			Help: "Collects stats about the number of chunks inserted by InsertBatch partitioned by result",
+		},
+		[]string{"database", "table", "result"},
+	)
+	pacePostgresInsertChunkDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_insert_chunk_duration_seconds"),
+			Help:    "Collect performance metrics for each chunk inserted by InsertBatch",
+			Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database", "table"},
+	)
+	pacePostgresInsertRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_insert_rows_total"),
+			Help: "Collects stats about the number of rows inserted by InsertBatch",
+		},
+		[]string{"database", "table"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresInsertChunkTotal,
+		pacePostgresInsertChunkDurationSeconds,
+		pacePostgresInsertRowsTotal,
+	)
+}
+
+// InsertErrors of all failed chunks of InsertBatchIndependent
+type InsertErrors []*BatchError
+
+func (e InsertErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// InsertBatch inserts the rows (a slice of models, e.g. []*Article) using
+// multi row inserts of at most chunkSize rows, this bounds the size of the
+// statements for large slices. All chunks are inserted in a single
+// transaction (see Transaction), either all or none of the rows are inserted.
+// The error of the failed chunk is returned as *BatchError. A chunkSize of
+// 0 defaults to POSTGRES_INSERT_CHUNK_SIZE.
+func InsertBatch(ctx context.Context, db *pg.DB, rows interface{}, chunkSize int) (int, error) {
+	b, err := newInsertBatch(ctx, db, rows, chunkSize)
+	if err != nil {
+		return 0, err
+	}
+	defer b.span.Finish()
+
+	inserted := 0
+	err = Transaction(b.ctx, db, func(tx *pg.Tx) error {
+		inserted = 0 // the transaction may be retried
+		for chunk := 0; chunk*b.chunkSize < b.len; chunk++ {
+			n, err := b.insertChunk(tx, chunk)
+			if err != nil {
+				return err
+			}
+			inserted += n
+		}
+		return nil
+	})
+	if err != nil {
+		b.span.LogFields(olog.Error(err))
+		return 0, err
+	}
+
+	pacePostgresInsertRowsTotal.With(b.labels).Add(float64(inserted))
+	b.span.LogFields(olog.Int("inserted", inserted))
+	return inserted, nil
+}
+
+// InsertBatchIndependent inserts the rows like InsertBatch, but each chunk
+// is inserted independently (atomically) without a surrounding transaction.
+// Failed chunks don't stop the insert of the remaining chunks, their errors
+// are returned as InsertErrors together with the number of inserted rows.
+func InsertBatchIndependent(ctx context.Context, db *pg.DB, rows interface{}, chunkSize int) (int, error) {
+	b, err := newInsertBatch(ctx, db, rows, chunkSize)
+	if err != nil {
+		return 0, err
+	}
+	defer b.span.Finish()
+
+	var (
+		inserted int
+		errs     InsertErrors
+	)
+	cdb := WithContext(b.ctx, db)
+	for chunk := 0; chunk*b.chunkSize < b.len; chunk++ {
+		n, err := b.insertChunk(cdb, chunk)
+		if err != nil {
+			errs = append(errs, err.(*BatchError))
+			continue
+		}
+		inserted += n
+		pacePostgresInsertRowsTotal.With(b.labels).Add(float64(n))
+	}
+
+	b.span.LogFields(olog.Int("inserted", inserted), olog.Int("failed_chunks", len(errs)))
+	log.Ctx(b.ctx).Info().Str("table", b.table).Int("inserted", inserted).
+		Int("failed_chunks", len(errs)).Msg("PostgreSQL batch insert finished")
+	if len(errs) > 0 {
+		return inserted, errs
+	}
+	return inserted, nil
+}
+
+// insertBatch contains the state of a batch insert
+type insertBatch struct {
+	ctx       context.Context
+	span      opentracing.Span
+	rows      reflect.Value
+	len       int
+	chunkSize int
+	table     string
+	labels    prometheus.Labels
+}
+
+func newInsertBatch(ctx context.Context, db *pg.DB, rows interface{}, chunkSize int) (*insertBatch, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	if readonly.Enabled() {
+		return nil, ErrReadOnly
+	}
+
+	v := reflect.ValueOf(rows)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("postgres: InsertBatch expects a slice of models, got %T", rows)
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("postgres: InsertBatch expects a slice of models, got %T", rows)
+	}
+
+	if chunkSize <= 0 {
+		mustSetup()
+		chunkSize = cfg.InsertChunkSize
+	}
+	table := strings.Trim(string(orm.GetTable(typ).Name), `"`)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: INSERT %s", table))
+	span.LogFields(olog.Int("rows", v.Len()), olog.Int("chunk_size", chunkSize))
+
+	return &insertBatch{
+		ctx:       ctx,
+		span:      span,
+		rows:      v,
+		len:       v.Len(),
+		chunkSize: chunkSize,
+		table:     table,
+		labels:    prometheus.Labels{"database": databaseLabel(db.Options()), "table": table},
+	}, nil
+}
+
+// insertChunk inserts the rows of the chunk using db, errors
+// are returned as *BatchError
+func (b *insertBatch) insertChunk(db orm.DB, chunk int) (int, error) {
+	offset := chunk * b.chunkSize
+	end := offset + b.chunkSize
+	if end > b.len {
+		end = b.len
+	}
+
+	// the chunk shares the underlying array, so that returned
+	// primary keys are set on the passed models
+	models := reflect.New(b.rows.Type())
+	models.Elem().Set(b.rows.Slice(offset, end))
+
+	start := time.Now()
+	_, err := db.Model(models.Interface()).Insert()
+	pacePostgresInsertChunkDurationSeconds.With(b.labels).Observe(time.Since(start).Seconds())
+
+	fields := []olog.Field{olog.Int("chunk", chunk), olog.Int("rows", end-offset)}
+	if err != nil {
+		berr := &BatchError{Batch: chunk, Offset: offset, Rows: end - offset, Err: err}
+		pacePostgresInsertChunkTotal.With(batchLabels(b.labels, copyBatchFailed)).Inc()
+		b.span.LogFields(append(fields, olog.Error(err))...)
+		log.Ctx(b.ctx).Warn().Err(berr).Str("table", b.table).Msg("PostgreSQL insert chunk failed")
+		return 0, berr
+	}
+
+	pacePostgresInsertChunkTotal.With(batchLabels(b.labels, copyBatchSuccess)).Inc()
+	b.span.LogFields(fields...)
+	return end - offset, nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+type insertTest struct {
+	tableName struct{} `sql:"insert_test"` // nolint: structcheck,unused
+
+	ID   int
+	Name string
+}
+
+func TestInsertBatchInvalidRows(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := InsertBatch(context.Background(), db, insertTest{}, 10); err == nil {
+		t.Error("expected error for rows that are not a slice")
+	}
+	if _, err := InsertBatch(context.Background(), db, []int{1, 2}, 10); err == nil {
+		t.Error("expected error for rows that are not models")
+	}
+}
+
+func TestInsertBatch(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec("DROP TABLE IF EXISTS insert_test; CREATE TABLE insert_test (id serial PRIMARY KEY, name text NOT NULL CHECK (name <> 'invalid'))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE insert_test") // nolint: errcheck
+
+	rows := make([]*insertTest, 25)
+	for i := range rows {
+		rows[i] = &insertTest{Name: "row"}
+	}
+
+	ctx := context.Background()
+	n, err := InsertBatch(ctx, db, rows, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 25 || rows[24].ID == 0 {
+		t.Errorf("expected 25 inserted rows with ids, got %d (last id %d)", n, rows[24].ID)
+	}
+
+	// a failed chunk rolls back all chunks
+	reset := func() {
+		for _, row := range rows {
+			row.ID = 0
+		}
+	}
+	reset()
+	rows[15] = &insertTest{Name: "invalid"}
+	n, err = InsertBatch(ctx, db, rows, 10)
+	berr, ok := err.(*BatchError)
+	if !ok || berr.Batch != 1 || berr.Offset != 10 || n != 0 {
+		t.Errorf("expected error of chunk 1, got %v (%d rows)", err, n)
+	}
+
+	// independent chunks are inserted anyway
+	reset()
+	n, err = InsertBatchIndependent(ctx, db, rows, 10)
+	errs, ok := err.(InsertErrors)
+	if !ok || len(errs) != 1 || n != 15 {
+		t.Errorf("expected one failed chunk and 15 inserted rows, got %v (%d rows)", err, n)
+	}
+
+	count, err := db.Model((*insertTest)(nil)).Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 40 {
+		t.Errorf("expected 40 rows, got %d", count)
+	}
+}
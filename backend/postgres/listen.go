@@ -0,0 +1,158 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// listenerPingChannel is used to check the health of the listener connection
+const listenerPingChannel = "bricks_listener_ping"
+
+// listenerPingInterval without notifications after which the listener
+// connection is checked, without response it is reconnected
+var listenerPingInterval = 30 * time.Second
+
+var (
+	pacePostgresNotificationTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_notification_total"),
+			Help: "Collects stats about the number of received postgres notifications",
+		},
+		[]string{"database", "channel"},
+	)
+	pacePostgresListenerReconnectTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_listener_reconnect_total"),
+			Help: "Collects stats about the number of reconnects of postgres listeners",
+		},
+		[]string{"database"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresNotificationTotal,
+		pacePostgresListenerReconnectTotal,
+	)
+}
+
+// NotificationHandler is called for each notification received on a channel
+type NotificationHandler func(ctx context.Context, channel, payload string)
+
+// ErrNoChannels is returned if Listen is called without channels
+var ErrNoChannels = errors.New("no channels to listen on")
+
+// Listen subscribes to the passed notification channels (LISTEN) and calls
+// fn for each notification until ctx is done. If the connection fails or
+// becomes unhealthy, the listener reconnects with backoff (see
+// POSTGRES_MIN_RETRY_BACKOFF and POSTGRES_MAX_RETRY_BACKOFF). Notifications
+// sent while the listener is reconnecting are lost.
+func Listen(ctx context.Context, db *pg.DB, fn NotificationHandler, channels ...string) error {
+	if len(channels) == 0 {
+		return ErrNoChannels
+	}
+
+	database := databaseLabel(db.Options())
+	logger := log.Ctx(ctx).With().Strs("channels", channels).Logger()
+
+	for attempt := 0; ; attempt++ {
+		established, err := listen(ctx, db, database, fn, channels)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if established {
+			attempt = 0
+		}
+
+		pacePostgresListenerReconnectTotal.With(prometheus.Labels{"database": database}).Inc()
+		logger.Warn().Err(err).Int("attempt", attempt+1).Msg("PostgreSQL listener reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+}
+
+// listen receives notifications using a single connection until an error
+// occurs, returns true if the connection was established
+func listen(ctx context.Context, db *pg.DB, database string, fn NotificationHandler, channels []string) (bool, error) {
+	ln := db.Listen()
+	defer ln.Close() // nolint: errcheck
+
+	all := append([]string{listenerPingChannel}, channels...)
+	err := ln.Listen(all...)
+	if err != nil {
+		return false, err
+	}
+	log.Ctx(ctx).Info().Strs("channels", channels).Msg("PostgreSQL listener established")
+
+	// close the listener to abort receiving if ctx is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ln.Close() // nolint: errcheck
+		case <-done:
+		}
+	}()
+
+	pinged := false
+	for {
+		channel, payload, err := ln.ReceiveTimeout(listenerPingInterval)
+		if err != nil {
+			if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+				return true, err
+			}
+
+			// no notification in time, check if the connection is still healthy
+			if pinged {
+				return true, fmt.Errorf("no response to ping after %v", listenerPingInterval)
+			}
+			_, err = db.Exec("NOTIFY ?", pg.F(listenerPingChannel))
+			if err != nil {
+				return true, err
+			}
+			pinged = true
+			continue
+		}
+
+		// any notification shows that the connection is healthy
+		pinged = false
+		if channel == listenerPingChannel {
+			continue
+		}
+
+		handleNotification(ctx, database, fn, channel, payload)
+	}
+}
+
+func handleNotification(ctx context.Context, database string, fn NotificationHandler, channel, payload string) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: NOTIFY %s", channel))
+	defer span.Finish()
+	span.LogFields(olog.String("channel", channel), olog.Int("size", len(payload)))
+
+	pacePostgresNotificationTotal.With(prometheus.Labels{
+		"database": database,
+		"channel":  channel,
+	}).Inc()
+	log.Ctx(ctx).Debug().Str("channel", channel).Int("size", len(payload)).
+		Msg("PostgreSQL notification received")
+
+	fn(ctx, channel, payload)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+func TestListenWithoutChannels(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if err := Listen(context.Background(), db, nil); err != ErrNoChannels {
+		t.Errorf("Expected error %v, got: %v", ErrNoChannels, err)
+	}
+}
+
+func TestListen(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	received := make(chan string, 1)
+	done := make(chan error)
+	go func() {
+		done <- Listen(ctx, db, func(ctx context.Context, channel, payload string) {
+			received <- payload
+		}, "bricks_test")
+	}()
+
+	// notify until the listener is established
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case payload := <-received:
+			if payload != "hello" {
+				t.Errorf("Expected payload %q, got: %q", "hello", payload)
+			}
+			break loop
+		case <-ticker.C:
+			db.Exec("NOTIFY ?, ?", pg.F("bricks_test"), "hello") // nolint: errcheck
+		case <-ctx.Done():
+			t.Fatal("Expected notification to be received")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected listener to stop without error, got: %v", err)
+	}
+}
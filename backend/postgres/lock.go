@@ -0,0 +1,162 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of lock acquisitions, used as metric label
+const (
+	lockAcquired    = "acquired"
+	lockNotAcquired = "not_acquired"
+	lockError       = "error"
+)
+
+// lockPollInterval in which a held lock is tried to be acquired again
+var lockPollInterval = 100 * time.Millisecond
+
+var (
+	pacePostgresLockTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_lock_total"),
+			Help: "Collects stats about the number of postgres advisory lock acquisitions partitioned by result",
+		},
+		[]string{"database", "result"},
+	)
+	pacePostgresLockWaitDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_lock_wait_duration_seconds"),
+			Help:    "Collect the time waited for postgres advisory locks",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresLockTotal,
+		pacePostgresLockWaitDurationSeconds,
+	)
+}
+
+// ErrLockNotAcquired is returned if the lock is held by another session
+// until the context is done
+var ErrLockNotAcquired = errors.New("advisory lock not acquired")
+
+// Lock is an acquired advisory lock
+type Lock struct {
+	Key string
+
+	tx   *pg.Tx
+	span opentracing.Span
+}
+
+// AcquireLock acquires the transaction level advisory lock (see
+// pg_advisory_xact_lock) for key. If the lock is held by another session,
+// it is tried again until ctx is done, then ErrLockNotAcquired is returned.
+// The lock uses a dedicated connection of the pool (transaction) until it is
+// released, if the connection breaks the lock is released by the database.
+func AcquireLock(ctx context.Context, db *pg.DB, key string) (*Lock, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: Lock %s", key))
+	span.LogFields(olog.String("key", key))
+
+	database := databaseLabel(db.Options())
+	start := time.Now()
+
+	lock, err := acquireLock(ctx, db, key)
+	pacePostgresLockWaitDurationSeconds.With(prometheus.Labels{"database": database}).
+		Observe(time.Since(start).Seconds())
+
+	result := lockAcquired
+	switch {
+	case err == ErrLockNotAcquired:
+		result = lockNotAcquired
+	case err != nil:
+		result = lockError
+	}
+	pacePostgresLockTotal.With(prometheus.Labels{"database": database, "result": result}).Inc()
+	span.LogFields(olog.String("result", result))
+
+	if err != nil {
+		span.LogFields(olog.Error(err))
+		span.Finish()
+		log.Ctx(ctx).Debug().Err(err).Str("key", key).Msg("PostgreSQL advisory lock not acquired")
+		return nil, err
+	}
+
+	lock.span = span
+	log.Ctx(ctx).Debug().Str("key", key).Msg("PostgreSQL advisory lock acquired")
+	return lock, nil
+}
+
+func acquireLock(ctx context.Context, db *pg.DB, key string) (*Lock, error) {
+	tx, err := db.WithContext(ctx).Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var acquired bool
+		_, err = tx.QueryOne(pg.Scan(&acquired), "SELECT pg_try_advisory_xact_lock(?)", lockID(key))
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+		if acquired {
+			return &Lock{Key: key, tx: tx}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = tx.Rollback()
+			return nil, ErrLockNotAcquired
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Release releases the lock and returns the connection to the pool
+func (l *Lock) Release() error {
+	defer l.span.Finish()
+	return l.tx.Rollback()
+}
+
+// WithLock executes fn while holding the advisory lock for key (see
+// AcquireLock). Use it to make sure only one instance of a service executes
+// fn at a time, e.g. for cron-style work. If the lock is not acquired until
+// ctx is done, ErrLockNotAcquired is returned and fn is not executed.
+func WithLock(ctx context.Context, db *pg.DB, key string, fn func(ctx context.Context) error) error {
+	lock, err := AcquireLock(ctx, db, key)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := lock.Release(); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Failed to release PostgreSQL advisory lock")
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// lockID returns the advisory lock id for key
+func lockID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key)) // nolint: errcheck
+	return int64(h.Sum64())
+}
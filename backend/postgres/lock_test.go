@@ -0,0 +1,61 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockID(t *testing.T) {
+	if lockID("cron") != lockID("cron") {
+		t.Error("Expected lock ids of the same key to be equal")
+	}
+	if lockID("cron") == lockID("cron2") {
+		t.Error("Expected lock ids of different keys to differ")
+	}
+}
+
+func TestWithLock(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	ctx := context.Background()
+	lock, err := AcquireLock(ctx, db, "TestWithLock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lock is held, second acquisition times out
+	tctx, cancel := context.WithTimeout(ctx, 3*lockPollInterval)
+	defer cancel()
+	executed := false
+	err = WithLock(tctx, db, "TestWithLock", func(ctx context.Context) error {
+		executed = true
+		return nil
+	})
+	if err != ErrLockNotAcquired || executed {
+		t.Errorf("Expected ErrLockNotAcquired without execution, got: %v (executed %v)", err, executed)
+	}
+
+	// lock is released, second acquisition succeeds
+	err = lock.Release()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tctx, cancel = context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	err = WithLock(tctx, db, "TestWithLock", func(ctx context.Context) error {
+		executed = true
+		return nil
+	})
+	if err != nil || !executed {
+		t.Errorf("Expected execution without error, got: %v (executed %v)", err, executed)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queryMetrics aggregates the query counters of a database in memory, they
+// are flushed to the prometheus counters every POSTGRES_METRICS_FLUSH_INTERVAL.
+// This avoids the label lookups and synchronization of the prometheus metrics
+// on the hot path of every query. The flush runs while pools of the database
+// are open (see startFlush).
+type queryMetrics struct {
+	total, failed, deadlineExceeded, slow, rows, affected int64 // accessed atomically
+
+	// metrics bound to the database label
+	totalCounter, failedCounter, deadlineExceededCounter prometheus.Counter
+	slowCounter, rowsCounter, affectedCounter            prometheus.Counter
+	duration                                             prometheus.Histogram
+
+	mu    sync.Mutex
+	pools int           // open pools of the database
+	stop  chan struct{} // stops the background flush
+}
+
+var queryMetricsMap sync.Map // database label -> *queryMetrics
+
+// queryResult contains the data of a processed query that is collected
+type queryResult struct {
+	elapsed          time.Duration
+	failed           bool
+	deadlineExceeded bool
+	rows, affected   int
+}
+
+// recordQuery collects the metrics of the processed query
+func recordQuery(database string, r queryResult) {
+	m := queryMetricsFor(database)
+
+	atomic.AddInt64(&m.total, 1)
+	if isSlowQuery(r.elapsed) {
+		atomic.AddInt64(&m.slow, 1)
+	}
+	if r.failed {
+		atomic.AddInt64(&m.failed, 1)
+		if r.deadlineExceeded {
+			atomic.AddInt64(&m.deadlineExceeded, 1)
+		}
+	}
+	if r.rows > 0 {
+		atomic.AddInt64(&m.rows, int64(r.rows))
+	}
+	if r.affected > 0 {
+		atomic.AddInt64(&m.affected, int64(r.affected))
+	}
+
+	m.duration.Observe(r.elapsed.Seconds())
+
+	if cfg.MetricsFlushInterval <= 0 {
+		m.flush()
+	}
+}
+
+// queryMetricsFor returns the metrics of the database
+func queryMetricsFor(database string) *queryMetrics {
+	if m, ok := queryMetricsMap.Load(database); ok {
+		return m.(*queryMetrics)
+	}
+
+	labels := prometheus.Labels{"database": database}
+	m, _ := queryMetricsMap.LoadOrStore(database, &queryMetrics{
+		totalCounter:            pacePostgresQueryTotal.With(labels),
+		failedCounter:           pacePostgresQueryFailed.With(labels),
+		deadlineExceededCounter: pacePostgresQueryDeadlineExceeded.With(labels),
+		slowCounter:             pacePostgresQuerySlow.With(labels),
+		rowsCounter:             pacePostgresQueryRowsTotal.With(labels),
+		affectedCounter:         pacePostgresQueryAffectedTotal.With(labels),
+		duration:                pacePostgresQueryDurationSeconds.With(labels),
+	})
+	return m.(*queryMetrics)
+}
+
+// startFlush starts the background flush of the metrics of the database
+// for a new pool. The flush stops once the returned function was called
+// for all pools of the database, the remaining counters are flushed then.
+func startFlush(database string) func() {
+	m := queryMetricsFor(database)
+	if cfg.MetricsFlushInterval <= 0 {
+		return func() {}
+	}
+
+	m.mu.Lock()
+	m.pools++
+	if m.pools == 1 {
+		m.stop = make(chan struct{})
+		go m.run(cfg.MetricsFlushInterval, m.stop)
+	}
+	m.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.pools--
+			if m.pools == 0 {
+				close(m.stop)
+			}
+			m.mu.Unlock()
+			m.flush()
+		})
+	}
+}
+
+// run flushes the metrics every interval until stop is closed
+func (m *queryMetrics) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.flush()
+		}
+	}
+}
+
+// flushQueryMetrics adds the aggregated counters of all databases
+// to the prometheus counters
+func flushQueryMetrics() {
+	queryMetricsMap.Range(func(_, m interface{}) bool {
+		m.(*queryMetrics).flush()
+		return true
+	})
+}
+
+func (m *queryMetrics) flush() {
+	add := func(c prometheus.Counter, v *int64) {
+		if n := atomic.SwapInt64(v, 0); n > 0 {
+			c.Add(float64(n))
+		}
+	}
+
+	add(m.totalCounter, &m.total)
+	add(m.failedCounter, &m.failed)
+	add(m.deadlineExceededCounter, &m.deadlineExceeded)
+	add(m.slowCounter, &m.slow)
+	add(m.rowsCounter, &m.rows)
+	add(m.affectedCounter, &m.affected)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRecordQuery(t *testing.T) {
+	labels := prometheus.Labels{"database": "metricstest"}
+
+	recordQuery("metricstest", queryResult{elapsed: time.Millisecond, rows: 3})
+	recordQuery("metricstest", queryResult{elapsed: 2 * cfg.SlowQueryThreshold, failed: true, deadlineExceeded: true})
+
+	// counters are only updated with the flush
+	if v := counterValue(t, pacePostgresQueryTotal.With(labels)); v != 0 {
+		t.Errorf("expected no queries before the flush, got %v", v)
+	}
+
+	flushQueryMetrics()
+
+	cases := []struct {
+		name     string
+		counter  *prometheus.CounterVec
+		expected float64
+	}{
+		{"total", pacePostgresQueryTotal, 2},
+		{"failed", pacePostgresQueryFailed, 1},
+		{"deadline exceeded", pacePostgresQueryDeadlineExceeded, 1},
+		{"slow", pacePostgresQuerySlow, 1},
+		{"rows", pacePostgresQueryRowsTotal, 3},
+		{"affected", pacePostgresQueryAffectedTotal, 0},
+	}
+	for _, c := range cases {
+		if v := counterValue(t, c.counter.With(labels)); v != c.expected {
+			t.Errorf("expected %s to be %v, got %v", c.name, c.expected, v)
+		}
+	}
+
+	// counters are reset after the flush
+	flushQueryMetrics()
+	if v := counterValue(t, pacePostgresQueryTotal.With(labels)); v != 2 {
+		t.Errorf("expected total to stay 2, got %v", v)
+	}
+}
+
+func TestStartFlush(t *testing.T) {
+	defer func(interval time.Duration) { cfg.MetricsFlushInterval = interval }(cfg.MetricsFlushInterval)
+	cfg.MetricsFlushInterval = time.Millisecond
+	labels := prometheus.Labels{"database": "flushtest"}
+
+	stop1, stop2 := startFlush("flushtest"), startFlush("flushtest")
+	m := queryMetricsFor("flushtest")
+	stopped := m.stop
+
+	recordQuery("flushtest", queryResult{elapsed: time.Millisecond})
+	time.Sleep(20 * time.Millisecond)
+	if v := counterValue(t, pacePostgresQueryTotal.With(labels)); v != 1 {
+		t.Errorf("expected the background flush to add the query, got %v", v)
+	}
+
+	// the flush runs until all pools of the database are closed
+	stop1()
+	stop1()
+	select {
+	case <-stopped:
+		t.Fatal("expected the flush to run while a pool is open")
+	default:
+	}
+
+	recordQuery("flushtest", queryResult{elapsed: time.Millisecond})
+	cfg.MetricsFlushInterval = time.Hour // remaining counters are flushed by stop
+	stop2()
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("expected the flush to stop after the last pool was closed")
+	}
+	if v := counterValue(t, pacePostgresQueryTotal.With(labels)); v != 2 {
+		t.Errorf("expected the remaining counters to be flushed, got %v", v)
+	}
+}
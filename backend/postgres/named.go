@@ -0,0 +1,131 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+var (
+	namedPoolsMu sync.Mutex
+	namedPools   = make(map[string]*pg.DB)
+)
+
+// ConnectionPoolNamed returns the connection pool for the database with the
+// passed name. The pool is configured using the POSTGRES_<NAME>_* environment
+// variables (e.g. POSTGRES_BILLING_HOST for "billing"), variables that are
+// not set default to the values of the default connection pool (POSTGRES_*).
+// Pools are created once and cached, the metrics of the pool are labeled
+// with the name.
+func ConnectionPoolNamed(name string) *pg.DB {
+	namedPoolsMu.Lock()
+	defer namedPoolsMu.Unlock()
+
+	if db, ok := namedPools[name]; ok {
+		return db
+	}
+
+	mustSetup()
+	c, err := namedConfig(name)
+	if err != nil {
+		log.Fatalf("Failed to parse postgres environment for %q: %v", name, err)
+	}
+
+	opts := options(c)
+	if opts.ApplicationName == "" || opts.ApplicationName == cfg.ApplicationName {
+		opts.ApplicationName = name
+	}
+	db := connectionPool(c, opts)
+	namedPools[name] = db
+
+	return db
+}
+
+// namedConfig returns the config of the default connection pool overwritten
+// by the POSTGRES_<NAME>_* environment variables
+func namedConfig(name string) (*config, error) {
+	c := cfg // copy of the default config
+	prefix := "POSTGRES_" + strings.ToUpper(name) + "_"
+
+	v := reflect.ValueOf(&c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("env")
+		if !strings.HasPrefix(key, "POSTGRES_") {
+			continue
+		}
+		key = prefix + strings.TrimPrefix(key, "POSTGRES_")
+
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		err := setConfigValue(v.Field(i), value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	err := validateConfig(&c, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// a URL of the pool overwrites the values of the default pool
+	if c.URL != cfg.URL {
+		err := applyURL(&c, c.connectionURL())
+		if err != nil {
+			return nil, fmt.Errorf("%sURL: %v", prefix, err)
+		}
+	}
+
+	return &c, nil
+}
+
+func setConfigValue(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(i))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %v", field.Type())
+		}
+		field.Set(reflect.ValueOf(strings.Split(value, ",")))
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+
+	return nil
+}
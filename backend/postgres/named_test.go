@@ -0,0 +1,73 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamedConfig(t *testing.T) {
+	os.Setenv("POSTGRES_BILLING_DB", "billing")                   // nolint: errcheck
+	os.Setenv("POSTGRES_BILLING_POOL_SIZE", "7")                  // nolint: errcheck
+	os.Setenv("POSTGRES_BILLING_READ_TIMEOUT", "3s")              // nolint: errcheck
+	os.Setenv("POSTGRES_BILLING_RETRY_STATEMENT_TIMEOUT", "true") // nolint: errcheck
+	defer func() {
+		for _, key := range []string{"DB", "POOL_SIZE", "READ_TIMEOUT", "RETRY_STATEMENT_TIMEOUT"} {
+			os.Unsetenv("POSTGRES_BILLING_" + key) // nolint: errcheck
+		}
+	}()
+
+	c, err := namedConfig("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Database != "billing" || c.PoolSize != 7 || c.ReadTimeout != 3*time.Second || !c.RetryStatementTimeout {
+		t.Errorf("Expected named config values, got: %+v", c)
+	}
+	if c.Host != cfg.Host || c.WriteTimeout != cfg.WriteTimeout {
+		t.Errorf("Expected unset values to default to the default pool, got: %+v", c)
+	}
+
+	os.Setenv("POSTGRES_BILLING_POOL_SIZE", "many") // nolint: errcheck
+	if _, err := namedConfig("billing"); err == nil {
+		t.Error("Expected error for invalid pool size")
+	}
+}
+
+func TestNamedConfigValidation(t *testing.T) {
+	cases := map[string]string{
+		"SSLMODE":           "verify_full",
+		"TRACING":           "zipkin",
+		"INSERT_CHUNK_SIZE": "0",
+		"PURGE_CHUNK_SIZE":  "-1",
+	}
+
+	for key, value := range cases {
+		t.Run(key, func(t *testing.T) {
+			os.Setenv("POSTGRES_BILLING_"+key, value)    // nolint: errcheck
+			defer os.Unsetenv("POSTGRES_BILLING_" + key) // nolint: errcheck
+
+			_, err := namedConfig("billing")
+			if err == nil {
+				t.Fatalf("Expected error for invalid %s %q", key, value)
+			}
+			if !strings.Contains(err.Error(), "POSTGRES_BILLING_"+key) {
+				t.Errorf("Expected error to name POSTGRES_BILLING_%s, got: %v", key, err)
+			}
+		})
+	}
+}
+
+func TestConnectionPoolNamed(t *testing.T) {
+	db := ConnectionPoolNamed("reporting")
+	if db != ConnectionPoolNamed("reporting") {
+		t.Error("Expected named connection pool to be cached")
+	}
+	if l := databaseLabel(db.Options()); l != "reporting" {
+		t.Errorf("Expected database label %q, got: %q", "reporting", l)
+	}
+}
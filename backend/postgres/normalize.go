@@ -0,0 +1,51 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+type queryNameKey struct{}
+
+// WithQueryName returns a context that names all queries executed with
+// it. The name is used instead of the normalized query as the query label of
+// the pace_postgres_query_statement_duration_seconds metric and as span name.
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+func queryNameFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(queryNameKey{}).(string)
+	return name
+}
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteral  = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	placeholder    = regexp.MustCompile(`\?\d*|\$\d+`)
+	placeholderIn  = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	placeholderRow = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)(?:\s*,\s*\(\s*\?(?:\s*,\s*\?)*\s*\))+`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery returns the fingerprint of the passed query. Literals
+// and placeholders are replaced by "?", IN lists and multi row VALUES
+// are collapsed and whitespace is normalized. Queries that only differ
+// in their parameters have the same fingerprint, this way it can be used
+// as metric label without unbounded cardinality.
+func NormalizeQuery(q string) string {
+	q = stringLiteral.ReplaceAllString(q, "?")
+	q = placeholder.ReplaceAllString(q, "?")
+	q = numberLiteral.ReplaceAllString(q, "?")
+	q = placeholderIn.ReplaceAllString(q, "IN (?)")
+	q = placeholderRow.ReplaceAllString(q, "(...)")
+	q = whitespace.ReplaceAllString(q, " ")
+	return strings.TrimSpace(q)
+}
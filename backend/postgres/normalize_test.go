@@ -0,0 +1,37 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	cases := []struct {
+		query, expected string
+	}{
+		{`SELECT * FROM users WHERE id = 42`, `SELECT * FROM users WHERE id = ?`},
+		{`SELECT * FROM users WHERE name = 'O''Brian' AND age > 2.5`, `SELECT * FROM users WHERE name = ? AND age > ?`},
+		{`SELECT * FROM users WHERE id IN (1, 2, 3)`, `SELECT * FROM users WHERE id IN (?)`},
+		{`SELECT * FROM users WHERE id in ($1,$2)`, `SELECT * FROM users WHERE id IN (?)`},
+		{"INSERT INTO t1 (a, b)\n\tVALUES (1, 'a'), (2, 'b'), (3, 'c')", `INSERT INTO t1 (a, b) VALUES (...)`},
+		{`SELECT ?0 + ?1 AS Calc`, `SELECT ? + ? AS Calc`},
+	}
+
+	for _, c := range cases {
+		if got := NormalizeQuery(c.query); got != c.expected {
+			t.Errorf("Expected %q for %q, got: %q", c.expected, c.query, got)
+		}
+	}
+}
+
+func TestQueryLabel(t *testing.T) {
+	if l := queryLabel(context.Background(), "SELECT 1"); l != "SELECT ?" {
+		t.Errorf("Expected normalized query, got: %q", l)
+	}
+	if l := queryLabel(WithQueryName(context.Background(), "one"), "SELECT 1"); l != "one" {
+		t.Errorf("Expected query name, got: %q", l)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-pg/pg/orm"
+	"github.com/go-pg/pg/types"
+)
+
+// VersionColumn is the column of the version of models
+// updated using UpdateVersioned
+const VersionColumn = "version"
+
+// ErrConcurrentModification is returned by UpdateVersioned and
+// DeleteVersioned if the row was modified (or deleted) since the
+// model was read
+var ErrConcurrentModification = errors.New("postgres: concurrent modification")
+
+// UpdateVersioned updates the model (a pointer to a struct with primary key
+// and integer version column, e.g. a Version int field tagged notnull)
+// only if the version of the row still matches the version of the model.
+// The version is incremented with the update. If the row was modified
+// concurrently, ErrConcurrentModification is returned and the model is left
+// unchanged, the caller usually reloads the row and retries. If columns are
+// passed, only these columns (and the version) are updated. Use WithContext
+// or a transaction as db to execute the update with a context.
+func UpdateVersioned(db orm.DB, model interface{}, columns ...string) error {
+	version, err := versionField(model)
+	if err != nil {
+		return err
+	}
+
+	current := version.Int()
+	version.SetInt(current + 1)
+
+	q := db.Model(model).WherePK().Where("?TableAlias.? = ?", types.F(VersionColumn), current)
+	if len(columns) > 0 {
+		q = q.Column(append(columns, VersionColumn)...)
+	}
+	res, err := q.Update()
+	if err == nil && res.RowsAffected() == 0 {
+		err = ErrConcurrentModification
+	}
+	if err != nil {
+		version.SetInt(current)
+		return err
+	}
+	return nil
+}
+
+// DeleteVersioned deletes the model only if the version of the row still
+// matches the version of the model, ErrConcurrentModification is returned
+// otherwise
+func DeleteVersioned(db orm.DB, model interface{}) error {
+	version, err := versionField(model)
+	if err != nil {
+		return err
+	}
+
+	res, err := db.Model(model).WherePK().
+		Where("?TableAlias.? = ?", types.F(VersionColumn), version.Int()).Delete()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// versionField returns the settable version field of the model
+func versionField(model interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("postgres: versioned model must be a pointer to a struct, got %T", model)
+	}
+	v = v.Elem()
+
+	field, ok := orm.GetTable(v.Type()).FieldsMap[VersionColumn]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("postgres: model %T has no %s column", model, VersionColumn)
+	}
+	version := field.Value(v)
+	switch version.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return version, nil
+	}
+	return reflect.Value{}, fmt.Errorf("postgres: %s column of %T must be an integer, got %s", VersionColumn, model, version.Type())
+}
@@ -0,0 +1,85 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"testing"
+)
+
+type versionedTest struct {
+	tableName struct{} `sql:"versioned_test"` // nolint: structcheck,unused
+
+	ID      int
+	Name    string
+	Version int `sql:",notnull"`
+}
+
+func TestVersionField(t *testing.T) {
+	if _, err := versionField(versionedTest{}); err == nil {
+		t.Error("expected error for model that is not a pointer")
+	}
+	if _, err := versionField(&insertTest{}); err == nil {
+		t.Error("expected error for model without version column")
+	}
+
+	m := &versionedTest{Version: 3}
+	v, err := versionField(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetInt(4)
+	if m.Version != 4 {
+		t.Errorf("expected version field to be settable, got %d", m.Version)
+	}
+}
+
+func TestUpdateVersioned(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec("DROP TABLE IF EXISTS versioned_test; CREATE TABLE versioned_test (id serial PRIMARY KEY, name text, version int NOT NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE versioned_test") // nolint: errcheck
+
+	m := &versionedTest{Name: "a"}
+	if err := db.Insert(m); err != nil {
+		t.Fatal(err)
+	}
+	stale := *m
+
+	m.Name = "b"
+	if err := UpdateVersioned(db, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 1 {
+		t.Errorf("expected version to be bumped to 1, got %d", m.Version)
+	}
+
+	stale.Name = "c"
+	if err := UpdateVersioned(db, &stale, "name"); err != ErrConcurrentModification {
+		t.Errorf("expected ErrConcurrentModification, got %v", err)
+	}
+	if stale.Version != 0 {
+		t.Errorf("expected version of failed update to be unchanged, got %d", stale.Version)
+	}
+	if err := DeleteVersioned(db, &stale); err != ErrConcurrentModification {
+		t.Errorf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	if err := db.Select(&stale); err != nil {
+		t.Fatal(err)
+	}
+	if stale.Name != "b" || stale.Version != 1 {
+		t.Errorf("expected row of first update, got %+v", stale)
+	}
+	if err := DeleteVersioned(db, &stale); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package pgtest helps writing hermetic integration tests against
+// PostgreSQL. Each test gets an isolated database that is created with
+// the migrations applied (or cloned from a template database) and dropped
+// afterwards. Tests that only need isolation from each other use Rollback
+// to run in a transaction that is rolled back.
+//
+// The server is configured using the POSTGRES_* environment variables of
+// the postgres package, the user needs the CREATEDB privilege. Tests are
+// skipped if the server isn't available.
+package pgtest
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/types"
+	"github.com/pace/bricks/backend/postgres"
+)
+
+// Prefix of the names of the created databases
+const Prefix = "bricks_test_"
+
+// Migration prepares the test database, e.g. creates the tables
+type Migration func(db *pg.DB) error
+
+// SQL returns a migration that executes the passed statements
+func SQL(statements ...string) Migration {
+	return func(db *pg.DB) error {
+		for _, stmt := range statements {
+			if _, err := db.Exec(stmt); err != nil {
+				return fmt.Errorf("%v: %s", err, stmt)
+			}
+		}
+		return nil
+	}
+}
+
+var (
+	adminOnce sync.Once
+	admin     *pg.DB
+	adminErr  error
+	counter   int64
+)
+
+// adminDB returns the connection pool of the configured database that
+// is used to create and drop the test databases
+func adminDB(t testing.TB) *pg.DB {
+	t.Helper()
+	adminOnce.Do(func() {
+		if adminErr = postgres.Setup(); adminErr != nil {
+			return
+		}
+		admin = postgres.ConnectionPool()
+		_, adminErr = admin.Exec("SELECT 1")
+	})
+	if adminErr != nil {
+		t.Skipf("PostgreSQL not available: %v", adminErr)
+	}
+	return admin
+}
+
+// databaseName returns a name that is unique for the test binaries
+// running concurrently
+func databaseName() string {
+	return fmt.Sprintf("%s%d_%d", Prefix, os.Getpid(), atomic.AddInt64(&counter, 1))
+}
+
+// connect returns a connection pool of the passed database using the
+// options of the admin pool
+func connect(name string) *pg.DB {
+	opts := *admin.Options()
+	opts.Database = name
+	opts.OnConnect = nil
+	return postgres.CustomConnectionPool(&opts)
+}
+
+// drop closes the pool and drops the database
+func drop(db *pg.DB, name string) error {
+	if db != nil {
+		db.Close() // nolint: errcheck
+	}
+	_, err := admin.Exec("DROP DATABASE IF EXISTS ?", types.F(name))
+	return err
+}
+
+// migrate applies the migrations on the database
+func migrate(db *pg.DB, migrations []Migration) error {
+	for i, m := range migrations {
+		if err := m(db); err != nil {
+			return fmt.Errorf("migration %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// NewDatabase creates an empty database, applies the migrations and
+// returns its connection pool. The returned function closes the pool and
+// drops the database, it needs to be called at the end of the test:
+//
+//	db, teardown := pgtest.NewDatabase(t, pgtest.SQL(postgres.TasksTable))
+//	defer teardown()
+func NewDatabase(t testing.TB, migrations ...Migration) (*pg.DB, func()) {
+	t.Helper()
+	return newDatabase(t, "", migrations)
+}
+
+// newDatabase creates the database, optionally from the template
+func newDatabase(t testing.TB, template string, migrations []Migration) (*pg.DB, func()) {
+	t.Helper()
+	a := adminDB(t)
+
+	name := databaseName()
+	var err error
+	if template == "" {
+		_, err = a.Exec("CREATE DATABASE ?", types.F(name))
+	} else {
+		_, err = a.Exec("CREATE DATABASE ? TEMPLATE ?", types.F(name), types.F(template))
+	}
+	if err != nil {
+		t.Fatalf("Failed to create test database %q: %v", name, err)
+	}
+
+	db := connect(name)
+	if err := migrate(db, migrations); err != nil {
+		drop(db, name) // nolint: errcheck
+		t.Fatalf("Failed to migrate test database %q: %v", name, err)
+	}
+
+	return db, func() {
+		if err := drop(db, name); err != nil {
+			t.Errorf("Failed to drop test database %q: %v", name, err)
+		}
+	}
+}
+
+// Template is a database with the migrations applied that is cloned for
+// each test, so that the migrations run once per test binary instead of
+// once per test. Templates are created on first use and dropped using
+// Close, e.g. in TestMain.
+type Template struct {
+	migrations []Migration
+
+	once sync.Once
+	name string
+	err  error
+}
+
+// NewTemplate returns a template database with the passed migrations
+func NewTemplate(migrations ...Migration) *Template {
+	return &Template{migrations: migrations}
+}
+
+// Database creates a clone of the template and returns its connection pool
+// like NewDatabase
+func (tpl *Template) Database(t testing.TB) (*pg.DB, func()) {
+	t.Helper()
+	a := adminDB(t)
+
+	tpl.once.Do(func() {
+		tpl.name = databaseName()
+		if _, tpl.err = a.Exec("CREATE DATABASE ?", types.F(tpl.name)); tpl.err != nil {
+			return
+		}
+		// the template can only be cloned without open connections
+		db := connect(tpl.name)
+		tpl.err = migrate(db, tpl.migrations)
+		db.Close() // nolint: errcheck
+	})
+	if tpl.err != nil {
+		t.Fatalf("Failed to create template database: %v", tpl.err)
+	}
+
+	return newDatabase(t, tpl.name, nil)
+}
+
+// Close drops the template database if it was created
+func (tpl *Template) Close() error {
+	if tpl.name == "" || admin == nil {
+		return nil
+	}
+	return drop(nil, tpl.name)
+}
+
+// Rollback runs fn in a transaction that is rolled back afterwards, so
+// that tests sharing a database don't see the changes of each other.
+// Code under test that starts transactions itself can't be used with
+// the transaction.
+func Rollback(t testing.TB, db *pg.DB, fn func(tx *pg.Tx)) {
+	t.Helper()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			t.Errorf("Failed to roll back transaction: %v", err)
+		}
+	}()
+	fn(tx)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package pgtest
+
+import (
+	"testing"
+
+	"github.com/go-pg/pg"
+)
+
+var template = NewTemplate(SQL(`CREATE TABLE items (id serial PRIMARY KEY, name text NOT NULL)`))
+
+func TestTemplateRollback(t *testing.T) {
+	defer template.Close() // nolint: errcheck
+
+	db, teardown := template.Database(t)
+	defer teardown()
+
+	Rollback(t, db, func(tx *pg.Tx) {
+		if _, err := tx.Exec(`INSERT INTO items (name) VALUES ('a'), ('b')`); err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		if _, err := tx.QueryOne(pg.Scan(&n), `SELECT count(*) FROM items`); err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("Expected 2 items in the transaction, got: %d", n)
+		}
+	})
+
+	var n int
+	if _, err := db.QueryOne(pg.Scan(&n), `SELECT count(*) FROM items`); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("Expected the insert to be rolled back, got %d items", n)
+	}
+}
+
+func TestNewDatabase(t *testing.T) {
+	db, teardown := NewDatabase(t, SQL(`CREATE TABLE a (id int)`, `CREATE TABLE b (id int)`))
+
+	var n int
+	_, err := db.QueryOne(pg.Scan(&n), `SELECT count(*) FROM information_schema.tables WHERE table_name IN ('a', 'b')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Expected migrated tables, got: %d", n)
+	}
+
+	name := db.Options().Database
+	teardown()
+
+	var exists bool
+	_, err = admin.QueryOne(pg.Scan(&exists), `SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = ?)`, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Errorf("Expected database %q to be dropped", name)
+	}
+}
@@ -3,7 +3,44 @@
 
 package postgres
 
-import "testing"
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	if err := Setup(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestParseConfigMalformed(t *testing.T) {
+	for key, value := range map[string]string{
+		"POSTGRES_PORT":              "not-a-port",
+		"POSTGRES_TRACING":           "unknown",
+		"POSTGRES_URL":               "mysql://db/orders",
+		"POSTGRES_INSERT_CHUNK_SIZE": "0",
+		"POSTGRES_PURGE_CHUNK_SIZE":  "0",
+		"POSTGRES_TENANT_MAX_POOLS":  "0",
+	} {
+		old, ok := os.LookupEnv(key)
+		os.Setenv(key, value) // nolint: errcheck
+		var c config
+		if err := parseConfig(&c); err == nil {
+			t.Errorf("Expected error for %s=%q", key, value)
+		}
+		if ok {
+			os.Setenv(key, old) // nolint: errcheck
+		} else {
+			os.Unsetenv(key) // nolint: errcheck
+		}
+	}
+}
 
 func TestConnectionPool(t *testing.T) {
 	db := ConnectionPool()
@@ -15,3 +52,112 @@ func TestConnectionPool(t *testing.T) {
 	// Note: This test can't actually test the logging correctly
 	// but the code will be accessed
 }
+
+func TestConnectionPoolOptions(t *testing.T) {
+	env := map[string]string{
+		"POSTGRES_HOST":                 "db.local",
+		"POSTGRES_PORT":                 "6432",
+		"POSTGRES_MAX_RETRIES":          "2",
+		"POSTGRES_DIAL_TIMEOUT":         "3s",
+		"POSTGRES_READ_TIMEOUT":         "7s",
+		"POSTGRES_WRITE_TIMEOUT":        "8s",
+		"POSTGRES_POOL_SIZE":            "12",
+		"POSTGRES_MIN_IDLE_CONNECTIONS": "0",
+		"POSTGRES_MAX_CONN_AGE":         "10m",
+		"POSTGRES_POOL_TIMEOUT":         "9s",
+		"POSTGRES_IDLE_TIMEOUT":         "2m",
+	}
+	for key, value := range env {
+		old, ok := os.LookupEnv(key)
+		os.Setenv(key, value) // nolint: errcheck
+		if ok {
+			defer os.Setenv(key, old) // nolint: errcheck
+		} else {
+			defer os.Unsetenv(key) // nolint: errcheck
+		}
+	}
+
+	var c config
+	if err := parseConfig(&c); err != nil {
+		t.Fatal(err)
+	}
+	db := CustomConnectionPool(options(&c))
+	defer db.Close() // nolint: errcheck
+
+	opts := db.Options()
+	if opts.Addr != "db.local:6432" {
+		t.Errorf("Expected addr %q, got: %q", "db.local:6432", opts.Addr)
+	}
+	if opts.MaxRetries != 2 {
+		t.Errorf("Expected max retries 2, got: %d", opts.MaxRetries)
+	}
+	if opts.PoolSize != 12 {
+		t.Errorf("Expected pool size 12, got: %d", opts.PoolSize)
+	}
+	if opts.MinIdleConns != 0 {
+		t.Errorf("Expected min idle conns 0, got: %d", opts.MinIdleConns)
+	}
+	durations := []struct {
+		name             string
+		actual, expected time.Duration
+	}{
+		{"dial timeout", opts.DialTimeout, 3 * time.Second},
+		{"read timeout", opts.ReadTimeout, 7 * time.Second},
+		{"write timeout", opts.WriteTimeout, 8 * time.Second},
+		{"max conn age", opts.MaxConnAge, 10 * time.Minute},
+		{"pool timeout", opts.PoolTimeout, 9 * time.Second},
+		{"idle timeout", opts.IdleTimeout, 2 * time.Minute},
+	}
+	for _, d := range durations {
+		if d.actual != d.expected {
+			t.Errorf("Expected %s %v, got: %v", d.name, d.expected, d.actual)
+		}
+	}
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cdb := WithContext(ctx, db)
+	if cdb.Context() != ctx {
+		t.Error("Expected context to be set on the database")
+	}
+	if opts := cdb.Options(); opts.ReadTimeout > time.Second || opts.WriteTimeout > time.Second {
+		t.Errorf("Expected timeouts to be limited by the deadline, got: %v/%v", opts.ReadTimeout, opts.WriteTimeout)
+	}
+	if opts := db.Options(); opts.ReadTimeout != cfg.ReadTimeout {
+		t.Errorf("Expected original pool to be unchanged, got: %v", opts.ReadTimeout)
+	}
+}
+
+func TestWithContextWithoutDeadline(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	cdb := WithContext(context.Background(), db)
+	if opts := cdb.Options(); opts.ReadTimeout != cfg.ReadTimeout {
+		t.Errorf("Expected read timeout %v, got: %v", cfg.ReadTimeout, opts.ReadTimeout)
+	}
+}
+
+func TestQueryLogLevel(t *testing.T) {
+	threshold := cfg.SlowQueryThreshold
+	defer func() { cfg.SlowQueryThreshold = threshold }()
+
+	cfg.SlowQueryThreshold = 100 * time.Millisecond
+	if l := queryLogLevel(10 * time.Millisecond); l != zerolog.DebugLevel {
+		t.Errorf("Expected fast query to be logged with debug level, got: %v", l)
+	}
+	if l := queryLogLevel(200 * time.Millisecond); l != zerolog.WarnLevel {
+		t.Errorf("Expected slow query to be logged with warn level, got: %v", l)
+	}
+
+	cfg.SlowQueryThreshold = 0
+	if l := queryLogLevel(time.Hour); l != zerolog.DebugLevel {
+		t.Errorf("Expected slow query logging to be disabled, got: %v", l)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/readonly"
+)
+
+// ErrReadOnly is returned for writes while the service is in read-only
+// mode (see maintenance/readonly)
+var ErrReadOnly = errors.New("postgres: writes are rejected in read-only mode")
+
+// IsReadOnly returns true if err was caused by the read-only mode, either
+// rejected by the connection pool or by the database server
+func IsReadOnly(err error) bool {
+	if err == ErrReadOnly {
+		return true
+	}
+	pgErr, ok := err.(pg.Error)
+	return ok && pgErr.Field('C') == "25006" // read_only_sql_transaction
+}
+
+// writeStatements are the statements that modify data or schema
+var writeStatements = map[string]bool{
+	"INSERT":   true,
+	"UPDATE":   true,
+	"DELETE":   true,
+	"MERGE":    true,
+	"TRUNCATE": true,
+	"CREATE":   true,
+	"ALTER":    true,
+	"DROP":     true,
+	"GRANT":    true,
+	"REVOKE":   true,
+	"COMMENT":  true,
+	"REINDEX":  true,
+	"CLUSTER":  true,
+	"VACUUM":   true,
+	"REFRESH":  true,
+}
+
+var (
+	writeCTE = regexp.MustCompile(`(?i)\b(INSERT\s+INTO|UPDATE\s+\S+\s+SET|DELETE\s+FROM)\b`)
+	copyFrom = regexp.MustCompile(`(?i)\bFROM\s+(STDIN|'|PROGRAM)`)
+)
+
+// isWriteQuery returns true if the query modifies data or schema
+func isWriteQuery(query string) bool {
+	switch op := queryOperation(query); op {
+	case "WITH":
+		return writeCTE.MatchString(stringLiteral.ReplaceAllString(query, "?"))
+	case "COPY":
+		return copyFrom.MatchString(query)
+	default:
+		return writeStatements[op]
+	}
+}
+
+// checkWritable returns ErrReadOnly if query is a write
+// and the service is in read-only mode
+func checkWritable(query string) error {
+	if readonly.Enabled() && isWriteQuery(query) {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// readOnlyOnConnect makes new connections of go-pg pools read-only
+// (default_transaction_read_only) while the service is in read-only
+// mode, the database rejects writes of these connections. The connections
+// are recycled when the mode changes (see recyclingDialer).
+func readOnlyOnConnect(next func(*pg.DB) error) func(*pg.DB) error {
+	return func(db *pg.DB) error {
+		if readonly.Enabled() {
+			_, err := db.Exec("SET default_transaction_read_only = on")
+			if err != nil {
+				return err
+			}
+		}
+		if next != nil {
+			return next(db)
+		}
+		return nil
+	}
+}
+
+// errConnRecycled is the error of queries on connections that were
+// recycled after the read-only mode changed
+var errConnRecycled = errors.New("postgres: connection recycled after the read-only mode changed")
+
+var (
+	connsMu sync.Mutex
+	conns   = make(map[*recyclableConn]struct{})
+)
+
+func init() {
+	readonly.OnChange(func(bool) { recycleConns() })
+}
+
+// recyclingDialer returns a dialer that tracks the connections of a go-pg
+// pool, so that they can be recycled after the read-only mode changed.
+// go-pg has no hook to reset a connection when it is checked out, instead
+// the next write of a recycled connection fails with a network error: go-pg
+// removes the connection from the pool and retries the query on a new
+// connection (POSTGRES_MAX_RETRIES, WithRetry).
+func recyclingDialer(opts *pg.Options) func(network, addr string) (net.Conn, error) {
+	next := opts.Dialer
+	if next == nil {
+		dialer := &net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 5 * time.Minute}
+		next = dialer.Dial
+	}
+
+	return func(network, addr string) (net.Conn, error) {
+		conn, err := next(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		c := &recyclableConn{Conn: conn}
+		connsMu.Lock()
+		conns[c] = struct{}{}
+		connsMu.Unlock()
+		return c, nil
+	}
+}
+
+// recycleConns marks all open connections to be recycled
+func recycleConns() {
+	connsMu.Lock()
+	defer connsMu.Unlock()
+	for c := range conns {
+		atomic.StoreInt32(&c.recycle, 1)
+	}
+}
+
+// recyclableConn is closed on the next write once it is recycled
+type recyclableConn struct {
+	net.Conn
+	recycle int32
+}
+
+func (c *recyclableConn) Write(b []byte) (int, error) {
+	if atomic.LoadInt32(&c.recycle) == 1 {
+		c.Close() // nolint: errcheck
+		return 0, &net.OpError{Op: "write", Net: "tcp", Addr: c.RemoteAddr(), Err: errConnRecycled}
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *recyclableConn) Close() error {
+	connsMu.Lock()
+	delete(conns, c)
+	connsMu.Unlock()
+	return c.Conn.Close()
+}
@@ -0,0 +1,85 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/readonly"
+)
+
+func TestIsWriteQuery(t *testing.T) {
+	cases := map[string]bool{
+		"SELECT * FROM articles":                                        false,
+		"select * from articles for update":                             false,
+		"insert into articles (id) values (1)":                          true,
+		"  UPDATE articles SET title = 'a'":                             true,
+		"DELETE FROM articles":                                          true,
+		"CREATE INDEX ON articles (title)":                              true,
+		"WITH a AS (SELECT 1) SELECT * FROM a":                          false,
+		"WITH a AS (SELECT 'insert into x') SELECT * FROM a":            false,
+		"WITH d AS (DELETE FROM articles RETURNING id) SELECT * FROM d": true,
+		"COPY articles (id, title) FROM STDIN":                          true,
+		"COPY (SELECT * FROM articles) TO STDOUT":                       false,
+		"SHOW default_transaction_read_only":                            false,
+	}
+	for query, expected := range cases {
+		if isWriteQuery(query) != expected {
+			t.Errorf("expected isWriteQuery(%q) to be %v", query, expected)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	readonly.Set(true)
+	defer readonly.Set(false)
+
+	if err := checkWritable("SELECT 1"); err != nil {
+		t.Errorf("expected reads to be allowed, got %v", err)
+	}
+	if err := checkWritable("DELETE FROM articles"); !IsReadOnly(err) {
+		t.Errorf("expected writes to be rejected, got %v", err)
+	}
+
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+	if _, err := InsertBatch(context.Background(), db, []*insertTest{{Name: "a"}}, 10); err != ErrReadOnly {
+		t.Errorf("expected batch insert to be rejected, got %v", err)
+	}
+}
+
+func TestRecyclingDialer(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()               // nolint: errcheck
+	go io.Copy(ioutil.Discard, server) // nolint: errcheck
+
+	dial := recyclingDialer(&pg.Options{Dialer: func(network, addr string) (net.Conn, error) {
+		return client, nil
+	}})
+	conn, err := dial("tcp", "localhost:5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("SELECT 1")); err != nil {
+		t.Fatalf("expected write to succeed, got %v", err)
+	}
+
+	readonly.Set(true)
+	defer readonly.Set(false)
+
+	_, err = conn.Write([]byte("SELECT 1"))
+	if opErr, ok := err.(*net.OpError); !ok || opErr.Err != errConnRecycled {
+		t.Fatalf("expected recycled connection error, got %v", err)
+	}
+	connsMu.Lock()
+	defer connsMu.Unlock()
+	if _, ok := conns[conn.(*recyclableConn)]; ok {
+		t.Error("expected recycled connection to be untracked")
+	}
+}
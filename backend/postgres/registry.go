@@ -0,0 +1,66 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrMetricsRegistered is returned by SetRegisterer if the
+// metrics are registered already
+var ErrMetricsRegistered = errors.New("postgres: metrics are registered already")
+
+var (
+	registryMu sync.Mutex
+	registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	collectors []prometheus.Collector
+	registered bool
+)
+
+// SetRegisterer sets the registry of the metrics of the package, the
+// default is the global prometheus registry. The metrics are registered
+// lazily when the first connection pool is created, therefore the
+// registry needs to be set before.
+func SetRegisterer(reg prometheus.Registerer) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if registered {
+		return ErrMetricsRegistered
+	}
+	registerer = reg
+	return nil
+}
+
+// addMetrics adds collectors that are registered with the metrics
+// of the package
+func addMetrics(cs ...prometheus.Collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	collectors = append(collectors, cs...)
+}
+
+// registerMetrics registers the metrics of the package once. Metrics that
+// are registered already (e.g. by another copy of the package) are skipped
+// instead of panicking.
+func registerMetrics() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if registered {
+		return
+	}
+	registered = true
+
+	for _, c := range collectors {
+		err := registerer.Register(c)
+		if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			log.Logger().Warn().Err(err).Msg("PostgreSQL metric registered already, skipping")
+		} else if err != nil {
+			log.Logger().Error().Err(err).Msg("PostgreSQL failed to register metric")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	// restore the package state for the other tests
+	registryMu.Lock()
+	oldRegisterer, oldRegistered := registerer, registered
+	registered = false
+	registryMu.Unlock()
+	defer func() {
+		registryMu.Lock()
+		registerer, registered = oldRegisterer, oldRegistered
+		registryMu.Unlock()
+	}()
+
+	reg := prometheus.NewRegistry()
+	if err := SetRegisterer(reg); err != nil {
+		t.Fatal(err)
+	}
+
+	// a collector with the same name is registered already
+	// (e.g. by the binary embedding bricks), this must not panic
+	err := reg.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pace_postgres_copy_rows_total",
+		Help: "conflicting metric",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerMetrics()
+	registerMetrics() // registers only once
+	assert.Equal(t, ErrMetricsRegistered, SetRegisterer(prometheus.NewRegistry()))
+
+	pacePostgresInsertRowsTotal.With(prometheus.Labels{"database": "registrytest", "table": "t"}).Inc()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]bool)
+	for _, mf := range mfs {
+		names[mf.GetName()] = true
+	}
+	assert.True(t, names["pace_postgres_insert_rows_total"])
+	assert.True(t, names["pace_postgres_copy_rows_total"])
+}
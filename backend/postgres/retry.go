@@ -0,0 +1,192 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/maintenance/readonly"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reasons for retries, used as metric label
+const (
+	retrySerializationFailure = "serialization_failure"
+	retryDeadlock             = "deadlock"
+	retryConnection           = "connection"
+	retryFailover             = "failover"
+	retryTimeout              = "timeout"
+)
+
+var pacePostgresRetryTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("postgres_retry_total"),
+		Help: "Collects stats about the number of retries of transient postgres errors",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	addMetrics(pacePostgresRetryTotal)
+}
+
+// WithRetry executes fn and retries it in case of transient errors
+// (serialization failures, deadlocks, connection resets and failovers)
+// with exponential backoff and jitter. The number of retries and the
+// backoff are configured using POSTGRES_MAX_RETRIES,
+// POSTGRES_MIN_RETRY_BACKOFF and POSTGRES_MAX_RETRY_BACKOFF.
+// Since fn is executed multiple times it needs to be idempotent
+// (e.g. a whole transaction). WithRetry is the only retry layer within fn:
+// pools passed through WithContext(ctx, db) don't retry the queries and
+// Transaction doesn't retry the transaction, so that the retries don't
+// compound. Timeouts of statements that might have been sent to the
+// server are not retried, since the server might have executed them
+// (e.g. the commit of a transaction), see WithIdempotentRetry.
+func WithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withRetry(ctx, false, fn)
+}
+
+// WithIdempotentRetry is like WithRetry, but fn is also retried after
+// timeouts of statements that might have been executed by the server
+// already. Use it only if executing fn multiple times has the same
+// effect as executing it once (e.g. queries or upserts).
+func WithIdempotentRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withRetry(ctx, true, fn)
+}
+
+func withRetry(ctx context.Context, idempotent bool, fn func(ctx context.Context) error) error {
+	if err := Setup(); err != nil {
+		return err
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "PostgreSQL: WithRetry")
+	defer span.Finish()
+	ctx = context.WithValue(ctx, retryScopeKey{}, true)
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn(ctx)
+
+		reason, ok := retryReason(err)
+		if !ok || (reason == retryTimeout && !idempotent) || attempt >= cfg.MaxRetries {
+			break
+		}
+
+		pacePostgresRetryTotal.With(prometheus.Labels{"reason": reason}).Inc()
+		span.LogFields(olog.Int("attempt", attempt+1), olog.String("reason", reason), olog.Error(err))
+		log.Ctx(ctx).Debug().Err(err).Int("attempt", attempt+1).
+			Str("reason", reason).Msg("Retrying postgres operation")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+
+	if err != nil {
+		span.LogFields(olog.Error(err))
+	}
+
+	return err
+}
+
+// retryScopeKey marks contexts of functions retried by WithRetry
+type retryScopeKey struct{}
+
+// retried returns true if ctx belongs to a function retried by WithRetry
+func retried(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(retryScopeKey{}).(bool)
+	return v
+}
+
+// withoutRetries returns a copy of db that doesn't retry the queries
+func withoutRetries(db *pg.DB) *pg.DB {
+	opts := db.Options()
+	if opts.MaxRetries == 0 {
+		return db
+	}
+	// WithTimeout copies the options of the pool
+	c := withTimeout(db, opts.ReadTimeout)
+	c.Options().WriteTimeout = opts.WriteTimeout
+	c.Options().MaxRetries = 0
+	return c
+}
+
+// retryReason returns the reason for retrying the operation that failed
+// with err and true if the error is transient. Timeouts of statements
+// that might have been sent are reported as retryTimeout, they may only be
+// retried if the operation is idempotent. Dial timeouts are connection
+// errors, nothing was sent to the server.
+func retryReason(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	if pgErr, ok := err.(pg.Error); ok {
+		code := pgErr.Field('C')
+		switch {
+		case code == "40001": // serialization_failure
+			return retrySerializationFailure, true
+		case code == "40P01": // deadlock_detected
+			return retryDeadlock, true
+		case strings.HasPrefix(code, "08"): // connection_exception
+			return retryConnection, true
+		case code == "57P01", // admin_shutdown
+			code == "57P02", // crash_shutdown
+			code == "57P03": // cannot_connect_now
+			return retryFailover, true
+		case code == "25006" && !readonly.Enabled(): // read_only_sql_transaction (connected to a replica after failover)
+			return retryFailover, true
+		}
+		return "", false
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return retryConnection, true
+	}
+	switch e := err.(type) {
+	case net.Error:
+		if op, ok := err.(*net.OpError); e.Timeout() && (!ok || op.Op != "dial") {
+			return retryTimeout, true
+		}
+		return retryConnection, true
+	case syscall.Errno:
+		if e == syscall.ECONNRESET || e == syscall.ECONNREFUSED || e == syscall.EPIPE {
+			return retryConnection, true
+		}
+	}
+
+	return "", false
+}
+
+// retryBackoff returns the exponential backoff for the given attempt
+// with jitter (between half and full backoff)
+func retryBackoff(attempt int) time.Duration {
+	min, max := cfg.MinRetryBackoff, cfg.MaxRetryBackoff
+	if min <= 0 {
+		return 0
+	}
+
+	d := min << uint(attempt)
+	if d <= 0 || (max > 0 && d > max) { // d <= 0 in case of overflow
+		d = max
+	}
+
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1)) // nolint: gosec
+}
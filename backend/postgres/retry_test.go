@@ -0,0 +1,175 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+type testPGError struct {
+	code string
+}
+
+func (e *testPGError) Error() string            { return "pg error " + e.code }
+func (e *testPGError) Field(f byte) string      { return map[byte]string{'C': e.code}[f] }
+func (e *testPGError) IntegrityViolation() bool { return false }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryReason(t *testing.T) {
+	cases := []struct {
+		err    error
+		reason string
+		retry  bool
+	}{
+		{nil, "", false},
+		{errors.New("some error"), "", false},
+		{&testPGError{"23505"}, "", false},
+		{&testPGError{"40001"}, retrySerializationFailure, true},
+		{&testPGError{"40P01"}, retryDeadlock, true},
+		{&testPGError{"08006"}, retryConnection, true},
+		{&testPGError{"57P01"}, retryFailover, true},
+		{&testPGError{"25006"}, retryFailover, true},
+		{io.EOF, retryConnection, true},
+		{&net.OpError{Op: "read", Err: timeoutError{}}, retryTimeout, true},
+		{&net.OpError{Op: "dial", Err: timeoutError{}}, retryConnection, true},
+		{&net.OpError{Op: "read", Err: syscall.ECONNRESET}, retryConnection, true},
+	}
+
+	for _, c := range cases {
+		reason, retry := retryReason(c.err)
+		if reason != c.reason || retry != c.retry {
+			t.Errorf("Expected %v to result in (%q, %v), got: (%q, %v)", c.err, c.reason, c.retry, reason, retry)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for attempt := 0; attempt < 100; attempt++ {
+		d := retryBackoff(attempt)
+		if d < cfg.MinRetryBackoff/2 || d > cfg.MaxRetryBackoff {
+			t.Errorf("Expected backoff of attempt %d to be in [%v, %v], got: %v",
+				attempt, cfg.MinRetryBackoff/2, cfg.MaxRetryBackoff, d)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	minBackoff, maxBackoff := cfg.MinRetryBackoff, cfg.MaxRetryBackoff
+	cfg.MinRetryBackoff, cfg.MaxRetryBackoff = time.Millisecond, 2*time.Millisecond
+	defer func() { cfg.MinRetryBackoff, cfg.MaxRetryBackoff = minBackoff, maxBackoff }()
+
+	t.Run("transient error", func(t *testing.T) {
+		calls := 0
+		err := WithRetry(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return &testPGError{"40001"}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 calls, got: %d", calls)
+		}
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		calls := 0
+		err := WithRetry(context.Background(), func(ctx context.Context) error {
+			calls++
+			return &testPGError{"23505"}
+		})
+		if err == nil {
+			t.Error("Expected error")
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got: %d", calls)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		timeout := &net.OpError{Op: "read", Err: timeoutError{}}
+		for idempotent, calls := range map[bool]int{false: 1, true: cfg.MaxRetries + 1} {
+			retry := WithRetry
+			if idempotent {
+				retry = WithIdempotentRetry
+			}
+			n := 0
+			err := retry(context.Background(), func(ctx context.Context) error {
+				n++
+				return timeout
+			})
+			if err != timeout {
+				t.Errorf("Expected %v, got: %v", timeout, err)
+			}
+			if n != calls {
+				t.Errorf("Expected %d calls (idempotent: %v), got: %d", calls, idempotent, n)
+			}
+		}
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		calls := 0
+		err := WithRetry(context.Background(), func(ctx context.Context) error {
+			calls++
+			return io.EOF
+		})
+		if err != io.EOF {
+			t.Errorf("Expected %v, got: %v", io.EOF, err)
+		}
+		if calls != cfg.MaxRetries+1 {
+			t.Errorf("Expected %d calls, got: %d", cfg.MaxRetries+1, calls)
+		}
+	})
+}
+
+func TestWithRetrySingleLayer(t *testing.T) {
+	db := pg.Connect(&pg.Options{MaxRetries: 5, ReadTimeout: time.Second, WriteTimeout: 2 * time.Second})
+	defer db.Close()
+
+	if WithContext(context.Background(), db).Options().MaxRetries != 5 {
+		t.Error("Expected queries outside of WithRetry to be retried by the pool")
+	}
+
+	calls := 0
+	err := WithRetry(context.Background(), func(ctx context.Context) error {
+		calls++
+		opts := WithContext(ctx, db).Options()
+		if opts.MaxRetries != 0 {
+			t.Errorf("Expected queries within WithRetry not to be retried by the pool, got: %d retries", opts.MaxRetries)
+		}
+		if opts.ReadTimeout != time.Second || opts.WriteTimeout != 2*time.Second {
+			t.Errorf("Expected timeouts to be kept, got: %v and %v", opts.ReadTimeout, opts.WriteTimeout)
+		}
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("Expected one call without error, got: %d calls and %v", calls, err)
+	}
+	if db.Options().MaxRetries != 5 {
+		t.Error("Expected the options of the pool to be unchanged")
+	}
+
+	// the retries would exceed the deadline by a multiple
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if WithContext(ctx, db).Options().MaxRetries != 0 {
+		t.Error("Expected queries with deadline not to be retried by the pool")
+	}
+}
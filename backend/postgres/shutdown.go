@@ -0,0 +1,129 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// drainCheckInterval in which the pools are checked for in-flight queries
+var drainCheckInterval = 10 * time.Millisecond
+
+// errPoolClosed is the message of the (internal) go-pg error returned
+// when closing a closed pool
+const errPoolClosed = "pg: database is closed"
+
+// poolCloser drains and closes a connection pool
+type poolCloser func(ctx context.Context) error
+
+var (
+	poolsMu sync.Mutex
+	pools   = make(map[interface{}]poolCloser)
+)
+
+// registerPool registers the pool to be closed by CloseAll, the flush
+// of the query metrics of the pool stops once it is closed
+func registerPool(db *pg.DB) {
+	stopFlush := startFlush(databaseLabel(db.Options()))
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	pools[db] = func(ctx context.Context) error {
+		drain(ctx, db)
+		defer stopFlush()
+		err := db.Close()
+		if err != nil && err.Error() == errPoolClosed {
+			return nil // closed by the service already
+		}
+		return err
+	}
+}
+
+// registerSQLPool registers the database/sql pool to be closed by CloseAll,
+// the flush of the query metrics of the pool stops once it is closed
+func registerSQLPool(db *sql.DB, database string) {
+	stopFlush := startFlush(database)
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	pools[db] = func(ctx context.Context) error {
+		defer stopFlush()
+		// waits for the in-flight queries to finish
+		return db.Close()
+	}
+}
+
+// drain waits until all connections of the pool are idle or ctx is done
+func drain(ctx context.Context, db *pg.DB) {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		stats := db.PoolStats()
+		if stats.TotalConns <= stats.IdleConns {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Ctx(ctx).Warn().Uint32("in_use", stats.TotalConns-stats.IdleConns).
+				Str("database", databaseLabel(db.Options())).
+				Msg("PostgreSQL connection pool closed with in-flight queries")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseAll closes all connection pools created by this package (including
+// the named and database/sql pools). It waits for in-flight queries to finish
+// until ctx is done, then the pools are closed regardless. Call it on
+// shutdown (e.g. SIGTERM) after the http server stopped accepting requests.
+// Returns the first error that occurred closing a pool.
+func CloseAll(ctx context.Context) error {
+	poolsMu.Lock()
+	closers := pools
+	pools = make(map[interface{}]poolCloser)
+	poolsMu.Unlock()
+
+	namedPoolsMu.Lock()
+	namedPools = make(map[string]*pg.DB)
+	namedPoolsMu.Unlock()
+
+	tenantPoolsMu.Lock()
+	tenantPools = make(map[tenantPoolKey]*list.Element)
+	tenantPoolsOrder.Init()
+	tenantPoolsMu.Unlock()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, closer := range closers {
+		wg.Add(1)
+		go func(closer poolCloser) {
+			defer wg.Done()
+			err := closer(ctx)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(closer)
+	}
+	wg.Wait()
+
+	log.Ctx(ctx).Info().Int("pools", len(closers)).Msg("PostgreSQL connection pools closed")
+	return firstErr
+}
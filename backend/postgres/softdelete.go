@@ -0,0 +1,142 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"github.com/go-pg/pg/types"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var pacePostgresPurgedRowsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("postgres_purged_rows_total"),
+		Help: "Collects stats about the number of soft deleted rows purged by Purge",
+	},
+	[]string{"database", "table"},
+)
+
+func init() {
+	addMetrics(pacePostgresPurgedRowsTotal)
+}
+
+// DeletedAtColumn is the column of the soft delete convention, models
+// use it with a `pg:",soft_delete"` tag on a DeletedAt time.Time field
+const DeletedAtColumn = "deleted_at"
+
+// softDeleteTable returns the table of the model (pointer to a struct
+// or slice of structs) if it follows the soft delete convention
+func softDeleteTable(model interface{}) (*orm.Table, error) {
+	typ := reflect.TypeOf(model)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("postgres: soft delete model must be a struct, got %T", model)
+	}
+
+	table := orm.GetTable(typ)
+	field, ok := table.FieldsMap[DeletedAtColumn]
+	if !ok || !strings.Contains(field.Field.Tag.Get("pg"), "soft_delete") {
+		return nil, fmt.Errorf("postgres: model %T has no %s column tagged with soft_delete", model, DeletedAtColumn)
+	}
+	return table, nil
+}
+
+// Unscoped returns a query on the table of the model that includes soft
+// deleted rows. The query has no model, therefore the model needs to be
+// passed to Select and columns are referenced without ?TableAlias, e.g.
+//
+//	err := postgres.Unscoped(db, &articles).Where("author_id = ?", id).Select(&articles)
+//
+// Models without soft delete return a regular query of the model.
+func Unscoped(db orm.DB, model interface{}) *orm.Query {
+	table, err := softDeleteTable(model)
+	if err != nil {
+		return db.Model(model)
+	}
+	return db.Model().TableExpr("? AS ?", table.Name, table.Alias)
+}
+
+// Restore undeletes the soft deleted model, the model is identified by
+// its primary key
+func Restore(db orm.DB, model interface{}) error {
+	table, err := softDeleteTable(model)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("postgres: Restore expects a pointer to a struct, got %T", model)
+	}
+	strct := v.Elem()
+
+	q := Unscoped(db, model).Set("? = NULL", types.F(DeletedAtColumn))
+	for _, pk := range table.PKs {
+		q = q.Where("? = ?", types.F(pk.SQLName), pk.Value(strct).Interface())
+	}
+	res, err := q.Update()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+
+	table.FieldsMap[DeletedAtColumn].Value(strct).Set(reflect.Zero(table.FieldsMap[DeletedAtColumn].Type))
+	return nil
+}
+
+// Purge deletes the rows of the table of the model that were soft deleted
+// more than olderThan ago. The rows are deleted in chunks of
+// POSTGRES_PURGE_CHUNK_SIZE rows, so that large tables are not locked for
+// a long time. The number of purged rows is returned.
+func Purge(ctx context.Context, db *pg.DB, model interface{}, olderThan time.Duration) (int, error) {
+	if err := Setup(); err != nil {
+		return 0, err
+	}
+	table, err := softDeleteTable(model)
+	if err != nil {
+		return 0, err
+	}
+	name := strings.Trim(string(table.Name), `"`)
+	labels := prometheus.Labels{"database": databaseLabel(db.Options()), "table": name}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: PURGE %s", name))
+	defer span.Finish()
+
+	cdb := WithContext(ctx, db)
+	before := time.Now().Add(-olderThan)
+	purged := 0
+	for {
+		res, err := cdb.Exec(`DELETE FROM ? WHERE ctid IN (SELECT ctid FROM ? WHERE ? < ? LIMIT ?)`,
+			table.Name, table.Name, types.F(DeletedAtColumn), before, cfg.PurgeChunkSize)
+		if err != nil {
+			span.LogFields(olog.Int("purged", purged), olog.Error(err))
+			return purged, err
+		}
+
+		n := res.RowsAffected()
+		purged += n
+		pacePostgresPurgedRowsTotal.With(labels).Add(float64(n))
+		if n < cfg.PurgeChunkSize {
+			break
+		}
+	}
+
+	span.LogFields(olog.Int("purged", purged))
+	log.Ctx(ctx).Info().Str("table", name).Int("purged", purged).Msg("PostgreSQL soft deleted rows purged")
+	return purged, nil
+}
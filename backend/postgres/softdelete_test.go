@@ -0,0 +1,103 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type softDeleteTest struct {
+	tableName struct{} `sql:"soft_delete_test"` // nolint: structcheck,unused
+
+	ID        int
+	Name      string
+	DeletedAt time.Time `pg:",soft_delete"`
+}
+
+func TestSoftDeleteTable(t *testing.T) {
+	if _, err := softDeleteTable(&insertTest{}); err == nil {
+		t.Error("expected error for model without soft delete")
+	}
+	if _, err := softDeleteTable(42); err == nil {
+		t.Error("expected error for non struct model")
+	}
+	table, err := softDeleteTable(&[]*softDeleteTest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Trim(string(table.Name), `"`) != "soft_delete_test" {
+		t.Errorf("expected table soft_delete_test, got %s", table.Name)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec("DROP TABLE IF EXISTS soft_delete_test; CREATE TABLE soft_delete_test (id serial PRIMARY KEY, name text, deleted_at timestamptz)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE soft_delete_test") // nolint: errcheck
+
+	a, b := &softDeleteTest{Name: "a"}, &softDeleteTest{Name: "b"}
+	if err := db.Insert(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(a); err != nil {
+		t.Fatal(err)
+	}
+
+	count := func() int {
+		n, err := db.Model((*softDeleteTest)(nil)).Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := count(); n != 1 {
+		t.Errorf("expected soft deleted row to be filtered, got %d rows", n)
+	}
+
+	var all []softDeleteTest
+	if err := Unscoped(db, &all).Order("id").Select(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].DeletedAt.IsZero() {
+		t.Errorf("expected unscoped query to include soft deleted row, got %+v", all)
+	}
+
+	if err := Restore(db, a); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 2 || !a.DeletedAt.IsZero() {
+		t.Errorf("expected restored row, got %d rows", n)
+	}
+
+	// purge only rows deleted before the retention
+	if err := db.Delete(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(b); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("UPDATE soft_delete_test SET deleted_at = now() - interval '2 days' WHERE id = ?", a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := Purge(context.Background(), db, a, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 purged row, got %d", n)
+	}
+}
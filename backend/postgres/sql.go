@@ -0,0 +1,357 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SQLConnectionPool returns a new database/sql connection pool using the
+// passed driver (e.g. "pgx" or "postgres") that is already configured with
+// the correct credentials and instrumented with tracing, logging and metrics.
+// The driver needs to be imported (registered) by the service.
+func SQLConnectionPool(driverName string) (*sql.DB, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	params := url.Values{
+		"sslmode":         []string{cfg.SSLMode},
+		"connect_timeout": []string{strconv.Itoa(int(cfg.DialTimeout.Seconds()))},
+	}
+	if cfg.ApplicationName != "" {
+		params.Set("application_name", cfg.ApplicationName)
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Path:     "/" + cfg.Database,
+		RawQuery: params.Encode(),
+	}
+
+	db, err := CustomSQLConnectionPool(driverName, dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.PoolSize)
+	db.SetMaxIdleConns(cfg.MinIdleConns)
+	db.SetConnMaxLifetime(cfg.MaxConnAge)
+
+	return db, nil
+}
+
+// CustomSQLConnectionPool returns a new database/sql connection pool using
+// the passed driver and data source name that is instrumented with tracing,
+// logging and metrics
+func CustomSQLConnectionPool(driverName, dsn string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close() // nolint: errcheck
+
+	database := driverName
+	if u, err := url.Parse(dsn); err == nil && u.Host != "" {
+		database = u.Host + u.Path
+	}
+
+	log.Logger().Info().Str("driver", driverName).Str("database", database).
+		Msg("PostgreSQL database/sql connection pool created")
+
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	registerMetrics()
+	sqlDB := sql.OpenDB(&sqlConnector{dsn: dsn, driver: drv, database: database, info: connInfoFromDSN(dsn)})
+	registerSQLPool(sqlDB, database)
+	return sqlDB, nil
+}
+
+// sqlConnector creates instrumented connections using the underlying driver
+type sqlConnector struct {
+	dsn      string
+	driver   driver.Driver
+	database string // database label
+	info     connInfo
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	sc := &sqlConn{Conn: conn, database: c.database, info: c.info}
+	if cfg.StatementCacheSize > 0 {
+		sc.stmts = newStmtCache(cfg.StatementCacheSize)
+	}
+	return sc, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sqlConn instruments all queries of the underlying connection
+type sqlConn struct {
+	driver.Conn
+	database string
+	info     connInfo
+	stmts    *stmtCache // nil if disabled
+}
+
+func (c *sqlConn) Close() error {
+	if c.stmts != nil {
+		c.stmts.close()
+	}
+	return c.Conn.Close()
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bt, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bt.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // nolint: staticcheck
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := checkWritable(query); err != nil {
+		return nil, err
+	}
+
+	// queries with arguments are repeated query shapes, use the statement cache
+	if c.stmts != nil && len(args) > 0 {
+		start := time.Now()
+		rows, err := c.queryCached(ctx, commentedStatement(ctx, query), args)
+		c.queryProcessed(ctx, query, start, nil, err)
+		return rows, err
+	}
+
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, commented(ctx, query), args)
+	c.queryProcessed(ctx, query, start, nil, err)
+	return rows, err
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := checkWritable(query); err != nil {
+		return nil, err
+	}
+
+	if c.stmts != nil && len(args) > 0 {
+		start := time.Now()
+		res, err := c.execCached(ctx, commentedStatement(ctx, query), args)
+		c.queryProcessed(ctx, query, start, res, err)
+		return res, err
+	}
+
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := e.ExecContext(ctx, commented(ctx, query), args)
+	c.queryProcessed(ctx, query, start, res, err)
+	return res, err
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.Conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// commented adds the sqlcommenter comment to the query if enabled using
+// POSTGRES_QUERY_COMMENTS
+func commented(ctx context.Context, query string) string {
+	if !cfg.QueryComments {
+		return query
+	}
+	return Comment(ctx, query)
+}
+
+// commentedStatement adds the sqlcommenter comment to the query of a
+// cached statement if enabled using POSTGRES_QUERY_COMMENTS. The statements
+// are reused by other requests, the comment only contains the action and
+// route (see commentStatement), the statements are cached per route.
+func commentedStatement(ctx context.Context, query string) string {
+	if !cfg.QueryComments {
+		return query
+	}
+	return commentStatement(ctx, query)
+}
+
+// queryProcessed logs, traces and collects metrics of the executed query
+// like the adapters of the go-pg connection pool
+func (c *sqlConn) queryProcessed(ctx context.Context, query string, start time.Time, res driver.Result, err error) {
+	if err == driver.ErrSkip {
+		return // query will be executed using a prepared statement
+	}
+	elapsed := time.Since(start)
+
+	// logging
+	le := log.Ctx(ctx).WithLevel(queryLogLevel(elapsed)).
+		Float64("duration", float64(elapsed)/float64(time.Millisecond))
+	fields := []olog.Field{olog.String("query", query)}
+
+	if clientID, userID, ok := oauth2.Identity(ctx); ok {
+		le = le.Str("client_id", clientID)
+		fields = append(fields, olog.String("client_id", clientID))
+		if userID != "" {
+			le = le.Str("user_id", userID)
+			fields = append(fields, olog.String("user_id", userID))
+		}
+	}
+
+	if err != nil {
+		le = le.Err(err)
+		fields = append(fields, olog.Error(err))
+	} else if res != nil {
+		if affected, err := res.RowsAffected(); err == nil {
+			le = le.Int64("affected", affected)
+			fields = append(fields, olog.Int64("affected", affected))
+		}
+	}
+	le.Msg(query)
+
+	// tracing
+	if cfg.Tracing == tracingOTel {
+		finishOTelSpan(otelSpan(ctx, c.info, query, start), time.Now(), err)
+	} else {
+		span, _ := opentracing.StartSpanFromContext(ctx,
+			fmt.Sprintf("PostgreSQL: %s", queryLabel(ctx, query)),
+			opentracing.StartTime(start))
+		span.LogFields(fields...)
+		span.Finish()
+	}
+
+	// metrics
+	r := queryResult{elapsed: elapsed}
+	if err != nil {
+		r.failed = true
+		r.deadlineExceeded = ctx.Err() == context.DeadlineExceeded
+	} else if res != nil {
+		if affected, err := res.RowsAffected(); err == nil {
+			r.affected = int(affected)
+		}
+	}
+	recordQuery(c.database, r)
+	recordTenantQuery(ctx, c.database, elapsed)
+	pacePostgresQueryStatementDurationSeconds.With(prometheus.Labels{
+		"database": c.database,
+		"query":    queryLabel(ctx, query),
+	}).Observe(elapsed.Seconds())
+}
+
+// sqlStmt instruments the execution of prepared statements
+type sqlStmt struct {
+	driver.Stmt
+	conn  *sqlConn
+	query string
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := checkWritable(s.query); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		res, err = s.Stmt.Exec(namedValues(args)) // nolint: staticcheck
+	}
+	s.conn.queryProcessed(ctx, s.query, start, res, err)
+	return res, err
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := checkWritable(s.query); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedValues(args)) // nolint: staticcheck
+	}
+	s.conn.queryProcessed(ctx, s.query, start, nil, err)
+	return rows, err
+}
+
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
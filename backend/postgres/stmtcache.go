@@ -0,0 +1,136 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"container/list"
+	"context"
+	"database/sql/driver"
+
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of statement cache lookups, used as metric label
+const (
+	stmtCacheHit  = "hit"
+	stmtCacheMiss = "miss"
+)
+
+var pacePostgresStatementCacheTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("postgres_statement_cache_total"),
+		Help: "Collects stats about the number of prepared statement cache lookups partitioned by result",
+	},
+	[]string{"database", "result"},
+)
+
+func init() {
+	addMetrics(pacePostgresStatementCacheTotal)
+}
+
+// stmtCache is a LRU cache of the prepared statements of a connection.
+// Connections are not used concurrently, therefore the cache
+// is not synchronized.
+type stmtCache struct {
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type stmtCacheEntry struct {
+	query string
+	stmt  driver.Stmt
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached statement of the query
+func (c *stmtCache) get(query string) (driver.Stmt, bool) {
+	e, ok := c.items[query]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*stmtCacheEntry).stmt, true
+}
+
+// add adds the statement to the cache, the least recently
+// used statement is closed if the cache is full
+func (c *stmtCache) add(query string, stmt driver.Stmt) {
+	c.items[query] = c.ll.PushFront(&stmtCacheEntry{query: query, stmt: stmt})
+	if c.ll.Len() <= c.size {
+		return
+	}
+
+	e := c.ll.Back()
+	entry := c.ll.Remove(e).(*stmtCacheEntry)
+	delete(c.items, entry.query)
+	entry.stmt.Close() // nolint: errcheck
+}
+
+// close closes all cached statements
+func (c *stmtCache) close() {
+	for e := c.ll.Front(); e != nil; e = e.Next() {
+		e.Value.(*stmtCacheEntry).stmt.Close() // nolint: errcheck
+	}
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// cachedStmt returns the cached prepared statement for the query,
+// the statement is prepared if it is not cached yet
+func (c *sqlConn) cachedStmt(ctx context.Context, query string) (driver.Stmt, error) {
+	if stmt, ok := c.stmts.get(query); ok {
+		pacePostgresStatementCacheTotal.With(prometheus.Labels{"database": c.database, "result": stmtCacheHit}).Inc()
+		return stmt, nil
+	}
+	pacePostgresStatementCacheTotal.With(prometheus.Labels{"database": c.database, "result": stmtCacheMiss}).Inc()
+
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.stmts.add(query, stmt)
+	return stmt, nil
+}
+
+// queryCached executes the query using the cached prepared statement
+func (c *sqlConn) queryCached(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stmt, err := c.cachedStmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if q, ok := stmt.(driver.StmtQueryContext); ok {
+		return q.QueryContext(ctx, args)
+	}
+	return stmt.Query(namedValues(args)) // nolint: staticcheck
+}
+
+// execCached executes the query using the cached prepared statement
+func (c *sqlConn) execCached(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	stmt, err := c.cachedStmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := stmt.(driver.StmtExecContext); ok {
+		return e.ExecContext(ctx, args)
+	}
+	return stmt.Exec(namedValues(args)) // nolint: staticcheck
+}
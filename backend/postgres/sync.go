@@ -0,0 +1,235 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+)
+
+// syncSetupSQL creates the shared sequence, tombstone tables and trigger
+// functions as well as the columns, index and triggers of the table
+const syncSetupSQL = `CREATE SEQUENCE IF NOT EXISTS bricks_sync_seq;
+CREATE TABLE IF NOT EXISTS bricks_sync_tombstones (
+	table_name text NOT NULL,
+	id text NOT NULL,
+	sync_txid bigint NOT NULL DEFAULT txid_current(),
+	sync_seq bigint NOT NULL DEFAULT nextval('bricks_sync_seq'),
+	deleted_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (table_name, id)
+);
+CREATE INDEX IF NOT EXISTS bricks_sync_tombstones_position ON bricks_sync_tombstones (table_name, sync_txid, sync_seq);
+CREATE TABLE IF NOT EXISTS bricks_sync_purged (
+	table_name text PRIMARY KEY,
+	sync_txid bigint NOT NULL
+);
+CREATE OR REPLACE FUNCTION bricks_sync_update() RETURNS trigger AS $$
+BEGIN
+	NEW.sync_txid := txid_current();
+	NEW.sync_seq := nextval('bricks_sync_seq');
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE OR REPLACE FUNCTION bricks_sync_delete() RETURNS trigger AS $$
+BEGIN
+	INSERT INTO bricks_sync_tombstones (table_name, id) VALUES (TG_TABLE_NAME, OLD.id::text)
+	ON CONFLICT (table_name, id) DO UPDATE SET sync_txid = txid_current(),
+		sync_seq = nextval('bricks_sync_seq'), deleted_at = now();
+	RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS sync_txid bigint NOT NULL DEFAULT txid_current();
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS sync_seq bigint NOT NULL DEFAULT nextval('bricks_sync_seq');
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (sync_txid, sync_seq);
+DROP TRIGGER IF EXISTS %[3]s ON %[1]s;
+CREATE TRIGGER %[3]s BEFORE UPDATE ON %[1]s FOR EACH ROW EXECUTE PROCEDURE bricks_sync_update();
+DROP TRIGGER IF EXISTS %[4]s ON %[1]s;
+CREATE TRIGGER %[4]s AFTER DELETE ON %[1]s FOR EACH ROW EXECUTE PROCEDURE bricks_sync_delete();
+`
+
+// SyncSetupSQL returns the (idempotent) SQL statements that prepare the
+// table for delta syncs, use it in a migration. The table needs an id
+// column and gets the sync_txid and sync_seq columns that are set to the
+// writing transaction and a shared sequence on every insert and update,
+// deleted rows are recorded as tombstones.
+func SyncSetupSQL(table string) string {
+	name := tableName(table)
+	return fmt.Sprintf(syncSetupSQL, quoteTable(table),
+		quoteIdent(name+"_sync_position"),
+		quoteIdent(name+"_sync_update"),
+		quoteIdent(name+"_sync_delete"))
+}
+
+var (
+	// ErrInvalidSyncPosition is returned for malformed sync positions
+	ErrInvalidSyncPosition = errors.New("invalid sync position")
+	// ErrSyncPositionExpired is returned if tombstones newer than the
+	// sync position were purged, the client needs to sync from scratch
+	ErrSyncPositionExpired = errors.New("sync position expired")
+)
+
+// syncPosition is the position of a change, the transaction that wrote
+// the row and the sequence of the write within the transaction
+type syncPosition struct {
+	txid, seq int64
+}
+
+// parseSyncPosition parses the position ("txid.seq"), the
+// empty position is the initial sync
+func parseSyncPosition(position string) (syncPosition, error) {
+	if position == "" {
+		return syncPosition{}, nil
+	}
+	parts := strings.Split(position, ".")
+	if len(parts) != 2 {
+		return syncPosition{}, ErrInvalidSyncPosition
+	}
+	txid, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || txid < 0 {
+		return syncPosition{}, ErrInvalidSyncPosition
+	}
+	seq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || seq < 0 {
+		return syncPosition{}, ErrInvalidSyncPosition
+	}
+	return syncPosition{txid: txid, seq: seq}, nil
+}
+
+func (p syncPosition) String() string {
+	return strconv.FormatInt(p.txid, 10) + "." + strconv.FormatInt(p.seq, 10)
+}
+
+// Changes of a table since a sync position
+type Changes struct {
+	// Changed contains the ids of created and updated rows
+	Changed []string
+	// Deleted contains the ids of deleted rows
+	Deleted []string
+	// Next is the position to request the next changes with
+	Next string
+	// More is true if there are more changes than returned
+	More bool
+}
+
+// ChangesSince returns the ids of the rows of the table that changed after
+// the passed sync position (the empty position returns all rows), at most
+// limit changes are returned. The table needs to be prepared using
+// SyncSetupSQL. Use runtime.EncodeSyncToken to pass Next to the client.
+//
+// Changes are returned in the order of the writing transactions and only
+// once all transactions that started before are finished, so that changes
+// committed later aren't missed. Long running transactions delay the
+// changes of all transactions that started after them.
+// ErrSyncPositionExpired is returned if tombstones after the position
+// were purged (see PurgeTombstones).
+func ChangesSince(ctx context.Context, db *pg.DB, table string, since string, limit int) (*Changes, error) {
+	pos, err := parseSyncPosition(since)
+	if err != nil {
+		return nil, err
+	}
+
+	if since != "" {
+		var purged []int64
+		_, err = WithContext(ctx, db).Query(&purged, `SELECT sync_txid FROM bricks_sync_purged
+WHERE table_name = ? AND sync_txid >= ?`, tableName(table), pos.txid)
+		if err != nil {
+			return nil, err
+		}
+		if len(purged) > 0 {
+			return nil, ErrSyncPositionExpired
+		}
+	}
+
+	// the horizon is the oldest transaction that is still in progress, all
+	// transactions before it are finished. Changes of the transactions
+	// after it are returned with a later request.
+	var rows []syncChange
+	_, err = WithContext(ctx, db).Query(&rows, `SELECT horizon, id, sync_txid, sync_seq, deleted
+FROM (SELECT txid_snapshot_xmin(txid_current_snapshot()) AS horizon) h
+LEFT JOIN (
+	SELECT id::text AS id, sync_txid, sync_seq, false AS deleted FROM ?0
+	WHERE (sync_txid, sync_seq) > (?1, ?2)
+	UNION ALL
+	SELECT id, sync_txid, sync_seq, true AS deleted FROM bricks_sync_tombstones
+	WHERE table_name = ?3 AND (sync_txid, sync_seq) > (?1, ?2)
+) changes ON changes.sync_txid < h.horizon
+ORDER BY sync_txid, sync_seq LIMIT ?4`, pg.Q(quoteTable(table)), pos.txid, pos.seq, tableName(table), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &Changes{Next: since}
+	if len(rows) > limit {
+		changes.More = true
+		rows = rows[:limit]
+	}
+	for _, row := range rows {
+		if row.ID == "" {
+			continue // no changes, only the horizon
+		}
+		if row.Deleted {
+			changes.Deleted = append(changes.Deleted, row.ID)
+		} else {
+			changes.Changed = append(changes.Changed, row.ID)
+		}
+		pos = syncPosition{txid: row.SyncTxid, seq: row.SyncSeq}
+	}
+	// without more changes the client continues at the horizon
+	if !changes.More && len(rows) > 0 && rows[0].Horizon > pos.txid {
+		pos = syncPosition{txid: rows[0].Horizon}
+	}
+	if pos != (syncPosition{}) {
+		changes.Next = pos.String()
+	}
+
+	return changes, nil
+}
+
+// syncChange is a changed or deleted row
+type syncChange struct {
+	Horizon  int64
+	ID       string
+	SyncTxid int64
+	SyncSeq  int64
+	Deleted  bool
+}
+
+// PurgeTombstones deletes the tombstones of the table that are older than
+// the retention, returns the number of purged tombstones. Clients that
+// synced before the purged deletes get ErrSyncPositionExpired and need
+// to sync from scratch, the retention should exceed the time clients
+// stay offline. Call it periodically, e.g. with WithLock.
+func PurgeTombstones(ctx context.Context, db *pg.DB, table string, retention time.Duration) (int, error) {
+	var purged int
+	_, err := WithContext(ctx, db).QueryOne(pg.Scan(&purged), `WITH purged AS (
+	DELETE FROM bricks_sync_tombstones WHERE table_name = ?0 AND deleted_at < now() - ?1 * interval '1 millisecond'
+	RETURNING sync_txid
+), watermark AS (
+	INSERT INTO bricks_sync_purged (table_name, sync_txid)
+	SELECT ?0, max(sync_txid) FROM purged HAVING count(*) > 0
+	ON CONFLICT (table_name) DO UPDATE SET sync_txid = GREATEST(bricks_sync_purged.sync_txid, EXCLUDED.sync_txid)
+)
+SELECT count(*) FROM purged`, tableName(table), int64(retention/time.Millisecond))
+	return purged, err
+}
+
+// tableName returns the table name without schema
+func tableName(table string) string {
+	return table[strings.LastIndex(table, ".")+1:]
+}
+
+// quoteTable quotes the (schema qualified) table name
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
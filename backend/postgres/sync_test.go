@@ -0,0 +1,133 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncSetupSQL(t *testing.T) {
+	sql := SyncSetupSQL("app.articles")
+	for _, expected := range []string{
+		`ALTER TABLE "app"."articles" ADD COLUMN IF NOT EXISTS sync_seq`,
+		`CREATE TRIGGER "articles_sync_update" BEFORE UPDATE ON "app"."articles"`,
+		`CREATE TRIGGER "articles_sync_delete" AFTER DELETE ON "app"."articles"`,
+	} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected setup to contain %q, got: %s", expected, sql)
+		}
+	}
+}
+
+func TestChangesSince(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	_, err := db.Exec("DROP TABLE IF EXISTS sync_test; CREATE TABLE sync_test (id int PRIMARY KEY, name text)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE sync_test") // nolint: errcheck
+	_, err = db.Exec(SyncSetupSQL("sync_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, err = db.Exec("INSERT INTO sync_test VALUES (1, 'a'), (2, 'b'), (3, 'c')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial, err := ChangesSince(ctx, db, "sync_test", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(initial.Changed, []string{"1", "2"}) || !initial.More {
+		t.Errorf("Expected first page of changes, got: %#v", initial)
+	}
+
+	_, err = db.Exec("UPDATE sync_test SET name = 'x' WHERE id = 1; DELETE FROM sync_test WHERE id = 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta, err := ChangesSince(ctx, db, "sync_test", initial.Next, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(delta.Changed, []string{"3", "1"}) || !reflect.DeepEqual(delta.Deleted, []string{"2"}) || delta.More {
+		t.Errorf("Expected delta with update and delete, got: %#v", delta)
+	}
+
+	// changes of transactions in progress are delayed until they finish
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec("UPDATE sync_test SET name = 'y' WHERE id = 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("UPDATE sync_test SET name = 'z' WHERE id = 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := ChangesSince(ctx, db, "sync_test", delta.Next, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.Changed) != 0 {
+		t.Errorf("Expected changes after a transaction in progress to be delayed, got: %#v", pending)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	committed, err := ChangesSince(ctx, db, "sync_test", pending.Next, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(committed.Changed, []string{"3", "1"}) {
+		t.Errorf("Expected changes in transaction order, got: %#v", committed)
+	}
+
+	// purged tombstones expire older sync positions
+	_, err = db.Exec("DELETE FROM bricks_sync_purged WHERE table_name = 'sync_test'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DELETE FROM bricks_sync_purged WHERE table_name = 'sync_test'") // nolint: errcheck
+	purged, err := PurgeTombstones(ctx, db, "sync_test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged tombstone, got: %d", purged)
+	}
+	if _, err := ChangesSince(ctx, db, "sync_test", initial.Next, 10); err != ErrSyncPositionExpired {
+		t.Errorf("Expected expired sync position, got: %v", err)
+	}
+	if _, err := ChangesSince(ctx, db, "sync_test", committed.Next, 10); err != nil {
+		t.Errorf("Expected sync position after the purge to be valid, got: %v", err)
+	}
+}
+
+func TestParseSyncPosition(t *testing.T) {
+	for _, position := range []string{"0.0", "42.7"} {
+		pos, err := parseSyncPosition(position)
+		if err != nil || pos.String() != position {
+			t.Errorf("Expected %q, got: %q (%v)", position, pos, err)
+		}
+	}
+	for _, position := range []string{"42", "a.b", "-1.0", "1.2.3"} {
+		if _, err := parseSyncPosition(position); err != ErrInvalidSyncPosition {
+			t.Errorf("Expected invalid position for %q, got: %v", position, err)
+		}
+	}
+}
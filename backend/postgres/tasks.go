@@ -0,0 +1,387 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/jobs"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of task executions, used as metric label
+const (
+	taskSuccess = "success"
+	taskRetry   = "retry"
+	taskFailed  = "failed"
+)
+
+// maxTaskRetryBackoff limits the backoff between the attempts of a task
+const maxTaskRetryBackoff = time.Hour
+
+var (
+	pacePostgresTaskTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_task_total"),
+			Help: "Collects stats about the number of executed tasks partitioned by result",
+		},
+		[]string{"queue", "task", "result"},
+	)
+	pacePostgresTaskDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_task_duration_seconds"),
+			Help:    "Collect performance metrics for each executed task",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 60, 300},
+		},
+		[]string{"queue", "task"},
+	)
+	pacePostgresTaskDriftSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_task_drift_seconds"),
+			Help:    "Collect the delay between the scheduled and the actual start of tasks",
+			Buckets: []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+		},
+		[]string{"queue"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresTaskTotal,
+		pacePostgresTaskDurationSeconds,
+		pacePostgresTaskDriftSeconds,
+	)
+}
+
+// TasksTable creates the table of the tasks, services add it to their migrations
+const TasksTable = `CREATE TABLE IF NOT EXISTS bricks_tasks (
+	id bigserial PRIMARY KEY,
+	queue text NOT NULL,
+	name text NOT NULL,
+	payload jsonb,
+	run_at timestamptz NOT NULL,
+	attempts int NOT NULL DEFAULT 0,
+	locked_until timestamptz,
+	locked_by text,
+	last_error text,
+	failed_at timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS bricks_tasks_due ON bricks_tasks (queue, run_at) WHERE failed_at IS NULL`
+
+// Task is a persisted one-off task
+type Task struct {
+	tableName struct{} `sql:"bricks_tasks"` // nolint: structcheck,unused
+
+	ID          int64
+	Queue       string    `sql:",notnull"`
+	Name        string    `sql:",notnull"`
+	Payload     string    `sql:",type:jsonb"`
+	RunAt       time.Time `sql:",notnull"`
+	Attempts    int       `sql:",notnull"`
+	LockedUntil time.Time
+	LockedBy    string
+	LastError   string
+	FailedAt    time.Time
+	CreatedAt   time.Time `sql:"default:now()"`
+}
+
+// Decode unmarshals the JSON payload of the task into v
+func (t *Task) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(t.Payload), v)
+}
+
+// TaskHandler executes a task, returned errors are retried with backoff
+type TaskHandler func(ctx context.Context, task *Task) error
+
+// Tasks schedules one-off tasks that are executed at a given time, e.g.
+// "expire reservation in 15 minutes". The tasks are persisted in the
+// bricks_tasks table (see TasksTable), so that they survive restarts. Tasks
+// are leased by the workers of all instances for POSTGRES_TASK_LEASE, tasks
+// of crashed workers are executed again once the lease expired (at least
+// once). Therefore handlers need to be idempotent.
+type Tasks struct {
+	db       *pg.DB
+	queue    string
+	worker   string
+	mu       sync.RWMutex
+	handlers map[string]TaskHandler
+}
+
+// NewTasks returns the tasks of the passed queue, the queue can be
+// registered at the jobs dashboard (see jobs.RegisterQueue)
+func NewTasks(db *pg.DB, queue string) *Tasks {
+	mustSetup()
+	hostname, _ := os.Hostname() // nolint: errcheck
+	return &Tasks{
+		db:       db,
+		queue:    queue,
+		worker:   hostname + "-" + strconv.Itoa(os.Getpid()),
+		handlers: make(map[string]TaskHandler),
+	}
+}
+
+// Handle sets the handler of the tasks with the passed name
+func (t *Tasks) Handle(name string, fn TaskHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers[name] = fn
+}
+
+func (t *Tasks) handler(name string) TaskHandler {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.handlers[name]
+}
+
+// RunAt schedules the task with the passed name and payload (marshaled
+// as JSON) to be executed at the passed time, the id of the task is
+// returned
+func (t *Tasks) RunAt(ctx context.Context, name string, at time.Time, payload interface{}) (int64, error) {
+	return t.schedule(ctx, WithContext(ctx, t.db), name, at, payload)
+}
+
+// RunAtTx schedules the task like RunAt in the passed transaction, the
+// task is only executed if the transaction is committed
+func (t *Tasks) RunAtTx(tx *pg.Tx, name string, at time.Time, payload interface{}) (int64, error) {
+	return t.schedule(tx.Context(), tx, name, at, payload)
+}
+
+func (t *Tasks) schedule(ctx context.Context, db orm.DB, name string, at time.Time, payload interface{}) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	task := &Task{Queue: t.queue, Name: name, Payload: string(data), RunAt: at}
+	err = db.Insert(task)
+	if err != nil {
+		return 0, err
+	}
+	log.Ctx(ctx).Debug().Str("queue", t.queue).Str("task", name).Int64("id", task.ID).
+		Time("run_at", at).Msg("PostgreSQL task scheduled")
+	return task.ID, nil
+}
+
+// RunAfter schedules the task to be executed after the passed duration
+func (t *Tasks) RunAfter(ctx context.Context, name string, d time.Duration, payload interface{}) (int64, error) {
+	return t.RunAt(ctx, name, time.Now().Add(d), payload)
+}
+
+// Cancel removes the scheduled task with the passed id, returns
+// false if the task doesn't exist (anymore)
+func (t *Tasks) Cancel(ctx context.Context, id int64) (bool, error) {
+	res, err := WithContext(ctx, t.db).Exec(`DELETE FROM bricks_tasks WHERE queue = ? AND id = ?`, t.queue, id)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// Work executes the due tasks until ctx is done, at most
+// POSTGRES_TASK_CONCURRENCY tasks are executed concurrently. Running
+// tasks are awaited before Work returns.
+func (t *Tasks) Work(ctx context.Context) {
+	var (
+		wg      sync.WaitGroup
+		running = make(chan struct{}, cfg.TaskConcurrency)
+	)
+	defer wg.Wait()
+
+	ticker := time.NewTicker(cfg.TaskPollInterval)
+	defer ticker.Stop()
+
+	for {
+		free := cap(running) - len(running)
+		if free > 0 {
+			tasks, err := t.claim(ctx, free)
+			if err != nil && ctx.Err() == nil {
+				log.Ctx(ctx).Warn().Err(err).Str("queue", t.queue).Msg("PostgreSQL failed to claim tasks")
+			}
+			for _, task := range tasks {
+				running <- struct{}{}
+				wg.Add(1)
+				go func(task *Task) {
+					defer func() {
+						<-running
+						wg.Done()
+					}()
+					t.execute(ctx, task)
+				}(task)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim leases up to limit due tasks (including tasks of expired leases)
+func (t *Tasks) claim(ctx context.Context, limit int) ([]*Task, error) {
+	var tasks []*Task
+	_, err := WithContext(ctx, t.db).Query(&tasks, `UPDATE bricks_tasks
+		SET locked_until = now() + ? * interval '1 millisecond', locked_by = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM bricks_tasks
+			WHERE queue = ? AND failed_at IS NULL AND run_at <= now()
+				AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`, int64(cfg.TaskLease/time.Millisecond), t.worker, t.queue, limit)
+	return tasks, err
+}
+
+// execute runs the handler of the task and completes, retries
+// or fails the task depending on the result
+func (t *Tasks) execute(ctx context.Context, task *Task) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("PostgreSQL: Task %s", task.Name))
+	defer span.Finish()
+	span.LogFields(olog.String("queue", t.queue), olog.Int64("id", task.ID), olog.Int("attempt", task.Attempts))
+
+	pacePostgresTaskDriftSeconds.With(prometheus.Labels{"queue": t.queue}).
+		Observe(time.Since(task.RunAt).Seconds())
+
+	// the task must be finished before the lease expires
+	hctx, cancel := context.WithTimeout(ctx, cfg.TaskLease)
+	start := time.Now()
+	err := t.run(hctx, task)
+	cancel()
+	pacePostgresTaskDurationSeconds.With(prometheus.Labels{"queue": t.queue, "task": task.Name}).
+		Observe(time.Since(start).Seconds())
+
+	// the outcome is stored even if the worker is shutting down
+	dctx, dcancel := context.WithTimeout(ctxutil.Detached(ctx), cfg.TaskPollInterval+5*time.Second)
+	defer dcancel()
+	db := WithContext(dctx, t.db)
+
+	logger := log.Ctx(ctx).With().Str("queue", t.queue).Str("task", task.Name).
+		Int64("id", task.ID).Int("attempt", task.Attempts).Logger()
+
+	result := taskSuccess
+	switch {
+	case err == nil:
+		_, err = db.Exec(`DELETE FROM bricks_tasks WHERE id = ? AND locked_by = ?`, task.ID, t.worker)
+		logger.Debug().Msg("PostgreSQL task completed")
+	case task.Attempts >= cfg.TaskMaxAttempts:
+		result = taskFailed
+		span.LogFields(olog.Error(err))
+		logger.Error().Err(err).Msg("PostgreSQL task failed permanently")
+		_, err = db.Exec(`UPDATE bricks_tasks SET failed_at = now(), locked_until = NULL, last_error = ?
+			WHERE id = ? AND locked_by = ?`, err.Error(), task.ID, t.worker)
+	default:
+		result = taskRetry
+		span.LogFields(olog.Error(err))
+		backoff := taskRetryBackoff(task.Attempts)
+		logger.Warn().Err(err).Dur("backoff", backoff).Msg("PostgreSQL task failed, retrying")
+		_, err = db.Exec(`UPDATE bricks_tasks SET run_at = now() + ? * interval '1 millisecond', locked_until = NULL, last_error = ?
+			WHERE id = ? AND locked_by = ?`, int64(backoff/time.Millisecond), err.Error(), task.ID, t.worker)
+	}
+	if err != nil {
+		// the task is executed again once the lease expired
+		logger.Warn().Err(err).Msg("PostgreSQL failed to store task result")
+	}
+
+	pacePostgresTaskTotal.With(prometheus.Labels{"queue": t.queue, "task": task.Name, "result": result}).Inc()
+}
+
+// run calls the handler of the task, panics are returned as errors
+func (t *Tasks) run(ctx context.Context, task *Task) (err error) {
+	fn := t.handler(task.Name)
+	if fn == nil {
+		return fmt.Errorf("no handler for task %q", task.Name)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Ctx(ctx).Error().Str("stack", string(debug.Stack())).Msgf("Panic in task %s: %v", task.Name, r)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, task)
+}
+
+// taskRetryBackoff returns the exponential backoff (based on
+// POSTGRES_TASK_RETRY_BACKOFF) after the passed attempt
+func taskRetryBackoff(attempt int) time.Duration {
+	backoff := cfg.TaskRetryBackoff
+	for i := 1; i < attempt && backoff < maxTaskRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxTaskRetryBackoff {
+		backoff = maxTaskRetryBackoff
+	}
+	return backoff
+}
+
+// Name returns the name of the queue
+func (t *Tasks) Name() string {
+	return t.queue
+}
+
+// Stats returns the number of due, leased and failed tasks
+func (t *Tasks) Stats(ctx context.Context) (jobs.QueueStats, error) {
+	var stats jobs.QueueStats
+	_, err := WithContext(ctx, t.db).QueryOne(&stats, `SELECT
+		count(*) FILTER (WHERE failed_at IS NULL AND run_at <= now()
+			AND (locked_until IS NULL OR locked_until < now())) AS depth,
+		count(*) FILTER (WHERE failed_at IS NULL AND locked_until >= now()) AS in_flight,
+		count(*) FILTER (WHERE failed_at IS NOT NULL) AS failed
+		FROM bricks_tasks WHERE queue = ?`, t.queue)
+	return stats, err
+}
+
+// Failed returns up to limit permanently failed tasks, the most recent first
+func (t *Tasks) Failed(ctx context.Context, limit int) ([]jobs.FailedJob, error) {
+	var tasks []*Task
+	err := WithContext(ctx, t.db).Model(&tasks).
+		Where("queue = ?", t.queue).Where("failed_at IS NOT NULL").
+		Order("failed_at DESC").Limit(limit).Select()
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make([]jobs.FailedJob, len(tasks))
+	for i, task := range tasks {
+		failed[i] = jobs.FailedJob{
+			ID:       strconv.FormatInt(task.ID, 10),
+			Name:     task.Name,
+			Error:    task.LastError,
+			Attempts: task.Attempts,
+			FailedAt: task.FailedAt,
+		}
+	}
+	return failed, nil
+}
+
+// Retry schedules the permanently failed task with the passed id again
+func (t *Tasks) Retry(ctx context.Context, id string) error {
+	res, err := WithContext(ctx, t.db).Exec(`UPDATE bricks_tasks
+		SET failed_at = NULL, attempts = 0, run_at = now(), locked_until = NULL
+		WHERE queue = ? AND id = ? AND failed_at IS NOT NULL`, t.queue, id)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return jobs.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTaskRetryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		4:  80 * time.Second,
+		20: time.Hour,
+	}
+	for attempt, expected := range cases {
+		if backoff := taskRetryBackoff(attempt); backoff != expected {
+			t.Errorf("expected backoff %v after attempt %d, got %v", expected, attempt, backoff)
+		}
+	}
+}
+
+func TestTaskRun(t *testing.T) {
+	tasks := NewTasks(nil, "test")
+	if err := tasks.run(context.Background(), &Task{Name: "unknown"}); err == nil {
+		t.Error("expected error for task without handler")
+	}
+
+	tasks.Handle("panic", func(ctx context.Context, task *Task) error { panic("boom") })
+	if err := tasks.run(context.Background(), &Task{Name: "panic"}); err == nil || err.Error() != "panic: boom" {
+		t.Errorf("expected panic to be returned as error, got %v", err)
+	}
+
+	var payload struct{ ID string }
+	task := &Task{Payload: `{"ID":"42"}`}
+	if err := task.Decode(&payload); err != nil || payload.ID != "42" {
+		t.Errorf("expected decoded payload, got %+v (%v)", payload, err)
+	}
+}
+
+func TestTasks(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+	if _, err := db.Exec(TasksTable); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE bricks_tasks") // nolint: errcheck
+
+	defer func(poll time.Duration, attempts int, backoff time.Duration) {
+		cfg.TaskPollInterval, cfg.TaskMaxAttempts, cfg.TaskRetryBackoff = poll, attempts, backoff
+	}(cfg.TaskPollInterval, cfg.TaskMaxAttempts, cfg.TaskRetryBackoff)
+	cfg.TaskPollInterval, cfg.TaskMaxAttempts, cfg.TaskRetryBackoff = 10*time.Millisecond, 2, time.Millisecond
+
+	tasks := NewTasks(db, "test")
+	done := make(chan string, 10)
+	tasks.Handle("expire", func(ctx context.Context, task *Task) error {
+		var id string
+		if err := task.Decode(&id); err != nil {
+			return err
+		}
+		done <- id
+		return nil
+	})
+	tasks.Handle("fail", func(ctx context.Context, task *Task) error {
+		return errors.New("always fails")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := tasks.RunAt(ctx, "expire", time.Now().Add(-time.Second), "due"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tasks.RunAfter(ctx, "expire", 50*time.Millisecond, "later"); err != nil {
+		t.Fatal(err)
+	}
+	id, err := tasks.RunAfter(ctx, "expire", time.Hour, "cancelled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := tasks.Cancel(ctx, id); !ok || err != nil {
+		t.Errorf("expected task to be cancelled, got %v (%v)", ok, err)
+	}
+	if _, err := tasks.RunAt(ctx, "fail", time.Now(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	go tasks.Work(ctx)
+
+	for _, expected := range []string{"due", "later"} {
+		select {
+		case id := <-done:
+			if id != expected {
+				t.Errorf("expected task %q, got %q", expected, id)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("task %q was not executed", expected)
+		}
+	}
+
+	// the failing task fails permanently after 2 attempts
+	var failed int
+	for i := 0; i < 100 && failed == 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+		stats, err := tasks.Stats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		failed = stats.Failed
+	}
+	if failed != 1 {
+		t.Fatal("expected failing task to fail permanently")
+	}
+	jobs, err := tasks.Failed(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Attempts != 2 || jobs[0].Error != "always fails" {
+		t.Errorf("expected failed task with 2 attempts, got %+v", jobs)
+	}
+	if err := tasks.Retry(ctx, jobs[0].ID); err != nil {
+		t.Error(err)
+	}
+	if err := tasks.Retry(ctx, strconv.Itoa(-1)); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/types"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/pkg/reqcontext"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pacePostgresTenantQueryTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_tenant_query_total"),
+			Help: "Collects stats about the number of postgres queries made per tenant",
+		},
+		[]string{"database", "tenant"},
+	)
+	pacePostgresTenantQueryDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_tenant_query_duration_seconds"),
+			Help:    "Collect performance metrics for each postgres query per tenant",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database", "tenant"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresTenantQueryTotal,
+		pacePostgresTenantQueryDurationSeconds,
+	)
+}
+
+// ErrNoTenant is returned if the context has no (valid) tenant
+var ErrNoTenant = errors.New("postgres: no tenant in context")
+
+// validTenant restricts tenants to characters that are safe in schema names
+var validTenant = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// TenantResolver derives the tenant from a (request) context, e.g. from
+// the authenticated client or a request header
+type TenantResolver func(ctx context.Context) (string, bool)
+
+var (
+	tenantResolverMu sync.RWMutex
+	tenantResolver   TenantResolver
+)
+
+// SetTenantResolver sets the resolver used for contexts without
+// a tenant set using reqcontext.WithTenant
+func SetTenantResolver(resolver TenantResolver) {
+	tenantResolverMu.Lock()
+	defer tenantResolverMu.Unlock()
+	tenantResolver = resolver
+}
+
+// TenantFromContext returns the tenant set using reqcontext.WithTenant
+// or derived by the tenant resolver (see SetTenantResolver). The queries
+// executed using TenantTransaction or TenantPool use the schema of the
+// tenant (POSTGRES_TENANT_SCHEMA_PREFIX followed by the tenant).
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	if tenant, ok := reqcontext.Tenant(ctx); ok && tenant != "" {
+		return tenant, true
+	}
+
+	tenantResolverMu.RLock()
+	resolver := tenantResolver
+	tenantResolverMu.RUnlock()
+	if resolver == nil {
+		return "", false
+	}
+	tenant, ok := resolver(ctx)
+	return tenant, ok && tenant != ""
+}
+
+// TenantSchema returns the schema of the tenant
+func TenantSchema(tenant string) string {
+	mustSetup()
+	return cfg.TenantSchemaPrefix + tenant
+}
+
+// contextTenant returns the tenant of ctx, invalid tenants are rejected
+// since they are used as part of the search_path
+func contextTenant(ctx context.Context) (string, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	if !validTenant.MatchString(tenant) {
+		return "", fmt.Errorf("postgres: invalid tenant %q", tenant)
+	}
+	return tenant, nil
+}
+
+// TenantTransaction executes fn in a transaction (see Transaction) that
+// uses the schema of the tenant of ctx. The search_path is set using SET
+// LOCAL and is therefore only valid in the transaction, the connection is
+// returned to the pool unchanged. ErrNoTenant is returned if ctx has no
+// tenant.
+func TenantTransaction(ctx context.Context, db *pg.DB, fn func(tx *pg.Tx) error) error {
+	tenant, err := contextTenant(ctx)
+	if err != nil {
+		return err
+	}
+	return Transaction(ctx, db, func(tx *pg.Tx) error {
+		_, err := tx.Exec("SET LOCAL search_path TO ?, public", searchPath(tenant))
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// tenantPoolDrainTimeout is the time evicted tenant pools wait
+// for in-flight queries before they are closed
+var tenantPoolDrainTimeout = time.Minute
+
+var (
+	tenantPoolsMu    sync.Mutex
+	tenantPools      = make(map[tenantPoolKey]*list.Element)
+	tenantPoolsOrder = list.New() // front is the most recently used
+)
+
+type tenantPoolKey struct {
+	db     *pg.DB
+	tenant string
+}
+
+type tenantPoolEntry struct {
+	key  tenantPoolKey
+	pool *pg.DB
+}
+
+// TenantPool returns a connection pool for the tenant of ctx whose
+// connections use the schema of the tenant for all queries. The pool is
+// created with the options of db (limited to POSTGRES_TENANT_POOL_SIZE
+// connections) on first use and cached, the returned pool uses ctx (see
+// WithContext). At most POSTGRES_TENANT_MAX_POOLS pools are cached, the
+// least recently used pool is closed once its in-flight queries finished.
+// The returned pool should therefore only be used for the current request.
+// ErrNoTenant is returned if ctx has no tenant.
+func TenantPool(ctx context.Context, db *pg.DB) (*pg.DB, error) {
+	if err := Setup(); err != nil {
+		return nil, err
+	}
+	tenant, err := contextTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantPoolsMu.Lock()
+	defer tenantPoolsMu.Unlock()
+
+	key := tenantPoolKey{db: db, tenant: tenant}
+	if el, ok := tenantPools[key]; ok {
+		tenantPoolsOrder.MoveToFront(el)
+		return WithContext(ctx, el.Value.(*tenantPoolEntry).pool), nil
+	}
+
+	credentialsMu.RLock()
+	opts := *db.Options() // copy
+	credentialsMu.RUnlock()
+	opts.PoolSize = cfg.TenantPoolSize
+	if opts.MinIdleConns > opts.PoolSize {
+		opts.MinIdleConns = opts.PoolSize
+	}
+	opts.OnConnect = searchPathOnConnect(tenant, opts.OnConnect)
+	pool := CustomConnectionPool(&opts)
+	tenantPools[key] = tenantPoolsOrder.PushFront(&tenantPoolEntry{key: key, pool: pool})
+
+	for tenantPoolsOrder.Len() > cfg.TenantMaxPools {
+		el := tenantPoolsOrder.Back()
+		tenantPoolsOrder.Remove(el)
+		entry := el.Value.(*tenantPoolEntry)
+		delete(tenantPools, entry.key)
+		go closeTenantPool(entry.pool)
+	}
+
+	return WithContext(ctx, pool), nil
+}
+
+// closeTenantPool closes an evicted tenant pool after its in-flight
+// queries finished (at most tenantPoolDrainTimeout)
+func closeTenantPool(db *pg.DB) {
+	poolsMu.Lock()
+	closer, ok := pools[db]
+	delete(pools, db)
+	poolsMu.Unlock()
+	if !ok {
+		return // closed by CloseAll
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tenantPoolDrainTimeout)
+	defer cancel()
+	if err := closer(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("database", databaseLabel(db.Options())).
+			Msg("Failed to close evicted tenant pool")
+	}
+}
+
+// searchPathOnConnect sets the search_path of new connections
+// before calling the passed OnConnect hook
+func searchPathOnConnect(tenant string, next func(*pg.DB) error) func(*pg.DB) error {
+	return func(conn *pg.DB) error {
+		_, err := conn.Exec("SET search_path TO ?, public", searchPath(tenant))
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			return next(conn)
+		}
+		return nil
+	}
+}
+
+// searchPath returns the quoted schema of the tenant, the public schema
+// is added to the search_path to keep shared tables and extensions accessible
+func searchPath(tenant string) types.F {
+	return types.F(TenantSchema(tenant))
+}
+
+// recordTenantQuery collects the per tenant metrics of a query
+// executed with a tenant context
+func recordTenantQuery(ctx context.Context, database string, elapsed time.Duration) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return
+	}
+	labels := prometheus.Labels{"database": database, "tenant": tenant}
+	pacePostgresTenantQueryTotal.With(labels).Inc()
+	pacePostgresTenantQueryDurationSeconds.With(labels).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,128 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/pkg/reqcontext"
+)
+
+func TestTenantFromContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TenantFromContext(ctx); ok {
+		t.Error("Expected no tenant")
+	}
+
+	tenant, ok := TenantFromContext(reqcontext.WithTenant(ctx, "acme"))
+	if !ok || tenant != "acme" {
+		t.Errorf("Expected tenant %q, got %q", "acme", tenant)
+	}
+
+	SetTenantResolver(func(ctx context.Context) (string, bool) { return "resolved", true })
+	defer SetTenantResolver(nil)
+	if tenant, _ := TenantFromContext(ctx); tenant != "resolved" {
+		t.Errorf("Expected resolved tenant, got %q", tenant)
+	}
+	if tenant, _ := TenantFromContext(reqcontext.WithTenant(ctx, "acme")); tenant != "acme" {
+		t.Errorf("Expected explicit tenant to be preferred, got %q", tenant)
+	}
+}
+
+func TestContextTenant(t *testing.T) {
+	if _, err := contextTenant(context.Background()); err != ErrNoTenant {
+		t.Errorf("Expected ErrNoTenant, got %v", err)
+	}
+	if _, err := contextTenant(reqcontext.WithTenant(context.Background(), `a"; DROP SCHEMA public; --`)); err == nil {
+		t.Error("Expected invalid tenant to be rejected")
+	}
+	if TenantSchema("acme") != "tenant_acme" {
+		t.Errorf("Expected schema tenant_acme, got %q", TenantSchema("acme"))
+	}
+}
+
+func TestTenantPoolEviction(t *testing.T) {
+	mustSetup()
+	defer func(max int) { cfg.TenantMaxPools = max }(cfg.TenantMaxPools)
+	cfg.TenantMaxPools = 1
+
+	db := CustomConnectionPool(&pg.Options{Addr: "localhost:5432"})
+	defer db.Close() // nolint: errcheck
+
+	if _, err := TenantPool(reqcontext.WithTenant(context.Background(), "first"), db); err != nil {
+		t.Fatal(err)
+	}
+	tenantPoolsMu.Lock()
+	first := tenantPools[tenantPoolKey{db: db, tenant: "first"}].Value.(*tenantPoolEntry).pool
+	tenantPoolsMu.Unlock()
+
+	if _, err := TenantPool(reqcontext.WithTenant(context.Background(), "second"), db); err != nil {
+		t.Fatal(err)
+	}
+
+	tenantPoolsMu.Lock()
+	_, ok := tenantPools[tenantPoolKey{db: db, tenant: "first"}]
+	n := len(tenantPools)
+	tenantPoolsMu.Unlock()
+	if ok || n != 1 {
+		t.Fatalf("Expected only the second tenant pool to be cached, got %d pools", n)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := first.Exec("SELECT 1"); err != nil && err.Error() == errPoolClosed {
+			break // closed on eviction
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected evicted tenant pool to be closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTenant(t *testing.T) {
+	db := ConnectionPool()
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	ctx := reqcontext.WithTenant(context.Background(), "test")
+	_, err := db.Exec("CREATE SCHEMA IF NOT EXISTS tenant_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP SCHEMA tenant_test") // nolint: errcheck
+
+	var schema string
+	err = TenantTransaction(ctx, db, func(tx *pg.Tx) error {
+		_, err := tx.QueryOne(pg.Scan(&schema), "SELECT current_schema()")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema != "tenant_test" {
+		t.Errorf("Expected schema tenant_test in transaction, got %q", schema)
+	}
+
+	pool, err := TenantPool(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close() // nolint: errcheck
+	_, err = pool.QueryOne(pg.Scan(&schema), "SELECT current_schema()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema != "tenant_test" {
+		t.Errorf("Expected schema tenant_test in tenant pool, got %q", schema)
+	}
+
+	if err := TenantTransaction(context.Background(), db, func(*pg.Tx) error { return nil }); err != ErrNoTenant {
+		t.Errorf("Expected ErrNoTenant, got %v", err)
+	}
+}
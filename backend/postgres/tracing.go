@@ -0,0 +1,190 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/oauth2"
+)
+
+// Supported values of POSTGRES_TRACING
+const (
+	tracingOpenTracing = "opentracing"
+	tracingOTel        = "otel"
+)
+
+// connInfo describes the database connection of the spans
+type connInfo struct {
+	database, user, host string
+	port                 int
+}
+
+// connInfoFromOptions returns the connection info of a go-pg pool
+func connInfoFromOptions(opts *pg.Options) connInfo {
+	ci := connInfo{database: opts.Database, user: opts.User}
+	ci.host, ci.port = splitHostPort(opts.Addr)
+	return ci
+}
+
+// connInfoFromDSN returns the connection info of a database/sql pool,
+// unknown parts are empty
+func connInfoFromDSN(dsn string) connInfo {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Host == "" {
+		return connInfo{}
+	}
+	ci := connInfo{database: strings.TrimPrefix(u.Path, "/"), user: u.User.Username()}
+	ci.host, ci.port = splitHostPort(u.Host)
+	return ci
+}
+
+func splitHostPort(addr string) (string, int) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 0
+	}
+	p, _ := strconv.Atoi(port) // nolint: errcheck
+	return host, p
+}
+
+// otelSpan starts a span using the names and attributes of the OpenTelemetry
+// database semantic conventions. The span is created with the opentracing
+// tracer, services that migrate to OpenTelemetry install the OpenTelemetry
+// opentracing bridge as global tracer and receive the spans with the
+// attributes they expect.
+func otelSpan(ctx context.Context, ci connInfo, query string, start time.Time) opentracing.Span {
+	span, _ := opentracing.StartSpanFromContext(ctx, otelSpanName(ctx, ci, query),
+		opentracing.StartTime(start), otelTags(ci, query))
+	return span
+}
+
+// otelSpanName returns "<db.operation> <db.name>", the name of the query
+// (see WithQueryName) is preferred over the operation
+func otelSpanName(ctx context.Context, ci connInfo, query string) string {
+	name := queryNameFromContext(ctx)
+	if name == "" {
+		name = queryOperation(query)
+	}
+	if name == "" {
+		name = "postgresql"
+	}
+	if ci.database != "" {
+		name += " " + ci.database
+	}
+	return name
+}
+
+// otelTags returns the attributes of the OpenTelemetry database
+// semantic conventions
+func otelTags(ci connInfo, query string) opentracing.Tags {
+	tags := opentracing.Tags{
+		string(ext.SpanKind): ext.SpanKindRPCClientEnum,
+		"db.system":          "postgresql",
+		"db.statement":       query,
+	}
+	if op := queryOperation(query); op != "" {
+		tags["db.operation"] = op
+	}
+	if ci.database != "" {
+		tags["db.name"] = ci.database
+	}
+	if ci.user != "" {
+		tags["db.user"] = ci.user
+	}
+	if ci.host != "" {
+		tags["net.peer.name"] = ci.host
+	}
+	if ci.port != 0 {
+		tags["net.peer.port"] = ci.port
+	}
+	return tags
+}
+
+// queryOperation returns the first keyword of the query in upper case
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSuffix(fields[0], ";"))
+}
+
+// finishOTelSpan marks the span as failed (the bridge maps the error tag
+// to the span status) and finishes it at end
+func finishOTelSpan(span opentracing.Span, end time.Time, err error, fields ...olog.Field) {
+	if err != nil {
+		ext.Error.Set(span, true)
+		fields = append(fields, olog.String("event", "exception"),
+			olog.String("exception.message", err.Error()))
+	}
+	span.LogFields(fields...)
+	span.FinishWithOptions(opentracing.FinishOptions{FinishTime: end})
+}
+
+// otelAdapter traces the processed queries of go-pg pools using the
+// OpenTelemetry semantic conventions (POSTGRES_TRACING=otel)
+func otelAdapter(event *queryEvent, opts *pg.Options) {
+	q, qe := event.UnformattedQuery()
+	if qe != nil {
+		// this is only a display issue not a "real" issue
+		q = qe.Error()
+	}
+
+	ctx := event.DB.Context()
+	span := otelSpan(ctx, connInfoFromOptions(opts), q, event.StartTime)
+	span.SetTag("code.function", event.Func)
+	span.SetTag("code.filepath", event.File)
+	span.SetTag("code.lineno", event.Line)
+
+	if clientID, userID, ok := oauth2.Identity(ctx); ok {
+		span.SetTag("client_id", clientID)
+		if userID != "" {
+			span.SetTag("enduser.id", userID)
+		}
+	}
+
+	var fields []olog.Field
+	if event.Attempt > 0 {
+		fields = append(fields, olog.Int("attempt", event.Attempt))
+	}
+	if event.Error == nil {
+		span.SetTag("db.response.affected_rows", event.Result.RowsAffected())
+		span.SetTag("db.response.returned_rows", event.Result.RowsReturned())
+	}
+	if event.plan != "" {
+		fields = append(fields, olog.String("db.plan", event.plan))
+	}
+	finishOTelSpan(span, event.StartTime.Add(event.elapsed), event.Error, fields...)
+}
+
+// tracingAdapter returns the tracing hook of go-pg pools configured
+// using POSTGRES_TRACING
+func tracingAdapter(opts *pg.Options) func(event *queryEvent) {
+	if cfg.Tracing == tracingOTel {
+		return func(event *queryEvent) {
+			otelAdapter(event, opts)
+		}
+	}
+	return openTracingAdapter
+}
+
+// validateTracing returns an error for unknown tracing modes
+func validateTracing(tracing string) error {
+	switch tracing {
+	case tracingOpenTracing, tracingOTel:
+		return nil
+	}
+	return fmt.Errorf("unknown tracing mode %q, expected %q or %q", tracing, tracingOpenTracing, tracingOTel)
+}
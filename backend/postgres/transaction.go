@@ -0,0 +1,154 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of transactions, used as metric label
+const (
+	transactionCommit   = "commit"
+	transactionRollback = "rollback"
+)
+
+var (
+	pacePostgresTransactionTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("postgres_transaction_total"),
+			Help: "Collects stats about the number of postgres transactions partitioned by result",
+		},
+		[]string{"database", "result"},
+	)
+	pacePostgresTransactionDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("postgres_transaction_duration_seconds"),
+			Help:    "Collect performance metrics for each postgres transaction (including retries)",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"database"},
+	)
+)
+
+func init() {
+	addMetrics(
+		pacePostgresTransactionTotal,
+		pacePostgresTransactionDurationSeconds,
+	)
+}
+
+// Transaction executes fn in a transaction. The transaction is committed
+// if fn returns nil, otherwise (or if fn panics) it is rolled back.
+// Transactions that fail because of a serialization failure or deadlock are
+// retried (see WithRetry), therefore fn needs to be idempotent. Within
+// WithRetry the transaction is executed once and retried by WithRetry only.
+// The queries of the transaction use ctx for logging and tracing, the
+// statements are limited to the deadline of ctx (statement_timeout).
+func Transaction(ctx context.Context, db *pg.DB, fn func(tx *pg.Tx) error) error {
+	if err := Setup(); err != nil {
+		return err
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "PostgreSQL: Transaction")
+	defer span.Finish()
+
+	database := databaseLabel(db.Options())
+	start := time.Now()
+
+	var err error
+retry:
+	for attempt := 0; ; attempt++ {
+		err = runTransaction(ctx, WithContext(ctx, db), fn)
+
+		reason, ok := transactionRetryReason(err)
+		if !ok || attempt >= cfg.MaxRetries || retried(ctx) {
+			break
+		}
+
+		pacePostgresRetryTotal.With(prometheus.Labels{"reason": reason}).Inc()
+		span.LogFields(olog.Int("attempt", attempt+1), olog.String("reason", reason), olog.Error(err))
+		log.Ctx(ctx).Debug().Err(err).Int("attempt", attempt+1).
+			Str("reason", reason).Msg("Retrying postgres transaction")
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break retry
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+
+	dur := time.Since(start)
+	pacePostgresTransactionDurationSeconds.With(prometheus.Labels{"database": database}).Observe(dur.Seconds())
+
+	result := transactionCommit
+	if err != nil {
+		result = transactionRollback
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Info().Err(err).Float64("duration", float64(dur)/float64(time.Millisecond)).
+			Msg("PostgreSQL transaction rolled back")
+	} else {
+		log.Ctx(ctx).Debug().Float64("duration", float64(dur)/float64(time.Millisecond)).
+			Msg("PostgreSQL transaction committed")
+	}
+	pacePostgresTransactionTotal.With(prometheus.Labels{"database": database, "result": result}).Inc()
+	span.LogFields(olog.String("result", result))
+
+	return err
+}
+
+// transactionRetryReason returns the reason for retrying the transaction
+// that failed with err. Only transactions that were rolled back by the database
+// are retried, a failed commit because of a connection error might have been
+// executed already.
+func transactionRetryReason(err error) (string, bool) {
+	reason, ok := retryReason(err)
+	if !ok || (reason != retrySerializationFailure && reason != retryDeadlock) {
+		return "", false
+	}
+	return reason, true
+}
+
+// runTransaction executes fn in a single transaction. If ctx has a
+// deadline, the statement_timeout of the transaction is limited to it,
+// so the statements end on the server once the deadline expired.
+func runTransaction(ctx context.Context, db *pg.DB, fn func(tx *pg.Tx) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline) / time.Millisecond
+		if timeout <= 0 {
+			// a zero timeout would disable the timeout
+			timeout = 1
+		}
+		if _, err := tx.Exec("SET LOCAL statement_timeout = ?", int64(timeout)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,61 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-pg/pg"
+)
+
+func TestTransactionRetryReason(t *testing.T) {
+	cases := []struct {
+		err   error
+		retry bool
+	}{
+		{nil, false},
+		{errors.New("some error"), false},
+		{&testPGError{"40001"}, true},
+		{&testPGError{"40P01"}, true},
+		{&testPGError{"08006"}, false},
+		{io.EOF, false},
+	}
+
+	for _, c := range cases {
+		if _, retry := transactionRetryReason(c.err); retry != c.retry {
+			t.Errorf("Expected retry %v for %v, got: %v", c.retry, c.err, retry)
+		}
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	db := ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	expected := errors.New("rollback")
+	err := Transaction(context.Background(), db, func(tx *pg.Tx) error {
+		return expected
+	})
+	if err != expected {
+		t.Errorf("Expected error %v, got: %v", expected, err)
+	}
+
+	var result struct {
+		Calc int
+	}
+	err = Transaction(context.Background(), db, func(tx *pg.Tx) error {
+		_, err := tx.QueryOne(&result, `SELECT ? + ? AS Calc`, 10, 10)
+		return err
+	})
+	if err != nil || result.Calc != 20 {
+		t.Errorf("Expected committed transaction with result 20, got: %v %d", err, result.Calc)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pace/bricks/backend/amqp"
+)
+
+type amqpDriver struct {
+	client    *amqp.Client
+	publisher *amqp.Publisher
+	exchange  string
+	workers   int
+}
+
+// NewAMQPDriver returns a driver publishing to the exchange with the
+// topic as routing key, subscriptions consume the queue named like the
+// topic with the passed number of workers (see amqp.Consumer). Failed
+// messages are requeued once and rejected afterwards, so that the broker
+// routes them to the dead letter exchange of the queue.
+func NewAMQPDriver(client *amqp.Client, exchange string, workers int) Driver {
+	return &amqpDriver{
+		client:    client,
+		publisher: amqp.NewPublisher(client),
+		exchange:  exchange,
+		workers:   workers,
+	}
+}
+
+func (d *amqpDriver) Name() string {
+	return "amqp"
+}
+
+func (d *amqpDriver) Publish(ctx context.Context, msg Message) error {
+	p := amqp.Publishing{
+		Headers:      make(map[string]interface{}, len(msg.Headers)),
+		DeliveryMode: 2,
+		Body:         msg.Body,
+	}
+	for k, v := range msg.Headers {
+		p.Headers[k] = v
+	}
+	return d.publisher.Publish(ctx, d.exchange, msg.Topic, p)
+}
+
+func (d *amqpDriver) Subscribe(ctx context.Context, topic string, h Handler) error {
+	consumer := amqp.NewConsumer(d.client, topic, d.workers)
+	return consumer.Run(ctx, func(ctx context.Context, delivery amqp.Delivery) error {
+		m := Message{
+			ID:      delivery.MessageID,
+			Topic:   topic,
+			Headers: make(map[string]string, len(delivery.Headers)),
+			Body:    delivery.Body,
+		}
+		if m.ID == "" {
+			m.ID = fmt.Sprint(delivery.DeliveryTag)
+		}
+		for k, v := range delivery.Headers {
+			if s, ok := v.(string); ok {
+				m.Headers[k] = s
+			}
+		}
+		return h(ctx, m)
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// headers of dead lettered messages
+const (
+	HeaderDeadLetterTopic    = "x-dead-letter-topic"
+	HeaderDeadLetterID       = "x-dead-letter-id"
+	HeaderDeadLetterError    = "x-dead-letter-error"
+	HeaderDeadLetterAttempts = "x-dead-letter-attempts"
+	HeaderDeadLetterTime     = "x-dead-letter-time"
+)
+
+var (
+	paceQueueDeadLetteredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("queue_dead_lettered_total"),
+			Help: "Collects stats about the number of messages moved to the dead letter topic partitioned by driver and topic",
+		},
+		[]string{"driver", "topic"},
+	)
+	paceQueueReprocessedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("queue_reprocessed_total"),
+			Help: "Collects stats about the number of dead lettered messages moved back to their topic partitioned by driver and topic",
+		},
+		[]string{"driver", "topic"},
+	)
+	paceQueueDeadLetterDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("queue_dead_letter_depth"),
+			Help: "Number of messages in the dead letter topic, partitioned by driver and topic",
+		},
+		[]string{"driver", "topic"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceQueueDeadLetteredTotal)
+	prometheus.MustRegister(paceQueueReprocessedTotal)
+	prometheus.MustRegister(paceQueueDeadLetterDepth)
+}
+
+// ErrUnsupported is returned if the driver doesn't support the operation
+var ErrUnsupported = errors.New("queue: operation not supported by the driver")
+
+// Depther is implemented by drivers that can count the messages of a topic
+type Depther interface {
+	Depth(ctx context.Context, topic string) (int64, error)
+}
+
+// Drainer is implemented by drivers that can remove all messages of a
+// topic, it is used to reprocess dead letter topics
+type Drainer interface {
+	// Drain passes the messages of the topic to h and removes the
+	// handled messages, it returns once the topic is empty
+	Drain(ctx context.Context, topic string, h Handler) (int, error)
+}
+
+// DeadLetterTopic returns the dead letter topic of the topic
+func (q *Queue) DeadLetterTopic(topic string) string {
+	return topic + q.DeadLetterSuffix
+}
+
+// deadLetter publishes the failed message to the dead letter topic, if
+// that fails the error is returned to the driver to deliver it again
+func (q *Queue) deadLetter(ctx context.Context, msg Message, err error, attempts int) error {
+	dead := Message{
+		Topic:   q.DeadLetterTopic(msg.Topic),
+		Key:     msg.Key,
+		Headers: make(map[string]string, len(msg.Headers)+5),
+		Body:    msg.Body,
+	}
+	for k, v := range msg.Headers {
+		dead.Headers[k] = v
+	}
+	dead.Headers[HeaderDeadLetterTopic] = msg.Topic
+	dead.Headers[HeaderDeadLetterID] = msg.ID
+	dead.Headers[HeaderDeadLetterError] = err.Error()
+	dead.Headers[HeaderDeadLetterAttempts] = strconv.Itoa(attempts)
+	dead.Headers[HeaderDeadLetterTime] = time.Now().UTC().Format(time.RFC3339)
+
+	if perr := q.driver.Publish(ctx, dead); perr != nil {
+		log.Ctx(ctx).Warn().Err(perr).Str("topic", msg.Topic).Str("id", msg.ID).
+			Msg("Failed to move queue message to dead letter topic")
+		return err
+	}
+	paceQueueDeadLetteredTotal.With(q.labels(msg.Topic, "")).Inc()
+	log.Ctx(ctx).Info().Str("topic", msg.Topic).Str("id", msg.ID).Str("dead_letter_topic", dead.Topic).
+		Msg("Moved queue message to dead letter topic")
+	return nil
+}
+
+// DeadLetterDepth returns the number of messages in the dead letter topic
+// of the topic and updates pace_queue_dead_letter_depth. The driver needs
+// to implement Depther.
+func (q *Queue) DeadLetterDepth(ctx context.Context, topic string) (int64, error) {
+	d, ok := q.driver.(Depther)
+	if !ok {
+		return 0, ErrUnsupported
+	}
+	depth, err := d.Depth(ctx, q.DeadLetterTopic(topic))
+	if err != nil {
+		return 0, err
+	}
+	paceQueueDeadLetterDepth.With(q.labels(topic, "")).Set(float64(depth))
+	return depth, nil
+}
+
+// MonitorDeadLetters updates the depth of the dead letter topics of the
+// passed topics in the interval until ctx is done
+func (q *Queue) MonitorDeadLetters(ctx context.Context, interval time.Duration, topics ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, topic := range topics {
+			if _, err := q.DeadLetterDepth(ctx, topic); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("topic", topic).Msg("Failed to get dead letter depth")
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reprocess moves the messages of the dead letter topic of the topic back
+// to their original topic, e.g. after the cause of the failures was fixed.
+// If the driver implements Drainer, it returns once the dead letter topic
+// is empty, otherwise it consumes the dead letter topic until ctx is done.
+// It returns the number of moved messages.
+func (q *Queue) Reprocess(ctx context.Context, topic string) (int, error) {
+	var moved int64
+	h := func(ctx context.Context, msg Message) error {
+		err := q.driver.Publish(ctx, reprocessed(topic, msg))
+		if err != nil {
+			return err
+		}
+		atomic.AddInt64(&moved, 1)
+		paceQueueReprocessedTotal.With(q.labels(topic, "")).Inc()
+		return nil
+	}
+
+	deadLetterTopic := q.DeadLetterTopic(topic)
+	if d, ok := q.driver.(Drainer); ok {
+		_, err := d.Drain(ctx, deadLetterTopic, h)
+		q.DeadLetterDepth(ctx, topic) // nolint: errcheck
+		return int(atomic.LoadInt64(&moved)), err
+	}
+
+	err := q.driver.Subscribe(ctx, deadLetterTopic, h)
+	if ctx.Err() != nil {
+		err = nil
+	}
+	return int(atomic.LoadInt64(&moved)), err
+}
+
+// reprocessed returns the message to publish to its original topic
+func reprocessed(topic string, msg Message) Message {
+	m := Message{Topic: topic, Key: msg.Key, Headers: make(map[string]string), Body: msg.Body}
+	if original, ok := msg.Headers[HeaderDeadLetterTopic]; ok {
+		m.Topic = original
+	}
+	for k, v := range msg.Headers {
+		if !strings.HasPrefix(k, "x-dead-letter-") {
+			m.Headers[k] = v
+		}
+	}
+	return m
+}
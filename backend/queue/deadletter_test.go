@@ -0,0 +1,72 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter(t *testing.T) {
+	d := &memoryDriver{handlers: make(map[string]Handler), stored: make(map[string][]Message)}
+	q := New(d)
+	q.Retry = RetryPolicy{MaxAttempts: 2}
+	q.DeadLetterSuffix = ".dead"
+	ctx := context.Background()
+
+	fixed := false
+	assert.NoError(t, q.Subscribe(ctx, "orders", func(ctx context.Context, msg Message) error {
+		if !fixed && string(msg.Body) == "poison" {
+			return errors.New("invalid order")
+		}
+		return nil
+	}))
+
+	for _, body := range []string{"ok", "poison", "poison"} {
+		msg := Message{Topic: "orders", Key: "k", Headers: map[string]string{"content-type": "json"}, Body: []byte(body)}
+		assert.NoError(t, q.Publish(ctx, msg))
+	}
+	assert.Empty(t, d.failed)
+
+	dead := d.stored["orders.dead"]
+	if assert.Len(t, dead, 2) {
+		assert.Equal(t, "orders", dead[0].Headers[HeaderDeadLetterTopic])
+		assert.Equal(t, "poison", dead[0].Headers[HeaderDeadLetterID])
+		assert.Equal(t, "invalid order", dead[0].Headers[HeaderDeadLetterError])
+		assert.Equal(t, "2", dead[0].Headers[HeaderDeadLetterAttempts])
+		assert.Equal(t, "json", dead[0].Headers["content-type"])
+		assert.Equal(t, "k", dead[0].Key)
+	}
+
+	depth, err := q.DeadLetterDepth(ctx, "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), depth)
+
+	fixed = true
+	moved, err := q.Reprocess(ctx, "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, moved)
+	assert.Empty(t, d.stored["orders.dead"])
+}
+
+func TestReprocessed(t *testing.T) {
+	msg := Message{
+		ID:    "1-0",
+		Topic: "orders.dead",
+		Headers: map[string]string{
+			"content-type":        "json",
+			HeaderDeadLetterTopic: "orders",
+			HeaderDeadLetterError: "invalid order",
+		},
+		Body: []byte("{}"),
+	}
+	assert.Equal(t, Message{
+		Topic:   "orders",
+		Headers: map[string]string{"content-type": "json"},
+		Body:    []byte("{}"),
+	}, reprocessed("fallback", msg))
+}
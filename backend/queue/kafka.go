@@ -0,0 +1,66 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pace/bricks/backend/kafka"
+)
+
+// KafkaReaderFunc returns a reader of the topic for a member of the group
+type KafkaReaderFunc func(topic string) (kafka.Reader, error)
+
+type kafkaDriver struct {
+	producer *kafka.Producer
+	reader   KafkaReaderFunc
+	group    string
+}
+
+// NewKafkaDriver returns a driver publishing using the writer, topics are
+// consumed by the passed group using the readers of the reader func (see
+// kafka.Consumer). Kafka can't deliver single messages again, failed
+// messages are handled again (see kafka.Consumer.RetryFailed) and only
+// committed once they were handled or moved to the dead letter topic.
+func NewKafkaDriver(w kafka.Writer, reader KafkaReaderFunc, group string) Driver {
+	return &kafkaDriver{producer: kafka.NewProducer(w), reader: reader, group: group}
+}
+
+func (d *kafkaDriver) Name() string {
+	return "kafka"
+}
+
+func (d *kafkaDriver) Publish(ctx context.Context, msg Message) error {
+	m := kafka.Message{Topic: msg.Topic, Value: msg.Body}
+	if msg.Key != "" {
+		m.Key = []byte(msg.Key)
+	}
+	for k, v := range msg.Headers {
+		m.Headers = append(m.Headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+	return d.producer.Produce(ctx, m)
+}
+
+func (d *kafkaDriver) Subscribe(ctx context.Context, topic string, h Handler) error {
+	r, err := d.reader(topic)
+	if err != nil {
+		return err
+	}
+	consumer := kafka.NewConsumer(r, d.group)
+	consumer.RetryFailed = true
+	return consumer.Run(ctx, func(ctx context.Context, msg kafka.Message) error {
+		m := Message{
+			ID:      fmt.Sprintf("%d:%d", msg.Partition, msg.Offset),
+			Topic:   msg.Topic,
+			Key:     string(msg.Key),
+			Headers: make(map[string]string, len(msg.Headers)),
+			Body:    msg.Value,
+		}
+		for _, header := range msg.Headers {
+			m.Headers[header.Key] = string(header.Value)
+		}
+		return h(ctx, m)
+	})
+}
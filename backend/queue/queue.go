@@ -0,0 +1,332 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package queue provides publishing and subscribing independent of the
+// message broker. The broker is accessed by a driver (redis streams,
+// kafka or amqp), the queue adds retries with backoff, tracing and
+// metrics consistently for all drivers. Messages are delivered at least
+// once, handlers need to be idempotent.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/getkin/kin-openapi/openapi3"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/configcheck"
+	perrors "github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Number of attempts to handle a message before it is
+	// returned to the driver as failed
+	MaxAttempts int `env:"QUEUE_MAX_ATTEMPTS" envDefault:"3"`
+	// Backoff of the first retry, doubled with every attempt
+	MinRetryBackoff time.Duration `env:"QUEUE_MIN_RETRY_BACKOFF" envDefault:"100ms"`
+	MaxRetryBackoff time.Duration `env:"QUEUE_MAX_RETRY_BACKOFF" envDefault:"5s"`
+	// Suffix of the dead letter topics, empty disables dead lettering
+	DeadLetterSuffix string `env:"QUEUE_DEAD_LETTER_SUFFIX"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the QUEUE_* environment of the queues, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse queue environment: %v", err)
+	}
+}
+
+// results of publishings and handled messages, used as metric label
+const (
+	resultOK      = "ok"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+)
+
+var (
+	paceQueuePublishedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("queue_published_total"),
+			Help: "Collects stats about the number of published messages partitioned by driver, topic and result",
+		},
+		[]string{"driver", "topic", "result"},
+	)
+	paceQueueHandledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("queue_handled_total"),
+			Help: "Collects stats about the number of handled messages partitioned by driver, topic and result",
+		},
+		[]string{"driver", "topic", "result"},
+	)
+	paceQueueRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("queue_retries_total"),
+			Help: "Collects stats about the number of retried handler calls partitioned by driver and topic",
+		},
+		[]string{"driver", "topic"},
+	)
+	paceQueueHandleDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("queue_handle_duration_seconds"),
+			Help:    "Collect performance metrics for the handler calls",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"driver", "topic"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceQueuePublishedTotal)
+	prometheus.MustRegister(paceQueueHandledTotal)
+	prometheus.MustRegister(paceQueueRetriesTotal)
+	prometheus.MustRegister(paceQueueHandleDurationSeconds)
+	configcheck.Register("queue", Setup)
+}
+
+// errPanic is returned for handlers that panicked
+var errPanic = errors.New("queue: handler panicked")
+
+// Message is published to a topic
+type Message struct {
+	// ID is assigned by the driver, it is only set for received messages
+	ID    string
+	Topic string
+	// Key is the partition key of kafka, it is ignored by the other drivers
+	Key     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Handler handles a received message. If an error is returned
+// the message is delivered again.
+type Handler func(ctx context.Context, msg Message) error
+
+// Publisher publishes messages
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Subscriber passes the messages of the topic to the handler until
+// ctx is done. Messages for which the handler returns an error
+// are delivered again.
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic string, h Handler) error
+}
+
+// Driver implements publishing and subscribing for a broker
+type Driver interface {
+	Publisher
+	Subscriber
+	// Name of the driver, used as metric label
+	Name() string
+}
+
+// RetryPolicy of the handlers
+type RetryPolicy struct {
+	// MaxAttempts to handle a message before it is returned to
+	// the driver as failed, 1 disables retries
+	MaxAttempts int
+	// MinBackoff of the first retry, doubled with every attempt
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// backoff returns the exponential backoff with full jitter of the attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff << uint(attempt)
+	if backoff <= 0 || backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff))) // nolint: gosec
+}
+
+// Queue publishes and subscribes using a driver
+type Queue struct {
+	// Retry policy of the handlers, defaults to the environment
+	Retry RetryPolicy
+	// DeadLetterSuffix is appended to the topic of messages that failed
+	// all attempts to get the dead letter topic they are moved to. If
+	// empty, failed messages are returned to the driver. Defaults to
+	// QUEUE_DEAD_LETTER_SUFFIX.
+	DeadLetterSuffix string
+
+	driver Driver
+
+	mu      sync.RWMutex
+	schemas map[string]*openapi3.Schema
+}
+
+// New returns a queue using the passed driver
+func New(d Driver) *Queue {
+	mustSetup()
+	return &Queue{
+		Retry: RetryPolicy{
+			MaxAttempts: cfg.MaxAttempts,
+			MinBackoff:  cfg.MinRetryBackoff,
+			MaxBackoff:  cfg.MaxRetryBackoff,
+		},
+		DeadLetterSuffix: cfg.DeadLetterSuffix,
+		driver:           d,
+	}
+}
+
+// Publish publishes the message, the span context is
+// propagated to the subscribers using the headers. Messages that don't
+// match the schema of the topic are not published, an
+// InvalidMessageError is returned instead.
+func (q *Queue) Publish(ctx context.Context, msg Message) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Queue: publish %s", msg.Topic))
+	defer span.Finish()
+	ext.SpanKindProducer.Set(span)
+	ext.MessageBusDestination.Set(span, msg.Topic)
+	span.SetTag("driver", q.driver.Name())
+
+	if err := q.validate(msg, opPublish); err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		paceQueuePublishedTotal.With(q.labels(msg.Topic, resultFailed)).Inc()
+		return err
+	}
+
+	headers := make(map[string]string, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+	err := span.Tracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(msg.Headers))
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Failed to inject span context into queue message")
+	}
+
+	err = q.driver.Publish(ctx, msg)
+	result := resultOK
+	if err != nil {
+		result = resultFailed
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+	}
+	paceQueuePublishedTotal.With(q.labels(msg.Topic, result)).Inc()
+	return err
+}
+
+// Subscribe passes the messages of the topic to h until ctx is done.
+// Failed handler calls (errors and panics) are retried according to the
+// retry policy, if all attempts failed the message is moved to the dead
+// letter topic (see DeadLetterSuffix) or returned to the driver which
+// delivers it again or moves it to its dead letter queue. Messages that
+// don't match the schema of the topic are not passed to h and not
+// retried, they are moved to the dead letter topic or, without dead
+// letter suffix, acknowledged and dropped, as they would be delivered
+// again forever.
+func (q *Queue) Subscribe(ctx context.Context, topic string, h Handler) error {
+	return q.driver.Subscribe(ctx, topic, func(ctx context.Context, msg Message) error {
+		return q.handle(ctx, h, msg)
+	})
+}
+
+func (q *Queue) handle(ctx context.Context, h Handler, msg Message) error {
+	var opts []opentracing.StartSpanOption
+	sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, opentracing.TextMapCarrier(msg.Headers))
+	if err == nil {
+		opts = append(opts, opentracing.FollowsFrom(sc))
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Queue: handle %s", msg.Topic), opts...)
+	defer span.Finish()
+	ext.SpanKindConsumer.Set(span)
+	ext.MessageBusDestination.Set(span, msg.Topic)
+	span.SetTag("driver", q.driver.Name())
+	span.LogFields(olog.String("id", msg.ID))
+
+	// invalid messages are not passed to the handler
+	err = q.validate(msg, opConsume)
+	valid, attempts := err == nil, 0
+	if !valid && q.DeadLetterSuffix == "" {
+		paceQueueHandledTotal.With(q.labels(msg.Topic, resultDropped)).Inc()
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("topic", msg.Topic).Str("id", msg.ID).
+			Str("driver", q.driver.Name()).Msg("Dropped invalid queue message")
+		return nil
+	}
+	for attempt := 0; valid; attempt++ {
+		attempts++
+		start := time.Now()
+		err = call(ctx, h, msg)
+		paceQueueHandleDurationSeconds.With(q.labels(msg.Topic, "")).Observe(time.Since(start).Seconds())
+		if err == nil || attempt+1 >= q.Retry.MaxAttempts {
+			break
+		}
+
+		paceQueueRetriesTotal.With(q.labels(msg.Topic, "")).Inc()
+		span.LogFields(olog.Int("attempt", attempt+1), olog.Error(err))
+		log.Ctx(ctx).Debug().Err(err).Int("attempt", attempt+1).Str("topic", msg.Topic).
+			Str("id", msg.ID).Msg("Retrying queue message")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(q.Retry.backoff(attempt)):
+		}
+	}
+
+	if err != nil {
+		paceQueueHandledTotal.With(q.labels(msg.Topic, resultFailed)).Inc()
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("topic", msg.Topic).Str("id", msg.ID).
+			Str("driver", q.driver.Name()).Msg("Failed to handle queue message")
+		if q.DeadLetterSuffix != "" {
+			return q.deadLetter(ctx, msg, err, attempts)
+		}
+		return err
+	}
+	paceQueueHandledTotal.With(q.labels(msg.Topic, resultOK)).Inc()
+	return nil
+}
+
+// call calls the handler, panics are recovered and returned as errPanic
+func call(ctx context.Context, h Handler, msg Message) (err error) {
+	err = errPanic
+	defer perrors.HandleWithCtx(ctx, "queue handler "+msg.Topic)
+	err = h(ctx, msg)
+	return err
+}
+
+func (q *Queue) labels(topic, result string) prometheus.Labels {
+	labels := prometheus.Labels{"driver": q.driver.Name(), "topic": topic}
+	if result != "" {
+		labels["result"] = result
+	}
+	return labels
+}
+
+var (
+	_ Publisher  = (*Queue)(nil)
+	_ Subscriber = (*Queue)(nil)
+)
@@ -0,0 +1,128 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryDriver delivers the published messages synchronously to the
+// handler of the topic, messages of topics without handler are stored
+// if stored is set
+type memoryDriver struct {
+	handlers map[string]Handler
+	stored   map[string][]Message
+	failed   []string
+}
+
+func (d *memoryDriver) Name() string { return "memory" }
+
+func (d *memoryDriver) Publish(ctx context.Context, msg Message) error {
+	h, ok := d.handlers[msg.Topic]
+	if !ok && d.stored != nil {
+		d.stored[msg.Topic] = append(d.stored[msg.Topic], msg)
+		return nil
+	}
+	if !ok {
+		return errors.New("unknown topic")
+	}
+	msg.ID = string(msg.Body)
+	if err := h(ctx, msg); err != nil {
+		d.failed = append(d.failed, msg.ID)
+	}
+	return nil
+}
+
+func (d *memoryDriver) Subscribe(ctx context.Context, topic string, h Handler) error {
+	d.handlers[topic] = h
+	return nil
+}
+
+func (d *memoryDriver) Depth(ctx context.Context, topic string) (int64, error) {
+	return int64(len(d.stored[topic])), nil
+}
+
+func (d *memoryDriver) Drain(ctx context.Context, topic string, h Handler) (int, error) {
+	drained := 0
+	for len(d.stored[topic]) > 0 {
+		if err := h(ctx, d.stored[topic][0]); err != nil {
+			return drained, err
+		}
+		d.stored[topic] = d.stored[topic][1:]
+		drained++
+	}
+	return drained, nil
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestQueue(t *testing.T) {
+	d := &memoryDriver{handlers: make(map[string]Handler)}
+	q := New(d)
+	q.Retry = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	ctx := context.Background()
+
+	labels := prometheus.Labels{"driver": "memory", "topic": "orders"}
+	retries := counterValue(t, paceQueueRetriesTotal.With(labels))
+
+	attempts := make(map[string]int)
+	assert.NoError(t, q.Subscribe(ctx, "orders", func(ctx context.Context, msg Message) error {
+		attempts[msg.ID]++
+		assert.Equal(t, "json", msg.Headers["content-type"])
+		switch msg.ID {
+		case "flaky":
+			if attempts[msg.ID] < 2 {
+				return errors.New("temporary")
+			}
+		case "fail":
+			return errors.New("permanent")
+		case "panic":
+			panic("panic")
+		}
+		return nil
+	}))
+
+	headers := map[string]string{"content-type": "json"}
+	for _, body := range []string{"ok", "flaky", "fail", "panic"} {
+		assert.NoError(t, q.Publish(ctx, Message{Topic: "orders", Headers: headers, Body: []byte(body)}))
+	}
+	assert.Error(t, q.Publish(ctx, Message{Topic: "unknown"}))
+
+	assert.Equal(t, map[string]int{"ok": 1, "flaky": 2, "fail": 3, "panic": 3}, attempts)
+	assert.Equal(t, []string{"fail", "panic"}, d.failed)
+	assert.Equal(t, retries+5, counterValue(t, paceQueueRetriesTotal.With(labels)))
+	assert.Equal(t, map[string]string{"content-type": "json"}, headers)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt := 0; attempt < 100; attempt++ {
+		assert.True(t, p.backoff(attempt) < 50*time.Millisecond)
+	}
+	assert.Equal(t, time.Duration(0), RetryPolicy{}.backoff(3))
+}
+
+func TestRedisValues(t *testing.T) {
+	msg := Message{Topic: "orders", Headers: map[string]string{"uber-trace-id": "1"}, Body: []byte("{}")}
+	values := redisValues(msg)
+	assert.Equal(t, map[string]interface{}{"body": "{}", "header:uber-trace-id": "1"}, values)
+
+	received := redisMessage("orders", goredis.XMessage{ID: "1-0", Values: values})
+	msg.ID = "1-0"
+	assert.Equal(t, msg, received)
+}
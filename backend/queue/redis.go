@@ -0,0 +1,104 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	goredis "github.com/go-redis/redis"
+	"github.com/pace/bricks/backend/redis"
+)
+
+// fields of the stream messages
+const (
+	redisBodyField   = "body"
+	redisHeaderField = "header:"
+)
+
+type redisDriver struct {
+	client *goredis.Client
+	group  string
+}
+
+// NewRedisDriver returns a driver using redis streams, the topics are
+// streams that are consumed by the passed consumer group (see
+// redis.ConsumerGroup). Failed messages are claimed again and moved to
+// the dead letter stream after the maximum attempts of the group. The
+// driver implements Depther and Drainer.
+func NewRedisDriver(client *goredis.Client, group string) Driver {
+	return &redisDriver{client: client, group: group}
+}
+
+func (d *redisDriver) Name() string {
+	return "redis"
+}
+
+func (d *redisDriver) Publish(ctx context.Context, msg Message) error {
+	_, err := redis.AddToStream(ctx, d.client, msg.Topic, redisValues(msg))
+	return err
+}
+
+func (d *redisDriver) Subscribe(ctx context.Context, topic string, h Handler) error {
+	group := redis.NewConsumerGroup(d.client, topic, d.group)
+	return group.Run(ctx, func(ctx context.Context, msg goredis.XMessage) error {
+		return h(ctx, redisMessage(topic, msg))
+	})
+}
+
+// Depth returns the length of the stream
+func (d *redisDriver) Depth(ctx context.Context, topic string) (int64, error) {
+	return d.client.XLen(topic).Result()
+}
+
+// Drain passes the messages of the stream to h and deletes them
+func (d *redisDriver) Drain(ctx context.Context, topic string, h Handler) (int, error) {
+	drained := 0
+	for ctx.Err() == nil {
+		msgs, err := d.client.XRangeN(topic, "-", "+", 100).Result()
+		if err != nil || len(msgs) == 0 {
+			return drained, err
+		}
+		for _, msg := range msgs {
+			if err := h(ctx, redisMessage(topic, msg)); err != nil {
+				return drained, err
+			}
+			if err := d.client.Do("xdel", topic, msg.ID).Err(); err != nil {
+				return drained, err
+			}
+			drained++
+		}
+	}
+	return drained, ctx.Err()
+}
+
+// redisValues returns the stream values of the message
+func redisValues(msg Message) map[string]interface{} {
+	values := map[string]interface{}{redisBodyField: string(msg.Body)}
+	for k, v := range msg.Headers {
+		values[redisHeaderField+k] = v
+	}
+	return values
+}
+
+// redisMessage returns the message of the stream values
+func redisMessage(topic string, msg goredis.XMessage) Message {
+	m := Message{ID: msg.ID, Topic: topic, Headers: make(map[string]string)}
+	for k, v := range msg.Values {
+		s := fmt.Sprint(v)
+		switch {
+		case k == redisBodyField:
+			m.Body = []byte(s)
+		case strings.HasPrefix(k, redisHeaderField):
+			m.Headers[strings.TrimPrefix(k, redisHeaderField)] = s
+		}
+	}
+	return m
+}
+
+var (
+	_ Depther = (*redisDriver)(nil)
+	_ Drainer = (*redisDriver)(nil)
+)
@@ -0,0 +1,113 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// operations that validate messages, used as metric label
+const (
+	opPublish = "publish"
+	opConsume = "consume"
+)
+
+var paceQueueInvalidTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("queue_invalid_total"),
+		Help: "Collects stats about the number of messages rejected by the schema of the topic partitioned by driver, topic and operation (publish, consume)",
+	},
+	[]string{"driver", "topic", "op"},
+)
+
+func init() {
+	prometheus.MustRegister(paceQueueInvalidTotal)
+}
+
+// InvalidMessageError is returned for messages that don't
+// match the schema registered for their topic
+type InvalidMessageError struct {
+	Topic string
+	Err   error
+}
+
+func (e *InvalidMessageError) Error() string {
+	return fmt.Sprintf("queue: invalid message of topic %q: %v", e.Topic, e.Err)
+}
+
+// IsInvalidMessage returns true if the error is an InvalidMessageError
+func IsInvalidMessage(err error) bool {
+	_, ok := err.(*InvalidMessageError)
+	return ok
+}
+
+// RegisterSchema registers the schema of the bodies of the messages of the
+// topic. Messages are validated on publish and on consume, a nil schema
+// removes the schema of the topic.
+func (q *Queue) RegisterSchema(topic string, schema *openapi3.Schema) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if schema == nil {
+		delete(q.schemas, topic)
+		return
+	}
+	if q.schemas == nil {
+		q.schemas = make(map[string]*openapi3.Schema)
+	}
+	q.schemas[topic] = schema
+}
+
+// RegisterJSONSchema registers the JSON schema of the bodies of the
+// messages of the topic, the keywords supported by OpenAPIv3 are validated
+func (q *Queue) RegisterJSONSchema(topic string, data []byte) error {
+	var schema openapi3.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("queue: invalid schema of topic %q: %v", topic, err)
+	}
+	q.RegisterSchema(topic, &schema)
+	return nil
+}
+
+// RegisterComponent registers the schema component of the OpenAPIv3
+// specification (#/components/schemas/<name>) for the topic, the
+// references of the specification need to be resolved
+func (q *Queue) RegisterComponent(topic string, spec *openapi3.Swagger, name string) error {
+	ref, ok := spec.Components.Schemas[name]
+	if !ok || ref.Value == nil {
+		return fmt.Errorf("queue: schema component %q of topic %q not found", name, topic)
+	}
+	q.RegisterSchema(topic, ref.Value)
+	return nil
+}
+
+// validate validates the body of the message against the schema
+// of its topic, the invalid message is counted for the operation
+func (q *Queue) validate(msg Message, op string) error {
+	q.mu.RLock()
+	schema, ok := q.schemas[msg.Topic]
+	q.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	err := json.Unmarshal(msg.Body, &value)
+	if err != nil {
+		err = fmt.Errorf("invalid json: %v", err)
+	} else {
+		err = schema.VisitJSON(value)
+	}
+	if err != nil {
+		labels := q.labels(msg.Topic, "")
+		labels["op"] = op
+		paceQueueInvalidTotal.With(labels).Inc()
+		return &InvalidMessageError{Topic: msg.Topic, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaValidation(t *testing.T) {
+	d := &memoryDriver{handlers: make(map[string]Handler), stored: make(map[string][]Message)}
+	q := New(d)
+	q.Retry = RetryPolicy{MaxAttempts: 3}
+	q.DeadLetterSuffix = ".dead"
+	ctx := context.Background()
+
+	assert.Error(t, q.RegisterJSONSchema("orders", []byte(`{"type": `)))
+	assert.NoError(t, q.RegisterJSONSchema("orders", []byte(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {"id": {"type": "string"}, "amount": {"type": "number", "minimum": 0}}
+	}`)))
+
+	handled := 0
+	assert.NoError(t, q.Subscribe(ctx, "orders", func(ctx context.Context, msg Message) error {
+		handled++
+		return nil
+	}))
+
+	labels := func(op string) prometheus.Labels {
+		return prometheus.Labels{"driver": "memory", "topic": "orders", "op": op}
+	}
+	published := counterValue(t, paceQueueInvalidTotal.With(labels(opPublish)))
+	consumed := counterValue(t, paceQueueInvalidTotal.With(labels(opConsume)))
+
+	// rejected on publish
+	assert.NoError(t, q.Publish(ctx, Message{Topic: "orders", Body: []byte(`{"id": "1", "amount": 10}`)}))
+	err := q.Publish(ctx, Message{Topic: "orders", Body: []byte(`{"amount": -1}`)})
+	assert.True(t, IsInvalidMessage(err), "%v", err)
+	assert.False(t, IsInvalidMessage(errors.New("queue")))
+	assert.Equal(t, published+1, counterValue(t, paceQueueInvalidTotal.With(labels(opPublish))))
+
+	// rejected to the dead letter topic on consume, e.g. published by
+	// services without the schema
+	assert.NoError(t, d.Publish(ctx, Message{Topic: "orders", Body: []byte(`not json`)}))
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, consumed+1, counterValue(t, paceQueueInvalidTotal.With(labels(opConsume))))
+	if dead := d.stored["orders.dead"]; assert.Len(t, dead, 1) {
+		assert.Equal(t, "0", dead[0].Headers[HeaderDeadLetterAttempts])
+		assert.Contains(t, dead[0].Headers[HeaderDeadLetterError], "invalid json")
+	}
+
+	// dropped without dead letter topic, they would be delivered again forever
+	q.DeadLetterSuffix = ""
+	dropped := counterValue(t, paceQueueHandledTotal.With(prometheus.Labels{"driver": "memory", "topic": "orders", "result": resultDropped}))
+	assert.NoError(t, d.Publish(ctx, Message{Topic: "orders", Body: []byte(`not json`)}))
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, consumed+2, counterValue(t, paceQueueInvalidTotal.With(labels(opConsume))))
+	assert.Equal(t, dropped+1, counterValue(t, paceQueueHandledTotal.With(prometheus.Labels{"driver": "memory", "topic": "orders", "result": resultDropped})))
+	assert.Len(t, d.stored["orders.dead"], 1)
+	assert.Empty(t, d.failed)
+
+	// schema components of a specification
+	spec := &openapi3.Swagger{Components: openapi3.Components{Schemas: map[string]*openapi3.SchemaRef{
+		"Order": openapi3.NewObjectSchema().WithProperty("id", openapi3.NewStringSchema()).NewRef(),
+	}}}
+	assert.Error(t, q.RegisterComponent("orders", spec, "Invoice"))
+	assert.NoError(t, q.RegisterComponent("orders", spec, "Order"))
+	assert.True(t, IsInvalidMessage(q.Publish(ctx, Message{Topic: "orders", Body: []byte(`{"id": 1}`)})))
+
+	q.RegisterSchema("orders", nil)
+	assert.NoError(t, q.Publish(ctx, Message{Topic: "orders", Body: []byte(`{"id": 1}`)}))
+	assert.Equal(t, 2, handled)
+}
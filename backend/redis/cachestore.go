@@ -0,0 +1,81 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// CacheStore is the shared tier of tiered caches (see cache.NewTiered)
+type CacheStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewCacheStore creates a store using the passed client, the keys
+// are prefixed with prefix (e.g. "<service>:cache:<name>:")
+func NewCacheStore(client *redis.Client, prefix string) *CacheStore {
+	return &CacheStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of key, false if it doesn't exist
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := WithContext(ctx, s.client).Get(s.prefix + key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set stores the value of key that expires after ttl (never if 0)
+func (s *CacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return WithContext(ctx, s.client).Set(s.prefix+key, value, ttl).Err()
+}
+
+// Delete removes the passed keys
+func (s *CacheStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	return WithContext(ctx, s.client).Del(prefixed...).Err()
+}
+
+// CacheLocker makes sure that only one replica computes the value of an
+// uncached key of a tiered cache (see cache.TieredOptions)
+type CacheLocker struct {
+	locker *Locker
+	prefix string
+}
+
+// NewCacheLocker creates a cache locker using the passed client, the
+// locks are prefixed with prefix (e.g. "<service>:cache:<name>:lock:")
+func NewCacheLocker(client *redis.Client, prefix string) *CacheLocker {
+	return &CacheLocker{locker: NewLocker(client), prefix: prefix}
+}
+
+// TryLock acquires the lock of key that expires after ttl, false is
+// returned if the lock is held by another replica
+func (l *CacheLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	lock, err := l.locker.TryAcquire(ctx, l.prefix+key, ttl)
+	if err == ErrLockNotAcquired {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return func() {
+		if err := lock.Release(ctxutil.Detached(ctx)); err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("key", lock.Key).Msg("Failed to release cache lock")
+		}
+	}, true, nil
+}
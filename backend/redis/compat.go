@@ -0,0 +1,98 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/maintenance/health"
+)
+
+// CheckMinVersion returns an error if the version of the redis server
+// is older than min (e.g. "5.0")
+func CheckMinVersion(ctx context.Context, client *redis.Client, min string) error {
+	info, err := WithContext(ctx, client).Info("server").Result()
+	if err != nil {
+		return err
+	}
+	version := infoValue(info, "redis_version")
+	if version == "" {
+		return fmt.Errorf("redis %s: unknown server version", client.Options().Addr)
+	}
+
+	older, err := versionOlder(version, min)
+	if err != nil {
+		return err
+	}
+	if older {
+		return fmt.Errorf("redis %s: server version %s is older than %s", client.Options().Addr, version, min)
+	}
+	return nil
+}
+
+// RegisterMinVersion registers a readiness check (see health.RegisterCheck)
+// that fails until the redis server has at least the passed version. Once
+// the version is fulfilled, the check isn't executed anymore.
+func RegisterMinVersion(client *redis.Client, min string) {
+	var fulfilled int32
+	health.RegisterCheck("redis "+client.Options().Addr, func(ctx context.Context) error {
+		if atomic.LoadInt32(&fulfilled) == 1 {
+			return nil
+		}
+		err := CheckMinVersion(ctx, client, min)
+		if err == nil {
+			atomic.StoreInt32(&fulfilled, 1)
+		}
+		return err
+	})
+}
+
+// RegisterClusterMinVersion registers a readiness check (see
+// health.RegisterCheck) that fails until all masters of the cluster
+// have at least the passed version
+func RegisterClusterMinVersion(client *redis.ClusterClient, min string) {
+	health.RegisterCheck("redis cluster "+strings.Join(client.Options().Addrs, ","), func(ctx context.Context) error {
+		return client.ForEachMaster(func(master *redis.Client) error {
+			return CheckMinVersion(ctx, master, min)
+		})
+	})
+}
+
+// infoValue returns the value of key of the INFO response
+func infoValue(info, key string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, key+":"))
+		}
+	}
+	return ""
+}
+
+// versionOlder returns true if version is older than min,
+// the versions are compared part by part (e.g. "5.0.7" < "6.2")
+func versionOlder(version, min string) (bool, error) {
+	v, m := strings.Split(version, "."), strings.Split(min, ".")
+	for i := 0; i < len(m); i++ {
+		mp, err := strconv.Atoi(m[i])
+		if err != nil {
+			return false, fmt.Errorf("invalid redis version %q", min)
+		}
+		vp := 0
+		if i < len(v) {
+			vp, err = strconv.Atoi(v[i])
+			if err != nil {
+				return false, fmt.Errorf("invalid redis version %q", version)
+			}
+		}
+		if vp != mp {
+			return vp < mp, nil
+		}
+	}
+	return false, nil
+}
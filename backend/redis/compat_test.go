@@ -0,0 +1,52 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVersionOlder(t *testing.T) {
+	cases := []struct {
+		version, min string
+		older        bool
+	}{
+		{"5.0.7", "5.0", false},
+		{"5.0.7", "6.2", true},
+		{"6.2.1", "6", false},
+		{"3.2.12", "3.2.13", true},
+		{"7.0", "7.0.1", true},
+	}
+	for _, c := range cases {
+		older, err := versionOlder(c.version, c.min)
+		if err != nil || older != c.older {
+			t.Errorf("expected versionOlder(%q, %q) to be %v, got %v (%v)", c.version, c.min, c.older, older, err)
+		}
+	}
+	if _, err := versionOlder("5.0", "latest"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestInfoValue(t *testing.T) {
+	info := "# Server\r\nredis_version:5.0.7\r\nredis_mode:standalone\r\n"
+	if v := infoValue(info, "redis_version"); v != "5.0.7" {
+		t.Errorf("expected 5.0.7, got %q", v)
+	}
+}
+
+func TestCheckMinVersion(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	if err := CheckMinVersion(context.Background(), client, "1.0"); err != nil {
+		t.Errorf("expected version to be fulfilled, got %v", err)
+	}
+	if err := CheckMinVersion(context.Background(), client, "99"); err == nil {
+		t.Error("expected version 99 not to be fulfilled")
+	}
+}
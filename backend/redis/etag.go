@@ -0,0 +1,111 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of etag checks, used as metric label
+const (
+	etagNotModified = "not_modified"
+	etagModified    = "modified"
+	etagError       = "error"
+)
+
+var paceRedisETagTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("redis_etag_total"),
+		Help: "Collects stats about the number of collection etag checks partitioned by result",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisETagTotal)
+}
+
+// CollectionVersions maintains version counters of collections in redis.
+// The versions are bumped on every write to a collection and are used as
+// cheap ETags of list endpoints, so that unchanged collections can be
+// answered with 304 Not Modified without executing the underlying query.
+type CollectionVersions struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewCollectionVersions creates collection versions stored using the
+// passed client, the keys are prefixed with prefix (e.g. the service name)
+func NewCollectionVersions(client *redis.Client, prefix string) *CollectionVersions {
+	return &CollectionVersions{client: client, prefix: prefix}
+}
+
+func (v *CollectionVersions) key(collection string) string {
+	return fmt.Sprintf("%s:collection:%s:version", v.prefix, collection)
+}
+
+// Bump increments the versions of the passed collections, call it
+// after every write to the collections (e.g. after the commit)
+func (v *CollectionVersions) Bump(ctx context.Context, collections ...string) error {
+	_, err := WithContext(ctx, v.client).Pipelined(func(pipe redis.Pipeliner) error {
+		for _, collection := range collections {
+			pipe.Incr(v.key(collection))
+		}
+		return nil
+	})
+	return err
+}
+
+// ETag returns the weak etag of the current versions of the
+// passed collections
+func (v *CollectionVersions) ETag(ctx context.Context, collections ...string) (string, error) {
+	keys := make([]string, len(collections))
+	for i, collection := range collections {
+		keys[i] = v.key(collection)
+	}
+
+	versions, err := WithContext(ctx, v.client).MGet(keys...).Result()
+	if err != nil {
+		return "", err
+	}
+
+	h := fnv.New64a()
+	for i, version := range versions {
+		if version == nil {
+			version = "0" // collection was never written
+		}
+		fmt.Fprintf(h, "%s=%v;", collections[i], version)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum64()), nil
+}
+
+// NotModified sets the ETag header of the response to the etag of the
+// collections (see ETag). If the client has the current version already
+// (If-None-Match), a 304 Not Modified response is sent and true is returned.
+// If redis is not available, false is returned so that the response is
+// generated as usual.
+func (v *CollectionVersions) NotModified(w http.ResponseWriter, r *http.Request, collections ...string) bool {
+	etag, err := v.ETag(r.Context(), collections...)
+	if err != nil {
+		paceRedisETagTotal.With(prometheus.Labels{"result": etagError}).Inc()
+		log.Req(r).Warn().Err(err).Strs("collections", collections).Msg("Failed to get collection etag")
+		return false
+	}
+
+	if runtime.NotModifiedETag(w, r, etag) {
+		paceRedisETagTotal.With(prometheus.Labels{"result": etagNotModified}).Inc()
+		return true
+	}
+	paceRedisETagTotal.With(prometheus.Labels{"result": etagModified}).Inc()
+	return false
+}
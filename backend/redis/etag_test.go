@@ -0,0 +1,48 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCollectionVersions(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	ctx := context.Background()
+	v := NewCollectionVersions(client, "TestCollectionVersions")
+	client.Del(v.key("articles"), v.key("comments"))
+
+	etag, err := v.ETag(ctx, "articles", "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unchanged collections are not modified
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/articles", nil)
+	req.Header.Set("If-None-Match", etag)
+	if !v.NotModified(rec, req, "articles", "comments") || rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 Not Modified, got: %d", rec.Code)
+	}
+
+	// writes change the etag
+	err = v.Bump(ctx, "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	if v.NotModified(rec, req, "articles", "comments") {
+		t.Error("Expected collection to be modified")
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf("Expected new etag, got: %q", etag)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceRedisHealthCheckDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("redis_health_check_duration_seconds"),
+			Help:    "Collect performance metrics for each health check (PING) of the redis servers",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"addr"},
+	)
+	paceRedisHealthCheckUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("redis_health_check_up"),
+			Help: "Result of the last health check of the redis servers, 1 if healthy 0 otherwise",
+		},
+		[]string{"addr"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisHealthCheckDurationSeconds)
+	prometheus.MustRegister(paceRedisHealthCheckUp)
+}
+
+var (
+	healthClientOnce sync.Once
+	healthClient     *redis.Client
+	healthCluster    *redis.ClusterClient
+)
+
+// HealthCheck pings the redis server configured using the environment
+// (see Client), all masters in cluster mode. It can be registered at the
+// readiness endpoint using health.RegisterCheck("redis", redis.HealthCheck)
+func HealthCheck(ctx context.Context) error {
+	if err := Setup(); err != nil {
+		return err
+	}
+	healthClientOnce.Do(func() {
+		if cfg.Mode == modeCluster {
+			healthCluster = ClusterClient()
+		} else {
+			healthClient = Client()
+		}
+	})
+	if healthCluster != nil {
+		return healthCluster.ForEachMaster(func(master *redis.Client) error {
+			return ping(ctx, master)
+		})
+	}
+	return ping(ctx, healthClient)
+}
+
+// ClientHealthCheck returns a health check that pings the passed client
+func ClientHealthCheck(client *redis.Client) health.Check {
+	return func(ctx context.Context) error {
+		return ping(ctx, client)
+	}
+}
+
+// RegisterHealthCheck registers the health check of the passed client
+// at the readiness endpoint (see health.RegisterCheck)
+func RegisterHealthCheck(client *redis.Client) {
+	health.RegisterCheck("redis ping "+client.Options().Addr, ClientHealthCheck(client))
+}
+
+// ping sends a PING to the server, the check fails after
+// REDIS_HEALTH_CHECK_TIMEOUT or when ctx is done
+func ping(ctx context.Context, client *redis.Client) error {
+	addr := client.Options().Addr
+	timeout := cfg.HealthCheckTimeout
+	if timeout <= 0 {
+		timeout = client.Options().ReadTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan error, 1)
+	go func() {
+		result <- WithContext(ctx, client).Ping().Err()
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = fmt.Errorf("redis %s: ping: %v", addr, ctx.Err())
+	}
+
+	paceRedisHealthCheckDurationSeconds.With(prometheus.Labels{"addr": addr}).
+		Observe(time.Since(start).Seconds())
+	up := 1.0
+	if err != nil {
+		up = 0
+	}
+	paceRedisHealthCheckUp.With(prometheus.Labels{"addr": addr}).Set(up)
+
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestHealthCheck(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	if err := HealthCheck(context.Background()); err != nil {
+		t.Errorf("expected redis to be healthy, got %v", err)
+	}
+	if v := gaugeValue(t, paceRedisHealthCheckUp.With(prometheus.Labels{"addr": client.Options().Addr})); v != 1 {
+		t.Errorf("expected up to be 1, got %v", v)
+	}
+}
+
+func TestHealthCheckUnavailable(t *testing.T) {
+	client := CustomClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer client.Close() // nolint: errcheck
+
+	if err := ClientHealthCheck(client)(context.Background()); err == nil {
+		t.Error("expected health check to fail")
+	}
+	if v := gaugeValue(t, paceRedisHealthCheckUp.With(prometheus.Labels{"addr": "127.0.0.1:1"})); v != 0 {
+		t.Errorf("expected up to be 0, got %v", v)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/pkg/cache"
+)
+
+// CacheInvalidator distributes the invalidations of replicated caches
+// (see cache.Cache.Replicate) using redis pub/sub
+type CacheInvalidator struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewCacheInvalidator creates an invalidator that publishes to and
+// subscribes the passed channel (e.g. "<service>:cache")
+func NewCacheInvalidator(client *redis.Client, channel string) *CacheInvalidator {
+	return &CacheInvalidator{client: client, channel: channel}
+}
+
+// Publish sends the invalidation to all subscribed replicas
+func (i *CacheInvalidator) Publish(ctx context.Context, inv cache.Invalidation) (int, error) {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return 0, err
+	}
+	receivers, err := WithContext(ctx, i.client).Publish(i.channel, data).Result()
+	return int(receivers), err
+}
+
+// Subscribe calls fn for every invalidation published on the channel
+// until ctx is done. The subscription is re-established automatically
+// if the connection breaks, invalidations published in the meantime
+// are lost.
+func (i *CacheInvalidator) Subscribe(ctx context.Context, fn func(inv cache.Invalidation)) error {
+	pubsub := i.client.Subscribe(i.channel)
+	// wait for the confirmation of the subscription
+	_, err := pubsub.Receive()
+	if err != nil {
+		pubsub.Close() // nolint: errcheck
+		return err
+	}
+
+	go func() {
+		defer errors.HandleWithCtx(ctx, "CacheInvalidator")
+		defer pubsub.Close() // nolint: errcheck
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var inv cache.Invalidation
+				err := json.Unmarshal([]byte(msg.Payload), &inv)
+				if err != nil {
+					log.Ctx(ctx).Warn().Err(err).Str("channel", msg.Channel).Msg("Invalid cache invalidation")
+					continue
+				}
+				fn(inv)
+			}
+		}
+	}()
+	return nil
+}
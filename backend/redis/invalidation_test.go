@@ -0,0 +1,22 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/pace/bricks/pkg/cache"
+	"github.com/pace/bricks/pkg/cache/cachetest"
+)
+
+func TestCacheInvalidatorConsistency(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	cachetest.Consistency(t, 3, func() cache.Invalidator {
+		return NewCacheInvalidator(Client(), "TestCacheInvalidatorConsistency")
+	})
+}
@@ -0,0 +1,376 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of lock acquisitions, used as metric label
+const (
+	lockAcquired    = "acquired"
+	lockNotAcquired = "not_acquired"
+	lockError       = "error"
+)
+
+// lockPollInterval in which a held lock is tried to be acquired again
+var lockPollInterval = 100 * time.Millisecond
+
+var (
+	paceRedisLockTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("redis_lock_total"),
+			Help: "Collects stats about the number of redis lock acquisitions partitioned by result",
+		},
+		[]string{"result"},
+	)
+	paceRedisLockWaitDurationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("redis_lock_wait_duration_seconds"),
+			Help:    "Collect the time waited for redis locks",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+	)
+	paceRedisLockLostTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: metric.Name("redis_lock_lost_total"),
+			Help: "Collects stats about the number of redis locks that expired before they were extended or released",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisLockTotal)
+	prometheus.MustRegister(paceRedisLockWaitDurationSeconds)
+	prometheus.MustRegister(paceRedisLockLostTotal)
+}
+
+var (
+	// ErrLockNotAcquired is returned if the lock is held by another
+	// owner until the context is done
+	ErrLockNotAcquired = errors.New("redis lock not acquired")
+	// ErrLockLost is returned if the lock expired (and may be held by
+	// another owner) before it was extended or released
+	ErrLockLost = errors.New("redis lock lost")
+)
+
+// fencingScript increments the fencing token if the lock is
+// held by the owner, it returns 0 if the lock isn't held
+var fencingScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+
+// extendScript sets the expiry of the lock if it is still held by the owner
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript deletes the lock if it is still held by the owner
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Locker acquires distributed locks on one or more independent redis
+// servers (redlock). With multiple servers, a lock is acquired once the
+// majority of the servers granted it. The fencing tokens are issued by
+// the first server only, a single authoritative counter, so that they
+// increase with each acquisition even if the majorities differ.
+type Locker struct {
+	clients []*redis.Client
+}
+
+// NewLocker returns a locker using the passed clients, the clients
+// should point to independent servers (not replicas of each other).
+// The first client issues the fencing tokens, locks can't be acquired
+// while its server is unavailable.
+func NewLocker(clients ...*redis.Client) *Locker {
+	return &Locker{clients: clients}
+}
+
+// Lock is an acquired distributed lock, it expires automatically after
+// its ttl unless it is extended
+type Lock struct {
+	Key string
+	// Token is a fencing token that increases with each acquisition of
+	// the lock. Pass it to the resources protected by the lock, so that
+	// they can reject writes of owners whose lock expired in the meantime.
+	// It is issued by the first server of the locker, which therefore needs
+	// persistence (e.g. AOF) to not issue tokens again after a restart.
+	Token int64
+
+	locker *Locker
+	value  string
+	expiry time.Time
+	span   opentracing.Span
+}
+
+// Acquire acquires the lock for key that expires after ttl. If the lock is
+// held by another owner, it is tried again until ctx is done, then
+// ErrLockNotAcquired is returned.
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	return l.traceAcquire(ctx, key, ttl, true)
+}
+
+// TryAcquire acquires the lock for key that expires after ttl. If the
+// lock is held by another owner, ErrLockNotAcquired is returned
+// immediately.
+func (l *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	return l.traceAcquire(ctx, key, ttl, false)
+}
+
+func (l *Locker) traceAcquire(ctx context.Context, key string, ttl time.Duration, wait bool) (*Lock, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Redis: Lock %s", key))
+	span.LogFields(olog.String("key", key), olog.String("ttl", ttl.String()))
+
+	start := time.Now()
+	lock, err := l.acquire(ctx, key, ttl, wait)
+	paceRedisLockWaitDurationSeconds.Observe(time.Since(start).Seconds())
+
+	result := lockAcquired
+	switch {
+	case err == ErrLockNotAcquired:
+		result = lockNotAcquired
+	case err != nil:
+		result = lockError
+	}
+	paceRedisLockTotal.With(prometheus.Labels{"result": result}).Inc()
+	span.LogFields(olog.String("result", result))
+
+	if err != nil {
+		span.LogFields(olog.Error(err))
+		span.Finish()
+		log.Ctx(ctx).Debug().Err(err).Str("key", key).Msg("Redis lock not acquired")
+		return nil, err
+	}
+
+	span.LogFields(olog.Int64("token", lock.Token))
+	lock.span = span
+	log.Ctx(ctx).Debug().Str("key", key).Int64("token", lock.Token).Msg("Redis lock acquired")
+	return lock, nil
+}
+
+func (l *Locker) acquire(ctx context.Context, key string, ttl time.Duration, wait bool) (*Lock, error) {
+	value, err := lockValue()
+	if err != nil {
+		return nil, err
+	}
+	lock := &Lock{Key: key, locker: l, value: value}
+
+	for {
+		ok, err := lock.try(ctx, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return lock, nil
+		}
+		if !wait {
+			return nil, ErrLockNotAcquired
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrLockNotAcquired
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// try acquires the lock on the majority of the servers once, the lock
+// is released on all servers if it wasn't acquired
+func (lock *Lock) try(ctx context.Context, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	granted := 0
+	var lastErr error
+	for _, client := range lock.locker.clients {
+		ok, err := WithContext(ctx, client).SetNX(lock.Key, lock.value, ttl).Result()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ok {
+			granted++
+		}
+	}
+
+	if granted >= lock.locker.quorum() {
+		token, err := lock.fencingToken(ctx)
+		if err != nil {
+			lock.unlock(ctx) // nolint: errcheck
+			return false, err
+		}
+
+		// the lock is valid for the ttl reduced by the time needed to
+		// acquire it and a drift of the clocks of the servers
+		validity := ttl - time.Since(start) - lockDrift(ttl)
+		if token > 0 && validity > 0 {
+			lock.Token = token
+			lock.expiry = start.Add(validity)
+			return true, nil
+		}
+	}
+
+	lock.unlock(ctx) // nolint: errcheck
+	if granted == 0 && lastErr != nil {
+		return false, lastErr
+	}
+	return false, nil
+}
+
+// fencingToken increments the fencing token of the key on the first
+// server, 0 is returned if the lock isn't held there (anymore)
+func (lock *Lock) fencingToken(ctx context.Context) (int64, error) {
+	return fencingScript.Run(WithContext(ctx, lock.locker.clients[0]),
+		[]string{lock.Key, lock.Key + ":fencing"}, lock.value).Int64()
+}
+
+// Extend sets the expiry of the lock to ttl from now. If the lock
+// expired already, ErrLockLost is returned.
+func (lock *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	start := time.Now()
+	extended := 0
+	var lastErr error
+	for _, client := range lock.locker.clients {
+		n, err := extendScript.Run(WithContext(ctx, client),
+			[]string{lock.Key}, lock.value, int64(ttl/time.Millisecond)).Int64()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if n > 0 {
+			extended++
+		}
+	}
+
+	if extended >= lock.locker.quorum() {
+		lock.expiry = start.Add(ttl - time.Since(start) - lockDrift(ttl))
+		lock.span.LogFields(olog.String("event", "extended"))
+		return nil
+	}
+	if extended == 0 && lastErr != nil {
+		return lastErr
+	}
+	paceRedisLockLostTotal.Inc()
+	lock.span.LogFields(olog.String("event", "lost"))
+	return ErrLockLost
+}
+
+// Release releases the lock. If the lock expired already,
+// ErrLockLost is returned.
+func (lock *Lock) Release(ctx context.Context) error {
+	defer lock.span.Finish()
+	released, err := lock.unlock(ctx)
+	if err != nil {
+		return err
+	}
+	if released < lock.locker.quorum() {
+		paceRedisLockLostTotal.Inc()
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Expiry returns the time until the lock is guaranteed to be held
+func (lock *Lock) Expiry() time.Time {
+	return lock.expiry
+}
+
+// unlock deletes the lock on all servers and returns the number of
+// servers the lock was held on
+func (lock *Lock) unlock(ctx context.Context) (int, error) {
+	released := 0
+	var lastErr error
+	for _, client := range lock.locker.clients {
+		n, err := releaseScript.Run(WithContext(ctx, client), []string{lock.Key}, lock.value).Int64()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		released += int(n)
+	}
+	if released == 0 && lastErr != nil {
+		return 0, lastErr
+	}
+	return released, nil
+}
+
+// WithLock executes fn while holding the lock for key (see Acquire). The
+// lock is extended every ttl/3 while fn is executed, if it can't be
+// extended the context of fn is canceled. Use it to make sure only one
+// replica of a service executes fn at a time, e.g. for leader election or
+// cron-style work. If the lock is not acquired until ctx is done,
+// ErrLockNotAcquired is returned and fn is not executed.
+func (l *Locker) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	fctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := lock.Extend(fctx, ttl); err != nil {
+					log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Failed to extend redis lock, canceling")
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(fctx)
+	close(done)
+
+	if rerr := lock.Release(ctxutil.Detached(ctx)); rerr != nil {
+		log.Ctx(ctx).Warn().Err(rerr).Str("key", key).Msg("Failed to release redis lock")
+	}
+	return err
+}
+
+// quorum returns the number of servers that need to grant a lock
+func (l *Locker) quorum() int {
+	return len(l.clients)/2 + 1
+}
+
+// lockDrift returns the clock drift of the servers for the ttl
+func lockDrift(ttl time.Duration) time.Duration {
+	return ttl/100 + 2*time.Millisecond
+}
+
+// lockValue returns a random value that identifies the owner of a lock
+func lockValue() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,74 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocker(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	locker := NewLocker(client)
+	ctx := context.Background()
+	key := "bricks:test:lock"
+
+	lock, err := locker.Acquire(ctx, key, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the lock is held
+	tctx, cancel := context.WithTimeout(ctx, 150*time.Millisecond)
+	_, err = locker.Acquire(tctx, key, time.Second)
+	cancel()
+	assert.Equal(t, ErrLockNotAcquired, err)
+	_, err = locker.TryAcquire(ctx, key, time.Second)
+	assert.Equal(t, ErrLockNotAcquired, err)
+
+	assert.NoError(t, lock.Extend(ctx, 2*time.Second))
+	assert.NoError(t, lock.Release(ctx))
+
+	// the fencing token increases with each acquisition
+	next, err := locker.Acquire(ctx, key, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, next.Token > lock.Token)
+
+	// the lock expired
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, ErrLockLost, next.Extend(ctx, time.Second))
+	assert.Equal(t, ErrLockLost, next.Release(ctx))
+}
+
+func TestLockerWithLock(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	locker := NewLocker(client)
+
+	executed := false
+	err := locker.WithLock(context.Background(), "bricks:test:withlock", 300*time.Millisecond, func(ctx context.Context) error {
+		// longer than the ttl, the lock is extended
+		time.Sleep(500 * time.Millisecond)
+		executed = ctx.Err() == nil
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, executed)
+}
+
+func TestLockerQuorum(t *testing.T) {
+	assert.Equal(t, 1, NewLocker(nil).quorum())
+	assert.Equal(t, 2, NewLocker(nil, nil, nil).quorum())
+	assert.Equal(t, 3, NewLocker(nil, nil, nil, nil, nil).quorum())
+}
@@ -0,0 +1,280 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of received messages, used as metric label
+const (
+	messageProcessed = "processed"
+	messageFailed    = "failed"
+	messageDropped   = "dropped"
+)
+
+// subscriberPingInterval after which the connection is checked
+// if no messages were received
+var subscriberPingInterval = 5 * time.Second
+
+var (
+	paceRedisPubSubMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("redis_pubsub_messages_total"),
+			Help: "Collects stats about the number of received pub/sub messages partitioned by channel (or pattern) and result",
+		},
+		[]string{"channel", "result"},
+	)
+	paceRedisPubSubReconnectsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: metric.Name("redis_pubsub_reconnects_total"),
+			Help: "Collects stats about the number of pub/sub connection losses",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisPubSubMessagesTotal)
+	prometheus.MustRegister(paceRedisPubSubReconnectsTotal)
+}
+
+// MessageHandler handles a message received on a subscribed channel
+type MessageHandler func(ctx context.Context, msg *redis.Message) error
+
+// Subscriber delivers the messages of subscribed channels and patterns to
+// handlers. The subscriptions are re-established automatically if the
+// connection breaks, messages published in the meantime are lost.
+type Subscriber struct {
+	// BufferSize is the number of received messages that are buffered
+	// until they are handled, further messages are dropped. Defaults to 100.
+	BufferSize int
+
+	client   *redis.Client
+	channels map[string]MessageHandler
+	patterns map[string]MessageHandler
+}
+
+// NewSubscriber creates a subscriber using the passed client
+func NewSubscriber(client *redis.Client) *Subscriber {
+	return &Subscriber{
+		client:   client,
+		channels: make(map[string]MessageHandler),
+		patterns: make(map[string]MessageHandler),
+	}
+}
+
+// Handle subscribes the channel, its messages are passed to h.
+// Needs to be called before Run.
+func (s *Subscriber) Handle(channel string, h MessageHandler) {
+	s.channels[channel] = h
+}
+
+// HandlePattern subscribes the channels matching the pattern (e.g.
+// "events.*"), their messages are passed to h. Needs to be called
+// before Run.
+func (s *Subscriber) HandlePattern(pattern string, h MessageHandler) {
+	s.patterns[pattern] = h
+}
+
+// Run subscribes the channels and patterns and handles the received
+// messages one after another until ctx is done. On shutdown no further
+// messages are received, the buffered messages are handled before Run
+// returns. An error is returned if the initial subscription fails.
+func (s *Subscriber) Run(ctx context.Context) error {
+	pubsub, err := s.subscribe()
+	if err != nil {
+		return err
+	}
+
+	size := s.BufferSize
+	if size <= 0 {
+		size = 100
+	}
+	msgs := make(chan *redis.Message, size)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// buffered messages are handled after ctx is done
+		hctx := ctxutil.Detached(ctx)
+		for msg := range msgs {
+			s.handle(hctx, msg)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(done)
+		pubsub.Close() // nolint: errcheck
+	}()
+
+	s.receive(ctx, pubsub, msgs, done)
+	close(msgs)
+	wg.Wait()
+	return nil
+}
+
+// subscribe subscribes the channels and patterns and waits
+// for the confirmation of the subscriptions
+func (s *Subscriber) subscribe() (*redis.PubSub, error) {
+	pubsub := s.client.Subscribe()
+	if len(s.channels) > 0 {
+		err := pubsub.Subscribe(handlerKeys(s.channels)...)
+		if err != nil {
+			pubsub.Close() // nolint: errcheck
+			return nil, err
+		}
+	}
+	if len(s.patterns) > 0 {
+		err := pubsub.PSubscribe(handlerKeys(s.patterns)...)
+		if err != nil {
+			pubsub.Close() // nolint: errcheck
+			return nil, err
+		}
+	}
+
+	for i := 0; i < len(s.channels)+len(s.patterns); i++ {
+		_, err := pubsub.Receive()
+		if err != nil {
+			pubsub.Close() // nolint: errcheck
+			return nil, err
+		}
+	}
+	return pubsub, nil
+}
+
+// receive passes the received messages to msgs until done is closed.
+// If the connection breaks, the pubsub reconnects and resubscribes with
+// the next receive.
+func (s *Subscriber) receive(ctx context.Context, pubsub *redis.PubSub, msgs chan<- *redis.Message, done <-chan struct{}) {
+	failures := 0
+	for {
+		res, err := pubsub.ReceiveTimeout(subscriberPingInterval)
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		if err != nil {
+			if isTimeout(err) {
+				// no messages, check the connection
+				err = pubsub.Ping()
+				if err == nil {
+					continue
+				}
+			}
+
+			if failures == 0 {
+				paceRedisPubSubReconnectsTotal.Inc()
+				log.Ctx(ctx).Warn().Err(err).Msg("Redis subscription lost, resubscribing")
+			}
+			failures++
+			select {
+			case <-done:
+				return
+			case <-time.After(resubscribeBackoff(failures)):
+			}
+			continue
+		}
+		if failures > 0 {
+			log.Ctx(ctx).Info().Msg("Redis subscription re-established")
+			failures = 0
+		}
+
+		msg, ok := res.(*redis.Message)
+		if !ok {
+			continue // subscription confirmations and pongs
+		}
+		select {
+		case msgs <- msg:
+		default:
+			paceRedisPubSubMessagesTotal.With(prometheus.Labels{"channel": msgLabel(msg), "result": messageDropped}).Inc()
+			log.Ctx(ctx).Warn().Str("channel", msg.Channel).Msg("Redis subscriber buffer full, message dropped")
+		}
+	}
+}
+
+// handle passes the message to its handler with a span
+// of the channel, panics of the handler are recovered
+func (s *Subscriber) handle(ctx context.Context, msg *redis.Message) {
+	h := s.channels[msg.Channel]
+	if msg.Pattern != "" {
+		h = s.patterns[msg.Pattern]
+	}
+	if h == nil {
+		return
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Redis: receive %s", msg.Channel))
+	defer span.Finish()
+	ext.DBType.Set(span, "redis")
+	span.LogFields(olog.String("channel", msg.Channel), olog.Int("size", len(msg.Payload)))
+
+	result := messageFailed
+	defer func() {
+		paceRedisPubSubMessagesTotal.With(prometheus.Labels{"channel": msgLabel(msg), "result": result}).Inc()
+	}()
+	defer errors.HandleWithCtx(ctx, "redis subscriber "+msg.Channel)
+
+	err := h(ctx, msg)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("channel", msg.Channel).Msg("Failed to handle redis message")
+		return
+	}
+	result = messageProcessed
+}
+
+// msgLabel returns the pattern of the message if it was received
+// using a pattern subscription, the channel otherwise
+func msgLabel(msg *redis.Message) string {
+	if msg.Pattern != "" {
+		return msg.Pattern
+	}
+	return msg.Channel
+}
+
+func handlerKeys(m map[string]MessageHandler) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// isTimeout returns true if err is a network timeout
+func isTimeout(err error) bool {
+	te, ok := err.(interface{ Timeout() bool })
+	return ok && te.Timeout()
+}
+
+// resubscribeBackoff returns the time to wait before the next attempt,
+// it doubles with every failure up to 5s
+func resubscribeBackoff(failures int) time.Duration {
+	d := 100 * time.Millisecond
+	for i := 1; i < failures && d < 5*time.Second; i++ {
+		d *= 2
+	}
+	if d > 5*time.Second {
+		d = 5 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,99 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriber(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	received := make(chan string, 2)
+	s := NewSubscriber(client)
+	s.Handle("bricks:test:pubsub", func(ctx context.Context, msg *redis.Message) error {
+		received <- msg.Payload
+		return nil
+	})
+	s.HandlePattern("bricks:test:pubsub:*", func(ctx context.Context, msg *redis.Message) error {
+		received <- msg.Channel
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	// wait for the subscription
+	for i := 0; i < 50; i++ {
+		n, err := client.PubSubNumSub("bricks:test:pubsub").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n["bricks:test:pubsub"] > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.Publish("bricks:test:pubsub", "hello")
+	client.Publish("bricks:test:pubsub:a", "world")
+	for _, expected := range []string{"hello", "bricks:test:pubsub:a"} {
+		select {
+		case got := <-received:
+			assert.Equal(t, expected, got)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected message %q", expected)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected subscriber to shut down")
+	}
+}
+
+func TestSubscriberHandle(t *testing.T) {
+	s := NewSubscriber(nil)
+	s.Handle("ok", func(ctx context.Context, msg *redis.Message) error { return nil })
+	s.Handle("failed", func(ctx context.Context, msg *redis.Message) error { return errors.New("failed") })
+	s.Handle("panic", func(ctx context.Context, msg *redis.Message) error { panic("panic") })
+	s.HandlePattern("events.*", func(ctx context.Context, msg *redis.Message) error { return nil })
+
+	cases := []struct {
+		msg    *redis.Message
+		label  string
+		result string
+	}{
+		{&redis.Message{Channel: "ok"}, "ok", messageProcessed},
+		{&redis.Message{Channel: "failed"}, "failed", messageFailed},
+		{&redis.Message{Channel: "panic"}, "panic", messageFailed},
+		{&redis.Message{Channel: "events.a", Pattern: "events.*"}, "events.*", messageProcessed},
+	}
+	for _, c := range cases {
+		labels := prometheus.Labels{"channel": c.label, "result": c.result}
+		before := counterValue(t, paceRedisPubSubMessagesTotal.With(labels))
+		s.handle(context.Background(), c.msg)
+		assert.Equal(t, before+1, counterValue(t, paceRedisPubSubMessagesTotal.With(labels)), c.label)
+	}
+}
+
+func TestResubscribeBackoff(t *testing.T) {
+	assert.Equal(t, 100*time.Millisecond, resubscribeBackoff(1))
+	assert.Equal(t, 400*time.Millisecond, resubscribeBackoff(3))
+	assert.Equal(t, 5*time.Second, resubscribeBackoff(100))
+}
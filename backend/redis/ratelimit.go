@@ -0,0 +1,380 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of rate limit checks, used as metric label
+const (
+	rateLimitAllowed = "allowed"
+	rateLimitDenied  = "denied"
+	rateLimitError   = "error"
+)
+
+var paceRedisRateLimitTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("redis_rate_limit_total"),
+		Help: "Collects stats about the number of rate limit checks partitioned by limiter and result",
+	},
+	[]string{"name", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisRateLimitTotal)
+}
+
+// RateLimitAlgorithm is the algorithm used to limit the rate
+type RateLimitAlgorithm int
+
+const (
+	// SlidingWindow allows Limit requests in any Window. The requests of
+	// the previous window are weighted by its overlap with the sliding
+	// window, so that no burst at the window boundaries is possible.
+	SlidingWindow RateLimitAlgorithm = iota
+	// TokenBucket refills Limit tokens per Window continuously up to
+	// Burst tokens, each request takes a token.
+	TokenBucket
+)
+
+// slidingWindowScript increments the counter of the current window by
+// ARGV[2] if the weighted count of the current and previous window stays
+// within the limit ARGV[1], the previous window is weighted by ARGV[3].
+// Returns whether the requests are allowed and both counters.
+var slidingWindowScript = redis.NewScript(`
+local cur = tonumber(redis.call("GET", KEYS[1]) or "0")
+local prev = tonumber(redis.call("GET", KEYS[2]) or "0")
+local n = tonumber(ARGV[2])
+if math.floor(prev * tonumber(ARGV[3])) + cur + n > tonumber(ARGV[1]) then
+	return {0, cur, prev}
+end
+cur = redis.call("INCRBY", KEYS[1], n)
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return {1, cur, prev}`)
+
+// tokenBucketScript refills the bucket with ARGV[1] tokens per
+// millisecond up to ARGV[2] tokens and takes ARGV[4] tokens if
+// available. Returns whether the requests are allowed and the
+// remaining tokens.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local n = tonumber(ARGV[4])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate))
+return {allowed, tostring(tokens)}`)
+
+// RateLimiter limits the rate of requests per key (e.g. client id,
+// IP or tenant) across all replicas of a service
+type RateLimiter struct {
+	// Name of the limiter, used as prefix of the keys and as metric label
+	Name string
+	// Limit is the number of requests allowed per Window
+	Limit int
+	// Window in which Limit requests are allowed
+	Window time.Duration
+	// Burst is the maximum number of tokens of the TokenBucket,
+	// defaults to Limit
+	Burst int
+	// Algorithm defaults to SlidingWindow
+	Algorithm RateLimitAlgorithm
+
+	client *redis.Client
+}
+
+// NewRateLimiter returns a sliding window rate limiter that allows limit
+// requests per window, set the Algorithm to use a TokenBucket instead
+func NewRateLimiter(client *redis.Client, name string, limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		Name:   name,
+		Limit:  limit,
+		Window: window,
+		client: client,
+	}
+}
+
+// RateLimitResult is the result of a rate limit check
+type RateLimitResult struct {
+	Allowed bool
+	// Limit of the limiter
+	Limit int
+	// Remaining number of requests that are currently allowed
+	Remaining int
+	// RetryAfter is the time after which the denied requests
+	// are allowed, 0 if they were allowed
+	RetryAfter time.Duration
+}
+
+// Allow checks and counts a single request of key
+func (rl *RateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	return rl.AllowN(ctx, key, 1)
+}
+
+// AllowN checks and counts n requests of key, the requests are
+// only counted if they are allowed
+func (rl *RateLimiter) AllowN(ctx context.Context, key string, n int) (RateLimitResult, error) {
+	var (
+		res RateLimitResult
+		err error
+	)
+	switch rl.Algorithm {
+	case SlidingWindow:
+		res, err = rl.slidingWindow(ctx, key, n, time.Now())
+	case TokenBucket:
+		res, err = rl.tokenBucket(ctx, key, n, time.Now())
+	default:
+		err = fmt.Errorf("unknown rate limit algorithm %d", rl.Algorithm)
+	}
+
+	result := rateLimitAllowed
+	switch {
+	case err != nil:
+		result = rateLimitError
+	case !res.Allowed:
+		result = rateLimitDenied
+	}
+	paceRedisRateLimitTotal.With(prometheus.Labels{"name": rl.Name, "result": result}).Inc()
+
+	return res, err
+}
+
+func (rl *RateLimiter) key(key string) string {
+	return fmt.Sprintf("%s:ratelimit:%s", rl.Name, key)
+}
+
+func (rl *RateLimiter) slidingWindow(ctx context.Context, key string, n int, now time.Time) (RateLimitResult, error) {
+	window := int64(rl.Window / time.Millisecond)
+	if window <= 0 {
+		return RateLimitResult{}, errors.New("rate limit window must be at least 1ms")
+	}
+	ms := now.UnixNano() / int64(time.Millisecond)
+	idx, elapsed := ms/window, ms%window
+	weight := 1 - float64(elapsed)/float64(window)
+
+	keys := []string{
+		fmt.Sprintf("%s:%d", rl.key(key), idx),
+		fmt.Sprintf("%s:%d", rl.key(key), idx-1),
+	}
+	// the counter is required until the end of the next window
+	ret, err := slidingWindowScript.Run(WithContext(ctx, rl.client), keys,
+		rl.Limit, n, strconv.FormatFloat(weight, 'f', -1, 64), 2*window).Result()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	vals, ok := ret.([]interface{})
+	if !ok || len(vals) != 3 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script result %v", ret)
+	}
+	allowed, _ := vals[0].(int64)
+	cur, _ := vals[1].(int64)
+	prev, _ := vals[2].(int64)
+
+	count := int64(math.Floor(float64(prev)*weight)) + cur
+	res := RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     rl.Limit,
+		Remaining: int(math.Max(0, float64(int64(rl.Limit)-count))),
+	}
+	if !res.Allowed {
+		res.RetryAfter = slidingWindowRetryAfter(rl.Limit, n, cur, prev, weight, rl.Window)
+	}
+	return res, nil
+}
+
+// slidingWindowRetryAfter estimates the time until n requests are
+// allowed, assuming no other requests are made in the meantime
+func slidingWindowRetryAfter(limit, n int, cur, prev int64, weight float64, window time.Duration) time.Duration {
+	rest := time.Duration(weight * float64(window))
+	excess := float64(cur) + float64(prev)*weight + float64(n) - float64(limit)
+
+	// the requests of the previous window decay until the end of the current one
+	if prev > 0 && excess <= float64(prev)*weight {
+		return time.Duration(excess / float64(prev) * float64(window))
+	}
+	// the requests of the current window decay during the next one
+	excess -= float64(prev) * weight
+	if cur > 0 && excess <= float64(cur) {
+		return rest + time.Duration(excess/float64(cur)*float64(window))
+	}
+	return rest + window
+}
+
+func (rl *RateLimiter) tokenBucket(ctx context.Context, key string, n int, now time.Time) (RateLimitResult, error) {
+	if rl.Window <= 0 || rl.Limit <= 0 {
+		return RateLimitResult{}, errors.New("rate limit window and limit must be positive")
+	}
+	burst := rl.Burst
+	if burst <= 0 {
+		burst = rl.Limit
+	}
+	rate := float64(rl.Limit) / float64(rl.Window/time.Millisecond) // tokens per ms
+
+	ret, err := tokenBucketScript.Run(WithContext(ctx, rl.client), []string{rl.key(key)},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, now.UnixNano()/int64(time.Millisecond), n).Result()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	vals, ok := ret.([]interface{})
+	if !ok || len(vals) != 2 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script result %v", ret)
+	}
+	allowed, _ := vals[0].(int64)
+	s, _ := vals[1].(string)
+	tokens, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	res := RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     burst,
+		Remaining: int(tokens),
+	}
+	if !res.Allowed {
+		res.RetryAfter = time.Duration((float64(n) - tokens) / rate * float64(time.Millisecond))
+	}
+	return res, nil
+}
+
+// trustedProxies are the parsed REDIS_RATE_LIMIT_TRUSTED_PROXIES
+var trustedProxies []*net.IPNet
+
+// parseTrustedProxies parses the addresses and CIDRs of trusted proxies
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid REDIS_RATE_LIMIT_TRUSTED_PROXIES address %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_RATE_LIMIT_TRUSTED_PROXIES CIDR %q: %v", proxy, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// isTrustedProxy returns true if ip is a trusted proxy
+func isTrustedProxy(ip net.IP) bool {
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteAddrKey returns the address of the client of r. Use it as key of
+// the Middleware to limit the rate per IP. The X-Forwarded-For and
+// X-Real-Ip headers can be set by any caller, they are therefore only
+// used if the request was sent by a proxy configured in
+// REDIS_RATE_LIMIT_TRUSTED_PROXIES. In X-Forwarded-For the last address
+// that is not a trusted proxy is the client. Without trusted proxies the
+// address of the connection (r.RemoteAddr) is used.
+func RemoteAddrKey(r *http.Request) string {
+	mustSetup()
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isTrustedProxy(ip) {
+		return host
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		addresses := strings.Split(xff, ",")
+		for i := len(addresses) - 1; i >= 0; i-- {
+			client := net.ParseIP(strings.TrimSpace(addresses[i]))
+			if client == nil {
+				break // malformed, use the last trusted address
+			}
+			ip = client
+			if !isTrustedProxy(client) {
+				break
+			}
+		}
+		return ip.String()
+	}
+	if client := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); client != nil {
+		return client.String()
+	}
+	return host
+}
+
+// Middleware returns a middleware that limits the rate of requests per key
+// returned by keyFn, requests with an empty key are not limited. Denied
+// requests are answered with 429 Too Many Requests and a Retry-After header.
+// All responses get X-RateLimit-Limit and X-RateLimit-Remaining headers. If
+// redis is not available, the requests are allowed.
+func (rl *RateLimiter) Middleware(keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFn(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := rl.Allow(r.Context(), key)
+			if err != nil {
+				log.Req(r).Warn().Err(err).Str("limiter", rl.Name).Msg("Failed to check rate limit")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				log.Req(r).Debug().Str("limiter", rl.Name).Str("key", key).Msg("Rate limit exceeded")
+				runtime.WriteError(w, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
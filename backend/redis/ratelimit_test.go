@@ -0,0 +1,130 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, algorithm := range []RateLimitAlgorithm{SlidingWindow, TokenBucket} {
+		rl := NewRateLimiter(client, "TestRateLimiter", 3, time.Minute)
+		rl.Algorithm = algorithm
+		key := time.Now().String()
+
+		for i := 0; i < 3; i++ {
+			res, err := rl.Allow(ctx, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 2-i, res.Remaining)
+		}
+
+		res, err := rl.Allow(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.False(t, res.Allowed)
+		assert.True(t, res.RetryAfter > 0 && res.RetryAfter <= 2*time.Minute, res.RetryAfter)
+
+		// other keys are limited independently
+		res, err = rl.Allow(ctx, key+"other")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, res.Allowed)
+	}
+}
+
+func TestRateLimiterMiddlewareUnavailable(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1})
+	rl := NewRateLimiter(client, "TestRateLimiterMiddlewareUnavailable", 1, time.Minute)
+
+	labels := prometheus.Labels{"name": rl.Name, "result": rateLimitError}
+	failed := counterValue(t, paceRedisRateLimitTotal.With(labels))
+
+	h := rl.Middleware(RemoteAddrKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// requests are allowed if redis is not available
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, failed+1, counterValue(t, paceRedisRateLimitTotal.With(labels)))
+}
+
+func TestSlidingWindowRetryAfter(t *testing.T) {
+	window := time.Minute
+	cases := []struct {
+		name      string
+		cur, prev int64
+		weight    float64
+		expected  time.Duration
+	}{
+		// 10 of the previous window weighted by 0.5 count 5, 1 needs to decay
+		{"previous window", 5, 10, 0.5, 6 * time.Second},
+		// the previous window is empty, the current window needs to decay
+		{"current window", 10, 0, 0.5, 30*time.Second + 6*time.Second},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, slidingWindowRetryAfter(10, 1, c.cur, c.prev, c.weight, window))
+		})
+	}
+}
+
+func TestRemoteAddrKey(t *testing.T) {
+	mustSetup()
+	defer func(proxies []*net.IPNet) { trustedProxies = proxies }(trustedProxies)
+	var err error
+	trustedProxies, err = parseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		remoteAddr, xff, realIP, key string
+	}{
+		{"203.0.113.7:1234", "", "", "203.0.113.7"},
+		// headers of untrusted callers are ignored
+		{"203.0.113.7:1234", "198.51.100.1", "198.51.100.2", "203.0.113.7"},
+		// the last address that is not a trusted proxy is the client
+		{"10.1.2.3:1234", "198.51.100.9, 198.51.100.1, 192.0.2.1", "", "198.51.100.1"},
+		{"192.0.2.1:1234", "", "198.51.100.2", "198.51.100.2"},
+		{"10.1.2.3:1234", "", "", "10.1.2.3"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = c.remoteAddr
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		if c.realIP != "" {
+			req.Header.Set("X-Real-Ip", c.realIP)
+		}
+		assert.Equal(t, c.key, RemoteAddrKey(req), c)
+	}
+
+	_, err = parseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = parseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
@@ -7,18 +7,28 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/caarlos0/env"
 	"github.com/go-redis/redis"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/debugtrace"
+	"github.com/pace/bricks/maintenance/metric"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/pace/bricks/maintenance/log"
 )
 
 type config struct {
+	// Mode is standalone, sentinel or cluster
+	Mode               string        `env:"REDIS_MODE" envDefault:"standalone"`
+	SentinelMaster     string        `env:"REDIS_SENTINEL_MASTER"`
+	SentinelAddrs      []string      `env:"REDIS_SENTINEL_ADDRS" envSeparator:","`
 	Addrs              []string      `env:"REDIS_HOSTS" envSeparator:"," envDefault:"redis:6379"`
 	Password           string        `env:"REDIS_PASSWORD"`
 	DB                 int           `env:"REDIS_DB"`
@@ -34,26 +44,39 @@ type config struct {
 	PoolTimeout        time.Duration `env:"REDIS_POOL_TIMEOUT"`
 	IdleTimeout        time.Duration `env:"REDIS_IDLE_TIMEOUT"`
 	IdleCheckFrequency time.Duration `env:"REDIS_IDLE_CHECK_FREQUENCY"`
+	MinVersion         string        `env:"REDIS_MIN_VERSION"`
+	// Cluster mode options
+	ClusterReadOnly       bool `env:"REDIS_CLUSTER_READ_ONLY"`
+	ClusterRouteByLatency bool `env:"REDIS_CLUSTER_ROUTE_BY_LATENCY"`
+	ClusterMaxRedirects   int  `env:"REDIS_CLUSTER_MAX_REDIRECTS"`
+	// Commands that take longer are logged with warn level.
+	// 0 disables slow command logging.
+	SlowCommandThreshold time.Duration `env:"REDIS_SLOW_COMMAND_THRESHOLD" envDefault:"100ms"`
+	// Maximum duration of the health check (PING).
+	HealthCheckTimeout time.Duration `env:"REDIS_HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	// Addresses or CIDRs of proxies whose forwarded headers
+	// are used by RemoteAddrKey.
+	RateLimitTrustedProxies []string `env:"REDIS_RATE_LIMIT_TRUSTED_PROXIES" envSeparator:","`
 }
 
 var (
 	paceRedisCmdTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pace_redis_cmd_total",
+			Name: metric.Name("redis_cmd_total"),
 			Help: "Collects stats about the number of redis requests made",
 		},
 		[]string{"method"},
 	)
 	paceRedisCmdFailed = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pace_redis_cmd_failed",
+			Name: metric.Name("redis_cmd_failed"),
 			Help: "Collects stats about the number of redis requests failed",
 		},
 		[]string{"method"},
 	)
 	paceRedisCmdDurationSeconds = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pace_redis_cmd_duration_seconds",
+			Name:    metric.Name("redis_cmd_duration_seconds"),
 			Help:    "Collect performance metrics for each method",
 			Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 60},
 		},
@@ -61,23 +84,52 @@ var (
 	)
 )
 
-var cfg config
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
 
 func init() {
 	prometheus.MustRegister(paceRedisCmdTotal)
 	prometheus.MustRegister(paceRedisCmdFailed)
 	prometheus.MustRegister(paceRedisCmdDurationSeconds)
+	configcheck.Register("redis", Setup)
+}
 
-	// parse log config
-	err := env.Parse(&cfg)
-	if err != nil {
+// Setup parses and validates the REDIS_* environment of the
+// clients and the rate limit key (mode and trusted proxies), see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+		if errSetup == nil {
+			errSetup = validateMode(&cfg)
+		}
+		if errSetup == nil {
+			trustedProxies, errSetup = parseTrustedProxies(cfg.RateLimitTrustedProxies)
+		}
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
 		log.Fatalf("Failed to parse redis environment: %v", err)
 	}
 }
 
-// Client with environment based configuration
+// Client with environment based configuration, in sentinel mode
+// the failover client is returned (see FailoverClient). In cluster mode
+// the process exits, use ClusterClient or UniversalClient instead.
 func Client() *redis.Client {
-	return CustomClient(&redis.Options{
+	mustSetup()
+	switch cfg.Mode {
+	case modeSentinel:
+		return FailoverClient()
+	case modeCluster:
+		log.Fatalf("Redis is configured in cluster mode (REDIS_MODE), use redis.ClusterClient or redis.UniversalClient")
+	}
+	client := CustomClient(&redis.Options{
 		Addr:               cfg.Addrs[0],
 		Password:           cfg.Password,
 		DB:                 cfg.DB,
@@ -94,6 +146,10 @@ func Client() *redis.Client {
 		IdleTimeout:        cfg.IdleTimeout,
 		IdleCheckFrequency: cfg.IdleCheckFrequency,
 	})
+	if cfg.MinVersion != "" {
+		RegisterMinVersion(client, cfg.MinVersion)
+	}
+	return client
 }
 
 // CustomClient with passed configuration
@@ -105,8 +161,12 @@ func CustomClient(opts *redis.Options) *redis.Client {
 
 // ClusterClient with environment based configuration
 func ClusterClient() *redis.ClusterClient {
-	return CustomClusterClient(&redis.ClusterOptions{
+	mustSetup()
+	client := CustomClusterClient(&redis.ClusterOptions{
 		Addrs:              cfg.Addrs,
+		ReadOnly:           cfg.ClusterReadOnly,
+		RouteByLatency:     cfg.ClusterRouteByLatency,
+		MaxRedirects:       cfg.ClusterMaxRedirects,
 		Password:           cfg.Password,
 		MaxRetries:         cfg.MaxRetries,
 		MinRetryBackoff:    cfg.MinRetryBackoff,
@@ -121,6 +181,10 @@ func ClusterClient() *redis.ClusterClient {
 		IdleTimeout:        cfg.IdleTimeout,
 		IdleCheckFrequency: cfg.IdleCheckFrequency,
 	})
+	if cfg.MinVersion != "" {
+		RegisterClusterMinVersion(client, cfg.MinVersion)
+	}
+	return client
 }
 
 // CustomClusterClient with passed configuration
@@ -130,66 +194,145 @@ func CustomClusterClient(opts *redis.ClusterOptions) *redis.ClusterClient {
 	return redis.NewClusterClient(opts)
 }
 
-// WithContext adds a logging and tracing wrapper to the passed client
+// WithContext adds a logging, tracing and metrics wrapper to the passed
+// client, the commands and pipelines of the returned client use ctx
 func WithContext(ctx context.Context, c *redis.Client) *redis.Client {
+	mustSetup()
 	c = c.WithContext(ctx)
-	c.WrapProcess((&logtracer{ctx}).handle)
+	lt := &logtracer{ctx: ctx, addr: c.Options().Addr}
+	c.WrapProcess(lt.handle)
+	c.WrapProcessPipeline(lt.handlePipeline)
 	return c
 }
 
-// WithClusterContext adds a logging and tracing wrapper to the passed client
+// WithClusterContext adds a logging, tracing and metrics wrapper to the
+// passed client, the commands and pipelines of the returned client use ctx
 func WithClusterContext(ctx context.Context, c *redis.ClusterClient) *redis.ClusterClient {
+	mustSetup()
 	c = c.WithContext(ctx)
-	c.WrapProcess((&logtracer{ctx}).handle)
+	lt := &logtracer{ctx: ctx, addr: strings.Join(c.Options().Addrs, ",")}
+	c.WrapProcess(lt.handle)
+	c.WrapProcessPipeline(lt.handlePipeline)
 	return c
 }
 
 type logtracer struct {
-	ctx context.Context
+	ctx  context.Context
+	addr string
 }
 
 func (lt *logtracer) handle(realProcess func(redis.Cmder) error) func(redis.Cmder) error {
 	return func(cmder redis.Cmder) error {
-		// check if log context is given
-		var logger *zerolog.Logger
-		if lt.ctx != nil {
-			logger = log.Ctx(lt.ctx)
-		} else {
-			logger = log.Logger()
-		}
-
-		// logging prep and tracing
-		le := logger.Debug().Str("cmd", cmder.Name())
-		startTime := time.Now()
 		span, _ := opentracing.StartSpanFromContext(lt.ctx,
 			fmt.Sprintf("Redis: %s", cmder.Name()))
-		span.LogFields(olog.String("cmd", cmder.Name()))
 		defer span.Finish()
-
-		paceRedisCmdTotal.With(prometheus.Labels{
-			"method": cmder.Name(),
-		}).Inc()
+		lt.tag(span)
+		span.LogFields(olog.String("cmd", cmder.Name()))
 
 		// execute redis command
+		startTime := time.Now()
 		err := realProcess(cmder)
+		elapsed := time.Since(startTime)
 
-		// add error
-		if err != nil {
-			span.LogFields(olog.Error(err))
-			le = le.Err(err)
-			paceRedisCmdFailed.With(prometheus.Labels{
-				"method": cmder.Name(),
-			}).Inc()
+		lt.count(span, cmder.Name(), err)
+		paceRedisCmdDurationSeconds.With(prometheus.Labels{
+			"method": cmder.Name(),
+		}).Observe(elapsed.Seconds())
+
+		le := lt.logEvent(elapsed, err).Str("cmd", cmder.Name())
+		le.Msg("Redis query")
+		debugtrace.Record(lt.ctx, "redis", cmder.Name(), startTime, elapsed, err)
+
+		return err
+	}
+}
+
+// handlePipeline traces the pipeline with a single span, the commands
+// are counted individually, the duration is collected as "pipeline"
+func (lt *logtracer) handlePipeline(realProcess func([]redis.Cmder) error) func([]redis.Cmder) error {
+	return func(cmds []redis.Cmder) error {
+		span, _ := opentracing.StartSpanFromContext(lt.ctx, "Redis: pipeline")
+		defer span.Finish()
+		lt.tag(span)
+
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.Name()
 		}
+		span.LogFields(olog.String("cmds", strings.Join(names, " ")))
 
-		// do log statement
-		dur := float64(time.Since(startTime)) / float64(time.Millisecond)
-		le.Float64("duration", dur).Msg("Redis query")
+		// execute redis commands
+		startTime := time.Now()
+		err := realProcess(cmds)
+		elapsed := time.Since(startTime)
 
+		for _, cmd := range cmds {
+			lt.count(span, cmd.Name(), cmd.Err())
+		}
 		paceRedisCmdDurationSeconds.With(prometheus.Labels{
-			"method": cmder.Name(),
-		}).Observe(dur)
+			"method": "pipeline",
+		}).Observe(elapsed.Seconds())
+
+		le := lt.logEvent(elapsed, err).Strs("cmds", names)
+		le.Msg("Redis pipeline")
+		debugtrace.Record(lt.ctx, "redis", "pipeline "+strings.Join(names, " "), startTime, elapsed, err)
 
 		return err
 	}
 }
+
+// tag adds the database tags to the span
+func (lt *logtracer) tag(span opentracing.Span) {
+	ext.DBType.Set(span, "redis")
+	if lt.addr != "" {
+		ext.PeerAddress.Set(span, lt.addr)
+	}
+}
+
+// count counts the executed command and its failure, redis.Nil
+// (e.g. missing key) is a result and not counted as failure
+func (lt *logtracer) count(span opentracing.Span, name string, err error) {
+	paceRedisCmdTotal.With(prometheus.Labels{
+		"method": name,
+	}).Inc()
+
+	if failed(err) {
+		span.LogFields(olog.String("cmd", name), olog.Error(err))
+		paceRedisCmdFailed.With(prometheus.Labels{
+			"method": name,
+		}).Inc()
+	}
+}
+
+// logEvent returns the log event of the executed command with
+// debug level, warn level for slow commands (see
+// REDIS_SLOW_COMMAND_THRESHOLD) and error level for failed commands
+func (lt *logtracer) logEvent(elapsed time.Duration, err error) *zerolog.Event {
+	// check if log context is given
+	var logger *zerolog.Logger
+	if lt.ctx != nil {
+		logger = log.Ctx(lt.ctx)
+	} else {
+		logger = log.Logger()
+	}
+
+	level := zerolog.DebugLevel
+	switch {
+	case failed(err):
+		level = zerolog.ErrorLevel
+	case cfg.SlowCommandThreshold > 0 && elapsed >= cfg.SlowCommandThreshold:
+		level = zerolog.WarnLevel
+	}
+
+	le := logger.WithLevel(level).
+		Float64("duration", float64(elapsed)/float64(time.Millisecond))
+	if failed(err) {
+		le = le.Err(err)
+	}
+	return le
+}
+
+// failed returns true if err is a failure of the command
+func failed(err error) bool {
+	return err != nil && err != redis.Nil
+}
@@ -5,7 +5,12 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"testing"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestRedisClient(t *testing.T) {
@@ -17,3 +22,52 @@ func TestRedisClusterClient(t *testing.T) {
 	c := WithClusterContext(context.Background(), ClusterClient())
 	c.Ping()
 }
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestLogtracerCounts(t *testing.T) {
+	lt := &logtracer{ctx: context.Background(), addr: "localhost:6379"}
+	labels := prometheus.Labels{"method": "hgetall"}
+	total := counterValue(t, paceRedisCmdTotal.With(labels))
+	failed := counterValue(t, paceRedisCmdFailed.With(labels))
+
+	// a missing key is a result not a failure
+	process := lt.handle(func(cmd redis.Cmder) error { return redis.Nil })
+	if err := process(redis.NewStringStringMapCmd("hgetall", "key")); err != redis.Nil {
+		t.Errorf("Expected redis.Nil, got: %v", err)
+	}
+	process = lt.handle(func(cmd redis.Cmder) error { return errors.New("connection refused") })
+	process(redis.NewStringStringMapCmd("hgetall", "key")) // nolint: errcheck
+
+	if v := counterValue(t, paceRedisCmdTotal.With(labels)) - total; v != 2 {
+		t.Errorf("Expected 2 commands, got: %v", v)
+	}
+	if v := counterValue(t, paceRedisCmdFailed.With(labels)) - failed; v != 1 {
+		t.Errorf("Expected 1 failed command, got: %v", v)
+	}
+}
+
+func TestLogtracerPipeline(t *testing.T) {
+	lt := &logtracer{ctx: context.Background()}
+	labels := prometheus.Labels{"method": "incrby"}
+	total := counterValue(t, paceRedisCmdTotal.With(labels))
+
+	process := lt.handlePipeline(func(cmds []redis.Cmder) error { return nil })
+	err := process([]redis.Cmder{
+		redis.NewIntCmd("incrby", "a", 1),
+		redis.NewIntCmd("incrby", "b", 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := counterValue(t, paceRedisCmdTotal.With(labels)) - total; v != 2 {
+		t.Errorf("Expected 2 commands, got: %v", v)
+	}
+}
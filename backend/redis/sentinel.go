@@ -0,0 +1,193 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// modes of the environment based configuration
+const (
+	modeStandalone = "standalone"
+	modeSentinel   = "sentinel"
+	modeCluster    = "cluster"
+)
+
+// sentinelEvents are the events of the sentinels that are logged
+var sentinelEvents = []string{
+	"+switch-master",
+	"+sdown", "-sdown",
+	"+odown", "-odown",
+	"+failover-end",
+	"+failover-end-for-timeout",
+}
+
+// sentinelRetryInterval after which the next sentinel is subscribed
+// if the subscription failed
+var sentinelRetryInterval = time.Second
+
+var paceRedisSentinelEventTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("redis_sentinel_event_total"),
+		Help: "Collects stats about the number of sentinel events (failovers, instances down) partitioned by master and event",
+	},
+	[]string{"master", "event"},
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisSentinelEventTotal)
+}
+
+// validateMode returns an error if the mode of the config
+// is unknown or incomplete
+func validateMode(c *config) error {
+	switch c.Mode {
+	case modeStandalone, modeCluster:
+		return nil
+	case modeSentinel:
+		if c.SentinelMaster == "" || len(c.SentinelAddrs) == 0 {
+			return fmt.Errorf("redis sentinel mode requires REDIS_SENTINEL_MASTER and REDIS_SENTINEL_ADDRS")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown redis mode %q", c.Mode)
+}
+
+// FailoverClient with environment based configuration, the
+// master is discovered using the sentinels
+func FailoverClient() *redis.Client {
+	mustSetup()
+	client := CustomFailoverClient(&redis.FailoverOptions{
+		MasterName:         cfg.SentinelMaster,
+		SentinelAddrs:      cfg.SentinelAddrs,
+		Password:           cfg.Password,
+		DB:                 cfg.DB,
+		MaxRetries:         cfg.MaxRetries,
+		MinRetryBackoff:    cfg.MinRetryBackoff,
+		MaxRetryBackoff:    cfg.MaxRetryBackoff,
+		DialTimeout:        cfg.DialTimeout,
+		ReadTimeout:        cfg.ReadTimeout,
+		WriteTimeout:       cfg.WriteTimeout,
+		PoolSize:           cfg.PoolSize,
+		MinIdleConns:       cfg.MinIdleConns,
+		MaxConnAge:         cfg.MaxConnAge,
+		PoolTimeout:        cfg.PoolTimeout,
+		IdleTimeout:        cfg.IdleTimeout,
+		IdleCheckFrequency: cfg.IdleCheckFrequency,
+	})
+	if cfg.MinVersion != "" {
+		RegisterMinVersion(client, cfg.MinVersion)
+	}
+	return client
+}
+
+var (
+	watchedMu sync.Mutex
+	watched   = make(map[string]bool) // master and sentinels
+)
+
+// CustomFailoverClient with passed configuration, the events of the
+// sentinels (failovers, instances down) are logged and collected in
+// the pace_redis_sentinel_event_total metric. The sentinels of a master
+// are watched once per process, regardless of the number of clients.
+func CustomFailoverClient(opts *redis.FailoverOptions) *redis.Client {
+	log.Logger().Info().Str("master", opts.MasterName).Strs("sentinels", opts.SentinelAddrs).
+		Msg("Redis failover connection pool created")
+
+	addrs := append([]string(nil), opts.SentinelAddrs...)
+	sort.Strings(addrs)
+	key := opts.MasterName + " " + strings.Join(addrs, ",")
+	watchedMu.Lock()
+	if !watched[key] {
+		watched[key] = true
+		go watchSentinels(opts.MasterName, addrs)
+	}
+	watchedMu.Unlock()
+
+	return redis.NewFailoverClient(opts)
+}
+
+// UniversalClient with environment based configuration, returns a
+// cluster client in cluster mode, a failover client in sentinel mode
+// and a client otherwise
+func UniversalClient() redis.UniversalClient {
+	mustSetup()
+	switch cfg.Mode {
+	case modeCluster:
+		return ClusterClient()
+	case modeSentinel:
+		return FailoverClient()
+	}
+	return Client()
+}
+
+// watchSentinels subscribes to the events of one of the sentinels,
+// the next sentinel is used if the subscription fails
+func watchSentinels(master string, addrs []string) {
+	for i := 0; len(addrs) > 0; i++ {
+		addr := addrs[i%len(addrs)]
+		err := watchSentinel(master, addr)
+		log.Logger().Warn().Err(err).Str("master", master).Str("sentinel", addr).
+			Msg("Redis sentinel subscription failed")
+		time.Sleep(sentinelRetryInterval)
+	}
+}
+
+// watchSentinel handles the events of the sentinel until
+// the subscription fails
+func watchSentinel(master, addr string) error {
+	sentinel := redis.NewSentinelClient(&redis.Options{Addr: addr})
+	defer sentinel.Close() // nolint: errcheck
+
+	pubsub := sentinel.PubSub()
+	defer pubsub.Close() // nolint: errcheck
+	err := pubsub.Subscribe(sentinelEvents...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			return err
+		}
+		handleSentinelEvent(master, msg.Channel, msg.Payload)
+	}
+}
+
+// handleSentinelEvent logs and counts the event if it affects master.
+// The payload has the format "<type> <name> <ip> <port>" for masters and
+// "<type> <name> <ip> <port> @ <master> <ip> <port>" for other instances,
+// "<master> <old-ip> <old-port> <new-ip> <new-port>" for +switch-master.
+func handleSentinelEvent(master, event, payload string) bool {
+	parts := strings.Fields(payload)
+
+	var le *zerolog.Event
+	switch {
+	case event == "+switch-master" && len(parts) == 5 && parts[0] == master:
+		le = log.Logger().Error().
+			Str("old", parts[1]+":"+parts[2]).
+			Str("new", parts[3]+":"+parts[4])
+	case len(parts) >= 4 && parts[0] == "master" && parts[1] == master:
+		le = log.Logger().Warn().Str("instance", "master").Str("addr", parts[2]+":"+parts[3])
+	case len(parts) >= 6 && parts[4] == "@" && parts[5] == master:
+		le = log.Logger().Warn().Str("instance", parts[0]).Str("addr", parts[2]+":"+parts[3])
+	default:
+		return false
+	}
+
+	paceRedisSentinelEventTotal.With(prometheus.Labels{"master": master, "event": event}).Inc()
+	le.Str("master", master).Str("event", event).Msg("Redis sentinel event")
+	return true
+}
@@ -0,0 +1,75 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMode(t *testing.T) {
+	cases := []struct {
+		name  string
+		c     config
+		valid bool
+	}{
+		{"standalone", config{Mode: modeStandalone}, true},
+		{"cluster", config{Mode: modeCluster}, true},
+		{"sentinel", config{Mode: modeSentinel, SentinelMaster: "mymaster", SentinelAddrs: []string{"sentinel:26379"}}, true},
+		{"sentinel without master", config{Mode: modeSentinel, SentinelAddrs: []string{"sentinel:26379"}}, false},
+		{"sentinel without addrs", config{Mode: modeSentinel, SentinelMaster: "mymaster"}, false},
+		{"unknown", config{Mode: "ring"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateMode(&c.c)
+			assert.Equal(t, c.valid, err == nil, err)
+		})
+	}
+}
+
+func TestHandleSentinelEvent(t *testing.T) {
+	cases := []struct {
+		event, payload string
+		handled        bool
+	}{
+		{"+switch-master", "mymaster 10.0.0.1 6379 10.0.0.2 6379", true},
+		{"+switch-master", "othermaster 10.0.0.1 6379 10.0.0.2 6379", false},
+		{"+sdown", "master mymaster 10.0.0.1 6379", true},
+		{"+odown", "master mymaster 10.0.0.1 6379 #quorum 2/2", true},
+		{"+sdown", "slave 10.0.0.3:6379 10.0.0.3 6379 @ mymaster 10.0.0.1 6379", true},
+		{"-sdown", "sentinel 10.0.0.4:26379 10.0.0.4 26379 @ othermaster 10.0.0.1 6379", false},
+		{"+sdown", "invalid", false},
+	}
+	for _, c := range cases {
+		labels := prometheus.Labels{"master": "mymaster", "event": c.event}
+		before := counterValue(t, paceRedisSentinelEventTotal.With(labels))
+
+		assert.Equal(t, c.handled, handleSentinelEvent("mymaster", c.event, c.payload), c.payload)
+
+		expected := before
+		if c.handled {
+			expected++
+		}
+		assert.Equal(t, expected, counterValue(t, paceRedisSentinelEventTotal.With(labels)), c.payload)
+	}
+}
+
+func TestCustomFailoverClientWatchesOnce(t *testing.T) {
+	watchedMu.Lock()
+	before := len(watched)
+	watchedMu.Unlock()
+
+	for _, addrs := range [][]string{{"127.0.0.1:1", "127.0.0.1:2"}, {"127.0.0.1:2", "127.0.0.1:1"}} {
+		client := CustomFailoverClient(&redis.FailoverOptions{MasterName: "watchonce", SentinelAddrs: addrs})
+		defer client.Close() // nolint: errcheck
+	}
+
+	watchedMu.Lock()
+	defer watchedMu.Unlock()
+	assert.Equal(t, before+1, len(watched))
+}
@@ -0,0 +1,308 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of stream messages, used as metric label
+const (
+	streamProcessed    = "processed"
+	streamFailed       = "failed"
+	streamClaimed      = "claimed"
+	streamDeadLettered = "dead_lettered"
+)
+
+var (
+	paceRedisStreamMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("redis_stream_messages_total"),
+			Help: "Collects stats about the number of stream messages partitioned by stream, group and result",
+		},
+		[]string{"stream", "group", "result"},
+	)
+	paceRedisStreamProcessingDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("redis_stream_processing_duration_seconds"),
+			Help:    "Collect performance metrics for the handling of stream messages",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"stream", "group"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceRedisStreamMessagesTotal)
+	prometheus.MustRegister(paceRedisStreamProcessingDurationSeconds)
+}
+
+// StreamHandler handles a message of a stream, the message is
+// acknowledged if no error is returned
+type StreamHandler func(ctx context.Context, msg redis.XMessage) error
+
+// AddToStream adds a message with the passed values to the stream
+// and returns the id of the message
+func AddToStream(ctx context.Context, client *redis.Client, stream string, values map[string]interface{}) (string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Redis: add %s", stream))
+	defer span.Finish()
+	ext.DBType.Set(span, "redis")
+
+	id, err := client.XAdd(&redis.XAddArgs{Stream: stream, Values: values}).Result()
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		return "", err
+	}
+	span.LogFields(olog.String("id", id))
+	return id, nil
+}
+
+// ConsumerGroup consumes the messages of a stream as member of a
+// group, every message is handled by one consumer of the group.
+// Messages that are not acknowledged, because the handler failed or the
+// consumer crashed, are claimed and handled again after MinIdle. After
+// MaxAttempts they are moved to the dead letter stream.
+type ConsumerGroup struct {
+	// Stream that is consumed
+	Stream string
+	// Group of the consumer, created if it doesn't exist
+	Group string
+	// Consumer name, defaults to the hostname
+	Consumer string
+	// Count of messages read at once. Defaults to 10.
+	Count int64
+	// Block time waiting for new messages, needs to be lower than the
+	// read timeout of the client. Defaults to 1s.
+	Block time.Duration
+	// MinIdle time after which unacknowledged messages are claimed.
+	// Defaults to 1m.
+	MinIdle time.Duration
+	// MaxAttempts to handle a message before it is moved to the
+	// dead letter stream. Defaults to 5.
+	MaxAttempts int64
+	// DeadLetterStream the failed messages are moved to,
+	// defaults to the stream with suffix ":dead".
+	DeadLetterStream string
+
+	client *redis.Client
+}
+
+// NewConsumerGroup creates a consumer group for the stream
+// using the passed client
+func NewConsumerGroup(client *redis.Client, stream, group string) *ConsumerGroup {
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = fmt.Sprintf("consumer-%d", os.Getpid())
+	}
+	return &ConsumerGroup{
+		Stream:           stream,
+		Group:            group,
+		Consumer:         consumer,
+		Count:            10,
+		Block:            time.Second,
+		MinIdle:          time.Minute,
+		MaxAttempts:      5,
+		DeadLetterStream: stream + ":dead",
+		client:           client,
+	}
+}
+
+// Run creates the group if needed and passes the messages to h until
+// ctx is done. An error is returned if the group can't be created.
+func (g *ConsumerGroup) Run(ctx context.Context, h StreamHandler) error {
+	err := g.createGroup()
+	if err != nil {
+		return err
+	}
+
+	var lastClaim time.Time
+	failures := 0
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= g.MinIdle/2 {
+			lastClaim = time.Now()
+			err = g.claim(ctx, h)
+		}
+
+		if err == nil {
+			var streams []redis.XStream
+			streams, err = g.client.XReadGroup(&redis.XReadGroupArgs{
+				Group:    g.Group,
+				Consumer: g.Consumer,
+				Streams:  []string{g.Stream, ">"},
+				Count:    g.Count,
+				Block:    g.Block,
+			}).Result()
+			if err == redis.Nil {
+				err = nil // no new messages
+			}
+			for _, s := range streams {
+				for _, msg := range s.Messages {
+					g.handle(ctx, h, msg)
+				}
+			}
+		}
+
+		if err != nil {
+			failures++
+			log.Ctx(ctx).Warn().Err(err).Str("stream", g.Stream).Str("group", g.Group).
+				Msg("Failed to read redis stream")
+			select {
+			case <-ctx.Done():
+			case <-time.After(resubscribeBackoff(failures)):
+			}
+			err = nil
+			continue
+		}
+		failures = 0
+	}
+	return nil
+}
+
+// createGroup creates the group (and stream) starting
+// with new messages, existing groups are kept
+func (g *ConsumerGroup) createGroup() error {
+	err := g.client.Do("xgroup", "create", g.Stream, g.Group, "$", "mkstream").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// claim handles the messages that are pending longer than MinIdle, messages
+// that exceeded MaxAttempts are moved to the dead letter stream
+func (g *ConsumerGroup) claim(ctx context.Context, h StreamHandler) error {
+	pending, err := g.client.XPendingExt(&redis.XPendingExtArgs{
+		Stream: g.Stream,
+		Group:  g.Group,
+		Start:  "-",
+		End:    "+",
+		Count:  g.Count,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, p := range pending {
+		if p.Idle < g.MinIdle {
+			continue
+		}
+		if p.RetryCount >= g.MaxAttempts {
+			err = g.deadLetter(ctx, p)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		ids = append(ids, p.Id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	msgs, err := g.client.XClaim(&redis.XClaimArgs{
+		Stream:   g.Stream,
+		Group:    g.Group,
+		Consumer: g.Consumer,
+		MinIdle:  g.MinIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		paceRedisStreamMessagesTotal.With(g.labels(streamClaimed)).Inc()
+		g.handle(ctx, h, msg)
+	}
+	return nil
+}
+
+// deadLetter moves the pending message to the dead letter stream, the
+// origin of the message is added to its values
+func (g *ConsumerGroup) deadLetter(ctx context.Context, p redis.XPendingExt) error {
+	msgs, err := g.client.XRange(g.Stream, p.Id, p.Id).Result()
+	if err != nil {
+		return err
+	}
+
+	// the message might have been deleted from the stream
+	if len(msgs) == 1 {
+		values := deadLetterValues(msgs[0], g.Stream, g.Group, p.RetryCount)
+		err = g.client.XAdd(&redis.XAddArgs{Stream: g.DeadLetterStream, Values: values}).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = g.client.XAck(g.Stream, g.Group, p.Id).Err()
+	if err != nil {
+		return err
+	}
+	paceRedisStreamMessagesTotal.With(g.labels(streamDeadLettered)).Inc()
+	log.Ctx(ctx).Error().Str("stream", g.Stream).Str("group", g.Group).Str("id", p.Id).
+		Int64("attempts", p.RetryCount).Msg("Redis stream message moved to dead letter stream")
+	return nil
+}
+
+// handle passes the message to the handler with a span and
+// acknowledges it on success, panics of the handler are recovered
+func (g *ConsumerGroup) handle(ctx context.Context, h StreamHandler, msg redis.XMessage) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("Redis: consume %s", g.Stream))
+	defer span.Finish()
+	ext.DBType.Set(span, "redis")
+	span.LogFields(olog.String("group", g.Group), olog.String("id", msg.ID))
+
+	start := time.Now()
+	result := streamFailed
+	defer func() {
+		paceRedisStreamProcessingDurationSeconds.With(prometheus.Labels{"stream": g.Stream, "group": g.Group}).
+			Observe(float64(time.Since(start)) / float64(time.Second))
+		paceRedisStreamMessagesTotal.With(g.labels(result)).Inc()
+	}()
+	defer errors.HandleWithCtx(ctx, "redis stream consumer "+g.Stream)
+
+	err := h(ctx, msg)
+	if err == nil {
+		err = g.client.XAck(g.Stream, g.Group, msg.ID).Err()
+	}
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+		log.Ctx(ctx).Warn().Err(err).Str("stream", g.Stream).Str("id", msg.ID).Msg("Failed to handle redis stream message")
+		return
+	}
+	result = streamProcessed
+}
+
+func (g *ConsumerGroup) labels(result string) prometheus.Labels {
+	return prometheus.Labels{"stream": g.Stream, "group": g.Group, "result": result}
+}
+
+// deadLetterValues returns the values of the message with
+// its origin prefixed with "dead_letter_"
+func deadLetterValues(msg redis.XMessage, stream, group string, attempts int64) map[string]interface{} {
+	values := make(map[string]interface{}, len(msg.Values)+4)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["dead_letter_stream"] = stream
+	values["dead_letter_group"] = group
+	values["dead_letter_id"] = msg.ID
+	values["dead_letter_attempts"] = attempts
+	return values
+}
@@ -0,0 +1,81 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerGroup(t *testing.T) {
+	client := Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	stream := "bricks:test:stream"
+	client.Del(stream, stream+":dead")
+	defer client.Del(stream, stream+":dead")
+
+	g := NewConsumerGroup(client, stream, "test")
+	g.Block = 10 * time.Millisecond
+	g.MinIdle = 0
+	g.MaxAttempts = 2
+
+	attempts := make(map[string]int)
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		g.Run(ctx, func(ctx context.Context, msg redis.XMessage) error { // nolint: errcheck
+			attempts[msg.Values["name"].(string)]++
+			if msg.Values["name"] == "poison" {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}()
+
+	// wait for the group to be created
+	time.Sleep(50 * time.Millisecond)
+	for _, name := range []string{"ok", "poison"} {
+		_, err := AddToStream(ctx, client, stream, map[string]interface{}{"name": name})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var dead []redis.XMessage
+	for i := 0; i < 100 && len(dead) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		dead = client.XRange(stream+":dead", "-", "+").Val()
+	}
+	cancel()
+	<-done
+
+	if len(dead) != 1 {
+		t.Fatalf("Expected poison message in dead letter stream, got: %v", dead)
+	}
+	assert.Equal(t, "poison", dead[0].Values["name"])
+	assert.Equal(t, "2", dead[0].Values["dead_letter_attempts"])
+	assert.Equal(t, 1, attempts["ok"])
+	assert.Equal(t, 2, attempts["poison"])
+	assert.Empty(t, client.XPendingExt(&redis.XPendingExtArgs{Stream: stream, Group: "test", Start: "-", End: "+", Count: 10}).Val())
+}
+
+func TestDeadLetterValues(t *testing.T) {
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"name": "poison"}}
+	assert.Equal(t, map[string]interface{}{
+		"name":                 "poison",
+		"dead_letter_stream":   "events",
+		"dead_letter_group":    "workers",
+		"dead_letter_id":       "1-0",
+		"dead_letter_attempts": int64(5),
+	}, deadLetterValues(msg, "events", "workers", 5))
+	assert.Len(t, msg.Values, 1)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pace/bricks/internal/service"
 	"github.com/pace/bricks/internal/service/generate"
@@ -70,6 +71,23 @@ func addRootCommands(rootCmd *cobra.Command) {
 	}
 	cmdTest.Flags().BoolVar(&testGoConvey, "goconvey", false, "use goconvey for testing")
 	rootCmd.AddCommand(cmdTest)
+
+	var alertsPath string
+	alertsOptions := generate.AlertsOptions{}
+	cmdAlerts := &cobra.Command{
+		Use:   "alerts NAME",
+		Short: "Generates prometheus alerting rules for the service",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alertsOptions.Name = args[0]
+			generate.Alerts(alertsPath, alertsOptions)
+		},
+	}
+	cmdAlerts.Flags().StringVar(&alertsPath, "path", "", "path for the generated rules (default stdout)")
+	cmdAlerts.Flags().Float64Var(&alertsOptions.Availability, "availability", 99.9, "availability SLO in percent")
+	cmdAlerts.Flags().DurationVar(&alertsOptions.Latency, "latency", 500*time.Millisecond, "latency SLO (99th percentile)")
+	cmdAlerts.Flags().DurationVar(&alertsOptions.QueryLatency, "query-latency", 100*time.Millisecond, "postgres query latency threshold (95th percentile)")
+	rootCmd.AddCommand(cmdAlerts)
 }
 
 // pace service ...
@@ -158,23 +176,40 @@ func addServiceCommands(cmdService *cobra.Command) {
 
 // pace service generate ...
 func addServiceGenerateCommands(cmdServiceGenerate *cobra.Command) {
-	var pkgName, path, source string
+	var pkgName, path, source, dashboard string
 	cmdRest := &cobra.Command{
 		Use:  "rest",
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			generate.Rest(generate.RestOptions{
-				PkgName: pkgName,
-				Path:    path,
-				Source:  source,
+				PkgName:       pkgName,
+				Path:          path,
+				Source:        source,
+				DashboardPath: dashboard,
 			})
 		},
 	}
 	cmdRest.Flags().StringVar(&pkgName, "pkg", "", "name for the generated go package")
 	cmdRest.Flags().StringVar(&path, "path", "", "path for generated file")
 	cmdRest.Flags().StringVar(&source, "source", "", "OpenAPIv3 source to use for generation")
+	cmdRest.Flags().StringVar(&dashboard, "dashboard", "", "path for generated grafana dashboard (optional)")
 	cmdServiceGenerate.AddCommand(cmdRest)
 
+	var modelsOptions generate.ModelsOptions
+	cmdModels := &cobra.Command{
+		Use:   "models",
+		Short: "Generates go-pg models from the tables of the database (POSTGRES_* environment)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			generate.Models(modelsOptions)
+		},
+	}
+	cmdModels.Flags().StringVar(&modelsOptions.PkgName, "pkg", "models", "name for the generated go package")
+	cmdModels.Flags().StringVar(&modelsOptions.Path, "path", "", "path for generated file (default stdout)")
+	cmdModels.Flags().StringVar(&modelsOptions.Schema, "schema", "public", "schema of the tables")
+	cmdModels.Flags().StringSliceVar(&modelsOptions.Tables, "tables", nil, "tables to generate models for (default all)")
+	cmdServiceGenerate.AddCommand(cmdModels)
+
 	var commandsPath string
 	cmdCommands := &cobra.Command{
 		Use:  "commands NAME",
@@ -0,0 +1,291 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// Driver adapts the client to the grpc library of the service
+type Driver interface {
+	// Code returns the status code of the error returned
+	// by a call (e.g. status.Code of grpc-go)
+	Code(err error) Code
+	// WithMetadata returns ctx with md appended to the outgoing
+	// metadata (e.g. metadata.AppendToOutgoingContext of grpc-go)
+	WithMetadata(ctx context.Context, md Metadata) context.Context
+}
+
+// UnaryInvoker invokes the unary call with the passed
+// context, it is called again for retries
+type UnaryInvoker func(ctx context.Context) error
+
+// ClientStream is a stream of a streaming call
+type ClientStream interface {
+	SendMsg(m interface{}) error
+	// RecvMsg returns io.EOF after the stream ended successfully
+	RecvMsg(m interface{}) error
+	CloseSend() error
+}
+
+// StreamOpener opens the stream of a streaming call with the passed context
+type StreamOpener func(ctx context.Context) (ClientStream, error)
+
+// Options of a client
+type Options struct {
+	// Target of the connection (e.g. "dns:///payment:9000"),
+	// added to the spans and logs
+	Target string
+	// Driver of the grpc library, required
+	Driver Driver
+	// Timeout is the deadline of unary calls whose
+	// context has no deadline, 0 disables it
+	Timeout time.Duration
+	// MaxRetries of unary calls of the RetryMethods that
+	// failed with one of the RetryCodes
+	MaxRetries int
+	// RetryMethods are the full method names of the idempotent unary
+	// calls that are retried, other calls are never retried
+	RetryMethods []string
+	// RetryBackoff is the delay before the first retry, it
+	// is doubled for every further retry
+	RetryBackoff time.Duration
+	// RetryCodes that are retried, defaults to Unavailable
+	RetryCodes []Code
+}
+
+// Client instruments the calls of a connection, the unary and stream
+// client interceptors of the connection delegate to Unary and Stream
+type Client struct {
+	opts Options
+}
+
+// NewClient returns a client of the connection to target configured
+// by the environment, the environment is parsed by it (see Setup).
+// Only the calls of the passed idempotent methods are retried.
+func NewClient(target string, driver Driver, retryMethods ...string) *Client {
+	mustSetup()
+	return CustomClient(Options{
+		Target:       target,
+		Driver:       driver,
+		Timeout:      cfg.Timeout,
+		MaxRetries:   cfg.MaxRetries,
+		RetryBackoff: cfg.RetryBackoff,
+		RetryMethods: retryMethods,
+	})
+}
+
+// CustomClient returns a client with the passed options
+func CustomClient(opts Options) *Client {
+	if opts.Driver == nil {
+		panic("grpc: driver is required")
+	}
+	if len(opts.RetryCodes) == 0 {
+		opts.RetryCodes = []Code{Unavailable}
+	}
+	return &Client{opts: opts}
+}
+
+// Unary executes the unary call of the full method name (e.g.
+// "/payment.v1.PaymentService/Authorize"). The span context and the
+// request id are propagated using the metadata. A deadline is added
+// if ctx has none, calls of the retry methods that failed with a retry
+// code are retried until MaxRetries or the deadline.
+func (c *Client) Unary(ctx context.Context, method string, invoke UnaryInvoker) error {
+	span, ctx := c.startSpan(ctx, method)
+	defer span.Finish()
+
+	if _, ok := ctx.Deadline(); !ok && c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	var err error
+	idempotent := c.idempotent(method)
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err = invoke(ctx)
+		code := c.code(err)
+		c.observe(ctx, method, code, time.Since(start), attempt, err)
+
+		if code == OK || !idempotent || attempt >= c.opts.MaxRetries || !c.retryable(code) {
+			c.finishSpan(span, code, err)
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			c.finishSpan(span, code, err)
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		paceGRPCClientRetriesTotal.With(prometheus.Labels{"method": method}).Inc()
+	}
+}
+
+// Stream opens the stream of the full method name, the span context
+// and request id are propagated using the metadata. The call is
+// finished (span, metrics, log) once RecvMsg returns an error, so the
+// stream needs to be read until then.
+func (c *Client) Stream(ctx context.Context, method string, open StreamOpener) (ClientStream, error) {
+	span, ctx := c.startSpan(ctx, method)
+
+	start := time.Now()
+	cs, err := open(ctx)
+	if err != nil {
+		code := c.code(err)
+		c.observe(ctx, method, code, time.Since(start), 0, err)
+		c.finishSpan(span, code, err)
+		span.Finish()
+		return nil, err
+	}
+
+	return &clientStream{
+		ClientStream: cs,
+		client:       c,
+		ctx:          ctx,
+		span:         span,
+		method:       method,
+		start:        start,
+	}, nil
+}
+
+// startSpan starts the client span of the call and
+// propagates it with the request id using the metadata
+func (c *Client) startSpan(ctx context.Context, method string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gRPC: "+method)
+	ext.SpanKindRPCClient.Set(span)
+	ext.Component.Set(span, "grpc")
+	if c.opts.Target != "" {
+		ext.PeerAddress.Set(span, c.opts.Target)
+	}
+
+	md := make(Metadata)
+	err := span.Tracer().Inject(span.Context(), opentracing.TextMap, md)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Failed to inject span context into gRPC metadata")
+	}
+	if id := log.RequestIDFromContext(ctx); id != "" {
+		md.Set("request-id", id)
+	}
+	if len(md) > 0 {
+		ctx = c.opts.Driver.WithMetadata(ctx, md)
+	}
+	return span, ctx
+}
+
+func (c *Client) finishSpan(span opentracing.Span, code Code, err error) {
+	span.SetTag("grpc.code", code.String())
+	if code != OK {
+		ext.Error.Set(span, true)
+		span.LogFields(olog.Error(err))
+	}
+}
+
+// observe collects the metrics and logs the call with debug level,
+// failed calls with warn level
+func (c *Client) observe(ctx context.Context, method string, code Code, elapsed time.Duration, attempt int, err error) {
+	paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": code.String()}).Inc()
+	paceGRPCClientCallDurationSeconds.With(prometheus.Labels{"method": method}).Observe(elapsed.Seconds())
+
+	level := zerolog.DebugLevel
+	if code != OK {
+		level = zerolog.WarnLevel
+	}
+	le := log.Ctx(ctx).WithLevel(level).
+		Str("method", method).
+		Str("target", c.opts.Target).
+		Str("code", code.String()).
+		Float64("duration", float64(elapsed)/float64(time.Millisecond))
+	if attempt > 0 {
+		le = le.Int("attempt", attempt)
+	}
+	if err != nil {
+		le = le.Err(err)
+	}
+	le.Msg("gRPC call")
+}
+
+// code returns the status code of err, context errors
+// are mapped without the driver
+func (c *Client) code(err error) Code {
+	switch err {
+	case nil:
+		return OK
+	case context.DeadlineExceeded:
+		return DeadlineExceeded
+	case context.Canceled:
+		return Canceled
+	}
+	return c.opts.Driver.Code(err)
+}
+
+func (c *Client) idempotent(method string) bool {
+	for _, m := range c.opts.RetryMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) retryable(code Code) bool {
+	for _, rc := range c.opts.RetryCodes {
+		if rc == code {
+			return true
+		}
+	}
+	return false
+}
+
+// clientStream finishes the call once RecvMsg returns an error
+type clientStream struct {
+	ClientStream
+	client *Client
+	ctx    context.Context
+	span   opentracing.Span
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		paceGRPCClientStreamMsgsTotal.With(prometheus.Labels{"method": s.method, "direction": "sent"}).Inc()
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		paceGRPCClientStreamMsgsTotal.With(prometheus.Labels{"method": s.method, "direction": "received"}).Inc()
+		return nil
+	}
+
+	s.once.Do(func() {
+		code, cause := OK, error(nil)
+		if err != io.EOF {
+			code, cause = s.client.code(err), err
+		}
+		s.client.observe(s.ctx, s.method, code, time.Since(s.start), 0, cause)
+		s.client.finishSpan(s.span, code, cause)
+		s.span.Finish()
+	})
+	return err
+}
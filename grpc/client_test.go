@@ -0,0 +1,183 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusError is the error of a call with a status code
+type statusError Code
+
+func (e statusError) Error() string { return Code(e).String() }
+
+type testDriver struct {
+	md Metadata
+}
+
+func (d *testDriver) Code(err error) Code {
+	if se, ok := err.(statusError); ok {
+		return Code(se)
+	}
+	return Unknown
+}
+
+func (d *testDriver) WithMetadata(ctx context.Context, md Metadata) context.Context {
+	d.md = md
+	return ctx
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestUnaryRetry(t *testing.T) {
+	method := "/test.v1.Test/Retry"
+	c := CustomClient(Options{Driver: &testDriver{}, MaxRetries: 3, RetryBackoff: time.Millisecond, Timeout: time.Second,
+		RetryMethods: []string{method}})
+	unavailable := counterValue(t, paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": "Unavailable"}))
+
+	calls := 0
+	err := c.Unary(context.Background(), method, func(ctx context.Context) error {
+		calls++
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "expected deadline to be injected")
+		if calls < 3 {
+			return statusError(Unavailable)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, unavailable+2, counterValue(t, paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": "Unavailable"})))
+	assert.Equal(t, 2.0, counterValue(t, paceGRPCClientRetriesTotal.With(prometheus.Labels{"method": method})))
+
+	// not retryable
+	calls = 0
+	err = c.Unary(context.Background(), method, func(ctx context.Context) error {
+		calls++
+		return statusError(InvalidArgument)
+	})
+	assert.Equal(t, statusError(InvalidArgument), err)
+	assert.Equal(t, 1, calls)
+
+	// retries are limited
+	calls = 0
+	err = c.Unary(context.Background(), method, func(ctx context.Context) error {
+		calls++
+		return statusError(Unavailable)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+
+	// other methods are not retried
+	calls = 0
+	err = c.Unary(context.Background(), "/test.v1.Test/Create", func(ctx context.Context) error {
+		calls++
+		return statusError(Unavailable)
+	})
+	assert.Equal(t, statusError(Unavailable), err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestUnaryDeadline(t *testing.T) {
+	c := CustomClient(Options{Driver: &testDriver{}, MaxRetries: 5, RetryBackoff: time.Hour, Timeout: 10 * time.Millisecond,
+		RetryMethods: []string{"/test.v1.Test/Deadline"}})
+
+	// the existing deadline is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+	err := c.Unary(ctx, "/test.v1.Test/Deadline", func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		assert.Equal(t, expected, deadline)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// retries stop at the deadline
+	calls := 0
+	start := time.Now()
+	err = c.Unary(context.Background(), "/test.v1.Test/Deadline", func(ctx context.Context) error {
+		calls++
+		return statusError(Unavailable)
+	})
+	assert.Equal(t, statusError(Unavailable), err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+type testStream struct {
+	msgs []string
+	err  error
+}
+
+func (s *testStream) SendMsg(m interface{}) error { return nil }
+func (s *testStream) CloseSend() error            { return nil }
+func (s *testStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return s.err
+	}
+	*m.(*string) = s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func TestStream(t *testing.T) {
+	c := CustomClient(Options{Driver: &testDriver{}})
+	method := "/test.v1.Test/Stream"
+	ok := counterValue(t, paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": "OK"}))
+
+	cs, err := c.Stream(context.Background(), method, func(ctx context.Context) (ClientStream, error) {
+		return &testStream{msgs: []string{"a", "b"}, err: io.EOF}, nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, cs.SendMsg("req"))
+
+	var received []string
+	for {
+		var msg string
+		if err := cs.RecvMsg(&msg); err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+		received = append(received, msg)
+	}
+	assert.Equal(t, []string{"a", "b"}, received)
+	assert.Equal(t, ok+1, counterValue(t, paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": "OK"})))
+	assert.Equal(t, 2.0, counterValue(t, paceGRPCClientStreamMsgsTotal.With(prometheus.Labels{"method": method, "direction": "received"})))
+
+	_, err = c.Stream(context.Background(), method, func(ctx context.Context) (ClientStream, error) {
+		return nil, errors.New("refused")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1.0, counterValue(t, paceGRPCClientCallsTotal.With(prometheus.Labels{"method": method, "code": "Unknown"})))
+}
+
+func TestMetadata(t *testing.T) {
+	md := make(Metadata)
+	md.Set("Uber-Trace-Id", "1")
+	md.Set("request-id", "2")
+
+	read := make(map[string]string)
+	assert.NoError(t, md.ForeachKey(func(key, val string) error {
+		read[key] = val
+		return nil
+	}))
+	assert.Equal(t, map[string]string{"uber-trace-id": "1", "request-id": "2"}, read)
+	assert.Equal(t, "Unavailable", Unavailable.String())
+	assert.Equal(t, "Code(42)", Code(42).String())
+}
@@ -0,0 +1,152 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package grpc instruments the calls of gRPC clients with logging,
+// tracing (propagated using the metadata), metrics, default deadlines
+// and retries, like the transport package does for HTTP clients. The
+// protocol is implemented by the grpc driver of the service (e.g.
+// google.golang.org/grpc), whose client interceptors delegate to the
+// Client and which is adapted to the Driver interface.
+package grpc
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Deadline of unary calls whose context has no deadline
+	Timeout time.Duration `env:"GRPC_CLIENT_TIMEOUT" envDefault:"10s"`
+	// Maximum number of retries of unary calls with a retryable code
+	MaxRetries int `env:"GRPC_CLIENT_MAX_RETRIES" envDefault:"3"`
+	// Delay before the first retry, doubled for every further retry
+	RetryBackoff time.Duration `env:"GRPC_CLIENT_RETRY_BACKOFF" envDefault:"100ms"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the GRPC_CLIENT_* environment of the clients, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse gRPC client environment: %v", err)
+	}
+}
+
+var (
+	paceGRPCClientCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("grpc_client_calls_total"),
+			Help: "Collects stats about the number of gRPC calls (attempts) partitioned by method and code",
+		},
+		[]string{"method", "code"},
+	)
+	paceGRPCClientCallDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("grpc_client_call_duration_seconds"),
+			Help:    "Collect performance metrics for each gRPC call (attempt) or stream",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"method"},
+	)
+	paceGRPCClientRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("grpc_client_retries_total"),
+			Help: "Collects stats about the number of retried gRPC calls partitioned by method",
+		},
+		[]string{"method"},
+	)
+	paceGRPCClientStreamMsgsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("grpc_client_stream_msgs_total"),
+			Help: "Collects stats about the number of stream messages partitioned by method and direction (sent, received)",
+		},
+		[]string{"method", "direction"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceGRPCClientCallsTotal)
+	prometheus.MustRegister(paceGRPCClientCallDurationSeconds)
+	prometheus.MustRegister(paceGRPCClientRetriesTotal)
+	prometheus.MustRegister(paceGRPCClientStreamMsgsTotal)
+	configcheck.Register("grpc client", Setup)
+}
+
+// Code is a gRPC status code, the values equal codes.Code of grpc-go
+type Code uint32
+
+// Status codes
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	Unknown            Code = 2
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	AlreadyExists      Code = 6
+	PermissionDenied   Code = 7
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Aborted            Code = 10
+	OutOfRange         Code = 11
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	DataLoss           Code = 15
+	Unauthenticated    Code = 16
+)
+
+var codeNames = [...]string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// Metadata of a call, equals metadata.MD of grpc-go. The keys are
+// lower case, the span context is propagated using it.
+type Metadata map[string][]string
+
+// Set implements opentracing.TextMapWriter
+func (md Metadata) Set(key, val string) {
+	key = strings.ToLower(key)
+	md[key] = append(md[key], val)
+}
+
+// ForeachKey implements opentracing.TextMapReader
+func (md Metadata) ForeachKey(handler func(key, val string) error) error {
+	for k, vals := range md {
+		for _, v := range vals {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
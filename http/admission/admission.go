@@ -0,0 +1,341 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package admission provides a middleware that limits the concurrent
+// requests of a service. Under overload requests are queued by priority
+// (health > interactive > batch) and lower priorities are shed first.
+package admission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Maximum number of concurrent requests
+	Limit int `env:"ADMISSION_LIMIT" envDefault:"100"`
+	// Maximum number of requests waiting for admission
+	QueueSize int `env:"ADMISSION_QUEUE_SIZE" envDefault:"100"`
+	// Maximum duration a request waits for admission
+	MaxWait time.Duration `env:"ADMISSION_MAX_WAIT" envDefault:"1s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the ADMISSION_* environment of the controller, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse admission environment: %v", err)
+	}
+}
+
+// results of the admission, used as metric label
+const (
+	resultAdmitted = "admitted"
+	resultQueued   = "queued"
+	resultShed     = "shed"
+)
+
+var (
+	paceHTTPAdmissionTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_admission_total"),
+			Help: "Collects stats about the number of requests partitioned by priority and result (admitted, queued, shed)",
+		},
+		[]string{"priority", "result"},
+	)
+	paceHTTPAdmissionWaitSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("http_admission_wait_seconds"),
+			Help:    "Collect the time requests waited for admission",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"priority"},
+	)
+	paceHTTPAdmissionDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("http_admission_duration_seconds"),
+			Help:    "Collect the latency of admitted requests including the wait for admission",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"priority"},
+	)
+	paceHTTPAdmissionInflight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: metric.Name("http_admission_inflight"),
+			Help: "Number of admitted requests in progress",
+		},
+	)
+	paceHTTPAdmissionQueued = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("http_admission_queued"),
+			Help: "Number of requests waiting for admission partitioned by priority",
+		},
+		[]string{"priority"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPAdmissionTotal)
+	prometheus.MustRegister(paceHTTPAdmissionWaitSeconds)
+	prometheus.MustRegister(paceHTTPAdmissionDurationSeconds)
+	prometheus.MustRegister(paceHTTPAdmissionInflight)
+	prometheus.MustRegister(paceHTTPAdmissionQueued)
+	configcheck.Register("admission", Setup)
+}
+
+// ErrOverloaded is returned to the clients of shed requests
+var ErrOverloaded = errors.New("service overloaded, retry later")
+
+// HeaderPriority classifies a request, clients can only lower the
+// priority of the route (e.g. "X-Request-Priority: batch")
+const HeaderPriority = "X-Request-Priority"
+
+// Priority of a request, higher priorities are admitted first
+type Priority int
+
+// priorities in ascending order
+const (
+	Batch Priority = iota
+	Interactive
+	Health
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case Batch:
+		return "batch"
+	case Interactive:
+		return "interactive"
+	case Health:
+		return "health"
+	}
+	return "unknown"
+}
+
+// ParsePriority returns the priority of the passed name
+func ParsePriority(name string) (Priority, bool) {
+	for p := Batch; p < numPriorities; p++ {
+		if strings.EqualFold(name, p.String()) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// waiter is a request waiting for admission, it
+// receives true if it was admitted, false if shed
+type waiter struct {
+	priority Priority
+	ready    chan bool
+}
+
+// Controller admits requests up to the limit. Further requests wait in
+// the queue, the highest priority is admitted first. If the queue is
+// full, the newest waiting request of the lowest priority is shed if it
+// has a lower priority than the new request, otherwise the new request
+// is shed. Health requests are always admitted.
+type Controller struct {
+	// Limit of concurrent requests, defaults to ADMISSION_LIMIT
+	Limit int
+	// QueueSize is the maximum number of waiting requests,
+	// defaults to ADMISSION_QUEUE_SIZE
+	QueueSize int
+	// MaxWait for the admission, defaults to ADMISSION_MAX_WAIT
+	MaxWait time.Duration
+	// Routes maps route names (or path templates of unnamed routes) to
+	// their priority. Paths with prefix /health default to Health, all
+	// others to Interactive.
+	Routes map[string]Priority
+
+	mu       sync.Mutex
+	inflight int
+	waiting  [numPriorities][]*waiter
+}
+
+// NewController returns a controller configured by the environment
+func NewController() *Controller {
+	mustSetup()
+	return &Controller{
+		Limit:     cfg.Limit,
+		QueueSize: cfg.QueueSize,
+		MaxWait:   cfg.MaxWait,
+		Routes:    make(map[string]Priority),
+	}
+}
+
+// Middleware admits the requests, shed requests are answered with
+// 503 Service Unavailable and Retry-After
+func (c *Controller) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		p := c.Classify(r)
+		labels := prometheus.Labels{"priority": p.String()}
+
+		admitted, queued := c.acquire(r.Context(), p)
+		if queued {
+			paceHTTPAdmissionWaitSeconds.With(labels).Observe(time.Since(start).Seconds())
+		}
+		if !admitted {
+			paceHTTPAdmissionTotal.With(prometheus.Labels{"priority": p.String(), "result": resultShed}).Inc()
+			log.Req(r).Debug().Str("priority", p.String()).Msg("Request shed")
+			w.Header().Set("Retry-After", "1")
+			runtime.WriteError(w, http.StatusServiceUnavailable, ErrOverloaded)
+			return
+		}
+		defer c.release()
+
+		result := resultAdmitted
+		if queued {
+			result = resultQueued
+		}
+		paceHTTPAdmissionTotal.With(prometheus.Labels{"priority": p.String(), "result": result}).Inc()
+		next.ServeHTTP(w, r)
+		paceHTTPAdmissionDurationSeconds.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Classify returns the priority of the request, based on the route
+// and lowered by HeaderPriority
+func (c *Controller) Classify(r *http.Request) Priority {
+	p := Interactive
+	if strings.HasPrefix(r.URL.Path, "/health") {
+		p = Health
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		name := route.GetName()
+		if name == "" {
+			name, _ = route.GetPathTemplate() // nolint: errcheck
+		}
+		if rp, ok := c.Routes[name]; ok {
+			p = rp
+		}
+	}
+	if hp, ok := ParsePriority(r.Header.Get(HeaderPriority)); ok && hp < p {
+		p = hp
+	}
+	return p
+}
+
+// acquire blocks until the request is admitted or shed
+func (c *Controller) acquire(ctx context.Context, p Priority) (admitted, queued bool) {
+	c.mu.Lock()
+	if p == Health || c.inflight < c.Limit {
+		c.inflight++
+		paceHTTPAdmissionInflight.Set(float64(c.inflight))
+		c.mu.Unlock()
+		return true, false
+	}
+	if c.queued() >= c.QueueSize && !c.shedLowest(p) {
+		c.mu.Unlock()
+		return false, false
+	}
+	w := &waiter{priority: p, ready: make(chan bool, 1)}
+	c.waiting[p] = append(c.waiting[p], w)
+	c.updateQueued(p)
+	c.mu.Unlock()
+
+	timeout := time.NewTimer(c.MaxWait)
+	defer timeout.Stop()
+	select {
+	case ok := <-w.ready:
+		return ok, true
+	case <-timeout.C:
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	removed := c.remove(w)
+	c.mu.Unlock()
+	if removed {
+		return false, true
+	}
+	// admitted or shed concurrently
+	return <-w.ready, true
+}
+
+// release passes the slot of a finished request
+// to the next waiter of the highest priority
+func (c *Controller) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inflight <= c.Limit {
+		for p := numPriorities - 1; p >= Batch; p-- {
+			if len(c.waiting[p]) == 0 {
+				continue
+			}
+			w := c.waiting[p][0]
+			c.waiting[p] = c.waiting[p][1:]
+			c.updateQueued(p)
+			w.ready <- true
+			return
+		}
+	}
+	c.inflight--
+	paceHTTPAdmissionInflight.Set(float64(c.inflight))
+}
+
+// shedLowest sheds the newest waiter of the lowest priority
+// below p, false if there is none
+func (c *Controller) shedLowest(p Priority) bool {
+	for lp := Batch; lp < p; lp++ {
+		n := len(c.waiting[lp])
+		if n == 0 {
+			continue
+		}
+		w := c.waiting[lp][n-1]
+		c.waiting[lp] = c.waiting[lp][:n-1]
+		c.updateQueued(lp)
+		w.ready <- false
+		return true
+	}
+	return false
+}
+
+func (c *Controller) remove(w *waiter) bool {
+	queue := c.waiting[w.priority]
+	for i, qw := range queue {
+		if qw == w {
+			c.waiting[w.priority] = append(queue[:i], queue[i+1:]...)
+			c.updateQueued(w.priority)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) queued() int {
+	n := 0
+	for _, queue := range c.waiting {
+		n += len(queue)
+	}
+	return n
+}
+
+func (c *Controller) updateQueued(p Priority) {
+	paceHTTPAdmissionQueued.With(prometheus.Labels{"priority": p.String()}).Set(float64(len(c.waiting[p])))
+}
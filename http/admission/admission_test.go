@@ -0,0 +1,165 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package admission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func newController(limit, queueSize int) *Controller {
+	c := NewController()
+	c.Limit = limit
+	c.QueueSize = queueSize
+	c.MaxWait = time.Second
+	return c
+}
+
+// waitQueued waits until n requests are waiting
+func waitQueued(c *Controller, n int) {
+	for {
+		c.mu.Lock()
+		queued := c.queued()
+		c.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type result struct {
+	admitted, queued bool
+}
+
+func acquireAsync(c *Controller, p Priority) chan result {
+	ch := make(chan result, 1)
+	go func() {
+		admitted, queued := c.acquire(context.Background(), p)
+		ch <- result{admitted, queued}
+	}()
+	return ch
+}
+
+func TestPriorities(t *testing.T) {
+	c := newController(1, 1)
+	ctx := context.Background()
+
+	admitted, queued := c.acquire(ctx, Interactive)
+	assert.True(t, admitted)
+	assert.False(t, queued)
+
+	// health requests are always admitted
+	admitted, _ = c.acquire(ctx, Health)
+	assert.True(t, admitted)
+	c.release()
+
+	batch := acquireAsync(c, Batch)
+	waitQueued(c, 1)
+
+	// the queue is full, the waiting batch request is shed
+	interactive := acquireAsync(c, Interactive)
+	assert.Equal(t, result{false, true}, <-batch)
+	waitQueued(c, 1)
+
+	// the queue is full without lower priorities
+	admitted, queued = c.acquire(ctx, Batch)
+	assert.False(t, admitted)
+	assert.False(t, queued)
+
+	// the slot is passed to the waiting request
+	c.release()
+	assert.Equal(t, result{true, true}, <-interactive)
+	c.release()
+	assert.Equal(t, 0, c.inflight)
+}
+
+func TestMaxWait(t *testing.T) {
+	c := newController(1, 10)
+	c.MaxWait = 10 * time.Millisecond
+
+	admitted, _ := c.acquire(context.Background(), Interactive)
+	assert.True(t, admitted)
+	admitted, queued := c.acquire(context.Background(), Interactive)
+	assert.False(t, admitted)
+	assert.True(t, queued)
+	assert.Equal(t, 0, c.queued())
+}
+
+func TestMiddleware(t *testing.T) {
+	c := newController(1, 0)
+	c.Routes["batch"] = Batch
+
+	block := make(chan struct{})
+	r := mux.NewRouter()
+	r.Use(c.Middleware)
+	r.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	})
+	r.HandleFunc("/export", func(w http.ResponseWriter, r *http.Request) {}).Name("batch")
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orders", nil))
+		close(done)
+	}()
+	for {
+		c.mu.Lock()
+		inflight := c.inflight
+		c.mu.Unlock()
+		if inflight == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/export", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	close(block)
+	<-done
+}
+
+func TestClassify(t *testing.T) {
+	c := newController(1, 1)
+	c.Routes["/reports/{id}"] = Batch
+
+	var got []Priority
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, c.Classify(r))
+		})
+	})
+	r.HandleFunc("/reports/{id}", nil)
+	r.HandleFunc("/orders", nil)
+	r.HandleFunc("/health/ready", nil)
+
+	for _, req := range []struct{ path, priority string }{
+		{"/reports/1", ""},
+		{"/orders", ""},
+		{"/orders", "batch"},
+		{"/reports/1", "interactive"},
+		{"/health/ready", ""},
+		{"/orders", "health"},
+	} {
+		rq := httptest.NewRequest("GET", req.path, nil)
+		rq.Header.Set(HeaderPriority, req.priority)
+		r.ServeHTTP(httptest.NewRecorder(), rq)
+	}
+	assert.Equal(t, []Priority{Batch, Interactive, Batch, Batch, Health, Interactive}, got)
+}
@@ -0,0 +1,414 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package analytics aggregates the API usage per client and operation
+// (counts, latencies and payload sizes) in memory and exports the
+// aggregates periodically to a sink (postgres, kafka or http), e.g. for
+// usage reports of partners. Unique users per client and day are counted
+// in redis HyperLogLogs shared by the replicas.
+package analytics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	goredis "github.com/go-redis/redis"
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/internal/ctxutil"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/pkg/probabilistic"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Interval in which the aggregates are exported
+	ExportInterval time.Duration `env:"ANALYTICS_EXPORT_INTERVAL" envDefault:"1m"`
+	// Maximum number of aggregates (client and operation pairs) per
+	// interval, further pairs are dropped
+	MaxRecords int `env:"ANALYTICS_MAX_RECORDS" envDefault:"10000"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the ANALYTICS_* environment of the collector, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse analytics environment: %v", err)
+	}
+}
+
+var (
+	paceAnalyticsExportTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("analytics_export_total"),
+			Help: "Collects stats about the number of exported batches partitioned by result",
+		},
+		[]string{"result"},
+	)
+	paceAnalyticsDroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("analytics_dropped_total"),
+			Help: "Collects stats about the number of dropped requests, records or users partitioned by reason (limit, export, users)",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceAnalyticsExportTotal)
+	prometheus.MustRegister(paceAnalyticsDroppedTotal)
+	configcheck.Register("analytics", Setup)
+}
+
+// AnonymousClient is the client of requests without token
+const AnonymousClient = "anonymous"
+
+// Usage of an operation by a client within an interval
+type Usage struct {
+	ClientID  string    `json:"clientId"`
+	Operation string    `json:"operation"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	// Requests is the number of requests, ClientErrors and
+	// ServerErrors the number of 4xx and 5xx responses
+	Requests     int64 `json:"requests"`
+	ClientErrors int64 `json:"clientErrors"`
+	ServerErrors int64 `json:"serverErrors"`
+	// DurationSum and DurationMax of the requests in seconds
+	DurationSum   float64 `json:"durationSum"`
+	DurationMax   float64 `json:"durationMax"`
+	RequestBytes  int64   `json:"requestBytes"`
+	ResponseBytes int64   `json:"responseBytes"`
+}
+
+// Sink exports the aggregated usage
+type Sink interface {
+	Export(ctx context.Context, usage []Usage) error
+}
+
+// SinkFunc implements Sink using a function
+type SinkFunc func(ctx context.Context, usage []Usage) error
+
+// Export implements Sink
+func (f SinkFunc) Export(ctx context.Context, usage []Usage) error {
+	return f(ctx, usage)
+}
+
+type usageKey struct {
+	clientID, operation string
+}
+
+// uniqueCounter counts unique items, implemented by probabilistic.HyperLogLog
+type uniqueCounter interface {
+	Add(ctx context.Context, items ...string) (bool, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+// UniqueUsersRetention is the duration the unique users of a day are kept
+const UniqueUsersRetention = 32 * 24 * time.Hour
+
+// ErrUniqueUsersDisabled is returned by UniqueUsers if the
+// collector doesn't count unique users (see CountUniqueUsers)
+var ErrUniqueUsersDisabled = errors.New("analytics: unique users are not counted")
+
+// Collector aggregates the usage of the requests and
+// exports it in intervals to the sink
+type Collector struct {
+	sink       Sink
+	interval   time.Duration
+	maxRecords int
+
+	mu         sync.Mutex
+	start      time.Time
+	usage      map[usageKey]*Usage
+	pending    []Usage // batch of a failed export
+	newCounter func(name string) uniqueCounter
+	users      map[string]map[string]struct{} // users by client since the last flush
+	userCount  int
+}
+
+// NewCollector returns a collector exporting to the sink
+func NewCollector(sink Sink) *Collector {
+	mustSetup()
+	return &Collector{
+		sink:       sink,
+		interval:   cfg.ExportInterval,
+		maxRecords: cfg.MaxRecords,
+		start:      time.Now(),
+		usage:      make(map[usageKey]*Usage),
+		users:      make(map[string]map[string]struct{}),
+	}
+}
+
+// CountUniqueUsers enables counting the unique users per client and day
+// in the HyperLogLogs <prefix><client>:<day> of the redis client, e.g.
+// for the monthly active users of partners. The users are added with
+// every flush, see UniqueUsers.
+func (c *Collector) CountUniqueUsers(client *goredis.Client, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.newCounter = func(name string) uniqueCounter {
+		hll := probabilistic.NewHyperLogLog(client, prefix+name)
+		hll.TTL = UniqueUsersRetention
+		return hll
+	}
+}
+
+// UniqueUsers returns the estimated number of unique users of
+// the client on the day (UTC), including the users of all replicas
+// up to their last flush
+func (c *Collector) UniqueUsers(ctx context.Context, clientID string, day time.Time) (int64, error) {
+	c.mu.Lock()
+	newCounter := c.newCounter
+	c.mu.Unlock()
+	if newCounter == nil {
+		return 0, ErrUniqueUsersDisabled
+	}
+	return newCounter(dayName(clientID, day)).Count(ctx)
+}
+
+// RecordUser adds the user to the unique users of the client,
+// it is a no-op unless unique users are counted
+func (c *Collector) RecordUser(clientID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.newCounter == nil || userID == "" {
+		return
+	}
+
+	users, ok := c.users[clientID]
+	if !ok {
+		users = make(map[string]struct{})
+		c.users[clientID] = users
+	}
+	if _, ok := users[userID]; ok {
+		return
+	}
+	if c.userCount >= c.maxRecords {
+		paceAnalyticsDroppedTotal.With(prometheus.Labels{"reason": "limit"}).Inc()
+		return
+	}
+	users[userID] = struct{}{}
+	c.userCount++
+}
+
+// dayName returns the name of the HyperLogLog of the client and day
+func dayName(clientID string, day time.Time) string {
+	return clientID + ":" + day.UTC().Format("2006-01-02")
+}
+
+// Record adds a request of the client to the usage of the operation
+func (c *Collector) Record(clientID, operation string, status int, duration time.Duration, requestBytes, responseBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := usageKey{clientID: clientID, operation: operation}
+	u, ok := c.usage[key]
+	if !ok {
+		if len(c.usage) >= c.maxRecords {
+			paceAnalyticsDroppedTotal.With(prometheus.Labels{"reason": "limit"}).Inc()
+			return
+		}
+		u = &Usage{ClientID: clientID, Operation: operation}
+		c.usage[key] = u
+	}
+
+	u.Requests++
+	switch {
+	case status >= 500:
+		u.ServerErrors++
+	case status >= 400:
+		u.ClientErrors++
+	}
+	seconds := duration.Seconds()
+	u.DurationSum += seconds
+	if seconds > u.DurationMax {
+		u.DurationMax = seconds
+	}
+	u.RequestBytes += requestBytes
+	u.ResponseBytes += responseBytes
+}
+
+// Run exports the usage in the interval until ctx is done,
+// the usage of the last interval is exported before it returns
+func (c *Collector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.Flush(ctxutil.Detached(ctx)) // nolint: errcheck
+			return
+		case <-ticker.C:
+			c.Flush(ctx) // nolint: errcheck
+		}
+	}
+}
+
+// Flush exports the usage since the last export. If the export fails,
+// the batch is exported again with the next flush; it is dropped if that
+// fails as well.
+func (c *Collector) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	batch := c.pending
+	for _, u := range c.usage {
+		u.Start = c.start
+		u.End = now
+		batch = append(batch, *u)
+	}
+	retried := len(c.pending)
+	c.pending = nil
+	c.start = now
+	c.usage = make(map[usageKey]*Usage)
+	users, newCounter := c.users, c.newCounter
+	c.users = make(map[string]map[string]struct{})
+	c.userCount = 0
+	c.mu.Unlock()
+
+	if newCounter != nil {
+		c.flushUsers(ctx, newCounter, users, now)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := c.sink.Export(ctx, batch)
+	if err != nil {
+		paceAnalyticsExportTotal.With(prometheus.Labels{"result": "error"}).Inc()
+		log.Ctx(ctx).Warn().Err(err).Int("records", len(batch)).Msg("Failed to export analytics")
+
+		c.mu.Lock()
+		c.pending = batch[retried:]
+		c.mu.Unlock()
+		if retried > 0 {
+			paceAnalyticsDroppedTotal.With(prometheus.Labels{"reason": "export"}).Add(float64(retried))
+		}
+		return err
+	}
+	paceAnalyticsExportTotal.With(prometheus.Labels{"result": "success"}).Inc()
+	return nil
+}
+
+// flushUsers adds the users to the HyperLogLogs of the day, users
+// that can't be added are dropped
+func (c *Collector) flushUsers(ctx context.Context, newCounter func(name string) uniqueCounter, users map[string]map[string]struct{}, now time.Time) {
+	for clientID, set := range users {
+		ids := make([]string, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		if _, err := newCounter(dayName(clientID, now)).Add(ctx, ids...); err != nil {
+			paceAnalyticsDroppedTotal.With(prometheus.Labels{"reason": "users"}).Add(float64(len(ids)))
+			log.Ctx(ctx).Warn().Err(err).Str("client_id", clientID).Int("users", len(ids)).Msg("Failed to count unique users")
+		}
+	}
+}
+
+// Middleware records the usage of the requests. The operation is the
+// name of the route or, for unnamed routes, the method and path template.
+// The client is identified by the oauth2 token (see oauth2.ClientID), the
+// user of the token is added to the unique users of the client.
+func (c *Collector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		cw := &countingWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(cw, r)
+
+		clientID, ok := oauth2.ClientID(r.Context())
+		if !ok || clientID == "" {
+			clientID = AnonymousClient
+		}
+		c.Record(clientID, operation(r), cw.status, time.Since(start), body.n, cw.n)
+		if userID, ok := oauth2.UserID(r.Context()); ok {
+			c.RecordUser(clientID, userID)
+		}
+	})
+}
+
+// operation returns the name of the route of the request
+func operation(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+	if name := route.GetName(); name != "" {
+		return name
+	}
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.ToUpper(r.Method) + " " + tpl
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	status int
+	n      int64
+}
+
+func (w *countingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped writer does
+func (w *countingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the wrapped writer does
+func (w *countingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
@@ -0,0 +1,200 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	batches [][]Usage
+	err     error
+}
+
+func (s *recordingSink) Export(ctx context.Context, usage []Usage) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, usage)
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	sink := &recordingSink{}
+	c := NewCollector(sink)
+
+	r := mux.NewRouter()
+	r.Use(c.Middleware)
+	r.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body) // nolint: errcheck
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created")) // nolint: errcheck
+	}).Methods("POST").Name("CreateOrder")
+	r.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for i := 0; i < 2; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/orders", strings.NewReader("{}")))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orders/1", nil))
+
+	assert.NoError(t, c.Flush(context.Background()))
+	if !assert.Len(t, sink.batches, 1) {
+		return
+	}
+	usage := make(map[string]Usage)
+	for _, u := range sink.batches[0] {
+		assert.Equal(t, AnonymousClient, u.ClientID)
+		assert.False(t, u.End.Before(u.Start))
+		usage[u.Operation] = u
+	}
+	assert.Equal(t, int64(2), usage["CreateOrder"].Requests)
+	assert.Equal(t, int64(4), usage["CreateOrder"].RequestBytes)
+	assert.Equal(t, int64(14), usage["CreateOrder"].ResponseBytes)
+	assert.Equal(t, int64(1), usage["GET /orders/{id}"].ClientErrors)
+
+	// nothing to export
+	assert.NoError(t, c.Flush(context.Background()))
+	assert.Len(t, sink.batches, 1)
+}
+
+func TestMiddlewareStreaming(t *testing.T) {
+	c := NewCollector(&recordingSink{})
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data: 1\n\n")) // nolint: errcheck
+		w.(http.Flusher).Flush()
+
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.Equal(t, http.ErrNotSupported, err)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/events", nil))
+	assert.True(t, rec.Flushed)
+}
+
+func TestRecord(t *testing.T) {
+	sink := &recordingSink{}
+	c := NewCollector(sink)
+	c.maxRecords = 2
+
+	c.Record("a", "GetOrder", 200, 100*time.Millisecond, 0, 10)
+	c.Record("a", "GetOrder", 503, 300*time.Millisecond, 0, 20)
+	c.Record("b", "GetOrder", 200, time.Millisecond, 0, 0)
+	c.Record("c", "GetOrder", 200, time.Millisecond, 0, 0) // dropped
+
+	// failed batches are exported with the next flush
+	sink.err = errors.New("unavailable")
+	assert.Error(t, c.Flush(context.Background()))
+	sink.err = nil
+	c.Record("a", "GetOrder", 200, time.Millisecond, 0, 0)
+	assert.NoError(t, c.Flush(context.Background()))
+
+	if assert.Len(t, sink.batches, 1) {
+		assert.Len(t, sink.batches[0], 3)
+		for _, u := range sink.batches[0][:2] {
+			if u.ClientID == "a" {
+				assert.Equal(t, int64(2), u.Requests)
+				assert.Equal(t, int64(1), u.ServerErrors)
+				assert.InDelta(t, 0.4, u.DurationSum, 0.0001)
+				assert.InDelta(t, 0.3, u.DurationMax, 0.0001)
+				assert.Equal(t, int64(30), u.ResponseBytes)
+			}
+		}
+	}
+}
+
+// testCounter is an exact in-memory unique counter
+type testCounter struct {
+	items map[string]bool
+	err   error
+}
+
+func (c *testCounter) Add(ctx context.Context, items ...string) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	changed := false
+	for _, item := range items {
+		changed = changed || !c.items[item]
+		c.items[item] = true
+	}
+	return changed, nil
+}
+
+func (c *testCounter) Count(ctx context.Context) (int64, error) {
+	return int64(len(c.items)), c.err
+}
+
+func TestUniqueUsers(t *testing.T) {
+	c := NewCollector(&recordingSink{})
+	ctx := context.Background()
+	today := time.Now()
+
+	c.RecordUser("a", "user-1") // not counted
+	_, err := c.UniqueUsers(ctx, "a", today)
+	assert.Equal(t, ErrUniqueUsersDisabled, err)
+
+	counters := make(map[string]*testCounter)
+	c.CountUniqueUsers(nil, "test:")
+	c.newCounter = func(name string) uniqueCounter {
+		if counters[name] == nil {
+			counters[name] = &testCounter{items: make(map[string]bool)}
+		}
+		return counters[name]
+	}
+
+	c.RecordUser("a", "user-1")
+	c.RecordUser("a", "user-1")
+	c.RecordUser("a", "user-2")
+	c.RecordUser("b", "user-1")
+	c.RecordUser("b", "")
+	assert.NoError(t, c.Flush(ctx))
+	c.RecordUser("a", "user-3")
+	assert.NoError(t, c.Flush(ctx))
+
+	count, err := c.UniqueUsers(ctx, "a", today)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	count, err = c.UniqueUsers(ctx, "b", today)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = c.UniqueUsers(ctx, "a", today.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// users that can't be added are dropped
+	counters[dayName("a", today)].err = errors.New("unavailable")
+	c.RecordUser("a", "user-4")
+	assert.NoError(t, c.Flush(ctx))
+	assert.Empty(t, c.users)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []Usage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	usage := []Usage{{ClientID: "a", Operation: "GetOrder", Requests: 1}}
+	assert.NoError(t, HTTPSink(server.Client(), server.URL).Export(context.Background(), usage))
+	assert.Equal(t, usage, received)
+
+	err := HTTPSink(server.Client(), server.URL+"/%zz").Export(context.Background(), usage)
+	assert.Error(t, err)
+}
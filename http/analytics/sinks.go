@@ -0,0 +1,115 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/pace/bricks/backend/kafka"
+	"github.com/pace/bricks/backend/postgres"
+)
+
+// UsageRow is a row of the api_usage table of PostgresSink:
+//
+//	CREATE TABLE api_usage (
+//		id bigserial PRIMARY KEY,
+//		client_id text NOT NULL,
+//		operation text NOT NULL,
+//		start timestamptz NOT NULL,
+//		"end" timestamptz NOT NULL,
+//		requests bigint NOT NULL,
+//		client_errors bigint NOT NULL,
+//		server_errors bigint NOT NULL,
+//		duration_sum double precision NOT NULL,
+//		duration_max double precision NOT NULL,
+//		request_bytes bigint NOT NULL,
+//		response_bytes bigint NOT NULL
+//	);
+type UsageRow struct {
+	tableName struct{} `sql:"api_usage"` // nolint: structcheck,unused
+
+	ID            int64
+	ClientID      string
+	Operation     string
+	Start         time.Time
+	End           time.Time
+	Requests      int64   `sql:",notnull"`
+	ClientErrors  int64   `sql:",notnull"`
+	ServerErrors  int64   `sql:",notnull"`
+	DurationSum   float64 `sql:",notnull"`
+	DurationMax   float64 `sql:",notnull"`
+	RequestBytes  int64   `sql:",notnull"`
+	ResponseBytes int64   `sql:",notnull"`
+}
+
+// PostgresSink inserts the usage into the api_usage table (see UsageRow)
+func PostgresSink(db *pg.DB) Sink {
+	return SinkFunc(func(ctx context.Context, usage []Usage) error {
+		rows := make([]*UsageRow, len(usage))
+		for i, u := range usage {
+			rows[i] = &UsageRow{
+				ClientID:      u.ClientID,
+				Operation:     u.Operation,
+				Start:         u.Start,
+				End:           u.End,
+				Requests:      u.Requests,
+				ClientErrors:  u.ClientErrors,
+				ServerErrors:  u.ServerErrors,
+				DurationSum:   u.DurationSum,
+				DurationMax:   u.DurationMax,
+				RequestBytes:  u.RequestBytes,
+				ResponseBytes: u.ResponseBytes,
+			}
+		}
+		_, err := postgres.InsertBatch(ctx, db, rows, 0)
+		return err
+	})
+}
+
+// KafkaSink produces the usage as json messages to the
+// topic, the messages are keyed by client
+func KafkaSink(w kafka.Writer, topic string) Sink {
+	producer := kafka.NewProducer(w)
+	return SinkFunc(func(ctx context.Context, usage []Usage) error {
+		msgs := make([]kafka.Message, len(usage))
+		for i, u := range usage {
+			value, err := json.Marshal(u)
+			if err != nil {
+				return err
+			}
+			msgs[i] = kafka.Message{Topic: topic, Key: []byte(u.ClientID), Value: value}
+		}
+		return producer.Produce(ctx, msgs...)
+	})
+}
+
+// HTTPSink posts the usage as json array to the url
+func HTTPSink(client *http.Client, url string) Sink {
+	return SinkFunc(func(ctx context.Context, usage []Usage) error {
+		body, err := json.Marshal(usage)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close() // nolint: errcheck
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("analytics sink responded with %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
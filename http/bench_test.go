@@ -0,0 +1,73 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+)
+
+// routerAllocBudget is the maximum number of allocations of a request
+// passing the default middleware chain, raise it only deliberately
+const routerAllocBudget = 80
+
+// discardWriter is a response writer without allocations
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+// benchmarkRouter returns the default router with a mounted service route
+func benchmarkRouter() *mux.Router {
+	sr := mux.NewRouter()
+	sr.HandleFunc("/bench/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK\n")) // nolint: errcheck
+	}).Methods("GET")
+
+	r := Router()
+	r.PathPrefix("/bench/").Handler(sr)
+	return r
+}
+
+// withoutLogging disables the log output, it would dominate the results
+func withoutLogging() func() {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	return func() { zerolog.SetGlobalLevel(level) }
+}
+
+func BenchmarkRouter(b *testing.B) {
+	defer withoutLogging()()
+	r := benchmarkRouter()
+	req := httptest.NewRequest("GET", "/bench/1", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(&discardWriter{header: make(http.Header)}, req)
+	}
+}
+
+func TestRouterAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation budgets are not checked in short mode")
+	}
+	defer withoutLogging()()
+	r := benchmarkRouter()
+	req := httptest.NewRequest("GET", "/bench/1", nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.ServeHTTP(&discardWriter{header: make(http.Header)}, req)
+	})
+	if allocs > routerAllocBudget {
+		t.Errorf("Expected at most %d allocations per request, got: %v", routerAllocBudget, allocs)
+	}
+}
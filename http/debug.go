@@ -0,0 +1,48 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// debugRouter returns the subrouter of the operational endpoints under
+// /debug, requests need the DEBUG_TOKEN as bearer token. Requests are
+// rejected if no DEBUG_TOKEN is configured. The authorization header
+// can't be sent by simple cross-site requests (forms, images, ...).
+func debugRouter(r *mux.Router) *mux.Router {
+	d := r.PathPrefix("/debug").Subrouter()
+	d.Use(debugAuthMiddleware)
+	return d
+}
+
+// debugAuthMiddleware rejects requests without the DEBUG_TOKEN
+func debugAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validDebugToken(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="debug"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validDebugToken returns true if the request has the DEBUG_TOKEN
+// as bearer token, the tokens are compared in constant time
+func validDebugToken(r *http.Request) bool {
+	if cfg.DebugToken == "" {
+		return false
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cfg.DebugToken)) == 1
+}
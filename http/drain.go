@@ -0,0 +1,250 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kinds of long-lived connections, used as metric label
+const (
+	// StreamSSE is a server-sent events response
+	StreamSSE = "sse"
+	// StreamWebSocket is a hijacked websocket connection
+	StreamWebSocket = "websocket"
+	// StreamResponse is a streamed (chunked) response, e.g. a large export
+	StreamResponse = "stream"
+)
+
+// sseRetry is the reconnection time proposed to the clients of
+// server-sent events, they reconnect to another replica then
+const sseRetry = time.Second
+
+// websocketGoingAway is the close code of websockets of a server that
+// is shutting down (RFC 6455 7.4.1)
+const websocketGoingAway = 1001
+
+// Stream is a long-lived connection of a handler, e.g. server-sent events
+// or a websocket. On Shutdown the context of the stream is canceled, the
+// handler sends the termination message of the protocol using Terminate
+// and returns. Streams that don't end within SHUTDOWN_GRACE_PERIOD are
+// closed.
+type Stream struct {
+	kind   string
+	d      *drainer
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+
+	mu   sync.Mutex
+	conn io.Closer
+}
+
+// StartStream registers the long-lived connection of the request, Done
+// needs to be called once the handler returns. The kind is one of
+// StreamSSE, StreamWebSocket or StreamResponse.
+func StartStream(r *http.Request, kind string) *Stream {
+	ctx, cancel := context.WithCancel(r.Context())
+	s := &Stream{kind: kind, d: streams, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+	paceHTTPStreams.With(prometheus.Labels{"kind": kind}).Inc()
+	s.d.add(s)
+	return s
+}
+
+// Context returns the context of the stream, it is canceled once the
+// client disconnected or the server is shutting down
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// Draining returns true if the server is shutting down
+func (s *Stream) Draining() bool {
+	return s.d.isDraining()
+}
+
+// SetConn sets the hijacked connection (e.g. of a websocket), it is closed
+// if the stream doesn't end within the grace period. Connections that are
+// not hijacked are closed by the server.
+func (s *Stream) SetConn(conn io.Closer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn = conn
+}
+
+// Terminate writes the termination message of the protocol to w:
+// a shutdown event proposing to reconnect for server-sent events, a
+// going away close frame for websockets and nothing for streamed
+// responses, they end with the handler. w is flushed afterwards.
+func (s *Stream) Terminate(w io.Writer) error {
+	var err error
+	switch s.kind {
+	case StreamSSE:
+		_, err = fmt.Fprintf(w, "event: shutdown\nretry: %d\ndata:\n\n", sseRetry/time.Millisecond)
+	case StreamWebSocket:
+		err = writeWebSocketClose(w, websocketGoingAway, "server shutdown")
+	}
+	if err != nil {
+		return err
+	}
+
+	switch f := w.(type) {
+	case http.Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		err = f.Flush()
+	}
+	return err
+}
+
+// Done unregisters the stream, it is safe to call it multiple times
+func (s *Stream) Done() {
+	s.once.Do(func() {
+		s.cancel()
+		s.d.remove(s)
+		paceHTTPStreams.With(prometheus.Labels{"kind": s.kind}).Dec()
+		close(s.done)
+	})
+}
+
+// forceClose closes the hijacked connection of the stream (if any)
+func (s *Stream) forceClose() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close() // nolint: errcheck
+	}
+}
+
+// writeWebSocketClose writes an unmasked close frame of the server
+func writeWebSocketClose(w io.Writer, code uint16, reason string) error {
+	// control frames have a payload of at most 125 bytes
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	frame := make([]byte, 4, 4+len(reason))
+	frame[0] = 0x88 // FIN and close opcode
+	frame[1] = byte(2 + len(reason))
+	binary.BigEndian.PutUint16(frame[2:], code)
+	frame = append(frame, reason...)
+	_, err := w.Write(frame)
+	return err
+}
+
+// drainer keeps track of the open streams
+type drainer struct {
+	mu       sync.Mutex
+	streams  map[*Stream]struct{}
+	draining bool
+}
+
+var streams = newDrainer()
+
+func newDrainer() *drainer {
+	return &drainer{streams: make(map[*Stream]struct{})}
+}
+
+func (d *drainer) add(s *Stream) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.streams[s] = struct{}{}
+	// streams started during the shutdown end right away
+	if d.draining {
+		s.cancel()
+	}
+}
+
+func (d *drainer) remove(s *Stream) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.streams, s)
+}
+
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// drain cancels the contexts of all open streams
+func (d *drainer) drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.draining = true
+	for s := range d.streams {
+		s.cancel()
+	}
+}
+
+// open returns the streams that are still open
+func (d *drainer) open() []*Stream {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	open := make([]*Stream, 0, len(d.streams))
+	for s := range d.streams {
+		open = append(open, s)
+	}
+	return open
+}
+
+// wait waits until all streams are done or ctx is done, it
+// returns the streams that are still open
+func (d *drainer) wait(ctx context.Context) []*Stream {
+	for _, s := range d.open() {
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return d.open()
+		}
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the server: the handlers of the streams
+// are notified by canceling their context, then the server waits for the
+// requests and streams to end, at most SHUTDOWN_GRACE_PERIOD or until ctx
+// is done. Afterwards the remaining connections are closed, the number of
+// force-closed streams is returned. Use it instead of srv.Shutdown:
+//
+//	sig := make(chan os.Signal, 1)
+//	signal.Notify(sig, syscall.SIGTERM, os.Interrupt)
+//	<-sig
+//	http.Shutdown(context.Background(), server) // nolint: errcheck
+func Shutdown(ctx context.Context, srv *http.Server) (int, error) {
+	mustSetup()
+	return streams.shutdown(ctx, srv, cfg.ShutdownGracePeriod)
+}
+
+func (d *drainer) shutdown(ctx context.Context, srv *http.Server, grace time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, grace)
+	defer cancel()
+
+	logger := log.Ctx(ctx)
+	logger.Info().Int("streams", len(d.open())).Dur("grace_period", grace).Msg("Shutting down the server")
+	d.drain()
+	err := srv.Shutdown(ctx)
+	open := d.wait(ctx)
+
+	// close the remaining connections
+	if err != nil {
+		srv.Close() // nolint: errcheck
+	}
+	for _, s := range open {
+		s.forceClose()
+		paceHTTPStreamsForceClosedTotal.With(prometheus.Labels{"kind": s.kind}).Inc()
+	}
+	if len(open) > 0 {
+		logger.Warn().Int("streams", len(open)).Msg("Closed streams that didn't end within the grace period")
+	}
+	return len(open), err
+}
@@ -0,0 +1,118 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func forceClosedStreams(t *testing.T, kind string) float64 {
+	var m dto.Metric
+	if err := paceHTTPStreamsForceClosedTotal.With(prometheus.Labels{"kind": kind}).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestShutdownDrainsStreams(t *testing.T) {
+	defer func(d *drainer) { streams = d }(streams)
+	streams = newDrainer()
+
+	started := make(chan struct{})
+	r := Router()
+	r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		s := StartStream(r, StreamSSE)
+		defer s.Done()
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: hello\n\n")
+		w.(http.Flusher).Flush()
+		close(started)
+
+		<-s.Context().Done()
+		if s.Draining() {
+			assert.NoError(t, s.Terminate(w))
+		}
+	})
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	<-started
+
+	forced, err := streams.shutdown(context.Background(), ts.Config, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, forced)
+
+	data, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: hello\n\nevent: shutdown\nretry: 1000\ndata:\n\n", string(data))
+}
+
+func TestShutdownForceClosesStreams(t *testing.T) {
+	defer func(d *drainer) { streams = d }(streams)
+	streams = newDrainer()
+	before := forceClosedStreams(t, StreamWebSocket)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := StartStream(r, StreamWebSocket)
+		defer s.Done()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		s.SetConn(conn)
+		close(started)
+		// ignores the shutdown
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	go http.Get(ts.URL) // nolint: errcheck
+	<-started
+
+	forced, err := streams.shutdown(context.Background(), ts.Config, 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, forced)
+	assert.Equal(t, before+1, forceClosedStreams(t, StreamWebSocket))
+}
+
+func TestStreamTerminate(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Stream{kind: StreamWebSocket}
+	assert.NoError(t, s.Terminate(bufio.NewWriter(&buf)))
+	assert.Equal(t, append([]byte{0x88, 17, 0x03, 0xe9}, "server shutdown"...), buf.Bytes())
+
+	buf.Reset()
+	s = &Stream{kind: StreamResponse}
+	assert.NoError(t, s.Terminate(&buf))
+	assert.Empty(t, buf.String())
+
+	// started during the shutdown
+	defer func(d *drainer) { streams = d }(streams)
+	streams = newDrainer()
+	streams.drain()
+	s = StartStream(httptest.NewRequest("GET", "/events", nil), StreamSSE)
+	defer s.Done()
+	assert.Error(t, s.Context().Err())
+	assert.True(t, s.Draining())
+}
@@ -0,0 +1,466 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package export implements exports of large collections as background
+// jobs. The export endpoint enqueues the job and responds with a JSON:API
+// operation instead of holding the connection until the collection is
+// written. A worker streams the collection into the object storage, the
+// completed operation contains a time-limited signed download URL.
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/google/jsonapi"
+	"github.com/pace/bricks/backend/objstore"
+	"github.com/pace/bricks/backend/queue"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Topic of the export jobs
+	Topic string `env:"EXPORT_TOPIC" envDefault:"exports"`
+	// Prefix of the keys of the operations and exported files
+	Prefix string `env:"EXPORT_PREFIX" envDefault:"exports/"`
+	// Validity of the signed download URLs
+	URLExpiry time.Duration `env:"EXPORT_URL_EXPIRY" envDefault:"15m"`
+	// Maximum duration of writing an export
+	Timeout time.Duration `env:"EXPORT_TIMEOUT" envDefault:"30m"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the EXPORT_* environment of the exports, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse export environment: %v", err)
+	}
+}
+
+var (
+	paceExportJobsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("export_jobs_total"),
+			Help: "Collects stats about the number of export jobs partitioned by export and result",
+		},
+		[]string{"export", "result"},
+	)
+	paceExportDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("export_duration_seconds"),
+			Help:    "Collect performance metrics for writing and uploading exports",
+			Buckets: []float64{.1, .5, 1, 5, 10, 30, 60, 300, 900, 1800},
+		},
+		[]string{"export"},
+	)
+	paceExportBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("export_bytes_total"),
+			Help: "Collects stats about the number of uploaded bytes partitioned by export",
+		},
+		[]string{"export"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceExportJobsTotal)
+	prometheus.MustRegister(paceExportDurationSeconds)
+	prometheus.MustRegister(paceExportBytesTotal)
+	configcheck.Register("export", Setup)
+}
+
+// Statuses of an operation
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// ErrNotFound is returned for unknown operations
+var ErrNotFound = errors.New("export: operation not found")
+
+// ErrUnknownExport is returned if the export wasn't registered
+var ErrUnknownExport = errors.New("export: unknown export")
+
+// Storage stores the operations and exported files, implemented
+// by objstore.Client
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, objstore.ObjectInfo, error)
+	PresignGet(key string, expires time.Duration) (string, error)
+}
+
+// Params of an export, e.g. the filters of the collection
+type Params map[string]string
+
+// Principal is the oauth2 identity that started an export, it is
+// empty for exports started without authentication
+type Principal struct {
+	ClientID string `json:"clientId,omitempty"`
+	UserID   string `json:"userId,omitempty"`
+}
+
+// principalFromContext returns the identity of the oauth2 token of ctx
+func principalFromContext(ctx context.Context) Principal {
+	var p Principal
+	p.ClientID, _ = oauth2.ClientID(ctx)
+	p.UserID, _ = oauth2.UserID(ctx)
+	return p
+}
+
+// Func writes the collection filtered by params to w, exports
+// need to be scoped to the collection of the principal
+type Func func(ctx context.Context, principal Principal, params Params, w io.Writer) error
+
+// Export of a collection
+type Export struct {
+	// Name of the export, used as metric label
+	Name string
+	// ContentType of the file, e.g. "text/csv"
+	ContentType string
+	// FileName of the exported file, defaults to the name
+	FileName string
+	// Write writes the collection
+	Write Func
+}
+
+// Operation is the state of an export, it is responded as JSON:API
+// resource by the handlers
+type Operation struct {
+	ID          string     `jsonapi:"primary,exportOperation"`
+	Export      string     `jsonapi:"attr,export"`
+	Status      string     `jsonapi:"attr,status"`
+	CreatedAt   time.Time  `jsonapi:"attr,createdAt,iso8601"`
+	CompletedAt *time.Time `jsonapi:"attr,completedAt,iso8601,omitempty"`
+	Size        int64      `jsonapi:"attr,size,omitempty"`
+	Error       string     `jsonapi:"attr,error,omitempty"`
+	// DownloadURL is the signed URL of the completed export,
+	// it is valid until ExpiresAt
+	DownloadURL string     `jsonapi:"attr,downloadUrl,omitempty"`
+	ExpiresAt   *time.Time `jsonapi:"attr,expiresAt,iso8601,omitempty"`
+
+	self string
+}
+
+// JSONAPILinks implements jsonapi.Linkable
+func (o *Operation) JSONAPILinks() *jsonapi.Links {
+	if o.self == "" {
+		return nil
+	}
+	return &jsonapi.Links{"self": o.self}
+}
+
+// Done returns true if the operation completed or failed
+func (o *Operation) Done() bool {
+	return o.Status == StatusCompleted || o.Status == StatusFailed
+}
+
+// state of an operation in the storage
+type state struct {
+	ID          string     `json:"id"`
+	Export      string     `json:"export"`
+	Status      string     `json:"status"`
+	Params      Params     `json:"params,omitempty"`
+	Principal   Principal  `json:"principal"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Size        int64      `json:"size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Key         string     `json:"key,omitempty"`
+}
+
+// Exporter starts exports and writes them in the background
+type Exporter struct {
+	// OperationPath is the path of the operation endpoint, the id of
+	// the operation is appended for the links. Defaults to "/exports/".
+	OperationPath string
+
+	storage   Storage
+	publisher queue.Publisher
+	topic     string
+	prefix    string
+	urlExpiry time.Duration
+	timeout   time.Duration
+
+	mu      sync.RWMutex
+	exports map[string]Export
+}
+
+// New returns an exporter storing the operations and files using storage,
+// the jobs are published to EXPORT_TOPIC using publisher (e.g. a queue.Queue)
+func New(storage Storage, publisher queue.Publisher) *Exporter {
+	mustSetup()
+	return &Exporter{
+		OperationPath: "/exports/",
+		storage:       storage,
+		publisher:     publisher,
+		topic:         cfg.Topic,
+		prefix:        cfg.Prefix,
+		urlExpiry:     cfg.URLExpiry,
+		timeout:       cfg.Timeout,
+		exports:       make(map[string]Export),
+	}
+}
+
+// Topic returns the topic of the export jobs, the workers
+// subscribe Handle to it
+func (e *Exporter) Topic() string {
+	return e.topic
+}
+
+// Register registers the export, exports need to be registered
+// by the replicas starting and the workers writing them
+func (e *Exporter) Register(exp Export) {
+	if exp.FileName == "" {
+		exp.FileName = exp.Name
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exports[exp.Name] = exp
+}
+
+func (e *Exporter) export(name string) (Export, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	exp, ok := e.exports[name]
+	return exp, ok
+}
+
+// Start stores the pending operation of the export and enqueues the job,
+// the oauth2 identity of ctx is stored as principal of the operation
+func (e *Exporter) Start(ctx context.Context, name string, params Params) (*Operation, error) {
+	if _, ok := e.export(name); !ok {
+		return nil, ErrUnknownExport
+	}
+
+	st := &state{
+		ID:        newID(),
+		Export:    name,
+		Status:    StatusPending,
+		Params:    params,
+		Principal: principalFromContext(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := e.save(ctx, st); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(job{ID: st.ID})
+	if err != nil {
+		return nil, err
+	}
+	err = e.publisher.Publish(ctx, queue.Message{Topic: e.topic, Key: st.ID, Body: body})
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to enqueue %s: %v", name, err)
+	}
+	return e.operation(st), nil
+}
+
+// Operation returns the operation with the passed id, completed
+// operations contain a newly signed download URL. Operations of other
+// principals than the oauth2 identity of ctx are not found.
+func (e *Exporter) Operation(ctx context.Context, id string) (*Operation, error) {
+	st, err := e.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.Principal != principalFromContext(ctx) {
+		return nil, ErrNotFound
+	}
+	op := e.operation(st)
+
+	if st.Status == StatusCompleted {
+		op.DownloadURL, err = e.storage.PresignGet(st.Key, e.urlExpiry)
+		if err != nil {
+			return nil, err
+		}
+		expires := time.Now().Add(e.urlExpiry).UTC()
+		op.ExpiresAt = &expires
+	}
+	return op, nil
+}
+
+// job is the body of the queue messages
+type job struct {
+	ID string `json:"id"`
+}
+
+// Handle writes the export of the job message into a temporary file and
+// uploads it, it is the queue.Handler of the workers:
+//
+//	q.Subscribe(ctx, exporter.Topic(), exporter.Handle)
+//
+// Failed exports are not retried, their operation contains the error.
+func (e *Exporter) Handle(ctx context.Context, msg queue.Message) error {
+	var j job
+	if err := json.Unmarshal(msg.Body, &j); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("id", msg.ID).Msg("Dropping malformed export job")
+		return nil
+	}
+	st, err := e.load(ctx, j.ID)
+	if err == ErrNotFound {
+		log.Ctx(ctx).Warn().Str("operation", j.ID).Msg("Dropping export job of unknown operation")
+		return nil
+	}
+	if err != nil {
+		return err // retried by the queue
+	}
+	if st.Status == StatusCompleted || st.Status == StatusFailed {
+		return nil // delivered again, running jobs are written again as the worker may have died
+	}
+
+	st.Status = StatusRunning
+	if err := e.save(ctx, st); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = e.write(ctx, st)
+	now := time.Now().UTC()
+	st.CompletedAt = &now
+	result := StatusCompleted
+	if err != nil {
+		result = StatusFailed
+		st.Error = err.Error()
+		log.Ctx(ctx).Warn().Err(err).Str("export", st.Export).Str("operation", st.ID).Msg("Export failed")
+	}
+	st.Status = result
+	paceExportJobsTotal.With(prometheus.Labels{"export": st.Export, "result": result}).Inc()
+	paceExportDurationSeconds.With(prometheus.Labels{"export": st.Export}).Observe(time.Since(start).Seconds())
+
+	return e.save(ctx, st)
+}
+
+// write writes the export into a temporary file, as the size
+// needs to be known for the upload, and uploads it
+func (e *Exporter) write(ctx context.Context, st *state) error {
+	exp, ok := e.export(st.Export)
+	if !ok {
+		return ErrUnknownExport
+	}
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	f, err := ioutil.TempFile("", "export-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // nolint: errcheck
+	defer f.Close()           // nolint: errcheck
+
+	if err := exp.Write(ctx, st.Principal, st.Params, f); err != nil {
+		return err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := e.prefix + st.ID + "/" + exp.FileName
+	if err := e.storage.Put(ctx, key, f, size, exp.ContentType); err != nil {
+		return err
+	}
+	paceExportBytesTotal.With(prometheus.Labels{"export": st.Export}).Add(float64(size))
+	st.Key = key
+	st.Size = size
+	return nil
+}
+
+func (e *Exporter) operation(st *state) *Operation {
+	return &Operation{
+		ID:          st.ID,
+		Export:      st.Export,
+		Status:      st.Status,
+		CreatedAt:   st.CreatedAt,
+		CompletedAt: st.CompletedAt,
+		Size:        st.Size,
+		Error:       st.Error,
+		self:        e.OperationPath + st.ID,
+	}
+}
+
+func (e *Exporter) stateKey(id string) string {
+	return e.prefix + id + "/operation.json"
+}
+
+func (e *Exporter) save(ctx context.Context, st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return e.storage.Put(ctx, e.stateKey(st.ID), bytes.NewReader(data), int64(len(data)), "application/json")
+}
+
+func (e *Exporter) load(ctx context.Context, id string) (*state, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+	r, _, err := e.storage.Get(ctx, e.stateKey(id))
+	if err == objstore.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() // nolint: errcheck
+
+	var st state
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// newID returns a random operation id
+func newID() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// validID returns true for ids returned by newID, other ids
+// could access other keys of the storage
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,190 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/pace/bricks/backend/objstore"
+	"github.com/pace/bricks/backend/queue"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{objects: make(map[string][]byte), types: make(map[string]string)}
+}
+
+func (s *testStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	s.types[key] = contentType
+	return nil
+}
+
+func (s *testStorage) Get(ctx context.Context, key string) (io.ReadCloser, objstore.ObjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, objstore.ObjectInfo{}, objstore.ErrNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), objstore.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (s *testStorage) PresignGet(key string, expires time.Duration) (string, error) {
+	return "https://storage.example.com/" + key + "?expires=" + expires.String(), nil
+}
+
+type testPublisher struct {
+	msgs []queue.Message
+}
+
+func (p *testPublisher) Publish(ctx context.Context, msg queue.Message) error {
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+// testIntrospecter accepts every token as token of the user with the same name
+type testIntrospecter struct{}
+
+func (testIntrospecter) IntrospectToken(ctx context.Context, token string) (*oauth2.IntrospectResponse, error) {
+	return &oauth2.IntrospectResponse{Active: true, ClientID: "app", UserID: token}, nil
+}
+
+// request returns a request authenticated with the token of user
+func request(target, user string) *http.Request {
+	r := httptest.NewRequest("GET", target, nil)
+	r.Header.Set("Authorization", "Bearer "+user)
+	return r
+}
+
+func decodeOperation(t *testing.T, rec *httptest.ResponseRecorder) *Operation {
+	var op Operation
+	if err := jsonapi.UnmarshalPayload(rec.Body, &op); err != nil {
+		t.Fatal(err)
+	}
+	return &op
+}
+
+func TestExport(t *testing.T) {
+	storage, publisher := newTestStorage(), &testPublisher{}
+	e := New(storage, publisher)
+	e.Register(Export{
+		Name:        "transactions",
+		ContentType: "text/csv",
+		FileName:    "transactions.csv",
+		Write: func(ctx context.Context, principal Principal, params Params, w io.Writer) error {
+			_, err := io.WriteString(w, "user,station\n"+principal.UserID+","+params["station"]+"\n")
+			return err
+		},
+	})
+
+	auth := oauth2.NewMiddleware(testIntrospecter{})
+	start, operation := auth.Handler(e.StartHandler("transactions")), auth.Handler(e.OperationHandler())
+
+	rec := httptest.NewRecorder()
+	start.ServeHTTP(rec, request("/transactions/export?station=42", "alice"))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	op := decodeOperation(t, rec)
+	assert.Equal(t, StatusPending, op.Status)
+	assert.Equal(t, "/exports/"+op.ID, rec.Header().Get("Location"))
+
+	// pending operation
+	rec = httptest.NewRecorder()
+	operation.ServeHTTP(rec, request("/exports/"+op.ID, "alice"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, retryAfter, rec.Header().Get("Retry-After"))
+	assert.Equal(t, StatusPending, decodeOperation(t, rec).Status)
+
+	// worker
+	if assert.Len(t, publisher.msgs, 1) {
+		assert.Equal(t, e.Topic(), publisher.msgs[0].Topic)
+		assert.NoError(t, e.Handle(context.Background(), publisher.msgs[0]))
+	}
+	key := "exports/" + op.ID + "/transactions.csv"
+	assert.Equal(t, "user,station\nalice,42\n", string(storage.objects[key]))
+	assert.Equal(t, "text/csv", storage.types[key])
+
+	// the operation of alice is not found for bob
+	rec = httptest.NewRecorder()
+	operation.ServeHTTP(rec, request("/exports/"+op.ID, "bob"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// completed operation
+	rec = httptest.NewRecorder()
+	operation.ServeHTTP(rec, request("/exports/"+op.ID, "alice"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+	done := decodeOperation(t, rec)
+	assert.Equal(t, StatusCompleted, done.Status)
+	assert.Equal(t, int64(22), done.Size)
+	assert.True(t, strings.HasPrefix(done.DownloadURL, "https://storage.example.com/"+key))
+	assert.NotNil(t, done.ExpiresAt)
+	assert.NotNil(t, done.CompletedAt)
+
+	// delivered again
+	assert.NoError(t, e.Handle(context.Background(), publisher.msgs[0]))
+}
+
+func TestExportFailed(t *testing.T) {
+	storage, publisher := newTestStorage(), &testPublisher{}
+	e := New(storage, publisher)
+	e.Register(Export{
+		Name: "broken",
+		Write: func(ctx context.Context, principal Principal, params Params, w io.Writer) error {
+			return errors.New("database unavailable")
+		},
+	})
+
+	op, err := e.Start(context.Background(), "broken", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Handle(context.Background(), publisher.msgs[0]))
+
+	op, err = e.Operation(context.Background(), op.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFailed, op.Status)
+	assert.Equal(t, "database unavailable", op.Error)
+	assert.Empty(t, op.DownloadURL)
+	assert.True(t, op.Done())
+}
+
+func TestExportNotFound(t *testing.T) {
+	e := New(newTestStorage(), &testPublisher{})
+
+	rec := httptest.NewRecorder()
+	e.StartHandler("unknown").ServeHTTP(rec, httptest.NewRequest("GET", "/unknown/export", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	for _, id := range []string{newID(), "..%2Foperation.json", "unknown"} {
+		rec = httptest.NewRecorder()
+		e.OperationHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/exports/"+id, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, id)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package export
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// retryAfter is the polling interval proposed to the clients
+// of operations that are not done yet, in seconds
+const retryAfter = "5"
+
+// StartHandler returns the export endpoint of the registered export, the
+// query parameters and the oauth2 identity of the request are passed to
+// the export. It responds
+// 202 Accepted with the pending operation, the Location header contains
+// the link of the operation.
+func (e *Exporter) StartHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := make(Params)
+		for key, values := range r.URL.Query() {
+			params[key] = values[0]
+		}
+
+		op, err := e.Start(r.Context(), name, params)
+		if err == ErrUnknownExport {
+			runtime.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			log.Req(r).Error().Err(err).Str("export", name).Msg("Failed to start export")
+			runtime.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Location", op.self)
+		w.Header().Set("Retry-After", retryAfter)
+		runtime.Marshal(w, op, http.StatusAccepted)
+	})
+}
+
+// OperationHandler returns the operation endpoint, the id is taken from
+// the route variable "id" or the last path segment. Only the principal
+// that started the export gets the operation, it is not found for
+// others. Completed operations contain a signed download URL, clients
+// poll operations that are not done after the Retry-After header.
+func (e *Exporter) OperationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if id == "" {
+			id = path.Base(r.URL.Path)
+		}
+
+		op, err := e.Operation(r.Context(), id)
+		if err == ErrNotFound {
+			runtime.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			log.Req(r).Error().Err(err).Str("operation", id).Msg("Failed to load export operation")
+			runtime.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if !op.Done() {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		runtime.Marshal(w, op, http.StatusOK)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// violations of the response writing, used as metric label
+const (
+	violationDoubleWriteHeader    = "double_write_header"
+	violationWriteAfterTimeout    = "write_after_timeout"
+	violationWriteAfterDisconnect = "write_after_disconnect"
+)
+
+// errClientDisconnected is returned for writes after the client disconnected
+var errClientDisconnected = errors.New("http: client disconnected")
+
+// ResponseGuardMiddleware wraps the http.ResponseWriter so that double
+// WriteHeader calls, writes after the request timed out or a write failed
+// and writes after the client disconnected are dropped and logged as
+// warnings with the route of the request, instead of silently corrupting
+// the response. It is the
+// outermost middleware of the Router, so that the writes of all other
+// middlewares are guarded as well.
+func ResponseGuardMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gw := &guardWriter{ResponseWriter: w, req: r, start: time.Now()}
+		next.ServeHTTP(gw, r)
+	})
+}
+
+type guardWriter struct {
+	http.ResponseWriter
+	req        *http.Request
+	start      time.Time
+	status     int
+	violations map[string]bool
+	// failed is the violation of a failed write, the
+	// response can't reach the client anymore
+	failed string
+}
+
+func (w *guardWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		w.warn(violationDoubleWriteHeader).Int("status", w.status).Int("ignored_status", status).
+			Msg("Response header already written, ignoring status")
+		return
+	}
+	if violation := w.lost(); violation != "" {
+		w.warn(violation).Int("status", status).Msg("Response header written too late, ignoring status")
+		return
+	}
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *guardWriter) Write(b []byte) (int, error) {
+	if violation := w.lost(); violation != "" {
+		w.warn(violation).Int("status", w.status).Int("size", len(b)).
+			Msg("Response body written too late, ignoring write")
+		if violation == violationWriteAfterDisconnect {
+			return 0, errClientDisconnected
+		}
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	if err != nil {
+		// e.g. the write deadline of the server passed or the
+		// connection was closed by the client
+		w.failed = violationWriteAfterDisconnect
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			w.failed = violationWriteAfterTimeout
+		}
+		w.warn(w.failed).Int("status", w.status).Int("size", len(b)).
+			Err(err).Msg("Response body can't be written, the following writes are ignored")
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped writer does
+func (w *guardWriter) Flush() {
+	if w.lost() != "" {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the wrapped writer does
+func (w *guardWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+// lost returns the violation if the response can't reach the
+// client anymore, or an empty string if it still can
+func (w *guardWriter) lost() string {
+	switch w.req.Context().Err() {
+	case context.Canceled:
+		return violationWriteAfterDisconnect
+	case context.DeadlineExceeded:
+		return violationWriteAfterTimeout
+	}
+	return w.failed
+}
+
+// warn counts the violation and returns the warning, each violation
+// is logged once per request to not flood the logs with streamed writes
+func (w *guardWriter) warn(violation string) *zerolog.Event {
+	paceHTTPResponseGuardTotal.With(prometheus.Labels{"violation": violation}).Inc()
+	if w.violations[violation] {
+		return nil
+	}
+	if w.violations == nil {
+		w.violations = make(map[string]bool)
+	}
+	w.violations[violation] = true
+
+	return log.Logger().Warn().
+		Str("req_id", w.Header().Get("Request-Id")).
+		Str("route", routeName(w.req)).
+		Str("method", w.req.Method).
+		Str("path", w.req.URL.Path).
+		Str("violation", violation).
+		Dur("elapsed", time.Since(w.start))
+}
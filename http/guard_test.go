@@ -0,0 +1,132 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func guardViolations(t *testing.T, violation string) float64 {
+	var m dto.Metric
+	if err := paceHTTPResponseGuardTotal.With(prometheus.Labels{"violation": violation}).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestResponseGuardDoubleWriteHeader(t *testing.T) {
+	before := guardViolations(t, violationDoubleWriteHeader)
+
+	r := mux.NewRouter()
+	r.Use(ResponseGuardMiddleware)
+	r.HandleFunc("/foo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, "created")
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/foo", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "created", rec.Body.String())
+	assert.Equal(t, before+2, guardViolations(t, violationDoubleWriteHeader))
+}
+
+func TestResponseGuardWriteAfterDisconnect(t *testing.T) {
+	before := guardViolations(t, violationWriteAfterDisconnect)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var writeErr error
+	h := ResponseGuardMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "partial")
+		cancel() // client disconnects
+		_, writeErr = io.WriteString(w, "lost")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/foo", nil).WithContext(ctx))
+
+	assert.Equal(t, errClientDisconnected, writeErr)
+	assert.Equal(t, "partial", rec.Body.String())
+	assert.Equal(t, before+1, guardViolations(t, violationWriteAfterDisconnect))
+}
+
+func TestResponseGuardWriteAfterTimeout(t *testing.T) {
+	before := guardViolations(t, violationWriteAfterTimeout)
+
+	var writeErr error
+	h := ResponseGuardMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusOK)
+		_, writeErr = io.WriteString(w, "lost")
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/foo", nil).WithContext(ctx))
+
+	assert.Equal(t, http.ErrHandlerTimeout, writeErr)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, before+2, guardViolations(t, violationWriteAfterTimeout))
+}
+
+// failingWriter fails all writes with err
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	err error
+}
+
+func (w failingWriter) Write(b []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestResponseGuardFailedWrite(t *testing.T) {
+	cases := []struct {
+		err       error
+		violation string
+		dropped   error
+	}{
+		{&net.OpError{Op: "write", Err: timeoutError{}}, violationWriteAfterTimeout, http.ErrHandlerTimeout},
+		{&net.OpError{Op: "write", Err: errors.New("broken pipe")}, violationWriteAfterDisconnect, errClientDisconnected},
+	}
+	for _, c := range cases {
+		before := guardViolations(t, c.violation)
+
+		var errs []error
+		h := ResponseGuardMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i < 2; i++ {
+				_, err := io.WriteString(w, "lost")
+				errs = append(errs, err)
+			}
+		}))
+		h.ServeHTTP(failingWriter{httptest.NewRecorder(), c.err}, httptest.NewRequest("GET", "/foo", nil))
+
+		// the following writes are dropped
+		assert.Equal(t, []error{c.err, c.dropped}, errs)
+		assert.Equal(t, before+2, guardViolations(t, c.violation))
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -54,34 +54,46 @@ func loadSwaggerFromURI(loader *openapi3.SwaggerLoader, url *url.URL) (*openapi3
 // BuildSource generates the go code in the specified path with specified package name
 // based on the passed schema source (url or file path)
 func (g *Generator) BuildSource(source, packagePath, packageName string) (string, error) {
+	schema, err := loadSwagger(source)
+	if err != nil {
+		return "", err
+	}
+
+	return g.BuildSchema(schema, packagePath, packageName)
+}
+
+// BuildDashboardSource generates a grafana dashboard for the service
+// based on the passed schema source (url or file path)
+func (g *Generator) BuildDashboardSource(source, serviceName string) (string, error) {
+	schema, err := loadSwagger(source)
+	if err != nil {
+		return "", err
+	}
+
+	return g.BuildDashboard(schema, serviceName)
+}
+
+// loadSwagger loads the schema from the passed source (url or file path)
+func loadSwagger(source string) (*openapi3.Swagger, error) {
 	loader := openapi3.NewSwaggerLoader()
-	var schema *openapi3.Swagger
 
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
 		loc, err := url.Parse(source)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		schema, err = loadSwaggerFromURI(loader, loc)
-		if err != nil {
-			return "", err
-		}
-	} else {
-		// read spec
-		data, err := ioutil.ReadFile(source) // nolint: gosec
-		if err != nil {
-			return "", err
-		}
+		return loadSwaggerFromURI(loader, loc)
+	}
 
-		// parse spec
-		schema, err = loader.LoadSwaggerFromData(data)
-		if err != nil {
-			return "", err
-		}
+	// read spec
+	data, err := ioutil.ReadFile(source) // nolint: gosec
+	if err != nil {
+		return nil, err
 	}
 
-	return g.BuildSchema(schema, packagePath, packageName)
+	// parse spec
+	return loader.LoadSwaggerFromData(data)
 }
 
 // BuildSchema generates the go code in the specified path with specified package name
@@ -97,13 +109,18 @@ func (g *Generator) BuildSchema(schema *openapi3.Swagger, packagePath, packageNa
 
 	g.serviceName = packageName
 
+	err := addSyncParameters(schema)
+	if err != nil {
+		return "", err
+	}
+
 	buildFuncs := []buildFunc{
 		g.BuildTypes,
 		g.BuildHandler,
 	}
 
 	for _, bf := range buildFuncs {
-		err := bf(schema)
+		err = bf(schema)
 		if err != nil {
 			return "", err
 		}
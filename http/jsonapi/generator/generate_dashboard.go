@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pace/bricks/maintenance/metric"
+)
+
+// dashboard is the subset of the grafana dashboard model
+// that is used for the generated dashboards
+type dashboard struct {
+	Title         string           `json:"title"`
+	UID           string           `json:"uid"`
+	Tags          []string         `json:"tags"`
+	Timezone      string           `json:"timezone"`
+	SchemaVersion int              `json:"schemaVersion"`
+	Refresh       string           `json:"refresh"`
+	Time          dashboardTime    `json:"time"`
+	Panels        []dashboardPanel `json:"panels"`
+}
+
+type dashboardTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type dashboardPanel struct {
+	ID         int               `json:"id"`
+	Type       string            `json:"type"`
+	Title      string            `json:"title"`
+	Datasource string            `json:"datasource,omitempty"`
+	GridPos    dashboardGridPos  `json:"gridPos"`
+	Targets    []dashboardTarget `json:"targets,omitempty"`
+	Collapsed  bool              `json:"collapsed,omitempty"`
+}
+
+type dashboardGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type dashboardTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	RefID        string `json:"refId"`
+}
+
+// BuildDashboard generates a grafana dashboard (JSON) for the passed schema.
+// The dashboard contains request rate, error rate and latency panels
+// for each operation of the service.
+func (g *Generator) BuildDashboard(schema *openapi3.Swagger, serviceName string) (string, error) {
+	d := dashboard{
+		Title:         fmt.Sprintf("%s (generated)", serviceName),
+		UID:           serviceName,
+		Tags:          []string{"pace", "generated", serviceName},
+		Timezone:      "browser",
+		SchemaVersion: 16,
+		Refresh:       "30s",
+		Time:          dashboardTime{From: "now-6h", To: "now"},
+	}
+
+	id, y := 0, 0
+	nextID := func() int {
+		id++
+		return id
+	}
+	graph := func(title, expr, legend string, x int) dashboardPanel {
+		return dashboardPanel{
+			ID:         nextID(),
+			Type:       "graph",
+			Title:      title,
+			Datasource: "Prometheus",
+			GridPos:    dashboardGridPos{H: 8, W: 8, X: x, Y: y},
+			Targets: []dashboardTarget{
+				{Expr: expr, LegendFormat: legend, RefID: "A"},
+			},
+		}
+	}
+
+	for _, operation := range operationNames(schema) {
+		selector := fmt.Sprintf(`service=%q,operation=%q`, serviceName, operation)
+
+		d.Panels = append(d.Panels, dashboardPanel{
+			ID:      nextID(),
+			Type:    "row",
+			Title:   operation,
+			GridPos: dashboardGridPos{H: 1, W: 24, X: 0, Y: y},
+		})
+		y++
+
+		total, duration := metric.Name("api_http_operation_total"), metric.Name("api_http_operation_duration_seconds")
+		d.Panels = append(d.Panels,
+			graph("Requests", fmt.Sprintf(
+				`sum(rate(%s{%s}[5m])) by (code)`, total, selector),
+				"{{code}}", 0),
+			graph("Error rate", fmt.Sprintf(
+				`sum(rate(%s{%s,code=~"5.."}[5m])) / sum(rate(%s{%s}[5m]))`,
+				total, selector, total, selector),
+				"errors", 8),
+			graph("Latency", fmt.Sprintf(
+				`histogram_quantile(0.95, sum(rate(%s_bucket{%s}[5m])) by (le))`, duration, selector),
+				"p95", 16),
+		)
+		y += 8
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// operationNames returns the sorted names of all operations of the schema,
+// names match the operation label of the collected metrics
+func operationNames(schema *openapi3.Swagger) []string {
+	var names []string
+
+	for pattern, pathItem := range schema.Paths {
+		for method, op := range pathItem.Operations() {
+			if op == nil {
+				continue
+			}
+			names = append(names, OperationName(strings.Title(strings.ToLower(method)), op, pattern))
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
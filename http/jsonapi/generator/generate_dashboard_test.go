@@ -0,0 +1,51 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestBuildDashboard(t *testing.T) {
+	g := Generator{}
+	result, err := g.BuildDashboardSource("./internal/poi/open-api.json", "poi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d dashboard
+	if err := json.Unmarshal([]byte(result), &d); err != nil {
+		t.Fatalf("expected valid json dashboard, got: %v", err)
+	}
+
+	if d.UID != "poi" {
+		t.Errorf("expected dashboard uid %q, got: %q", "poi", d.UID)
+	}
+
+	rows := 0
+	for _, panel := range d.Panels {
+		if panel.Type == "row" {
+			rows++
+		}
+	}
+	if ops := len(operationNames(mustLoadSwagger(t, "./internal/poi/open-api.json"))); rows != ops {
+		t.Errorf("expected a row for each of the %d operations, got: %d", ops, rows)
+	}
+
+	if !strings.Contains(result, `pace_api_http_operation_total{service=\"poi\",operation=\"GetApps\"}`) {
+		t.Errorf("expected operation selector in dashboard, got: %s", result)
+	}
+}
+
+func mustLoadSwagger(t *testing.T, source string) *openapi3.Swagger {
+	schema, err := loadSwagger(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
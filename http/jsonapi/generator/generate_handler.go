@@ -198,13 +198,22 @@ func (g *Generator) generateResponseInterface(route *route, schema *openapi3.Swa
 				// generate the method as function for the implementing type
 				g.addGoDoc(methodName, fmt.Sprintf("responds with jsonapi marshaled data (HTTP code %d)", codeNum))
 				g.goSource.Func().Params(jen.Id("w").Op("*").Id(route.responseTypeImpl)).
-					Id(methodName).Params(jen.Id("data").Add(typeReference)).Block(
-					jen.Qual(pkgJSONAPIRuntime, "Marshal").Call(
-						jen.Id("w"),
+					Id(methodName).Params(jen.Id("data").Add(typeReference)).BlockFunc(func(g *jen.Group) {
+					// add last modified header for cachable resources and
+					// respond with not modified if the client cached it
+					if codeNum == 200 && route.lastModifiedField != "" {
+						g.If(jen.Id("data").Op("!=").Nil().Op("&&").Qual(pkgJSONAPIRuntime, "NotModified").Call(
+							jen.Id("w"),
+							jen.Id("w").Dot("request"),
+							jen.Id("data").Dot(route.lastModifiedField),
+						)).Block(jen.Return())
+					}
+					g.Qual(pkgJSONAPIRuntime, "Marshal").Call(
+						jen.Id("w").Dot("ResponseWriter"),
 						jen.Id("data"),
 						jen.Lit(codeNum),
-					),
-				)
+					)
+				})
 			}()
 		} else {
 			method.Params()
@@ -237,9 +246,13 @@ func (g *Generator) generateResponseInterface(route *route, schema *openapi3.Swa
 	g.goSource.Type().Id(route.responseType).Interface(methods...)
 
 	// Implementation type
-	g.goSource.Type().Id(route.responseTypeImpl).Struct(
-		jen.Qual("net/http", "ResponseWriter"),
-	)
+	g.goSource.Type().Id(route.responseTypeImpl).StructFunc(func(g *jen.Group) {
+		g.Qual("net/http", "ResponseWriter")
+		// the request of cachable resources is needed for conditional requests
+		if route.lastModifiedField != "" {
+			g.Id("request").Op("*").Qual("net/http", "Request")
+		}
+	})
 
 	return nil
 }
@@ -319,6 +332,22 @@ func (g *Generator) buildServiceInterface(routes []*route, schema *openapi3.Swag
 	g.goSource.Line().Commentf("%s interface for all handlers", serviceInterface)
 	g.goSource.Type().Id(serviceInterface).Interface(methods...)
 
+	// optional interfaces for conditional requests
+	for _, route := range routes {
+		if route.lastModifiedField == "" {
+			continue
+		}
+		g.addGoDoc(route.lastModifierInterface(), "can optionally be implemented by the service to\n"+
+			"cheaply supply the last modification of the resource before it is loaded.\n"+
+			"Requests with an unchanged resource are answered with 304 Not Modified")
+		g.goSource.Type().Id(route.lastModifierInterface()).Interface(
+			jen.Id(route.serviceFunc+"LastModified").Params(
+				jen.Qual("context", "Context"),
+				jen.Op("*").Id(route.requestType),
+			).Params(jen.Qual("time", "Time"), jen.Error()),
+		)
+	}
+
 	return nil
 }
 
@@ -362,19 +391,39 @@ func (g *Generator) buildRouter(routes []*route, schema *openapi3.Swagger) error
 				Dot("Handler").Call(jen.Id(route.handler).Call(jen.Id("service")))
 
 			// add query parameters for route matching
-			if len(route.queryValues) > 0 {
-				for key, value := range route.queryValues {
-					if len(value) != 1 {
-						panic("query paths can only handle one query parameter with the same name!")
-					}
-					routeStmt.Dot("Queries").Call(jen.Lit(key), jen.Lit(value[0]))
-				}
-			}
+			addQueries(routeStmt, route)
 
 			// add the name to build routes
 			routeStmt.Dot("Name").Call(jen.Lit(route.serviceFunc))
 
 			routeStmts = append(routeStmts, routeStmt)
+
+			// answer HEAD requests using the GET handler, if the spec
+			// doesn't define HEAD explicitly
+			if route.method == "GET" && !hasMethod(routes, route.url.Path, "HEAD") {
+				headStmt := jen.Id(subrouterID).Dot("Methods").Call(jen.Lit("HEAD")).
+					Dot("Path").Call(jen.Lit(route.url.Path)).
+					Dot("Handler").Call(jen.Qual(pkgJSONAPIRuntime, "HeadHandler").Call(
+					jen.Id(route.handler).Call(jen.Id("service"))))
+				addQueries(headStmt, route)
+				// same name to apply the same scopes
+				headStmt.Dot("Name").Call(jen.Lit(route.serviceFunc))
+				routeStmts = append(routeStmts, headStmt)
+			}
+		}
+
+		// answer OPTIONS requests with the methods allowed by the spec
+		for _, path := range routePaths(routes) {
+			if hasMethod(routes, path, "OPTIONS") {
+				continue
+			}
+			routeStmts = append(routeStmts, jen.Id(subrouterID).Dot("Methods").Call(jen.Lit("OPTIONS")).
+				Dot("Path").Call(jen.Lit(path)).
+				Dot("Handler").Call(jen.Qual(pkgJSONAPIRuntime, "OptionsHandler").CallFunc(func(g *jen.Group) {
+				for _, method := range allowedMethods(routes, path) {
+					g.Lit(method)
+				}
+			})))
 		}
 	}
 
@@ -389,6 +438,62 @@ func (g *Generator) buildRouter(routes []*route, schema *openapi3.Swagger) error
 	return nil
 }
 
+// addQueries adds the query parameters of the route for route matching
+func addQueries(routeStmt *jen.Statement, route *route) {
+	for key, value := range route.queryValues {
+		if len(value) != 1 {
+			panic("query paths can only handle one query parameter with the same name!")
+		}
+		routeStmt.Dot("Queries").Call(jen.Lit(key), jen.Lit(value[0]))
+	}
+}
+
+// routePaths returns the sorted list of distinct paths of all routes
+func routePaths(routes []*route) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, route := range routes {
+		if !seen[route.url.Path] {
+			seen[route.url.Path] = true
+			paths = append(paths, route.url.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// hasMethod returns true if the spec defines the method for the path
+func hasMethod(routes []*route, path, method string) bool {
+	for _, route := range routes {
+		if route.url.Path == path && route.method == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedMethods returns the methods that are answered for the path,
+// including the automatically generated HEAD and OPTIONS methods
+func allowedMethods(routes []*route, path string) []string {
+	methods := map[string]bool{"OPTIONS": true}
+	for _, route := range routes {
+		if route.url.Path != path {
+			continue
+		}
+		methods[route.method] = true
+		if route.method == "GET" {
+			methods["HEAD"] = true
+		}
+	}
+
+	list := make([]string, 0, len(methods))
+	for method := range methods {
+		list = append(list, method)
+	}
+	sort.Strings(list)
+	return list
+}
+
 func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern string, pathItem *openapi3.PathItem) (*route, error) {
 	route := &route{
 		method:    strings.ToUpper(method),
@@ -402,11 +507,10 @@ func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern
 	}
 
 	// use OperationID for go function names or generate the name
-	oid := strings.Title(op.OperationID)
-	if oid == "" {
+	if op.OperationID == "" {
 		log.Warnf("Note: Avoid automatic method name generation for path (use OperationID): %s", pattern)
-		oid = generateName(method, op, pattern)
 	}
+	oid := OperationName(method, op, pattern)
 	handler := oid + "Handler"
 	route.handler = handler
 	route.serviceFunc = oid
@@ -422,6 +526,11 @@ func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern
 		}
 	}
 
+	// conditional requests are supported for resources that are updated
+	if route.method == "GET" {
+		route.lastModifiedField = lastModifiedField(op)
+	}
+
 	// generate handler function
 	gen := g // generator is used less frequent then the jen group, make available with longer name
 	g.addGoDoc(handler, fmt.Sprintf("handles request/response marshaling and validation for \n %s %s",
@@ -445,13 +554,19 @@ func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern
 				g.Line().Comment("Setup context, response writer and request type")
 
 				// response writer
-				g.Id("writer").Op(":=").Id(route.responseTypeImpl).
-					Block(jen.Id("ResponseWriter").Op(":").
-						Qual(pkgJSONAPIMetrics, "NewMetric").Call(
-						jen.Lit(gen.serviceName),
-						jen.Lit(route.pattern),
-						jen.Id("w"),
-						jen.Id("r")).Op(","))
+				g.Id("metric").Op(":=").Qual(pkgJSONAPIMetrics, "NewOperationMetric").Call(
+					jen.Lit(gen.serviceName),
+					jen.Lit(route.serviceFunc),
+					jen.Lit(route.pattern),
+					jen.Id("w"),
+					jen.Id("r"))
+				g.Defer().Id("metric").Dot("ObserveSizes").Call(jen.Id("handlerSpan"))
+				g.Id("writer").Op(":=").Id(route.responseTypeImpl).BlockFunc(func(g *jen.Group) {
+					g.Id("ResponseWriter").Op(":").Id("metric").Op(",")
+					if route.lastModifiedField != "" {
+						g.Id("request").Op(":").Id("r").Op(",")
+					}
+				})
 
 				// request
 				g.Id("request").Op(":=").Id(route.requestType).
@@ -501,6 +616,30 @@ func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern
 					)
 				}
 
+				// check if the resource was modified using the optional service hook
+				if route.lastModifiedField != "" {
+					g.Line().Comment("Respond with not modified if the resource wasn't modified since the client cached it")
+					g.If(jen.List(jen.Id("lm"), jen.Id("ok")).Op(":=").Id("service").Assert(jen.Id(route.lastModifierInterface())),
+						jen.Id("ok")).Block(
+						jen.List(jen.Id("lastModified"), jen.Id("err")).Op(":=").Id("lm").Dot(route.serviceFunc+"LastModified").Call(
+							jen.Id("ctx"),
+							jen.Op("&").Id("request"),
+						),
+						jen.If(jen.Id("err").Op("!=").Nil()).Block(
+							jen.Qual(pkgMaintErrors, "HandleError").Call(jen.Id("err"),
+								jen.Lit(handler),
+								jen.Id("w"),
+								jen.Id("r")),
+							jen.Return(),
+						),
+						jen.If(jen.Qual(pkgJSONAPIRuntime, "NotModified").Call(
+							jen.Id("w"),
+							jen.Id("r"),
+							jen.Id("lastModified"),
+						)).Block(jen.Return()),
+					)
+				}
+
 				// invoke service and handle error with internal server error response
 				invokeService := jen.Comment("Invoke service that implements the business logic").Line().
 					Id("err").Op(":=").Id("service").Dot(route.serviceFunc).Call(
@@ -565,8 +704,47 @@ func (g *Generator) buildHandler(method string, op *openapi3.Operation, pattern
 	return route, nil
 }
 
+// lastModifiedField returns the go field name of the updatedAt attribute
+// of the resource returned by the operation or an empty string if the
+// resource has no such attribute
+func lastModifiedField(op *openapi3.Operation) string {
+	resp := op.Responses["200"]
+	if resp == nil || resp.Value == nil {
+		return ""
+	}
+	mt := resp.Value.Content.Get(jsonapiContent)
+	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+		return ""
+	}
+	data := mt.Schema.Value.Properties["data"]
+	if data == nil || data.Value == nil || data.Value.Type != "object" {
+		return ""
+	}
+	attrs := data.Value.Properties["attributes"]
+	if attrs == nil || attrs.Value == nil {
+		return ""
+	}
+	updatedAt := attrs.Value.Properties["updatedAt"]
+	if updatedAt == nil || updatedAt.Value == nil ||
+		updatedAt.Value.Type != "string" || updatedAt.Value.Format != "date-time" {
+		return ""
+	}
+	return goNameHelper("updatedAt")
+}
+
 var asciiName = regexp.MustCompile("([^a-zA-Z]+)")
 
+// OperationName returns the name of the operation, which is the
+// OperationID if given or a name generated based on method and pattern.
+// It is used for the handler, the service function and the route name.
+func OperationName(method string, op *openapi3.Operation, pattern string) string {
+	oid := strings.Title(op.OperationID)
+	if oid == "" {
+		oid = generateName(method, op, pattern)
+	}
+	return oid
+}
+
 func generateName(method string, op *openapi3.Operation, pattern string) string {
 	name := method
 	parts := strings.Split(asciiName.ReplaceAllString(pattern, "/"), "/")
@@ -0,0 +1,54 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// jsonStringExtension marks integer properties that are marshalled
+// as JSON strings (x-json-string: true), see runtime.StringInt64
+const jsonStringExtension = "x-json-string"
+
+// isStringInt64 returns true if the property is an int64 that is
+// represented as JSON string. This is opt-in using the json string
+// extension on properties of type integer or string with format int64,
+// other properties keep their go type
+func isStringInt64(schema *openapi3.Schema) (bool, error) {
+	switch schema.Type {
+	case "string":
+		if schema.Format != "int64" {
+			return false, nil
+		}
+	case "integer":
+		if schema.Format == "int32" {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	return hasJSONStringExtension(schema)
+}
+
+func hasJSONStringExtension(schema *openapi3.Schema) (bool, error) {
+	value, ok := schema.Extensions[jsonStringExtension]
+	if !ok {
+		return false, nil
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case json.RawMessage:
+		var str bool
+		if err := json.Unmarshal(v, &str); err != nil {
+			return false, fmt.Errorf("invalid value for %s: %s", jsonStringExtension, v)
+		}
+		return str, nil
+	}
+	return false, fmt.Errorf("invalid value for %s: %v", jsonStringExtension, value)
+}
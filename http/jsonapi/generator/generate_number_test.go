@@ -0,0 +1,73 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const numberSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Numbers", "version": "1.0"},
+	"paths": {},
+	"components": {
+		"schemas": {
+			"Payment": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "string", "format": "uuid"},
+					"type": {"type": "string", "enum": ["payment"]},
+					"attributes": {
+						"type": "object",
+						"properties": {
+							"amount": {"type": "integer", "format": "int64", "x-json-string": true},
+							"customerId": {"type": "string", "format": "int64", "x-json-string": true},
+							"orderId": {"type": "string", "format": "int64"},
+							"priority": {"type": "integer", "enum": [1, 2, 3], "x-json-string": true},
+							"quantity": {"type": "integer", "format": "int64"},
+							"retries": {"type": "integer", "format": "int32", "x-json-string": true}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+func TestStringInt64(t *testing.T) {
+	schema, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(numberSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := Generator{}
+	result, err := g.BuildSchema(schema, "numbers", "numbers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{
+		"Amount     runtime.StringInt64",
+		"CustomerID runtime.StringInt64",
+		"OrderID    string",
+		"Priority   runtime.StringInt64 `json:\"priority,omitempty\" jsonapi:\"attr,priority,omitempty\" valid:\"optional,in(1|2|3)\"`",
+		"Quantity   int64",
+		"Retries    int32",
+	} {
+		if !strings.Contains(result, field) {
+			t.Errorf("Expected field %q, got:\n%s", field, result)
+		}
+	}
+}
+
+func TestStringInt64InvalidExtension(t *testing.T) {
+	schema := openapi3.NewInt64Schema()
+	schema.Extensions = map[string]interface{}{jsonStringExtension: "yes"}
+	if _, err := isStringInt64(schema); err == nil {
+		t.Error("Expected error for invalid extension value")
+	}
+}
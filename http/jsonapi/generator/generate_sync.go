@@ -0,0 +1,67 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+)
+
+// syncExtension marks list operations that support delta
+// syncs (x-sync: true), see runtime.MarshalDelta
+const syncExtension = "x-sync"
+
+// addSyncParameters adds the sync token query parameter to all operations
+// with the sync extension, the generated request type has the token
+// as ParamSince field
+func addSyncParameters(schema *openapi3.Swagger) error {
+	for pattern, path := range schema.Paths {
+		for method, op := range path.Operations() {
+			sync, err := hasSyncExtension(op)
+			if err != nil {
+				return fmt.Errorf("%s %s: %v", method, pattern, err)
+			}
+			if !sync || hasQueryParameter(op, runtime.SyncTokenParameter) {
+				continue
+			}
+
+			param := openapi3.NewQueryParameter(runtime.SyncTokenParameter).
+				WithDescription("sync token of the last delta, returns all resources if empty").
+				WithSchema(openapi3.NewStringSchema())
+			op.Parameters = append(op.Parameters, &openapi3.ParameterRef{Value: param})
+		}
+	}
+	return nil
+}
+
+func hasSyncExtension(op *openapi3.Operation) (bool, error) {
+	value, ok := op.Extensions[syncExtension]
+	if !ok {
+		return false, nil
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case json.RawMessage:
+		var sync bool
+		if err := json.Unmarshal(v, &sync); err != nil {
+			return false, fmt.Errorf("invalid value for %s: %s", syncExtension, v)
+		}
+		return sync, nil
+	}
+	return false, fmt.Errorf("invalid value for %s: %v", syncExtension, value)
+}
+
+func hasQueryParameter(op *openapi3.Operation, name string) bool {
+	for _, param := range op.Parameters {
+		if param.Value != nil && param.Value.In == "query" && param.Value.Name == name {
+			return true
+		}
+	}
+	return false
+}
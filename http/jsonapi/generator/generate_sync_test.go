@@ -0,0 +1,60 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const syncSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Sync", "version": "1.0"},
+	"paths": {
+		"/articles": {
+			"get": {
+				"operationId": "GetArticles",
+				"x-sync": true,
+				"responses": {"200": {"description": "delta of the articles"}}
+			}
+		},
+		"/comments": {
+			"get": {
+				"operationId": "GetComments",
+				"responses": {"200": {"description": "all comments"}}
+			}
+		}
+	}
+}`
+
+func TestSyncParameters(t *testing.T) {
+	schema, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(syncSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := Generator{}
+	result, err := g.BuildSchema(schema, "sync", "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(result, "ParamSince string") != 1 {
+		t.Errorf("Expected only the synced operation to have the since parameter, got:\n%s", result)
+	}
+	if !strings.Contains(result, `Name:     "since"`) {
+		t.Errorf("Expected since parameter to be scanned, got:\n%s", result)
+	}
+
+	// the parameter is only added once
+	err = addSyncParameters(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(schema.Paths["/articles"].Get.Parameters); n != 1 {
+		t.Errorf("Expected 1 parameter, got: %d", n)
+	}
+}
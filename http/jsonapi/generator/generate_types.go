@@ -138,7 +138,22 @@ func (g *Generator) buildType(prefix string, stmt *jen.Statement, schema *openap
 			return nil
 		}
 
-		err := g.goType(stmt, val, tags)
+		// int64 that is marshalled as string to not lose precision in JavaScript
+		str, err := isStringInt64(val)
+		if err != nil {
+			return fmt.Errorf("%s: %v", prefix, err)
+		}
+		if str {
+			// only keep the validators of the regular go type
+			err = g.goType(jen.Null(), val, tags)
+			if err != nil {
+				return err
+			}
+			stmt.Qual(pkgJSONAPIRuntime, "StringInt64")
+			return nil
+		}
+
+		err = g.goType(stmt, val, tags)
 		if err != nil {
 			return err
 		}
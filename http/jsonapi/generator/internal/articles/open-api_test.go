@@ -48,7 +48,8 @@ type MapTypeString map[string]string
 
 /*
 UpdateArticleCommentsHandler handles request/response marshaling and validation for
- Patch /api/articles/{uuid}/relationships/comments
+
+	Patch /api/articles/{uuid}/relationships/comments
 */
 func UpdateArticleCommentsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -59,8 +60,10 @@ func UpdateArticleCommentsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("articles", "UpdateArticleComments", "/api/articles/{uuid}/relationships/comments", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateArticleCommentsResponseWriter{
-			ResponseWriter: metrics.NewMetric("articles", "/api/articles/{uuid}/relationships/comments", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateArticleCommentsRequest{
 			Request: r.WithContext(ctx),
@@ -98,7 +101,8 @@ func UpdateArticleCommentsHandler(service Service) http.Handler {
 
 /*
 UpdateArticleInlineTypeHandler handles request/response marshaling and validation for
- Patch /api/articles/{uuid}/relationships/inline
+
+	Patch /api/articles/{uuid}/relationships/inline
 */
 func UpdateArticleInlineTypeHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -109,8 +113,10 @@ func UpdateArticleInlineTypeHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("articles", "UpdateArticleInlineType", "/api/articles/{uuid}/relationships/inline", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateArticleInlineTypeResponseWriter{
-			ResponseWriter: metrics.NewMetric("articles", "/api/articles/{uuid}/relationships/inline", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateArticleInlineTypeRequest{
 			Request: r.WithContext(ctx),
@@ -143,7 +149,8 @@ func UpdateArticleInlineTypeHandler(service Service) http.Handler {
 
 /*
 UpdateArticleInlineRefHandler handles request/response marshaling and validation for
- Patch /api/articles/{uuid}/relationships/inlineref
+
+	Patch /api/articles/{uuid}/relationships/inlineref
 */
 func UpdateArticleInlineRefHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -154,8 +161,10 @@ func UpdateArticleInlineRefHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("articles", "UpdateArticleInlineRef", "/api/articles/{uuid}/relationships/inlineref", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateArticleInlineRefResponseWriter{
-			ResponseWriter: metrics.NewMetric("articles", "/api/articles/{uuid}/relationships/inlineref", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateArticleInlineRefRequest{
 			Request: r.WithContext(ctx),
@@ -319,5 +328,8 @@ func Router(service Service) *mux.Router {
 	s1.Methods("PATCH").Path("/api/articles/{uuid}/relationships/comments").Handler(UpdateArticleCommentsHandler(service)).Name("UpdateArticleComments")
 	s1.Methods("PATCH").Path("/api/articles/{uuid}/relationships/inline").Handler(UpdateArticleInlineTypeHandler(service)).Name("UpdateArticleInlineType")
 	s1.Methods("PATCH").Path("/api/articles/{uuid}/relationships/inlineref").Handler(UpdateArticleInlineRefHandler(service)).Name("UpdateArticleInlineRef")
+	s1.Methods("OPTIONS").Path("/api/articles/{uuid}/relationships/comments").Handler(runtime.OptionsHandler("OPTIONS", "PATCH"))
+	s1.Methods("OPTIONS").Path("/api/articles/{uuid}/relationships/inline").Handler(runtime.OptionsHandler("OPTIONS", "PATCH"))
+	s1.Methods("OPTIONS").Path("/api/articles/{uuid}/relationships/inlineref").Handler(runtime.OptionsHandler("OPTIONS", "PATCH"))
 	return router
 }
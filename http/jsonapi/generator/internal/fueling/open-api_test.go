@@ -123,7 +123,8 @@ type Currency string
 
 /*
 ProcessPaymentHandler handles request/response marshaling and validation for
- Post /beta/gas-station/{gasStationId}/payment
+
+	Post /beta/gas-station/{gasStationId}/payment
 */
 func ProcessPaymentHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -134,8 +135,10 @@ func ProcessPaymentHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("fueling", "ProcessPayment", "/beta/gas-station/{gasStationId}/payment", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := processPaymentResponseWriter{
-			ResponseWriter: metrics.NewMetric("fueling", "/beta/gas-station/{gasStationId}/payment", w, r),
+			ResponseWriter: metric,
 		}
 		request := ProcessPaymentRequest{
 			Request: r.WithContext(ctx),
@@ -168,7 +171,8 @@ func ProcessPaymentHandler(service Service) http.Handler {
 
 /*
 ApproachingAtTheForecourtHandler handles request/response marshaling and validation for
- Post /beta/gas-stations/{gasStationId}/approaching
+
+	Post /beta/gas-stations/{gasStationId}/approaching
 */
 func ApproachingAtTheForecourtHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -179,8 +183,10 @@ func ApproachingAtTheForecourtHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("fueling", "ApproachingAtTheForecourt", "/beta/gas-stations/{gasStationId}/approaching", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := approachingAtTheForecourtResponseWriter{
-			ResponseWriter: metrics.NewMetric("fueling", "/beta/gas-stations/{gasStationId}/approaching", w, r),
+			ResponseWriter: metric,
 		}
 		request := ApproachingAtTheForecourtRequest{
 			Request: r.WithContext(ctx),
@@ -217,7 +223,8 @@ func ApproachingAtTheForecourtHandler(service Service) http.Handler {
 
 /*
 GetPumpHandler handles request/response marshaling and validation for
- Get /beta/gas-stations/{gasStationId}/pumps/{pumpId}
+
+	Get /beta/gas-stations/{gasStationId}/pumps/{pumpId}
 */
 func GetPumpHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -228,8 +235,10 @@ func GetPumpHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("fueling", "GetPump", "/beta/gas-stations/{gasStationId}/pumps/{pumpId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPumpResponseWriter{
-			ResponseWriter: metrics.NewMetric("fueling", "/beta/gas-stations/{gasStationId}/pumps/{pumpId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPumpRequest{
 			Request: r.WithContext(ctx),
@@ -264,7 +273,8 @@ func GetPumpHandler(service Service) http.Handler {
 
 /*
 WaitOnPumpStatusChangeHandler handles request/response marshaling and validation for
- Get /beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change
+
+	Get /beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change
 */
 func WaitOnPumpStatusChangeHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -275,8 +285,10 @@ func WaitOnPumpStatusChangeHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("fueling", "WaitOnPumpStatusChange", "/beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := waitOnPumpStatusChangeResponseWriter{
-			ResponseWriter: metrics.NewMetric("fueling", "/beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change", w, r),
+			ResponseWriter: metric,
 		}
 		request := WaitOnPumpStatusChangeRequest{
 			Request: r.WithContext(ctx),
@@ -374,7 +386,7 @@ func (w *processPaymentResponseWriter) BadRequest(err error) {
 
 // Created responds with jsonapi marshaled data (HTTP code 201)
 func (w *processPaymentResponseWriter) Created(data *ProcessPaymentCreated) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // ProcessPaymentRequest ...
@@ -410,7 +422,7 @@ func (w *approachingAtTheForecourtResponseWriter) BadRequest(err error) {
 
 // Created responds with jsonapi marshaled data (HTTP code 201)
 func (w *approachingAtTheForecourtResponseWriter) Created(data ApproachingResponse) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // ApproachingAtTheForecourtRequest ...
@@ -441,7 +453,7 @@ func (w *getPumpResponseWriter) NotFound(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPumpResponseWriter) OK(data PumpResponse) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -486,7 +498,7 @@ func (w *waitOnPumpStatusChangeResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *waitOnPumpStatusChangeResponseWriter) OK(data PumpResponse) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -545,8 +557,14 @@ func Router(service Service) *mux.Router {
 	// Subrouter s1 - Path: /fueling
 	s1 := router.PathPrefix("/fueling").Subrouter()
 	s1.Methods("GET").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change").Handler(WaitOnPumpStatusChangeHandler(service)).Name("WaitOnPumpStatusChange")
+	s1.Methods("HEAD").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change").Handler(runtime.HeadHandler(WaitOnPumpStatusChangeHandler(service))).Name("WaitOnPumpStatusChange")
 	s1.Methods("GET").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}").Handler(GetPumpHandler(service)).Name("GetPump")
+	s1.Methods("HEAD").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}").Handler(runtime.HeadHandler(GetPumpHandler(service))).Name("GetPump")
 	s1.Methods("POST").Path("/beta/gas-station/{gasStationId}/payment").Handler(ProcessPaymentHandler(service)).Name("ProcessPayment")
 	s1.Methods("POST").Path("/beta/gas-stations/{gasStationId}/approaching").Handler(ApproachingAtTheForecourtHandler(service)).Name("ApproachingAtTheForecourt")
+	s1.Methods("OPTIONS").Path("/beta/gas-station/{gasStationId}/payment").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/gas-stations/{gasStationId}/approaching").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/gas-stations/{gasStationId}/pumps/{pumpId}/wait-for-status-change").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
 	return router
 }
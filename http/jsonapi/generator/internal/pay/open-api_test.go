@@ -78,7 +78,8 @@ type Currency string
 
 /*
 GetPaymentMethodsHandler handles request/response marshaling and validation for
- Get /beta/payment-methods
+
+	Get /beta/payment-methods
 */
 func GetPaymentMethodsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -89,8 +90,10 @@ func GetPaymentMethodsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "GetPaymentMethods", "/beta/payment-methods", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPaymentMethodsResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPaymentMethodsRequest{
 			Request: r.WithContext(ctx),
@@ -108,7 +111,8 @@ func GetPaymentMethodsHandler(service Service) http.Handler {
 
 /*
 CreatePaymentMethodSEPAHandler handles request/response marshaling and validation for
- Post /beta/payment-methods/sepa-direct-debit
+
+	Post /beta/payment-methods/sepa-direct-debit
 */
 func CreatePaymentMethodSEPAHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -119,8 +123,10 @@ func CreatePaymentMethodSEPAHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "CreatePaymentMethodSEPA", "/beta/payment-methods/sepa-direct-debit", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := createPaymentMethodSEPAResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods/sepa-direct-debit", w, r),
+			ResponseWriter: metric,
 		}
 		request := CreatePaymentMethodSEPARequest{
 			Request: r.WithContext(ctx),
@@ -144,7 +150,8 @@ func CreatePaymentMethodSEPAHandler(service Service) http.Handler {
 
 /*
 DeletePaymentMethodHandler handles request/response marshaling and validation for
- Delete /beta/payment-methods/{paymentMethodId}
+
+	Delete /beta/payment-methods/{paymentMethodId}
 */
 func DeletePaymentMethodHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -155,8 +162,10 @@ func DeletePaymentMethodHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "DeletePaymentMethod", "/beta/payment-methods/{paymentMethodId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := deletePaymentMethodResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods/{paymentMethodId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := DeletePaymentMethodRequest{
 			Request: r.WithContext(ctx),
@@ -186,7 +195,8 @@ func DeletePaymentMethodHandler(service Service) http.Handler {
 
 /*
 AuthorizePaymentMethodHandler handles request/response marshaling and validation for
- Post /beta/payment-methods/{paymentMethodId}/authorize
+
+	Post /beta/payment-methods/{paymentMethodId}/authorize
 */
 func AuthorizePaymentMethodHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -197,8 +207,10 @@ func AuthorizePaymentMethodHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "AuthorizePaymentMethod", "/beta/payment-methods/{paymentMethodId}/authorize", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := authorizePaymentMethodResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods/{paymentMethodId}/authorize", w, r),
+			ResponseWriter: metric,
 		}
 		request := AuthorizePaymentMethodRequest{
 			Request: r.WithContext(ctx),
@@ -231,7 +243,8 @@ func AuthorizePaymentMethodHandler(service Service) http.Handler {
 
 /*
 DeletePaymentTokenHandler handles request/response marshaling and validation for
- Delete /beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}
+
+	Delete /beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}
 */
 func DeletePaymentTokenHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -242,8 +255,10 @@ func DeletePaymentTokenHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "DeletePaymentToken", "/beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := deletePaymentTokenResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := DeletePaymentTokenRequest{
 			Request: r.WithContext(ctx),
@@ -278,7 +293,8 @@ func DeletePaymentTokenHandler(service Service) http.Handler {
 
 /*
 GetPaymentMethodsIncludingCreditCheckHandler handles request/response marshaling and validation for
- Get /beta/payment-methods?include=creditCheck
+
+	Get /beta/payment-methods?include=creditCheck
 */
 func GetPaymentMethodsIncludingCreditCheckHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -289,8 +305,10 @@ func GetPaymentMethodsIncludingCreditCheckHandler(service Service) http.Handler
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "GetPaymentMethodsIncludingCreditCheck", "/beta/payment-methods?include=creditCheck", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPaymentMethodsIncludingCreditCheckResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods?include=creditCheck", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPaymentMethodsIncludingCreditCheckRequest{
 			Request: r.WithContext(ctx),
@@ -318,7 +336,8 @@ func GetPaymentMethodsIncludingCreditCheckHandler(service Service) http.Handler
 
 /*
 GetPaymentMethodsIncludingPaymentTokenHandler handles request/response marshaling and validation for
- Get /beta/payment-methods?include=paymentToken
+
+	Get /beta/payment-methods?include=paymentToken
 */
 func GetPaymentMethodsIncludingPaymentTokenHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -329,8 +348,10 @@ func GetPaymentMethodsIncludingPaymentTokenHandler(service Service) http.Handler
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "GetPaymentMethodsIncludingPaymentToken", "/beta/payment-methods?include=paymentToken", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPaymentMethodsIncludingPaymentTokenResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/payment-methods?include=paymentToken", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPaymentMethodsIncludingPaymentTokenRequest{
 			Request: r.WithContext(ctx),
@@ -358,7 +379,8 @@ func GetPaymentMethodsIncludingPaymentTokenHandler(service Service) http.Handler
 
 /*
 ProcessPaymentHandler handles request/response marshaling and validation for
- Post /beta/transaction
+
+	Post /beta/transaction
 */
 func ProcessPaymentHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -369,8 +391,10 @@ func ProcessPaymentHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("pay", "ProcessPayment", "/beta/transaction", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := processPaymentResponseWriter{
-			ResponseWriter: metrics.NewMetric("pay", "/beta/transaction", w, r),
+			ResponseWriter: metric,
 		}
 		request := ProcessPaymentRequest{
 			Request: r.WithContext(ctx),
@@ -406,7 +430,7 @@ type getPaymentMethodsResponseWriter struct {
 
 // AllThePaymentMethodsForUser responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPaymentMethodsResponseWriter) AllThePaymentMethodsForUser(data AllPaymentMethods) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -444,7 +468,7 @@ func (w *createPaymentMethodSEPAResponseWriter) BadRequest(err error) {
 
 // Created responds with jsonapi marshaled data (HTTP code 201)
 func (w *createPaymentMethodSEPAResponseWriter) Created(data *CreatePaymentMethodSEPACreated) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // CreatePaymentMethodSEPARequest ...
@@ -526,7 +550,7 @@ func (w *authorizePaymentMethodResponseWriter) AmountCannotBeAuthorized(err erro
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *authorizePaymentMethodResponseWriter) OK(data *AuthorizePaymentMethodOK) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 // AuthorizePaymentMethodContent ...
@@ -591,7 +615,7 @@ type getPaymentMethodsIncludingCreditCheckResponseWriter struct {
 
 // AllThePaymentMethodsThatCouldBeUsed responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPaymentMethodsIncludingCreditCheckResponseWriter) AllThePaymentMethodsThatCouldBeUsed(data AllPaymentMethods) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -617,7 +641,7 @@ type getPaymentMethodsIncludingPaymentTokenResponseWriter struct {
 
 // AllThePaymentMethodsWithPreAuthorisedAmounts responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPaymentMethodsIncludingPaymentTokenResponseWriter) AllThePaymentMethodsWithPreAuthorisedAmounts(data PaymentMethodsWithPaymentTokens) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -686,7 +710,7 @@ func (w *processPaymentResponseWriter) BadRequest(err error) {
 
 // Created responds with jsonapi marshaled data (HTTP code 201)
 func (w *processPaymentResponseWriter) Created(data *ProcessPaymentCreated) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // ProcessPaymentRequest ...
@@ -751,13 +775,22 @@ func Router(service Service) *mux.Router {
 	router := mux.NewRouter()
 	// Subrouter s1 - Path: /pay
 	s1 := router.PathPrefix("/pay").Subrouter()
+	s1.Methods("GET").Path("/beta/payment-methods").Handler(GetPaymentMethodsIncludingCreditCheckHandler(service)).Queries("include", "creditCheck").Name("GetPaymentMethodsIncludingCreditCheck")
+	s1.Methods("HEAD").Path("/beta/payment-methods").Handler(runtime.HeadHandler(GetPaymentMethodsIncludingCreditCheckHandler(service))).Queries("include", "creditCheck").Name("GetPaymentMethodsIncludingCreditCheck")
+	s1.Methods("GET").Path("/beta/payment-methods").Handler(GetPaymentMethodsIncludingPaymentTokenHandler(service)).Queries("include", "paymentToken").Name("GetPaymentMethodsIncludingPaymentToken")
+	s1.Methods("HEAD").Path("/beta/payment-methods").Handler(runtime.HeadHandler(GetPaymentMethodsIncludingPaymentTokenHandler(service))).Queries("include", "paymentToken").Name("GetPaymentMethodsIncludingPaymentToken")
 	s1.Methods("DELETE").Path("/beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}").Handler(DeletePaymentTokenHandler(service)).Name("DeletePaymentToken")
 	s1.Methods("POST").Path("/beta/payment-methods/{paymentMethodId}/authorize").Handler(AuthorizePaymentMethodHandler(service)).Name("AuthorizePaymentMethod")
 	s1.Methods("POST").Path("/beta/payment-methods/sepa-direct-debit").Handler(CreatePaymentMethodSEPAHandler(service)).Name("CreatePaymentMethodSEPA")
 	s1.Methods("DELETE").Path("/beta/payment-methods/{paymentMethodId}").Handler(DeletePaymentMethodHandler(service)).Name("DeletePaymentMethod")
-	s1.Methods("GET").Path("/beta/payment-methods").Handler(GetPaymentMethodsIncludingPaymentTokenHandler(service)).Queries("include", "paymentToken").Name("GetPaymentMethodsIncludingPaymentToken")
-	s1.Methods("GET").Path("/beta/payment-methods").Handler(GetPaymentMethodsIncludingCreditCheckHandler(service)).Queries("include", "creditCheck").Name("GetPaymentMethodsIncludingCreditCheck")
 	s1.Methods("GET").Path("/beta/payment-methods").Handler(GetPaymentMethodsHandler(service)).Name("GetPaymentMethods")
+	s1.Methods("HEAD").Path("/beta/payment-methods").Handler(runtime.HeadHandler(GetPaymentMethodsHandler(service))).Name("GetPaymentMethods")
 	s1.Methods("POST").Path("/beta/transaction").Handler(ProcessPaymentHandler(service)).Name("ProcessPayment")
+	s1.Methods("OPTIONS").Path("/beta/payment-methods").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/payment-methods/sepa-direct-debit").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/payment-methods/{paymentMethodId}").Handler(runtime.OptionsHandler("DELETE", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/payment-methods/{paymentMethodId}/authorize").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/payment-methods/{paymentMethodId}/paymentTokens/{paymentTokenId}").Handler(runtime.OptionsHandler("DELETE", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/transaction").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
 	return router
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pace/bricks/http/jsonapi/generator/routetest"
 	"github.com/pace/bricks/http/jsonapi/runtime"
 	"github.com/pace/bricks/maintenance/log"
 )
@@ -149,3 +150,7 @@ func TestHandlerError(t *testing.T) {
 		t.Error(string(b[:]))
 	}
 }
+
+func TestRouteReachability(t *testing.T) {
+	routetest.AssertReachable(t, "open-api.json", Router(&testService{t}), nil)
+}
@@ -245,7 +245,8 @@ type FuelAmountUnit string
 
 /*
 GetAppsHandler handles request/response marshaling and validation for
- Get /beta/apps
+
+	Get /beta/apps
 */
 func GetAppsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -256,8 +257,10 @@ func GetAppsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetApps", "/beta/apps", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getAppsResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetAppsRequest{
 			Request: r.WithContext(ctx),
@@ -297,7 +300,8 @@ func GetAppsHandler(service Service) http.Handler {
 
 /*
 CreateAppHandler handles request/response marshaling and validation for
- Post /beta/apps
+
+	Post /beta/apps
 */
 func CreateAppHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -308,8 +312,10 @@ func CreateAppHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "CreateApp", "/beta/apps", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := createAppResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps", w, r),
+			ResponseWriter: metric,
 		}
 		request := CreateAppRequest{
 			Request: r.WithContext(ctx),
@@ -333,7 +339,8 @@ func CreateAppHandler(service Service) http.Handler {
 
 /*
 CheckForPaceAppHandler handles request/response marshaling and validation for
- Get /beta/apps/query
+
+	Get /beta/apps/query
 */
 func CheckForPaceAppHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -344,8 +351,10 @@ func CheckForPaceAppHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "CheckForPaceApp", "/beta/apps/query", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := checkForPaceAppResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/query", w, r),
+			ResponseWriter: metric,
 		}
 		request := CheckForPaceAppRequest{
 			Request: r.WithContext(ctx),
@@ -401,7 +410,8 @@ func CheckForPaceAppHandler(service Service) http.Handler {
 
 /*
 DeleteAppHandler handles request/response marshaling and validation for
- Delete /beta/apps/{appID}
+
+	Delete /beta/apps/{appID}
 */
 func DeleteAppHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -412,8 +422,10 @@ func DeleteAppHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "DeleteApp", "/beta/apps/{appID}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := deleteAppResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/{appID}", w, r),
+			ResponseWriter: metric,
 		}
 		request := DeleteAppRequest{
 			Request: r.WithContext(ctx),
@@ -443,7 +455,8 @@ func DeleteAppHandler(service Service) http.Handler {
 
 /*
 GetAppHandler handles request/response marshaling and validation for
- Get /beta/apps/{appID}
+
+	Get /beta/apps/{appID}
 */
 func GetAppHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -454,8 +467,10 @@ func GetAppHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetApp", "/beta/apps/{appID}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getAppResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/{appID}", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetAppRequest{
 			Request: r.WithContext(ctx),
@@ -485,7 +500,8 @@ func GetAppHandler(service Service) http.Handler {
 
 /*
 UpdateAppHandler handles request/response marshaling and validation for
- Put /beta/apps/{appID}
+
+	Put /beta/apps/{appID}
 */
 func UpdateAppHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -496,8 +512,10 @@ func UpdateAppHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "UpdateApp", "/beta/apps/{appID}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateAppResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/{appID}", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateAppRequest{
 			Request: r.WithContext(ctx),
@@ -530,7 +548,8 @@ func UpdateAppHandler(service Service) http.Handler {
 
 /*
 GetAppPOIsRelationshipsHandler handles request/response marshaling and validation for
- Get /beta/apps/{appID}/relationships/pois
+
+	Get /beta/apps/{appID}/relationships/pois
 */
 func GetAppPOIsRelationshipsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -541,8 +560,10 @@ func GetAppPOIsRelationshipsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetAppPOIsRelationships", "/beta/apps/{appID}/relationships/pois", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getAppPOIsRelationshipsResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/{appID}/relationships/pois", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetAppPOIsRelationshipsRequest{
 			Request: r.WithContext(ctx),
@@ -572,7 +593,8 @@ func GetAppPOIsRelationshipsHandler(service Service) http.Handler {
 
 /*
 UpdateAppPOIsRelationshipsHandler handles request/response marshaling and validation for
- Patch /beta/apps/{appID}/relationships/pois
+
+	Patch /beta/apps/{appID}/relationships/pois
 */
 func UpdateAppPOIsRelationshipsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -583,8 +605,10 @@ func UpdateAppPOIsRelationshipsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "UpdateAppPOIsRelationships", "/beta/apps/{appID}/relationships/pois", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateAppPOIsRelationshipsResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/apps/{appID}/relationships/pois", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateAppPOIsRelationshipsRequest{
 			Request: r.WithContext(ctx),
@@ -617,7 +641,8 @@ func UpdateAppPOIsRelationshipsHandler(service Service) http.Handler {
 
 /*
 GetEventsHandler handles request/response marshaling and validation for
- Get /beta/events
+
+	Get /beta/events
 */
 func GetEventsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -628,8 +653,10 @@ func GetEventsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetEvents", "/beta/events", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getEventsResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/events", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetEventsRequest{
 			Request: r.WithContext(ctx),
@@ -669,7 +696,8 @@ func GetEventsHandler(service Service) http.Handler {
 
 /*
 GetGasStationsHandler handles request/response marshaling and validation for
- Get /beta/gas-stations
+
+	Get /beta/gas-stations
 */
 func GetGasStationsHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -680,8 +708,10 @@ func GetGasStationsHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetGasStations", "/beta/gas-stations", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getGasStationsResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/gas-stations", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetGasStationsRequest{
 			Request: r.WithContext(ctx),
@@ -757,7 +787,8 @@ func GetGasStationsHandler(service Service) http.Handler {
 
 /*
 GetGasStationHandler handles request/response marshaling and validation for
- Get /beta/gas-stations/{id}
+
+	Get /beta/gas-stations/{id}
 */
 func GetGasStationHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -768,8 +799,10 @@ func GetGasStationHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetGasStation", "/beta/gas-stations/{id}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getGasStationResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/gas-stations/{id}", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetGasStationRequest{
 			Request: r.WithContext(ctx),
@@ -799,7 +832,8 @@ func GetGasStationHandler(service Service) http.Handler {
 
 /*
 GetPoisHandler handles request/response marshaling and validation for
- Get /beta/pois
+
+	Get /beta/pois
 */
 func GetPoisHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -810,8 +844,10 @@ func GetPoisHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetPois", "/beta/pois", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPoisResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/pois", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPoisRequest{
 			Request: r.WithContext(ctx),
@@ -855,7 +891,8 @@ func GetPoisHandler(service Service) http.Handler {
 
 /*
 GetPoiHandler handles request/response marshaling and validation for
- Get /beta/pois/{poiId}
+
+	Get /beta/pois/{poiId}
 */
 func GetPoiHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -866,8 +903,11 @@ func GetPoiHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetPoi", "/beta/pois/{poiId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPoiResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/pois/{poiId}", w, r),
+			ResponseWriter: metric,
+			request:        r,
 		}
 		request := GetPoiRequest{
 			Request: r.WithContext(ctx),
@@ -887,6 +927,18 @@ func GetPoiHandler(service Service) http.Handler {
 			return // invalid request stop further processing
 		}
 
+		// Respond with not modified if the resource wasn't modified since the client cached it
+		if lm, ok := service.(GetPoiLastModifier); ok {
+			lastModified, err := lm.GetPoiLastModified(ctx, &request)
+			if err != nil {
+				errors.HandleError(err, "GetPoiHandler", w, r)
+				return
+			}
+			if runtime.NotModified(w, r, lastModified) {
+				return
+			}
+		}
+
 		// Invoke service that implements the business logic
 		err := service.GetPoi(ctx, &writer, &request)
 		if err != nil {
@@ -897,7 +949,8 @@ func GetPoiHandler(service Service) http.Handler {
 
 /*
 ChangePoiHandler handles request/response marshaling and validation for
- Patch /beta/pois/{poiId}
+
+	Patch /beta/pois/{poiId}
 */
 func ChangePoiHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -908,8 +961,10 @@ func ChangePoiHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "ChangePoi", "/beta/pois/{poiId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := changePoiResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/pois/{poiId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := ChangePoiRequest{
 			Request: r.WithContext(ctx),
@@ -942,7 +997,8 @@ func ChangePoiHandler(service Service) http.Handler {
 
 /*
 GetPoliciesHandler handles request/response marshaling and validation for
- Get /beta/policies
+
+	Get /beta/policies
 */
 func GetPoliciesHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -953,8 +1009,10 @@ func GetPoliciesHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetPolicies", "/beta/policies", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPoliciesResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/policies", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPoliciesRequest{
 			Request: r.WithContext(ctx),
@@ -998,7 +1056,8 @@ func GetPoliciesHandler(service Service) http.Handler {
 
 /*
 CreatePolicyHandler handles request/response marshaling and validation for
- Post /beta/policies
+
+	Post /beta/policies
 */
 func CreatePolicyHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1009,8 +1068,10 @@ func CreatePolicyHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "CreatePolicy", "/beta/policies", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := createPolicyResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/policies", w, r),
+			ResponseWriter: metric,
 		}
 		request := CreatePolicyRequest{
 			Request: r.WithContext(ctx),
@@ -1034,7 +1095,8 @@ func CreatePolicyHandler(service Service) http.Handler {
 
 /*
 GetPolicyHandler handles request/response marshaling and validation for
- Get /beta/policies/{policyId}
+
+	Get /beta/policies/{policyId}
 */
 func GetPolicyHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1045,8 +1107,10 @@ func GetPolicyHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetPolicy", "/beta/policies/{policyId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getPolicyResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/policies/{policyId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetPolicyRequest{
 			Request: r.WithContext(ctx),
@@ -1076,7 +1140,8 @@ func GetPolicyHandler(service Service) http.Handler {
 
 /*
 GetSourcesHandler handles request/response marshaling and validation for
- Get /beta/sources
+
+	Get /beta/sources
 */
 func GetSourcesHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1087,8 +1152,10 @@ func GetSourcesHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetSources", "/beta/sources", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getSourcesResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/sources", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetSourcesRequest{
 			Request: r.WithContext(ctx),
@@ -1128,7 +1195,8 @@ func GetSourcesHandler(service Service) http.Handler {
 
 /*
 CreateSourceHandler handles request/response marshaling and validation for
- Post /beta/sources
+
+	Post /beta/sources
 */
 func CreateSourceHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1139,8 +1207,10 @@ func CreateSourceHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "CreateSource", "/beta/sources", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := createSourceResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/sources", w, r),
+			ResponseWriter: metric,
 		}
 		request := CreateSourceRequest{
 			Request: r.WithContext(ctx),
@@ -1164,7 +1234,8 @@ func CreateSourceHandler(service Service) http.Handler {
 
 /*
 DeleteSourceHandler handles request/response marshaling and validation for
- Delete /beta/sources/{sourceId}
+
+	Delete /beta/sources/{sourceId}
 */
 func DeleteSourceHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1175,8 +1246,10 @@ func DeleteSourceHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "DeleteSource", "/beta/sources/{sourceId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := deleteSourceResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/sources/{sourceId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := DeleteSourceRequest{
 			Request: r.WithContext(ctx),
@@ -1206,7 +1279,8 @@ func DeleteSourceHandler(service Service) http.Handler {
 
 /*
 GetSourceHandler handles request/response marshaling and validation for
- Get /beta/sources/{sourceId}
+
+	Get /beta/sources/{sourceId}
 */
 func GetSourceHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1217,8 +1291,11 @@ func GetSourceHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetSource", "/beta/sources/{sourceId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getSourceResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/sources/{sourceId}", w, r),
+			ResponseWriter: metric,
+			request:        r,
 		}
 		request := GetSourceRequest{
 			Request: r.WithContext(ctx),
@@ -1238,6 +1315,18 @@ func GetSourceHandler(service Service) http.Handler {
 			return // invalid request stop further processing
 		}
 
+		// Respond with not modified if the resource wasn't modified since the client cached it
+		if lm, ok := service.(GetSourceLastModifier); ok {
+			lastModified, err := lm.GetSourceLastModified(ctx, &request)
+			if err != nil {
+				errors.HandleError(err, "GetSourceHandler", w, r)
+				return
+			}
+			if runtime.NotModified(w, r, lastModified) {
+				return
+			}
+		}
+
 		// Invoke service that implements the business logic
 		err := service.GetSource(ctx, &writer, &request)
 		if err != nil {
@@ -1248,7 +1337,8 @@ func GetSourceHandler(service Service) http.Handler {
 
 /*
 UpdateSourceHandler handles request/response marshaling and validation for
- Put /beta/sources/{sourceId}
+
+	Put /beta/sources/{sourceId}
 */
 func UpdateSourceHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1259,8 +1349,10 @@ func UpdateSourceHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "UpdateSource", "/beta/sources/{sourceId}", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := updateSourceResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/sources/{sourceId}", w, r),
+			ResponseWriter: metric,
 		}
 		request := UpdateSourceRequest{
 			Request: r.WithContext(ctx),
@@ -1293,7 +1385,8 @@ func UpdateSourceHandler(service Service) http.Handler {
 
 /*
 CreateSubscriptionHandler handles request/response marshaling and validation for
- Post /beta/subscriptions
+
+	Post /beta/subscriptions
 */
 func CreateSubscriptionHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1304,8 +1397,10 @@ func CreateSubscriptionHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "CreateSubscription", "/beta/subscriptions", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := createSubscriptionResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/subscriptions", w, r),
+			ResponseWriter: metric,
 		}
 		request := CreateSubscriptionRequest{
 			Request: r.WithContext(ctx),
@@ -1329,7 +1424,8 @@ func CreateSubscriptionHandler(service Service) http.Handler {
 
 /*
 GetTilesHandler handles request/response marshaling and validation for
- Post /beta/tiles/query
+
+	Post /beta/tiles/query
 */
 func GetTilesHandler(service Service) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1340,8 +1436,10 @@ func GetTilesHandler(service Service) http.Handler {
 		defer handlerSpan.Finish()
 
 		// Setup context, response writer and request type
+		metric := metrics.NewOperationMetric("poi", "GetTiles", "/beta/tiles/query", w, r)
+		defer metric.ObserveSizes(handlerSpan)
 		writer := getTilesResponseWriter{
-			ResponseWriter: metrics.NewMetric("poi", "/beta/tiles/query", w, r),
+			ResponseWriter: metric,
 		}
 		request := GetTilesRequest{
 			Request: r.WithContext(ctx),
@@ -1377,7 +1475,7 @@ func (w *getAppsResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getAppsResponseWriter) OK(data LocationBasedApps) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1412,7 +1510,7 @@ func (w *createAppResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 201)
 func (w *createAppResponseWriter) OK(data *LocationBasedApp) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // CreateAppRequest ...
@@ -1441,7 +1539,7 @@ func (w *checkForPaceAppResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *checkForPaceAppResponseWriter) OK(data LocationBasedApps) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1519,7 +1617,7 @@ func (w *getAppResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getAppResponseWriter) OK(data *LocationBasedApp) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1557,7 +1655,7 @@ func (w *updateAppResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *updateAppResponseWriter) OK(data *LocationBasedApp) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 // UpdateAppRequest ...
@@ -1587,7 +1685,7 @@ func (w *getAppPOIsRelationshipsResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getAppPOIsRelationshipsResponseWriter) OK(data AppPOIsRelationships) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1625,7 +1723,7 @@ func (w *updateAppPOIsRelationshipsResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *updateAppPOIsRelationshipsResponseWriter) OK(data AppPOIsRelationships) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 // UpdateAppPOIsRelationshipsRequest ...
@@ -1649,7 +1747,7 @@ type getEventsResponseWriter struct {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getEventsResponseWriter) OK(data Events) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1684,7 +1782,7 @@ func (w *getGasStationsResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getGasStationsResponseWriter) OK(data GasStations) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1728,7 +1826,7 @@ func (w *getGasStationResponseWriter) NotFound(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getGasStationResponseWriter) OK(data *GasStation) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1760,7 +1858,7 @@ func (w *getPoisResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPoisResponseWriter) OK(data POIs) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1788,6 +1886,7 @@ type GetPoiResponseWriter interface {
 }
 type getPoiResponseWriter struct {
 	http.ResponseWriter
+	request *http.Request
 }
 
 // NotFound responds with jsonapi error (HTTP code 404)
@@ -1802,7 +1901,10 @@ func (w *getPoiResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPoiResponseWriter) OK(data *POI) {
-	runtime.Marshal(w, data, 200)
+	if data != nil && runtime.NotModified(w, w.request, data.UpdatedAt) {
+		return
+	}
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1840,7 +1942,7 @@ func (w *changePoiResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *changePoiResponseWriter) OK(data *POI) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 // ChangePoiRequest ...
@@ -1870,7 +1972,7 @@ func (w *getPoliciesResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPoliciesResponseWriter) OK(data Policies) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1906,7 +2008,7 @@ func (w *createPolicyResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 201)
 func (w *createPolicyResponseWriter) OK(data *Policy) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // CreatePolicyRequest ...
@@ -1941,7 +2043,7 @@ func (w *getPolicyResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getPolicyResponseWriter) OK(data *Policy) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -1973,7 +2075,7 @@ func (w *getSourcesResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getSourcesResponseWriter) OK(data Sources) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -2008,7 +2110,7 @@ func (w *createSourceResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 201)
 func (w *createSourceResponseWriter) OK(data *Source) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // CreateSourceRequest ...
@@ -2062,6 +2164,7 @@ type GetSourceResponseWriter interface {
 }
 type getSourceResponseWriter struct {
 	http.ResponseWriter
+	request *http.Request
 }
 
 // NotFound responds with jsonapi error (HTTP code 404)
@@ -2076,7 +2179,10 @@ func (w *getSourceResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *getSourceResponseWriter) OK(data *Source) {
-	runtime.Marshal(w, data, 200)
+	if data != nil && runtime.NotModified(w, w.request, data.UpdatedAt) {
+		return
+	}
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 /*
@@ -2114,7 +2220,7 @@ func (w *updateSourceResponseWriter) BadRequest(err error) {
 
 // OK responds with jsonapi marshaled data (HTTP code 200)
 func (w *updateSourceResponseWriter) OK(data *Source) {
-	runtime.Marshal(w, data, 200)
+	runtime.Marshal(w.ResponseWriter, data, 200)
 }
 
 // UpdateSourceRequest ...
@@ -2144,7 +2250,7 @@ func (w *createSubscriptionResponseWriter) BadRequest(err error) {
 
 // Created responds with jsonapi marshaled data (HTTP code 201)
 func (w *createSubscriptionResponseWriter) Created(data *Subscription) {
-	runtime.Marshal(w, data, 201)
+	runtime.Marshal(w.ResponseWriter, data, 201)
 }
 
 // CreateSubscriptionRequest ...
@@ -2367,6 +2473,24 @@ type Service interface {
 	GetTiles(context.Context, GetTilesResponseWriter, *GetTilesRequest) error
 }
 
+/*
+GetPoiLastModifier can optionally be implemented by the service to
+cheaply supply the last modification of the resource before it is loaded.
+Requests with an unchanged resource are answered with 304 Not Modified
+*/
+type GetPoiLastModifier interface {
+	GetPoiLastModified(context.Context, *GetPoiRequest) (time.Time, error)
+}
+
+/*
+GetSourceLastModifier can optionally be implemented by the service to
+cheaply supply the last modification of the resource before it is loaded.
+Requests with an unchanged resource are answered with 304 Not Modified
+*/
+type GetSourceLastModifier interface {
+	GetSourceLastModified(context.Context, *GetSourceRequest) (time.Time, error)
+}
+
 /*
 Router implements: PACE POI API
 
@@ -2377,28 +2501,56 @@ func Router(service Service) *mux.Router {
 	// Subrouter s1 - Path: /poi
 	s1 := router.PathPrefix("/poi").Subrouter()
 	s1.Methods("GET").Path("/beta/apps/query").Handler(CheckForPaceAppHandler(service)).Name("CheckForPaceApp")
+	s1.Methods("HEAD").Path("/beta/apps/query").Handler(runtime.HeadHandler(CheckForPaceAppHandler(service))).Name("CheckForPaceApp")
 	s1.Methods("POST").Path("/beta/tiles/query").Handler(GetTilesHandler(service)).Name("GetTiles")
-	s1.Methods("GET").Path("/beta/pois").Handler(GetPoisHandler(service)).Name("GetPois")
+	s1.Methods("GET").Path("/beta/policies").Handler(GetPoliciesHandler(service)).Name("GetPolicies")
+	s1.Methods("HEAD").Path("/beta/policies").Handler(runtime.HeadHandler(GetPoliciesHandler(service))).Name("GetPolicies")
 	s1.Methods("POST").Path("/beta/apps").Handler(CreateAppHandler(service)).Name("CreateApp")
 	s1.Methods("POST").Path("/beta/subscriptions").Handler(CreateSubscriptionHandler(service)).Name("CreateSubscription")
+	s1.Methods("POST").Path("/beta/sources").Handler(CreateSourceHandler(service)).Name("CreateSource")
+	s1.Methods("GET").Path("/beta/apps").Handler(GetAppsHandler(service)).Name("GetApps")
+	s1.Methods("HEAD").Path("/beta/apps").Handler(runtime.HeadHandler(GetAppsHandler(service))).Name("GetApps")
 	s1.Methods("GET").Path("/beta/sources").Handler(GetSourcesHandler(service)).Name("GetSources")
-	s1.Methods("POST").Path("/beta/policies").Handler(CreatePolicyHandler(service)).Name("CreatePolicy")
-	s1.Methods("GET").Path("/beta/policies").Handler(GetPoliciesHandler(service)).Name("GetPolicies")
+	s1.Methods("HEAD").Path("/beta/sources").Handler(runtime.HeadHandler(GetSourcesHandler(service))).Name("GetSources")
 	s1.Methods("GET").Path("/beta/events").Handler(GetEventsHandler(service)).Name("GetEvents")
+	s1.Methods("HEAD").Path("/beta/events").Handler(runtime.HeadHandler(GetEventsHandler(service))).Name("GetEvents")
 	s1.Methods("GET").Path("/beta/gas-stations").Handler(GetGasStationsHandler(service)).Name("GetGasStations")
-	s1.Methods("POST").Path("/beta/sources").Handler(CreateSourceHandler(service)).Name("CreateSource")
-	s1.Methods("GET").Path("/beta/apps").Handler(GetAppsHandler(service)).Name("GetApps")
-	s1.Methods("PATCH").Path("/beta/apps/{appID}/relationships/pois").Handler(UpdateAppPOIsRelationshipsHandler(service)).Name("UpdateAppPOIsRelationships")
+	s1.Methods("HEAD").Path("/beta/gas-stations").Handler(runtime.HeadHandler(GetGasStationsHandler(service))).Name("GetGasStations")
+	s1.Methods("POST").Path("/beta/policies").Handler(CreatePolicyHandler(service)).Name("CreatePolicy")
+	s1.Methods("GET").Path("/beta/pois").Handler(GetPoisHandler(service)).Name("GetPois")
+	s1.Methods("HEAD").Path("/beta/pois").Handler(runtime.HeadHandler(GetPoisHandler(service))).Name("GetPois")
 	s1.Methods("GET").Path("/beta/apps/{appID}/relationships/pois").Handler(GetAppPOIsRelationshipsHandler(service)).Name("GetAppPOIsRelationships")
-	s1.Methods("GET").Path("/beta/gas-stations/{id}").Handler(GetGasStationHandler(service)).Name("GetGasStation")
+	s1.Methods("HEAD").Path("/beta/apps/{appID}/relationships/pois").Handler(runtime.HeadHandler(GetAppPOIsRelationshipsHandler(service))).Name("GetAppPOIsRelationships")
+	s1.Methods("PATCH").Path("/beta/apps/{appID}/relationships/pois").Handler(UpdateAppPOIsRelationshipsHandler(service)).Name("UpdateAppPOIsRelationships")
 	s1.Methods("PATCH").Path("/beta/pois/{poiId}").Handler(ChangePoiHandler(service)).Name("ChangePoi")
+	s1.Methods("GET").Path("/beta/pois/{poiId}").Handler(GetPoiHandler(service)).Name("GetPoi")
+	s1.Methods("HEAD").Path("/beta/pois/{poiId}").Handler(runtime.HeadHandler(GetPoiHandler(service))).Name("GetPoi")
+	s1.Methods("GET").Path("/beta/gas-stations/{id}").Handler(GetGasStationHandler(service)).Name("GetGasStation")
+	s1.Methods("HEAD").Path("/beta/gas-stations/{id}").Handler(runtime.HeadHandler(GetGasStationHandler(service))).Name("GetGasStation")
 	s1.Methods("GET").Path("/beta/policies/{policyId}").Handler(GetPolicyHandler(service)).Name("GetPolicy")
+	s1.Methods("HEAD").Path("/beta/policies/{policyId}").Handler(runtime.HeadHandler(GetPolicyHandler(service))).Name("GetPolicy")
 	s1.Methods("PUT").Path("/beta/apps/{appID}").Handler(UpdateAppHandler(service)).Name("UpdateApp")
-	s1.Methods("GET").Path("/beta/apps/{appID}").Handler(GetAppHandler(service)).Name("GetApp")
 	s1.Methods("DELETE").Path("/beta/sources/{sourceId}").Handler(DeleteSourceHandler(service)).Name("DeleteSource")
 	s1.Methods("GET").Path("/beta/sources/{sourceId}").Handler(GetSourceHandler(service)).Name("GetSource")
+	s1.Methods("HEAD").Path("/beta/sources/{sourceId}").Handler(runtime.HeadHandler(GetSourceHandler(service))).Name("GetSource")
 	s1.Methods("PUT").Path("/beta/sources/{sourceId}").Handler(UpdateSourceHandler(service)).Name("UpdateSource")
+	s1.Methods("GET").Path("/beta/apps/{appID}").Handler(GetAppHandler(service)).Name("GetApp")
+	s1.Methods("HEAD").Path("/beta/apps/{appID}").Handler(runtime.HeadHandler(GetAppHandler(service))).Name("GetApp")
 	s1.Methods("DELETE").Path("/beta/apps/{appID}").Handler(DeleteAppHandler(service)).Name("DeleteApp")
-	s1.Methods("GET").Path("/beta/pois/{poiId}").Handler(GetPoiHandler(service)).Name("GetPoi")
+	s1.Methods("OPTIONS").Path("/beta/apps").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/apps/query").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/apps/{appID}").Handler(runtime.OptionsHandler("DELETE", "GET", "HEAD", "OPTIONS", "PUT"))
+	s1.Methods("OPTIONS").Path("/beta/apps/{appID}/relationships/pois").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS", "PATCH"))
+	s1.Methods("OPTIONS").Path("/beta/events").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/gas-stations").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/gas-stations/{id}").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/pois").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/pois/{poiId}").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS", "PATCH"))
+	s1.Methods("OPTIONS").Path("/beta/policies").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/policies/{policyId}").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS"))
+	s1.Methods("OPTIONS").Path("/beta/sources").Handler(runtime.OptionsHandler("GET", "HEAD", "OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/sources/{sourceId}").Handler(runtime.OptionsHandler("DELETE", "GET", "HEAD", "OPTIONS", "PUT"))
+	s1.Methods("OPTIONS").Path("/beta/subscriptions").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
+	s1.Methods("OPTIONS").Path("/beta/tiles/query").Handler(runtime.OptionsHandler("OPTIONS", "POST"))
 	return router
 }
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pace/bricks/http/jsonapi/runtime"
 )
@@ -204,3 +205,152 @@ func TestCreatePolicyHandler(t *testing.T) {
 		return
 	}
 }
+
+func TestHeadHandler(t *testing.T) {
+	r := Router(&testService{t})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("HEAD", "/poi/beta/apps/query?"+
+		"filter[latitude]=41.859194&filter[longitude]=-87.646984&filter[appType]=fueling&filter[gpsSource]=raw", nil)
+	req.Header.Set("Accept", runtime.JSONAPIContentType)
+	req.Header.Set("Content-Type", runtime.JSONAPIContentType)
+
+	r.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("expected OK got: %d", resp.StatusCode)
+		return
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != runtime.JSONAPIContentType {
+		t.Errorf("expected content type %q got: %q", runtime.JSONAPIContentType, ct)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected no body got: %q", rec.Body.String())
+	}
+}
+
+func TestOptionsHandler(t *testing.T) {
+	r := Router(&testService{t})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("OPTIONS", "/poi/beta/sources/f106ac99-213c-4cf7-8c1b-1e841516026b", nil)
+
+	r.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		t.Errorf("expected No Content got: %d", resp.StatusCode)
+		return
+	}
+	if allow := resp.Header.Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("expected Allow header %q got: %q", "DELETE, GET, HEAD, OPTIONS, PUT", allow)
+	}
+}
+
+type lastModifiedService struct {
+	testService
+	lastModified time.Time
+}
+
+func (s *lastModifiedService) GetPoiLastModified(context.Context, *GetPoiRequest) (time.Time, error) {
+	return s.lastModified, nil
+}
+
+func (s *lastModifiedService) GetPoi(ctx context.Context, w GetPoiResponseWriter, r *GetPoiRequest) error {
+	w.OK(&POI{ID: r.ParamPoiID, UpdatedAt: s.lastModified})
+	return nil
+}
+
+func TestConditionalGetHandler(t *testing.T) {
+	lastModified := time.Date(2019, 4, 1, 12, 30, 15, 0, time.UTC)
+	r := Router(&lastModifiedService{testService{t}, lastModified})
+
+	cases := []struct {
+		title           string
+		ifModifiedSince string
+		code            int
+	}{
+		{"without header", "", 200},
+		{"modified", "Mon, 01 Apr 2019 12:00:00 GMT", 200},
+		{"not modified", "Mon, 01 Apr 2019 12:30:15 GMT", 304},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/poi/beta/pois/f106ac99-213c-4cf7-8c1b-1e841516026b", nil)
+			req.Header.Set("Accept", runtime.JSONAPIContentType)
+			if c.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", c.ifModifiedSince)
+			}
+
+			r.ServeHTTP(rec, req)
+
+			resp := rec.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != c.code {
+				t.Errorf("expected %d got: %d", c.code, resp.StatusCode)
+				t.Error(rec.Body.String())
+			}
+			if lm := resp.Header.Get("Last-Modified"); lm != "Mon, 01 Apr 2019 12:30:15 GMT" {
+				t.Errorf("expected Last-Modified header, got: %q", lm)
+			}
+		})
+	}
+}
+
+type updatedAtService struct {
+	testService
+	lastModified time.Time
+}
+
+func (s *updatedAtService) GetPoi(ctx context.Context, w GetPoiResponseWriter, r *GetPoiRequest) error {
+	w.OK(&POI{ID: r.ParamPoiID, UpdatedAt: s.lastModified})
+	return nil
+}
+
+func TestConditionalGetResponseWriter(t *testing.T) {
+	lastModified := time.Date(2019, 4, 1, 12, 30, 15, 0, time.UTC)
+	r := Router(&updatedAtService{testService{t}, lastModified})
+
+	cases := []struct {
+		title           string
+		ifModifiedSince string
+		code            int
+	}{
+		{"without header", "", 200},
+		{"modified", "Mon, 01 Apr 2019 12:00:00 GMT", 200},
+		{"not modified", "Mon, 01 Apr 2019 12:30:15 GMT", 304},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/poi/beta/pois/f106ac99-213c-4cf7-8c1b-1e841516026b", nil)
+			req.Header.Set("Accept", runtime.JSONAPIContentType)
+			if c.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", c.ifModifiedSince)
+			}
+
+			r.ServeHTTP(rec, req)
+
+			resp := rec.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != c.code {
+				t.Errorf("expected %d got: %d", c.code, resp.StatusCode)
+				t.Error(rec.Body.String())
+			}
+			if lm := resp.Header.Get("Last-Modified"); lm != "Mon, 01 Apr 2019 12:30:15 GMT" {
+				t.Errorf("expected Last-Modified header, got: %q", lm)
+			}
+			if c.code == 304 && rec.Body.Len() != 0 {
+				t.Errorf("expected empty body, got: %q", rec.Body.String())
+			}
+		})
+	}
+}
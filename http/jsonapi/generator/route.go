@@ -16,6 +16,9 @@ type route struct {
 	operation                                   *openapi3.Operation
 	url                                         *url.URL
 	queryValues                                 url.Values
+	// lastModifiedField is the name of the updatedAt field of the
+	// returned resource, if any (used for conditional requests)
+	lastModifiedField string
 }
 
 type sortableRouteList []*route
@@ -29,6 +32,12 @@ func (r *route) parseURL() (err error) {
 	return
 }
 
+// lastModifierInterface returns the name of the optional service interface
+// to supply the last modification of the resource
+func (r *route) lastModifierInterface() string {
+	return r.serviceFunc + "LastModifier"
+}
+
 // Len is the number of elements in the collection.
 func (l *sortableRouteList) Len() int {
 	return len(*l)
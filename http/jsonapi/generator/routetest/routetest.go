@@ -0,0 +1,251 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package routetest asserts that the operations of an OpenAPIv3
+// specification are reachable using a mounted router. It catches drift
+// between regenerated code and manual edits of the router, e.g. removed,
+// renamed or shadowed routes and missing middlewares.
+//
+// Usage in the tests of a service:
+//
+//	func TestRoutes(t *testing.T) {
+//		routetest.AssertReachable(t, "open-api.json", router.Router(), &routetest.Options{
+//			Prepare: func(r *http.Request) {
+//				r.Header.Set("Authorization", "Bearer "+testToken)
+//			},
+//			Middlewares: []routetest.Middleware{{
+//				Name: "oauth2",
+//				Applied: func(r *http.Request) bool {
+//					_, ok := oauth2.BearerToken(r.Context())
+//					return ok
+//				},
+//			}},
+//		})
+//	}
+package routetest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/http/jsonapi/generator"
+)
+
+// TestingT is the subset of *testing.T used to report failures
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Middleware is the expectation of a middleware that has to handle
+// the requests of a route
+type Middleware struct {
+	// Name of the middleware used in failure messages
+	Name string
+	// Applied reports if the middleware handled the request, it is
+	// called with the request as it reached the handler of the route
+	// (e.g. to check the values that the middleware added to the context)
+	Applied func(r *http.Request) bool
+}
+
+// Options of the assertion
+type Options struct {
+	// Prepare is called with every request before it is served,
+	// e.g. to add the authorization required by the middlewares
+	Prepare func(r *http.Request)
+	// Middlewares that have to handle the requests of every route
+	Middlewares []Middleware
+	// RouteMiddlewares that have to handle the requests of
+	// specific routes, the key is the name of the route
+	RouteMiddlewares map[string][]Middleware
+}
+
+// Operation of the specification
+type Operation struct {
+	// Name of the route (and generated handler)
+	Name string
+	// Method of the operation in upper case
+	Method string
+	// Path including the path of the server and the query values
+	// that are used to match the route (e.g. "?include=creditCheck")
+	Path string
+}
+
+// Operations returns the operations of the specification for all
+// servers, sorted by path and method
+func Operations(spec *openapi3.Swagger) ([]Operation, error) {
+	bases := make(map[string]struct{})
+	for _, server := range spec.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			return nil, err
+		}
+		bases[u.Path] = struct{}{}
+	}
+	if len(bases) == 0 {
+		bases[""] = struct{}{}
+	}
+
+	var ops []Operation
+	for base := range bases {
+		for pattern, pathItem := range spec.Paths {
+			for method, op := range pathItem.Operations() {
+				ops = append(ops, Operation{
+					Name:   generator.OperationName(strings.Title(strings.ToLower(method)), op, pattern),
+					Method: method,
+					Path:   base + pattern,
+				})
+			}
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path == ops[j].Path {
+			return ops[i].Method < ops[j].Method
+		}
+		return ops[i].Path < ops[j].Path
+	})
+	return ops, nil
+}
+
+// AssertReachable loads the specification from specFile and
+// asserts the reachability of its operations, see AssertOperations
+func AssertReachable(t TestingT, specFile string, router *mux.Router, opts *Options) bool {
+	data, err := ioutil.ReadFile(specFile) // nolint: gosec
+	if err != nil {
+		t.Errorf("Failed to read specification %q: %v", specFile, err)
+		return false
+	}
+	spec, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	if err != nil {
+		t.Errorf("Failed to load specification %q: %v", specFile, err)
+		return false
+	}
+	ops, err := Operations(spec)
+	if err != nil {
+		t.Errorf("Invalid specification %q: %v", specFile, err)
+		return false
+	}
+	return AssertOperations(t, ops, router, opts)
+}
+
+// AssertOperations asserts that every operation resolves to a route of
+// the router with the name and method of the operation and, for GET
+// operations, to a HEAD route of the same name. The request of every
+// operation is then served by the router to assert that the middlewares
+// of the options handled the request before it reached the route.
+//
+// To serve the requests without calling the service, the handlers of the
+// routes are replaced temporarily. The router must therefore not be used
+// concurrently.
+func AssertOperations(t TestingT, ops []Operation, router *mux.Router, opts *Options) bool {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	ok := true
+	for _, op := range ops {
+		route := assertRoute(t, router, op, op.Method)
+		if route == nil {
+			ok = false
+			continue
+		}
+		if op.Method == http.MethodGet && assertRoute(t, router, op, http.MethodHead) == nil {
+			ok = false
+		}
+
+		middlewares := append(append([]Middleware(nil), opts.Middlewares...), opts.RouteMiddlewares[op.Name]...)
+		if len(middlewares) > 0 && !assertMiddlewares(t, router, route, op, opts.Prepare, middlewares) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// assertRoute returns the route that matches the request of the
+// operation using method, or nil if there is none or it doesn't
+// match the operation
+func assertRoute(t TestingT, router *mux.Router, op Operation, method string) *mux.Route {
+	var match mux.RouteMatch
+	if !router.Match(newRequest(op, method), &match) || match.MatchErr != nil || match.Route == nil {
+		reason := "no route"
+		if match.MatchErr == mux.ErrMethodMismatch {
+			reason = "method not allowed"
+		}
+		t.Errorf("%s %s (%s) is not reachable: %s", method, op.Path, op.Name, reason)
+		return nil
+	}
+
+	if name := match.Route.GetName(); name != op.Name {
+		tpl, _ := match.Route.GetPathTemplate() // nolint: errcheck
+		t.Errorf("%s %s (%s) resolves to route %q (%s)", method, op.Path, op.Name, name, tpl)
+		return nil
+	}
+
+	methods, err := match.Route.GetMethods()
+	if err != nil || !contains(methods, method) {
+		t.Errorf("%s %s (%s) resolves to route %q without method %s", method, op.Path, op.Name, op.Name, method)
+		return nil
+	}
+	return match.Route
+}
+
+// assertMiddlewares serves the request of the operation and asserts that
+// it reached the route after it was handled by all middlewares
+func assertMiddlewares(t TestingT, router *mux.Router, route *mux.Route, op Operation, prepare func(r *http.Request), middlewares []Middleware) bool {
+	var reached *http.Request
+	handler := route.GetHandler()
+	route.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer route.Handler(handler)
+
+	req := newRequest(op, op.Method)
+	if prepare != nil {
+		prepare(req)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if reached == nil {
+		t.Errorf("%s %s (%s) didn't reach the route, got status %d: %s", op.Method, op.Path, op.Name, rec.Code, strings.TrimSpace(rec.Body.String()))
+		return false
+	}
+
+	ok := true
+	for _, m := range middlewares {
+		if !m.Applied(reached) {
+			t.Errorf("%s %s (%s) wasn't handled by middleware %s", op.Method, op.Path, op.Name, m.Name)
+			ok = false
+		}
+	}
+	return ok
+}
+
+var pathParam = regexp.MustCompile(`{([^}]+)}`)
+
+// newRequest creates the request for the operation, path
+// parameters are filled with the name of the parameter
+func newRequest(op Operation, method string) *http.Request {
+	path := pathParam.ReplaceAllStringFunc(op.Path, func(param string) string {
+		return url.PathEscape(param[1 : len(param)-1])
+	})
+	return httptest.NewRequest(method, fmt.Sprintf("http://localhost%s", path), nil)
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
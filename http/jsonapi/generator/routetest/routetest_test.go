@@ -0,0 +1,163 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package routetest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const spec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Test", "version": "1.0"},
+  "servers": [{"url": "https://api.example.com/test"}],
+  "paths": {
+    "/items": {
+      "get": {"operationId": "GetItems", "responses": {"200": {"description": "OK"}}}
+    },
+    "/items?include=details": {
+      "get": {"operationId": "GetItemsIncludingDetails", "responses": {"200": {"description": "OK"}}}
+    },
+    "/items/{itemId}": {
+      "delete": {"operationId": "deleteItem", "responses": {"204": {"description": "Deleted"}}}
+    }
+  }
+}`
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type ctxKey struct{}
+
+func contextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, true)))
+	})
+}
+
+var contextApplied = Middleware{
+	Name: "context",
+	Applied: func(r *http.Request) bool {
+		return r.Context().Value(ctxKey{}) != nil
+	},
+}
+
+func loadOperations(t *testing.T) []Operation {
+	s, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := Operations(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ops
+}
+
+func TestOperations(t *testing.T) {
+	assert.Equal(t, []Operation{
+		{Name: "GetItems", Method: "GET", Path: "/test/items"},
+		{Name: "DeleteItem", Method: "DELETE", Path: "/test/items/{itemId}"},
+		{Name: "GetItemsIncludingDetails", Method: "GET", Path: "/test/items?include=details"},
+	}, loadOperations(t))
+}
+
+func TestAssertOperations(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected handler of the route not to be called")
+	})
+	router := func(edit func(s *mux.Router)) *mux.Router {
+		r := mux.NewRouter()
+		s := r.PathPrefix("/test").Subrouter()
+		s.Use(contextMiddleware)
+		s.Methods("GET").Path("/items").Handler(handler).Queries("include", "details").Name("GetItemsIncludingDetails")
+		s.Methods("HEAD").Path("/items").Handler(handler).Queries("include", "details").Name("GetItemsIncludingDetails")
+		if edit != nil {
+			edit(s)
+		}
+		s.Methods("GET").Path("/items").Handler(handler).Name("GetItems")
+		s.Methods("HEAD").Path("/items").Handler(handler).Name("GetItems")
+		return r
+	}
+
+	cases := []struct {
+		name   string
+		edit   func(s *mux.Router)
+		opts   *Options
+		errors []string
+	}{
+		{
+			name: "reachable",
+			edit: func(s *mux.Router) {
+				s.Methods("DELETE").Path("/items/{itemId}").Handler(handler).Name("DeleteItem")
+			},
+			opts: &Options{Middlewares: []Middleware{contextApplied}},
+		},
+		{
+			name:   "missing route",
+			errors: []string{"DELETE /test/items/{itemId} (DeleteItem) is not reachable: no route"},
+		},
+		{
+			name: "wrong method",
+			edit: func(s *mux.Router) {
+				s.Methods("POST").Path("/items/{itemId}").Handler(handler).Name("DeleteItem")
+			},
+			errors: []string{"DELETE /test/items/{itemId} (DeleteItem) is not reachable: method not allowed"},
+		},
+		{
+			name: "shadowed route",
+			edit: func(s *mux.Router) {
+				s.Methods("GET", "HEAD").Path("/items").Handler(handler).Name("ListItems")
+				s.Methods("DELETE").Path("/items/{itemId}").Handler(handler).Name("DeleteItem")
+			},
+			errors: []string{
+				`GET /test/items (GetItems) resolves to route "ListItems" (/test/items)`,
+			},
+		},
+		{
+			name: "missing middleware",
+			edit: func(s *mux.Router) {
+				s.Methods("DELETE").Path("/items/{itemId}").Handler(handler).Name("DeleteItem")
+			},
+			opts: &Options{RouteMiddlewares: map[string][]Middleware{
+				"DeleteItem": {{Name: "auth", Applied: func(r *http.Request) bool { return false }}},
+			}},
+			errors: []string{"DELETE /test/items/{itemId} (DeleteItem) wasn't handled by middleware auth"},
+		},
+		{
+			name: "rejected by middleware",
+			edit: func(s *mux.Router) {
+				s.Methods("DELETE").Path("/items/{itemId}").Handler(handler).Name("DeleteItem")
+			},
+			opts: &Options{Middlewares: []Middleware{contextApplied}, Prepare: func(r *http.Request) {
+				r.URL.Path = strings.Replace(r.URL.Path, "/test", "/unknown", 1)
+			}},
+			errors: []string{
+				"GET /test/items (GetItems) didn't reach the route, got status 404: 404 page not found",
+				"DELETE /test/items/{itemId} (DeleteItem) didn't reach the route, got status 404: 404 page not found",
+				"GET /test/items?include=details (GetItemsIncludingDetails) didn't reach the route, got status 404: 404 page not found",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := &recorder{}
+			ok := AssertOperations(rec, loadOperations(t), router(c.edit), c.opts)
+			assert.Equal(t, len(c.errors) == 0, ok)
+			assert.Equal(t, c.errors, rec.errors)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// allocation budgets for marshalling and un-marshalling an article,
+// raise them only deliberately
+const (
+	marshalAllocBudget   = 30
+	unmarshalAllocBudget = 80
+)
+
+type benchArticle struct {
+	ID    string `jsonapi:"primary,articles" valid:"optional,uuid"`
+	Title string `jsonapi:"attr,title" valid:"required"`
+	Views int    `jsonapi:"attr,views" valid:"optional"`
+}
+
+const benchArticlePayload = `{"data":{"type":"articles","id":"cb855aff-f03c-4307-9a22-ab5fcc6b6d7c","attributes":{"title":"This is my first blog","views":42}}}`
+
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func benchMarshal() {
+	article := benchArticle{
+		ID:    "cb855aff-f03c-4307-9a22-ab5fcc6b6d7c",
+		Title: "This is my first blog",
+		Views: 42,
+	}
+	Marshal(&discardWriter{header: make(http.Header)}, &article, http.StatusOK)
+}
+
+func benchUnmarshal() bool {
+	req := httptest.NewRequest("POST", "/articles", strings.NewReader(benchArticlePayload))
+	req.Header.Set("Accept", JSONAPIContentType)
+	req.Header.Set("Content-Type", JSONAPIContentType)
+
+	var article benchArticle
+	return Unmarshal(&discardWriter{header: make(http.Header)}, req, &article)
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchMarshal()
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !benchUnmarshal() {
+			b.Fatal("Un-marshalling failed")
+		}
+	}
+}
+
+func TestMarshalAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation budgets are not checked in short mode")
+	}
+
+	if allocs := testing.AllocsPerRun(100, benchMarshal); allocs > marshalAllocBudget {
+		t.Errorf("Expected at most %d allocations for marshalling, got: %v", marshalAllocBudget, allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { benchUnmarshal() }); allocs > unmarshalAllocBudget {
+		t.Errorf("Expected at most %d allocations for un-marshalling, got: %v", unmarshalAllocBudget, allocs)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SetLastModified sets the Last-Modified header of the response,
+// zero times are ignored
+func SetLastModified(w http.ResponseWriter, lastModified time.Time) {
+	if lastModified.IsZero() {
+		return
+	}
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+}
+
+// NotModified sets the Last-Modified header and compares the last modification
+// of the resource with the If-Modified-Since request header. If the resource
+// wasn't modified since, a 304 Not Modified response is sent and true is returned.
+func NotModified(w http.ResponseWriter, r *http.Request, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+	SetLastModified(w, lastModified)
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	// the header has second precision only
+	if lastModified.Truncate(time.Second).After(t) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// NotModifiedETag sets the ETag header and compares the etag of the resource
+// with the If-None-Match request header. If one of the etags matches
+// (weak comparison), a 304 Not Modified response is sent and true is returned.
+func NotModifiedETag(w http.ResponseWriter, r *http.Request, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	w.Header().Set("ETag", etag)
+
+	inm := r.Header.Get("If-None-Match")
+	if inm == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(inm, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2019, 4, 1, 12, 30, 15, 500, time.UTC)
+
+	cases := []struct {
+		title           string
+		ifModifiedSince string
+		notModified     bool
+	}{
+		{"no header", "", false},
+		{"invalid header", "yesterday", false},
+		{"modified since", "Mon, 01 Apr 2019 12:30:14 GMT", false},
+		{"not modified since (same second)", "Mon, 01 Apr 2019 12:30:15 GMT", true},
+		{"not modified since", "Mon, 01 Apr 2019 13:00:00 GMT", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", c.ifModifiedSince)
+			}
+
+			if got := NotModified(rec, req, lastModified); got != c.notModified {
+				t.Errorf("Expected not modified to be %v, got: %v", c.notModified, got)
+			}
+
+			resp := rec.Result()
+			defer resp.Body.Close()
+
+			if lm := resp.Header.Get("Last-Modified"); lm != "Mon, 01 Apr 2019 12:30:15 GMT" {
+				t.Errorf("Expected Last-Modified header, got: %q", lm)
+			}
+			if c.notModified && resp.StatusCode != http.StatusNotModified {
+				t.Errorf("Expected status code %d got: %d", http.StatusNotModified, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSetLastModifiedZero(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SetLastModified(rec, time.Time{})
+	if lm := rec.Header().Get("Last-Modified"); lm != "" {
+		t.Errorf("Expected no Last-Modified header, got: %q", lm)
+	}
+}
+
+func TestNotModifiedETag(t *testing.T) {
+	etag := `W/"v42"`
+
+	cases := []struct {
+		title       string
+		ifNoneMatch string
+		notModified bool
+	}{
+		{"no header", "", false},
+		{"other etag", `W/"v41"`, false},
+		{"same etag", `W/"v42"`, true},
+		{"strong etag", `"v42"`, true},
+		{"list of etags", `W/"v40", W/"v42"`, true},
+		{"any", "*", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", c.ifNoneMatch)
+			}
+
+			if got := NotModifiedETag(rec, req, etag); got != c.notModified {
+				t.Errorf("Expected not modified to be %v, got: %v", c.notModified, got)
+			}
+
+			resp := rec.Result()
+			if resp.Header.Get("ETag") != etag {
+				t.Errorf("Expected ETag %q, got: %q", etag, resp.Header.Get("ETag"))
+			}
+			if c.notModified && resp.StatusCode != http.StatusNotModified {
+				t.Errorf("Expected status code %d, got: %d", http.StatusNotModified, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -6,3 +6,8 @@ package runtime
 // JSONAPIContentType is the content type required for
 // jsonapi based requests and responses
 const JSONAPIContentType = "application/vnd.api+json"
+
+// MessagePackContentType is the content type of jsonapi based requests
+// and responses encoded as MessagePack instead of JSON, it is meant
+// for high-volume traffic between services
+const MessagePackContentType = "application/vnd.api+msgpack"
@@ -5,5 +5,33 @@
 Package runtime contains functions for marshalling, error handling,
 parameter parsing and validation. These functions are used by the generator
 to implement the different request handlers.
+
+Attributes of responses can be masked centrally per resource type based on
+the scopes of the token, instead of masking them in every handler:
+
+	runtime.RegisterMasking("paymentMethod",
+		runtime.Masking{Attribute: "cardNumber", Scope: "pay:cards:read", Mask: runtime.MaskCardNumber},
+		runtime.Masking{Attribute: "email", Scope: "pay:cards:read", Mask: runtime.MaskEmail},
+	)
+
+The attributes are masked by Marshal for tokens without the scope. The
+generated handlers provide the context of the request, if Marshal is called
+without it, the registered attributes are always masked.
+
+Services can request and respond with documents encoded as MessagePack
+instead of JSON using the MessagePackContentType in the Content-Type and
+Accept header. It reduces the size of high-volume traffic between services,
+external clients keep using JSON. Errors are always responded as JSON.
+
+Integer attributes of type StringInt64 (e.g. IDs or monetary amounts) are
+marshalled as JSON strings to protect JavaScript clients from precision
+loss above 2^53, strings and numbers are accepted by Unmarshal. The
+generator only uses the type for properties with the extension
+"x-json-string": true, of type integer or string with format int64.
+
+Clients of JSON:API services can range over collections using an Iterator,
+it follows the next links of the pages, retries rate limited (429) pages
+after the Retry-After time and stops if the context is done or MaxPages
+are fetched.
 */
 package runtime
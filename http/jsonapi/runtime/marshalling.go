@@ -4,7 +4,10 @@
 package runtime
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"reflect"
@@ -21,27 +24,23 @@ func Unmarshal(w http.ResponseWriter, r *http.Request, data interface{}) bool {
 	// don't leak , but error can't be handled
 	defer r.Body.Close() // nolint: errcheck
 
-	// verify that the client accepts our response
-	// Note: logically this would be done before marshalling,
-	//       to prevent stale backend/frontend state we respond before
-	// 		 Additionally, marshal has no access to the request struct
-	accept := r.Header.Get("Accept")
-	if accept != JSONAPIContentType {
-		WriteError(w, http.StatusNotAcceptable,
-			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Accept", JSONAPIContentType))
+	body, ok := requestBody(w, r)
+	if !ok {
 		return false
 	}
 
-	// if the client didn't send a content type, don't verify
-	contentType := r.Header.Get("Content-Type")
-	if contentType != JSONAPIContentType {
-		WriteError(w, http.StatusUnsupportedMediaType,
-			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Content-Type", JSONAPIContentType))
+	body, restore, err := extractStringInt64(body, reflect.TypeOf(data))
+	if err != nil {
+		WriteError(w, http.StatusUnprocessableEntity,
+			fmt.Errorf("can't parse content: %v", err))
 		return false
 	}
 
 	// parse request
-	err := jsonapi.UnmarshalPayload(r.Body, data)
+	err = jsonapi.UnmarshalPayload(body, data)
+	if err == nil {
+		err = restore(data)
+	}
 	if err != nil {
 		WriteError(w, http.StatusUnprocessableEntity,
 			fmt.Errorf("can't parse content: %v", err))
@@ -60,27 +59,23 @@ func UnmarshalMany(w http.ResponseWriter, r *http.Request, t reflect.Type) (bool
 	// don't leak , but error can't be handled
 	defer r.Body.Close() // nolint: errcheck
 
-	// verify that the client accepts our response
-	// Note: logically this would be done before marshalling,
-	//       to prevent stale backend/frontend state we respond before
-	// 		 Additionally, marshal has no access to the request struct
-	accept := r.Header.Get("Accept")
-	if accept != JSONAPIContentType {
-		WriteError(w, http.StatusNotAcceptable,
-			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Accept", JSONAPIContentType))
+	body, ok := requestBody(w, r)
+	if !ok {
 		return false, nil
 	}
 
-	// if the client didn't send a content type, don't verify
-	contentType := r.Header.Get("Content-Type")
-	if contentType != JSONAPIContentType {
-		WriteError(w, http.StatusUnsupportedMediaType,
-			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Content-Type", JSONAPIContentType))
+	body, restore, err := extractStringInt64(body, t)
+	if err != nil {
+		WriteError(w, http.StatusUnprocessableEntity,
+			fmt.Errorf("can't parse content: %v", err))
 		return false, nil
 	}
 
 	// parse request
-	data, err := jsonapi.UnmarshalManyPayload(r.Body, t)
+	data, err := jsonapi.UnmarshalManyPayload(body, t)
+	if err == nil {
+		err = restore(data...)
+	}
 	if err != nil {
 		WriteError(w, http.StatusUnprocessableEntity,
 			fmt.Errorf("can't parse content: %v", err))
@@ -95,15 +90,62 @@ func UnmarshalMany(w http.ResponseWriter, r *http.Request, t reflect.Type) (bool
 	return true, data
 }
 
+// requestBody verifies the content type and accepted content type of the
+// request and returns the JSON document of the body. Requests encoded as
+// MessagePack are converted. In case of an error, an jsonapi error message
+// will be directly send to the client.
+func requestBody(w http.ResponseWriter, r *http.Request) (io.Reader, bool) {
+	// verify that the client accepts our response
+	// Note: logically this would be done before marshalling,
+	//       to prevent stale backend/frontend state we respond before
+	// 		 Additionally, marshal has no access to the request struct
+	accept := r.Header.Get("Accept")
+	if !acceptedContentType(accept) {
+		WriteError(w, http.StatusNotAcceptable,
+			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Accept", JSONAPIContentType))
+		return nil, false
+	}
+
+	// if the client didn't send a content type, don't verify
+	contentType := r.Header.Get("Content-Type")
+	if !acceptedContentType(contentType) {
+		WriteError(w, http.StatusUnsupportedMediaType,
+			fmt.Errorf("request needs to be send with %q header, containing value: %q", "Content-Type", JSONAPIContentType))
+		return nil, false
+	}
+
+	if contentType != MessagePackContentType {
+		return r.Body, true
+	}
+	body, err := readMessagePack(r.Body)
+	if err != nil {
+		WriteError(w, http.StatusUnprocessableEntity,
+			fmt.Errorf("can't parse content: %v", err))
+		return nil, false
+	}
+	return body, true
+}
+
 // Marshal the given data and writes them into the response writer, sets
-// the content-type and code as well
+// the content-type and code as well. Attributes are masked according to
+// the registered maskings (see RegisterMasking). If the response writer
+// knows the request (e.g. the metric response writer of the generated
+// handlers) and it only accepts MessagePackContentType, the response is
+// encoded as MessagePack.
 func Marshal(w http.ResponseWriter, data interface{}, code int) {
+	contentType := responseContentType(w)
+
 	// write response header
-	w.Header().Set("Content-Type", JSONAPIContentType)
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(code)
 
 	// write marshaled response body
-	err := jsonapi.MarshalPayload(w, data)
+	var err error
+	if contentType == MessagePackContentType {
+		err = marshalMessagePack(w, data)
+	} else {
+		err = marshalPayload(w, data)
+	}
 	if err != nil {
 		switch err.(type) {
 		case *net.OpError:
@@ -113,3 +155,39 @@ func Marshal(w http.ResponseWriter, data interface{}, code int) {
 		}
 	}
 }
+
+// marshalPayload writes the payload of data, the attributes
+// are masked if maskings are registered
+func marshalPayload(w http.ResponseWriter, data interface{}) error {
+	if !hasMaskings() {
+		return jsonapi.MarshalPayload(w, data)
+	}
+
+	payload, err := jsonapi.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var ctx context.Context
+	if cw, ok := w.(contextWriter); ok {
+		ctx = cw.Context()
+	}
+	maskPayload(ctx, payload)
+	return json.NewEncoder(w).Encode(payload)
+}
+
+// marshalMessagePack writes the payload of data encoded as
+// MessagePack, the attributes are masked if maskings are registered
+func marshalMessagePack(w http.ResponseWriter, data interface{}) error {
+	payload, err := jsonapi.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if hasMaskings() {
+		var ctx context.Context
+		if cw, ok := w.(contextWriter); ok {
+			ctx = cw.Context()
+		}
+		maskPayload(ctx, payload)
+	}
+	return writeMessagePack(w, payload)
+}
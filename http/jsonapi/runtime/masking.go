@@ -0,0 +1,153 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonapi"
+)
+
+// MaskFunc returns the masked value of an attribute
+type MaskFunc func(value interface{}) interface{}
+
+// Masking masks an attribute of a resource type in responses to
+// requests whose token doesn't have the scope
+type Masking struct {
+	// Attribute is the jsonapi name of the attribute, e.g. "cardNumber"
+	Attribute string
+	// Scope that is required to get the unmasked value
+	Scope string
+	Mask  MaskFunc
+}
+
+// HasScope returns true if the token of ctx has the scope. It is set by
+// the oauth2 package, without it all registered attributes are masked.
+var HasScope func(ctx context.Context, scope string) bool
+
+var (
+	maskingsMu sync.RWMutex
+	maskings   = make(map[string][]Masking)
+)
+
+// RegisterMasking registers the maskings of the attributes of the jsonapi
+// resource type (e.g. "paymentMethod"). The attributes are masked by
+// Marshal, if the response writer provides the context of the request
+// (e.g. the metric response writer of the generated handlers).
+func RegisterMasking(resourceType string, m ...Masking) {
+	maskingsMu.Lock()
+	defer maskingsMu.Unlock()
+	maskings[resourceType] = append(maskings[resourceType], m...)
+}
+
+// contextWriter is implemented by response writers that know
+// the context of the request
+type contextWriter interface {
+	Context() context.Context
+}
+
+// hasMaskings returns true if any maskings are registered
+func hasMaskings() bool {
+	maskingsMu.RLock()
+	defer maskingsMu.RUnlock()
+	return len(maskings) > 0
+}
+
+// maskPayload masks the attributes of the data and included nodes
+func maskPayload(ctx context.Context, payload jsonapi.Payloader) {
+	var nodes []*jsonapi.Node
+	switch p := payload.(type) {
+	case *jsonapi.OnePayload:
+		nodes = append(nodes, p.Data)
+		nodes = append(nodes, p.Included...)
+	case *jsonapi.ManyPayload:
+		nodes = append(nodes, p.Data...)
+		nodes = append(nodes, p.Included...)
+	}
+
+	// scopes are only checked once per response
+	granted := make(map[string]bool)
+	hasScope := func(scope string) bool {
+		ok, checked := granted[scope]
+		if !checked {
+			ok = ctx != nil && HasScope != nil && HasScope(ctx, scope)
+			granted[scope] = ok
+		}
+		return ok
+	}
+
+	maskingsMu.RLock()
+	defer maskingsMu.RUnlock()
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		for _, m := range maskings[node.Type] {
+			value, ok := node.Attributes[m.Attribute]
+			if !ok || value == nil || hasScope(m.Scope) {
+				continue
+			}
+			node.Attributes[m.Attribute] = m.Mask(value)
+		}
+	}
+}
+
+// maskString applies fn to string values, values of other
+// types are removed
+func maskString(value interface{}, fn func(s string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return fn(*v)
+	}
+	return nil
+}
+
+// keepLast replaces all but the last n characters of s with *
+func keepLast(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return strings.Repeat("*", len(r))
+	}
+	return strings.Repeat("*", len(r)-n) + string(r[len(r)-n:])
+}
+
+// MaskCardNumber keeps the last 4 digits of card numbers,
+// e.g. "************1234"
+func MaskCardNumber(value interface{}) interface{} {
+	return maskString(value, func(s string) string {
+		return keepLast(strings.Replace(s, " ", "", -1), 4)
+	})
+}
+
+// MaskEmail keeps the first character of the local part and the
+// domain of email addresses, e.g. "j***@example.com"
+func MaskEmail(value interface{}) interface{} {
+	return maskString(value, func(s string) string {
+		at := strings.LastIndex(s, "@")
+		if at < 1 {
+			return keepLast(s, 0)
+		}
+		return string([]rune(s)[:1]) + "***" + s[at:]
+	})
+}
+
+// MaskVIN keeps the last 4 characters of vehicle identification
+// numbers, e.g. "*************1234"
+func MaskVIN(value interface{}) interface{} {
+	return maskString(value, func(s string) string {
+		return keepLast(s, 4)
+	})
+}
+
+// Redact removes the value
+func Redact(value interface{}) interface{} {
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type ctxWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+}
+
+func (w *ctxWriter) Context() context.Context {
+	return w.ctx
+}
+
+type scopeKey struct{}
+
+func TestMarshalMasking(t *testing.T) {
+	type Card struct {
+		ID     string  `jsonapi:"primary,maskingCard"`
+		Number string  `jsonapi:"attr,number"`
+		Email  *string `jsonapi:"attr,email"`
+		VIN    string  `jsonapi:"attr,vin"`
+	}
+	RegisterMasking("maskingCard",
+		Masking{Attribute: "number", Scope: "cards:read", Mask: MaskCardNumber},
+		Masking{Attribute: "email", Scope: "cards:read", Mask: MaskEmail},
+		Masking{Attribute: "vin", Scope: "cards:admin", Mask: Redact},
+	)
+	defer func() {
+		maskingsMu.Lock()
+		delete(maskings, "maskingCard")
+		maskingsMu.Unlock()
+	}()
+
+	defer func(fn func(ctx context.Context, scope string) bool) { HasScope = fn }(HasScope)
+	HasScope = func(ctx context.Context, scope string) bool {
+		return ctx.Value(scopeKey{}) == scope
+	}
+
+	email := "john@example.com"
+	card := &Card{ID: "1", Number: "4111 1111 1111 1234", Email: &email, VIN: "WVWZZZ1JZXW000001"}
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		expected []string
+	}{
+		{"no scope", context.Background(), []string{`"number":"************1234"`, `"email":"j***@example.com"`, `"vin":null`}},
+		{"scope", context.WithValue(context.Background(), scopeKey{}, "cards:read"), []string{`"number":"4111 1111 1111 1234"`, `"email":"john@example.com"`, `"vin":null`}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Marshal(&ctxWriter{ResponseWriter: rec, ctx: c.ctx}, card, http.StatusOK)
+			body := rec.Body.String()
+			for _, e := range c.expected {
+				if !strings.Contains(body, e) {
+					t.Errorf("Expected %s in %s", e, body)
+				}
+			}
+		})
+	}
+
+	// without context of the request, the attributes are masked
+	rec := httptest.NewRecorder()
+	Marshal(rec, []*Card{card}, http.StatusOK)
+	if !strings.Contains(rec.Body.String(), `"number":"************1234"`) {
+		t.Errorf("Expected masked card number, got: %s", rec.Body.String())
+	}
+}
+
+func TestMaskFuncs(t *testing.T) {
+	cases := []struct {
+		fn       MaskFunc
+		value    interface{}
+		expected interface{}
+	}{
+		{MaskCardNumber, "4111111111111234", "************1234"},
+		{MaskCardNumber, "123", "***"},
+		{MaskEmail, "john@example.com", "j***@example.com"},
+		{MaskEmail, "invalid", "*******"},
+		{MaskVIN, "WVWZZZ1JZXW000001", "*************0001"},
+		{MaskVIN, 1, nil},
+		{Redact, "secret", nil},
+	}
+	for _, c := range cases {
+		if got := c.fn(c.value); got != c.expected {
+			t.Errorf("Expected %v for %v, got: %v", c.expected, c.value, got)
+		}
+	}
+}
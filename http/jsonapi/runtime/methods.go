@@ -0,0 +1,38 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HeadHandler answers HEAD requests using the passed handler (usually the
+// GET handler of the same route). Status code and headers are sent as
+// usual, the response body is discarded.
+func HeadHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&headResponseWriter{ResponseWriter: w}, r)
+	})
+}
+
+// OptionsHandler answers OPTIONS requests with an empty response
+// and the Allow header set to the passed methods
+func OptionsHandler(methods ...string) http.Handler {
+	allow := strings.Join(methods, ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// headResponseWriter drops all body writes
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write pretends to write the data, since HEAD responses have no body
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
@@ -0,0 +1,51 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeadHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("HEAD", "/", nil)
+
+	HeadHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", JSONAPIContentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data":[]}`)) // nolint: errcheck
+	})).ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code %d got: %d", http.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != JSONAPIContentType {
+		t.Errorf("Expected content type %q got: %q", JSONAPIContentType, ct)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected no body got: %q", rec.Body.String())
+	}
+}
+
+func TestOptionsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+
+	OptionsHandler("GET", "HEAD", "OPTIONS").ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status code %d got: %d", http.StatusNoContent, resp.StatusCode)
+	}
+	if allow := resp.Header.Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Allow header %q got: %q", "GET, HEAD, OPTIONS", allow)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/google/jsonapi"
+	"github.com/pace/bricks/pkg/msgpack"
+)
+
+// requestWriter is implemented by response writers that know
+// the request, e.g. to negotiate the content type of the response
+type requestWriter interface {
+	Request() *http.Request
+}
+
+// acceptedContentType returns true if the content type is
+// accepted for requests and responses
+func acceptedContentType(contentType string) bool {
+	return contentType == JSONAPIContentType || contentType == MessagePackContentType
+}
+
+// responseContentType returns the content type of the response to the
+// request of w, MessagePack is used if the request accepts it exclusively.
+// If w doesn't know the request JSON is used.
+func responseContentType(w http.ResponseWriter) string {
+	rw, ok := w.(requestWriter)
+	if !ok || rw.Request() == nil {
+		return JSONAPIContentType
+	}
+	w.Header().Add("Vary", "Accept")
+	if rw.Request().Header.Get("Accept") == MessagePackContentType {
+		return MessagePackContentType
+	}
+	return JSONAPIContentType
+}
+
+// writeMessagePack writes the payload encoded as MessagePack. The
+// documents and nodes are encoded directly, only attribute and meta values
+// of other than the basic types (e.g. nested structs) are converted using
+// their JSON representation.
+func writeMessagePack(w io.Writer, payload jsonapi.Payloader) error {
+	e := msgpack.Encoder{Fallback: jsonValue}
+	var err error
+	switch p := payload.(type) {
+	case *jsonapi.OnePayload:
+		err = encodeDocument(&e, p.Data, p.Included, p.Links, p.Meta)
+	case *jsonapi.ManyPayload:
+		err = encodeDocument(&e, p.Data, p.Included, p.Links, p.Meta)
+	default:
+		err = fmt.Errorf("msgpack: unsupported payload %T", payload)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(e.Bytes())
+	return err
+}
+
+// encodeDocument encodes a document like its JSON representation,
+// data is either a *jsonapi.Node or a []*jsonapi.Node
+func encodeDocument(e *msgpack.Encoder, data interface{}, included []*jsonapi.Node, links *jsonapi.Links, meta *jsonapi.Meta) error {
+	n := 1
+	if len(included) > 0 {
+		n++
+	}
+	if links != nil {
+		n++
+	}
+	if meta != nil {
+		n++
+	}
+	e.MapHeader(n)
+
+	e.String("data")
+	if err := encodeData(e, data); err != nil {
+		return err
+	}
+	if len(included) > 0 {
+		e.String("included")
+		if err := encodeData(e, included); err != nil {
+			return err
+		}
+	}
+	return encodeLinksMeta(e, links, meta)
+}
+
+// encodeData encodes a *jsonapi.Node or a []*jsonapi.Node
+func encodeData(e *msgpack.Encoder, data interface{}) error {
+	switch data := data.(type) {
+	case *jsonapi.Node:
+		return encodeNode(e, data)
+	case []*jsonapi.Node:
+		if data == nil {
+			return e.Encode(nil)
+		}
+		e.ArrayHeader(len(data))
+		for _, node := range data {
+			if err := encodeNode(e, node); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.Encode(data)
+}
+
+func encodeNode(e *msgpack.Encoder, node *jsonapi.Node) error {
+	if node == nil {
+		return e.Encode(nil)
+	}
+	n := 1
+	for _, present := range []bool{
+		node.ID != "", node.ClientID != "", len(node.Attributes) > 0,
+		len(node.Relationships) > 0, node.Links != nil, node.Meta != nil,
+	} {
+		if present {
+			n++
+		}
+	}
+	e.MapHeader(n)
+
+	e.String("type")
+	e.String(node.Type)
+	if node.ID != "" {
+		e.String("id")
+		e.String(node.ID)
+	}
+	if node.ClientID != "" {
+		e.String("client-id")
+		e.String(node.ClientID)
+	}
+	if len(node.Attributes) > 0 {
+		e.String("attributes")
+		if err := e.Encode(node.Attributes); err != nil {
+			return err
+		}
+	}
+	if len(node.Relationships) > 0 {
+		e.String("relationships")
+		e.MapHeader(len(node.Relationships))
+		for name, rel := range node.Relationships {
+			e.String(name)
+			if err := encodeRelationship(e, rel); err != nil {
+				return err
+			}
+		}
+	}
+	return encodeLinksMeta(e, node.Links, node.Meta)
+}
+
+func encodeRelationship(e *msgpack.Encoder, rel interface{}) error {
+	var (
+		data  interface{}
+		links *jsonapi.Links
+		meta  *jsonapi.Meta
+	)
+	switch rel := rel.(type) {
+	case *jsonapi.RelationshipOneNode:
+		data, links, meta = rel.Data, rel.Links, rel.Meta
+	case *jsonapi.RelationshipManyNode:
+		data, links, meta = rel.Data, rel.Links, rel.Meta
+	default:
+		return e.Encode(rel)
+	}
+
+	n := 1
+	if links != nil {
+		n++
+	}
+	if meta != nil {
+		n++
+	}
+	e.MapHeader(n)
+	e.String("data")
+	if err := encodeData(e, data); err != nil {
+		return err
+	}
+	return encodeLinksMeta(e, links, meta)
+}
+
+// encodeLinksMeta encodes the members links and meta if they are present
+func encodeLinksMeta(e *msgpack.Encoder, links *jsonapi.Links, meta *jsonapi.Meta) error {
+	if links != nil {
+		e.String("links")
+		e.MapHeader(len(*links))
+		for name, link := range *links {
+			e.String(name)
+			if err := encodeLink(e, link); err != nil {
+				return err
+			}
+		}
+	}
+	if meta != nil {
+		e.String("meta")
+		if err := e.Encode(map[string]interface{}(*meta)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeLink(e *msgpack.Encoder, link interface{}) error {
+	l, ok := link.(jsonapi.Link)
+	if !ok {
+		return e.Encode(link)
+	}
+	if l.Meta == nil {
+		e.MapHeader(1)
+	} else {
+		e.MapHeader(2)
+	}
+	e.String("href")
+	e.String(l.Href)
+	if l.Meta != nil {
+		e.String("meta")
+		return e.Encode(map[string]interface{}(l.Meta))
+	}
+	return nil
+}
+
+// jsonValue converts the value into a generic value using its
+// JSON representation, e.g. for structs and json.Marshaler
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// readMessagePack converts the MessagePack encoded document of r
+// into JSON in one pass, so that it can be unmarshaled by jsonapi
+func readMessagePack(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err = msgpack.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
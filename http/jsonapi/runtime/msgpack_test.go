@@ -0,0 +1,163 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/jsonapi"
+	"github.com/pace/bricks/pkg/msgpack"
+	"github.com/stretchr/testify/assert"
+)
+
+type reqWriter struct {
+	http.ResponseWriter
+	req *http.Request
+}
+
+func (w reqWriter) Request() *http.Request {
+	return w.req
+}
+
+func TestMessagePack(t *testing.T) {
+	type Article struct {
+		ID    string `jsonapi:"primary,articles"`
+		Title string `jsonapi:"attr,title" valid:"required"`
+		Views int64  `jsonapi:"attr,views"`
+	}
+
+	body, err := msgpack.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "articles",
+			"id":         "1",
+			"attributes": map[string]interface{}{"title": "MessagePack", "views": 42},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Accept", MessagePackContentType)
+	req.Header.Set("Content-Type", MessagePackContentType)
+
+	var article Article
+	if !Unmarshal(rec, req, &article) {
+		t.Fatalf("Un-marshalling should have been ok: %s", rec.Body.String())
+	}
+	if article.ID != "1" || article.Title != "MessagePack" || article.Views != 42 {
+		t.Errorf("Unexpected article %#v", article)
+	}
+
+	Marshal(reqWriter{rec, req}, &article, http.StatusOK)
+	resp := rec.Result()
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != MessagePackContentType {
+		t.Errorf("Expected content type %q got: %q", MessagePackContentType, ct)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := msgpack.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := doc.(map[string]interface{})["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+	if attrs["title"] != "MessagePack" || attrs["views"] != int64(42) {
+		t.Errorf("Unexpected attributes %#v", attrs)
+	}
+
+	// clients that accept JSON get JSON
+	rec = httptest.NewRecorder()
+	req.Header.Set("Accept", JSONAPIContentType)
+	Marshal(reqWriter{rec, req}, &article, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); ct != JSONAPIContentType {
+		t.Errorf("Expected content type %q got: %q", JSONAPIContentType, ct)
+	}
+	if vary := rec.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("Expected Vary header %q got: %q", "Accept", vary)
+	}
+}
+
+func TestMessagePackInvalid(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte{0x92, 0x01}))
+	req.Header.Set("Accept", MessagePackContentType)
+	req.Header.Set("Content-Type", MessagePackContentType)
+
+	var v struct {
+		ID string `jsonapi:"primary,articles"`
+	}
+	if Unmarshal(rec, req, &v) {
+		t.Error("Un-marshalling should fail")
+	}
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d got: %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+}
+
+type msgpackAuthor struct {
+	ID   string `jsonapi:"primary,authors"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type msgpackLocation struct {
+	Lat, Lng float64
+}
+
+type msgpackArticle struct {
+	ID       string           `jsonapi:"primary,articles"`
+	Title    string           `jsonapi:"attr,title"`
+	Tags     []string         `jsonapi:"attr,tags"`
+	Location *msgpackLocation `jsonapi:"attr,location"`
+	Author   *msgpackAuthor   `jsonapi:"relation,author"`
+	Related  []*msgpackAuthor `jsonapi:"relation,related"`
+}
+
+func (a msgpackArticle) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"self": "/articles/" + a.ID,
+		"next": jsonapi.Link{Href: "/articles/2", Meta: jsonapi.Meta{"count": 1}},
+	}
+}
+
+func (a msgpackArticle) JSONAPIMeta() *jsonapi.Meta {
+	return &jsonapi.Meta{"version": 3}
+}
+
+// TestWriteMessagePack verifies that the directly encoded payloads
+// are equal to their JSON representation
+func TestWriteMessagePack(t *testing.T) {
+	author := &msgpackAuthor{ID: "7", Name: "Jane"}
+	for _, data := range []interface{}{
+		&msgpackArticle{ID: "1", Title: "MessagePack", Tags: []string{"a", "b"},
+			Location: &msgpackLocation{Lat: 1.5, Lng: 2}, Author: author, Related: []*msgpackAuthor{author}},
+		[]*msgpackArticle{{ID: "1"}, {ID: "2", Author: author}},
+		[]*msgpackArticle{},
+	} {
+		payload, err := jsonapi.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := writeMessagePack(&buf, payload); err != nil {
+			t.Fatal(err)
+		}
+		converted, err := msgpack.ToJSON(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.JSONEq(t, string(expected), string(converted))
+	}
+}
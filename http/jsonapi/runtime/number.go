@@ -0,0 +1,191 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StringInt64 is an int64 (e.g. an ID or a monetary amount in cents)
+// that is marshalled as JSON string, to protect JavaScript clients from
+// the precision loss of numbers above 2^53. Strings and numbers are
+// accepted on decode. The generator uses it for properties of type
+// integer (or string with format int64) with x-json-string: true.
+type StringInt64 int64
+
+// String implements fmt.Stringer
+func (i StringInt64) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// MarshalJSON implements json.Marshaler
+func (i StringInt64) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(i.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, JSON null keeps the value
+func (i *StringInt64) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid int64 %s: %v", data, err)
+	}
+	*i = StringInt64(v)
+	return nil
+}
+
+var (
+	_ json.Marshaler   = StringInt64(0)
+	_ json.Unmarshaler = (*StringInt64)(nil)
+)
+
+var stringInt64Type = reflect.TypeOf(StringInt64(0))
+
+// stringInt64Attributes returns the struct field index of all jsonapi
+// attributes of type StringInt64 (or a pointer to it) by attribute name
+func stringInt64Attributes(t reflect.Type) map[string]int {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var attrs map[string]int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type != stringInt64Type && field.Type != reflect.PtrTo(stringInt64Type) {
+			continue
+		}
+		args := strings.Split(field.Tag.Get("jsonapi"), ",")
+		if len(args) < 2 || args[0] != "attr" {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(map[string]int)
+		}
+		attrs[args[1]] = i
+	}
+	return attrs
+}
+
+// extractStringInt64 removes the StringInt64 attributes of the resources
+// of type t from the jsonapi document, as the jsonapi package decodes
+// numbers as float64 and rejects strings for integer fields. The returned
+// function decodes the removed attributes into the unmarshalled resources
+// (pointers to t), in the order of the document.
+func extractStringInt64(body io.Reader, t reflect.Type) (io.Reader, func(resources ...interface{}) error, error) {
+	noop := func(...interface{}) error { return nil }
+	attrs := stringInt64Attributes(t)
+	if len(attrs) == 0 {
+		return body, noop, nil
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		// leave the error reporting to the jsonapi package
+		return bytes.NewReader(data), noop, nil
+	}
+
+	// single resource or list of resources
+	var (
+		resources []map[string]json.RawMessage
+		many      bool
+	)
+	raw := bytes.TrimSpace(doc["data"])
+	if len(raw) > 0 && raw[0] == '[' {
+		many = true
+		err = json.Unmarshal(raw, &resources)
+	} else {
+		var resource map[string]json.RawMessage
+		err = json.Unmarshal(raw, &resource)
+		resources = append(resources, resource)
+	}
+	if err != nil {
+		return bytes.NewReader(data), noop, nil
+	}
+
+	values := make([]map[string]json.RawMessage, len(resources))
+	for i, resource := range resources {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(resource["attributes"], &attributes); err != nil || attributes == nil {
+			continue
+		}
+		for name := range attrs {
+			value, ok := attributes[name]
+			if !ok {
+				continue
+			}
+			if values[i] == nil {
+				values[i] = make(map[string]json.RawMessage)
+			}
+			values[i][name] = value
+			delete(attributes, name)
+		}
+		if resource["attributes"], err = json.Marshal(attributes); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if many {
+		doc["data"], err = json.Marshal(resources)
+	} else {
+		doc["data"], err = json.Marshal(resources[0])
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	restore := func(unmarshalled ...interface{}) error {
+		for i, resource := range unmarshalled {
+			if i >= len(values) {
+				break
+			}
+			v := reflect.Indirect(reflect.ValueOf(resource))
+			for name, value := range values[i] {
+				field := v.Field(attrs[name])
+				if field.Kind() == reflect.Ptr {
+					if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+						continue
+					}
+					field.Set(reflect.New(stringInt64Type))
+					field = field.Elem()
+				}
+				err := json.Unmarshal(value, field.Addr().Interface())
+				if err != nil {
+					return fmt.Errorf("attribute %s: %v", name, err)
+				}
+			}
+		}
+		return nil
+	}
+	return bytes.NewReader(data), restore, nil
+}
@@ -0,0 +1,93 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type payment struct {
+	ID       string       `jsonapi:"primary,payment" valid:"optional"`
+	Amount   StringInt64  `jsonapi:"attr,amount,omitempty" valid:"required"`
+	Customer *StringInt64 `jsonapi:"attr,customer,omitempty" valid:"optional"`
+	Currency string       `jsonapi:"attr,currency,omitempty" valid:"optional"`
+}
+
+func TestStringInt64JSON(t *testing.T) {
+	b, err := json.Marshal(StringInt64(9007199254740993))
+	assert.NoError(t, err)
+	assert.Equal(t, `"9007199254740993"`, string(b))
+
+	for in, expected := range map[string]StringInt64{
+		`"9007199254740993"`: 9007199254740993,
+		`-42`:                -42,
+		`null`:               0,
+	} {
+		var v StringInt64
+		assert.NoError(t, json.Unmarshal([]byte(in), &v), in)
+		assert.Equal(t, expected, v, in)
+	}
+
+	for _, in := range []string{`"abc"`, `1.5`, `""`, `true`} {
+		var v StringInt64
+		assert.Error(t, json.Unmarshal([]byte(in), &v), in)
+	}
+}
+
+func TestMarshalStringInt64(t *testing.T) {
+	rec := httptest.NewRecorder()
+	customer := StringInt64(42)
+	Marshal(rec, &payment{ID: "1", Amount: 9007199254740993, Customer: &customer}, http.StatusOK)
+
+	b, err := ioutil.ReadAll(rec.Result().Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"amount":"9007199254740993"`)
+	assert.Contains(t, string(b), `"customer":"42"`)
+}
+
+func TestUnmarshalStringInt64(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Accept", JSONAPIContentType)
+		req.Header.Set("Content-Type", JSONAPIContentType)
+		return req
+	}
+
+	// strings and numbers are accepted, strings without precision loss
+	var p payment
+	rec := httptest.NewRecorder()
+	ok := Unmarshal(rec, newRequest(`{"data":{"type":"payment","id":"1","attributes":{
+		"amount":"9007199254740993","customer":42,"currency":"EUR"}}}`), &p)
+	assert.True(t, ok, rec.Body.String())
+	assert.Equal(t, StringInt64(9007199254740993), p.Amount)
+	if assert.NotNil(t, p.Customer) {
+		assert.Equal(t, StringInt64(42), *p.Customer)
+	}
+	assert.Equal(t, "EUR", p.Currency)
+
+	rec = httptest.NewRecorder()
+	ok, payments := UnmarshalMany(rec, newRequest(`{"data":[
+		{"type":"payment","id":"1","attributes":{"amount":"1"}},
+		{"type":"payment","id":"2","attributes":{"amount":2,"customer":null}}
+	]}`), reflect.TypeOf(new(payment)))
+	assert.True(t, ok, rec.Body.String())
+	if assert.Len(t, payments, 2) {
+		assert.Equal(t, StringInt64(1), payments[0].(*payment).Amount)
+		assert.Equal(t, StringInt64(2), payments[1].(*payment).Amount)
+		assert.Nil(t, payments[1].(*payment).Customer)
+	}
+
+	rec = httptest.NewRecorder()
+	ok = Unmarshal(rec, newRequest(`{"data":{"type":"payment","id":"1","attributes":{"amount":"12.50"}}}`), &payment{})
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
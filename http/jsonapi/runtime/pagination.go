@@ -0,0 +1,212 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+)
+
+// ErrPageLimit is returned by Iterator.Err if the collection has more
+// pages than the iterator is allowed to fetch
+var ErrPageLimit = errors.New("jsonapi: page limit reached")
+
+// maxRetryAfter limits the time to wait before a rate limited
+// page is requested again
+const maxRetryAfter = 30 * time.Second
+
+// Iterator ranges over the resources of a JSON:API collection,
+// following the next links of the pages transparently:
+//
+//	it := runtime.NewIterator(client, "https://api.example.com/articles", reflect.TypeOf(new(Article)))
+//	for it.Next(ctx) {
+//		article := it.Value().(*Article)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator struct {
+	// MaxPages that are fetched, if the collection has more pages
+	// ErrPageLimit is returned. Defaults to 0 (no limit).
+	MaxPages int
+	// MaxRetries of a page that is rate limited (429). Defaults to 3.
+	MaxRetries int
+	// Prepare is called with every request before it is sent,
+	// e.g. to add the authorization
+	Prepare func(r *http.Request)
+
+	client *http.Client
+	t      reflect.Type
+	next   string
+	pages  int
+	values []interface{}
+	value  interface{}
+	err    error
+}
+
+// NewIterator creates an iterator over the collection at url, the
+// resources are unmarshaled into new values of type t (pointer to the
+// resource struct). If client is nil the http.DefaultClient is used.
+func NewIterator(client *http.Client, url string, t reflect.Type) *Iterator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Iterator{
+		MaxRetries: 3,
+		client:     client,
+		t:          t,
+		next:       url,
+	}
+}
+
+// Next advances to the next resource, the next page is fetched if
+// needed. It returns false if there are no more resources, ctx is
+// done or an error occurred, see Err.
+func (it *Iterator) Next(ctx context.Context) bool {
+	for len(it.values) == 0 {
+		if it.err != nil || it.next == "" {
+			it.value = nil
+			return false
+		}
+		if it.MaxPages > 0 && it.pages >= it.MaxPages {
+			it.err = ErrPageLimit
+			it.value = nil
+			return false
+		}
+		it.values, it.next, it.err = it.fetch(ctx, it.next)
+		it.pages++
+	}
+
+	it.value, it.values = it.values[0], it.values[1:]
+	return true
+}
+
+// Value returns the current resource, it has the type passed to
+// NewIterator
+func (it *Iterator) Value() interface{} {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// fetch requests the page at u and returns its resources
+// and the absolute url of the next page
+func (it *Iterator) fetch(ctx context.Context, u string) ([]interface{}, string, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Accept", JSONAPIContentType)
+		if it.Prepare != nil {
+			it.Prepare(req)
+		}
+
+		resp, err := it.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close() // nolint: errcheck
+		if err != nil {
+			return nil, "", err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < it.MaxRetries {
+			select {
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			case <-time.After(retryAfter(resp.Header.Get("Retry-After"), attempt)):
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", responseError(resp.StatusCode, body)
+		}
+
+		values, err := jsonapi.UnmarshalManyPayload(bytes.NewReader(body), it.t)
+		if err != nil {
+			return nil, "", fmt.Errorf("can't parse page %s: %v", u, err)
+		}
+		next, err := nextLink(req.URL, body)
+		if err != nil {
+			return nil, "", err
+		}
+		return values, next, nil
+	}
+}
+
+// nextLink returns the absolute url of the next link of the
+// page, or an empty string if it is the last page
+func nextLink(base *url.URL, body []byte) (string, error) {
+	var page struct {
+		Links map[string]json.RawMessage `json:"links"`
+	}
+	err := json.Unmarshal(body, &page)
+	if err != nil {
+		return "", err
+	}
+
+	raw, ok := page.Links[jsonapi.KeyNextPage]
+	if !ok || string(raw) == "null" {
+		return "", nil
+	}
+	var href string
+	if json.Unmarshal(raw, &href) != nil {
+		// link object
+		var link jsonapi.Link
+		err = json.Unmarshal(raw, &link)
+		if err != nil {
+			return "", fmt.Errorf("invalid next link: %s", raw)
+		}
+		href = link.Href
+	}
+	if href == "" {
+		return "", nil
+	}
+
+	next, err := base.Parse(href)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %v", href, err)
+	}
+	return next.String(), nil
+}
+
+// retryAfter returns the time to wait for the passed Retry-After
+// header (in seconds), or a time that doubles with every attempt
+func retryAfter(header string, attempt int) time.Duration {
+	d := time.Second << uint(attempt)
+	if s, err := strconv.Atoi(header); err == nil && s >= 0 {
+		d = time.Duration(s) * time.Second
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d
+}
+
+// responseError returns the jsonapi errors of the body,
+// or an error with the status code
+func responseError(code int, body []byte) error {
+	var eo errorObjects
+	if json.Unmarshal(body, &eo) == nil && len(eo.List) > 0 {
+		return eo.List
+	}
+	return fmt.Errorf("unexpected response status %d", code)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type pageArticle struct {
+	ID    string `jsonapi:"primary,article"`
+	Title string `jsonapi:"attr,title"`
+}
+
+func pageServer(t *testing.T) *httptest.Server {
+	limited := false
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != JSONAPIContentType {
+			t.Errorf("Expected accept header %q, got: %q", JSONAPIContentType, r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", JSONAPIContentType)
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"type":"article","id":"1","attributes":{"title":"a"}},{"type":"article","id":"2","attributes":{"title":"b"}}],"links":{"next":"/articles?page=2"}}`)
+		case "2":
+			if !limited {
+				limited = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprintf(w, `{"data":[],"links":{"next":{"href":"http://%s/articles?page=3"}}}`, r.Host)
+		case "3":
+			fmt.Fprint(w, `{"data":[{"type":"article","id":"3","attributes":{"title":"c"}}],"links":{"next":null}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":[{"title":"forbidden"}]}`)
+		}
+	}))
+}
+
+func collect(ctx context.Context, it *Iterator) []string {
+	var ids []string
+	for it.Next(ctx) {
+		ids = append(ids, it.Value().(*pageArticle).ID)
+	}
+	return ids
+}
+
+func TestIterator(t *testing.T) {
+	srv := pageServer(t)
+	defer srv.Close()
+	typ := reflect.TypeOf(new(pageArticle))
+
+	it := NewIterator(srv.Client(), srv.URL+"/articles", typ)
+	var authorized int
+	it.Prepare = func(r *http.Request) { authorized++ }
+	ids := collect(context.Background(), it)
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("Expected articles 1, 2 and 3, got: %v", ids)
+	}
+	if authorized != 4 {
+		t.Errorf("Expected 4 prepared requests, got: %d", authorized)
+	}
+
+	// page limit
+	it = NewIterator(srv.Client(), srv.URL+"/articles", typ)
+	it.MaxPages = 1
+	ids = collect(context.Background(), it)
+	if len(ids) != 2 || it.Err() != ErrPageLimit {
+		t.Errorf("Expected 2 articles and page limit, got: %v %v", ids, it.Err())
+	}
+
+	// rate limited without retries
+	limitedSrv := pageServer(t)
+	defer limitedSrv.Close()
+	it = NewIterator(limitedSrv.Client(), limitedSrv.URL+"/articles?page=2", typ)
+	it.MaxRetries = 0
+	collect(context.Background(), it)
+	if it.Err() == nil || it.Err().Error() != "unexpected response status 429" {
+		t.Errorf("Expected rate limit error, got: %v", it.Err())
+	}
+
+	// jsonapi errors
+	it = NewIterator(srv.Client(), srv.URL+"/articles?page=4", typ)
+	collect(context.Background(), it)
+	if errs, ok := it.Err().(Errors); !ok || errs.Error() != "forbidden" {
+		t.Errorf("Expected jsonapi errors, got: %v", it.Err())
+	}
+
+	// cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewIterator(srv.Client(), srv.URL+"/articles", typ)
+	if it.Next(ctx) || it.Err() == nil {
+		t.Errorf("Expected iteration to stop with cancelled context, got: %v", it.Err())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		header   string
+		attempt  int
+		expected time.Duration
+	}{
+		{"", 0, time.Second},
+		{"", 2, 4 * time.Second},
+		{"invalid", 1, 2 * time.Second},
+		{"5", 3, 5 * time.Second},
+		{"3600", 0, maxRetryAfter},
+		{"", 10, maxRetryAfter},
+	}
+	for _, c := range cases {
+		if d := retryAfter(c.header, c.attempt); d != c.expected {
+			t.Errorf("Expected %v for %q (attempt %d), got: %v", c.expected, c.header, c.attempt, d)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// SyncTokenParameter is the name of the query parameter containing the
+// sync token of the last delta the client received. Operations with
+// the x-sync extension get the parameter generated.
+const SyncTokenParameter = "since"
+
+// syncTokenPrefix versions the format of the sync token
+const syncTokenPrefix = "v2:"
+
+// EncodeSyncToken returns the opaque sync token for the passed
+// sync position (e.g. postgres.Changes.Next)
+func EncodeSyncToken(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncTokenPrefix + position))
+}
+
+// DecodeSyncToken returns the sync position of the passed sync token,
+// the empty token is the initial sync (empty position)
+func DecodeSyncToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(data), syncTokenPrefix) {
+		return "", fmt.Errorf("invalid sync token %q", token)
+	}
+	return strings.TrimPrefix(string(data), syncTokenPrefix), nil
+}
+
+// ScanSyncToken decodes the sync token, in case of an invalid token a 400
+// along with a jsonapi errors object is sent to the ResponseWriter and
+// false is returned.
+func ScanSyncToken(w http.ResponseWriter, token string) (string, bool) {
+	position, err := DecodeSyncToken(token)
+	if err != nil {
+		WriteSyncTokenError(w, http.StatusBadRequest, err)
+		return "", false
+	}
+	return position, true
+}
+
+// WriteSyncTokenError sends a jsonapi error for the sync token parameter,
+// e.g. 410 if the sync token expired and the client needs to sync from scratch
+func WriteSyncTokenError(w http.ResponseWriter, code int, err error) {
+	WriteError(w, code, &Error{
+		Title:  fmt.Sprintf("invalid value for %s", SyncTokenParameter),
+		Detail: err.Error(),
+		Source: &map[string]interface{}{
+			"parameter": SyncTokenParameter,
+		},
+	})
+}
+
+// Tombstone identifies a deleted resource
+type Tombstone struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Delta contains the resources that changed since the sync token of the client
+type Delta struct {
+	// Changed contains the created and updated resources,
+	// a slice of pointers to jsonapi structs
+	Changed interface{}
+	// Deleted resources
+	Deleted []Tombstone
+	// Next is the sync token for the next delta request
+	Next string
+	// More is true if there are more changes than returned
+	More bool
+}
+
+// MarshalDelta writes the delta as jsonapi document into the response writer.
+// The changed resources are the primary data, the deleted resources and the
+// next sync token are part of the meta object:
+//
+//	{"data": [...], "meta": {"deleted": [...], "syncToken": "...", "more": false}}
+func MarshalDelta(w http.ResponseWriter, delta *Delta, code int) {
+	payload, err := jsonapi.Marshal(delta.Changed)
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal jsonapi delta for %#v: %s", delta.Changed, err))
+	}
+	many, ok := payload.(*jsonapi.ManyPayload)
+	if !ok {
+		panic(fmt.Errorf("failed to marshal jsonapi delta: changed resources need to be a slice, got %T", delta.Changed))
+	}
+
+	deleted := delta.Deleted
+	if deleted == nil {
+		deleted = []Tombstone{}
+	}
+	many.Meta = &jsonapi.Meta{
+		"deleted":   deleted,
+		"syncToken": delta.Next,
+		"more":      delta.More,
+	}
+
+	w.Header().Set("Content-Type", JSONAPIContentType)
+	w.WriteHeader(code)
+
+	err = json.NewEncoder(w).Encode(many)
+	if err != nil {
+		switch err.(type) {
+		case *net.OpError:
+			log.Errorf("Connection error: %s", err)
+		default:
+			panic(fmt.Errorf("failed to write jsonapi delta: %s", err))
+		}
+	}
+}
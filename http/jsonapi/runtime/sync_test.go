@@ -0,0 +1,58 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package runtime
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSyncToken(t *testing.T) {
+	for _, position := range []string{"0.0", "1.2", "1099511627776.7"} {
+		got, err := DecodeSyncToken(EncodeSyncToken(position))
+		if err != nil || got != position {
+			t.Errorf("Expected %q, got: %q (%v)", position, got, err)
+		}
+	}
+
+	if position, err := DecodeSyncToken(""); err != nil || position != "" {
+		t.Errorf("Expected initial sync for empty token, got: %q (%v)", position, err)
+	}
+	v1 := base64.RawURLEncoding.EncodeToString([]byte("v1:42"))
+	for _, token := range []string{"42", "!!!", v1} {
+		if _, err := DecodeSyncToken(token); err == nil {
+			t.Errorf("Expected error for token %q", token)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	if _, ok := ScanSyncToken(rec, "invalid"); ok || rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid token, got: %d", rec.Code)
+	}
+}
+
+func TestMarshalDelta(t *testing.T) {
+	type Article struct {
+		ID    string `jsonapi:"primary,articles"`
+		Title string `jsonapi:"attr,title"`
+	}
+
+	rec := httptest.NewRecorder()
+	MarshalDelta(rec, &Delta{
+		Changed: []*Article{{ID: "1", Title: "Changed"}},
+		Deleted: []Tombstone{{Type: "articles", ID: "2"}},
+		Next:    "next",
+	}, http.StatusOK)
+
+	expected := `{"data":[{"type":"articles","id":"1","attributes":{"title":"Changed"}}],` +
+		`"meta":{"deleted":[{"type":"articles","id":"2"}],"more":false,"syncToken":"next"}}` + "\n"
+	if rec.Body.String() != expected {
+		t.Errorf("Expected %q, got: %q", expected, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != JSONAPIContentType {
+		t.Errorf("Expected content type %q, got: %q", JSONAPIContentType, ct)
+	}
+}
@@ -86,9 +86,17 @@ func generateValidationError(e valid.Error, source string) *Error {
 		path = strings.Replace(path, "/param", "", 1)
 	}
 
+	return NewValidationError(source, path, e.Name, e.Err.Error())
+}
+
+// NewValidationError creates a new jsonapi error for a validation error
+// of the value with the given name at path (e.g. "/data/attributes/name")
+// of the passed source (pointer or parameter). All validation errors
+// use this format.
+func NewValidationError(source, path, name, detail string) *Error {
 	return &Error{
-		Title:  fmt.Sprintf("%s is invalid", e.Name),
-		Detail: e.Err.Error(),
+		Title:  fmt.Sprintf("%s is invalid", name),
+		Detail: detail,
 		Source: &map[string]interface{}{
 			source: path,
 		},
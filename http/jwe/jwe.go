@@ -0,0 +1,243 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package jwe encrypts and decrypts payloads using JSON Web Encryption
+// (RFC 7516, compact serialization) and provides a middleware for routes
+// that require application-layer encryption of request and response bodies.
+package jwe
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported key management algorithms
+const (
+	AlgorithmRSAOAEP256 = "RSA-OAEP-256"
+	AlgorithmDirect     = "dir"
+)
+
+// Supported content encryption algorithms
+const (
+	EncryptionA128GCM = "A128GCM"
+	EncryptionA256GCM = "A256GCM"
+)
+
+// ErrKeyNotFound is returned by key providers for unknown key ids
+var ErrKeyNotFound = errors.New("jwe: key not found")
+
+// ErrInvalidToken is returned for payloads that are not valid
+// compact serialized JWE tokens
+var ErrInvalidToken = errors.New("jwe: invalid token")
+
+// Key is a key used for the key management of JWE tokens, either an
+// RSA key (RSA-OAEP-256) or a shared secret of 16 or 32 bytes (dir)
+type Key struct {
+	// ID of the key, used as kid header
+	ID string
+	// PublicKey to encrypt, PrivateKey to decrypt (RSA-OAEP-256)
+	PublicKey  *rsa.PublicKey
+	PrivateKey *rsa.PrivateKey
+	// Secret is used as content encryption key (dir)
+	Secret []byte
+}
+
+// algorithm returns the key management algorithm of the key
+func (k *Key) algorithm() string {
+	if k.Secret != nil {
+		return AlgorithmDirect
+	}
+	return AlgorithmRSAOAEP256
+}
+
+// KeyProvider provides the keys of the encrypted payloads, e.g. backed by
+// files, vault or a KMS. The encryption key usually is the public key of
+// the partner, the decryption keys are the private keys of the service.
+type KeyProvider interface {
+	// EncryptionKey returns the key to encrypt responses for the request ctx
+	EncryptionKey(ctx context.Context) (*Key, error)
+	// DecryptionKey returns the key with the passed id (kid header),
+	// the id is empty if the token has no kid header
+	DecryptionKey(ctx context.Context, id string) (*Key, error)
+}
+
+// StaticKeys is a KeyProvider with a fixed set of keys
+type StaticKeys struct {
+	Encryption *Key
+	Decryption []*Key
+}
+
+// EncryptionKey returns the encryption key
+func (s *StaticKeys) EncryptionKey(ctx context.Context) (*Key, error) {
+	if s.Encryption == nil {
+		return nil, ErrKeyNotFound
+	}
+	return s.Encryption, nil
+}
+
+// DecryptionKey returns the decryption key with the passed id, the
+// first key is returned if id is empty
+func (s *StaticKeys) DecryptionKey(ctx context.Context, id string) (*Key, error) {
+	for _, key := range s.Decryption {
+		if id == "" || key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// header is the protected header of a token
+type header struct {
+	Algorithm   string `json:"alg"`
+	Encryption  string `json:"enc"`
+	KeyID       string `json:"kid,omitempty"`
+	ContentType string `json:"cty,omitempty"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// Encrypt encrypts the plaintext using the passed key and returns the
+// compact serialized token, the content type is added as cty header
+func Encrypt(key *Key, plaintext []byte, contentType string) (string, error) {
+	h := header{Algorithm: key.algorithm(), KeyID: key.ID, ContentType: contentType}
+
+	var cek, encryptedKey []byte
+	switch h.Algorithm {
+	case AlgorithmDirect:
+		cek = key.Secret
+		enc, err := encryptionFor(len(cek))
+		if err != nil {
+			return "", err
+		}
+		h.Encryption = enc
+	default:
+		if key.PublicKey == nil {
+			return "", fmt.Errorf("jwe: key %q has no public key", key.ID)
+		}
+		h.Encryption = EncryptionA256GCM
+		cek = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+			return "", err
+		}
+		var err error
+		encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, key.PublicKey, cek, nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	protected := b64.EncodeToString(hb)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{
+		protected,
+		b64.EncodeToString(encryptedKey),
+		b64.EncodeToString(iv),
+		b64.EncodeToString(ciphertext),
+		b64.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypt decrypts the compact serialized token using the key of the
+// provider and returns the plaintext and the content type (cty header)
+func Decrypt(ctx context.Context, provider KeyProvider, token string) ([]byte, string, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 5 {
+		return nil, "", ErrInvalidToken
+	}
+	var raw [5][]byte
+	for i, part := range parts {
+		b, err := b64.DecodeString(part)
+		if err != nil {
+			return nil, "", ErrInvalidToken
+		}
+		raw[i] = b
+	}
+
+	var h header
+	if err := json.Unmarshal(raw[0], &h); err != nil {
+		return nil, "", ErrInvalidToken
+	}
+
+	key, err := provider.DecryptionKey(ctx, h.KeyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if h.Algorithm != key.algorithm() {
+		return nil, "", fmt.Errorf("jwe: algorithm %q doesn't match key %q", h.Algorithm, key.ID)
+	}
+
+	var cek []byte
+	switch h.Algorithm {
+	case AlgorithmDirect:
+		cek = key.Secret
+	case AlgorithmRSAOAEP256:
+		if key.PrivateKey == nil {
+			return nil, "", fmt.Errorf("jwe: key %q has no private key", key.ID)
+		}
+		cek, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, key.PrivateKey, raw[1], nil)
+		if err != nil {
+			return nil, "", ErrInvalidToken
+		}
+	}
+	if enc, err := encryptionFor(len(cek)); err != nil || enc != h.Encryption {
+		return nil, "", fmt.Errorf("jwe: unsupported encryption %q", h.Encryption)
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(raw[2]) != gcm.NonceSize() {
+		return nil, "", ErrInvalidToken
+	}
+	plaintext, err := gcm.Open(nil, raw[2], append(raw[3], raw[4]...), []byte(parts[0]))
+	if err != nil {
+		return nil, "", ErrInvalidToken
+	}
+	return plaintext, h.ContentType, nil
+}
+
+// encryptionFor returns the content encryption algorithm for keys
+// of the passed length
+func encryptionFor(n int) (string, error) {
+	switch n {
+	case 16:
+		return EncryptionA128GCM, nil
+	case 32:
+		return EncryptionA256GCM, nil
+	}
+	return "", fmt.Errorf("jwe: invalid content encryption key length %d", n)
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
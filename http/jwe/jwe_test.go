@@ -0,0 +1,116 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package jwe
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func rsaKey(t *testing.T, id string) *Key {
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{ID: id, PrivateKey: pk, PublicKey: &pk.PublicKey}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	keys := []*Key{
+		rsaKey(t, "rsa"),
+		{ID: "a128", Secret: []byte("0123456789abcdef")},
+		{ID: "a256", Secret: []byte("0123456789abcdef0123456789abcdef")},
+	}
+	provider := &StaticKeys{Decryption: keys}
+
+	for _, key := range keys {
+		token, err := Encrypt(key, []byte(`{"a":1}`), "application/json")
+		if err != nil {
+			t.Fatalf("%s: %v", key.ID, err)
+		}
+		if n := strings.Count(token, "."); n != 4 {
+			t.Errorf("%s: expected compact serialization with 5 parts, got %d", key.ID, n+1)
+		}
+
+		plaintext, cty, err := Decrypt(ctx, provider, token)
+		if err != nil {
+			t.Fatalf("%s: %v", key.ID, err)
+		}
+		if string(plaintext) != `{"a":1}` || cty != "application/json" {
+			t.Errorf("%s: expected decrypted payload, got %q (%s)", key.ID, plaintext, cty)
+		}
+
+		// tampered ciphertext
+		parts := strings.Split(token, ".")
+		parts[3] = "AAAA" + parts[3][4:]
+		if _, _, err := Decrypt(ctx, provider, strings.Join(parts, ".")); err != ErrInvalidToken {
+			t.Errorf("%s: expected ErrInvalidToken for tampered token, got %v", key.ID, err)
+		}
+	}
+
+	if _, err := Encrypt(&Key{Secret: []byte("short")}, nil, ""); err == nil {
+		t.Error("expected error for invalid secret length")
+	}
+	if _, _, err := Decrypt(ctx, provider, "a.b.c"); err != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+	token, _ := Encrypt(&Key{ID: "unknown", Secret: keys[1].Secret}, nil, "") // nolint: errcheck
+	if _, _, err := Decrypt(ctx, provider, token); err != ErrKeyNotFound {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	service, partner := rsaKey(t, "service"), rsaKey(t, "partner")
+	h := Middleware(&StaticKeys{Encryption: partner, Decryption: []*Key{service}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body) // nolint: errcheck
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body) // nolint: errcheck
+		}))
+
+	// unencrypted requests are rejected
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"a":1}`)))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status 415, got %d", rec.Code)
+	}
+
+	token, err := Encrypt(&Key{ID: "service", PublicKey: &service.PrivateKey.PublicKey}, []byte(`{"a":1}`), "application/vnd.api+json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(token))
+	req.Header.Set("Content-Type", ContentType)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated || rec.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("expected encrypted response with status 201, got %d (%s): %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	plaintext, cty, err := Decrypt(context.Background(), &StaticKeys{Decryption: []*Key{partner}}, rec.Body.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != `{"a":1}` || cty != "application/vnd.api+json" {
+		t.Errorf("expected echoed payload, got %q (%s)", plaintext, cty)
+	}
+
+	// invalid tokens
+	req = httptest.NewRequest("POST", "/", strings.NewReader("invalid"))
+	req.Header.Set("Content-Type", ContentType)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package jwe
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ContentType of encrypted request and response bodies
+const ContentType = "application/jose"
+
+// maxBodySize limits the size of encrypted request bodies
+const maxBodySize = 10 << 20
+
+var paceHTTPJWETotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("http_jwe_total"),
+		Help: "Collects stats about the number of encrypted payloads partitioned by direction and result",
+	},
+	[]string{"direction", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPJWETotal)
+}
+
+// Middleware decrypts the JWE encrypted request bodies and encrypts the
+// response bodies of the wrapped handler using the keys of the provider.
+// Requests with a body that isn't encrypted (Content-Type application/jose)
+// are rejected, so the middleware should only be used for the routes that
+// require encryption, e.g. on a subrouter:
+//
+//	s := r.PathPrefix("/partner").Subrouter()
+//	s.Use(jwe.Middleware(keys))
+//
+// The decrypted body has the content type of the cty header (defaults to
+// application/json). Responses are encrypted as a whole, therefore they are
+// buffered and not suitable for streaming.
+func Middleware(provider KeyProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength != 0 && r.Body != nil && r.Body != http.NoBody {
+				if !decryptRequest(w, r, provider) {
+					return
+				}
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w}
+			next.ServeHTTP(bw, r)
+			encryptResponse(w, r, provider, bw)
+		})
+	}
+}
+
+// decryptRequest replaces the body of the request with the plaintext,
+// errors are written to w and false is returned
+func decryptRequest(w http.ResponseWriter, r *http.Request, provider KeyProvider) bool {
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, ContentType) {
+		paceHTTPJWETotal.With(prometheus.Labels{"direction": "request", "result": "rejected"}).Inc()
+		runtime.WriteError(w, http.StatusUnsupportedMediaType,
+			fmt.Errorf("request body needs to be encrypted (Content-Type: %s)", ContentType))
+		return false
+	}
+
+	token, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		runtime.WriteError(w, http.StatusBadRequest, err)
+		return false
+	}
+	plaintext, cty, err := Decrypt(r.Context(), provider, string(token))
+	if err != nil {
+		paceHTTPJWETotal.With(prometheus.Labels{"direction": "request", "result": "failed"}).Inc()
+		log.Req(r).Info().Err(err).Msg("Failed to decrypt request body")
+		runtime.WriteError(w, http.StatusBadRequest, fmt.Errorf("failed to decrypt request body"))
+		return false
+	}
+	paceHTTPJWETotal.With(prometheus.Labels{"direction": "request", "result": "success"}).Inc()
+
+	if cty == "" {
+		cty = "application/json"
+	}
+	r.Header.Set("Content-Type", cty)
+	r.Header.Set("Content-Length", strconv.Itoa(len(plaintext)))
+	r.ContentLength = int64(len(plaintext))
+	r.Body = ioutil.NopCloser(bytes.NewReader(plaintext))
+	return true
+}
+
+// encryptResponse writes the encrypted buffered response to w
+func encryptResponse(w http.ResponseWriter, r *http.Request, provider KeyProvider, bw *bufferedWriter) {
+	status := bw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if bw.buf.Len() == 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	key, err := provider.EncryptionKey(r.Context())
+	var token string
+	if err == nil {
+		token, err = Encrypt(key, bw.buf.Bytes(), w.Header().Get("Content-Type"))
+	}
+	if err != nil {
+		paceHTTPJWETotal.With(prometheus.Labels{"direction": "response", "result": "failed"}).Inc()
+		log.Req(r).Error().Err(err).Msg("Failed to encrypt response body")
+		runtime.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to encrypt response body"))
+		return
+	}
+	paceHTTPJWETotal.With(prometheus.Labels{"direction": "response", "result": "success"}).Inc()
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(token)))
+	w.WriteHeader(status)
+	_, err = w.Write([]byte(token))
+	if err != nil {
+		log.Req(r).Warn().Err(err).Msg("Failed to write encrypted response")
+	}
+}
+
+// bufferedWriter buffers the response body, the headers are
+// written to the underlying response writer directly
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/pace/bricks/maintenance/metric"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -15,13 +18,13 @@ const (
 
 var (
 	paceHTTPInFlightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "pace_http_in_flight_requests",
+		Name: metric.Name("http_in_flight_requests"),
 		Help: "A gauge of requests currently being served by the wrapped handler.",
 	})
 
 	paceHTTPCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pace_http_request_total",
+			Name: metric.Name("http_request_total"),
 			Help: "A counter for requests to the wrapped handler.",
 		},
 		[]string{"code", "method", "source"},
@@ -31,7 +34,7 @@ var (
 	// It uses custom buckets based on the expected request duration.
 	paceHTTPDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pace_http_request_duration_milliseconds",
+			Name:    metric.Name("http_request_duration_milliseconds"),
 			Help:    "A histogram of latencies for requests.",
 			Buckets: []float64{10, 50, 100, 300, 600, 1000, 2500, 5000, 10000, 60000},
 		},
@@ -42,7 +45,7 @@ var (
 	// It uses custom buckets based on the expected response size.
 	paceHTTPResponseSize = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name: "pace_http_response_size_bytes",
+			Name: metric.Name("http_response_size_bytes"),
 			Help: "A histogram of response sizes for requests.",
 			Buckets: []float64{
 				100,
@@ -52,11 +55,37 @@ var (
 		},
 		[]string{"code", "method", "source"},
 	)
+
+	// ResponseGuard is labeled by the violation of the response writing.
+	paceHTTPResponseGuardTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_response_guard_total"),
+			Help: "A counter for invalid response writes that were dropped.",
+		},
+		[]string{"violation"},
+	)
+
+	// Streams is labeled by the kind of the long-lived connections.
+	paceHTTPStreams = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("http_streams"),
+			Help: "A gauge of long-lived connections (sse, websocket, stream) currently open.",
+		},
+		[]string{"kind"},
+	)
+	paceHTTPStreamsForceClosedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_streams_force_closed_total"),
+			Help: "A counter for long-lived connections closed after the shutdown grace period.",
+		},
+		[]string{"kind"},
+	)
 )
 
 func init() {
 	// Register all of the metrics in the standard registry.
-	prometheus.MustRegister(paceHTTPInFlightGauge, paceHTTPCounter, paceHTTPDuration, paceHTTPResponseSize)
+	prometheus.MustRegister(paceHTTPInFlightGauge, paceHTTPCounter, paceHTTPDuration, paceHTTPResponseSize, paceHTTPResponseGuardTotal)
+	prometheus.MustRegister(paceHTTPStreams, paceHTTPStreamsForceClosedTotal)
 }
 
 func metricsMiddleware(next http.Handler) http.Handler {
@@ -98,6 +127,22 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Flush implements http.Flusher if the wrapped writer does
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the wrapped writer does
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
 func filterRequestSource(source string) string {
 	switch source {
 	case "uptime", "kubernetes", "nginx", "livetest":
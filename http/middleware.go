@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
 	"github.com/pace/bricks/http/jsonapi/runtime"
 	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/pkg/reqcontext"
 )
 
 type jsonApiErrorWriter struct {
@@ -23,7 +25,8 @@ func (e *jsonApiErrorWriter) Write(b []byte) (int, error) {
 		return 0, nil
 	}
 	repliesJsonApi := e.Header().Get("Content-Type") == runtime.JSONAPIContentType
-	requestsJsonApi := e.req.Header.Get("Accept") == runtime.JSONAPIContentType
+	accept := e.req.Header.Get("Accept")
+	requestsJsonApi := accept == runtime.JSONAPIContentType || accept == runtime.MessagePackContentType
 	if e.statusCode >= 400 && requestsJsonApi && !repliesJsonApi {
 		if e.hasBytes {
 			log.Req(e.req).Warn().Msgf("Body already contains data from previous writes: ignoring: %q", string(b))
@@ -54,3 +57,28 @@ func JsonApiErrorWriterMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(&jsonApiErrorWriter{ResponseWriter: w, req: r}, r)
 	})
 }
+
+// routeMiddleware stores the name (or path template if unnamed)
+// of the matched route in the request context
+func routeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name := routeName(r); name != "" {
+			r = r.WithContext(reqcontext.WithRoute(r.Context(), name))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeName returns the name (or path template if unnamed) of the
+// matched route, or an empty string if no route matched
+func routeName(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	name := route.GetName()
+	if name == "" {
+		name, _ = route.GetPathTemplate() // nolint: errcheck
+	}
+	return name
+}
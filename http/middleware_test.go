@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/pkg/reqcontext"
 )
 
 const payload = "dummy response data"
@@ -141,3 +142,16 @@ func TestJsonApiErrorMiddlewareInvalidWriteOrder(t *testing.T) {
 		t.Fatalf("bad response body, expected %q, got %q", payload, string(b))
 	}
 }
+
+func TestRouteMiddleware(t *testing.T) {
+	var route string
+	r := Router()
+	r.HandleFunc("/articles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		route, _ = reqcontext.Route(r.Context())
+	}).Name("GetArticle")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/articles/1", nil))
+	if route != "GetArticle" {
+		t.Errorf("expected route %q, got: %q", "GetArticle", route)
+	}
+}
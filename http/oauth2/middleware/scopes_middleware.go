@@ -25,9 +25,14 @@ func NewScopesMiddleware(scopes RequiredScopes) *ScopesMiddleware {
 }
 
 // Handler checks if the token extracted from the request's context has the required scope
-// for the requested route and returns a 401 response if not.
+// for the requested route and returns a 401 response if not. Anonymous requests on routes
+// with optional authentication are passed through.
 func (m *ScopesMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if oauth2.IsAnonymous(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		routeName := mux.CurrentRoute(r).GetName()
 		if oauth2.HasScope(r.Context(), m.RequiredScopes[routeName]) {
 			next.ServeHTTP(w, r)
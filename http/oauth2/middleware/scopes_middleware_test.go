@@ -61,6 +61,26 @@ func TestScopesMiddleware(t *testing.T) {
 	})
 }
 
+func TestScopesMiddlewareAnonymous(t *testing.T) {
+	m := NewScopesMiddleware(RequiredScopes{"GetFoo": oauth2.Scope("foo:read")})
+	om := oauth2.NewMiddleware(&tokenIntrospecter{})
+	om.OptionalRoutes = oauth2.OptionalRoutes{"GetFoo": true}
+
+	r := mux.NewRouter()
+	r.Use(om.Handler)
+	r.Use(m.Handler)
+	r.HandleFunc("/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Hello")
+	}).Name("GetFoo")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/foo", nil))
+
+	if got, ex := w.Code, 200; got != ex {
+		t.Errorf("Expected status code %d, got %d", ex, got)
+	}
+}
+
 func setupRouter(requiredScope string, tokenScope string) *mux.Router {
 	rs := RequiredScopes{
 		"GetFoo": oauth2.Scope(requiredScope),
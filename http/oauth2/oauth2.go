@@ -8,25 +8,86 @@ package oauth2
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/caarlos0/env"
+	"github.com/gorilla/mux"
 	opentracing "github.com/opentracing/opentracing-go"
 	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/configcheck"
 	"github.com/pace/bricks/maintenance/log"
 )
 
+type config struct {
+	// PropagateUserID allows to annotate backend queries and outgoing
+	// requests with the user id (the client id is always propagated)
+	PropagateUserID bool `env:"OAUTH2_PROPAGATE_USER_ID" envDefault:"true"`
+	// RevocationCacheTTL is the maximum time revoked tokens keep working
+	// if a RevocationCache is used
+	RevocationCacheTTL time.Duration `env:"OAUTH2_REVOCATION_CACHE_TTL" envDefault:"5s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+func init() {
+	configcheck.Register("oauth2", Setup)
+}
+
+// Setup parses and validates the OAUTH2_* environment of the
+// middleware and the revocation cache, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = parseConfig(&cfg)
+	})
+	return errSetup
+}
+
+// parseConfig parses the environment into the passed config
+func parseConfig(c *config) error {
+	err := env.Parse(c)
+	if err != nil {
+		return err
+	}
+	if c.RevocationCacheTTL <= 0 {
+		return fmt.Errorf("OAUTH2_REVOCATION_CACHE_TTL needs to be positive, got %v", c.RevocationCacheTTL)
+	}
+	return nil
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse oauth2 environment: %v", err)
+	}
+}
+
 type ctxkey string
 
 var tokenKey = ctxkey("Token")
+var anonymousKey = ctxkey("Anonymous")
 
 const headerPrefix = "Bearer "
 
 // Middleware holds data necessary for Oauth processing
 type Middleware struct {
 	Backend TokenIntrospecter
+	// Revocation is optionally used to check if the token was revoked
+	Revocation RevocationChecker
+	// OptionalRoutes are the names of the routes where authentication is
+	// attempted but not required
+	OptionalRoutes OptionalRoutes
 }
 
+// OptionalRoutes defines for each route (name) if authentication is optional
+type OptionalRoutes map[string]bool
+
 type token struct {
 	value    string
 	userID   string
@@ -40,18 +101,29 @@ func NewMiddleware(backend TokenIntrospecter) *Middleware {
 }
 
 // Handler will parse the bearer token, introspect it, and put the token and other
-// relevant information back in the context.
+// relevant information back in the context. Requests to optional routes (see
+// OptionalRoutes) proceed anonymously if the token is missing or invalid.
 func (m *Middleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Setup tracing
 		span, ctx := opentracing.StartSpanFromContext(r.Context(), "Oauth2")
 		defer span.Finish()
 
+		// unauthorized returns the error or proceeds anonymously for optional routes
+		unauthorized := func(msg string, code int) {
+			if m.isOptional(r) {
+				span.LogFields(olog.Bool("anonymous", true))
+				next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, anonymousKey, true)))
+				return
+			}
+			http.Error(w, msg, code)
+		}
+
 		qualifiedToken := r.Header.Get("Authorization")
 
 		items := strings.Split(qualifiedToken, headerPrefix)
 		if len(items) < 2 {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			unauthorized("Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
@@ -61,18 +133,32 @@ func (m *Middleware) Handler(next http.Handler) http.Handler {
 		switch err {
 		case ErrBadUpstreamResponse:
 			log.Req(r).Info().Msg(err.Error())
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			unauthorized(err.Error(), http.StatusBadGateway)
 			return
 		case ErrUpstreamConnection:
 			log.Req(r).Info().Msg(err.Error())
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			unauthorized(err.Error(), http.StatusBadGateway)
 			return
 		case ErrInvalidToken:
 			log.Req(r).Info().Msg(err.Error())
-			http.Error(w, err.Error(), http.StatusUnauthorized)
+			unauthorized(err.Error(), http.StatusUnauthorized)
 			return
 		}
 
+		if m.Revocation != nil {
+			revoked, err := m.Revocation.IsRevoked(ctx, tokenValue)
+			if err != nil {
+				log.Req(r).Info().Err(err).Msg(ErrUpstreamConnection.Error())
+				unauthorized(ErrUpstreamConnection.Error(), http.StatusBadGateway)
+				return
+			}
+			if revoked {
+				log.Req(r).Info().Msg(ErrRevokedToken.Error())
+				unauthorized(ErrRevokedToken.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
 		t := fromIntrospectResponse(s, tokenValue)
 
 		ctx = context.WithValue(ctx, tokenKey, &t)
@@ -88,6 +174,25 @@ func (m *Middleware) Handler(next http.Handler) http.Handler {
 	})
 }
 
+// isOptional returns true if authentication is optional for the route of r
+func (m *Middleware) isOptional(r *http.Request) bool {
+	if len(m.OptionalRoutes) == 0 {
+		return false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	return m.OptionalRoutes[route.GetName()]
+}
+
+// IsAnonymous returns true if the request proceeded without authentication
+// on a route with optional authentication
+func IsAnonymous(ctx context.Context) bool {
+	anonymous, _ := ctx.Value(anonymousKey).(bool)
+	return anonymous
+}
+
 func fromIntrospectResponse(s *IntrospectResponse, tokenValue string) token {
 	t := token{
 		userID:   s.UserID,
@@ -0,0 +1,77 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOptionalRoutes(t *testing.T) {
+	m := NewMiddleware(&tokenIntrospecterWithError{returnedErr: ErrInvalidToken})
+	m.OptionalRoutes = OptionalRoutes{"GetPublic": true}
+
+	r := mux.NewRouter()
+	r.Use(m.Handler)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if !IsAnonymous(r.Context()) {
+			t.Error("Expected request to be anonymous")
+		}
+		if _, ok := ClientID(r.Context()); ok {
+			t.Error("Expected no client id for anonymous request")
+		}
+	}
+	r.HandleFunc("/public", handler).Name("GetPublic")
+	r.HandleFunc("/private", handler).Name("GetPrivate")
+
+	cases := []struct {
+		path, token string
+		code        int
+	}{
+		{"/public", "", 200},
+		{"/public", "Bearer invalid", 200},
+		{"/private", "", 401},
+		{"/private", "Bearer invalid", 401},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", c.path, nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", c.token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != c.code {
+			t.Errorf("Expected %d for %s with token %q, got: %d", c.code, c.path, c.token, rec.Code)
+		}
+	}
+}
+
+func TestOptionalRouteWithValidToken(t *testing.T) {
+	m := NewMiddleware(activeTokenIntrospecter{})
+	m.OptionalRoutes = OptionalRoutes{"GetPublic": true}
+
+	r := mux.NewRouter()
+	r.Use(m.Handler)
+	r.HandleFunc("/public", func(w http.ResponseWriter, r *http.Request) {
+		if IsAnonymous(r.Context()) {
+			t.Error("Expected request not to be anonymous")
+		}
+		if clientID, _ := ClientID(r.Context()); clientID != "client" {
+			t.Errorf("Expected client id %q, got: %q", "client", clientID)
+		}
+	}).Name("GetPublic")
+
+	req := httptest.NewRequest("GET", "/public", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Errorf("Expected 200, got: %d", rec.Code)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// Rule grants access to a resource if it is satisfied by
+// the token of the request
+type Rule struct {
+	// Name of the rule, logged in the audit log. Unnamed
+	// rules are logged with their index, e.g. "rule[1]".
+	Name  string
+	Allow func(ctx context.Context) bool
+}
+
+// UserOwns grants access to the user with userID, e.g.
+// UserOwns(car.UserID). Empty user ids never match.
+func UserOwns(userID string) Rule {
+	return Rule{
+		Name: "user_owns",
+		Allow: func(ctx context.Context) bool {
+			token := tokenFromContext(ctx)
+			return token != nil && userID != "" && token.userID == userID
+		},
+	}
+}
+
+// ClientOwns grants access to the client with clientID.
+// Empty client ids never match.
+func ClientOwns(clientID string) Rule {
+	return Rule{
+		Name: "client_owns",
+		Allow: func(ctx context.Context) bool {
+			token := tokenFromContext(ctx)
+			return token != nil && clientID != "" && token.clientID == clientID
+		},
+	}
+}
+
+// ScopeGrants grants access to tokens with the scope, e.g.
+// for administrative clients. Empty scopes never match.
+func ScopeGrants(scope Scope) Rule {
+	return Rule{
+		Name: "scope:" + string(scope),
+		Allow: func(ctx context.Context) bool {
+			return len(scope.toSlice()) > 0 && HasScope(ctx, scope)
+		},
+	}
+}
+
+// Policy declares the access to a type of resources, access is granted
+// if any of the rules passed to Authorize or Check is satisfied. Every
+// decision is logged as audit log entry.
+type Policy struct {
+	// Resource is the type of the resources, e.g. "car"
+	Resource string
+	// Conceal answers denied requests with 404 Not Found instead
+	// of 403 Forbidden, to not disclose that the resource exists
+	Conceal bool
+}
+
+// Authorize returns true if any of the rules grants access to the
+// resource with id to the token of ctx
+func (p Policy) Authorize(ctx context.Context, id string, rules ...Rule) bool {
+	granted := -1 // index of the rule that granted access
+	for i, rule := range rules {
+		if rule.Allow(ctx) {
+			granted = i
+			break
+		}
+	}
+	p.audit(ctx, id, rules, granted)
+	return granted >= 0
+}
+
+// Check authorizes the request (see Authorize) and writes a jsonapi
+// 403 Forbidden response (404 Not Found if concealed) if access is
+// denied. Returns true if the handler can proceed.
+func (p Policy) Check(w http.ResponseWriter, r *http.Request, id string, rules ...Rule) bool {
+	if p.Authorize(r.Context(), id, rules...) {
+		return true
+	}
+
+	if p.Conceal {
+		runtime.WriteError(w, http.StatusNotFound, errors.New("Not Found"))
+	} else {
+		runtime.WriteError(w, http.StatusForbidden, errors.New("Forbidden"))
+	}
+	return false
+}
+
+// audit logs the decision with the identity of the request, granted
+// is the index of the rule that granted access, -1 if access is denied
+func (p Policy) audit(ctx context.Context, id string, rules []Rule, granted int) {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("rule[%d]", i)
+		}
+	}
+
+	decision, grantedBy := "denied", ""
+	if granted >= 0 {
+		decision, grantedBy = "granted", names[granted]
+	}
+
+	var clientID, userID string
+	if token := tokenFromContext(ctx); token != nil {
+		clientID, userID = token.clientID, token.userID
+	}
+
+	log.Ctx(ctx).Info().
+		Bool("audit", true).
+		Str("resource", p.Resource).
+		Str("resource_id", id).
+		Str("client_id", clientID).
+		Str("user_id", userID).
+		Str("decision", decision).
+		Str("rules", strings.Join(names, ",")).
+		Str("granted_by", grantedBy).
+		Msg("Authorization")
+
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span.LogFields(olog.String("resource", p.Resource), olog.String("resource_id", id),
+			olog.String("authorization", decision))
+	}
+}
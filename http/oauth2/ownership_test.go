@@ -0,0 +1,75 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tokenKey, &token{
+		userID:   "someuserid",
+		clientID: "someclientid",
+		scope:    Scope("cars:read"),
+	})
+	p := Policy{Resource: "car"}
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		rules    []Rule
+		expected bool
+	}{
+		{"owner", ctx, []Rule{UserOwns("someuserid")}, true},
+		{"other user", ctx, []Rule{UserOwns("otheruserid")}, false},
+		{"empty owner", context.WithValue(ctx, tokenKey, &token{}), []Rule{UserOwns("")}, false},
+		{"client", ctx, []Rule{ClientOwns("someclientid")}, true},
+		{"scope", ctx, []Rule{UserOwns("otheruserid"), ScopeGrants("cars:read")}, true},
+		{"missing scope", ctx, []Rule{ScopeGrants("cars:admin")}, false},
+		{"empty scope", ctx, []Rule{ScopeGrants("")}, false},
+		{"no token", context.Background(), []Rule{UserOwns("someuserid")}, false},
+		{"no rules", ctx, nil, false},
+		{"unnamed rule", ctx, []Rule{{Allow: func(context.Context) bool { return true }}}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := p.Authorize(c.ctx, "1", c.rules...); got != c.expected {
+				t.Errorf("Expected %v, got: %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tokenKey, &token{userID: "someuserid"})
+
+	cases := []struct {
+		name     string
+		policy   Policy
+		owner    string
+		expected int
+	}{
+		{"granted", Policy{Resource: "car"}, "someuserid", http.StatusOK},
+		{"forbidden", Policy{Resource: "car"}, "otheruserid", http.StatusForbidden},
+		{"concealed", Policy{Resource: "car", Conceal: true}, "otheruserid", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/cars/1", nil).WithContext(ctx)
+			if c.policy.Check(rec, req, "1", UserOwns(c.owner)) {
+				rec.WriteHeader(http.StatusOK)
+			}
+			if rec.Code != c.expected {
+				t.Errorf("Expected %d, got: %d", c.expected, rec.Code)
+			}
+			if c.expected != http.StatusOK && rec.Header().Get("Content-Type") != "application/vnd.api+json" {
+				t.Errorf("Expected jsonapi error, got: %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+)
+
+// Identity returns the client id and user id of the authenticated
+// request to annotate logs and traces of backend queries and outgoing
+// requests. The user id is empty if propagation of the user id is
+// disabled. Returns false if ctx doesn't contain a token.
+func Identity(ctx context.Context) (clientID, userID string, ok bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+
+	token := tokenFromContext(ctx)
+	if token == nil {
+		return "", "", false
+	}
+
+	mustSetup()
+	if cfg.PropagateUserID {
+		userID = token.userID
+	}
+
+	return token.clientID, userID, true
+}
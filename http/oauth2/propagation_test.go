@@ -0,0 +1,33 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdentity(t *testing.T) {
+	if _, _, ok := Identity(context.Background()); ok {
+		t.Fatal("Expected no identity without token")
+	}
+
+	ctx := context.WithValue(context.Background(), tokenKey, &token{
+		userID:   "someuserid",
+		clientID: "someclientid",
+	})
+
+	clientID, userID, ok := Identity(ctx)
+	if !ok || clientID != "someclientid" || userID != "someuserid" {
+		t.Errorf("Expected client and user id, got: %q %q %v", clientID, userID, ok)
+	}
+
+	defer func(propagate bool) { cfg.PropagateUserID = propagate }(cfg.PropagateUserID)
+	cfg.PropagateUserID = false
+
+	clientID, userID, _ = Identity(ctx)
+	if clientID != "someclientid" || userID != "" {
+		t.Errorf("Expected client id only, got: %q %q", clientID, userID)
+	}
+}
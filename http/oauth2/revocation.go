@@ -0,0 +1,123 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRevokedToken in case the token was revoked
+var ErrRevokedToken = errors.New("user token was revoked")
+
+// RevocationChecker needs to be implemented to check if long-lived
+// tokens were revoked
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, token string) (bool, error)
+}
+
+// RevocationCheckerFunc allows to use a function as RevocationChecker
+type RevocationCheckerFunc func(ctx context.Context, token string) (bool, error)
+
+// IsRevoked calls f(ctx, token)
+func (f RevocationCheckerFunc) IsRevoked(ctx context.Context, token string) (bool, error) {
+	return f(ctx, token)
+}
+
+// IntrospectionRevocationChecker uses the token introspection to check
+// if a token was revoked (revoked tokens are not active)
+func IntrospectionRevocationChecker(backend TokenIntrospecter) RevocationChecker {
+	return RevocationCheckerFunc(func(ctx context.Context, token string) (bool, error) {
+		_, err := backend.IntrospectToken(ctx, token)
+		if err == ErrInvalidToken {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// RevocationCache caches the results of the passed RevocationChecker for a
+// short time (OAUTH2_REVOCATION_CACHE_TTL), this way revoked tokens stop
+// working within the TTL without checking the revocation on every request.
+// The results are stored by the sha256 hash of the token, the cache doesn't
+// keep the tokens. Run removes the expired results in the background.
+type RevocationCache struct {
+	checker RevocationChecker
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*revocationEntry
+}
+
+type revocationEntry struct {
+	revoked bool
+	checked time.Time
+}
+
+// NewRevocationCache creates a new cache for the passed checker
+func NewRevocationCache(checker RevocationChecker) *RevocationCache {
+	mustSetup()
+	return &RevocationCache{
+		checker: checker,
+		ttl:     cfg.RevocationCacheTTL,
+		entries: make(map[[sha256.Size]byte]*revocationEntry),
+	}
+}
+
+// IsRevoked returns the cached revocation state of the token, the
+// checker is used if there is no result for the token or the result expired
+func (c *RevocationCache) IsRevoked(ctx context.Context, token string) (bool, error) {
+	now := time.Now()
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && now.Sub(e.checked) < c.ttl {
+		c.mu.Unlock()
+		return e.revoked, nil
+	}
+	c.mu.Unlock()
+
+	revoked, err := c.checker.IsRevoked(ctx, token)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &revocationEntry{revoked: revoked, checked: now}
+	c.mu.Unlock()
+
+	return revoked, nil
+}
+
+// Run removes the expired results in the background (every TTL)
+// until ctx is done
+func (c *RevocationCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.removeExpired()
+		}
+	}
+}
+
+func (c *RevocationCache) removeExpired() {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if now.Sub(e.checked) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type activeTokenIntrospecter struct{}
+
+func (activeTokenIntrospecter) IntrospectToken(ctx context.Context, token string) (*IntrospectResponse, error) {
+	return &IntrospectResponse{Active: true, ClientID: "client"}, nil
+}
+
+func TestRevocationCache(t *testing.T) {
+	var calls, revoked int32
+	cache := NewRevocationCache(RevocationCheckerFunc(func(ctx context.Context, token string) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return atomic.LoadInt32(&revoked) == 1, nil
+	}))
+	cache.ttl = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cache.Run(ctx)
+
+	for i := 0; i < 3; i++ {
+		if r, err := cache.IsRevoked(ctx, "token"); err != nil || r {
+			t.Fatalf("Expected token not to be revoked, got: %v %v", r, err)
+		}
+	}
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Errorf("Expected one check for cached token, got: %d", c)
+	}
+
+	atomic.StoreInt32(&revoked, 1)
+	time.Sleep(50 * time.Millisecond)
+
+	if r, _ := cache.IsRevoked(ctx, "token"); !r {
+		t.Error("Expected token to be revoked after the TTL")
+	}
+}
+
+func TestMiddlewareRevokedToken(t *testing.T) {
+	m := NewMiddleware(activeTokenIntrospecter{})
+	m.Revocation = RevocationCheckerFunc(func(ctx context.Context, token string) (bool, error) {
+		return token == "revoked", nil
+	})
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for token, code := range map[string]int{"revoked": 401, "valid": 200} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != code {
+			t.Errorf("Expected %d for token %q, got: %d", code, token, rec.Code)
+		}
+	}
+}
+
+func TestParseConfigRevocationCacheTTL(t *testing.T) {
+	for _, value := range []string{"0s", "-5s"} {
+		os.Setenv("OAUTH2_REVOCATION_CACHE_TTL", value) // nolint: errcheck
+		var c config
+		if err := parseConfig(&c); err == nil {
+			t.Errorf("Expected error for OAUTH2_REVOCATION_CACHE_TTL=%q", value)
+		}
+	}
+	os.Unsetenv("OAUTH2_REVOCATION_CACHE_TTL") // nolint: errcheck
+}
+
+func TestRevocationCacheRemoveExpired(t *testing.T) {
+	cache := NewRevocationCache(RevocationCheckerFunc(func(ctx context.Context, token string) (bool, error) {
+		return false, nil
+	}))
+	cache.ttl = time.Nanosecond
+
+	if _, err := cache.IsRevoked(context.Background(), "token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.entries[sha256.Sum256([]byte("token"))]; !ok {
+		t.Error("Expected the result to be stored by the hash of the token")
+	}
+
+	time.Sleep(time.Millisecond)
+	cache.removeExpired()
+	if n := len(cache.entries); n != 0 {
+		t.Errorf("Expected expired results to be removed, got: %d", n)
+	}
+}
@@ -4,9 +4,19 @@
 package oauth2
 
 import (
+	"context"
 	"strings"
+
+	"github.com/pace/bricks/http/jsonapi/runtime"
 )
 
+func init() {
+	// the jsonapi runtime masks attributes based on the scopes of the token
+	runtime.HasScope = func(ctx context.Context, scope string) bool {
+		return HasScope(ctx, Scope(scope))
+	}
+}
+
 // Scope represents an OAuth 2 access token scope
 type Scope string
 
@@ -0,0 +1,175 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package proxy provides an instrumented reverse proxy to expose
+// a slice of an upstream API to authorized clients.
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/http/transport"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHTTPProxyRequestTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_proxy_request_total"),
+			Help: "Collects stats about the number of requests forwarded to the upstream partitioned by code",
+		},
+		[]string{"upstream", "code"},
+	)
+	paceHTTPProxyRequestDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: metric.Name("http_proxy_request_duration_seconds"),
+			Help: "Collect performance metrics for each request forwarded to the upstream",
+		},
+		[]string{"upstream"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPProxyRequestTotal)
+	prometheus.MustRegister(paceHTTPProxyRequestDurationSeconds)
+}
+
+// DefaultScrubRequestHeaders are removed from requests before forwarding
+var DefaultScrubRequestHeaders = []string{"Authorization", "Cookie", "Uber-Trace-Id"}
+
+// DefaultScrubResponseHeaders are removed from upstream responses
+var DefaultScrubResponseHeaders = []string{"Set-Cookie", "Server", "X-Powered-By"}
+
+// Options configure the proxy
+type Options struct {
+	// Upstream base URL the requests are forwarded to
+	Upstream *url.URL
+	// StripPrefix is removed from the request path before forwarding
+	StripPrefix string
+	// Rewrite optionally rewrites the path (after StripPrefix)
+	Rewrite func(path string) string
+	// Anonymous allows requests without oauth2 token, by default
+	// the oauth2 middleware needs to authenticate the request
+	Anonymous bool
+	// Scope that is required to forward the request
+	Scope oauth2.Scope
+	// ForwardToken passes the bearer token of the request to the upstream
+	ForwardToken bool
+	// ScrubRequestHeaders are removed before forwarding (in addition to
+	// DefaultScrubRequestHeaders)
+	ScrubRequestHeaders []string
+	// ScrubResponseHeaders are removed from the upstream response (in
+	// addition to DefaultScrubResponseHeaders)
+	ScrubResponseHeaders []string
+	// Transport used to forward the requests, defaults to a transport
+	// chain with tracing and logging
+	Transport http.RoundTripper
+}
+
+// Proxy forwards requests to the upstream
+type Proxy struct {
+	opts  Options
+	proxy *httputil.ReverseProxy
+}
+
+// New creates a new proxy using the passed options
+func New(opts Options) *Proxy {
+	if opts.Transport == nil {
+		opts.Transport = transport.Chain(&transport.JaegerRoundTripper{}, &transport.LoggingRoundTripper{})
+	}
+
+	p := &Proxy{opts: opts}
+	p.proxy = &httputil.ReverseProxy{
+		Director:       p.director,
+		Transport:      opts.Transport,
+		ModifyResponse: p.modifyResponse,
+		ErrorHandler:   p.errorHandler,
+	}
+	return p
+}
+
+// ServeHTTP enforces authorization and forwards the request to the upstream
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !p.opts.Anonymous {
+		if _, ok := oauth2.BearerToken(ctx); !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if p.opts.Scope != "" && !oauth2.HasScope(ctx, p.opts.Scope) {
+			http.Error(w, fmt.Sprintf("Forbidden - requires scope %q", p.opts.Scope), http.StatusForbidden)
+			return
+		}
+	}
+
+	start := time.Now()
+	p.proxy.ServeHTTP(w, r)
+	paceHTTPProxyRequestDurationSeconds.With(prometheus.Labels{
+		"upstream": p.opts.Upstream.Host,
+	}).Observe(time.Since(start).Seconds())
+}
+
+func (p *Proxy) director(r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, p.opts.StripPrefix)
+	if p.opts.Rewrite != nil {
+		path = p.opts.Rewrite(path)
+	}
+
+	r.URL.Scheme = p.opts.Upstream.Scheme
+	r.URL.Host = p.opts.Upstream.Host
+	r.URL.Path = singleJoiningSlash(p.opts.Upstream.Path, path)
+	r.URL.RawPath = ""
+	r.Host = p.opts.Upstream.Host
+
+	scrub(r.Header, DefaultScrubRequestHeaders, p.opts.ScrubRequestHeaders)
+	if p.opts.ForwardToken {
+		oauth2.Request(r)
+	}
+}
+
+func (p *Proxy) modifyResponse(resp *http.Response) error {
+	scrub(resp.Header, DefaultScrubResponseHeaders, p.opts.ScrubResponseHeaders)
+	paceHTTPProxyRequestTotal.With(prometheus.Labels{
+		"upstream": p.opts.Upstream.Host,
+		"code":     strconv.Itoa(resp.StatusCode),
+	}).Inc()
+	return nil
+}
+
+func (p *Proxy) errorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	log.Req(r).Warn().Err(err).Str("upstream", p.opts.Upstream.Host).Msg("Proxy request failed")
+	paceHTTPProxyRequestTotal.With(prometheus.Labels{
+		"upstream": p.opts.Upstream.Host,
+		"code":     strconv.Itoa(http.StatusBadGateway),
+	}).Inc()
+	http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+}
+
+func scrub(header http.Header, lists ...[]string) {
+	for _, list := range lists {
+		for _, name := range list {
+			header.Del(name)
+		}
+	}
+}
+
+func singleJoiningSlash(a, b string) string {
+	aslash := strings.HasSuffix(a, "/")
+	bslash := strings.HasPrefix(b, "/")
+	switch {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	}
+	return a + b
+}
@@ -0,0 +1,93 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package proxy
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pace/bricks/http/oauth2"
+)
+
+type tokenIntrospecter struct{}
+
+func (tokenIntrospecter) IntrospectToken(ctx context.Context, token string) (*oauth2.IntrospectResponse, error) {
+	return &oauth2.IntrospectResponse{Active: true, Scope: "foo:read"}, nil
+}
+
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "" {
+			t.Error("Expected cookie to be scrubbed")
+		}
+		w.Header().Set("Set-Cookie", "session=1")
+		w.Header().Set("X-Internal", "secret")
+		w.Write([]byte(r.URL.Path + " " + r.Header.Get("Authorization"))) // nolint: errcheck
+	}))
+	defer upstream.Close()
+
+	u, _ := url.Parse(upstream.URL + "/api")
+	p := New(Options{
+		Upstream:             u,
+		StripPrefix:          "/proxy",
+		Scope:                "foo:read",
+		ForwardToken:         true,
+		ScrubResponseHeaders: []string{"X-Internal"},
+	})
+	h := oauth2.NewMiddleware(tokenIntrospecter{}).Handler(p)
+
+	t.Run("authorized", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/proxy/items/1", nil)
+		req.Header.Set("Authorization", "Bearer sometoken")
+		req.Header.Set("Cookie", "session=2")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		resp := rec.Result()
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != 200 {
+			t.Fatalf("Expected 200, got: %d", resp.StatusCode)
+		}
+		if got, ex := string(body), "/api/items/1 Bearer sometoken"; got != ex {
+			t.Errorf("Expected body %q, got: %q", ex, got)
+		}
+		if resp.Header.Get("Set-Cookie") != "" || resp.Header.Get("X-Internal") != "" {
+			t.Errorf("Expected response headers to be scrubbed, got: %v", resp.Header)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest("GET", "/proxy/items/1", nil))
+		if rec.Code != 401 {
+			t.Errorf("Expected 401, got: %d", rec.Code)
+		}
+	})
+
+	t.Run("insufficient scope", func(t *testing.T) {
+		ps := New(Options{Upstream: u, Scope: "foo:write"})
+		req := httptest.NewRequest("GET", "/items/1", nil)
+		req.Header.Set("Authorization", "Bearer sometoken")
+		rec := httptest.NewRecorder()
+		oauth2.NewMiddleware(tokenIntrospecter{}).Handler(ps).ServeHTTP(rec, req)
+		if rec.Code != 403 {
+			t.Errorf("Expected 403, got: %d", rec.Code)
+		}
+	})
+}
+
+func TestProxyUpstreamError(t *testing.T) {
+	u, _ := url.Parse("http://127.0.0.1:1")
+	p := New(Options{Upstream: u, Anonymous: true})
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected 502, got: %d", rec.Code)
+	}
+}
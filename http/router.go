@@ -8,32 +8,55 @@ import (
 	"net/http/pprof"
 
 	"github.com/gorilla/mux"
+	"github.com/pace/bricks/maintenance/debugtrace"
 	"github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/feedback"
 	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/jobs"
 	"github.com/pace/bricks/maintenance/log"
 	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/maintenance/readonly"
+	"github.com/pace/bricks/maintenance/support"
 	"github.com/pace/bricks/maintenance/tracing"
 )
 
 // Router returns the default microservice endpoints for
 // health, metrics and debugging
 func Router() *mux.Router {
+	mustSetup()
 	r := mux.NewRouter()
 
-	r.Use(metricsMiddleware)
+	// guards the writes of all following middlewares and handlers
+	r.Use(ResponseGuardMiddleware)
+
+	// captures the timing breakdown of flagged requests
+	r.Use(debugtrace.Middleware)
+
+	r.Use(debugtrace.Wrap("metrics", metricsMiddleware))
 
 	// last resort error handler
-	r.Use(errors.Handler())
+	r.Use(debugtrace.Wrap("errors", errors.Handler()))
 
 	// for logging
-	r.Use(log.Handler())
+	r.Use(debugtrace.Wrap("log", log.Handler()))
 
-	r.Use(tracing.Handler(
+	// for correlating queries with requests
+	r.Use(debugtrace.Wrap("route", routeMiddleware))
+
+	// reject writes in read-only mode
+	r.Use(debugtrace.Wrap("readonly", readonly.Middleware(
+		// the mode can always be changed
+		"/debug",
+	)))
+
+	r.Use(debugtrace.Wrap("tracing", tracing.Handler(
 		// no tracing for these prefixes
 		"/metrics",
 		"/health",
 		"/debug",
-	))
+	)))
+
+	r.Use(debugtrace.HandlerMiddleware)
 
 	// for prometheus
 	r.Handle("/metrics", metric.Handler())
@@ -41,13 +64,39 @@ func Router() *mux.Router {
 	// for the API gateway
 	r.Handle("/health", health.Handler())
 
+	// for kubernetes, fails if the backends are not ready or compatible
+	r.Handle("/health/ready", health.ReadinessHandler())
+
+	// for clients to report responses they fail to decode, authenticated
+	// by the middleware passed to feedback.Authenticate
+	r.Handle(feedback.Path, feedback.Handler())
+
 	// for debugging purposes (e.g. deadlock, ...)
-	p := r.PathPrefix("/debug/pprof").Subrouter()
-	p.HandleFunc("/cmdline", pprof.Cmdline)
-	p.HandleFunc("/profile", pprof.Profile)
-	p.HandleFunc("/symbol", pprof.Symbol)
-	p.HandleFunc("/trace", pprof.Trace)
-	p.PathPrefix("/").Handler(http.HandlerFunc(pprof.Index))
+	if cfg.DebugEndpoints {
+		p := r.PathPrefix("/debug/pprof").Subrouter()
+		p.HandleFunc("/cmdline", pprof.Cmdline)
+		p.HandleFunc("/profile", pprof.Profile)
+		p.HandleFunc("/symbol", pprof.Symbol)
+		p.HandleFunc("/trace", pprof.Trace)
+		p.PathPrefix("/").Handler(http.HandlerFunc(pprof.Index))
+
+		// operational endpoints, they need the DEBUG_TOKEN
+		d := debugRouter(r)
+
+		// for operators to toggle the read-only mode
+		d.Handle("/readonly", readonly.Handler())
+
+		// for operators to triage background processing
+		d.Handle("/jobs", http.RedirectHandler("/debug/jobs/", http.StatusMovedPermanently))
+		d.PathPrefix("/jobs/").Handler(http.StripPrefix("/debug/jobs", jobs.Handler()))
+
+		// for attaching the state of the service to incident tickets
+		d.Handle("/support", support.Handler())
+
+		// for debugging individual slow requests
+		d.Handle("/traces", http.RedirectHandler("/debug/traces/", http.StatusMovedPermanently))
+		d.PathPrefix("/traces/").Handler(http.StripPrefix("/debug/traces", debugtrace.Handler()))
+	}
 
 	return r
 }
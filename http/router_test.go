@@ -76,3 +76,92 @@ func TestCustomRoutes(t *testing.T) {
 		t.Errorf("Expected first error to contain request ID, got: %#v", e.List[0])
 	}
 }
+
+func TestJobsDashboard(t *testing.T) {
+	mustSetup()
+	defer func(token string) { cfg.DebugToken = token }(cfg.DebugToken)
+	cfg.DebugToken = "secret"
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/debug/jobs/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	Router().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected /debug/jobs/ to respond with 200, got: %d", rec.Code)
+	}
+}
+
+func TestDebugAuth(t *testing.T) {
+	mustSetup()
+	defer func(enabled bool, token string) {
+		cfg.DebugEndpoints, cfg.DebugToken = enabled, token
+	}(cfg.DebugEndpoints, cfg.DebugToken)
+
+	cases := []struct {
+		enabled  bool
+		token    string
+		auth     string
+		expected int
+	}{
+		{true, "", "", http.StatusUnauthorized},
+		{true, "", "Bearer ", http.StatusUnauthorized},
+		{true, "secret", "", http.StatusUnauthorized},
+		{true, "secret", "Bearer wrong", http.StatusUnauthorized},
+		{true, "secret", "secret", http.StatusUnauthorized},
+		{true, "secret", "Bearer secret", http.StatusOK},
+		{false, "secret", "Bearer secret", http.StatusNotFound},
+	}
+	for _, path := range []string{"/debug/jobs/", "/debug/traces/"} {
+		for _, c := range cases {
+			cfg.DebugEndpoints, cfg.DebugToken = c.enabled, c.token
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", path, nil)
+			if c.auth != "" {
+				req.Header.Set("Authorization", c.auth)
+			}
+			Router().ServeHTTP(rec, req)
+
+			if rec.Code != c.expected {
+				t.Errorf("Expected %d for %s with DEBUG_ENDPOINTS=%v, DEBUG_TOKEN=%q and authorization %q, got: %d",
+					c.expected, path, c.enabled, c.token, c.auth, rec.Code)
+			}
+		}
+	}
+}
+
+func TestSupportBundle(t *testing.T) {
+	mustSetup()
+	defer func(token string) { cfg.DebugToken = token }(cfg.DebugToken)
+	cfg.DebugToken = "secret"
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/debug/support", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	Router().ServeHTTP(rec, req)
+
+	if rec.Code != 200 || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Errorf("Expected /debug/support to respond with a zip archive, got: %d", rec.Code)
+	}
+}
+
+func TestDebugEndpoints(t *testing.T) {
+	mustSetup()
+	defer func(enabled bool) { cfg.DebugEndpoints = enabled }(cfg.DebugEndpoints)
+
+	for _, enabled := range []bool{true, false} {
+		cfg.DebugEndpoints = enabled
+		rec := httptest.NewRecorder()
+		Router().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/pprof/", nil))
+
+		expected := http.StatusNotFound
+		if enabled {
+			expected = http.StatusOK
+		}
+		if rec.Code != expected {
+			t.Errorf("Expected /debug/pprof/ with DEBUG_ENDPOINTS=%v to respond with %d, got: %d", enabled, expected, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Input is the input document of the policy evaluation
+type Input struct {
+	Method string `json:"method"`
+	// Path of the request, split into segments
+	Path []string `json:"path"`
+	// Route is the name of the route, the path template if unnamed
+	Route    string            `json:"route"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	ClientID string            `json:"client_id,omitempty"`
+	UserID   string            `json:"user_id,omitempty"`
+	Scopes   []string          `json:"scopes,omitempty"`
+}
+
+// Evaluator evaluates the policy for the input and returns
+// true if the request is allowed
+type Evaluator interface {
+	Evaluate(ctx context.Context, input *Input) (bool, error)
+}
+
+// EvaluatorFunc allows to use a function as Evaluator, e.g. to
+// evaluate a bundled policy using a prepared rego query
+type EvaluatorFunc func(ctx context.Context, input *Input) (bool, error)
+
+// Evaluate calls f(ctx, input)
+func (f EvaluatorFunc) Evaluate(ctx context.Context, input *Input) (bool, error) {
+	return f(ctx, input)
+}
+
+// SidecarEvaluator evaluates the policy using the data API of
+// an OPA sidecar (POST /v1/data/<path>)
+type SidecarEvaluator struct {
+	URL    string // address of OPA, e.g. http://localhost:8181
+	Path   string // path of the decision, e.g. http/authz/allow
+	Client *http.Client
+}
+
+// sidecarRequest is the request of the data API
+type sidecarRequest struct {
+	Input *Input `json:"input"`
+}
+
+// sidecarResponse is the response of the data API, the result is
+// undefined if the decision doesn't exist
+type sidecarResponse struct {
+	Result *bool `json:"result"`
+}
+
+// Evaluate requests the decision from the sidecar, an undefined
+// decision is an error
+func (e *SidecarEvaluator) Evaluate(ctx context.Context, input *Input) (bool, error) {
+	body, err := json.Marshal(sidecarRequest{Input: input})
+	if err != nil {
+		return false, err
+	}
+
+	url := strings.TrimSuffix(e.URL, "/") + "/v1/data/" + strings.Trim(e.Path, "/")
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("opa responded with %d for %s", resp.StatusCode, e.Path)
+	}
+
+	var res sidecarResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return false, err
+	}
+	if res.Result == nil {
+		return false, fmt.Errorf("opa decision %s is undefined", e.Path)
+	}
+	return *res.Result, nil
+}
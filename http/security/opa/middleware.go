@@ -0,0 +1,197 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package opa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/pkg/reqcontext"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxCacheEntries limits the number of cached decisions
+const maxCacheEntries = 10000
+
+// Middleware authorizes requests using the decision of the evaluator,
+// it needs to be used after the oauth2 middleware to pass the identity
+// of the request to the policy
+type Middleware struct {
+	Evaluator Evaluator
+	// DryRun only logs denials and errors, the requests are processed
+	DryRun bool
+	// Headers that are passed in the input of the policy
+	Headers []string
+	// Timeout of the policy evaluation
+	Timeout time.Duration
+	// CacheTTL of decisions with the same input, 0 disables the cache
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cachedDecision
+}
+
+type cachedDecision struct {
+	allowed bool
+	expires time.Time
+}
+
+// NewMiddleware returns a middleware with environment based configuration
+// that evaluates the policy using the OPA sidecar
+func NewMiddleware() *Middleware {
+	mustSetup()
+	return &Middleware{
+		Evaluator: &SidecarEvaluator{URL: cfg.URL, Path: cfg.Path},
+		DryRun:    cfg.DryRun,
+		Headers:   cfg.Headers,
+		Timeout:   cfg.Timeout,
+		CacheTTL:  cfg.CacheTTL,
+	}
+}
+
+// Handler authorizes the request, denied requests are answered with
+// 403 Forbidden and requests that can't be evaluated with 502 Bad
+// Gateway. In dry-run mode all requests are processed.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		input := m.input(r)
+		allowed, err := m.decide(r.Context(), input)
+
+		result := decisionAllowed
+		switch {
+		case err != nil:
+			result = decisionError
+		case !allowed:
+			result = decisionDenied
+		}
+		paceOPADecisionTotal.With(prometheus.Labels{
+			"result":  result,
+			"dry_run": strconv.FormatBool(m.DryRun),
+		}).Inc()
+
+		if result != decisionAllowed {
+			le := log.Req(r).Info()
+			if err != nil {
+				le = log.Req(r).Warn().Err(err)
+			}
+			le.Str("route", input.Route).
+				Str("method", input.Method).
+				Str("client_id", input.ClientID).
+				Str("user_id", input.UserID).
+				Bool("dry_run", m.DryRun).
+				Msgf("OPA request %s", result)
+
+			if !m.DryRun {
+				if err != nil {
+					runtime.WriteError(w, http.StatusBadGateway, errors.New("authorization not available"))
+				} else {
+					runtime.WriteError(w, http.StatusForbidden, errors.New("Forbidden"))
+				}
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// input returns the input document of the request
+func (m *Middleware) input(r *http.Request) *Input {
+	ctx := r.Context()
+	input := &Input{
+		Method: r.Method,
+		Path:   strings.Split(strings.Trim(r.URL.Path, "/"), "/"),
+		Scopes: reqcontext.Scopes(ctx),
+	}
+	input.Route, _ = reqcontext.Route(ctx)
+	input.ClientID, _ = reqcontext.ClientID(ctx)
+	input.UserID, _ = reqcontext.UserID(ctx)
+
+	for _, h := range m.Headers {
+		if v := r.Header.Get(h); v != "" {
+			if input.Headers == nil {
+				input.Headers = make(map[string]string)
+			}
+			input.Headers[http.CanonicalHeaderKey(h)] = v
+		}
+	}
+	return input
+}
+
+// decide returns the cached decision for the input or evaluates it
+func (m *Middleware) decide(ctx context.Context, input *Input) (bool, error) {
+	var key [sha256.Size]byte
+	if m.CacheTTL > 0 {
+		b, err := json.Marshal(input)
+		if err != nil {
+			return false, err
+		}
+		key = sha256.Sum256(b)
+
+		if allowed, ok := m.cached(key); ok {
+			paceOPADecisionCacheTotal.With(prometheus.Labels{"result": "hit"}).Inc()
+			return allowed, nil
+		}
+		paceOPADecisionCacheTotal.With(prometheus.Labels{"result": "miss"}).Inc()
+	}
+
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	allowed, err := m.Evaluator.Evaluate(ctx, input)
+	paceOPADecisionDurationSeconds.Observe(time.Since(start).Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	if m.CacheTTL > 0 {
+		m.store(key, allowed)
+	}
+	return allowed, nil
+}
+
+func (m *Middleware) cached(key [sha256.Size]byte) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.cache[key]
+	if !ok || time.Now().After(d.expires) {
+		return false, false
+	}
+	return d.allowed, true
+}
+
+func (m *Middleware) store(key [sha256.Size]byte, allowed bool) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cache == nil {
+		m.cache = make(map[[sha256.Size]byte]cachedDecision)
+	}
+	// remove expired decisions, start over if all are valid
+	if len(m.cache) >= maxCacheEntries {
+		for k, d := range m.cache {
+			if now.After(d.expires) {
+				delete(m.cache, k)
+			}
+		}
+		if len(m.cache) >= maxCacheEntries {
+			m.cache = make(map[[sha256.Size]byte]cachedDecision)
+		}
+	}
+	m.cache[key] = cachedDecision{allowed: allowed, expires: now.Add(m.CacheTTL)}
+}
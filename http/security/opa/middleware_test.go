@@ -0,0 +1,110 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidecarEvaluator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/data/http/authz/allow", r.URL.Path)
+		var req sidecarRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if req.Input.Method == "DELETE" {
+			w.Write([]byte(`{}`)) // nolint: errcheck
+			return
+		}
+		w.Write([]byte(`{"result": ` + map[string]string{"GET": "true", "POST": "false"}[req.Input.Method] + `}`)) // nolint: errcheck
+	}))
+	defer srv.Close()
+
+	e := &SidecarEvaluator{URL: srv.URL, Path: "/http/authz/allow"}
+	ctx := context.Background()
+
+	allowed, err := e.Evaluate(ctx, &Input{Method: "GET"})
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = e.Evaluate(ctx, &Input{Method: "POST"})
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	// undefined decision
+	_, err = e.Evaluate(ctx, &Input{Method: "DELETE"})
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	evaluator := EvaluatorFunc(func(ctx context.Context, input *Input) (bool, error) {
+		calls++
+		switch input.Method {
+		case "GET":
+			return true, nil
+		case "POST":
+			return false, nil
+		}
+		return false, errors.New("failed")
+	})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		method   string
+		dryRun   bool
+		expected int
+	}{
+		{"GET", false, http.StatusNoContent},
+		{"POST", false, http.StatusForbidden},
+		{"PUT", false, http.StatusBadGateway},
+		{"POST", true, http.StatusNoContent},
+		{"PUT", true, http.StatusNoContent},
+	}
+	for _, c := range cases {
+		t.Run(c.method, func(t *testing.T) {
+			m := &Middleware{Evaluator: evaluator, DryRun: c.dryRun}
+			rec := httptest.NewRecorder()
+			m.Handler(next).ServeHTTP(rec, httptest.NewRequest(c.method, "/cars/1", nil))
+			assert.Equal(t, c.expected, rec.Code)
+		})
+	}
+
+	// decisions with the same input are cached
+	calls = 0
+	m := &Middleware{Evaluator: evaluator, CacheTTL: time.Minute, Headers: []string{"X-Tenant"}}
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/cars/1", nil)
+		req.Header.Set("X-Tenant", "a")
+		m.Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Equal(t, 1, calls)
+
+	req := httptest.NewRequest("GET", "/cars/1", nil)
+	req.Header.Set("X-Tenant", "b")
+	m.Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddlewareInput(t *testing.T) {
+	m := &Middleware{Headers: []string{"x-tenant"}}
+	req := httptest.NewRequest("GET", "/cars/1/", nil)
+	req.Header.Set("X-Tenant", "a")
+	req.Header.Set("Authorization", "Bearer secret")
+
+	input := m.input(req)
+	assert.Equal(t, []string{"cars", "1"}, input.Path)
+	assert.Equal(t, map[string]string{"X-Tenant": "a"}, input.Headers)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package opa provides a middleware that authorizes requests using
+// policies of the Open Policy Agent (OPA)
+package opa
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// URL of the OPA sidecar
+	URL string `env:"OPA_URL" envDefault:"http://localhost:8181"`
+	// Path of the decision, e.g. "http/authz/allow"
+	Path string `env:"OPA_POLICY_PATH" envDefault:"http/authz/allow"`
+	// Timeout of the policy evaluation
+	Timeout time.Duration `env:"OPA_TIMEOUT" envDefault:"1s"`
+	// DryRun only logs denials, the requests are processed
+	DryRun bool `env:"OPA_DRY_RUN"`
+	// CacheTTL of decisions with the same input, 0 disables the cache
+	CacheTTL time.Duration `env:"OPA_DECISION_CACHE_TTL" envDefault:"10s"`
+	// Headers that are passed in the input of the policy
+	Headers []string `env:"OPA_HEADERS" envSeparator:","`
+}
+
+// results of decisions, used as metric label
+const (
+	decisionAllowed = "allowed"
+	decisionDenied  = "denied"
+	decisionError   = "error"
+)
+
+var (
+	paceOPADecisionTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("opa_decision_total"),
+			Help: "Collects stats about the number of policy decisions partitioned by result",
+		},
+		[]string{"result", "dry_run"},
+	)
+	paceOPADecisionDurationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("opa_decision_duration_seconds"),
+			Help:    "Collect performance metrics of policy evaluations (cache misses)",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+	)
+	paceOPADecisionCacheTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("opa_decision_cache_total"),
+			Help: "Collects stats about the number of policy decision cache lookups partitioned by result",
+		},
+		[]string{"result"},
+	)
+)
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+func init() {
+	prometheus.MustRegister(paceOPADecisionTotal)
+	prometheus.MustRegister(paceOPADecisionDurationSeconds)
+	prometheus.MustRegister(paceOPADecisionCacheTotal)
+	configcheck.Register("opa", Setup)
+}
+
+// Setup parses the OPA_* environment of the middleware, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse opa environment: %v", err)
+	}
+}
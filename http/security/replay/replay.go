@@ -0,0 +1,164 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package replay rejects replayed requests. Requests carry a unique nonce
+// and the time they were created, the nonces are remembered in bloom
+// filters of pkg/probabilistic shared by the replicas.
+package replay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/pace/bricks/pkg/probabilistic"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of the checks, used as metric label
+const (
+	resultAccepted = "accepted"
+	resultReplayed = "replayed"
+	resultInvalid  = "invalid"
+	resultError    = "error"
+)
+
+var paceReplayRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("replay_requests_total"),
+		Help: "Collects stats about the number of checked requests partitioned by result",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceReplayRequestsTotal)
+}
+
+// Default headers of the nonce and the timestamp
+const (
+	DefaultNonceHeader     = "Request-Nonce"
+	DefaultTimestampHeader = "Request-Timestamp"
+)
+
+// filter remembers the nonces, implemented by probabilistic.BloomFilter
+type filter interface {
+	Add(ctx context.Context, item string) (bool, error)
+}
+
+// Middleware rejects requests whose nonce was used before
+type Middleware struct {
+	// NonceHeader contains the unique nonce of the request
+	NonceHeader string
+	// TimestampHeader contains the creation time of the
+	// request in unix seconds
+	TimestampHeader string
+	// Window is the maximum difference between the timestamp of a
+	// request and the time it is received, older requests are rejected.
+	// The nonces are remembered for three windows.
+	Window time.Duration
+
+	newFilter func(name string, ttl time.Duration) filter
+
+	mu      sync.Mutex
+	filters map[int64]filter
+}
+
+// NewMiddleware returns a middleware remembering the nonces in the bloom
+// filters name:<window> of client. Capacity and errorRate are the
+// parameters of the filter of a window (see probabilistic.NewBloomFilter),
+// a false positive rejects a request that wasn't replayed.
+func NewMiddleware(client *goredis.Client, name string, capacity int64, errorRate float64) *Middleware {
+	return &Middleware{
+		NonceHeader:     DefaultNonceHeader,
+		TimestampHeader: DefaultTimestampHeader,
+		Window:          5 * time.Minute,
+		newFilter: func(window string, ttl time.Duration) filter {
+			f := probabilistic.NewBloomFilter(client, name+":"+window, capacity, errorRate)
+			f.TTL = ttl
+			return f
+		},
+		filters: make(map[int64]filter),
+	}
+}
+
+// Handler rejects requests without nonce or timestamp and requests
+// outside of the window with 400 Bad Request, replayed requests with
+// 409 Conflict and requests that can't be checked with 503 Service
+// Unavailable. The headers need to be covered by the signature of the
+// request, otherwise the nonce of a replayed request can be replaced.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := m.check(r)
+		paceReplayRequestsTotal.With(prometheus.Labels{"result": result}).Inc()
+
+		switch result {
+		case resultInvalid:
+			runtime.WriteError(w, http.StatusBadRequest, err)
+			return
+		case resultReplayed:
+			log.Req(r).Info().Str("nonce", r.Header.Get(m.NonceHeader)).Msg("Replayed request rejected")
+			runtime.WriteError(w, http.StatusConflict, errors.New("request was replayed"))
+			return
+		case resultError:
+			log.Req(r).Warn().Err(err).Msg("Failed to check the request nonce")
+			runtime.WriteError(w, http.StatusServiceUnavailable, errors.New("replay protection not available"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// check adds the nonce of the request to the filter of the window of
+// the request timestamp, so replays always hit the same filter
+func (m *Middleware) check(r *http.Request) (string, error) {
+	nonce := r.Header.Get(m.NonceHeader)
+	if nonce == "" {
+		return resultInvalid, errors.New("missing header " + m.NonceHeader)
+	}
+	ts, err := strconv.ParseInt(r.Header.Get(m.TimestampHeader), 10, 64)
+	if err != nil {
+		return resultInvalid, errors.New("missing or malformed header " + m.TimestampHeader)
+	}
+	created := time.Unix(ts, 0)
+	if d := time.Since(created); d > m.Window || d < -m.Window {
+		return resultInvalid, errors.New("request expired")
+	}
+
+	added, err := m.filter(created).Add(r.Context(), nonce)
+	switch {
+	case err != nil:
+		return resultError, err
+	case !added:
+		return resultReplayed, nil
+	}
+	return resultAccepted, nil
+}
+
+// filter returns the filter of the window of t, the
+// filters of past windows are removed from the cache
+func (m *Middleware) filter(t time.Time) filter {
+	window := t.UnixNano() / int64(m.Window)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.filters[window]; ok {
+		return f
+	}
+	for w := range m.filters {
+		if w < window-2 {
+			delete(m.filters, w)
+		}
+	}
+	f := m.newFilter(strconv.FormatInt(window, 10), 3*m.Window)
+	m.filters[window] = f
+	return f
+}
@@ -0,0 +1,108 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/backend/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// testFilter is an exact in-memory filter
+type testFilter struct {
+	mu    sync.Mutex
+	items map[string]bool
+	err   error
+}
+
+func (f *testFilter) Add(ctx context.Context, item string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.items[item] {
+		return false, nil
+	}
+	f.items[item] = true
+	return true, nil
+}
+
+func request(nonce string, created time.Time) *http.Request {
+	r := httptest.NewRequest("POST", "/payments", nil)
+	if nonce != "" {
+		r.Header.Set(DefaultNonceHeader, nonce)
+	}
+	if !created.IsZero() {
+		r.Header.Set(DefaultTimestampHeader, strconv.FormatInt(created.Unix(), 10))
+	}
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	windows := make(map[string]*testFilter)
+	m := NewMiddleware(nil, "test", 1000, 0.001)
+	m.newFilter = func(name string, ttl time.Duration) filter {
+		assert.Equal(t, 3*m.Window, ttl)
+		f := &testFilter{items: make(map[string]bool)}
+		windows[name] = f
+		return f
+	}
+
+	calls := 0
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	serve := func(r *http.Request) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	now := time.Now()
+	assert.Equal(t, http.StatusOK, serve(request("a", now)))
+	assert.Equal(t, http.StatusOK, serve(request("b", now)))
+	assert.Equal(t, http.StatusConflict, serve(request("a", now)))
+	assert.Equal(t, 2, calls)
+	assert.Len(t, windows, 1)
+
+	// invalid requests
+	assert.Equal(t, http.StatusBadRequest, serve(request("", now)))
+	assert.Equal(t, http.StatusBadRequest, serve(request("c", time.Time{})))
+	assert.Equal(t, http.StatusBadRequest, serve(request("c", now.Add(-2*m.Window))))
+	assert.Equal(t, http.StatusBadRequest, serve(request("c", now.Add(2*m.Window))))
+	assert.Equal(t, 2, calls)
+
+	// requests that can't be checked are rejected
+	for _, f := range windows {
+		f.err = errors.New("redis unavailable")
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, serve(request("c", now)))
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddlewareRedis(t *testing.T) {
+	client := redis.Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	m := NewMiddleware(client, "TestMiddlewareRedis:"+time.Now().String(), 1000, 0.001)
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	now := time.Now()
+	for _, expected := range []int{http.StatusOK, http.StatusConflict} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, request("nonce", now))
+		assert.Equal(t, expected, rec.Code)
+	}
+}
@@ -7,14 +7,16 @@ import (
 	golog "log"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
 	"github.com/pace/bricks/maintenance/log"
 )
 
 func init() {
-	parseConfig()
+	configcheck.Register("http", Setup)
 }
 
 type config struct {
@@ -25,6 +27,12 @@ type config struct {
 	IdleTimeout    time.Duration `env:"IDLE_TIMEOUT" envDefault:"1h"`
 	ReadTimeout    time.Duration `env:"READ_TIMEOUT" envDefault:"60s"`
 	WriteTimeout   time.Duration `env:"WRITE_TIMEOUT" envDefault:"60s"`
+	DebugEndpoints bool          `env:"DEBUG_ENDPOINTS" envDefault:"true"`
+	// Bearer token of the operational endpoints under /debug
+	DebugToken string `env:"DEBUG_TOKEN"`
+	// Maximum duration Shutdown waits for requests and long-lived
+	// connections before they are closed
+	ShutdownGracePeriod time.Duration `env:"SHUTDOWN_GRACE_PERIOD" envDefault:"10s"`
 }
 
 // addrOrPort returns ADDR if it is defined, otherwise PORT is used
@@ -35,11 +43,23 @@ func (cfg config) addrOrPort() string {
 	return ":" + strconv.Itoa(cfg.Port)
 }
 
-var cfg config
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the environment of the http server (ADDR, PORT,
+// timeouts, debug endpoints and shutdown grace period), see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
 
-func parseConfig() {
-	err := env.Parse(&cfg)
-	if err != nil {
+func mustSetup() {
+	if err := Setup(); err != nil {
 		log.Fatalf("Failed to parse server environment: %v", err)
 	}
 }
@@ -47,6 +67,7 @@ func parseConfig() {
 // Server returns a http.Server configured using environment variables,
 // following https://12factor.net/.
 func Server(handler http.Handler) *http.Server {
+	mustSetup()
 	return &http.Server{
 		Addr:           cfg.addrOrPort(),
 		Handler:        handler,
@@ -60,5 +81,6 @@ func Server(handler http.Handler) *http.Server {
 
 // Environment returns the name of the current server environment
 func Environment() string {
+	mustSetup()
 	return cfg.Environment
 }
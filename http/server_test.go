@@ -7,8 +7,18 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/caarlos0/env"
 )
 
+// reparse parses the environment again, Setup only parses it once
+func reparse(t *testing.T) {
+	mustSetup()
+	if err := env.Parse(&cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestServer(t *testing.T) {
 	// Defaults
 	os.Setenv("ADDR", "")
@@ -17,7 +27,7 @@ func TestServer(t *testing.T) {
 	os.Setenv("IDLE_TIMEOUT", "")
 	os.Setenv("READ_TIMEOUT", "")
 	os.Setenv("WRITE_TIMEOUT", "")
-	parseConfig()
+	reparse(t)
 	s := Server(nil)
 	cases := []struct {
 		env              string
@@ -42,7 +52,7 @@ func TestServer(t *testing.T) {
 	os.Setenv("IDLE_TIMEOUT", "1s")
 	os.Setenv("READ_TIMEOUT", "2s")
 	os.Setenv("WRITE_TIMEOUT", "3s")
-	parseConfig()
+	reparse(t)
 	s = Server(nil)
 	cases = []struct {
 		env              string
@@ -64,14 +74,14 @@ func TestServer(t *testing.T) {
 func TestEnvironment(t *testing.T) {
 	// Defaults
 	os.Setenv("ENVIRONMENT", "")
-	parseConfig()
+	reparse(t)
 	if Environment() != "edge" {
 		t.Errorf("Expected edge, got: %q", Environment())
 	}
 
 	// custom
 	os.Setenv("ENVIRONMENT", "production")
-	parseConfig()
+	reparse(t)
 	if Environment() != "production" {
 		t.Errorf("Expected production, got: %q", Environment())
 	}
@@ -29,8 +29,18 @@ func (l *attemptRoundTripper) SetTransport(rt http.RoundTripper) {
 func (l *attemptRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	a := atomic.AddInt32(&l.attempt, 1)
 	ctx := context.WithValue(req.Context(), attemptKey, a)
+	r := req.WithContext(ctx)
+
+	// the body was consumed by the previous attempt
+	if a > 1 && req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
 
-	return l.Transport().RoundTrip(req.WithContext(ctx))
+	return l.Transport().RoundTrip(r)
 }
 
 func attemptFromCtx(ctx context.Context) int32 {
@@ -0,0 +1,281 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHTTPClientCircuitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("http_client_circuit_state"),
+			Help: "State of the circuit breaker of the host (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"host"},
+	)
+	paceHTTPClientCircuitRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_client_circuit_rejected_total"),
+			Help: "Collects stats about the number of requests rejected by the circuit breaker partitioned by host",
+		},
+		[]string{"host"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPClientCircuitState)
+	prometheus.MustRegister(paceHTTPClientCircuitRejectedTotal)
+}
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed passes the requests
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen passes a limited number of probe requests,
+	// whose results decide if the breaker is closed or opened again
+	BreakerHalfOpen
+	// BreakerOpen rejects the requests
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// CircuitOpenError is returned for requests rejected by the circuit breaker
+type CircuitOpenError struct {
+	Host  string
+	State BreakerState
+	// RetryAfter is the time until the breaker becomes half-open,
+	// 0 if it is half-open and all probes are in flight
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker of %s is %s", e.Host, e.State)
+}
+
+// IsCircuitOpen returns true if err was returned
+// for a request rejected by the circuit breaker
+func IsCircuitOpen(err error) bool {
+	_, ok := err.(*CircuitOpenError)
+	return ok
+}
+
+// CircuitBreakerRoundTripper implements a chainable round tripper with a
+// circuit breaker per host. After FailureThreshold consecutive failures
+// the breaker opens and rejects the requests of the host immediately with
+// a CircuitOpenError, so requests don't pile up waiting for a failing
+// upstream. After OpenTimeout the breaker becomes half-open and passes
+// HalfOpenProbes requests, it closes after SuccessThreshold successful
+// probes and opens again after a failed probe.
+//
+// Use it in front of the retry round tripper, so that retried requests
+// count once.
+type CircuitBreakerRoundTripper struct {
+	// FailureThreshold is the number of consecutive failures that open
+	// the breaker, defaults to 5
+	FailureThreshold int
+	// OpenTimeout is the duration the breaker stays open, defaults to 30s
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent probe requests of
+	// the half-open breaker, defaults to 1
+	HalfOpenProbes int
+	// SuccessThreshold is the number of successful probes that close
+	// the breaker, defaults to 1
+	SuccessThreshold int
+	// IsFailure classifies the results of the requests, defaults to
+	// errors (except canceled requests) and responses with a 5xx code
+	IsFailure func(req *http.Request, resp *http.Response, err error) bool
+
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// breaker is the circuit breaker of a host
+type breaker struct {
+	state     BreakerState
+	failures  int // consecutive failures while closed
+	successes int // successful probes while half-open
+	probes    int // probes in flight while half-open
+	openedAt  time.Time
+}
+
+// NewCircuitBreakerRoundTripper returns a circuit breaker that opens after
+// threshold consecutive failures of a host for openTimeout
+func NewCircuitBreakerRoundTripper(threshold int, openTimeout time.Duration) *CircuitBreakerRoundTripper {
+	return &CircuitBreakerRoundTripper{FailureThreshold: threshold, OpenTimeout: openTimeout}
+}
+
+// Transport returns the RoundTripper to make HTTP requests
+func (l *CircuitBreakerRoundTripper) Transport() http.RoundTripper {
+	return l.transport
+}
+
+// SetTransport sets the RoundTripper to make HTTP requests
+func (l *CircuitBreakerRoundTripper) SetTransport(rt http.RoundTripper) {
+	l.transport = rt
+}
+
+// RoundTrip executes a HTTP request if the breaker of the host allows it
+func (l *CircuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	b, probe, err := l.allow(req, host)
+	if err != nil {
+		paceHTTPClientCircuitRejectedTotal.With(prometheus.Labels{"host": host}).Inc()
+		return nil, err
+	}
+
+	resp, err := l.Transport().RoundTrip(req)
+
+	isFailure := l.IsFailure
+	if isFailure == nil {
+		isFailure = defaultIsFailure
+	}
+	l.done(req, host, b, probe, isFailure(req, resp, err))
+	return resp, err
+}
+
+// State returns the state of the breaker of the host
+func (l *CircuitBreakerRoundTripper) State(host string) BreakerState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.breakers[host]; ok {
+		return b.state
+	}
+	return BreakerClosed
+}
+
+// allow returns the breaker of the host if the request is allowed,
+// probe is true for the probe requests of half-open breakers
+func (l *CircuitBreakerRoundTripper) allow(req *http.Request, host string) (b *breaker, probe bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.breakers == nil {
+		l.breakers = make(map[string]*breaker)
+	}
+	b, ok := l.breakers[host]
+	if !ok {
+		b = &breaker{}
+		l.breakers[host] = b
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		elapsed := time.Since(b.openedAt)
+		if elapsed < l.openTimeout() {
+			return nil, false, &CircuitOpenError{Host: host, State: BreakerOpen, RetryAfter: l.openTimeout() - elapsed}
+		}
+		l.transition(req, host, b, BreakerHalfOpen)
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= l.halfOpenProbes() {
+			return nil, false, &CircuitOpenError{Host: host, State: BreakerHalfOpen}
+		}
+		b.probes++
+		return b, true, nil
+	}
+	return b, false, nil
+}
+
+// done records the result of an allowed request
+func (l *CircuitBreakerRoundTripper) done(req *http.Request, host string, b *breaker, probe, failure bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if probe {
+		b.probes--
+	}
+	switch b.state {
+	case BreakerClosed:
+		if !failure {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= l.failureThreshold() {
+			l.transition(req, host, b, BreakerOpen)
+		}
+	case BreakerHalfOpen:
+		if !probe {
+			return // started before the breaker opened
+		}
+		if failure {
+			l.transition(req, host, b, BreakerOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= l.successThreshold() {
+			l.transition(req, host, b, BreakerClosed)
+		}
+	}
+}
+
+func (l *CircuitBreakerRoundTripper) transition(req *http.Request, host string, b *breaker, state BreakerState) {
+	b.state = state
+	b.failures, b.successes = 0, 0
+	if state == BreakerOpen {
+		b.openedAt = time.Now()
+	}
+	paceHTTPClientCircuitState.With(prometheus.Labels{"host": host}).Set(float64(state))
+	log.Ctx(req.Context()).Warn().Str("host", host).Str("state", state.String()).Msg("Circuit breaker state changed")
+}
+
+func (l *CircuitBreakerRoundTripper) failureThreshold() int {
+	if l.FailureThreshold <= 0 {
+		return 5
+	}
+	return l.FailureThreshold
+}
+
+func (l *CircuitBreakerRoundTripper) halfOpenProbes() int {
+	if l.HalfOpenProbes <= 0 {
+		return 1
+	}
+	return l.HalfOpenProbes
+}
+
+func (l *CircuitBreakerRoundTripper) successThreshold() int {
+	if l.SuccessThreshold <= 0 {
+		return 1
+	}
+	return l.SuccessThreshold
+}
+
+func (l *CircuitBreakerRoundTripper) openTimeout() time.Duration {
+	if l.OpenTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return l.OpenTimeout
+}
+
+// defaultIsFailure treats errors and 5xx responses as failures, requests
+// canceled by the caller don't indicate a failing upstream
+func defaultIsFailure(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode >= 500
+}
@@ -0,0 +1,132 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type switchTransport struct {
+	code  int
+	err   error
+	calls int
+}
+
+func (t *switchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &http.Response{StatusCode: t.code}, nil
+}
+
+func TestCircuitBreakerRoundTripper(t *testing.T) {
+	rt := NewCircuitBreakerRoundTripper(3, 20*time.Millisecond)
+	tr := &switchTransport{code: 500}
+	rt.SetTransport(tr)
+	req := httptest.NewRequest("GET", "http://upstream.example.com/foo", nil)
+	host := "upstream.example.com"
+
+	// successes reset the consecutive failures
+	for _, code := range []int{500, 500, 200, 500, 500} {
+		tr.code = code
+		_, err := rt.RoundTrip(req)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, BreakerClosed, rt.State(host))
+
+	tr.err = errors.New("connection refused")
+	_, err := rt.RoundTrip(req)
+	assert.Equal(t, tr.err, err)
+	assert.Equal(t, BreakerOpen, rt.State(host))
+
+	// requests are rejected without calling the upstream
+	calls := tr.calls
+	_, err = rt.RoundTrip(req)
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, calls, tr.calls)
+	if coe, ok := err.(*CircuitOpenError); assert.True(t, ok) {
+		assert.Equal(t, host, coe.Host)
+		assert.Equal(t, BreakerOpen, coe.State)
+		assert.True(t, coe.RetryAfter > 0)
+	}
+
+	// other hosts are not affected
+	tr.err, tr.code = nil, 200
+	_, err = rt.RoundTrip(httptest.NewRequest("GET", "http://other.example.com/foo", nil))
+	assert.NoError(t, err)
+
+	// a failed probe opens the breaker again
+	time.Sleep(25 * time.Millisecond)
+	tr.code = 503
+	_, err = rt.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerOpen, rt.State(host))
+
+	// a successful probe closes it
+	time.Sleep(25 * time.Millisecond)
+	tr.code = 200
+	_, err = rt.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, rt.State(host))
+}
+
+type blockingTransport struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.started <- struct{}{}
+	<-t.release
+	return &http.Response{StatusCode: 200}, nil
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	rt := NewCircuitBreakerRoundTripper(1, 10*time.Millisecond)
+	rt.SetTransport(&switchTransport{err: errors.New("timeout")})
+	req := httptest.NewRequest("GET", "http://upstream.example.com/foo", nil)
+
+	_, err := rt.RoundTrip(req)
+	assert.False(t, IsCircuitOpen(err))
+	time.Sleep(15 * time.Millisecond)
+
+	bt := &blockingTransport{started: make(chan struct{}), release: make(chan struct{})}
+	rt.SetTransport(bt)
+	done := make(chan error)
+	go func() {
+		_, err := rt.RoundTrip(req)
+		done <- err
+	}()
+	<-bt.started
+
+	// only one probe is in flight
+	_, err = rt.RoundTrip(req)
+	if coe, ok := err.(*CircuitOpenError); assert.True(t, ok) {
+		assert.Equal(t, BreakerHalfOpen, coe.State)
+	}
+
+	close(bt.release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, BreakerClosed, rt.State("upstream.example.com"))
+}
+
+func TestCircuitBreakerCanceledRequests(t *testing.T) {
+	rt := NewCircuitBreakerRoundTripper(1, time.Minute)
+	rt.SetTransport(&switchTransport{err: context.Canceled})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := rt.RoundTrip(httptest.NewRequest("GET", "http://upstream.example.com/foo", nil).WithContext(ctx))
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, BreakerClosed, rt.State("upstream.example.com"))
+	assert.Equal(t, "half-open", BreakerHalfOpen.String())
+}
@@ -3,8 +3,11 @@
 
 package transport
 
-// NewDefaultTransportChain returns a transport chain with retry, jaeger and logging support.
+// NewDefaultTransportChain returns a transport chain with retry, metrics, jaeger,
+// request id and logging support. Only idempotent requests are retried, the
+// retries are limited by the DefaultRetryBudget (see NewRetryTransport).
 // If not explicitly finalized via `Final` it uses `http.DefaultTransport` as finalizer.
 func NewDefaultTransportChain() *RoundTripperChain {
-	return Chain(NewDefaultRetryRoundTripper(), &JaegerRoundTripper{}, &LoggingRoundTripper{})
+	retry := NewRetryRoundTripper(NewRetryTransport(RetryOptions{Budget: DefaultRetryBudget}))
+	return Chain(retry, &MetricsRoundTripper{}, &JaegerRoundTripper{}, &RequestIDRoundTripper{}, &LoggingRoundTripper{})
 }
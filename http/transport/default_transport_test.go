@@ -57,6 +57,37 @@ func TestNewDefaultTransportChain(t *testing.T) {
 	})
 }
 
+func TestNewDefaultTransportChainRetries(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	tr := NewDefaultTransportChain()
+
+	// idempotent requests are retried
+	resp, err := tr.RoundTrip(httptest.NewRequest("GET", ts.URL, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() // nolint: errcheck
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts of GET, got %d", calls)
+	}
+
+	// other requests are not
+	calls = 0
+	resp, err = tr.RoundTrip(httptest.NewRequest("POST", ts.URL, bytes.NewReader([]byte("{}"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() // nolint: errcheck
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt of POST, got %d", calls)
+	}
+}
+
 type transportWithBody struct {
 	// returned response as string
 	body string
@@ -0,0 +1,243 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+var hedgeKey = ctxkey(1)
+
+// hedgeWindow is the number of observed latencies used for the percentile
+const hedgeWindow = 1000
+
+// hedgeMinSamples is the number of latencies that need to be observed
+// before the percentile is used instead of the minimum delay
+const hedgeMinSamples = 20
+
+// HedgingRoundTripper implements a chainable round tripper that sends a
+// second (hedged) request if the first one didn't respond within the
+// configured percentile of the observed latencies. The response that
+// arrives first is used, the other request is canceled. This cuts the
+// tail latency of flaky upstreams at the cost of additional requests.
+//
+// Only idempotent requests are hedged: GET, HEAD, OPTIONS and TRACE, or
+// requests with an Idempotency-Key header. Requests with a body that
+// can't be replayed (see http.Request.GetBody) are never hedged.
+type HedgingRoundTripper struct {
+	// Percentile (0..1) of the observed latencies after which the
+	// hedged request is sent, e.g. 0.95
+	Percentile float64
+	// MinDelay before the hedged request is sent, used until enough
+	// latencies are observed
+	MinDelay time.Duration
+
+	transport http.RoundTripper
+
+	mu        sync.Mutex
+	latencies []time.Duration // ring buffer
+	next      int
+	delay     time.Duration // cached percentile
+	observed  int           // since the percentile was calculated
+}
+
+// NewHedgingRoundTripper returns a hedging round tripper that sends the
+// hedged request after the percentile of the latencies, but at least
+// after minDelay
+func NewHedgingRoundTripper(percentile float64, minDelay time.Duration) *HedgingRoundTripper {
+	return &HedgingRoundTripper{Percentile: percentile, MinDelay: minDelay}
+}
+
+// Transport returns the RoundTripper to make HTTP requests
+func (l *HedgingRoundTripper) Transport() http.RoundTripper {
+	return l.transport
+}
+
+// SetTransport sets the RoundTripper to make HTTP requests
+func (l *HedgingRoundTripper) SetTransport(rt http.RoundTripper) {
+	l.transport = rt
+}
+
+type hedgeResult struct {
+	hedge  bool
+	resp   *http.Response
+	err    error
+	cancel context.CancelFunc
+}
+
+// RoundTrip executes a HTTP request with hedging
+func (l *HedgingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !replayable(req) {
+		start := time.Now()
+		resp, err := l.Transport().RoundTrip(req)
+		if err == nil {
+			l.observe(time.Since(start))
+		}
+		return resp, err
+	}
+
+	ctx := req.Context()
+	results := make(chan hedgeResult, 2)
+	cancels := make(map[bool]context.CancelFunc, 2)
+	start := time.Now()
+	send := func(hedge bool) error {
+		r := req
+		if hedge && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			r = req.WithContext(ctx) // shallow copy
+			r.Body = body
+		}
+		actx, cancel := context.WithCancel(context.WithValue(ctx, hedgeKey, hedge))
+		cancels[hedge] = cancel
+		go func() {
+			resp, err := l.Transport().RoundTrip(r.WithContext(actx))
+			results <- hedgeResult{hedge: hedge, resp: resp, err: err, cancel: cancel}
+		}()
+		return nil
+	}
+
+	send(false) // nolint: errcheck
+	pending := 1
+
+	timer := time.NewTimer(l.hedgeDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if err := send(true); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("Hedged request not sent, body can't be replayed")
+				continue
+			}
+			pending++
+			log.Ctx(ctx).Debug().Str("url", req.URL.String()).Str("method", req.Method).
+				Float64("after", float64(time.Since(start))/float64(time.Millisecond)).
+				Msg("Sending hedged request")
+			if span := opentracing.SpanFromContext(ctx); span != nil {
+				span.LogFields(olog.String("event", "hedged request sent"))
+			}
+
+		case res := <-results:
+			pending--
+			if res.err != nil && pending > 0 {
+				// the other request may still succeed
+				res.cancel()
+				continue
+			}
+			if res.err != nil {
+				res.cancel()
+				return nil, res.err
+			}
+
+			l.observe(time.Since(start))
+			if pending > 0 {
+				cancels[!res.hedge]()
+				go drainLoser(results, pending)
+			}
+			if res.hedge {
+				log.Ctx(ctx).Debug().Str("url", req.URL.String()).Str("method", req.Method).
+					Msg("Hedged request won")
+				if span := opentracing.SpanFromContext(ctx); span != nil {
+					span.LogFields(olog.String("event", "hedged request won"))
+				}
+			}
+
+			// the request is canceled once the body is closed
+			res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: res.cancel}
+			return res.resp, nil
+		}
+	}
+}
+
+// drainLoser closes the responses of the canceled requests
+func drainLoser(results <-chan hedgeResult, pending int) {
+	for i := 0; i < pending; i++ {
+		res := <-results
+		if res.err == nil {
+			res.resp.Body.Close() // nolint: errcheck,gosec
+		}
+	}
+}
+
+// cancelBody cancels the context of the request when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// replayable returns true if the request is idempotent and can be replayed
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+	default:
+		if req.Header.Get("Idempotency-Key") == "" {
+			return false
+		}
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// hedgeDelay returns the delay after which the hedged request is sent
+func (l *HedgingRoundTripper) hedgeDelay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.latencies) < hedgeMinSamples {
+		return l.MinDelay
+	}
+	// recalculate the percentile regularly instead of per request
+	if l.delay == 0 || l.observed >= hedgeMinSamples {
+		sorted := make([]time.Duration, len(l.latencies))
+		copy(sorted, l.latencies)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		i := int(l.Percentile * float64(len(sorted)))
+		if i >= len(sorted) {
+			i = len(sorted) - 1
+		}
+		l.delay = sorted[i]
+		l.observed = 0
+	}
+	if l.delay < l.MinDelay {
+		return l.MinDelay
+	}
+	return l.delay
+}
+
+// observe adds the latency of a successful request
+func (l *HedgingRoundTripper) observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.latencies) < hedgeWindow {
+		l.latencies = append(l.latencies, d)
+	} else {
+		l.latencies[l.next] = d
+		l.next = (l.next + 1) % hedgeWindow
+	}
+	l.observed++
+}
+
+// hedgedFromCtx returns true if the request is the hedged request
+func hedgedFromCtx(ctx context.Context) bool {
+	hedge, _ := ctx.Value(hedgeKey).(bool)
+	return hedge
+}
@@ -0,0 +1,150 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowTransport responds to the first request after delay and
+// immediately to all other requests
+type slowTransport struct {
+	delay time.Duration
+
+	mu       sync.Mutex
+	requests int
+	hedged   int
+	canceled int
+}
+
+func (t *slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	n := t.requests
+	if hedgedFromCtx(req.Context()) {
+		t.hedged++
+	}
+	t.mu.Unlock()
+
+	if n == 1 {
+		select {
+		case <-time.After(t.delay):
+		case <-req.Context().Done():
+			t.mu.Lock()
+			t.canceled++
+			t.mu.Unlock()
+			return nil, req.Context().Err()
+		}
+	}
+
+	body := "primary"
+	if hedgedFromCtx(req.Context()) {
+		body = "hedge"
+	}
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func TestHedgingRoundTripper(t *testing.T) {
+	t.Run("hedged request wins", func(t *testing.T) {
+		tr := &slowTransport{delay: time.Second}
+		rt := NewHedgingRoundTripper(0.95, 10*time.Millisecond)
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body) // nolint: errcheck
+		resp.Body.Close()                    // nolint: errcheck
+		if string(body) != "hedge" {
+			t.Errorf("Expected hedged response, got %q", body)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		if tr.requests != 2 || tr.hedged != 1 {
+			t.Errorf("Expected 2 requests (1 hedged), got %d (%d hedged)", tr.requests, tr.hedged)
+		}
+		if tr.canceled != 1 {
+			t.Errorf("Expected the slow request to be canceled, got %d", tr.canceled)
+		}
+	})
+
+	t.Run("fast response is not hedged", func(t *testing.T) {
+		tr := &slowTransport{delay: 0}
+		rt := NewHedgingRoundTripper(0.95, 100*time.Millisecond)
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() // nolint: errcheck
+		if tr.requests != 1 {
+			t.Errorf("Expected 1 request, got %d", tr.requests)
+		}
+	})
+
+	t.Run("non idempotent requests are not hedged", func(t *testing.T) {
+		tr := &slowTransport{delay: 50 * time.Millisecond}
+		rt := NewHedgingRoundTripper(0.95, 10*time.Millisecond)
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("POST", "/foo", bytes.NewBufferString("{}")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() // nolint: errcheck
+		if tr.requests != 1 {
+			t.Errorf("Expected 1 request, got %d", tr.requests)
+		}
+	})
+}
+
+func TestReplayable(t *testing.T) {
+	post := httptest.NewRequest("POST", "/foo", nil)
+	post.Header.Set("Idempotency-Key", "abc")
+
+	// GetBody is only set by http.NewRequest
+	put, _ := http.NewRequest("PUT", "/foo", bytes.NewBufferString("{}")) // nolint: errcheck
+	put.Header.Set("Idempotency-Key", "abc")
+	putNoReplay := httptest.NewRequest("PUT", "/foo", bytes.NewBufferString("{}"))
+	putNoReplay.Header.Set("Idempotency-Key", "abc")
+
+	cases := map[string]struct {
+		req      *http.Request
+		expected bool
+	}{
+		"GET":                   {httptest.NewRequest("GET", "/foo", nil), true},
+		"POST":                  {httptest.NewRequest("POST", "/foo", nil), false},
+		"POST idempotency key":  {post, true},
+		"PUT replayable body":   {put, true},
+		"PUT unreplayable body": {putNoReplay, false},
+	}
+	for name, c := range cases {
+		if got := replayable(c.req); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", name, c.expected, got)
+		}
+	}
+}
+
+func TestHedgeDelay(t *testing.T) {
+	rt := NewHedgingRoundTripper(0.9, 5*time.Millisecond)
+	if d := rt.hedgeDelay(); d != 5*time.Millisecond {
+		t.Errorf("Expected min delay without samples, got %v", d)
+	}
+	for i := 1; i <= 100; i++ {
+		rt.observe(time.Duration(i) * time.Millisecond)
+	}
+	if d := rt.hedgeDelay(); d != 91*time.Millisecond {
+		t.Errorf("Expected p90 of 91ms, got %v", d)
+	}
+}
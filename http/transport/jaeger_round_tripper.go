@@ -8,7 +8,9 @@ import (
 	"net/http"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	olog "github.com/opentracing/opentracing-go/log"
+	"github.com/pace/bricks/http/oauth2"
 )
 
 // JaegerRoundTripper implements a chainable round tripper for tracing
@@ -31,13 +33,34 @@ func (l *JaegerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	operationName := fmt.Sprintf("%s %s", req.Method, req.URL.Path)
 	span, ctx := opentracing.StartSpanFromContext(req.Context(), operationName)
 	defer span.Finish()
+	ext.SpanKindRPCClient.Set(span)
 
-	resp, err := l.Transport().RoundTrip(req.WithContext(ctx))
+	// propagate the span context to the upstream, the header
+	// is copied as round trippers must not modify the request
+	req = req.WithContext(ctx)
+	req.Header = cloneHeader(req.Header)
+	err := span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	if err != nil {
+		span.LogFields(olog.Error(err))
+	}
+
+	// add authenticated client
+	if clientID, userID, ok := oauth2.Identity(ctx); ok {
+		span.LogFields(olog.String("client_id", clientID))
+		if userID != "" {
+			span.LogFields(olog.String("user_id", userID))
+		}
+	}
+
+	resp, err := l.Transport().RoundTrip(req)
 
 	attempt := attemptFromCtx(ctx)
 	if attempt > 0 {
 		span.LogFields(olog.Int("attempt", int(attempt)))
 	}
+	if hedgedFromCtx(ctx) {
+		span.LogFields(olog.Bool("hedged", true))
+	}
 	if err != nil {
 		span.LogFields(olog.Error(err))
 		return nil, err
@@ -4,6 +4,7 @@
 package transport
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/http/oauth2"
 	_ "github.com/pace/bricks/maintenance/tracing"
 )
 
@@ -40,6 +42,24 @@ func TestJaegerRoundTripper(t *testing.T) {
 			}
 		}
 	})
+	t.Run("With propagated span context", func(t *testing.T) {
+		l := &JaegerRoundTripper{}
+		tr := &recordingTransportWithResponse{statusCode: 200}
+		l.SetTransport(tr)
+
+		req := httptest.NewRequest("GET", "/foo", nil)
+		_, err := l.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Expected err to be nil, got %#v", err)
+		}
+
+		if tr.req.Header.Get("Uber-Trace-Id") == "" {
+			t.Errorf("Expected span context to be injected, got header %#v", tr.req.Header)
+		}
+		if v := req.Header.Get("Uber-Trace-Id"); v != "" {
+			t.Errorf("Expected original request not to be modified, got %q", v)
+		}
+	})
 	t.Run("With error response", func(t *testing.T) {
 		l := &JaegerRoundTripper{}
 		e := errors.New("some error")
@@ -61,6 +81,34 @@ func TestJaegerRoundTripper(t *testing.T) {
 			}
 		}
 	})
+	t.Run("With authenticated client", func(t *testing.T) {
+		l := &JaegerRoundTripper{}
+		tr := &recordingTransportWithResponse{statusCode: 200}
+		l.SetTransport(tr)
+
+		var req *http.Request
+		m := oauth2.NewMiddleware(&staticTokenIntrospecter{
+			resp: &oauth2.IntrospectResponse{Active: true, ClientID: "someclient", UserID: "someuser"},
+		})
+		m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = httptest.NewRequest("GET", "/baz", nil).WithContext(r.Context())
+		})).ServeHTTP(httptest.NewRecorder(), &http.Request{
+			Header: http.Header{"Authorization": []string{"Bearer sometoken"}},
+		})
+
+		_, err := l.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Expected err to be nil, got %#v", err)
+		}
+
+		spanString := fmt.Sprintf("%#v", tr.span)
+		exs := []string{`stringVal:"someclient"`, `stringVal:"someuser"`}
+		for _, ex := range exs {
+			if !strings.Contains(spanString, ex) {
+				t.Errorf("Expected %q to be included in span %v", ex, spanString)
+			}
+		}
+	})
 	t.Run("With retries", func(t *testing.T) {
 		tr := &retriedTransport{body: "", statusCodes: []int{502, 503, 200}}
 		l := Chain(NewDefaultRetryRoundTripper(), &JaegerRoundTripper{})
@@ -84,13 +132,23 @@ func TestJaegerRoundTripper(t *testing.T) {
 	})
 }
 
+type staticTokenIntrospecter struct {
+	resp *oauth2.IntrospectResponse
+}
+
+func (t *staticTokenIntrospecter) IntrospectToken(ctx context.Context, token string) (*oauth2.IntrospectResponse, error) {
+	return t.resp, nil
+}
+
 type recordingTransportWithResponse struct {
 	span       opentracing.Span
+	req        *http.Request
 	statusCode int
 }
 
 func (t *recordingTransportWithResponse) RoundTrip(req *http.Request) (*http.Response, error) {
 	t.span = opentracing.SpanFromContext(req.Context())
+	t.req = req
 	resp := &http.Response{StatusCode: t.statusCode}
 
 	return resp, nil
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pace/bricks/maintenance/debugtrace"
 	"github.com/pace/bricks/maintenance/log"
 )
 
@@ -37,12 +38,17 @@ func (l *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 
 	resp, err := l.Transport().RoundTrip(req)
 
-	dur := float64(time.Since(startTime)) / float64(time.Millisecond)
+	elapsed := time.Since(startTime)
+	debugtrace.Record(ctx, "http", logEventMsg(req), startTime, elapsed, err)
+	dur := float64(elapsed) / float64(time.Millisecond)
 	le = le.Float64("duration", dur)
 	attempt := attemptFromCtx(ctx)
 	if attempt > 0 {
 		le = le.Int("attempt", int(attempt))
 	}
+	if hedgedFromCtx(ctx) {
+		le = le.Bool("hedged", true)
+	}
 
 	if err != nil {
 		le.Err(err).Msg(logEventMsg(req))
@@ -0,0 +1,92 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHTTPClientRequestTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_client_request_total"),
+			Help: "Collects stats about the number of outbound HTTP requests partitioned by host, route and code",
+		},
+		[]string{"host", "route", "code"},
+	)
+	paceHTTPClientRequestDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("http_client_request_duration_seconds"),
+			Help:    "Collect performance metrics for each outbound HTTP request",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 60},
+		},
+		[]string{"host", "route"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPClientRequestTotal)
+	prometheus.MustRegister(paceHTTPClientRequestDurationSeconds)
+}
+
+// routeKey is the context key of the route
+var routeKey = ctxkey(2)
+
+// unknownRoute is the route label of requests without route
+const unknownRoute = "unknown"
+
+// WithRoute returns ctx with the route of outbound requests, it is used as
+// metric label (e.g. the operation id "GetUser"). Paths can't be used as
+// labels, as they contain ids.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+func routeFromCtx(ctx context.Context) string {
+	route, ok := ctx.Value(routeKey).(string)
+	if !ok || route == "" {
+		return unknownRoute
+	}
+	return route
+}
+
+// MetricsRoundTripper implements a chainable round tripper for collecting
+// metrics of the requests by host and route (see WithRoute)
+type MetricsRoundTripper struct {
+	transport http.RoundTripper
+}
+
+// Transport returns the RoundTripper to make HTTP requests
+func (l *MetricsRoundTripper) Transport() http.RoundTripper {
+	return l.transport
+}
+
+// SetTransport sets the RoundTripper to make HTTP requests
+func (l *MetricsRoundTripper) SetTransport(rt http.RoundTripper) {
+	l.transport = rt
+}
+
+// RoundTrip executes a HTTP request and collects its metrics, requests
+// that failed without response are counted with code "error"
+func (l *MetricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	labels := prometheus.Labels{"host": req.URL.Host, "route": routeFromCtx(req.Context())}
+	start := time.Now()
+
+	resp, err := l.Transport().RoundTrip(req)
+
+	paceHTTPClientRequestDurationSeconds.With(labels).Observe(time.Since(start).Seconds())
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	paceHTTPClientRequestTotal.With(prometheus.Labels{"host": labels["host"], "route": labels["route"], "code": code}).Inc()
+
+	return resp, err
+}
@@ -0,0 +1,42 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func requestTotal(t *testing.T, host, route, code string) float64 {
+	var m dto.Metric
+	err := paceHTTPClientRequestTotal.With(prometheus.Labels{"host": host, "route": route, "code": code}).Write(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestMetricsRoundTripper(t *testing.T) {
+	rt := &MetricsRoundTripper{}
+	rt.SetTransport(&transportWithResponse{statusCode: 404})
+
+	req := httptest.NewRequest("GET", "http://users.example.com/users/1", nil)
+	_, err := rt.RoundTrip(req.WithContext(WithRoute(req.Context(), "GetUser")))
+	assert.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, requestTotal(t, "users.example.com", "GetUser", "404"))
+	assert.Equal(t, 1.0, requestTotal(t, "users.example.com", "unknown", "404"))
+
+	e := errors.New("connection refused")
+	rt.SetTransport(&recordingTransportWithError{err: e})
+	_, err = rt.RoundTrip(req)
+	assert.Equal(t, e, err)
+	assert.Equal(t, 1.0, requestTotal(t, "users.example.com", "unknown", "error"))
+}
@@ -0,0 +1,47 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"net/http"
+
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// RequestIDRoundTripper implements a chainable round tripper for propagating
+// the id of the incoming request using the Request-Id header, so the logs of
+// the upstream can be correlated
+type RequestIDRoundTripper struct {
+	transport http.RoundTripper
+}
+
+// Transport returns the RoundTripper to make HTTP requests
+func (l *RequestIDRoundTripper) Transport() http.RoundTripper {
+	return l.transport
+}
+
+// SetTransport sets the RoundTripper to make HTTP requests
+func (l *RequestIDRoundTripper) SetTransport(rt http.RoundTripper) {
+	l.transport = rt
+}
+
+// RoundTrip executes a HTTP request with the Request-Id header of the
+// request id in the context, an existing header is kept
+func (l *RequestIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := log.RequestIDFromContext(req.Context()); id != "" && req.Header.Get("Request-Id") == "" {
+		req = req.WithContext(req.Context())
+		req.Header = cloneHeader(req.Header)
+		req.Header.Set("Request-Id", id)
+	}
+	return l.Transport().RoundTrip(req)
+}
+
+// cloneHeader returns a copy of h
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
@@ -0,0 +1,43 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDRoundTripper(t *testing.T) {
+	var id string
+	var outbound, existing *http.Request
+	log.Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id = log.RequestID(r)
+		outbound = httptest.NewRequest("GET", "/foo", nil).WithContext(r.Context())
+		existing = httptest.NewRequest("GET", "/foo", nil).WithContext(r.Context())
+		existing.Header.Set("Request-Id", "upstream")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	tr := &recordingTransport{}
+	rt := &RequestIDRoundTripper{}
+	rt.SetTransport(tr)
+
+	_, err := rt.RoundTrip(outbound)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, tr.req.Header.Get("Request-Id"))
+	assert.Empty(t, outbound.Header.Get("Request-Id"))
+
+	_, err = rt.RoundTrip(existing)
+	assert.NoError(t, err)
+	assert.Equal(t, "upstream", tr.req.Header.Get("Request-Id"))
+
+	// requests without request id
+	_, err = rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+	assert.NoError(t, err)
+	assert.Empty(t, tr.req.Header.Get("Request-Id"))
+}
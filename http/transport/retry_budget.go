@@ -0,0 +1,100 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHTTPClientRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_client_retries_total"),
+			Help: "Collects stats about the number of retried outbound HTTP requests partitioned by host",
+		},
+		[]string{"host"},
+	)
+	paceHTTPClientRetryBudgetExhaustedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("http_client_retry_budget_exhausted_total"),
+			Help: "Collects stats about the number of retries denied by the retry budget partitioned by host",
+		},
+		[]string{"host"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHTTPClientRetriesTotal)
+	prometheus.MustRegister(paceHTTPClientRetryBudgetExhaustedTotal)
+}
+
+// retryBudgetMinTokens is the minimum number of
+// retries a budget can save up
+const retryBudgetMinTokens = 100
+
+// DefaultRetryBudget is shared by the transports of NewRetryTransport, it
+// allows retries of 10% of the requests plus 10 retries per second
+var DefaultRetryBudget = NewRetryBudget(0.1, 10)
+
+// RetryBudget limits the retries in relation to the requests, so that
+// retries don't multiply the load of an overloaded upstream (retry storm).
+// Every request deposits Ratio tokens, every retry withdraws one. The
+// budget is safe for concurrent use and may be shared by many transports.
+type RetryBudget struct {
+	ratio        float64
+	minPerSecond float64
+	max          float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRetryBudget returns a budget that allows retries of ratio (e.g. 0.1)
+// of the requests, in addition minPerSecond retries are always allowed so
+// that services with few requests can retry
+func NewRetryBudget(ratio, minPerSecond float64) *RetryBudget {
+	max := math.Max(retryBudgetMinTokens, minPerSecond)
+	return &RetryBudget{
+		ratio:        ratio,
+		minPerSecond: minPerSecond,
+		max:          max,
+		tokens:       minPerSecond,
+		last:         time.Now(),
+	}
+}
+
+// deposit is called for every request
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	b.tokens = math.Min(b.max, b.tokens+b.ratio)
+}
+
+// withdraw returns true if a retry is allowed
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill adds the tokens of the minimum retries per second
+func (b *RetryBudget) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	if elapsed > 0 {
+		b.tokens = math.Min(b.max, b.tokens+elapsed*b.minPerSecond)
+	}
+}
@@ -4,12 +4,24 @@
 package transport
 
 import (
+	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/pace/bricks/maintenance/deprecation"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/streadway/handy/retry"
 )
 
+func init() {
+	deprecation.Register(deprecation.API{
+		Name:        "transport.DefaultRetryTransport",
+		Replacement: "use NewDefaultRetryTransport()",
+	})
+}
+
 // RetryRoundTripper implements a chainable round tripper for retrying requests
 type RetryRoundTripper struct {
 	RetryTransport *retry.Transport
@@ -79,6 +91,132 @@ func NewDefaultRetryRoundTripper() *RetryRoundTripper {
 	return &RetryRoundTripper{RetryTransport: NewDefaultRetryTransport()}
 }
 
+// RetryOptions configure the retries of NewRetryTransport
+type RetryOptions struct {
+	// MaxAttempts of a request including the first one, defaults to 3
+	MaxAttempts uint
+	// Backoff is the maximum delay before the first retry, it is doubled
+	// for every further retry. The delay is chosen randomly up to the
+	// backoff (full jitter). Defaults to 100ms.
+	Backoff time.Duration
+	// MaxBackoff limits the delays, defaults to 5s. Responses with a
+	// Retry-After header exceeding it are not retried.
+	MaxBackoff time.Duration
+	// Codes of the responses that are retried, defaults
+	// to 408, 429, 502, 503 and 504
+	Codes []int
+	// Budget limits the retries, defaults to DefaultRetryBudget
+	Budget *RetryBudget
+}
+
+// NewRetryTransport returns a retry transport with exponential backoff and
+// jitter that retries idempotent requests (GET, HEAD, OPTIONS, TRACE or
+// requests with an Idempotency-Key header) whose body can be replayed. The
+// Retry-After header of the responses is respected. After the last attempt
+// the response or error of the upstream is returned.
+func NewRetryTransport(opts RetryOptions) *retry.Transport {
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Second
+	}
+	if len(opts.Codes) == 0 {
+		opts.Codes = []int{408, 429, 502, 503, 504}
+	}
+	if opts.Budget == nil {
+		opts.Budget = DefaultRetryBudget
+	}
+	return &retry.Transport{
+		Delay: ExponentialBackoff(opts.Backoff, opts.MaxBackoff),
+		Retry: opts.retryer(),
+	}
+}
+
+func (opts RetryOptions) retryer() retry.Retryer {
+	conditions := retry.All(Context(), retry.EOF(), retry.Net(), retry.Temporary(), RetryCodes(opts.Codes...))
+	return func(a retry.Attempt) (retry.Decision, error) {
+		if a.Count == 1 {
+			opts.Budget.deposit()
+		}
+		if a.Count >= opts.MaxAttempts || !replayable(a.Request) {
+			return retry.Ignore, nil
+		}
+		if d, ok := retryAfter(a.Response, time.Now()); ok && d > opts.MaxBackoff {
+			return retry.Ignore, nil
+		}
+
+		decision, err := conditions(a)
+		if decision != retry.Retry {
+			return decision, err
+		}
+		labels := prometheus.Labels{"host": a.Request.URL.Host}
+		if !opts.Budget.withdraw() {
+			paceHTTPClientRetryBudgetExhaustedTotal.With(labels).Inc()
+			log.Ctx(a.Request.Context()).Debug().Str("host", a.Request.URL.Host).Msg("Retry budget exhausted")
+			return retry.Ignore, nil
+		}
+		paceHTTPClientRetriesTotal.With(labels).Inc()
+		return retry.Retry, nil
+	}
+}
+
+// ExponentialBackoff returns a delayer that waits up to base for the first
+// retry and doubles it for every further retry up to max, the delay is
+// chosen randomly (full jitter). The delay of the Retry-After header of the
+// response is used instead, but at most max. The delayer returns early if
+// the context of the request is done.
+func ExponentialBackoff(base, max time.Duration) retry.Delayer {
+	return func(a retry.Attempt) {
+		t := time.NewTimer(backoff(a, base, max, time.Now()))
+		defer t.Stop()
+		select {
+		case <-a.Request.Context().Done():
+		case <-t.C:
+		}
+	}
+}
+
+func backoff(a retry.Attempt, base, max time.Duration, now time.Time) time.Duration {
+	if d, ok := retryAfter(a.Response, now); ok {
+		if d > max {
+			return max
+		}
+		return d
+	}
+
+	d := max
+	if a.Count < 32 && base<<(a.Count-1) < max {
+		d = base << (a.Count - 1)
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// retryAfter returns the delay of the Retry-After header
+// of resp, which contains seconds or a HTTP date
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
 // Transport returns the RoundTripper to make HTTP requests
 func (l *RetryRoundTripper) Transport() http.RoundTripper {
 	return l.transport
@@ -91,6 +229,9 @@ func (l *RetryRoundTripper) SetTransport(rt http.RoundTripper) {
 
 // RoundTrip executes a HTTP request with retrying
 func (l *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l.RetryTransport == &DefaultRetryTransport {
+		deprecation.Use(req.Context(), "transport.DefaultRetryTransport")
+	}
 	l.RetryTransport.Next = transportWithAttempt(l.Transport())
 	resp, err := l.RetryTransport.RoundTrip(req)
 
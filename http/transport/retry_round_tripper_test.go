@@ -11,6 +11,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/pace/bricks/maintenance/deprecation"
+	"github.com/streadway/handy/retry"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRetryRoundTripper(t *testing.T) {
@@ -86,6 +91,147 @@ func TestRetryRoundTripper(t *testing.T) {
 	})
 }
 
+func TestRetryTransport(t *testing.T) {
+	opts := RetryOptions{Backoff: time.Millisecond, Budget: NewRetryBudget(0.1, 100)}
+
+	t.Run("Idempotent request", func(t *testing.T) {
+		rt := NewRetryRoundTripper(NewRetryTransport(opts))
+		tr := &sequenceTransport{codes: []int{503, 502, 200}}
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, 3, len(tr.bodies))
+	})
+	t.Run("Last response after max attempts", func(t *testing.T) {
+		rt := NewRetryRoundTripper(NewRetryTransport(opts))
+		tr := &sequenceTransport{codes: []int{503, 503, 503, 200}}
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+		assert.Equal(t, 3, len(tr.bodies))
+	})
+	t.Run("No retry of non-idempotent request", func(t *testing.T) {
+		rt := NewRetryRoundTripper(NewRetryTransport(opts))
+		tr := &sequenceTransport{codes: []int{503, 200}}
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("POST", "/foo", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+		assert.Equal(t, 1, len(tr.bodies))
+	})
+	t.Run("Body is replayed", func(t *testing.T) {
+		rt := NewRetryRoundTripper(NewRetryTransport(opts))
+		tr := &sequenceTransport{codes: []int{502, 200}}
+		rt.SetTransport(tr)
+
+		req, _ := http.NewRequest("PUT", "/foo", bytes.NewBufferString("{}")) // nolint: errcheck
+		req.Header.Set("Idempotency-Key", "abc")
+		resp, err := rt.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, []string{"{}", "{}"}, tr.bodies)
+	})
+	t.Run("Retry-After exceeding the max backoff", func(t *testing.T) {
+		rt := NewRetryRoundTripper(NewRetryTransport(opts))
+		tr := &sequenceTransport{codes: []int{429, 200}, retryAfter: "3600"}
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/foo", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Equal(t, 1, len(tr.bodies))
+	})
+	t.Run("Exhausted budget", func(t *testing.T) {
+		o := opts
+		o.Budget = NewRetryBudget(0, 0)
+		rt := NewRetryRoundTripper(NewRetryTransport(o))
+		tr := &sequenceTransport{codes: []int{503, 200}}
+		rt.SetTransport(tr)
+
+		resp, err := rt.RoundTrip(httptest.NewRequest("GET", "http://budget.example.com/foo", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 503, resp.StatusCode)
+		assert.Equal(t, 1, len(tr.bodies))
+	})
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := NewRetryBudget(0.5, 0)
+	assert.False(t, b.withdraw())
+	b.deposit()
+	b.deposit()
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+
+	// the minimum retries are refilled over time
+	b = NewRetryBudget(0, 2)
+	assert.True(t, b.withdraw())
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+	b.refill(b.last.Add(time.Second))
+	assert.True(t, b.withdraw())
+}
+
+func TestBackoff(t *testing.T) {
+	now := time.Now()
+	for count := uint(1); count < 40; count++ {
+		d := backoff(retry.Attempt{Count: count}, 100*time.Millisecond, time.Second, now)
+		assert.True(t, d >= 0 && d <= time.Second, "%d: %v", count, d)
+		if count == 1 {
+			assert.True(t, d <= 100*time.Millisecond)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	assert.Equal(t, 2*time.Second, backoff(retry.Attempt{Count: 1, Response: resp}, time.Millisecond, time.Minute, now))
+	assert.Equal(t, time.Second, backoff(retry.Attempt{Count: 1, Response: resp}, time.Millisecond, time.Second, now))
+
+	resp.Header.Set("Retry-After", now.Add(time.Minute).UTC().Format(http.TimeFormat))
+	d, ok := retryAfter(resp, now)
+	assert.True(t, ok)
+	assert.InDelta(t, float64(time.Minute), float64(d), float64(time.Second))
+
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = retryAfter(resp, now)
+	assert.False(t, ok)
+}
+
+type sequenceTransport struct {
+	// returned status codes in order they are provided
+	codes []int
+	// returned Retry-After header
+	retryAfter string
+	// recorded request bodies
+	bodies []string
+}
+
+func (t *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.bodies = append(t.bodies, string(body))
+
+	resp := &http.Response{
+		StatusCode: t.codes[len(t.bodies)-1],
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}
+	if t.retryAfter != "" {
+		resp.Header.Set("Retry-After", t.retryAfter)
+	}
+	return resp, nil
+}
+
 type retriedTransport struct {
 	// number of attempts
 	attempts int
@@ -111,3 +257,23 @@ func (t *retriedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 
 	return resp, nil
 }
+
+func TestRetryRoundTripperDeprecation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	client := &http.Client{Transport: Chain(NewRetryRoundTripper(&DefaultRetryTransport))}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() // nolint: errcheck
+
+	// the usage is reported with the calling code, not net/http or bricks
+	for _, u := range deprecation.Usages() {
+		if u.Name == "transport.DefaultRetryTransport" {
+			assert.Contains(t, u.Caller, "_test.go")
+			return
+		}
+	}
+	t.Error("Expected the usage of transport.DefaultRetryTransport to be reported")
+}
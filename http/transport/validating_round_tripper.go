@@ -0,0 +1,276 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/caarlos0/env"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/feedback"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// modes of the response validation
+const (
+	// ValidationDisabled doesn't validate the responses
+	ValidationDisabled = "disabled"
+	// ValidationLog logs responses that deviate from the specification
+	ValidationLog = "log"
+	// ValidationStrict returns an error for responses that
+	// deviate from the specification
+	ValidationStrict = "strict"
+)
+
+type validationConfig struct {
+	Mode string `env:"HTTP_CLIENT_RESPONSE_VALIDATION" envDefault:"disabled"`
+}
+
+var (
+	validationCfg validationConfig
+	cfgOnce       sync.Once
+	errSetup      error
+)
+
+func init() {
+	configcheck.Register("transport", Setup)
+}
+
+// Setup parses HTTP_CLIENT_RESPONSE_VALIDATION, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&validationCfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse response validation environment: %v", err)
+	}
+}
+
+// ResponseValidationError is returned in strict mode if the
+// response deviates from the specification
+type ResponseValidationError struct {
+	Method string
+	URL    string
+	Status int
+	Err    error
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("response of %s %s (%d) deviates from specification: %v", e.Method, e.URL, e.Status, e.Err)
+}
+
+// ValidatingRoundTripper implements a chainable round tripper that
+// validates the responses against the schemas of the OpenAPIv3
+// specification of the provider. The mode is configured using
+// HTTP_CLIENT_RESPONSE_VALIDATION, e.g. strict in tests and staging and
+// disabled in production. Responses of requests that are not part of the
+// specification are not validated.
+type ValidatingRoundTripper struct {
+	// Mode of the validation, see ValidationDisabled,
+	// ValidationLog and ValidationStrict
+	Mode string
+	// Reporter reports the deviations to the provider (optional),
+	// e.g. feedback.DefaultReporter()
+	Reporter *feedback.Reporter
+
+	operations []validationOperation
+	transport  http.RoundTripper
+}
+
+type validationOperation struct {
+	method   string
+	segments []string
+	query    url.Values
+	op       *openapi3.Operation
+}
+
+// NewValidatingRoundTripper returns a round tripper validating the
+// responses against spec, using the mode of the environment
+func NewValidatingRoundTripper(spec *openapi3.Swagger) *ValidatingRoundTripper {
+	var bases []string
+	for _, server := range spec.Servers {
+		u, err := url.Parse(server.URL)
+		if err == nil {
+			bases = append(bases, strings.TrimSuffix(u.Path, "/"))
+		}
+	}
+	if len(bases) == 0 {
+		bases = []string{""}
+	}
+
+	mustSetup()
+	l := &ValidatingRoundTripper{Mode: validationCfg.Mode}
+	for _, base := range bases {
+		for pattern, pathItem := range spec.Paths {
+			u, err := url.Parse(base + pattern)
+			if err != nil {
+				continue
+			}
+			for method, op := range pathItem.Operations() {
+				l.operations = append(l.operations, validationOperation{
+					method:   method,
+					segments: strings.Split(u.Path, "/"),
+					query:    u.Query(),
+					op:       op,
+				})
+			}
+		}
+	}
+	return l
+}
+
+// Transport returns the RoundTripper to make HTTP requests
+func (l *ValidatingRoundTripper) Transport() http.RoundTripper {
+	return l.transport
+}
+
+// SetTransport sets the RoundTripper to make HTTP requests
+func (l *ValidatingRoundTripper) SetTransport(rt http.RoundTripper) {
+	l.transport = rt
+}
+
+// RoundTrip executes a HTTP request and validates the response
+func (l *ValidatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := l.Transport().RoundTrip(req)
+	if err != nil || l.Mode == ValidationDisabled || l.Mode == "" {
+		return resp, err
+	}
+
+	op := l.operation(req)
+	if op == nil {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close() // nolint: errcheck
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	err = validateResponse(op, resp, body)
+	if err == nil {
+		return resp, nil
+	}
+
+	verr := &ResponseValidationError{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Err: err}
+	if l.Reporter != nil {
+		resp.Request = req
+		l.Reporter.ReportResponse(resp, feedback.KindContract, op.OperationID, err)
+	}
+	if l.Mode == ValidationStrict {
+		return nil, verr
+	}
+	log.Ctx(req.Context()).Warn().Err(verr).Msg("Response deviates from specification")
+	return resp, nil
+}
+
+// operation returns the operation of the specification that matches the
+// request, operations with matching query values are preferred
+func (l *ValidatingRoundTripper) operation(req *http.Request) *openapi3.Operation {
+	var match *validationOperation
+	segments := strings.Split(req.URL.Path, "/")
+	query := req.URL.Query()
+
+	for i := range l.operations {
+		o := &l.operations[i]
+		if o.method != req.Method || !matchSegments(o.segments, segments) {
+			continue
+		}
+		if len(o.query) == 0 {
+			if match == nil {
+				match = o
+			}
+			continue
+		}
+		if matchQuery(o.query, query) && (match == nil || len(o.query) > len(match.query)) {
+			match = o
+		}
+	}
+
+	if match == nil {
+		return nil
+	}
+	return match.op
+}
+
+// matchSegments returns true if the path segments match the
+// segments of the path template
+func matchSegments(template, path []string) bool {
+	if len(template) != len(path) {
+		return false
+	}
+	for i, s := range template {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchQuery returns true if query contains all values of expected
+func matchQuery(expected, query url.Values) bool {
+	for k := range expected {
+		if query.Get(k) != expected.Get(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateResponse validates status, content type and
+// body of the response against the operation
+func validateResponse(op *openapi3.Operation, resp *http.Response, body []byte) error {
+	ref := op.Responses.Get(resp.StatusCode)
+	if ref == nil {
+		ref = op.Responses.Default()
+	}
+	if ref == nil || ref.Value == nil {
+		return fmt.Errorf("status %d is not specified", resp.StatusCode)
+	}
+	if len(ref.Value.Content) == 0 {
+		return nil
+	}
+	if len(body) == 0 && resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid content type %q", resp.Header.Get("Content-Type"))
+	}
+	content := ref.Value.Content.Get(mediaType)
+	if content == nil {
+		return fmt.Errorf("content type %q is not specified", mediaType)
+	}
+	if content.Schema == nil || content.Schema.Value == nil {
+		return nil
+	}
+
+	var value interface{}
+	err = json.Unmarshal(body, &value)
+	if err != nil {
+		return fmt.Errorf("invalid json: %v", err)
+	}
+	return content.Schema.Value.VisitJSON(value)
+}
@@ -0,0 +1,156 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const validationSpec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Test", "version": "1.0"},
+  "servers": [{"url": "https://api.example.com/v1"}],
+  "paths": {
+    "/articles/{id}": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/vnd.api+json": {
+                "schema": {
+                  "type": "object",
+                  "required": ["data"],
+                  "properties": {
+                    "data": {
+                      "type": "object",
+                      "required": ["id", "type"],
+                      "properties": {
+                        "id": {"type": "string"},
+                        "type": {"type": "string", "enum": ["article"]}
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "404": {"description": "Not found"}
+        }
+      }
+    }
+  }
+}`
+
+type staticTransport struct {
+	status      int
+	contentType string
+	body        string
+}
+
+func (t *staticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	if t.contentType != "" {
+		rec.Header().Set("Content-Type", t.contentType)
+	}
+	rec.WriteHeader(t.status)
+	rec.WriteString(t.body) // nolint: errcheck
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func TestValidatingRoundTripper(t *testing.T) {
+	spec, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(validationSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const jsonapi = "application/vnd.api+json"
+	cases := []struct {
+		name      string
+		url       string
+		transport *staticTransport
+		invalid   string
+	}{
+		{"valid", "/v1/articles/1", &staticTransport{200, jsonapi, `{"data":{"id":"1","type":"article"}}`}, ""},
+		{"valid without content", "/v1/articles/1", &staticTransport{404, "", ""}, ""},
+		{"not specified", "/v1/comments/1", &staticTransport{200, "text/plain", "hello"}, ""},
+		{"invalid body", "/v1/articles/1", &staticTransport{200, jsonapi, `{"data":{"id":1,"type":"article"}}`}, "/data/id"},
+		{"invalid enum", "/v1/articles/1", &staticTransport{200, jsonapi, `{"data":{"id":"1","type":"comment"}}`}, "/data/type"},
+		{"missing property", "/v1/articles/1", &staticTransport{200, jsonapi, `{}`}, "\"data\""},
+		{"unspecified status", "/v1/articles/1", &staticTransport{500, "", ""}, "status 500 is not specified"},
+		{"unspecified content type", "/v1/articles/1", &staticTransport{200, "application/json", `{}`}, `content type "application/json" is not specified`},
+		{"invalid json", "/v1/articles/1", &staticTransport{200, jsonapi, `{`}, "invalid json"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, mode := range []string{ValidationDisabled, ValidationLog, ValidationStrict} {
+				rt := NewValidatingRoundTripper(spec)
+				rt.Mode = mode
+				rt.SetTransport(c.transport)
+
+				req := httptest.NewRequest("GET", "http://api.example.com"+c.url, nil)
+				resp, err := rt.RoundTrip(req)
+
+				if mode == ValidationStrict && c.invalid != "" {
+					if _, ok := err.(*ResponseValidationError); !ok || !strings.Contains(err.Error(), c.invalid) {
+						t.Errorf("Expected validation error containing %q, got: %v", c.invalid, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Expected no error in mode %s, got: %v", mode, err)
+				}
+				body, _ := ioutil.ReadAll(resp.Body) // nolint: errcheck
+				if string(body) != c.transport.body {
+					t.Errorf("Expected body %q in mode %s, got: %q", c.transport.body, mode, body)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatingRoundTripperOperation(t *testing.T) {
+	spec, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"paths": {
+			"/items": {"get": {"operationId": "list", "responses": {}}},
+			"/items?include=details": {"get": {"operationId": "details", "responses": {}}},
+			"/items/{id}": {"delete": {"operationId": "delete", "responses": {}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := NewValidatingRoundTripper(spec)
+
+	cases := []struct {
+		method, url, expected string
+	}{
+		{"GET", "/items", "list"},
+		{"GET", "/items?include=details", "details"},
+		{"GET", "/items?include=other", "list"},
+		{"DELETE", "/items/1", "delete"},
+		{"DELETE", "/items/", ""},
+		{"GET", "/items/1", ""},
+	}
+	for _, c := range cases {
+		op := rt.operation(httptest.NewRequest(c.method, c.url, nil))
+		id := ""
+		if op != nil {
+			id = op.OperationID
+		}
+		if id != c.expected {
+			t.Errorf("Expected operation %q for %s %s, got: %q", c.expected, c.method, c.url, id)
+		}
+	}
+}
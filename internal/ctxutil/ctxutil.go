@@ -0,0 +1,25 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package ctxutil contains context helpers shared by the bricks packages
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Detached returns a context that keeps the values (logger, span, ...)
+// of ctx but not its deadline and cancellation, e.g. to clean up after
+// the context of a request is done
+func Detached(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
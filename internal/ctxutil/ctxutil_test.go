@@ -0,0 +1,27 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+type key struct{}
+
+func TestDetached(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+
+	ctx = Detached(ctx)
+	if ctx.Err() != nil || ctx.Done() != nil {
+		t.Errorf("Expected detached context not to be canceled, got: %v", ctx.Err())
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected detached context without deadline")
+	}
+	if v := ctx.Value(key{}); v != "value" {
+		t.Errorf("Expected values to be kept, got: %v", v)
+	}
+}
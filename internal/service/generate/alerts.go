@@ -0,0 +1,157 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generate
+
+import (
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/pace/bricks/maintenance/metric"
+)
+
+// AlertsOptions configure the generated prometheus alerting rules
+type AlertsOptions struct {
+	// Name of the service, all rules select the metrics
+	// of the service using the job label of the scrape config
+	Name string
+	// Availability SLO in percent, e.g. 99.9
+	Availability float64
+	// Latency SLO, 99th percentile of the request duration
+	Latency time.Duration
+	// QueryLatency threshold for the 95th percentile of the
+	// postgres query duration
+	QueryLatency time.Duration
+}
+
+// ErrorBudget returns the ratio of requests that are allowed to fail
+func (o AlertsOptions) ErrorBudget() float64 {
+	return 1 - o.Availability/100
+}
+
+// BurnRateThreshold returns the error ratio that consumes the error
+// budget with the given burn rate
+func (o AlertsOptions) BurnRateThreshold(rate float64) string {
+	threshold := math.Round(rate*o.ErrorBudget()*1e6) / 1e6
+	return strconv.FormatFloat(threshold, 'f', -1, 64)
+}
+
+// Alerts generates prometheus alerting rules for the given options
+// to the specified path, if path is empty the rules are written to stdout
+func Alerts(path string, options AlertsOptions) {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close() // nolint: errcheck
+		w = f
+	}
+
+	err := alertsTemplate.Execute(w, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// alertsTemplate uses multiwindow, multi-burn-rate alerts for the
+// error budget: 14.4x burn rate consumes 2% of a 30 day budget in
+// one hour (page), 6x burn rate consumes 5% in six hours (ticket).
+// The metric names follow METRICS_NAMESPACE like the dashboard.
+var alertsTemplate = template.Must(template.New("alerts").Funcs(template.FuncMap{
+	"metric": metric.Name,
+}).Parse(`groups:
+- name: {{ .Name }}.slo
+  rules:
+  - alert: {{ .Name }}ErrorBudgetBurnFast
+    expr: |
+      (
+        sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}",code=~"5.."}[1h]))
+          / sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}"}[1h])) > {{ .BurnRateThreshold 14.4 }}
+      and
+        sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}",code=~"5.."}[5m]))
+          / sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}"}[5m])) > {{ .BurnRateThreshold 14.4 }}
+      )
+    labels:
+      severity: page
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} is burning its error budget fast"
+      description: "The error rate would consume the error budget of the {{ .Availability }}% availability SLO within 2 days."
+  - alert: {{ .Name }}ErrorBudgetBurnSlow
+    expr: |
+      (
+        sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}",code=~"5.."}[6h]))
+          / sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}"}[6h])) > {{ .BurnRateThreshold 6.0 }}
+      and
+        sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}",code=~"5.."}[30m]))
+          / sum(rate({{ metric "api_http_request_total" }}{job="{{ .Name }}"}[30m])) > {{ .BurnRateThreshold 6.0 }}
+      )
+    labels:
+      severity: ticket
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} is burning its error budget"
+      description: "The error rate would consume the error budget of the {{ .Availability }}% availability SLO within 5 days."
+  - alert: {{ .Name }}HighLatency
+    expr: |
+      histogram_quantile(0.99, sum(rate({{ metric "api_http_request_duration_seconds" }}_bucket{job="{{ .Name }}"}[5m])) by (le, path, method))
+        > {{ .Latency.Seconds }}
+    for: 10m
+    labels:
+      severity: ticket
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} {{"{{"}} $labels.method {{"}}"}} {{"{{"}} $labels.path {{"}}"}} is slow"
+      description: "The 99th percentile latency is above the {{ .Latency }} SLO."
+- name: {{ .Name }}.saturation
+  rules:
+  - alert: {{ .Name }}PostgresSlowQueries
+    expr: |
+      histogram_quantile(0.95, sum(rate({{ metric "postgres_query_duration_seconds" }}_bucket{job="{{ .Name }}"}[5m])) by (le, database))
+        > {{ .QueryLatency.Seconds }}
+    for: 10m
+    labels:
+      severity: ticket
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} postgres queries on {{"{{"}} $labels.database {{"}}"}} are slow"
+      description: "The 95th percentile query duration is above {{ .QueryLatency }}, the connection pool may be saturated."
+  - alert: {{ .Name }}PostgresDeadlineExceeded
+    expr: |
+      sum(rate({{ metric "postgres_query_deadline_exceeded" }}{job="{{ .Name }}"}[5m])) by (database)
+        / sum(rate({{ metric "postgres_query_total" }}{job="{{ .Name }}"}[5m])) by (database) > 0.01
+    for: 5m
+    labels:
+      severity: page
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} postgres queries on {{"{{"}} $labels.database {{"}}"}} exceed their deadlines"
+      description: "More than 1% of the queries are aborted because the request deadline was exceeded."
+  - alert: {{ .Name }}RedisFailures
+    expr: |
+      sum(rate({{ metric "redis_cmd_failed" }}{job="{{ .Name }}"}[5m]))
+        / sum(rate({{ metric "redis_cmd_total" }}{job="{{ .Name }}"}[5m])) > 0.05
+    for: 5m
+    labels:
+      severity: page
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} redis commands fail"
+      description: "More than 5% of the redis commands fail."
+  - alert: {{ .Name }}FileDescriptorsExhausted
+    expr: |
+      max(process_open_fds{job="{{ .Name }}"} / process_max_fds{job="{{ .Name }}"}) > 0.8
+    for: 10m
+    labels:
+      severity: ticket
+      service: {{ .Name }}
+    annotations:
+      summary: "{{ .Name }} uses more than 80% of the available file descriptors"
+`))
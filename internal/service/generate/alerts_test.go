@@ -0,0 +1,230 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/backend/postgres"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func init() {
+	sql.Register("alerts-test", slowDriver{})
+}
+
+// slowDriver executes every statement in 200ms
+type slowDriver struct{}
+
+func (slowDriver) Open(name string) (driver.Conn, error) { return slowConn{}, nil }
+
+type slowConn struct{}
+
+func (slowConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (slowConn) Close() error                              { return nil }
+func (slowConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrBadConn }
+func (slowConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(200 * time.Millisecond)
+	return driver.RowsAffected(0), nil
+}
+
+type alertRules struct {
+	Groups []struct {
+		Name  string `yaml:"name"`
+		Rules []struct {
+			Alert  string            `yaml:"alert"`
+			Expr   string            `yaml:"expr"`
+			For    string            `yaml:"for"`
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"rules"`
+	} `yaml:"groups"`
+}
+
+func TestAlertsOptions(t *testing.T) {
+	o := AlertsOptions{Availability: 99.9}
+	if budget := o.ErrorBudget(); budget < 0.000999 || budget > 0.001001 {
+		t.Errorf("Expected error budget of 0.001, got: %v", budget)
+	}
+	if threshold := o.BurnRateThreshold(14.4); threshold != "0.0144" {
+		t.Errorf("Expected fast burn rate threshold of 0.0144, got: %q", threshold)
+	}
+	if threshold := o.BurnRateThreshold(6); threshold != "0.006" {
+		t.Errorf("Expected slow burn rate threshold of 0.006, got: %q", threshold)
+	}
+}
+
+// generateAlerts returns the rules generated for the options
+func generateAlerts(t *testing.T, options AlertsOptions) alertRules {
+	dir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+	path := filepath.Join(dir, "alerts.yml")
+
+	Alerts(path, options)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rules alertRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		t.Fatalf("Expected valid yaml, got: %v\n%s", err, data)
+	}
+	return rules
+}
+
+func TestAlerts(t *testing.T) {
+	rules := generateAlerts(t, AlertsOptions{
+		Name:         "billing",
+		Availability: 99.5,
+		Latency:      300 * time.Millisecond,
+		QueryLatency: 50 * time.Millisecond,
+	})
+
+	alerts := make(map[string]string)
+	for _, group := range rules.Groups {
+		if !strings.HasPrefix(group.Name, "billing.") {
+			t.Errorf("Expected group of the service, got: %q", group.Name)
+		}
+		for _, rule := range group.Rules {
+			if rule.Labels["service"] != "billing" || rule.Labels["severity"] == "" {
+				t.Errorf("Expected service and severity labels of %s, got: %v", rule.Alert, rule.Labels)
+			}
+			if strings.Contains(rule.Expr, "{{") {
+				t.Errorf("Expected expression of %s to be templated, got: %s", rule.Alert, rule.Expr)
+			}
+			alerts[rule.Alert] = rule.Expr
+		}
+	}
+
+	expected := map[string]string{
+		"billingErrorBudgetBurnFast":      "> 0.072",
+		"billingErrorBudgetBurnSlow":      "> 0.03",
+		"billingHighLatency":              "> 0.3",
+		"billingPostgresSlowQueries":      "> 0.05",
+		"billingPostgresDeadlineExceeded": `job="billing"`,
+		"billingRedisFailures":            `job="billing"`,
+		"billingFileDescriptorsExhausted": "> 0.8",
+	}
+	for alert, contains := range expected {
+		expr, ok := alerts[alert]
+		if !ok {
+			t.Errorf("Expected alert %s", alert)
+			continue
+		}
+		if !strings.Contains(expr, contains) {
+			t.Errorf("Expected expression of %s to contain %q, got: %s", alert, contains, expr)
+		}
+	}
+	if len(alerts) != len(expected) {
+		t.Errorf("Expected %d alerts, got: %d", len(expected), len(alerts))
+	}
+}
+
+func TestAlertsNamespace(t *testing.T) {
+	defer func(ns string) { metric.Namespace = ns }(metric.Namespace)
+	metric.Namespace = "acme"
+
+	rules := generateAlerts(t, AlertsOptions{Name: "billing", Availability: 99.9})
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			if strings.Contains(rule.Expr, "pace_") {
+				t.Errorf("Expected metrics of the namespace in %s, got: %s", rule.Alert, rule.Expr)
+			}
+			if !strings.Contains(rule.Expr, `{job="billing"`) || strings.Contains(rule.Expr, "service=") {
+				t.Errorf("Expected job selector in %s, got: %s", rule.Alert, rule.Expr)
+			}
+		}
+	}
+	if expr := rules.Groups[0].Rules[0].Expr; !strings.Contains(expr, "acme_api_http_request_total{") {
+		t.Errorf("Expected request metric of the namespace, got: %s", expr)
+	}
+}
+
+// histogramQuantile computes the quantile of the buckets like
+// histogram_quantile of prometheus
+func histogramQuantile(q float64, h *dto.Histogram) float64 {
+	rank := q * float64(h.GetSampleCount())
+	lower, count := 0.0, 0.0
+	for _, b := range h.GetBucket() {
+		upper, cumulative := b.GetUpperBound(), float64(b.GetCumulativeCount())
+		if cumulative >= rank {
+			return lower + (upper-lower)*(rank-count)/(cumulative-count)
+		}
+		lower, count = upper, cumulative
+	}
+	// the quantile is in the +Inf bucket
+	return lower
+}
+
+func TestPostgresSlowQueriesSample(t *testing.T) {
+	db, err := postgres.CustomSQLConnectionPool("alerts-test", "postgres://localhost:5432/alertstest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close() // nolint: errcheck
+	if _, err := db.Exec("UPDATE orders SET paid = true"); err != nil {
+		t.Fatal(err)
+	}
+
+	// p95 of the real postgres query duration histogram
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p95 := math.NaN()
+	for _, f := range families {
+		if f.GetName() != metric.Name("postgres_query_duration_seconds") {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "database" && l.GetValue() == "localhost:5432/alertstest" {
+					p95 = histogramQuantile(0.95, m.GetHistogram())
+				}
+			}
+		}
+	}
+	if math.IsNaN(p95) {
+		t.Fatal("Expected sample of the query duration")
+	}
+
+	// a 200ms query fires the alert of a 100ms but not of a 300ms threshold
+	for latency, firing := range map[time.Duration]bool{100 * time.Millisecond: true, 300 * time.Millisecond: false} {
+		var expr string
+		for _, group := range generateAlerts(t, AlertsOptions{Name: "billing", Availability: 99.9, QueryLatency: latency}).Groups {
+			for _, rule := range group.Rules {
+				if rule.Alert == "billingPostgresSlowQueries" {
+					expr = rule.Expr
+				}
+			}
+		}
+		i := strings.LastIndex(expr, "> ")
+		if i < 0 {
+			t.Fatalf("Expected threshold in expression, got: %s", expr)
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(expr[i+2:]), 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (p95 > threshold) != firing {
+			t.Errorf("Expected p95 of %v to fire the alert of %v: %v, threshold: %v", p95, latency, firing, threshold)
+		}
+	}
+}
@@ -76,21 +76,47 @@ func Commands(path string, options CommandOptions) {
 func generateDaemonMain(f *jen.File, cmdName string) {
 	httpPkg := "github.com/pace/bricks/http"
 	logPkg := "github.com/pace/bricks/maintenance/log"
+	configcheckPkg := "github.com/pace/bricks/maintenance/configcheck"
+	deprecationPkg := "github.com/pace/bricks/maintenance/deprecation"
 	trancing := "github.com/pace/bricks/maintenance/tracing"
 
 	f.ImportAlias(httpPkg, "pacehttp")
 	f.Anon(trancing)
 	f.Func().Id("main").Params().BlockFunc(func(g *jen.Group) {
+		g.Qual(configcheckPkg, "ExitIfRequested").Call()
 		g.Defer().Qual(errorsPkg, "HandleWithCtx").Call(jen.Qual("context", "Background").Call(), jen.Lit(cmdName))
 		g.Id("router").Op(":=").Qual(httpPkg, "Router").Call()
 		g.Id("s").Op(":=").Qual(httpPkg, "Server").Call(jen.Id("router"))
+		g.Qual(deprecationPkg, "ScheduleSummary").Call()
 
 		g.Qual(logPkg, "Logger").Call().Dot("Info").Call().Dot("Str").Call(
 			jen.Lit("addr"),
 			jen.Id("s").Dot("Addr"),
 		).Dot("Msg").Call(jen.Lit(fmt.Sprintf("Starting %s ...", cmdName)))
 
-		g.Qual(logPkg, "Fatal").Call(jen.Id("s").Dot("ListenAndServe").Call())
+		// shut down gracefully on SIGTERM/SIGINT, waiting for the requests
+		// and streams at most SHUTDOWN_GRACE_PERIOD
+		g.Id("done").Op(":=").Make(jen.Chan().Struct())
+		g.Go().Func().Params().BlockFunc(func(g *jen.Group) {
+			g.Id("sig").Op(":=").Make(jen.Chan().Qual("os", "Signal"), jen.Lit(1))
+			g.Qual("os/signal", "Notify").Call(jen.Id("sig"), jen.Qual("syscall", "SIGTERM"), jen.Qual("os", "Interrupt"))
+			g.Op("<-").Id("sig")
+			g.If(
+				jen.List(jen.Id("_"), jen.Err()).Op(":=").Qual(httpPkg, "Shutdown").Call(jen.Qual("context", "Background").Call(), jen.Id("s")),
+				jen.Err().Op("!=").Nil(),
+			).Block(
+				jen.Qual(logPkg, "Logger").Call().Dot("Warn").Call().Dot("Err").Call(jen.Err()).Dot("Msg").Call(jen.Lit("Failed to shut down the server gracefully")),
+			)
+			g.Close(jen.Id("done"))
+		}).Call()
+
+		g.If(
+			jen.Err().Op(":=").Id("s").Dot("ListenAndServe").Call(),
+			jen.Err().Op("!=").Qual("net/http", "ErrServerClosed"),
+		).Block(
+			jen.Qual(logPkg, "Fatal").Call(jen.Err()),
+		)
+		g.Op("<-").Id("done")
 	})
 }
 
@@ -0,0 +1,53 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package generate
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/pace/bricks/backend/postgres"
+	"github.com/pace/bricks/backend/postgres/generator"
+)
+
+// ModelsOptions options to respect when generating the models
+type ModelsOptions struct {
+	PkgName, Path string
+	// Schema of the tables, e.g. public
+	Schema string
+	// Tables to generate models for, all tables of the schema if empty
+	Tables []string
+}
+
+// Models generates go-pg models of the tables of the database configured
+// using the POSTGRES_* environment variables, if path is empty the models
+// are written to stdout
+func Models(options ModelsOptions) {
+	db := postgres.ConnectionPool()
+	defer db.Close() // nolint: errcheck
+
+	tables, err := generator.Inspect(context.Background(), db, options.Schema, options.Tables...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(tables) == 0 {
+		log.Fatalf("no tables found in schema %q", options.Schema)
+	}
+
+	result, err := generator.Generate(options.PkgName, tables)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if options.Path == "" {
+		_, err = os.Stdout.WriteString(result)
+	} else {
+		err = ioutil.WriteFile(options.Path, []byte(result), 0644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@
 package generate
 
 import (
+	"io/ioutil"
 	"log"
 	"os"
 
@@ -13,6 +14,9 @@ import (
 // RestOptions options to respect when generating the rest api
 type RestOptions struct {
 	PkgName, Path, Source string
+	// DashboardPath is the path for the generated grafana
+	// dashboard, no dashboard is generated if empty
+	DashboardPath string
 }
 
 // Rest builds a jsonapi rest api
@@ -36,4 +40,16 @@ func Rest(options RestOptions) {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	if options.DashboardPath != "" {
+		dashboard, err := g.BuildDashboardSource(options.Source, options.PkgName)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = ioutil.WriteFile(options.DashboardPath, []byte(dashboard), 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,169 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package configcheck implements the configuration dry-run mode. Started
+// with --check-config, a service parses the environment based
+// configurations of all packages, checks that the backends are reachable,
+// prints a report and exits without serving traffic. The exit code is
+// non-zero if a configuration is malformed or a backend is not
+// available, so the mode can be used in CI and pre-deploy hooks.
+//
+// Packages with an environment based configuration export a Setup
+// function that parses (and validates) the environment once and returns
+// the error of the first call on subsequent calls. The functions of the
+// package call it on first use and exit the process if the environment
+// is malformed; services and tools that want to handle the error call
+// Setup explicitly before. The packages register their Setup using
+// Register, so the dry-run mode checks them.
+package configcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/profile"
+)
+
+// Flag enables the dry-run mode if passed as command line argument
+const Flag = "--check-config"
+
+// Timeout is the maximum duration of the backend checks
+var Timeout = 10 * time.Second
+
+var (
+	setupsMu sync.RWMutex
+	setups   = make(map[string]func() error)
+
+	backendsMu sync.RWMutex
+	backends   = make(map[string]func(ctx context.Context) error)
+)
+
+func init() {
+	// the log and profile packages can't register themselves,
+	// configcheck depends on them
+	Register("profile", profile.Setup)
+	Register("log", log.Setup)
+}
+
+// Register adds the setup of an environment based configuration, usually
+// the Setup function of a package. Packages of bricks register themselves.
+func Register(name string, setup func() error) {
+	setupsMu.Lock()
+	defer setupsMu.Unlock()
+	setups[name] = setup
+}
+
+// RegisterBackend adds a check of the connectivity of a backend, e.g.
+// redis.HealthCheck. The checks are only executed by the dry-run mode,
+// services register them before calling ExitIfRequested:
+//
+//	configcheck.RegisterBackend("redis", redis.HealthCheck)
+func RegisterBackend(name string, check func(ctx context.Context) error) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = check
+}
+
+// Requested returns true if the process was started with Flag
+func Requested() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == Flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitIfRequested checks the configuration and the backends and exits if
+// the process was started with Flag. Call it first in main, before the
+// router and the server are set up, only the configurations and backends
+// registered so far are checked:
+//
+//	func main() {
+//		configcheck.RegisterBackend("redis", redis.HealthCheck)
+//		configcheck.ExitIfRequested()
+//		s := pacehttp.Server(pacehttp.Router())
+//		log.Fatal(s.ListenAndServe())
+//	}
+func ExitIfRequested() {
+	if !Requested() {
+		return
+	}
+	ok := Run(os.Stdout)
+	if ok {
+		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+		ok = CheckBackends(ctx, os.Stdout)
+		cancel()
+	} else {
+		// backends can't be set up with malformed configurations
+		fmt.Fprintln(os.Stdout, "SKIP     backend checks") // nolint: errcheck
+	}
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Run parses all registered configurations and writes the report to w.
+// It returns false if a configuration is malformed.
+func Run(w io.Writer) bool {
+	ok := true
+
+	setupsMu.RLock()
+	defer setupsMu.RUnlock()
+	names := make([]string, 0, len(setups))
+	for name := range setups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := setup(setups[name]); err != nil {
+			ok = false
+			fmt.Fprintf(w, "FAIL     config %s: %v\n", name, err) // nolint: errcheck
+			continue
+		}
+		fmt.Fprintf(w, "OK       config %s\n", name) // nolint: errcheck
+	}
+	return ok
+}
+
+// CheckBackends executes the registered backend checks (see
+// RegisterBackend) and writes the report to w. It returns false if a check
+// failed. The readiness checks (see health.RegisterCheck) are not executed,
+// the service only becomes ready while serving, e.g. after warming up.
+func CheckBackends(ctx context.Context, w io.Writer) bool {
+	ok := true
+
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := backends[name](ctx); err != nil {
+			ok = false
+			fmt.Fprintf(w, "FAIL     backend %s: %v\n", name, err) // nolint: errcheck
+			continue
+		}
+		fmt.Fprintf(w, "OK       backend %s\n", name) // nolint: errcheck
+	}
+	return ok
+}
+
+// setup calls fn, panics are returned as error
+func setup(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
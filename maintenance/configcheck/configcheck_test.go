@@ -0,0 +1,59 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package configcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	Register("good", func() error { return nil })
+
+	var buf bytes.Buffer
+	assert.True(t, Run(&buf))
+	assert.Contains(t, buf.String(), "OK       config good\n")
+
+	Register("malformed", func() error { return errors.New(`env: parse error on field "Port"`) })
+	Register("panic", func() error { panic("missing variable") })
+	buf.Reset()
+	assert.False(t, Run(&buf))
+	assert.Contains(t, buf.String(), "OK       config good\n")
+	assert.Contains(t, buf.String(), "FAIL     config malformed: env: parse error on field \"Port\"\n")
+	assert.Contains(t, buf.String(), "FAIL     config panic: panic: missing variable\n")
+}
+
+func TestCheckBackends(t *testing.T) {
+	RegisterBackend("database", func(ctx context.Context) error { return nil })
+	// readiness checks are not executed, e.g. of warm-ups
+	health.RegisterCheck("cache warm-up", func(ctx context.Context) error {
+		return errors.New("cache not warmed up yet")
+	})
+
+	var buf bytes.Buffer
+	assert.True(t, CheckBackends(context.Background(), &buf))
+	assert.Equal(t, "OK       backend database\n", buf.String())
+
+	RegisterBackend("search", func(ctx context.Context) error { return errors.New("connection refused") })
+	buf.Reset()
+	assert.False(t, CheckBackends(context.Background(), &buf))
+	assert.Equal(t, "OK       backend database\n"+
+		"FAIL     backend search: connection refused\n", buf.String())
+}
+
+func TestRequested(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	os.Args = []string{"service"}
+	assert.False(t, Requested())
+	os.Args = []string{"service", "--check-config"}
+	assert.True(t, Requested())
+}
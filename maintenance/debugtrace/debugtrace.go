@@ -0,0 +1,281 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package debugtrace captures the timing breakdown of single requests for
+// debugging slow customer requests. Requests flagged with a signed
+// Debug-Trace header are traced by the middlewares, the handler, the
+// postgres queries, the redis commands and the outbound http requests.
+// The captured traces are kept in memory and retrieved as waterfall from
+// /debug/traces of the default router, which needs DEBUG_ENDPOINTS and the
+// DEBUG_TOKEN.
+package debugtrace
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Secret of the signatures of the debug header, empty disables the capture
+	Secret string `env:"DEBUG_TRACE_SECRET"`
+	// Maximum age of the signatures
+	MaxAge time.Duration `env:"DEBUG_TRACE_MAX_AGE" envDefault:"5m"`
+	// Number of captured traces kept in memory
+	Keep int `env:"DEBUG_TRACE_KEEP" envDefault:"100"`
+	// Maximum number of spans per trace
+	MaxSpans int `env:"DEBUG_TRACE_MAX_SPANS" envDefault:"1000"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses and validates the DEBUG_TRACE_* environment, see
+// configcheck. The middleware disables the capture if it is malformed.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+		if errSetup == nil && cfg.Keep <= 0 {
+			errSetup = errors.New("DEBUG_TRACE_KEEP must be positive")
+		}
+		if errSetup == nil {
+			store = newTraceStore(cfg.Keep)
+		}
+	})
+	return errSetup
+}
+
+var (
+	paceDebugTraceCapturedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: metric.Name("debug_trace_captured_total"),
+			Help: "Collects stats about the number of captured debug traces",
+		},
+	)
+	paceDebugTraceRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("debug_trace_rejected_total"),
+			Help: "Collects stats about the number of rejected debug headers partitioned by reason",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceDebugTraceCapturedTotal)
+	prometheus.MustRegister(paceDebugTraceRejectedTotal)
+	configcheck.Register("debug trace", Setup)
+}
+
+// Header flags requests whose trace is captured, it contains
+// the signature of Sign
+const Header = "Debug-Trace"
+
+// IDHeader of the responses contains the id of the captured trace
+const IDHeader = "Debug-Trace-Id"
+
+// Errors of the debug header
+var (
+	ErrDisabled         = errors.New("debugtrace: DEBUG_TRACE_SECRET not configured")
+	ErrMalformedHeader  = errors.New("debugtrace: malformed debug header")
+	ErrInvalidSignature = errors.New("debugtrace: invalid signature")
+	ErrExpiredSignature = errors.New("debugtrace: expired signature")
+)
+
+// Sign returns the value of the debug header signed with
+// DEBUG_TRACE_SECRET, it is valid for DEBUG_TRACE_MAX_AGE around t
+func Sign(t time.Time) (string, error) {
+	if err := Setup(); err != nil {
+		return "", err
+	}
+	if cfg.Secret == "" {
+		return "", ErrDisabled
+	}
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return ts + "." + signature(ts), nil
+}
+
+// verify checks the value of the debug header
+func verify(value string, now time.Time) error {
+	if cfg.Secret == "" {
+		return ErrDisabled
+	}
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 {
+		return ErrMalformedHeader
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ErrMalformedHeader
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(signature(parts[0]))) {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(ts, 0))
+	if age > cfg.MaxAge || age < -cfg.MaxAge {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func signature(ts string) string {
+	mac := hmac.New(sha256.New, []byte(cfg.Secret))
+	mac.Write([]byte(ts)) // nolint: errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Trace is the captured timing breakdown of a request
+type Trace struct {
+	ID       string    `json:"id"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Route    string    `json:"route,omitempty"`
+	Status   int       `json:"status"`
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration"` // ms
+	// Spans ordered by their start (waterfall)
+	Spans []Span `json:"spans,omitempty"`
+	// Truncated is true if spans were dropped because
+	// of DEBUG_TRACE_MAX_SPANS
+	Truncated bool `json:"truncated,omitempty"`
+
+	mu       sync.Mutex
+	nextSpan int
+}
+
+// Span is a timed part of a request, e.g. a middleware or a query
+type Span struct {
+	ID     int `json:"id"`
+	Parent int `json:"parent,omitempty"` // 0 for spans of the request
+	Depth  int `json:"depth"`
+	// Category of the span, e.g. "middleware", "handler", "db",
+	// "redis" or "http"
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Offset   float64 `json:"offset"`   // ms since the start of the request
+	Duration float64 `json:"duration"` // ms
+	Error    string  `json:"error,omitempty"`
+}
+
+type ctxkey int
+
+const (
+	traceKey ctxkey = iota
+	spanKey
+)
+
+// spanRef is the current span of a context
+type spanRef struct {
+	id    int
+	depth int
+}
+
+func fromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(traceKey).(*Trace)
+	return t
+}
+
+func contextWithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, traceKey, t)
+}
+
+// Enabled returns true if the trace of the request of ctx is captured
+func Enabled(ctx context.Context) bool {
+	return fromContext(ctx) != nil
+}
+
+// Start starts a span of the trace of ctx, the span ends with the call of
+// the returned function. The returned context contains the span as parent
+// of further spans. Without trace ctx is returned with a no-op function.
+func Start(ctx context.Context, category, name string) (context.Context, func()) {
+	t := fromContext(ctx)
+	if t == nil {
+		return ctx, func() {}
+	}
+	parent, _ := ctx.Value(spanKey).(spanRef)
+	start := time.Now()
+	id := t.reserve()
+	if id == 0 {
+		return ctx, func() {}
+	}
+	ref := spanRef{id: id, depth: parent.depth + 1}
+	return context.WithValue(ctx, spanKey, ref), func() {
+		t.add(ref, parent.id, category, name, start, time.Since(start), nil)
+	}
+}
+
+// Record adds a finished span to the trace of ctx, e.g. from the hooks of
+// database drivers. It is a no-op without trace.
+func Record(ctx context.Context, category, name string, start time.Time, elapsed time.Duration, err error) {
+	t := fromContext(ctx)
+	if t == nil {
+		return
+	}
+	parent, _ := ctx.Value(spanKey).(spanRef)
+	if id := t.reserve(); id != 0 {
+		t.add(spanRef{id: id, depth: parent.depth + 1}, parent.id, category, name, start, elapsed, err)
+	}
+}
+
+// reserve returns the id of a new span, 0 if the
+// maximum number of spans is reached
+func (t *Trace) reserve() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.nextSpan >= cfg.MaxSpans {
+		t.Truncated = true
+		return 0
+	}
+	t.nextSpan++
+	return t.nextSpan
+}
+
+func (t *Trace) add(ref spanRef, parent int, category, name string, start time.Time, elapsed time.Duration, err error) {
+	s := Span{
+		ID:       ref.id,
+		Parent:   parent,
+		Depth:    ref.depth,
+		Category: category,
+		Name:     name,
+		Offset:   milliseconds(start.Sub(t.Start)),
+		Duration: milliseconds(elapsed),
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	t.mu.Lock()
+	t.Spans = append(t.Spans, s)
+	t.mu.Unlock()
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// newID returns a random trace id
+func newID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,165 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package debugtrace
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) {
+	if err := Setup(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Secret = "secret"
+	store = newTraceStore(cfg.Keep)
+}
+
+func TestSignature(t *testing.T) {
+	setupTest(t)
+	now := time.Now()
+
+	header, err := Sign(now)
+	assert.NoError(t, err)
+	assert.NoError(t, verify(header, now.Add(time.Minute)))
+	assert.Equal(t, ErrExpiredSignature, verify(header, now.Add(time.Hour)))
+	assert.Equal(t, ErrExpiredSignature, verify(header, now.Add(-time.Hour)))
+	assert.Equal(t, ErrInvalidSignature, verify(header+"0", now))
+	assert.Equal(t, ErrMalformedHeader, verify("abc", now))
+	assert.Equal(t, ErrMalformedHeader, verify("abc.def", now))
+
+	cfg.Secret = ""
+	_, err = Sign(now)
+	assert.Equal(t, ErrDisabled, err)
+	assert.Equal(t, ErrDisabled, verify(header, now))
+}
+
+func TestMiddleware(t *testing.T) {
+	setupTest(t)
+
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.Use(Wrap("test", func(next http.Handler) http.Handler { return next }))
+	r.Use(HandlerMiddleware)
+	r.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx, end := Start(r.Context(), "service", "load user")
+		Record(ctx, "db", "SELECT users", time.Now(), time.Millisecond, errors.New("timeout"))
+		end()
+		w.WriteHeader(http.StatusNotFound)
+	}).Name("GetUser")
+
+	// requests without valid header are not captured
+	for _, header := range []string{"", "123.abc"} {
+		req := httptest.NewRequest("GET", "/users/1", nil)
+		req.Header.Set(Header, header)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get(IDHeader))
+	}
+
+	header, err := Sign(time.Now())
+	assert.NoError(t, err)
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(Header, header)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	id := rec.Header().Get(IDHeader)
+	assert.NotEmpty(t, id)
+
+	// list
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	var summaries []summary
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&summaries))
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, id, summaries[0].ID)
+		assert.Equal(t, "GetUser", summaries[0].Route)
+		assert.Equal(t, http.StatusNotFound, summaries[0].Status)
+	}
+
+	// waterfall
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/"+id, nil))
+	var trace Trace
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&trace))
+	if assert.Len(t, trace.Spans, 4) {
+		expected := []struct {
+			category, name string
+			depth          int
+		}{
+			{"middleware", "test", 1},
+			{"handler", "GetUser", 2},
+			{"service", "load user", 3},
+			{"db", "SELECT users", 4},
+		}
+		for i, e := range expected {
+			s := trace.Spans[i]
+			assert.Equal(t, e.category, s.Category)
+			assert.Equal(t, e.name, s.Name)
+			assert.Equal(t, e.depth, s.Depth)
+			if i > 0 {
+				assert.Equal(t, trace.Spans[i-1].ID, s.Parent)
+			}
+		}
+		assert.Equal(t, "timeout", trace.Spans[3].Error)
+		assert.True(t, trace.Spans[0].Duration <= trace.Duration)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMaxSpans(t *testing.T) {
+	setupTest(t)
+	maxSpans := cfg.MaxSpans
+	defer func() { cfg.MaxSpans = maxSpans }()
+	cfg.MaxSpans = 2
+
+	tr := &Trace{Start: time.Now()}
+	ctx := httptest.NewRequest("GET", "/", nil).Context()
+	assert.False(t, Enabled(ctx))
+	Record(ctx, "db", "ignored", time.Now(), 0, nil)
+
+	ctx = contextWithTrace(ctx, tr)
+	for i := 0; i < 3; i++ {
+		Record(ctx, "db", "query", time.Now(), 0, nil)
+	}
+	assert.Len(t, tr.Spans, 2)
+	assert.True(t, tr.Truncated)
+}
+
+func TestMalformedEnvironment(t *testing.T) {
+	setupTest(t)
+	defer func() { errSetup = nil }()
+	errSetup = errors.New("DEBUG_TRACE_KEEP must be positive")
+
+	// requests are served without capture
+	calls := 0
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.False(t, Enabled(r.Context()))
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, "1.abc")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, rec.Header().Get(IDHeader))
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	_, err := Sign(time.Now())
+	assert.Equal(t, errSetup, err)
+}
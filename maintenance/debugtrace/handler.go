@@ -0,0 +1,66 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package debugtrace
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// summary of a trace in the list of traces
+type summary struct {
+	ID       string    `json:"id"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Route    string    `json:"route,omitempty"`
+	Status   int       `json:"status"`
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration"`
+}
+
+// Handler returns the endpoint of the captured traces, it is mounted on
+// /debug/traces/ by the default router (paths are relative to the mount
+// point). GET / lists the traces (latest first), GET /{id} returns the
+// trace with its spans as waterfall. If the environment is malformed, it
+// responds 503 Service Unavailable.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Setup(); err != nil {
+			http.Error(w, "debug traces not available", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != "GET" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.Trim(r.URL.Path, "/")
+		if id == "" {
+			traces := store.list()
+			summaries := make([]summary, len(traces))
+			for i, t := range traces {
+				summaries[i] = summary{ID: t.ID, Method: t.Method, Path: t.Path, Route: t.Route,
+					Status: t.Status, Start: t.Start, Duration: t.Duration}
+			}
+			writeJSON(w, summaries)
+			return
+		}
+
+		t, ok := store.get(id)
+		if !ok {
+			http.Error(w, "trace not found", http.StatusNotFound)
+			return
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		writeJSON(w, t)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v) // nolint: errcheck
+}
@@ -0,0 +1,135 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package debugtrace
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Middleware captures the traces of the requests with a valid debug header,
+// the id of the trace is returned in the Debug-Trace-Id header. Requests
+// with invalid headers are served without capture. If the environment is
+// malformed, the error is logged and no traces are captured.
+func Middleware(next http.Handler) http.Handler {
+	if err := Setup(); err != nil {
+		log.Logger().Error().Err(err).Msg("Debug traces disabled, failed to parse debug trace environment")
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get(Header)
+		if value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := verify(value, time.Now()); err != nil {
+			paceDebugTraceRejectedTotal.With(prometheus.Labels{"reason": rejectReason(err)}).Inc()
+			log.Req(r).Warn().Err(err).Msg("Ignoring debug trace header")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		t := &Trace{ID: newID(), Method: r.Method, Path: r.URL.Path, Route: routeName(r), Start: time.Now()}
+		w.Header().Set(IDHeader, t.ID)
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r.WithContext(contextWithTrace(r.Context(), t)))
+
+		t.Status = sw.status
+		if t.Status == 0 {
+			t.Status = http.StatusOK
+		}
+		t.Duration = milliseconds(time.Since(t.Start))
+		store.add(t)
+		paceDebugTraceCapturedTotal.Inc()
+		log.Req(r).Info().Str("debug_trace", t.ID).Float64("duration", t.Duration).Msg("Debug trace captured")
+	})
+}
+
+// Wrap returns the middleware mw, which is traced as span "middleware"
+// with the name. The span is the parent of the spans of the following
+// middlewares and the handler, so its own time is its duration minus
+// the duration of its child.
+func Wrap(name string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		h := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !Enabled(r.Context()) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			ctx, end := Start(r.Context(), "middleware", name)
+			defer end()
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandlerMiddleware traces the handler of the route as span "handler",
+// it is the last middleware of the default router
+func HandlerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Enabled(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, end := Start(r.Context(), "handler", routeName(r))
+		defer end()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func rejectReason(err error) string {
+	switch err {
+	case ErrDisabled:
+		return "disabled"
+	case ErrExpiredSignature:
+		return "expired"
+	case ErrInvalidSignature:
+		return "signature"
+	}
+	return "malformed"
+}
+
+func routeName(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	name := route.GetName()
+	if name == "" {
+		name, _ = route.GetPathTemplate() // nolint: errcheck
+	}
+	return name
+}
+
+// statusWriter records the status of the response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
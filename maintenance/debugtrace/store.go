@@ -0,0 +1,61 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package debugtrace
+
+import (
+	"sort"
+	"sync"
+)
+
+// store keeps the captured traces, set by Setup
+var store *traceStore
+
+// traceStore keeps the last captured traces
+type traceStore struct {
+	mu     sync.Mutex
+	traces []*Trace // ring buffer
+	next   int
+}
+
+func newTraceStore(size int) *traceStore {
+	return &traceStore{traces: make([]*Trace, size)}
+}
+
+// add adds a finished trace, the spans are sorted by their start
+func (s *traceStore) add(t *Trace) {
+	t.mu.Lock()
+	sort.SliceStable(t.Spans, func(i, j int) bool { return t.Spans[i].Offset < t.Spans[j].Offset })
+	t.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traces[s.next] = t
+	s.next = (s.next + 1) % len(s.traces)
+}
+
+// get returns the trace with the id
+func (s *traceStore) get(id string) (*Trace, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.traces {
+		if t != nil && t.ID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// list returns the traces, latest first
+func (s *traceStore) list() []*Trace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	traces := make([]*Trace, 0, len(s.traces))
+	for i := 1; i <= len(s.traces); i++ {
+		t := s.traces[(s.next-i+len(s.traces))%len(s.traces)]
+		if t != nil {
+			traces = append(traces, t)
+		}
+	}
+	return traces
+}
@@ -0,0 +1,204 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package deprecation tracks the usage of deprecated bricks APIs. Bricks
+// packages register their deprecated functions and middlewares and report
+// every use; services log the first use with the calling code, expose a
+// metric of the usages and log a summary after the startup. Upgrades can be
+// planned using dashboards of the metric instead of searching the code of
+// every service.
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	// Delay after the start of the service the summary is logged
+	SummaryDelay time.Duration `env:"DEPRECATION_SUMMARY_DELAY" envDefault:"1m"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+var paceDeprecatedAPIUsageTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("deprecated_api_usage_total"),
+		Help: "Collects stats about the usage of deprecated bricks APIs partitioned by API and planned removal",
+	},
+	[]string{"api", "removal"},
+)
+
+func init() {
+	prometheus.MustRegister(paceDeprecatedAPIUsageTotal)
+	configcheck.Register("deprecation", Setup)
+}
+
+// Setup parses DEPRECATION_SUMMARY_DELAY, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse deprecation environment: %v", err)
+	}
+}
+
+// API is a deprecated function, type or middleware
+type API struct {
+	// Name of the API, e.g. "transport.DefaultRetryTransport"
+	Name string
+	// Since is the version that deprecated the API
+	Since string
+	// Removal is the version that is planned to remove the API (optional)
+	Removal string
+	// Replacement describes what to use instead
+	Replacement string
+}
+
+// Usage of a deprecated API
+type Usage struct {
+	API
+	Count int64
+	// Caller is the location of the code that used the API first
+	Caller   string
+	FirstUse time.Time
+}
+
+var (
+	mu     sync.Mutex
+	apis   = make(map[string]API)
+	usages = make(map[string]*Usage)
+)
+
+// Register registers a deprecated API, called by
+// the bricks package of the API in init
+func Register(api API) {
+	mu.Lock()
+	defer mu.Unlock()
+	apis[api.Name] = api
+}
+
+// Use reports the use of the deprecated API, called by the deprecated
+// API itself. The first use is logged with the location of the calling
+// code of the service (see caller), every use is counted.
+func Use(ctx context.Context, name string) {
+	mu.Lock()
+	u, ok := usages[name]
+	if !ok {
+		api, registered := apis[name]
+		if !registered {
+			api = API{Name: name}
+		}
+		u = &Usage{API: api, FirstUse: time.Now(), Caller: caller()}
+		usages[name] = u
+	}
+	u.Count++
+	api := u.API
+	caller := u.Caller
+	mu.Unlock()
+
+	paceDeprecatedAPIUsageTotal.With(prometheus.Labels{"api": api.Name, "removal": api.Removal}).Inc()
+	if !ok {
+		logger := log.Logger()
+		if ctx != nil {
+			logger = log.Ctx(ctx)
+		}
+		logger.Warn().Str("api", api.Name).Str("since", api.Since).Str("removal", api.Removal).
+			Str("replacement", api.Replacement).Str("caller", caller).Msg("Deprecated API used")
+	}
+}
+
+// caller returns the location of the first frame of the stack that is
+// neither part of bricks nor of net/http, e.g. the service code sending a
+// request with a deprecated round tripper. Frames of test files of bricks
+// are not skipped. If there is no such frame, the caller of the
+// deprecated API is returned.
+func caller() string {
+	pcs := make([]uintptr, 64)
+	// skip runtime.Callers, caller and Use
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var first string
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" {
+			location := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			if first == "" {
+				first = location
+			}
+			if !skipFrame(frame) {
+				return location
+			}
+		}
+		if !more {
+			return first
+		}
+	}
+}
+
+// skipFrame returns true if the frame is part of bricks,
+// net/http or the runtime
+func skipFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	// the path of vendored bricks is prefixed with the path of the service
+	return strings.Contains(frame.Function, "github.com/pace/bricks/") ||
+		strings.HasPrefix(frame.Function, "net/http.") ||
+		strings.HasPrefix(frame.Function, "runtime.")
+}
+
+// Usages returns the usages of deprecated APIs sorted by name
+func Usages() []Usage {
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]Usage, 0, len(usages))
+	for _, u := range usages {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// LogSummary logs the deprecated APIs used so far
+func LogSummary() {
+	used := Usages()
+	if len(used) == 0 {
+		log.Logger().Info().Msg("No deprecated APIs used")
+		return
+	}
+	for _, u := range used {
+		log.Logger().Warn().Str("api", u.Name).Str("since", u.Since).Str("removal", u.Removal).
+			Str("replacement", u.Replacement).Str("caller", u.Caller).Int64("count", u.Count).
+			Msg("Deprecated API used")
+	}
+	log.Logger().Warn().Int("apis", len(used)).Msg("Deprecated APIs used, see the replacements before upgrading bricks")
+}
+
+// ScheduleSummary logs the summary once after DEPRECATION_SUMMARY_DELAY,
+// the generated daemons call it on startup. Deprecated APIs are usually
+// used while the service is set up or with the first requests.
+func ScheduleSummary() {
+	mustSetup()
+	time.AfterFunc(cfg.SummaryDelay, LogSummary)
+}
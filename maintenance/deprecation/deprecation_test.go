@@ -0,0 +1,60 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package deprecation
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func deprecatedFunc() {
+	Use(context.Background(), "deprecation.deprecatedFunc")
+}
+
+func TestUse(t *testing.T) {
+	Register(API{Name: "deprecation.deprecatedFunc", Since: "v0.2.0", Removal: "v0.3.0", Replacement: "use newFunc"})
+
+	deprecatedFunc()
+	deprecatedFunc()
+	Use(context.TODO(), "deprecation.unregistered")
+
+	used := Usages()
+	if assert.Len(t, used, 2) {
+		assert.Equal(t, "deprecation.deprecatedFunc", used[0].Name)
+		assert.Equal(t, "v0.3.0", used[0].Removal)
+		assert.Equal(t, int64(2), used[0].Count)
+		assert.True(t, strings.Contains(used[0].Caller, "deprecation_test.go"), used[0].Caller)
+		assert.Equal(t, "deprecation.unregistered", used[1].Name)
+	}
+
+	var m dto.Metric
+	err := paceDeprecatedAPIUsageTotal.With(prometheus.Labels{"api": "deprecation.deprecatedFunc", "removal": "v0.3.0"}).Write(&m)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, m.GetCounter().GetValue())
+
+	LogSummary()
+}
+
+func TestSkipFrame(t *testing.T) {
+	cases := []struct {
+		function, file string
+		skipped        bool
+	}{
+		{"github.com/pace/bricks/http/transport.(*RetryRoundTripper).RoundTrip", "/bricks/http/transport/retry_round_tripper.go", true},
+		{"example.com/service/vendor/github.com/pace/bricks/http/transport.(*RetryRoundTripper).RoundTrip", "/service/vendor/github.com/pace/bricks/http/transport/retry_round_tripper.go", true},
+		{"net/http.(*Client).do", "/usr/local/go/src/net/http/client.go", true},
+		{"runtime.goexit", "/usr/local/go/src/runtime/asm_amd64.s", true},
+		{"example.com/service/internal/payment.(*Client).Authorize", "/service/internal/payment/client.go", false},
+		{"github.com/pace/bricks/maintenance/deprecation.TestUse", "/bricks/maintenance/deprecation/deprecation_test.go", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.skipped, skipFrame(runtime.Frame{Function: c.function, File: c.file}), c.function)
+	}
+}
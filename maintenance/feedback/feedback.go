@@ -0,0 +1,142 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package feedback is a channel through which clients report the decode
+// and contract errors of the responses of a provider back to the provider.
+// The reports are sampled and rate limited on both sides and surface the
+// drift between producer and consumer as metrics of the provider, instead
+// of hiding it in the logs of the clients.
+package feedback
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Path of the feedback endpoint of the providers
+const Path = "/contract/feedback"
+
+// kinds of the reported errors
+const (
+	// KindDecode is an error decoding the response
+	KindDecode = "decode"
+	// KindContract is a response deviating from the specification
+	KindContract = "contract"
+)
+
+type config struct {
+	// Name of the client sending the reports, defaults to JAEGER_SERVICE_NAME
+	ClientName string `env:"FEEDBACK_CLIENT_NAME"`
+	// Providers (scheme://host) the reports are sent to
+	Providers []string `env:"FEEDBACK_PROVIDERS" envSeparator:","`
+	// Fraction of the errors that are reported
+	SampleRate float64 `env:"FEEDBACK_SAMPLE_RATE" envDefault:"0.1"`
+	// Reports per second sent to a provider
+	RateLimit float64 `env:"FEEDBACK_RATE_LIMIT" envDefault:"1"`
+	// Reports per second accepted by the provider from a client
+	AcceptLimit float64 `env:"FEEDBACK_ACCEPT_LIMIT" envDefault:"10"`
+	// Timeout of sending a report
+	Timeout time.Duration `env:"FEEDBACK_TIMEOUT" envDefault:"5s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the FEEDBACK_* environment of the handler and the
+// reporters, see configcheck. The handler responds 503 if it is malformed.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse feedback environment: %v", err)
+	}
+}
+
+var (
+	paceContractFeedbackTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("contract_feedback_total"),
+			Help: "Collects stats about the number of errors reported by the clients partitioned by client, operation and kind (decode, contract)",
+		},
+		[]string{"client", "operation", "kind"},
+	)
+	paceContractFeedbackReportsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("contract_feedback_reports_total"),
+			Help: "Collects stats about the number of errors reported to the providers partitioned by provider and result (sent, sampled, limited, failed, ignored)",
+		},
+		[]string{"provider", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceContractFeedbackTotal)
+	prometheus.MustRegister(paceContractFeedbackReportsTotal)
+	configcheck.Register("feedback", Setup)
+}
+
+// Report of an error of a client
+type Report struct {
+	// Kind of the error, KindDecode or KindContract
+	Kind string `json:"kind"`
+	// Operation of the specification, e.g. "GetOrder" (optional)
+	Operation string `json:"operation,omitempty"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Error     string `json:"error"`
+}
+
+// limiter is a token bucket allowing rate events per second
+// with a burst of one second
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64) *limiter {
+	return &limiter{rate: rate, tokens: burst(rate)}
+}
+
+func burst(rate float64) float64 {
+	if rate < 1 {
+		return 1
+	}
+	return rate
+}
+
+func (l *limiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return false
+	}
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if b := burst(l.rate); l.tokens > b {
+			l.tokens = b
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,150 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package feedback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func counterValue(t *testing.T, c *prometheus.CounterVec, labels prometheus.Labels) float64 {
+	var m dto.Metric
+	if err := c.With(labels).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+// testIntrospecter accepts every token as token of the client with the same name
+type testIntrospecter struct{}
+
+func (testIntrospecter) IntrospectToken(ctx context.Context, token string) (*oauth2.IntrospectResponse, error) {
+	return &oauth2.IntrospectResponse{Active: true, ClientID: token}, nil
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler()
+	post := func(body, client string) int {
+		req := httptest.NewRequest("POST", Path, strings.NewReader(body))
+		req.Header.Set("Request-Source", "spoofed")
+		if client != "" {
+			req.Header.Set("Authorization", "Bearer "+client)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// rejected without authentication
+	assert.Equal(t, http.StatusUnauthorized, post(`{"kind":"decode"}`, "billing"))
+	Authenticate(oauth2.NewMiddleware(testIntrospecter{}).Handler)
+	defer Authenticate(nil)
+	assert.Equal(t, http.StatusUnauthorized, post(`{"kind":"decode"}`, ""))
+
+	// the client is taken from the token
+	labels := prometheus.Labels{"client": "billing", "operation": "GetOrder", "kind": KindDecode}
+	before := counterValue(t, paceContractFeedbackTotal, labels)
+	assert.Equal(t, http.StatusNoContent, post(`{"kind":"decode","operation":"GetOrder","status":200}`, "billing"))
+	assert.Equal(t, before+1, counterValue(t, paceContractFeedbackTotal, labels))
+	assert.Equal(t, http.StatusBadRequest, post(`{"kind":"unknown"}`, "billing"))
+	assert.Equal(t, http.StatusBadRequest, post(`not json`, "billing"))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", Path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// rate limited per client
+	limited := false
+	for i := 0; i < 100 && !limited; i++ {
+		limited = post(`{"kind":"contract"}`, "billing") == http.StatusTooManyRequests
+	}
+	assert.True(t, limited)
+	assert.Equal(t, http.StatusNoContent, post(`{"kind":"contract"}`, "invoicing"))
+}
+
+func TestAcceptanceLabels(t *testing.T) {
+	a := &acceptance{series: make(map[[2]string]bool)}
+	client, operation := a.labels("", strings.Repeat("a", 100))
+	assert.Equal(t, "unknown", client)
+	assert.Len(t, operation, maxLabelLength)
+
+	for i := 0; i < maxSeries; i++ {
+		a.labels("billing", fmt.Sprintf("op%d", i))
+	}
+	client, operation = a.labels("billing", "new")
+	assert.Equal(t, "other", client)
+	assert.Equal(t, "other", operation)
+	client, operation = a.labels("billing", "op1")
+	assert.Equal(t, "billing", client)
+	assert.Equal(t, "op1", operation)
+}
+
+func TestReporter(t *testing.T) {
+	received := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Path + " " + r.Header.Get("Request-Source") + " " + r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	r := &Reporter{Name: "billing", Providers: []string{ts.URL}, SampleRate: 1, RateLimit: 1, Client: ts.Client()}
+	req, err := http.NewRequest("GET", ts.URL+"/orders/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	resp := &http.Response{StatusCode: http.StatusOK, Request: req}
+
+	assert.False(t, r.ReportResponse(resp, KindDecode, "GetOrder", nil))
+	assert.True(t, r.ReportResponse(resp, KindDecode, "GetOrder", errors.New("unexpected EOF")))
+	select {
+	case got := <-received:
+		assert.Equal(t, Path+" billing Bearer token", got)
+	case <-time.After(time.Second):
+		t.Fatal("Expected report to be sent")
+	}
+
+	// rate limited per provider
+	limited := prometheus.Labels{"provider": ts.URL, "result": resultLimited}
+	before := counterValue(t, paceContractFeedbackReportsTotal, limited)
+	assert.False(t, r.ReportResponse(resp, KindDecode, "GetOrder", errors.New("unexpected EOF")))
+	assert.Equal(t, before+1, counterValue(t, paceContractFeedbackReportsTotal, limited))
+
+	// only sent to the providers
+	ignored := prometheus.Labels{"provider": "other", "result": resultIgnored}
+	before = counterValue(t, paceContractFeedbackReportsTotal, ignored)
+	other, err := http.NewRequest("GET", "http://orders.example.com/orders/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, r.ReportResponse(&http.Response{StatusCode: http.StatusOK, Request: other}, KindDecode, "GetOrder", errors.New("unexpected EOF")))
+	assert.Equal(t, before+1, counterValue(t, paceContractFeedbackReportsTotal, ignored))
+
+	// sampled out
+	r.SampleRate = 0
+	assert.False(t, r.ReportResponse(resp, KindDecode, "GetOrder", errors.New("unexpected EOF")))
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(2)
+	assert.True(t, l.allow(now))
+	assert.True(t, l.allow(now))
+	assert.False(t, l.allow(now))
+	assert.True(t, l.allow(now.Add(500*time.Millisecond)))
+	assert.False(t, l.allow(now.Add(500*time.Millisecond)))
+
+	assert.False(t, newLimiter(0).allow(now))
+}
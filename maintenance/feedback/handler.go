@@ -0,0 +1,160 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package feedback
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxReportSize is the maximum size of a report in bytes
+	maxReportSize = 16 << 10
+	// maxSeries limits the label combinations of the clients and operations,
+	// further combinations are counted as "other"
+	maxSeries = 100
+	// maxLabelLength is the maximum length of a label value
+	maxLabelLength = 64
+	// maxErrorLength is the maximum length of the logged error of a report
+	maxErrorLength = 1 << 10
+)
+
+// acceptance keeps the rate limits of the clients and the known series
+// of the handler
+type acceptance struct {
+	mu       sync.Mutex
+	limiters map[string]*limiter
+	series   map[[2]string]bool
+}
+
+// limiter returns the rate limit of the client, further clients share
+// one limit once maxSeries is reached
+func (a *acceptance) limiter(client string) *limiter {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if l, ok := a.limiters[client]; ok {
+		return l
+	}
+	if len(a.limiters) >= maxSeries {
+		client = "other"
+		if l, ok := a.limiters[client]; ok {
+			return l
+		}
+	}
+	l := newLimiter(cfg.AcceptLimit)
+	a.limiters[client] = l
+	return l
+}
+
+// label returns the client and operation as metric labels, unknown
+// combinations are replaced once maxSeries is reached
+func (a *acceptance) labels(client, operation string) (string, string) {
+	key := [2]string{truncate(client), truncate(operation)}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.series[key] {
+		if len(a.series) >= maxSeries {
+			return "other", "other"
+		}
+		a.series[key] = true
+	}
+	return key[0], key[1]
+}
+
+func truncate(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	if len(s) > maxLabelLength {
+		return s[:maxLabelLength]
+	}
+	return s
+}
+
+var (
+	authMu       sync.RWMutex
+	authenticate func(http.Handler) http.Handler
+)
+
+// Authenticate sets the middleware authenticating the reports, usually
+// the oauth2 middleware of the service. The client is taken from the
+// oauth2 token, without middleware all reports are rejected with 401.
+func Authenticate(middleware func(http.Handler) http.Handler) {
+	authMu.Lock()
+	defer authMu.Unlock()
+	authenticate = middleware
+}
+
+// Handler returns the feedback endpoint of the provider, the Router mounts
+// it on Path. The reports are authenticated by the middleware passed to
+// Authenticate, counted and logged. Reports exceeding FEEDBACK_ACCEPT_LIMIT
+// of a client are rejected with 429. If the environment is malformed, it
+// responds 503 Service Unavailable.
+func Handler() http.Handler {
+	a := &acceptance{limiters: make(map[string]*limiter), series: make(map[[2]string]bool)}
+	accept := a.handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Setup(); err != nil {
+			http.Error(w, "feedback not available", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		authMu.RLock()
+		middleware := authenticate
+		authMu.RUnlock()
+		if middleware == nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		middleware(accept).ServeHTTP(w, r)
+	})
+}
+
+// handler accepts the reports of authenticated clients
+func (a *acceptance) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, ok := oauth2.ClientID(r.Context())
+		if !ok || clientID == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !a.limiter(clientID).allow(time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many reports", http.StatusTooManyRequests)
+			return
+		}
+		var report Report
+		err := json.NewDecoder(io.LimitReader(r.Body, maxReportSize)).Decode(&report)
+		if err != nil || (report.Kind != KindDecode && report.Kind != KindContract) {
+			http.Error(w, "invalid report", http.StatusBadRequest)
+			return
+		}
+
+		client, operation := a.labels(clientID, report.Operation)
+		paceContractFeedbackTotal.With(prometheus.Labels{
+			"client":    client,
+			"operation": operation,
+			"kind":      report.Kind,
+		}).Inc()
+		reportErr := report.Error
+		if len(reportErr) > maxErrorLength {
+			reportErr = reportErr[:maxErrorLength]
+		}
+		log.Req(r).Warn().Str("client", clientID).Str("kind", report.Kind).Str("operation", report.Operation).
+			Str("method", report.Method).Str("path", report.Path).Int("status", report.Status).
+			Str("error", reportErr).Msg("Client reported error of a response")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
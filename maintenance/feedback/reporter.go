@@ -0,0 +1,208 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package feedback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of the reports, used as metric label
+const (
+	resultSent    = "sent"
+	resultSampled = "sampled"
+	resultLimited = "limited"
+	resultFailed  = "failed"
+	resultIgnored = "ignored"
+)
+
+// Reporter sends the reports of a client to the feedback endpoints of
+// the providers. The reports are sampled, rate limited per provider and
+// sent in the background, so that reporting never slows down the client.
+// The reports are authenticated with the bearer token of the request.
+type Reporter struct {
+	// Name of the client, sent as Request-Source header
+	Name string
+	// Providers the reports are sent to (scheme://host), the reports
+	// of other providers are ignored
+	Providers []string
+	// SampleRate is the fraction of the errors that are reported
+	SampleRate float64
+	// RateLimit is the number of reports per second sent to a provider
+	RateLimit float64
+	// Client sends the reports, it is not instrumented to not report
+	// the errors of the reports
+	Client *http.Client
+
+	mu       sync.Mutex
+	limiters map[string]*limiter
+}
+
+// NewReporter returns a reporter configured by the environment
+func NewReporter() *Reporter {
+	mustSetup()
+	name := cfg.ClientName
+	if name == "" {
+		name = os.Getenv("JAEGER_SERVICE_NAME")
+	}
+	return &Reporter{
+		Name:       name,
+		Providers:  cfg.Providers,
+		SampleRate: cfg.SampleRate,
+		RateLimit:  cfg.RateLimit,
+		Client:     &http.Client{Timeout: cfg.Timeout},
+		limiters:   make(map[string]*limiter),
+	}
+}
+
+var (
+	defaultReporter     *Reporter
+	defaultReporterOnce sync.Once
+)
+
+// DefaultReporter returns the reporter of the environment
+func DefaultReporter() *Reporter {
+	defaultReporterOnce.Do(func() {
+		defaultReporter = NewReporter()
+	})
+	return defaultReporter
+}
+
+// ReportResponse reports the error of the response using the default
+// reporter, the provider, method, path and status are taken from the
+// response. Generated clients call it for responses they fail to decode:
+//
+//	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+//		feedback.ReportResponse(resp, feedback.KindDecode, "GetOrder", err)
+//		return nil, err
+//	}
+func ReportResponse(resp *http.Response, kind, operation string, err error) bool {
+	return DefaultReporter().ReportResponse(resp, kind, operation, err)
+}
+
+// ReportResponse reports the error of the response, see Report. The
+// report is authenticated with the bearer token of the request.
+func (r *Reporter) ReportResponse(resp *http.Response, kind, operation string, err error) bool {
+	if resp == nil || resp.Request == nil || err == nil {
+		return false
+	}
+	req := resp.Request
+	ctx := req.Context()
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		ctx = oauth2.WithBearerToken(ctx, strings.TrimPrefix(auth, "Bearer "))
+	}
+	provider := req.URL.Scheme + "://" + req.URL.Host
+	return r.Report(ctx, provider, Report{
+		Kind:      kind,
+		Operation: operation,
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Status:    resp.StatusCode,
+		Error:     err.Error(),
+	})
+}
+
+// Report sends the report to the feedback endpoint of the provider
+// (scheme://host) in the background, authenticated with the bearer token
+// of ctx. It returns false if the provider isn't one of the Providers or
+// if the report was sampled out or rate limited.
+func (r *Reporter) Report(ctx context.Context, provider string, report Report) bool {
+	if !r.allowed(provider) {
+		// the provider isn't used as label, the label values stay bounded
+		paceContractFeedbackReportsTotal.With(prometheus.Labels{"provider": "other", "result": resultIgnored}).Inc()
+		return false
+	}
+	if r.SampleRate <= 0 || rand.Float64() >= r.SampleRate { // nolint: gosec
+		paceContractFeedbackReportsTotal.With(prometheus.Labels{"provider": provider, "result": resultSampled}).Inc()
+		return false
+	}
+	if !r.limiter(provider).allow(time.Now()) {
+		paceContractFeedbackReportsTotal.With(prometheus.Labels{"provider": provider, "result": resultLimited}).Inc()
+		return false
+	}
+
+	logger := log.Ctx(ctx)
+	token, _ := oauth2.BearerToken(ctx)
+	go func() {
+		result := resultSent
+		if err := r.send(provider, token, report); err != nil {
+			result = resultFailed
+			logger.Debug().Err(err).Str("provider", provider).Msg("Failed to report error to the provider")
+		}
+		paceContractFeedbackReportsTotal.With(prometheus.Labels{"provider": provider, "result": result}).Inc()
+	}()
+	return true
+}
+
+func (r *Reporter) allowed(provider string) bool {
+	for _, p := range r.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reporter) send(provider, token string, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, provider+Path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Request-Source", r.Name)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusNoContent {
+		return &statusError{status: resp.StatusCode}
+	}
+	return nil
+}
+
+func (r *Reporter) limiter(provider string) *limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.limiters == nil {
+		r.limiters = make(map[string]*limiter)
+	}
+	l, ok := r.limiters[provider]
+	if !ok {
+		l = newLimiter(r.RateLimit)
+		r.limiters[provider] = l
+	}
+	return l
+}
+
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return "feedback: provider responded " + http.StatusText(e.status)
+}
@@ -0,0 +1,180 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHealthDegraded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("health_degraded"),
+			Help: "Is 1 while the capability is degraded because an optional backend failed",
+		},
+		[]string{"capability"},
+	)
+	paceHealthDegradedSecondsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("health_degraded_seconds_total"),
+			Help: "Collects the time a capability was degraded because an optional backend failed",
+		},
+		[]string{"capability"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHealthDegraded)
+	prometheus.MustRegister(paceHealthDegradedSecondsTotal)
+}
+
+// capability is the state of a feature that depends on optional backends
+type capability struct {
+	err   error     // nil if available
+	since time.Time // last update of the degraded time
+}
+
+var (
+	// optional maps the names of optional checks to the
+	// capabilities that depend on them
+	optional = make(map[string][]string)
+
+	capabilitiesMu sync.RWMutex
+	capabilities   = make(map[string]*capability)
+)
+
+// RegisterOptionalCheck adds a readiness check of an optional backend. If
+// the check fails, the service stays ready and the passed capabilities
+// (the features depending on the backend) are degraded until the check
+// succeeds again, see Degraded.
+func RegisterOptionalCheck(name string, check Check, capabilityNames ...string) {
+	checksMu.Lock()
+	checks[name] = check
+	optional[name] = capabilityNames
+	checksMu.Unlock()
+
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	for _, c := range capabilityNames {
+		if _, ok := capabilities[c]; !ok {
+			capabilities[c] = &capability{}
+			paceHealthDegraded.With(prometheus.Labels{"capability": c}).Set(0)
+		}
+	}
+}
+
+// IsOptional returns true if the check was registered
+// using RegisterOptionalCheck
+func IsOptional(name string) bool {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	_, ok := optional[name]
+	return ok
+}
+
+// Degraded returns the reason if the capability is degraded, nil if it is
+// available or unknown. Handlers use it to skip or replace the features
+// depending on optional backends:
+//
+//	if err := health.Degraded("recommendations"); err != nil {
+//		return nil // respond without recommendations
+//	}
+func Degraded(name string) error {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+	if c, ok := capabilities[name]; ok {
+		return c.err
+	}
+	return nil
+}
+
+// Capabilities returns the reasons of all degraded capabilities by name
+func Capabilities() map[string]error {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+	degraded := make(map[string]error)
+	for name, c := range capabilities {
+		if c.err != nil {
+			degraded[name] = c.err
+		}
+	}
+	return degraded
+}
+
+// MarkDegraded degrades the capability, e.g. if a request to an optional
+// backend failed. It is available again with the next successful readiness
+// check of its backends or MarkAvailable.
+func MarkDegraded(ctx context.Context, name string, err error) {
+	setCapability(ctx, name, err)
+}
+
+// MarkAvailable marks the capability as available
+func MarkAvailable(ctx context.Context, name string) {
+	setCapability(ctx, name, nil)
+}
+
+// updateCapabilities degrades the capabilities of the failed optional
+// checks and marks all others as available
+func updateCapabilities(ctx context.Context, failed map[string]error) {
+	checksMu.RLock()
+	reasons := make(map[string][]string)
+	for name, capabilityNames := range optional {
+		for _, c := range capabilityNames {
+			if _, ok := reasons[c]; !ok {
+				reasons[c] = nil
+			}
+			if err, ok := failed[name]; ok {
+				reasons[c] = append(reasons[c], fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+	checksMu.RUnlock()
+
+	for c, r := range reasons {
+		if len(r) == 0 {
+			setCapability(ctx, c, nil)
+			continue
+		}
+		sort.Strings(r)
+		setCapability(ctx, c, errors.New(strings.Join(r, "; ")))
+	}
+}
+
+// setCapability updates the state of the capability, the transitions
+// are logged and the degraded time is collected
+func setCapability(ctx context.Context, name string, err error) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	now := time.Now()
+	c, ok := capabilities[name]
+	if !ok {
+		c = &capability{}
+		capabilities[name] = c
+	}
+	if c.err != nil {
+		paceHealthDegradedSecondsTotal.With(prometheus.Labels{"capability": name}).Add(now.Sub(c.since).Seconds())
+	}
+
+	switch {
+	case err != nil && c.err == nil:
+		log.Ctx(ctx).Warn().Err(err).Str("capability", name).Msg("Capability degraded")
+		paceHealthDegraded.With(prometheus.Labels{"capability": name}).Set(1)
+	case err == nil && c.err != nil:
+		log.Ctx(ctx).Info().Str("capability", name).Msg("Capability available again")
+		paceHealthDegraded.With(prometheus.Labels{"capability": name}).Set(0)
+	}
+	c.err = err
+	c.since = now
+}
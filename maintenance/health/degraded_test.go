@@ -0,0 +1,78 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadinessHandlerDegraded(t *testing.T) {
+	defer func() {
+		checks = make(map[string]Check)
+		optional = make(map[string][]string)
+		capabilities = make(map[string]*capability)
+	}()
+
+	var searchErr error
+	RegisterCheck("postgres", func(ctx context.Context) error { return nil })
+	RegisterOptionalCheck("elasticsearch", func(ctx context.Context) error { return searchErr }, "search", "suggestions")
+
+	ready := func(code int, body string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		ReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health/ready", nil))
+		if rec.Code != code || rec.Body.String() != body {
+			t.Errorf("Expected %d %q, got: %d %q", code, body, rec.Code, rec.Body.String())
+		}
+	}
+
+	ready(200, "OK\n")
+	if err := Degraded("search"); err != nil {
+		t.Errorf("Expected search to be available, got: %v", err)
+	}
+
+	// the optional backend fails, the service stays ready
+	searchErr = errors.New("connection refused")
+	ready(200, "DEGRADED\nelasticsearch: connection refused\n")
+	for _, c := range []string{"search", "suggestions"} {
+		if err := Degraded(c); err == nil || err.Error() != "elasticsearch: connection refused" {
+			t.Errorf("Expected %s to be degraded, got: %v", c, err)
+		}
+	}
+	if len(Capabilities()) != 2 {
+		t.Errorf("Expected two degraded capabilities, got: %v", Capabilities())
+	}
+
+	// required backends still fail the readiness
+	RegisterCheck("postgres", func(ctx context.Context) error { return errors.New("timeout") })
+	ready(503, "elasticsearch: connection refused\npostgres: timeout\n")
+
+	// recovered
+	RegisterCheck("postgres", func(ctx context.Context) error { return nil })
+	searchErr = nil
+	ready(200, "OK\n")
+	if len(Capabilities()) != 0 {
+		t.Errorf("Expected no degraded capabilities, got: %v", Capabilities())
+	}
+}
+
+func TestMarkDegraded(t *testing.T) {
+	defer func() { capabilities = make(map[string]*capability) }()
+	ctx := context.Background()
+
+	if Degraded("unknown") != nil {
+		t.Error("Expected unknown capability to be available")
+	}
+	MarkDegraded(ctx, "recommendations", errors.New("timeout"))
+	if err := Degraded("recommendations"); err == nil || err.Error() != "timeout" {
+		t.Errorf("Expected recommendations to be degraded, got: %v", err)
+	}
+	MarkAvailable(ctx, "recommendations")
+	if err := Degraded("recommendations"); err != nil {
+		t.Errorf("Expected recommendations to be available, got: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package grpchealth serves the readiness checks of the health package
+// using the gRPC health checking protocol (grpc.health.v1), so that the
+// serving status of gRPC servers follows the readiness endpoint of the
+// HTTP server. The protocol is implemented by the grpc driver of the
+// service, which adapts the Server to its grpc_health_v1.HealthServer.
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+type config struct {
+	// Register the reflection service on gRPC servers
+	Reflection bool `env:"GRPC_REFLECTION" envDefault:"false"`
+	// Interval in which the readiness checks are executed for watchers
+	WatchInterval time.Duration `env:"GRPC_HEALTH_WATCH_INTERVAL" envDefault:"5s"`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses the GRPC_* environment of the health server, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse gRPC health environment: %v", err)
+	}
+}
+
+func init() {
+	configcheck.Register("grpc", Setup)
+}
+
+// ReflectionEnabled returns true if GRPC_REFLECTION is set, services
+// register the reflection service (e.g. reflection.Register of grpc-go)
+// only then
+func ReflectionEnabled() bool {
+	mustSetup()
+	return cfg.Reflection
+}
+
+// Status is the serving status, the values equal the values
+// of grpc_health_v1.HealthCheckResponse_ServingStatus
+type Status int32
+
+// Serving statuses
+const (
+	StatusUnknown        Status = 0
+	StatusServing        Status = 1
+	StatusNotServing     Status = 2
+	StatusServiceUnknown Status = 3 // only used by Watch
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusServing:
+		return "SERVING"
+	case StatusNotServing:
+		return "NOT_SERVING"
+	case StatusServiceUnknown:
+		return "SERVICE_UNKNOWN"
+	}
+	return "UNKNOWN"
+}
+
+// ErrUnknownService is returned by Check for services that are not
+// registered, the adapter responds with codes.NotFound
+var ErrUnknownService = errors.New("grpchealth: unknown service")
+
+// Server determines the serving status of the server (empty service name)
+// and of the registered services using the readiness checks
+type Server struct {
+	mu       sync.RWMutex
+	services map[string][]string
+	shutdown bool
+}
+
+// NewServer returns a server, the status of the server follows all
+// readiness checks. Services need to be registered with RegisterService.
+func NewServer() *Server {
+	mustSetup()
+	return &Server{services: make(map[string][]string)}
+}
+
+// RegisterService registers the fully qualified gRPC service name (e.g.
+// "payment.v1.PaymentService"). The status of the service follows the
+// passed readiness checks, or all readiness checks if none are passed.
+func (s *Server) RegisterService(name string, checks ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services[name] = checks
+}
+
+// Shutdown sets the status of the server and all services to
+// not serving, e.g. while the server is gracefully stopped
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = true
+}
+
+// Check returns the serving status of the service. Like the readiness
+// endpoint, failed optional checks don't affect the status of the server
+// and of services without explicit checks.
+func (s *Server) Check(ctx context.Context, service string) (Status, error) {
+	s.mu.RLock()
+	checks, ok := s.services[service]
+	shutdown := s.shutdown
+	s.mu.RUnlock()
+	if !ok && service != "" {
+		return StatusServiceUnknown, ErrUnknownService
+	}
+	if shutdown {
+		return StatusNotServing, nil
+	}
+
+	if len(checks) == 0 {
+		if health.Ready(ctx) {
+			return StatusServing, nil
+		}
+		return StatusNotServing, nil
+	}
+
+	failed := health.CheckReadiness(ctx)
+	for _, name := range checks {
+		if _, ok := failed[name]; ok {
+			return StatusNotServing, nil
+		}
+	}
+	return StatusServing, nil
+}
+
+// Watch sends the serving status of the service and every change of it
+// until ctx is done or send returns an error. Unknown services are sent
+// as StatusServiceUnknown, they are served once they are registered.
+func (s *Server) Watch(ctx context.Context, service string, send func(Status) error) error {
+	ticker := time.NewTicker(cfg.WatchInterval)
+	defer ticker.Stop()
+
+	last := Status(-1)
+	for {
+		status, _ := s.Check(ctx, service) // nolint: errcheck
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if status != last {
+			if err := send(status); err != nil {
+				return err
+			}
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
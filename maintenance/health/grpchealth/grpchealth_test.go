@@ -0,0 +1,80 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		dbError error
+	)
+	health.RegisterCheck("grpchealth-db", func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return dbError
+	})
+	health.RegisterOptionalCheck("grpchealth-search", func(ctx context.Context) error {
+		return errors.New("unavailable")
+	}, "search")
+
+	s := NewServer()
+	s.RegisterService("test.v1.Orders")
+	s.RegisterService("test.v1.Search", "grpchealth-search")
+	ctx := context.Background()
+
+	status, err := s.Check(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusServing, status)
+	status, _ = s.Check(ctx, "test.v1.Orders") // nolint: errcheck
+	assert.Equal(t, StatusServing, status)
+	status, _ = s.Check(ctx, "test.v1.Search") // nolint: errcheck
+	assert.Equal(t, StatusNotServing, status)
+	status, err = s.Check(ctx, "test.v1.Unknown")
+	assert.Equal(t, ErrUnknownService, err)
+	assert.Equal(t, StatusServiceUnknown, status)
+
+	mu.Lock()
+	dbError = errors.New("connection refused")
+	mu.Unlock()
+	status, _ = s.Check(ctx, "") // nolint: errcheck
+	assert.Equal(t, StatusNotServing, status)
+
+	mu.Lock()
+	dbError = nil
+	mu.Unlock()
+	s.Shutdown()
+	status, _ = s.Check(ctx, "test.v1.Orders") // nolint: errcheck
+	assert.Equal(t, StatusNotServing, status)
+}
+
+func TestWatch(t *testing.T) {
+	defer func(interval time.Duration) { cfg.WatchInterval = interval }(cfg.WatchInterval)
+	s := NewServer()
+	cfg.WatchInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var sent []Status
+	err := s.Watch(ctx, "test.v1.Later", func(status Status) error {
+		sent = append(sent, status)
+		switch len(sent) {
+		case 1:
+			s.RegisterService("test.v1.Later")
+		case 2:
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []Status{StatusServiceUnknown, StatusServing}, sent)
+}
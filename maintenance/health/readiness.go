@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// readinessTimeout is the maximum duration of all readiness checks
+const readinessTimeout = 5 * time.Second
+
+// Check returns an error if the service is not ready, e.g. because
+// a backend is not available or not compatible
+type Check func(ctx context.Context) error
+
+var (
+	checksMu sync.RWMutex
+	checks   = make(map[string]Check)
+)
+
+// RegisterCheck adds a readiness check with the passed name, an existing
+// check with the same name is replaced
+func RegisterCheck(name string, check Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks[name] = check
+	delete(optional, name)
+}
+
+// Checks returns the sorted names of all readiness checks
+func Checks() []string {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckReadiness executes all readiness checks and returns the
+// errors of the failed checks by name
+func CheckReadiness(ctx context.Context) map[string]error {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = make(map[string]error)
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			if err := check(ctx); err != nil {
+				mu.Lock()
+				failed[name] = err
+				mu.Unlock()
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// Ready executes all readiness checks and returns true if no check
+// failed or only optional checks failed, like the readiness endpoint
+func Ready(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	failed := CheckReadiness(ctx)
+	updateCapabilities(ctx, failed)
+	return onlyOptional(failed)
+}
+
+// onlyOptional returns true if all failed checks are optional
+func onlyOptional(failed map[string]error) bool {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	for name := range failed {
+		if _, ok := optional[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type readinessHandler struct{}
+
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	failed := CheckReadiness(ctx)
+	updateCapabilities(r.Context(), failed)
+	w.Header().Set("Content-Type", "text/plain")
+	if len(failed) == 0 {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK\n"[:])) // nolint: gosec,errcheck
+		return
+	}
+
+	names := make([]string, 0, len(failed))
+	for name := range failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if onlyOptional(failed) {
+		// only optional checks failed
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("DEGRADED\n"[:])) // nolint: gosec,errcheck
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	for _, name := range names {
+		log.Req(r).Warn().Err(failed[name]).Str("check", name).Msg("Readiness check failed")
+		fmt.Fprintf(w, "%s: %v\n", name, failed[name]) // nolint: errcheck
+	}
+}
+
+// ReadinessHandler returns the readiness api endpoint, it responds
+// with 503 and the errors of the failed checks if any check failed.
+// If only optional checks failed (see RegisterOptionalCheck), it
+// responds with 200 and the errors of the degraded backends.
+func ReadinessHandler() http.Handler {
+	return &readinessHandler{}
+}
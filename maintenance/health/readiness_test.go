@@ -0,0 +1,31 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	defer func() { checks = make(map[string]Check) }()
+
+	RegisterCheck("postgres", func(ctx context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	ReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health/ready", nil))
+	if rec.Code != 200 || rec.Body.String() != "OK\n" {
+		t.Errorf("Expected service to be ready, got: %d %q", rec.Code, rec.Body.String())
+	}
+
+	RegisterCheck("redis", func(ctx context.Context) error { return errors.New("redis 3.2.1 is older than 5.0") })
+
+	rec = httptest.NewRecorder()
+	ReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health/ready", nil))
+	if rec.Code != 503 || rec.Body.String() != "redis: redis 3.2.1 is older than 5.0\n" {
+		t.Errorf("Expected service not to be ready, got: %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package jobs
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// defaultLimit is the number of failed jobs and runs shown per
+// queue and scheduler, can be changed with the limit parameter
+const defaultLimit = 20
+
+// Overview is the state of all registered queues and schedulers
+type Overview struct {
+	Queues     []QueueOverview     `json:"queues"`
+	Schedulers []SchedulerOverview `json:"schedulers"`
+}
+
+// QueueOverview is the state of a queue
+type QueueOverview struct {
+	Name string `json:"name"`
+	QueueStats
+	FailedJobs []FailedJob `json:"failedJobs"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// SchedulerOverview is the state of a scheduler
+type SchedulerOverview struct {
+	Name  string `json:"name"`
+	Runs  []Run  `json:"runs"`
+	Error string `json:"error,omitempty"`
+}
+
+// Handler returns the dashboard, it is mounted on /debug/jobs/ by the
+// default router if DEBUG_ENDPOINTS are enabled, the requests need the
+// DEBUG_TOKEN (paths are relative to the mount point). The overview is
+// returned as HTML or as JSON if requested with "Accept: application/json". Failed jobs are retried
+// with POST /queues/{queue}/failed/{id}/retry.
+func Handler() http.Handler {
+	r := mux.NewRouter()
+	r.Methods("GET").Path("/").HandlerFunc(overviewHandler)
+	r.Methods("POST").Path("/queues/{queue}/failed/{id}/retry").HandlerFunc(retryHandler)
+	return r
+}
+
+func overviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit := defaultLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	var overview Overview
+	qs, ss := registered()
+	for _, q := range qs {
+		qo := QueueOverview{Name: q.Name()}
+		stats, err := q.Stats(ctx)
+		if err == nil {
+			qo.QueueStats = stats
+			qo.FailedJobs, err = q.Failed(ctx, limit)
+		}
+		if err != nil {
+			qo.Error = err.Error()
+		}
+		overview.Queues = append(overview.Queues, qo)
+	}
+	for _, s := range ss {
+		so := SchedulerOverview{Name: s.Name()}
+		runs, err := s.Runs(ctx, limit)
+		if err != nil {
+			so.Error = err.Error()
+		}
+		so.Runs = runs
+		overview.Schedulers = append(overview.Schedulers, so)
+	}
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(overview) // nolint: errcheck
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := dashboardTemplate.Execute(w, overview)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to render job dashboard")
+	}
+}
+
+func retryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	q := queue(vars["queue"])
+	if q == nil {
+		http.Error(w, "queue not found", http.StatusNotFound)
+		return
+	}
+
+	err := q.Retry(r.Context(), vars["id"])
+	if err == ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Ctx(r.Context()).Info().Str("queue", vars["queue"]).Str("job", vars["id"]).Msg("Failed job retried")
+
+	if wantsJSON(r) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	// back to the overview
+	http.Redirect(w, r, "../../../../", http.StatusSeeOther)
+}
+
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Jobs</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Queues</h1>
+{{range .Queues}}
+<h2>{{.Name}}</h2>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<table>
+<tr><th>Depth</th><th>In-flight</th><th>Failed</th></tr>
+<tr><td>{{.Depth}}</td><td>{{.InFlight}}</td><td>{{.Failed}}</td></tr>
+</table>
+{{if .FailedJobs}}
+<table>
+<tr><th>ID</th><th>Job</th><th>Failed at</th><th>Attempts</th><th>Error</th><th></th></tr>
+{{$queue := .Name}}
+{{range .FailedJobs}}
+<tr>
+<td>{{.ID}}</td><td>{{.Name}}</td><td>{{.FailedAt.Format "2006-01-02 15:04:05"}}</td><td>{{.Attempts}}</td>
+<td class="error">{{.Error}}</td>
+<td><form method="post" action="queues/{{$queue}}/failed/{{.ID}}/retry"><button>Retry</button></form></td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{else}}
+<p>No queues registered</p>
+{{end}}
+<h1>Schedulers</h1>
+{{range .Schedulers}}
+<h2>{{.Name}}</h2>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<table>
+<tr><th>Job</th><th>Start</th><th>Duration</th><th>Error</th></tr>
+{{range .Runs}}
+<tr><td>{{.Job}}</td><td>{{.Start.Format "2006-01-02 15:04:05"}}</td><td>{{.Duration}}</td><td class="error">{{.Error}}</td></tr>
+{{end}}
+</table>
+{{else}}
+<p>No schedulers registered</p>
+{{end}}
+</body>
+</html>
+`))
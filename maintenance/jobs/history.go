@@ -0,0 +1,70 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// History is a Scheduler that keeps the last runs in memory, schedulers
+// that don't persist their runs record them in a history
+type History struct {
+	name string
+	size int
+
+	mu   sync.Mutex
+	runs []Run // ring buffer
+	next int
+}
+
+// NewHistory creates a history of the scheduler with the passed name
+// that keeps the last size runs
+func NewHistory(name string, size int) *History {
+	return &History{name: name, size: size}
+}
+
+// Name of the scheduler
+func (h *History) Name() string {
+	return h.name
+}
+
+// Record adds the run of job that started at start and failed with err
+// (nil if successful)
+func (h *History) Record(job string, start time.Time, err error) {
+	run := Run{Job: job, Start: start, Duration: time.Since(start)}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.size <= 0 {
+		return
+	}
+	if len(h.runs) < h.size {
+		h.runs = append(h.runs, run)
+		return
+	}
+	h.runs[h.next] = run
+	h.next = (h.next + 1) % h.size
+}
+
+// Runs returns up to limit runs, the most recent first
+func (h *History) Runs(ctx context.Context, limit int) ([]Run, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := len(h.runs)
+	if limit > n {
+		limit = n
+	}
+	runs := make([]Run, limit)
+	for i := range runs {
+		// the most recent run is before next
+		runs[i] = h.runs[(h.next-1-i+2*n)%n]
+	}
+	return runs, nil
+}
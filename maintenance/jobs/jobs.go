@@ -0,0 +1,101 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package jobs implements a dashboard for the background processing of
+// a service. Queues and schedulers register themselves, the dashboard
+// shows queue depths, in-flight and failed jobs (with retries) and the
+// run history of the schedulers without direct access to redis or the
+// database.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Queue.Retry if the failed job doesn't exist
+var ErrNotFound = errors.New("job not found")
+
+// QueueStats are the current numbers of jobs of a queue
+type QueueStats struct {
+	Depth    int `json:"depth"`    // jobs waiting to be processed
+	InFlight int `json:"inFlight"` // jobs currently processed
+	Failed   int `json:"failed"`   // jobs that failed permanently
+}
+
+// FailedJob is a job that failed permanently and can be retried
+type FailedJob struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// Queue is a job queue shown by the dashboard
+type Queue interface {
+	// Name of the queue
+	Name() string
+	// Stats returns the current numbers of jobs
+	Stats(ctx context.Context) (QueueStats, error)
+	// Failed returns up to limit failed jobs, the most recent first
+	Failed(ctx context.Context, limit int) ([]FailedJob, error)
+	// Retry enqueues the failed job with the passed id again
+	Retry(ctx context.Context, id string) error
+}
+
+// Run is a single execution of a scheduled job
+type Run struct {
+	Job      string        `json:"job"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Scheduler executes jobs periodically and keeps the history of the runs
+type Scheduler interface {
+	// Name of the scheduler
+	Name() string
+	// Runs returns up to limit runs, the most recent first
+	Runs(ctx context.Context, limit int) ([]Run, error)
+}
+
+var (
+	mu         sync.RWMutex
+	queues     []Queue
+	schedulers []Scheduler
+)
+
+// RegisterQueue adds the queue to the dashboard
+func RegisterQueue(q Queue) {
+	mu.Lock()
+	defer mu.Unlock()
+	queues = append(queues, q)
+}
+
+// RegisterScheduler adds the scheduler to the dashboard
+func RegisterScheduler(s Scheduler) {
+	mu.Lock()
+	defer mu.Unlock()
+	schedulers = append(schedulers, s)
+}
+
+func registered() ([]Queue, []Scheduler) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Queue(nil), queues...), append([]Scheduler(nil), schedulers...)
+}
+
+// queue returns the registered queue with the passed name or nil
+func queue(name string) Queue {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, q := range queues {
+		if q.Name() == name {
+			return q
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testQueue struct {
+	failed  []FailedJob
+	retried []string
+}
+
+func (q *testQueue) Name() string { return "mails" }
+
+func (q *testQueue) Stats(ctx context.Context) (QueueStats, error) {
+	return QueueStats{Depth: 3, InFlight: 1, Failed: len(q.failed)}, nil
+}
+
+func (q *testQueue) Failed(ctx context.Context, limit int) ([]FailedJob, error) {
+	return q.failed, nil
+}
+
+func (q *testQueue) Retry(ctx context.Context, id string) error {
+	for _, job := range q.failed {
+		if job.ID == id {
+			q.retried = append(q.retried, id)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func TestDashboard(t *testing.T) {
+	q := &testQueue{failed: []FailedJob{{ID: "42", Name: "welcome", Error: "smtp unavailable", Attempts: 5}}}
+	RegisterQueue(q)
+	h := NewHistory("cron", 10)
+	h.Record("cleanup", time.Now(), errors.New("timeout"))
+	RegisterScheduler(h)
+
+	// json
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/json")
+	Handler().ServeHTTP(rec, req)
+
+	var overview Overview
+	if err := json.NewDecoder(rec.Body).Decode(&overview); err != nil {
+		t.Fatal(err)
+	}
+	if len(overview.Queues) != 1 || overview.Queues[0].Depth != 3 || len(overview.Queues[0].FailedJobs) != 1 {
+		t.Errorf("unexpected queues: %#v", overview.Queues)
+	}
+	if len(overview.Schedulers) != 1 || overview.Schedulers[0].Runs[0].Error != "timeout" {
+		t.Errorf("unexpected schedulers: %#v", overview.Schedulers)
+	}
+
+	// html
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "smtp unavailable") || !strings.Contains(body, "queues/mails/failed/42/retry") {
+		t.Errorf("expected failed job with retry button, got: %s", body)
+	}
+
+	// retry
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/queues/mails/failed/42/retry", nil))
+	if rec.Code != http.StatusSeeOther || len(q.retried) != 1 {
+		t.Errorf("expected job to be retried, got %d %v", rec.Code, q.retried)
+	}
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/queues/mails/failed/43/retry", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown job, got %d", rec.Code)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory("cron", 2)
+	for _, job := range []string{"a", "b", "c"} {
+		h.Record(job, time.Now(), nil)
+	}
+
+	runs, err := h.Runs(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Job != "c" || runs[1].Job != "b" {
+		t.Errorf("expected the last two runs, most recent first, got %#v", runs)
+	}
+}
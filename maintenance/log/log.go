@@ -5,14 +5,17 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/profile"
 	"github.com/rs/zerolog/hlog"
 
 	"github.com/rs/zerolog"
@@ -24,6 +27,9 @@ import (
 type config struct {
 	LogLevel string `env:"LOG_LEVEL" envDefault:"debug"`
 	Format   string `env:"LOG_FORMAT" envDefault:"auto"`
+	// Number of recent log entries kept in memory (e.g. for support
+	// bundles), 0 disables it
+	RecentEntries int `env:"LOG_RECENT_ENTRIES" envDefault:"1000"`
 }
 
 // map to translate the string log level
@@ -37,20 +43,62 @@ var levelMap = map[string]zerolog.Level{
 	"disabled": zerolog.Disabled,
 }
 
-var cfg config
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup parses and validates the LOG_* environment, see configcheck. It
+// is called when the package is initialized, the process exits if the
+// environment is malformed unless the configuration is checked.
+func Setup() error {
+	cfgOnce.Do(func() {
+		var c config
+		errSetup = env.Parse(&c)
+		if errSetup != nil {
+			return
+		}
+		if _, ok := levelMap[strings.ToLower(c.LogLevel)]; !ok {
+			errSetup = fmt.Errorf("unknown log level: %q", c.LogLevel)
+			return
+		}
+		cfg = c
+	})
+	return errSetup
+}
+
+// checkingConfig returns true if the process was started with
+// --check-config, same as configcheck.Requested which can't be
+// imported because configcheck depends on this package
+func checkingConfig() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == "--check-config" {
+			return true
+		}
+	}
+	return false
+}
 
 func init() {
-	// parse log config
-	err := env.Parse(&cfg)
-	if err != nil {
-		Fatalf("Failed to parse server environment: %v", err)
+	// apply the profile defaults before any package parses the environment,
+	// the configuration check reports unknown profiles
+	errProfile := profile.Setup()
+	if errProfile != nil && !checkingConfig() {
+		Fatalf("Failed to apply environment profile: %v", errProfile)
 	}
 
-	// translate log level
-	v, ok := levelMap[strings.ToLower(cfg.LogLevel)]
-	if !ok {
-		Fatalf("Unknown log level: %q", cfg.LogLevel)
+	// parse log config, the configuration check reports malformed
+	// environments, the defaults are used until then
+	if err := Setup(); err != nil {
+		if !checkingConfig() {
+			Fatalf("Failed to parse log environment: %v", err)
+		}
+		cfg = config{LogLevel: "debug", Format: "auto"}
 	}
+
+	// translate log level
+	v := levelMap[strings.ToLower(cfg.LogLevel)]
 	zerolog.SetGlobalLevel(v)
 	log.Logger = log.Logger.Level(v)
 
@@ -67,14 +115,29 @@ func init() {
 		}
 	}
 
+	// keep recent entries as json, independent of the format
+	var out io.Writer = zerolog.ConsoleWriter{Out: os.Stdout}
+	if cfg.Format == "json" {
+		out = os.Stdout
+	}
+	if cfg.RecentEntries > 0 {
+		recent = newRingBuffer(cfg.RecentEntries)
+		out = zerolog.MultiLevelWriter(out, recent)
+	}
+
 	switch cfg.Format {
 	case "console":
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout})
+		log.Logger = log.Output(out)
 	case "json":
 		// configure json output log
-		log.Logger = log.Logger.Output(os.Stdout)
+		log.Logger = log.Logger.Output(out)
 		zerolog.TimestampFunc = func() time.Time { return time.Now().UTC() }
 	}
+
+	// report the profile defaults once the logger is set up
+	if name := profile.Name(); name != "" {
+		log.Debug().Str("profile", name).Strs("defaults", profile.Applied()).Msg("Environment profile applied")
+	}
 }
 
 // RequestID returns a unique request id or an empty string if there is none
@@ -0,0 +1,58 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package log
+
+import (
+	"sync"
+)
+
+// recent keeps the most recent log entries, nil if disabled
+var recent *ringBuffer
+
+// RecentEntries returns the most recent log entries (JSON encoded, oldest
+// first), at most LOG_RECENT_ENTRIES entries are kept
+func RecentEntries() [][]byte {
+	if recent == nil {
+		return nil
+	}
+	return recent.entries()
+}
+
+// ringBuffer is a writer that keeps the last written entries,
+// zerolog writes each entry with a single call of Write
+type ringBuffer struct {
+	mu   sync.Mutex
+	buf  [][]byte
+	next int
+	full bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{buf: make([][]byte, size)}
+}
+
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	r.mu.Lock()
+	r.buf[r.next] = entry
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	return len(p), nil
+}
+
+func (r *ringBuffer) entries() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([][]byte(nil), r.buf[:r.next]...)
+	}
+	return append(append([][]byte(nil), r.buf[r.next:]...), r.buf[:r.next]...)
+}
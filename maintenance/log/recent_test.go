@@ -0,0 +1,36 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := newRingBuffer(3)
+	if len(r.entries()) != 0 {
+		t.Error("expected empty ring buffer")
+	}
+	for _, entry := range []string{"a", "b", "c", "d", "e"} {
+		r.Write([]byte(entry)) // nolint: errcheck
+	}
+
+	var entries []string
+	for _, entry := range r.entries() {
+		entries = append(entries, string(entry))
+	}
+	if strings.Join(entries, ",") != "c,d,e" {
+		t.Errorf("expected the 3 most recent entries, got %v", entries)
+	}
+}
+
+func TestRecentEntries(t *testing.T) {
+	Logger().Info().Msg("recent entry")
+
+	entries := RecentEntries()
+	if len(entries) == 0 || !strings.Contains(string(entries[len(entries)-1]), `"message":"recent entry"`) {
+		t.Errorf("expected last entry to be logged as json, got %q", entries)
+	}
+}
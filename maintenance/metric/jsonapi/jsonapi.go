@@ -6,13 +6,16 @@
 package jsonapi
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strconv"
 	"time"
 
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/metric"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -29,21 +32,46 @@ const (
 var (
 	paceAPIHTTPRequestTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pace_api_http_request_total",
+			Name: metric.Name("api_http_request_total"),
 			Help: "Collects statistics about each microservice endpoint partitioned by code, method, path service and client_id",
 		},
 		[]string{"code", "method", "path", "service", "client_id"},
 	)
 	paceAPIHTTPRequestDurationSeconds = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name: "pace_api_http_request_duration_seconds",
+			Name: metric.Name("api_http_request_duration_seconds"),
 			Help: "Collect performance metrics for each API endpoint partitioned by method, path and service",
 		},
 		[]string{"method", "path", "service"},
 	)
+	paceAPIHTTPOperationTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("api_http_operation_total"),
+			Help: "Collects statistics about each microservice operation partitioned by code, service and operation",
+		},
+		[]string{"code", "service", "operation"},
+	)
+	paceAPIHTTPOperationDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: metric.Name("api_http_operation_duration_seconds"),
+			Help: "Collect performance metrics for each API operation partitioned by service and operation",
+		},
+		[]string{"service", "operation"},
+	)
+	paceAPIHTTPOperationSizeBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: metric.Name("api_http_operation_size_bytes"),
+			Help: "Collect request and response body size for each API operation partitioned by service and operation",
+			Buckets: []float64{
+				100, kb, 10 * kb, 100 * kb,
+				1 * mb, 5 * mb, 10 * mb, 100 * mb,
+			},
+		},
+		[]string{"service", "operation", "type"},
+	)
 	paceAPIHTTPSizeBytes = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name: "pace_api_http_size_bytes",
+			Name: metric.Name("api_http_size_bytes"),
 			Help: "Collect request and response body size for each API endpoint partitioned by method, path and service",
 			Buckets: []float64{
 				100, kb, 10 * kb, 100 * kb,
@@ -58,6 +86,9 @@ func init() {
 	prometheus.MustRegister(paceAPIHTTPRequestTotal)
 	prometheus.MustRegister(paceAPIHTTPRequestDurationSeconds)
 	prometheus.MustRegister(paceAPIHTTPSizeBytes)
+	prometheus.MustRegister(paceAPIHTTPOperationTotal)
+	prometheus.MustRegister(paceAPIHTTPOperationDurationSeconds)
+	prometheus.MustRegister(paceAPIHTTPOperationSizeBytes)
 }
 
 // Metric is an http.ResponseWriter implementing metrics collector
@@ -65,9 +96,11 @@ func init() {
 type Metric struct {
 	serviceName string
 	path        string // path is the patten path (not the request path)
+	operation   string // operation is the OpenAPI operation id (optional)
 	http.ResponseWriter
 	request      *http.Request
 	requestStart time.Time
+	requestBody  *lenCallbackReader
 	sizeWritten  int
 }
 
@@ -90,17 +123,48 @@ func NewMetric(serviceName, path string, w http.ResponseWriter, r *http.Request)
 	// metrics. This is basically a callback after the handler finished.
 	// A special case is when the handler did not read the body. In that case our
 	// lenCallbackReader counts the length of the rest as well (by reading it).
-	r.Body = &lenCallbackReader{
+	m.requestBody = &lenCallbackReader{
 		reader: r.Body,
 		onEOF: func(size int) {
 			AddPaceAPIHTTPSizeBytes(float64(size), r.Method, path, serviceName, TypeRequest)
 			AddPaceAPIHTTPSizeBytes(float64(m.sizeWritten), r.Method, path, serviceName, TypeResponse)
 		},
 	}
+	r.Body = m.requestBody
 
 	return &m
 }
 
+// NewOperationMetric creates a new metric collector (per request) like
+// NewMetric. Additionally the pace_api_http_operation_total counter and
+// pace_api_http_operation_duration_seconds histogram metric are collected
+// for the given operation (OpenAPI operation id).
+func NewOperationMetric(serviceName, operation, path string, w http.ResponseWriter, r *http.Request) *Metric {
+	m := NewMetric(serviceName, path, w, r)
+	m.operation = operation
+	return m
+}
+
+// ObserveSizes collects the pace_api_http_operation_size_bytes histogram
+// metric and adds the request and response body size to the passed span
+// (if any). It needs to be called after the handler finished, e.g. using defer.
+func (m *Metric) ObserveSizes(span opentracing.Span) {
+	requestSize := m.requestBody.size
+	if int64(requestSize) < m.request.ContentLength {
+		// body was not read (completely) by the handler
+		requestSize = int(m.request.ContentLength)
+	}
+
+	if m.operation != "" {
+		AddPaceAPIHTTPOperationSizeBytes(float64(requestSize), m.serviceName, m.operation, TypeRequest)
+		AddPaceAPIHTTPOperationSizeBytes(float64(m.sizeWritten), m.serviceName, m.operation, TypeResponse)
+	}
+	if span != nil {
+		span.SetTag("http.request_size", requestSize)
+		span.SetTag("http.response_size", m.sizeWritten)
+	}
+}
+
 // WriteHeader captures the status code for metric submission and
 // collects the pace_api_http_request_total counter and
 // pace_api_http_request_duration_seconds histogram metric
@@ -109,9 +173,25 @@ func (m *Metric) WriteHeader(statusCode int) {
 	IncPaceAPIHTTPRequestTotal(strconv.Itoa(statusCode), m.request.Method, m.path, m.serviceName, clientID)
 	duration := float64(time.Since(m.requestStart).Nanoseconds()) / float64(time.Second)
 	AddPaceAPIHTTPRequestDurationSeconds(duration, m.request.Method, m.path, m.serviceName)
+	if m.operation != "" {
+		IncPaceAPIHTTPOperationTotal(strconv.Itoa(statusCode), m.serviceName, m.operation)
+		AddPaceAPIHTTPOperationDurationSeconds(duration, m.serviceName, m.operation)
+	}
 	m.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Context returns the context of the request, e.g. to
+// mask attributes based on the scopes of the token
+func (m *Metric) Context() context.Context {
+	return m.request.Context()
+}
+
+// Request returns the request, e.g. to negotiate
+// the content type of the response
+func (m *Metric) Request() *http.Request {
+	return m.request
+}
+
 // Write captures the length of the response body.
 func (m *Metric) Write(p []byte) (int, error) {
 	size, err := m.ResponseWriter.Write(p)
@@ -139,6 +219,32 @@ func AddPaceAPIHTTPRequestDurationSeconds(duration float64, method, path, servic
 	}).Observe(duration)
 }
 
+// IncPaceAPIHTTPOperationTotal increments the pace_api_http_operation_total counter metric
+func IncPaceAPIHTTPOperationTotal(code, service, operation string) {
+	paceAPIHTTPOperationTotal.With(prometheus.Labels{
+		"code":      code,
+		"service":   service,
+		"operation": operation,
+	}).Inc()
+}
+
+// AddPaceAPIHTTPOperationDurationSeconds adds an observed value for the pace_api_http_operation_duration_seconds histogram metric
+func AddPaceAPIHTTPOperationDurationSeconds(duration float64, service, operation string) {
+	paceAPIHTTPOperationDurationSeconds.With(prometheus.Labels{
+		"service":   service,
+		"operation": operation,
+	}).Observe(duration)
+}
+
+// AddPaceAPIHTTPOperationSizeBytes adds an observed value for the pace_api_http_operation_size_bytes histogram metric
+func AddPaceAPIHTTPOperationSizeBytes(size float64, service, operation, requestOrResponse string) {
+	paceAPIHTTPOperationSizeBytes.With(prometheus.Labels{
+		"service":   service,
+		"operation": operation,
+		"type":      requestOrResponse,
+	}).Observe(size)
+}
+
 // AddPaceAPIHTTPSizeBytes adds an observed value for the pace_api_http_size_bytes histogram metric.
 func AddPaceAPIHTTPSizeBytes(size float64, method, path, service, requestOrResponse string) {
 	paceAPIHTTPSizeBytes.With(prometheus.Labels{
@@ -53,6 +53,41 @@ func TestMetric(t *testing.T) {
 		})
 	})
 
+	t.Run("capture operation metrics", func(t *testing.T) {
+		t.Run("api request", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/op/1234567", strings.NewReader("request"))
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				m := NewOperationMetric("operation", "GetOp", "/op/{id}", w, r)
+				defer m.ObserveSizes(nil)
+				m.WriteHeader(200)
+				m.Write([]byte("response")) // nolint: errcheck
+			}
+
+			handler(rec, req)
+			req.Body.Close() // that's something the server does
+		})
+		t.Run("get metrics request", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			metric.Handler().ServeHTTP(rec, req)
+
+			body := rec.Body.String()
+			for _, wantMetric := range []string{
+				`pace_api_http_operation_total{code="200",operation="GetOp",service="operation"} 1`,
+				`pace_api_http_operation_duration_seconds_count{operation="GetOp",service="operation"} 1`,
+				`pace_api_http_operation_size_bytes_sum{operation="GetOp",service="operation",type="req"} 7`,
+				`pace_api_http_operation_size_bytes_sum{operation="GetOp",service="operation",type="resp"} 8`,
+				`pace_api_http_request_total{client_id="",code="200",method="GET",path="/op/{id}",service="operation"} 1`,
+			} {
+				if !strings.Contains(body, wantMetric) {
+					t.Errorf("Expected metric %q, got: %v", wantMetric, body)
+				}
+			}
+		})
+	})
+
 	t.Run("capture request size", func(t *testing.T) {
 		t.Run("api request", func(t *testing.T) {
 			rec := httptest.NewRecorder()
@@ -0,0 +1,32 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package metric
+
+import "os"
+
+// DefaultNamespace of the metrics of bricks
+const DefaultNamespace = "pace"
+
+// Namespace is the prefix of the names of all metrics of bricks, it is
+// configured using METRICS_NAMESPACE. The metrics are created when the
+// packages are initialized, therefore the namespace can only be changed
+// using the environment. An empty namespace removes the prefix.
+var Namespace = namespace()
+
+func namespace() string {
+	ns, ok := os.LookupEnv("METRICS_NAMESPACE")
+	if !ok {
+		return DefaultNamespace
+	}
+	return ns
+}
+
+// Name returns the name of the metric in the namespace,
+// e.g. "pace_postgres_query_total" for "postgres_query_total"
+func Name(name string) string {
+	if Namespace == "" {
+		return name
+	}
+	return Namespace + "_" + name
+}
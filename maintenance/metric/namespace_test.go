@@ -0,0 +1,42 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package metric
+
+import (
+	"os"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	defer func(ns string) { Namespace = ns }(Namespace)
+
+	Namespace = DefaultNamespace
+	if got := Name("postgres_query_total"); got != "pace_postgres_query_total" {
+		t.Errorf("Expected pace_postgres_query_total, got: %q", got)
+	}
+
+	Namespace = ""
+	if got := Name("postgres_query_total"); got != "postgres_query_total" {
+		t.Errorf("Expected postgres_query_total, got: %q", got)
+	}
+}
+
+func TestNamespaceEnv(t *testing.T) {
+	defer os.Unsetenv("METRICS_NAMESPACE") // nolint: errcheck
+
+	os.Unsetenv("METRICS_NAMESPACE") // nolint: errcheck
+	if ns := namespace(); ns != DefaultNamespace {
+		t.Errorf("Expected default namespace, got: %q", ns)
+	}
+
+	os.Setenv("METRICS_NAMESPACE", "acme") // nolint: errcheck
+	if ns := namespace(); ns != "acme" {
+		t.Errorf("Expected acme, got: %q", ns)
+	}
+
+	os.Setenv("METRICS_NAMESPACE", "") // nolint: errcheck
+	if ns := namespace(); ns != "" {
+		t.Errorf("Expected empty namespace, got: %q", ns)
+	}
+}
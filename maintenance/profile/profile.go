@@ -0,0 +1,154 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package profile switches the bundled defaults of the environment
+// variables (log format and level, tracing sampling, debug endpoints,
+// response validation) by the environment tier in one place. The profile
+// is selected by ENVIRONMENT_PROFILE or derived from ENVIRONMENT, its
+// defaults are set by Setup before the packages parse the environment.
+// Variables that are set explicitly always take precedence.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Profiles of the environment tiers
+const (
+	Development = "development"
+	Staging     = "staging"
+	Production  = "production"
+	// None disables the profiles
+	None = "none"
+)
+
+// aliases maps the names of environments to the profiles
+var aliases = map[string]string{
+	"edge":        Development,
+	"dev":         Development,
+	"development": Development,
+	"local":       Development,
+	"stage":       Staging,
+	"staging":     Staging,
+	"prod":        Production,
+	"production":  Production,
+}
+
+// defaults of the environment variables per profile
+var defaults = map[string]map[string]string{
+	Development: {
+		"LOG_LEVEL":                       "debug",
+		"LOG_FORMAT":                      "auto",
+		"JAEGER_SAMPLER_TYPE":             "const",
+		"JAEGER_SAMPLER_PARAM":            "1",
+		"DEBUG_ENDPOINTS":                 "true",
+		"HTTP_CLIENT_RESPONSE_VALIDATION": "strict",
+	},
+	Staging: {
+		"LOG_LEVEL":                       "debug",
+		"LOG_FORMAT":                      "json",
+		"JAEGER_SAMPLER_TYPE":             "probabilistic",
+		"JAEGER_SAMPLER_PARAM":            "0.1",
+		"DEBUG_ENDPOINTS":                 "true",
+		"HTTP_CLIENT_RESPONSE_VALIDATION": "log",
+	},
+	Production: {
+		"LOG_LEVEL":                       "info",
+		"LOG_FORMAT":                      "json",
+		"JAEGER_SAMPLER_TYPE":             "probabilistic",
+		"JAEGER_SAMPLER_PARAM":            "0.01",
+		"DEBUG_ENDPOINTS":                 "false",
+		"HTTP_CLIENT_RESPONSE_VALIDATION": "disabled",
+	},
+}
+
+var (
+	name     string
+	applied  map[string]string
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+// Setup applies the defaults of the profile selected by the environment.
+// It is called by the log package before it parses its environment, as
+// log is initialized before the other packages of bricks, the defaults are
+// set before any package parses the environment. Without ENVIRONMENT and
+// ENVIRONMENT_PROFILE no profile is applied, unknown profiles and unknown
+// environments without ENVIRONMENT_PROFILE are rejected. The profile is
+// only applied once.
+func Setup() error {
+	cfgOnce.Do(func() {
+		name, applied, errSetup = apply(os.Getenv("ENVIRONMENT_PROFILE"), os.Getenv("ENVIRONMENT"))
+	})
+	return errSetup
+}
+
+// apply sets the defaults of the profile that aren't set explicitly,
+// it returns the profile and the applied defaults
+func apply(profile, environment string) (string, map[string]string, error) {
+	if profile == "" {
+		if environment == "" {
+			// keep the defaults of the packages
+			return "", nil, nil
+		}
+		profile = aliases[strings.ToLower(environment)]
+		if profile == "" {
+			return "", nil, fmt.Errorf("no profile for ENVIRONMENT %q, set ENVIRONMENT_PROFILE to %s, %s, %s or %s",
+				environment, Development, Staging, Production, None)
+		}
+	}
+	profile = strings.ToLower(profile)
+	if profile == None {
+		return "", nil, nil
+	}
+
+	vars, ok := defaults[profile]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown ENVIRONMENT_PROFILE %q", profile)
+	}
+	result := make(map[string]string, len(vars))
+	for key, value := range vars {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return profile, result, err
+		}
+		result[key] = value
+	}
+	return profile, result, nil
+}
+
+// Name returns the active profile, empty if no profile
+// is active or Setup wasn't called
+func Name() string {
+	return name
+}
+
+// Applied returns the variables set by the profile
+// (name=value), sorted by name
+func Applied() []string {
+	vars := make([]string, 0, len(applied))
+	for key, value := range applied {
+		vars = append(vars, key+"="+value)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// Defaults returns the defaults of the profile, nil for unknown profiles
+func Defaults(profile string) map[string]string {
+	vars, ok := defaults[profile]
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(vars))
+	for key, value := range vars {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package profile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unsetProfileVars unsets the variables of the profiles and
+// returns a function restoring them
+func unsetProfileVars(t *testing.T) func() {
+	saved := make(map[string]string)
+	for key := range defaults[Production] {
+		if value, ok := os.LookupEnv(key); ok {
+			saved[key] = value
+		}
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for key := range defaults[Production] {
+			os.Unsetenv(key) // nolint: errcheck
+		}
+		for key, value := range saved {
+			os.Setenv(key, value) // nolint: errcheck
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	defer unsetProfileVars(t)()
+
+	// explicit variables take precedence
+	os.Setenv("LOG_LEVEL", "warn") // nolint: errcheck
+	profile, vars, err := apply("", "prod")
+	assert.NoError(t, err)
+	assert.Equal(t, Production, profile)
+	assert.Equal(t, "warn", os.Getenv("LOG_LEVEL"))
+	assert.Equal(t, "json", os.Getenv("LOG_FORMAT"))
+	assert.Equal(t, "false", os.Getenv("DEBUG_ENDPOINTS"))
+	assert.NotContains(t, vars, "LOG_LEVEL")
+	assert.Equal(t, "0.01", vars["JAEGER_SAMPLER_PARAM"])
+}
+
+func TestSelection(t *testing.T) {
+	cases := []struct {
+		profile, environment, expected string
+	}{
+		{"", "", ""},
+		{"", "edge", Development},
+		{"", "Staging", Staging},
+		{"production", "prod-eu", Production},
+		{"none", "prod", ""},
+		{"none", "prod-eu", ""},
+	}
+	for _, c := range cases {
+		restore := unsetProfileVars(t)
+		profile, _, err := apply(c.profile, c.environment)
+		restore()
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, profile, "%q %q", c.profile, c.environment)
+	}
+
+	// unknown profiles and environments are rejected
+	_, _, err := apply("qa", "prod")
+	assert.Error(t, err)
+	restore := unsetProfileVars(t)
+	_, vars, err := apply("", "prod-eu")
+	restore()
+	assert.Error(t, err)
+	assert.Empty(t, vars)
+
+	assert.Nil(t, Defaults("qa"))
+	assert.Equal(t, "info", Defaults(Production)["LOG_LEVEL"])
+}
@@ -0,0 +1,80 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package readonly
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pace/bricks/http/jsonapi/runtime"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// ErrReadOnly is returned to the clients for mutating requests
+var ErrReadOnly = errors.New("service is in read-only mode")
+
+type status struct {
+	ReadOnly bool `json:"readOnly"`
+}
+
+// Handler returns the admin endpoint of the read-only mode, it is mounted
+// on /debug/readonly by the default router if DEBUG_ENDPOINTS are enabled,
+// the requests need the DEBUG_TOKEN. GET returns the current mode, PUT
+// changes it, e.g. "PUT /debug/readonly?enabled=true".
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+		case "PUT":
+			readOnly, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+			if err != nil {
+				http.Error(w, "invalid enabled parameter", http.StatusBadRequest)
+				return
+			}
+			log.Req(r).Info().Bool("read_only", readOnly).Msg("Read-only mode changed")
+			Set(readOnly)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status{ReadOnly: Enabled()}) // nolint: errcheck
+	})
+}
+
+// Middleware answers mutating requests (all methods except GET, HEAD and
+// OPTIONS) with 503 Service Unavailable in read-only mode. Requests with
+// one of the ignored path prefixes are always passed.
+func Middleware(ignoredPrefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if Enabled() && isMutating(r.Method) && !hasPrefix(r.URL.Path, ignoredPrefixes) {
+				runtime.WriteError(w, http.StatusServiceUnavailable, ErrReadOnly)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return false
+	}
+	return true
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package readonly implements a service wide read-only mode for planned
+// failovers and incident containment. In read-only mode the postgres
+// layer rejects writes, mutating http requests are answered with
+// 503 Service Unavailable and background writers pause.
+package readonly
+
+import (
+	"context"
+	"sync"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type config struct {
+	ReadOnly bool `env:"READ_ONLY" envDefault:"false"`
+}
+
+var paceReadOnly = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: metric.Name("read_only"),
+	Help: "Is 1 if the service is in read-only mode, 0 otherwise",
+})
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+var (
+	mu       sync.RWMutex
+	enabled  bool
+	writable = make(chan struct{}) // closed while writable
+
+	listenersMu sync.Mutex
+	listeners   []func(readOnly bool)
+)
+
+func init() {
+	prometheus.MustRegister(paceReadOnly)
+	configcheck.Register("readonly", Setup)
+	close(writable)
+}
+
+// Setup parses READ_ONLY and enables the read-only mode if it is set,
+// see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+		if errSetup == nil && cfg.ReadOnly {
+			set(true)
+		}
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse read-only environment: %v", err)
+	}
+}
+
+// Enabled returns true if the service is in read-only mode
+func Enabled() bool {
+	mustSetup()
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// Set enables or disables the read-only mode, the functions
+// registered with OnChange are called if the mode changed
+func Set(readOnly bool) {
+	mustSetup()
+	if !set(readOnly) {
+		return
+	}
+
+	listenersMu.Lock()
+	fns := listeners
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn(readOnly)
+	}
+}
+
+// OnChange registers fn to be called after the mode was changed
+// using Set, e.g. to reset connections of the previous mode
+func OnChange(fn func(readOnly bool)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// set changes the mode and returns true if it changed
+func set(readOnly bool) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if readOnly == enabled {
+		return false
+	}
+	enabled = readOnly
+
+	if readOnly {
+		writable = make(chan struct{})
+		paceReadOnly.Set(1)
+		log.Logger().Warn().Msg("Read-only mode enabled")
+	} else {
+		close(writable)
+		paceReadOnly.Set(0)
+		log.Logger().Info().Msg("Read-only mode disabled")
+	}
+	return true
+}
+
+// Wait blocks until the service is writable or ctx is done, background
+// writers call it before writing to pause in read-only mode
+func Wait(ctx context.Context) error {
+	mustSetup()
+	mu.RLock()
+	ch := writable
+	mu.RUnlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
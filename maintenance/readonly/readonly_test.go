@@ -0,0 +1,83 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package readonly
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	Set(true)
+	defer Set(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected writers to wait in read-only mode, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- Wait(context.Background()) }()
+	Set(false)
+	if err := <-done; err != nil {
+		t.Errorf("expected writers to continue, got %v", err)
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	var changes []bool
+	OnChange(func(readOnly bool) { changes = append(changes, readOnly) })
+
+	Set(true)
+	Set(true)
+	Set(false)
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("expected enable and disable to be notified once, got %v", changes)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := Middleware("/debug")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(method, path string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	if code := serve("POST", "/articles"); code != http.StatusOK {
+		t.Errorf("expected writes to pass, got %d", code)
+	}
+
+	Set(true)
+	defer Set(false)
+	if code := serve("POST", "/articles"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 in read-only mode, got %d", code)
+	}
+	if code := serve("GET", "/articles"); code != http.StatusOK {
+		t.Errorf("expected reads to pass, got %d", code)
+	}
+	if code := serve("PUT", "/debug/readonly"); code != http.StatusOK {
+		t.Errorf("expected ignored prefix to pass, got %d", code)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	defer Set(false)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("PUT", "/?enabled=true", nil))
+	if rec.Code != http.StatusOK || !Enabled() {
+		t.Errorf("expected read-only mode to be enabled, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("PUT", "/?enabled=maybe", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid parameter, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package support
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// Handler returns the support bundle as zip archive, the default router
+// mounts it with DEBUG_ENDPOINTS and requires the DEBUG_TOKEN, e.g.
+// curl -H "Authorization: Bearer $DEBUG_TOKEN" -o bundle.zip http://service:3000/debug/support
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		hostname, _ := os.Hostname() // nolint: errcheck
+		name := fmt.Sprintf("support-%s-%s.zip", hostname, time.Now().UTC().Format("20060102T150405Z"))
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+		if err := Write(r.Context(), w); err != nil {
+			log.Req(r).Warn().Err(err).Msg("Failed to write support bundle")
+		}
+	})
+}
@@ -0,0 +1,279 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package support gathers support bundles, a zip archive with a consistent
+// snapshot of the state of the service (version, redacted configuration,
+// health checks, recent logs, goroutines and metrics) that can be attached
+// to incident tickets.
+package support
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/configcheck"
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+type config struct {
+	// Names of additional environment variables whose
+	// values are written to the support bundle.
+	EnvAllowlist []string `env:"SUPPORT_ENV_ALLOWLIST" envSeparator:","`
+}
+
+var (
+	cfg      config
+	cfgOnce  sync.Once
+	errSetup error
+)
+
+func init() {
+	configcheck.Register("support", Setup)
+}
+
+// Setup parses SUPPORT_ENV_ALLOWLIST, see configcheck.
+func Setup() error {
+	cfgOnce.Do(func() {
+		errSetup = env.Parse(&cfg)
+	})
+	return errSetup
+}
+
+func mustSetup() {
+	if err := Setup(); err != nil {
+		log.Fatalf("Failed to parse support environment: %v", err)
+	}
+}
+
+// Version of the service, set at build time using
+// -ldflags "-X github.com/pace/bricks/maintenance/support.Version=..."
+var Version = "unknown"
+
+var started = time.Now()
+
+// healthTimeout is the maximum duration of the readiness checks
+const healthTimeout = 5 * time.Second
+
+// redacted replaces the values of environment variables
+// that are not allowlisted
+const redacted = "[REDACTED]"
+
+// allowlist contains the names of the environment variables whose values
+// are written to the bundle, the names of secrets can't be guessed reliably
+var allowlist = map[string]bool{
+	"ENVIRONMENT":                     true,
+	"HOSTNAME":                        true,
+	"GOMAXPROCS":                      true,
+	"GOGC":                            true,
+	"LOG_LEVEL":                       true,
+	"LOG_FORMAT":                      true,
+	"PORT":                            true,
+	"ADDR":                            true,
+	"READ_TIMEOUT":                    true,
+	"WRITE_TIMEOUT":                   true,
+	"IDLE_TIMEOUT":                    true,
+	"SHUTDOWN_GRACE_PERIOD":           true,
+	"DEBUG_ENDPOINTS":                 true,
+	"READ_ONLY":                       true,
+	"HTTP_CLIENT_RESPONSE_VALIDATION": true,
+	"JAEGER_SERVICE_NAME":             true,
+	"POSTGRES_HOST":                   true,
+	"POSTGRES_PORT":                   true,
+	"POSTGRES_DB":                     true,
+	"POSTGRES_USER":                   true,
+	"POSTGRES_SSLMODE":                true,
+	"POSTGRES_POOL_SIZE":              true,
+	"POSTGRES_MAX_RETRIES":            true,
+	"REDIS_HOSTS":                     true,
+	"REDIS_MODE":                      true,
+	"REDIS_DB":                        true,
+	"KAFKA_BROKERS":                   true,
+	"KAFKA_GROUP_ID":                  true,
+}
+
+// file of the support bundle
+type file struct {
+	name  string
+	write func(ctx context.Context, w io.Writer) error
+}
+
+var files = []file{
+	{"version.json", writeVersion},
+	{"config.txt", writeConfig},
+	{"health.txt", writeHealth},
+	{"logs.json", writeLogs},
+	{"goroutines.txt", writeGoroutines},
+	{"metrics.txt", writeMetrics},
+}
+
+// Write writes the support bundle as zip archive to w. Files that can't
+// be gathered contain the error instead of failing the whole bundle.
+func Write(ctx context.Context, w io.Writer) error {
+	mustSetup()
+	zw := zip.NewWriter(w)
+	now := time.Now()
+
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+
+		// buffer the file, so that the error can be written instead
+		var buf bytes.Buffer
+		if err := f.write(ctx, &buf); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("file", f.name).Msg("Failed to gather support bundle file")
+			buf.Reset()
+			fmt.Fprintf(&buf, "error: %v\n", err) // nolint: errcheck
+		}
+		if _, err := buf.WriteTo(fw); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeVersion(ctx context.Context, w io.Writer) error {
+	hostname, _ := os.Hostname() // nolint: errcheck
+	info := map[string]interface{}{
+		"version":    Version,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"hostname":   hostname,
+		"started":    started.UTC().Format(time.RFC3339),
+		"uptime":     time.Since(started).Round(time.Second).String(),
+		"goroutines": runtime.NumGoroutine(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info["module"] = bi.Main.Path + "@" + bi.Main.Version
+		deps := make(map[string]string, len(bi.Deps))
+		for _, dep := range bi.Deps {
+			deps[dep.Path] = dep.Version
+		}
+		info["dependencies"] = deps
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(info)
+}
+
+// writeConfig writes the environment (the configuration of the
+// services) with the values of variables that aren't allowlisted redacted
+func writeConfig(ctx context.Context, w io.Writer) error {
+	environ := os.Environ()
+	sort.Strings(environ)
+	for _, kv := range environ {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", parts[0], Redact(parts[0], parts[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redact returns the value of the environment variable with the passed
+// name if it is allowlisted (see SUPPORT_ENV_ALLOWLIST), a placeholder
+// otherwise. Passwords of URLs (e.g. connection strings) are redacted as well.
+func Redact(name, value string) string {
+	if !allowed(name) {
+		return redacted
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+			return u.String()
+		}
+	}
+	return value
+}
+
+// allowed returns true if the value of the environment
+// variable may be written to the bundle
+func allowed(name string) bool {
+	if allowlist[name] {
+		return true
+	}
+	for _, n := range cfg.EnvAllowlist {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHealth(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	results := health.CheckReadiness(ctx)
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "no readiness checks registered")
+		return err
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		status := "OK"
+		if err := results[name]; err != nil {
+			status = err.Error()
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", name, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLogs(ctx context.Context, w io.Writer) error {
+	for _, entry := range log.RecentEntries() {
+		if _, err := w.Write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGoroutines(ctx context.Context, w io.Writer) error {
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+func writeMetrics(ctx context.Context, w io.Writer) error {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return err
+	}
+	for _, mf := range families {
+		if _, err := expfmt.MetricFamilyToText(w, mf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,434 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package propagation extracts and injects the trace context of HTTP
+// requests and messages in the formats of Jaeger (uber-trace-id), Zipkin
+// (B3, multi and single header) and W3C (traceparent and baggage). The
+// Jaeger debug id (jaeger-debug-id) and baggage (jaeger-baggage and
+// uberctx-*) are extracted like the jaeger client does. The format of
+// incoming trace contexts is detected automatically, outgoing trace
+// contexts are injected in the configured formats. This way services
+// can interoperate with partner systems that use other tracing stacks.
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
+)
+
+// Format of the trace context
+type Format string
+
+// Supported formats
+const (
+	Jaeger   Format = "jaeger"   // uber-trace-id and uberctx-* baggage
+	B3       Format = "b3"       // X-B3-* headers
+	B3Single Format = "b3single" // b3 header
+	W3C      Format = "w3c"      // traceparent and baggage
+)
+
+// Header names of the formats (lower case)
+const (
+	jaegerHeader        = "uber-trace-id"
+	jaegerBaggagePrefix = "uberctx-"
+	b3TraceIDHeader     = "x-b3-traceid"
+	b3SpanIDHeader      = "x-b3-spanid"
+	b3ParentIDHeader    = "x-b3-parentspanid"
+	b3SampledHeader     = "x-b3-sampled"
+	b3FlagsHeader       = "x-b3-flags"
+	b3SingleHeader      = "b3"
+	w3cHeader           = "traceparent"
+	w3cBaggageHeader    = "baggage"
+)
+
+// detectionOrder of the formats, the first format found in the
+// carrier is used
+var detectionOrder = []Format{W3C, B3Single, B3, Jaeger}
+
+// jaegerTracer is only used to extract the Jaeger format with the
+// propagators of the jaeger client, they support the debug id, which
+// can't be set using the public API of jaeger.SpanContext
+var jaegerTracer, _ = jaeger.NewTracer("propagation", jaeger.NewConstSampler(false), jaeger.NewNullReporter())
+
+// ErrUnknownFormat is returned for unsupported formats
+var ErrUnknownFormat = errors.New("propagation: unknown format")
+
+// ParseFormats parses a comma separated list of formats (e.g. "jaeger,w3c")
+func ParseFormats(s string) ([]Format, error) {
+	var formats []Format
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		switch Format(f) {
+		case Jaeger, B3, B3Single, W3C:
+			formats = append(formats, Format(f))
+		default:
+			return nil, fmt.Errorf("%v: %q", ErrUnknownFormat, f)
+		}
+	}
+	return formats, nil
+}
+
+// Propagator injects and extracts jaeger span contexts, it implements
+// jaeger.Injector and jaeger.Extractor for opentracing.TextMapReader and
+// opentracing.TextMapWriter carriers (e.g. opentracing.HTTPHeadersCarrier
+// and opentracing.TextMapCarrier)
+type Propagator struct {
+	// Formats used for the injection, Jaeger if empty
+	Formats []Format
+	// Whether values are url encoded, use for HTTP headers
+	URLEncoding bool
+}
+
+// Inject injects the span context in all configured formats
+func (p *Propagator) Inject(sc jaeger.SpanContext, abstractCarrier interface{}) error {
+	carrier, ok := abstractCarrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	formats := p.Formats
+	if len(formats) == 0 {
+		formats = []Format{Jaeger}
+	}
+	for _, format := range formats {
+		switch format {
+		case Jaeger:
+			p.injectJaeger(sc, carrier)
+		case B3:
+			injectB3(sc, carrier)
+		case B3Single:
+			injectB3Single(sc, carrier)
+		case W3C:
+			injectW3C(sc, carrier)
+		default:
+			return fmt.Errorf("%v: %q", ErrUnknownFormat, format)
+		}
+	}
+	return nil
+}
+
+// Extract extracts the span context of the first format found in
+// the carrier, see Detect
+func (p *Propagator) Extract(abstractCarrier interface{}) (jaeger.SpanContext, error) {
+	carrier, ok := abstractCarrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaeger.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+	sc, _, err := p.extract(carrier)
+	return sc, err
+}
+
+// Detect returns the format of the trace context of the carrier,
+// opentracing.ErrSpanContextNotFound if there is none
+func Detect(carrier opentracing.TextMapReader) (Format, error) {
+	_, format, err := (&Propagator{}).extract(carrier)
+	return format, err
+}
+
+func (p *Propagator) extract(carrier opentracing.TextMapReader) (jaeger.SpanContext, Format, error) {
+	headers := make(map[string]string)
+	err := carrier.ForeachKey(func(key, value string) error {
+		headers[strings.ToLower(key)] = value
+		return nil
+	})
+	if err != nil {
+		return jaeger.SpanContext{}, "", err
+	}
+
+	for _, format := range detectionOrder {
+		var (
+			sc  jaeger.SpanContext
+			ok  bool
+			err error
+		)
+		switch format {
+		case W3C:
+			sc, ok, err = extractW3C(headers)
+		case B3Single:
+			sc, ok, err = extractB3Single(headers)
+		case B3:
+			sc, ok, err = extractB3(headers)
+		case Jaeger:
+			sc, ok, err = extractJaeger(headers)
+		}
+		if err != nil {
+			return jaeger.SpanContext{}, format, opentracing.ErrSpanContextCorrupted
+		}
+		if ok {
+			return withBaggage(sc, headers), format, nil
+		}
+	}
+
+	// baggage without trace context, like the jaeger client
+	if baggage := extractW3CBaggage(headers); len(baggage) > 0 {
+		return jaeger.NewSpanContext(jaeger.TraceID{}, 0, 0, false, baggage), W3C, nil
+	}
+	return jaeger.SpanContext{}, "", opentracing.ErrSpanContextNotFound
+}
+
+// withBaggage adds the baggage of the Jaeger (uberctx-* and jaeger-baggage)
+// and W3C (baggage) formats to the span context of any format
+func withBaggage(sc jaeger.SpanContext, headers map[string]string) jaeger.SpanContext {
+	for key, value := range headers {
+		switch {
+		case strings.HasPrefix(key, jaegerBaggagePrefix):
+			if v, err := url.QueryUnescape(value); err == nil {
+				value = v
+			}
+			sc = sc.WithBaggageItem(strings.TrimPrefix(key, jaegerBaggagePrefix), value)
+		case key == jaeger.JaegerBaggageHeader:
+			for k, v := range parseJaegerBaggage(value) {
+				sc = sc.WithBaggageItem(k, v)
+			}
+		}
+	}
+	for k, v := range extractW3CBaggage(headers) {
+		sc = sc.WithBaggageItem(k, v)
+	}
+	return sc
+}
+
+// FromHeaders returns the span context of the message headers using the
+// global tracer, use it as reference of the span of the consumed message
+func FromHeaders(headers map[string]string) (opentracing.SpanContext, error) {
+	return opentracing.GlobalTracer().Extract(opentracing.TextMap, opentracing.TextMapCarrier(headers))
+}
+
+// ToHeaders adds the span context of the span in ctx to the message
+// headers using the global tracer, the headers are not changed if ctx
+// has no span
+func ToHeaders(ctx context.Context, headers map[string]string) error {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	return opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers))
+}
+
+// Jaeger: uber-trace-id: {trace-id}:{span-id}:{parent-span-id}:{flags}
+
+func (p *Propagator) injectJaeger(sc jaeger.SpanContext, carrier opentracing.TextMapWriter) {
+	encode := func(s string) string { return s }
+	if p.URLEncoding {
+		encode = url.QueryEscape
+	}
+	carrier.Set(jaegerHeader, encode(sc.String()))
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		carrier.Set(jaegerBaggagePrefix+k, encode(v))
+		return true
+	})
+}
+
+// extractJaeger extracts uber-trace-id, jaeger-debug-id and the baggage
+// using the jaeger client. Contexts with only a debug id or baggage are
+// returned as well, the jaeger tracer starts a new (debug) trace for them.
+func extractJaeger(headers map[string]string) (jaeger.SpanContext, bool, error) {
+	_, hasTrace := headers[jaegerHeader]
+	_, hasDebugID := headers[jaeger.JaegerDebugHeader]
+	if !hasTrace && !hasDebugID {
+		return jaeger.SpanContext{}, false, nil
+	}
+
+	// values are unescaped like the baggage of the other formats
+	sc, err := jaegerTracer.Extract(opentracing.HTTPHeaders, opentracing.TextMapCarrier(headers))
+	if err != nil {
+		return jaeger.SpanContext{}, false, err
+	}
+	return sc.(jaeger.SpanContext), true, nil
+}
+
+// parseJaegerBaggage parses the jaeger-baggage header (k1=v1, k2=v2)
+func parseJaegerBaggage(value string) map[string]string {
+	baggage := make(map[string]string)
+	if v, err := url.QueryUnescape(value); err == nil {
+		value = v
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 && kv[0] != "" {
+			baggage[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return baggage
+}
+
+// B3: X-B3-TraceId, X-B3-SpanId, X-B3-ParentSpanId, X-B3-Sampled, X-B3-Flags
+
+func injectB3(sc jaeger.SpanContext, carrier opentracing.TextMapWriter) {
+	carrier.Set("X-B3-TraceId", traceIDHex(sc.TraceID()))
+	carrier.Set("X-B3-SpanId", spanIDHex(sc.SpanID()))
+	if sc.ParentID() != 0 {
+		carrier.Set("X-B3-ParentSpanId", spanIDHex(sc.ParentID()))
+	}
+	if sc.IsDebug() {
+		carrier.Set("X-B3-Flags", "1")
+	} else if sc.IsSampled() {
+		carrier.Set("X-B3-Sampled", "1")
+	} else {
+		carrier.Set("X-B3-Sampled", "0")
+	}
+}
+
+func extractB3(headers map[string]string) (jaeger.SpanContext, bool, error) {
+	traceID, ok := headers[b3TraceIDHeader]
+	if !ok {
+		return jaeger.SpanContext{}, false, nil
+	}
+	sampled := headers[b3SampledHeader] == "1" || headers[b3SampledHeader] == "true" || headers[b3FlagsHeader] == "1"
+	sc, err := spanContext(traceID, headers[b3SpanIDHeader], headers[b3ParentIDHeader], sampled)
+	return sc, err == nil, err
+}
+
+// B3 single: b3: {trace-id}-{span-id}-{sampling}-{parent-span-id}
+
+func injectB3Single(sc jaeger.SpanContext, carrier opentracing.TextMapWriter) {
+	sampling := "0"
+	if sc.IsDebug() {
+		sampling = "d"
+	} else if sc.IsSampled() {
+		sampling = "1"
+	}
+	value := traceIDHex(sc.TraceID()) + "-" + spanIDHex(sc.SpanID()) + "-" + sampling
+	if sc.ParentID() != 0 {
+		value += "-" + spanIDHex(sc.ParentID())
+	}
+	carrier.Set(b3SingleHeader, value)
+}
+
+func extractB3Single(headers map[string]string) (jaeger.SpanContext, bool, error) {
+	value, ok := headers[b3SingleHeader]
+	if !ok {
+		return jaeger.SpanContext{}, false, nil
+	}
+	parts := strings.Split(value, "-")
+	if len(parts) < 2 {
+		// only the sampling decision, no trace context
+		return jaeger.SpanContext{}, false, nil
+	}
+	var sampled bool
+	parent := ""
+	if len(parts) > 2 {
+		sampled = parts[2] == "1" || parts[2] == "d"
+	}
+	if len(parts) > 3 {
+		parent = parts[3]
+	}
+	sc, err := spanContext(parts[0], parts[1], parent, sampled)
+	return sc, err == nil, err
+}
+
+// W3C: traceparent: {version}-{trace-id}-{parent-id}-{trace-flags}
+
+func injectW3C(sc jaeger.SpanContext, carrier opentracing.TextMapWriter) {
+	flags := 0
+	if sc.IsSampled() {
+		flags = 1
+	}
+	tid := sc.TraceID()
+	carrier.Set(w3cHeader, fmt.Sprintf("00-%016x%016x-%016x-%02x", tid.High, tid.Low, uint64(sc.SpanID()), flags))
+
+	var baggage []string
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		baggage = append(baggage, escapeBaggage(k)+"="+escapeBaggage(v))
+		return true
+	})
+	if len(baggage) > 0 {
+		sort.Strings(baggage)
+		carrier.Set(w3cBaggageHeader, strings.Join(baggage, ","))
+	}
+}
+
+func extractW3C(headers map[string]string) (jaeger.SpanContext, bool, error) {
+	value, ok := headers[w3cHeader]
+	if !ok {
+		return jaeger.SpanContext{}, false, nil
+	}
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return jaeger.SpanContext{}, false, fmt.Errorf("malformed traceparent %q", value)
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return jaeger.SpanContext{}, false, err
+	}
+	sc, err := spanContext(parts[1], parts[2], "", flags&1 == 1)
+	return sc, err == nil, err
+}
+
+// escapeBaggage percent encodes keys and values of the baggage header
+func escapeBaggage(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// extractW3CBaggage parses the baggage header (k1=v1;property,k2=v2),
+// the properties are ignored
+func extractW3CBaggage(headers map[string]string) map[string]string {
+	value, ok := headers[w3cBaggageHeader]
+	if !ok {
+		return nil
+	}
+	baggage := make(map[string]string)
+	for _, member := range strings.Split(value, ",") {
+		member = strings.SplitN(member, ";", 2)[0]
+		kv := strings.SplitN(strings.TrimSpace(member), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, err := url.PathUnescape(strings.TrimSpace(kv[0]))
+		if err != nil || k == "" {
+			continue
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+		baggage[k] = v
+	}
+	return baggage
+}
+
+// spanContext returns the jaeger span context of the hex encoded ids
+func spanContext(traceID, spanID, parentID string, sampled bool) (jaeger.SpanContext, error) {
+	tid, err := jaeger.TraceIDFromString(traceID)
+	if err != nil {
+		return jaeger.SpanContext{}, err
+	}
+	sid, err := jaeger.SpanIDFromString(spanID)
+	if err != nil {
+		return jaeger.SpanContext{}, err
+	}
+	var pid jaeger.SpanID
+	if parentID != "" {
+		if pid, err = jaeger.SpanIDFromString(parentID); err != nil {
+			return jaeger.SpanContext{}, err
+		}
+	}
+	if !tid.IsValid() || sid == 0 {
+		return jaeger.SpanContext{}, errors.New("invalid trace or span id")
+	}
+	return jaeger.NewSpanContext(tid, sid, pid, sampled, nil), nil
+}
+
+// traceIDHex returns the 16 or 32 character hex encoded trace id
+func traceIDHex(tid jaeger.TraceID) string {
+	if tid.High == 0 {
+		return fmt.Sprintf("%016x", tid.Low)
+	}
+	return fmt.Sprintf("%016x%016x", tid.High, tid.Low)
+}
+
+// spanIDHex returns the 16 character hex encoded span id
+func spanIDHex(id jaeger.SpanID) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
@@ -0,0 +1,136 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	jaeger "github.com/uber/jaeger-client-go"
+)
+
+func testContext() jaeger.SpanContext {
+	return jaeger.NewSpanContext(
+		jaeger.TraceID{High: 0x4bf92f3577b34da6, Low: 0xa3ce929d0e0e4736},
+		jaeger.SpanID(0x00f067aa0ba902b7), jaeger.SpanID(0x1), true,
+		map[string]string{"tenant": "a b"})
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{Jaeger, B3, B3Single, W3C} {
+		p := &Propagator{Formats: []Format{format}, URLEncoding: true}
+		header := make(http.Header)
+		err := p.Inject(testContext(), opentracing.HTTPHeadersCarrier(header))
+		if !assert.NoError(t, err, format) {
+			continue
+		}
+
+		detected, err := Detect(opentracing.HTTPHeadersCarrier(header))
+		assert.NoError(t, err, format)
+		assert.Equal(t, format, detected)
+
+		sc, err := p.Extract(opentracing.HTTPHeadersCarrier(header))
+		if !assert.NoError(t, err, format) {
+			continue
+		}
+		assert.Equal(t, testContext().TraceID(), sc.TraceID(), format)
+		assert.Equal(t, testContext().SpanID(), sc.SpanID(), format)
+		assert.True(t, sc.IsSampled(), format)
+	}
+}
+
+func TestExtractPartnerHeaders(t *testing.T) {
+	cases := map[string]opentracing.TextMapCarrier{
+		"w3c":      {"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		"b3":       {"X-B3-TraceId": "4bf92f3577b34da6a3ce929d0e0e4736", "X-B3-SpanId": "00f067aa0ba902b7", "X-B3-Sampled": "1"},
+		"b3single": {"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+		"jaeger":   {"uber-trace-id": "4bf92f3577b34da6a3ce929d0e0e4736:f067aa0ba902b7:0:1", "uberctx-tenant": "a%20b"},
+	}
+	for name, carrier := range cases {
+		sc, err := (&Propagator{}).Extract(carrier)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String(), name)
+		assert.Equal(t, "f067aa0ba902b7", sc.SpanID().String(), name)
+		assert.True(t, sc.IsSampled(), name)
+	}
+
+	sc, err := (&Propagator{}).Extract(cases["jaeger"])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "a b"}, baggage(sc))
+}
+
+func baggage(sc jaeger.SpanContext) map[string]string {
+	items := make(map[string]string)
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		items[k] = v
+		return true
+	})
+	return items
+}
+
+func TestExtractErrors(t *testing.T) {
+	_, err := (&Propagator{}).Extract(opentracing.TextMapCarrier{})
+	assert.Equal(t, opentracing.ErrSpanContextNotFound, err)
+
+	_, err = (&Propagator{}).Extract(opentracing.TextMapCarrier{"traceparent": "00-xyz"})
+	assert.Equal(t, opentracing.ErrSpanContextCorrupted, err)
+
+	_, err = (&Propagator{}).Extract("not a carrier")
+	assert.Equal(t, opentracing.ErrInvalidCarrier, err)
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("jaeger, W3C,b3")
+	assert.NoError(t, err)
+	assert.Equal(t, []Format{Jaeger, W3C, B3}, formats)
+
+	formats, err = ParseFormats("")
+	assert.NoError(t, err)
+	assert.Empty(t, formats)
+
+	_, err = ParseFormats("xray")
+	assert.Error(t, err)
+}
+
+func TestExtractDebugID(t *testing.T) {
+	carrier := opentracing.TextMapCarrier{"jaeger-debug-id": "incident-42"}
+	format, err := Detect(carrier)
+	assert.NoError(t, err)
+	assert.Equal(t, Jaeger, format)
+
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(false), jaeger.NewNullReporter())
+	defer closer.Close() // nolint: errcheck
+	sc, err := (&Propagator{}).Extract(carrier)
+	assert.NoError(t, err)
+
+	// the jaeger tracer starts a sampled debug trace for the debug id
+	span := tracer.StartSpan("test", opentracing.ChildOf(sc))
+	defer span.Finish()
+	assert.True(t, span.Context().(jaeger.SpanContext).IsDebug())
+}
+
+func TestExtractBaggage(t *testing.T) {
+	carrier := opentracing.TextMapCarrier{
+		"traceparent":    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"baggage":        "tenant=a%20b;ttl=60, region=eu",
+		"jaeger-baggage": "user=42",
+	}
+	sc, err := (&Propagator{}).Extract(carrier)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "a b", "region": "eu", "user": "42"}, baggage(sc))
+
+	// baggage without trace context
+	sc, err = (&Propagator{}).Extract(opentracing.TextMapCarrier{"baggage": "tenant=acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, baggage(sc))
+
+	header := make(http.Header)
+	err = (&Propagator{Formats: []Format{W3C}}).Inject(testContext(), opentracing.HTTPHeadersCarrier(header))
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant=a%20b", header.Get("baggage"))
+}
@@ -6,6 +6,7 @@ package tracing
 import (
 	"io"
 	"net/http"
+	"os"
 	"strings"
 
 	opentracing "github.com/opentracing/opentracing-go"
@@ -14,6 +15,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics/prometheus"
 	"github.com/zenazn/goji/web/mutil"
 	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/tracing/propagation"
 )
 
 // Closer can be used in shutdown hooks to ensure that the internal queue of
@@ -35,8 +37,20 @@ func init() {
 		return
 	}
 
+	formats, err := propagation.ParseFormats(os.Getenv("TRACING_PROPAGATION"))
+	if err != nil {
+		log.Warnf("Unable to parse TRACING_PROPAGATION, using jaeger: %v", err)
+		formats = nil
+	}
+	httpPropagator := &propagation.Propagator{Formats: formats, URLEncoding: true}
+	textPropagator := &propagation.Propagator{Formats: formats}
+
 	Tracer, Closer, err = cfg.NewTracer(
 		config.Metrics(prometheus.New()),
+		config.Injector(opentracing.HTTPHeaders, httpPropagator),
+		config.Extractor(opentracing.HTTPHeaders, httpPropagator),
+		config.Injector(opentracing.TextMap, textPropagator),
+		config.Extractor(opentracing.TextMap, textPropagator),
 	)
 	opentracing.SetGlobalTracer(Tracer)
 	if err != nil {
@@ -0,0 +1,140 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var paceCacheTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("cache_total"),
+		Help: "Collects stats about the number of cache lookups partitioned by result",
+	},
+	[]string{"cache", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceCacheTotal)
+}
+
+// DefaultSize is the maximum number of entries of caches created with New
+const DefaultSize = 10000
+
+// Cache is an in-memory cache, entries expire after the ttl of the cache.
+// The least recently used entries are evicted if the size is exceeded.
+type Cache struct {
+	name    string
+	origin  string // identifies the cache in invalidations
+	entries *lru
+
+	mu          sync.RWMutex
+	invalidator Invalidator
+}
+
+// New creates a cache with the passed name and DefaultSize, the name
+// identifies the cache across replicas and is used as metric label.
+// A ttl of 0 disables the expiration.
+func New(name string, ttl time.Duration) *Cache {
+	return NewWithSize(name, ttl, DefaultSize)
+}
+
+// NewWithSize creates a cache (see New) with at most size entries
+func NewWithSize(name string, ttl time.Duration, size int) *Cache {
+	return &Cache{
+		name:    name,
+		origin:  newOrigin(),
+		entries: newLRU(size, ttl),
+	}
+}
+
+// Name returns the name of the cache
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// Get returns the value of key if it is cached and not expired
+func (c *Cache) Get(key string) (interface{}, bool) {
+	v, ok := c.entries.get(key)
+	if !ok {
+		paceCacheTotal.With(prometheus.Labels{"cache": c.name, "result": "miss"}).Inc()
+		return nil, false
+	}
+	paceCacheTotal.With(prometheus.Labels{"cache": c.name, "result": "hit"}).Inc()
+	return v, true
+}
+
+// Set caches the value of key
+func (c *Cache) Set(key string, value interface{}) {
+	c.entries.set(key, value)
+}
+
+// Delete removes the passed keys from the local cache only,
+// use Invalidate to remove them from all replicas
+func (c *Cache) Delete(keys ...string) {
+	c.entries.delete(keys...)
+}
+
+// Len returns the number of cached entries (including expired entries
+// that were not removed yet)
+func (c *Cache) Len() int {
+	return c.entries.len()
+}
+
+// Invalidate removes the passed keys from the cache and publishes the
+// invalidation to the caches of the other replicas if the cache is
+// replicated. If no keys are passed, all entries are invalidated.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	c.Delete(keys...)
+
+	c.mu.RLock()
+	invalidator := c.invalidator
+	c.mu.RUnlock()
+	if invalidator == nil {
+		return nil
+	}
+
+	return publish(ctx, invalidator, Invalidation{Origin: c.origin, Cache: c.name, Keys: keys})
+}
+
+// Replicate subscribes the cache to the invalidations of the other
+// replicas and publishes the invalidations of the cache using inv.
+// The subscription ends with ctx.
+func (c *Cache) Replicate(ctx context.Context, inv Invalidator) error {
+	err := inv.Subscribe(ctx, c.apply)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.invalidator = inv
+	c.mu.Unlock()
+	return nil
+}
+
+// apply removes the keys of invalidations published by other replicas
+func (c *Cache) apply(inv Invalidation) {
+	if inv.Cache != c.name || inv.Origin == c.origin {
+		return
+	}
+	c.Delete(inv.Keys...)
+	paceCacheInvalidationTotal.With(prometheus.Labels{"cache": c.name, "direction": "received"}).Inc()
+}
+
+// newOrigin returns a random id
+func newOrigin() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/pkg/cache"
+	"github.com/pace/bricks/pkg/cache/cachetest"
+)
+
+func TestCache(t *testing.T) {
+	c := cache.New("test", 20*time.Millisecond)
+	c.Set("a", 1)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("expected cached value 1, got %v (%v)", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected miss for unknown key")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected entry to be expired")
+	}
+}
+
+func TestCacheSize(t *testing.T) {
+	c := cache.NewWithSize("test", 0, 2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+}
+
+func TestInvalidateWithoutReplication(t *testing.T) {
+	c := cache.New("test", 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	if err := c.Invalidate(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected a to be invalidated")
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected 1 entry, got %d", c.Len())
+	}
+}
+
+func TestBusConsistency(t *testing.T) {
+	bus := cache.NewBus()
+	cachetest.Consistency(t, 3, func() cache.Invalidator { return bus })
+}
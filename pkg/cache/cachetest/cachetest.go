@@ -0,0 +1,108 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package cachetest implements a harness that checks the consistency of
+// replicated caches using an Invalidator implementation.
+package cachetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/pkg/cache"
+)
+
+// Timeout in which the invalidations need to be applied by all replicas
+var Timeout = 5 * time.Second
+
+// Consistency simulates the passed number of replicas, each with its own
+// cache and invalidator created by newInvalidator. It checks that keys
+// invalidated by one replica are removed from the caches of all other
+// replicas, also if all replicas invalidate concurrently.
+func Consistency(t *testing.T, replicas int, newInvalidator func() cache.Invalidator) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	name := fmt.Sprintf("cachetest-%d", time.Now().UnixNano())
+	caches := make([]*cache.Cache, replicas)
+	for i := range caches {
+		caches[i] = cache.New(name, 0)
+		err := caches[i].Replicate(ctx, newInvalidator())
+		if err != nil {
+			t.Fatalf("replica %d failed to subscribe: %v", i, err)
+		}
+	}
+
+	set := func(keys ...string) {
+		for _, c := range caches {
+			for _, key := range keys {
+				c.Set(key, key)
+			}
+		}
+	}
+
+	// single writer
+	set("a", "b")
+	if err := caches[0].Invalidate(ctx, "a"); err != nil {
+		t.Fatalf("failed to invalidate: %v", err)
+	}
+	waitFor(t, caches, "a", false)
+	for i, c := range caches {
+		if _, ok := c.Get("b"); !ok {
+			t.Errorf("replica %d lost key that was not invalidated", i)
+		}
+	}
+
+	// concurrent writers
+	keys := make([]string, replicas)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	set(keys...)
+	var wg sync.WaitGroup
+	for i, c := range caches {
+		wg.Add(1)
+		go func(c *cache.Cache, key string) {
+			defer wg.Done()
+			if err := c.Invalidate(ctx, key); err != nil {
+				t.Errorf("failed to invalidate: %v", err)
+			}
+		}(c, keys[i])
+	}
+	wg.Wait()
+	for _, key := range keys {
+		waitFor(t, caches, key, false)
+	}
+
+	// invalidation of all keys
+	set("c")
+	if err := caches[replicas-1].Invalidate(ctx); err != nil {
+		t.Fatalf("failed to invalidate: %v", err)
+	}
+	waitFor(t, caches, "b", false)
+	waitFor(t, caches, "c", false)
+}
+
+// waitFor waits until key is cached (or not) by all caches
+func waitFor(t *testing.T, caches []*cache.Cache, key string, cached bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(Timeout)
+	for i, c := range caches {
+		for {
+			if _, ok := c.Get(key); ok == cached {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("replica %d: expected key %q cached=%v within %v", i, key, cached, Timeout)
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
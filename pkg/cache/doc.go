@@ -0,0 +1,41 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package cache implements size bounded in-memory caches with expiration
+// whose entries can be invalidated across all replicas of a service. A replica that
+// writes invalidates the affected keys, the invalidation is published
+// using an Invalidator (e.g. redis.CacheInvalidator) and applied by the
+// caches of the other replicas.
+//
+//	users := cache.New("users", time.Minute)
+//	err := users.Replicate(ctx, redis.NewCacheInvalidator(client, "my-service:cache"))
+//	...
+//	// after the user was updated
+//	err = users.Invalidate(ctx, userID)
+//
+// Tiered caches keep an in-memory LRU tier in front of a shared store
+// (e.g. redis.CacheStore), the values are serialized using a Codec:
+//
+//	users := cache.NewTiered("users", redis.NewCacheStore(client, "my-service:users:"), cache.TieredOptions{TTL: time.Hour})
+//	var u User
+//	err := users.GetOrCompute(ctx, userID, &u, func(ctx context.Context) (interface{}, error) {
+//		return loadUser(ctx, userID)
+//	})
+//
+// Concurrent GetOrCompute calls for the same key compute the value once, with
+// TieredOptions.Locker (e.g. redis.CacheLocker) once across the replicas.
+//
+// Warm-up loaders preload caches at the start, the service is not ready
+// until every loader succeeded once:
+//
+//	cache.RegisterWarmUp("token keys", loadTokenKeys, cache.WarmUpOptions{Interval: time.Hour})
+//	go func() {
+//		if err := cache.WarmUp(ctx); err != nil {
+//			log.Ctx(ctx).Warn().Err(err).Msg("Cache warm-up incomplete")
+//		}
+//	}()
+//
+// Failed loaders are retried in their RetryInterval, loaders with an
+// Interval run again to refresh the entries. The runs are collected in
+// pace_cache_warmup_total and pace_cache_warmup_duration_seconds.
+package cache
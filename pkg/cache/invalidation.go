@@ -0,0 +1,103 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceCacheInvalidationTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("cache_invalidation_total"),
+			Help: "Collects stats about the number of cache invalidations partitioned by direction (published, received, failed)",
+		},
+		[]string{"cache", "direction"},
+	)
+	paceCacheInvalidationReceivers = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("cache_invalidation_receivers"),
+			Help:    "Collects the number of subscribers a cache invalidation was delivered to (fan-out)",
+			Buckets: []float64{0, 1, 2, 3, 5, 10, 25, 50, 100},
+		},
+		[]string{"cache"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceCacheInvalidationTotal)
+	prometheus.MustRegister(paceCacheInvalidationReceivers)
+}
+
+// Invalidation of cache entries, published by the replica that wrote
+type Invalidation struct {
+	Origin string   `json:"origin"` // cache that published the invalidation
+	Cache  string   `json:"cache"`  // name of the cache
+	Keys   []string `json:"keys"`   // invalidated keys, all if empty
+}
+
+// Invalidator distributes invalidations between the replicas
+type Invalidator interface {
+	// Publish sends the invalidation to all subscribers and returns
+	// the number of subscribers that received it
+	Publish(ctx context.Context, inv Invalidation) (int, error)
+	// Subscribe calls fn for every published invalidation
+	// until ctx is done
+	Subscribe(ctx context.Context, fn func(inv Invalidation)) error
+}
+
+func publish(ctx context.Context, invalidator Invalidator, inv Invalidation) error {
+	receivers, err := invalidator.Publish(ctx, inv)
+	if err != nil {
+		paceCacheInvalidationTotal.With(prometheus.Labels{"cache": inv.Cache, "direction": "failed"}).Inc()
+		return err
+	}
+	paceCacheInvalidationTotal.With(prometheus.Labels{"cache": inv.Cache, "direction": "published"}).Inc()
+	paceCacheInvalidationReceivers.With(prometheus.Labels{"cache": inv.Cache}).Observe(float64(receivers))
+	return nil
+}
+
+// Bus is an Invalidator that distributes invalidations within the
+// process, e.g. between multiple caches in tests
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[int]func(inv Invalidation)
+	next int
+}
+
+// NewBus creates a new in-process bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[int]func(inv Invalidation))}
+}
+
+// Publish calls all subscribers synchronously
+func (b *Bus) Publish(ctx context.Context, inv Invalidation) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.subs {
+		fn(inv)
+	}
+	return len(b.subs), nil
+}
+
+// Subscribe adds fn to the subscribers until ctx is done
+func (b *Bus) Subscribe(ctx context.Context, fn func(inv Invalidation)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}()
+	return nil
+}
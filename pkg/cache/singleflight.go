@@ -0,0 +1,72 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Locker coordinates the computation of values between the replicas
+// of a service, e.g. redis.CacheLocker
+type Locker interface {
+	// TryLock acquires the lock of key that expires after ttl, false is
+	// returned if the lock is held by another replica. The returned
+	// func releases the lock.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// call is an in-flight computation of a flightGroup
+type call struct {
+	wg   sync.WaitGroup
+	data []byte
+	err  error
+}
+
+// flightGroup coalesces concurrent computations of the same key
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do executes fn once for concurrent calls with the same key, the
+// callers that waited for the computation of another caller share
+// its result and get true. If fn panics, the waiting callers get the
+// panic as error and the panic is propagated to the calling caller.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.data, true, c.err
+	}
+	c := new(call)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.data, c.err = nil, fmt.Errorf("cache: computation of %q panicked: %v", key, r)
+			g.finish(key, c)
+			panic(r)
+		}
+		g.finish(key, c)
+	}()
+	c.data, c.err = fn()
+	return c.data, false, c.err
+}
+
+// finish releases the callers waiting for c
+func (g *flightGroup) finish(key string, c *call) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	c.wg.Done()
+}
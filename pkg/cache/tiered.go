@@ -0,0 +1,373 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// tiers of the tiered cache, used as metric label
+const (
+	tierLocal = "local"
+	tierStore = "store"
+)
+
+// results of GetOrCompute for uncached keys, used as metric label
+const (
+	computeComputed  = "computed"
+	computeCoalesced = "coalesced"
+	computeWaited    = "waited"
+)
+
+// storePollInterval in which the store is checked for a value
+// computed by another replica
+var storePollInterval = 50 * time.Millisecond
+
+var (
+	paceCacheTierTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("cache_tier_total"),
+			Help: "Collects stats about the number of tiered cache lookups partitioned by tier and result",
+		},
+		[]string{"cache", "tier", "result"},
+	)
+	paceCacheStoreDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("cache_store_duration_seconds"),
+			Help:    "Collect performance metrics of the shared store of tiered caches for each operation",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"cache", "op"},
+	)
+	paceCacheComputeTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("cache_compute_total"),
+			Help: "Collects stats about the number of values of uncached keys partitioned by result (computed, coalesced, waited)",
+		},
+		[]string{"cache", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceCacheTierTotal)
+	prometheus.MustRegister(paceCacheStoreDurationSeconds)
+	prometheus.MustRegister(paceCacheComputeTotal)
+}
+
+// Store is the shared tier of a Tiered cache, e.g. redis.CacheStore
+type Store interface {
+	// Get returns the value of key, false if it doesn't exist
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of key that expires after ttl
+	// (never if 0)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the passed keys
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Codec serializes the values of a Tiered cache
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSON encodes the values using encoding/json
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// TieredOptions configure a Tiered cache
+type TieredOptions struct {
+	// TTL of the entries in the store, 0 disables the expiration
+	TTL time.Duration
+	// LocalTTL of the entries in the in-memory tier, defaults to the TTL.
+	// Without replication (see Tiered.Replicate) it is the maximum time
+	// a replica may return values changed by another replica.
+	LocalTTL time.Duration
+	// LocalSize is the maximum number of entries of the in-memory tier,
+	// the least recently used entries are evicted. Defaults to 1000.
+	LocalSize int
+	// Codec serializes the values, defaults to JSON
+	Codec Codec
+	// Locker makes sure that only one replica computes the value of an
+	// uncached key, the other replicas wait for the value in the store.
+	// Concurrent computations within a replica are always coalesced.
+	Locker Locker
+	// LockTTL is the maximum time a replica waits for the value computed
+	// by another replica before it computes the value itself, it should
+	// be longer than the computation. Defaults to 5s.
+	LockTTL time.Duration
+}
+
+// Tiered is a cache with an in-memory LRU tier in front of a shared store
+// (e.g. redis). Values are serialized using the codec, so that the cached
+// values can't be modified by the callers.
+type Tiered struct {
+	name   string
+	store  Store
+	codec  Codec
+	ttl    time.Duration
+	local  *lru
+	origin string
+
+	flight  flightGroup
+	locker  Locker
+	lockTTL time.Duration
+
+	mu          sync.RWMutex
+	invalidator Invalidator
+}
+
+// NewTiered creates a tiered cache with the passed name, the name is
+// used as metric label and should be used as prefix of the store
+func NewTiered(name string, store Store, opts TieredOptions) *Tiered {
+	if opts.LocalTTL == 0 {
+		opts.LocalTTL = opts.TTL
+	}
+	if opts.LocalSize == 0 {
+		opts.LocalSize = 1000
+	}
+	if opts.Codec == nil {
+		opts.Codec = JSON
+	}
+	if opts.LockTTL == 0 {
+		opts.LockTTL = 5 * time.Second
+	}
+	return &Tiered{
+		name:    name,
+		store:   store,
+		codec:   opts.Codec,
+		ttl:     opts.TTL,
+		local:   newLRU(opts.LocalSize, opts.LocalTTL),
+		origin:  newOrigin(),
+		locker:  opts.Locker,
+		lockTTL: opts.LockTTL,
+	}
+}
+
+// Name returns the name of the cache
+func (t *Tiered) Name() string {
+	return t.name
+}
+
+// Get decodes the cached value of key into v and returns true if the key
+// is cached. The in-memory tier is filled with values found in the store.
+func (t *Tiered) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	data, ok, err := t.get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, t.codec.Unmarshal(data, v)
+}
+
+func (t *Tiered) get(ctx context.Context, key string) ([]byte, bool, error) {
+	if data, ok := t.local.get(key); ok {
+		t.count(tierLocal, "hit")
+		paceCacheTotal.With(prometheus.Labels{"cache": t.name, "result": "hit"}).Inc()
+		return data.([]byte), true, nil
+	}
+	t.count(tierLocal, "miss")
+
+	start := time.Now()
+	data, ok, err := t.store.Get(ctx, key)
+	t.observe("get", start)
+	switch {
+	case err != nil:
+		t.count(tierStore, "error")
+		paceCacheTotal.With(prometheus.Labels{"cache": t.name, "result": "miss"}).Inc()
+		return nil, false, err
+	case !ok:
+		t.count(tierStore, "miss")
+		paceCacheTotal.With(prometheus.Labels{"cache": t.name, "result": "miss"}).Inc()
+		return nil, false, nil
+	}
+
+	t.count(tierStore, "hit")
+	paceCacheTotal.With(prometheus.Labels{"cache": t.name, "result": "hit"}).Inc()
+	t.local.set(key, data)
+	return data, true, nil
+}
+
+// Set caches the value of key in both tiers, the in-memory tiers of
+// the other replicas are invalidated if the cache is replicated
+func (t *Tiered) Set(ctx context.Context, key string, v interface{}) error {
+	data, err := t.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return t.set(ctx, key, data)
+}
+
+func (t *Tiered) set(ctx context.Context, key string, data []byte) error {
+	start := time.Now()
+	err := t.store.Set(ctx, key, data, t.ttl)
+	t.observe("set", start)
+	if err != nil {
+		t.local.delete(key)
+		return err
+	}
+
+	t.local.set(key, data)
+	return t.publish(ctx, key)
+}
+
+// Delete removes the passed keys from both tiers, the in-memory tiers
+// of the other replicas are invalidated if the cache is replicated. If
+// no keys are passed, the local in-memory tier is cleared.
+func (t *Tiered) Delete(ctx context.Context, keys ...string) error {
+	t.local.delete(keys...)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := t.store.Delete(ctx, keys...)
+	t.observe("delete", start)
+	if err != nil {
+		return err
+	}
+	return t.publish(ctx, keys...)
+}
+
+// GetOrCompute decodes the cached value of key into v. If the key is not
+// cached, the value is computed using fn and cached. If the store is not
+// available, the value is computed and the error is only logged.
+//
+// Concurrent calls for the same key compute the value only once, the
+// other callers share its result (including errors and the cancellation
+// of the context of the first caller). If fn panics, the panic is
+// propagated to the first caller, the other callers get an error. With
+// a Locker, the value is computed only once across the replicas.
+func (t *Tiered) GetOrCompute(ctx context.Context, key string, v interface{}, fn func(ctx context.Context) (interface{}, error)) error {
+	data, ok, err := t.get(ctx, key)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("cache", t.name).Str("key", key).Msg("Failed to get cached value")
+	}
+	if ok {
+		return t.codec.Unmarshal(data, v)
+	}
+
+	data, shared, err := t.flight.do(key, func() ([]byte, error) {
+		return t.compute(ctx, key, fn)
+	})
+	if shared {
+		paceCacheComputeTotal.With(prometheus.Labels{"cache": t.name, "result": computeCoalesced}).Inc()
+	}
+	if err != nil {
+		return err
+	}
+	return t.codec.Unmarshal(data, v)
+}
+
+// compute computes and caches the value of key. With a locker, the value
+// computed by the replica holding the lock is used if it is available
+// within the lock ttl.
+func (t *Tiered) compute(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	if t.locker != nil {
+		release, ok, err := t.locker.TryLock(ctx, key, t.lockTTL)
+		switch {
+		case err != nil:
+			log.Ctx(ctx).Warn().Err(err).Str("cache", t.name).Str("key", key).Msg("Failed to lock cache key")
+		case ok:
+			defer release()
+			// the value may have been cached before the lock was acquired
+			if data, ok, err := t.store.Get(ctx, key); err == nil && ok {
+				t.local.set(key, data)
+				paceCacheComputeTotal.With(prometheus.Labels{"cache": t.name, "result": computeWaited}).Inc()
+				return data, nil
+			}
+		default:
+			if data, ok := t.wait(ctx, key); ok {
+				paceCacheComputeTotal.With(prometheus.Labels{"cache": t.name, "result": computeWaited}).Inc()
+				return data, nil
+			}
+		}
+	}
+
+	value, err := fn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paceCacheComputeTotal.With(prometheus.Labels{"cache": t.name, "result": computeComputed}).Inc()
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.set(ctx, key, data); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("cache", t.name).Str("key", key).Msg("Failed to cache value")
+	}
+	return data, nil
+}
+
+// wait polls the store for the value of key until the lock
+// ttl passed or ctx is done
+func (t *Tiered) wait(ctx context.Context, key string) ([]byte, bool) {
+	deadline := time.Now().Add(t.lockTTL)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(storePollInterval):
+		}
+
+		data, ok, err := t.store.Get(ctx, key)
+		if err != nil {
+			return nil, false
+		}
+		if ok {
+			t.local.set(key, data)
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// Replicate subscribes the in-memory tier to the invalidations of the
+// other replicas and publishes the keys changed by Set and Delete using
+// inv, so that the other replicas don't return stale values from their
+// in-memory tier. The subscription ends with ctx.
+func (t *Tiered) Replicate(ctx context.Context, inv Invalidator) error {
+	err := inv.Subscribe(ctx, func(i Invalidation) {
+		if i.Cache != t.name || i.Origin == t.origin {
+			return
+		}
+		t.local.delete(i.Keys...)
+		paceCacheInvalidationTotal.With(prometheus.Labels{"cache": t.name, "direction": "received"}).Inc()
+	})
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.invalidator = inv
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *Tiered) publish(ctx context.Context, keys ...string) error {
+	t.mu.RLock()
+	invalidator := t.invalidator
+	t.mu.RUnlock()
+	if invalidator == nil {
+		return nil
+	}
+	return publish(ctx, invalidator, Invalidation{Origin: t.origin, Cache: t.name, Keys: keys})
+}
+
+func (t *Tiered) count(tier, result string) {
+	paceCacheTierTotal.With(prometheus.Labels{"cache": t.name, "tier": tier, "result": result}).Inc()
+}
+
+func (t *Tiered) observe(op string, start time.Time) {
+	paceCacheStoreDurationSeconds.With(prometheus.Labels{"cache": t.name, "op": op}).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,309 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/pkg/cache"
+)
+
+// mapStore is an in-memory store that counts the lookups
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	gets int
+	err  error
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: make(map[string][]byte)}
+}
+
+func (s *mapStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	data, ok := s.data[key]
+	return data, ok, nil
+}
+
+func (s *mapStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = value
+	return nil
+}
+
+func (s *mapStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.data, key)
+	}
+	return nil
+}
+
+type user struct {
+	Name string
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	c := cache.NewTiered("test-tiered", store, cache.TieredOptions{TTL: time.Minute, LocalSize: 1})
+
+	if err := c.Set(ctx, "a", user{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// served by the in-memory tier
+	var u user
+	if ok, err := c.Get(ctx, "a", &u); err != nil || !ok || u.Name != "a" {
+		t.Fatalf("expected cached user a, got %v (%v, %v)", u, ok, err)
+	}
+	if store.gets != 0 {
+		t.Errorf("expected no store lookup, got %d", store.gets)
+	}
+
+	// a is evicted from the in-memory tier and served by the store
+	if err := c.Set(ctx, "b", user{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.Get(ctx, "a", &u); err != nil || !ok || u.Name != "a" {
+		t.Fatalf("expected cached user a, got %v (%v, %v)", u, ok, err)
+	}
+	if store.gets != 1 {
+		t.Errorf("expected one store lookup, got %d", store.gets)
+	}
+
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := c.Get(ctx, "a", &u); ok {
+		t.Error("expected a to be deleted")
+	}
+}
+
+func TestTieredGetOrCompute(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	c := cache.NewTiered("test-tiered-compute", store, cache.TieredOptions{})
+
+	computed := 0
+	compute := func(ctx context.Context) (interface{}, error) {
+		computed++
+		return user{Name: "a"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		var u user
+		if err := c.GetOrCompute(ctx, "a", &u, compute); err != nil || u.Name != "a" {
+			t.Fatalf("expected user a, got %v (%v)", u, err)
+		}
+	}
+	if computed != 1 {
+		t.Errorf("expected one computation, got %d", computed)
+	}
+
+	// the value is computed if the store is not available
+	store.err = errors.New("unavailable")
+	var u user
+	if err := c.GetOrCompute(ctx, "b", &u, compute); err != nil || u.Name != "a" {
+		t.Fatalf("expected user a, got %v (%v)", u, err)
+	}
+	if computed != 2 {
+		t.Errorf("expected two computations, got %d", computed)
+	}
+}
+
+func TestTieredReplicate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := cache.NewBus()
+	store := newMapStore()
+	a := cache.NewTiered("test-tiered-replicate", store, cache.TieredOptions{})
+	b := cache.NewTiered("test-tiered-replicate", store, cache.TieredOptions{})
+	for _, c := range []*cache.Tiered{a, b} {
+		if err := c.Replicate(ctx, bus); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var u user
+	if err := a.Set(ctx, "a", user{Name: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.Get(ctx, "a", &u); !ok || u.Name != "old" {
+		t.Fatalf("expected user old, got %v", u)
+	}
+
+	// b doesn't return the stale value of its in-memory tier
+	if err := a.Set(ctx, "a", user{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.Get(ctx, "a", &u); !ok || u.Name != "new" {
+		t.Fatalf("expected user new, got %v", u)
+	}
+}
+
+func TestTieredGetOrComputeCoalesced(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewTiered("test-tiered-coalesced", newMapStore(), cache.TieredOptions{})
+
+	var mu sync.Mutex
+	computed := 0
+	release := make(chan struct{})
+	compute := func(ctx context.Context) (interface{}, error) {
+		mu.Lock()
+		computed++
+		mu.Unlock()
+		<-release
+		return user{Name: "a"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var u user
+			if err := c.GetOrCompute(ctx, "a", &u, compute); err != nil || u.Name != "a" {
+				t.Errorf("expected user a, got %v (%v)", u, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if computed != 1 {
+		t.Errorf("expected one computation, got %d", computed)
+	}
+}
+
+func TestTieredGetOrComputePanic(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewTiered("test-tiered-panic", newMapStore(), cache.TieredOptions{})
+
+	release := make(chan struct{})
+	compute := func(ctx context.Context) (interface{}, error) {
+		<-release
+		panic("boom")
+	}
+
+	var (
+		mu             sync.Mutex
+		panics, failed int
+		wg             sync.WaitGroup
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					panics++
+					mu.Unlock()
+				}
+			}()
+			var u user
+			if err := c.GetOrCompute(ctx, "a", &u, compute); err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// the computing caller panics, the waiting callers get an error
+	if panics != 1 || failed != 4 {
+		t.Errorf("expected 1 panic and 4 errors, got %d panics and %d errors", panics, failed)
+	}
+}
+
+// mapLocker is an in-memory locker shared by multiple caches
+type mapLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *mapLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.held, key)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+func TestTieredGetOrComputeLocker(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	locker := &mapLocker{held: make(map[string]bool)}
+	a := cache.NewTiered("test-tiered-locker", store, cache.TieredOptions{Locker: locker})
+	b := cache.NewTiered("test-tiered-locker", store, cache.TieredOptions{Locker: locker})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var u user
+		err := a.GetOrCompute(ctx, "a", &u, func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			return user{Name: "a"}, nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+
+	// b waits for the value computed by a
+	<-started
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+	}()
+	var u user
+	err := b.GetOrCompute(ctx, "a", &u, func(ctx context.Context) (interface{}, error) {
+		t.Error("expected b not to compute the value")
+		return user{Name: "b"}, nil
+	})
+	<-done
+	if err != nil || u.Name != "a" {
+		t.Errorf("expected user a, got %v (%v)", u, err)
+	}
+
+	// the value is computed if the lock holder doesn't cache it in time
+	c := cache.NewTiered("test-tiered-locker", store, cache.TieredOptions{Locker: locker, LockTTL: 100 * time.Millisecond})
+	releaseLock, _, _ := locker.TryLock(ctx, "b", time.Minute) // nolint: errcheck
+	defer releaseLock()
+	if err := c.GetOrCompute(ctx, "b", &u, func(ctx context.Context) (interface{}, error) {
+		return user{Name: "c"}, nil
+	}); err != nil || u.Name != "c" {
+		t.Errorf("expected user c, got %v (%v)", u, err)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceCacheWarmUpTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("cache_warmup_total"),
+			Help: "Collects stats about the number of warm-up loader runs partitioned by loader and result",
+		},
+		[]string{"loader", "result"},
+	)
+	paceCacheWarmUpDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    metric.Name("cache_warmup_duration_seconds"),
+			Help:    "Collect performance metrics for each warm-up loader",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"loader"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceCacheWarmUpTotal)
+	prometheus.MustRegister(paceCacheWarmUpDurationSeconds)
+}
+
+// errNotWarm is the readiness error of loaders that didn't succeed yet
+var errNotWarm = errors.New("cache not warmed up yet")
+
+// Loader preloads cache entries, e.g. reference data or token keys
+type Loader func(ctx context.Context) error
+
+// WarmUpOptions configure a warm-up loader
+type WarmUpOptions struct {
+	// Timeout of a loader run, defaults to 30s
+	Timeout time.Duration
+	// Interval in which the loader runs again after the start, e.g. to
+	// refresh reference data. Defaults to 0 (only at the start).
+	Interval time.Duration
+	// RetryInterval in which a failed loader runs again until it
+	// succeeded once. Defaults to 5s.
+	RetryInterval time.Duration
+}
+
+type warmUp struct {
+	name   string
+	loader Loader
+	opts   WarmUpOptions
+
+	mu   sync.Mutex
+	warm bool
+	err  error
+}
+
+var (
+	warmUpsMu sync.Mutex
+	warmUps   []*warmUp
+)
+
+// RegisterWarmUp adds a loader that is run by WarmUp. The service is not
+// ready (see health.RegisterCheck) until the loader succeeded once.
+func RegisterWarmUp(name string, loader Loader, opts WarmUpOptions) {
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.RetryInterval == 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	w := &warmUp{name: name, loader: loader, opts: opts, err: errNotWarm}
+
+	warmUpsMu.Lock()
+	warmUps = append(warmUps, w)
+	warmUpsMu.Unlock()
+	health.RegisterCheck("cache warm-up "+name, w.ready)
+}
+
+// WarmUp runs all registered loaders concurrently and blocks until every
+// loader finished its first run, the failed loaders are returned as error,
+// panics of loaders are recovered and reported as failures.
+// Afterwards the loaders run in the background until ctx is done: failed
+// loaders are retried, the others run again in their interval.
+func WarmUp(ctx context.Context) error {
+	warmUpsMu.Lock()
+	loaders := make([]*warmUp, len(warmUps))
+	copy(loaders, warmUps)
+	warmUpsMu.Unlock()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+	for _, w := range loaders {
+		wg.Add(1)
+		go func(w *warmUp) {
+			err := w.run(ctx)
+			if err != nil {
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", w.name, err))
+				mu.Unlock()
+			}
+			wg.Done()
+			w.schedule(ctx)
+		}(w)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("cache warm-up failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// schedule runs the loader again until ctx is done
+func (w *warmUp) schedule(ctx context.Context) {
+	for {
+		w.mu.Lock()
+		interval := w.opts.Interval
+		if !w.warm {
+			interval = w.opts.RetryInterval
+		}
+		w.mu.Unlock()
+		if interval <= 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		w.run(ctx) // nolint: errcheck
+	}
+}
+
+func (w *warmUp) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := w.load(ctx)
+	paceCacheWarmUpDurationSeconds.With(prometheus.Labels{"loader": w.name}).Observe(time.Since(start).Seconds())
+
+	result := "success"
+	if err != nil {
+		result = "error"
+		log.Ctx(ctx).Warn().Err(err).Str("loader", w.name).Msg("Cache warm-up failed")
+	}
+	paceCacheWarmUpTotal.With(prometheus.Labels{"loader": w.name, "result": result}).Inc()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil {
+		w.warm = true
+		w.err = nil
+	} else if !w.warm {
+		// stale entries of warm caches are preferred over unavailability
+		w.err = err
+	}
+	return err
+}
+
+// load calls the loader, panics are recovered and returned as error
+func (w *warmUp) load(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cache: loader %q panicked: %v", w.name, r)
+		}
+	}()
+	return w.loader(ctx)
+}
+
+// ready is the readiness check of the loader
+func (w *warmUp) ready(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
@@ -0,0 +1,62 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/maintenance/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmUp(t *testing.T) {
+	var refreshed, attempts, slow int32
+	RegisterWarmUp("reference data", func(ctx context.Context) error {
+		atomic.AddInt32(&refreshed, 1)
+		return nil
+	}, WarmUpOptions{Interval: 10 * time.Millisecond})
+	RegisterWarmUp("token keys", func(ctx context.Context) error {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}, WarmUpOptions{RetryInterval: 30 * time.Millisecond})
+	RegisterWarmUp("slow", func(ctx context.Context) error {
+		if atomic.AddInt32(&slow, 1) > 1 {
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, WarmUpOptions{Timeout: 10 * time.Millisecond, RetryInterval: time.Hour})
+	RegisterWarmUp("broken", func(ctx context.Context) error {
+		panic("nil map")
+	}, WarmUpOptions{RetryInterval: time.Hour})
+
+	failed := health.CheckReadiness(context.Background())
+	assert.Len(t, failed, 4)
+	assert.Equal(t, errNotWarm, failed["cache warm-up reference data"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := WarmUp(ctx)
+	assert.EqualError(t, err, "cache warm-up failed: broken: cache: loader \"broken\" panicked: nil map; "+
+		"slow: context deadline exceeded; token keys: unavailable")
+
+	failed = health.CheckReadiness(context.Background())
+	assert.Equal(t, 3, len(failed))
+	assert.NotContains(t, failed, "cache warm-up reference data")
+
+	// the failed loader is retried, the other one refreshed
+	time.Sleep(80 * time.Millisecond)
+	failed = health.CheckReadiness(context.Background())
+	assert.Equal(t, 2, len(failed))
+	assert.Contains(t, failed, "cache warm-up slow")
+	assert.Contains(t, failed, "cache warm-up broken")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.True(t, atomic.LoadInt32(&refreshed) > 1)
+}
@@ -0,0 +1,17 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package fanout executes the upstream calls of aggregator services
+// concurrently. The timeouts of the calls are derived from the deadline
+// of the parent context, the results are aggregated even if some of the
+// optional calls failed.
+//
+//	results, err := fanout.Do(ctx,
+//		fanout.Task{Name: "user", Fn: fetchUser},
+//		fanout.Task{Name: "offers", Fn: fetchOffers, Budget: 0.5, Optional: true},
+//	)
+//	if err != nil {
+//		return err // a required task failed
+//	}
+//	user := results["user"].Value.(*User)
+package fanout
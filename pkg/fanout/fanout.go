@@ -0,0 +1,161 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package fanout
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	olog "github.com/opentracing/opentracing-go/log"
+	perrors "github.com/pace/bricks/maintenance/errors"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+// ErrPanic is the error of tasks that panicked, the panic is reported
+var ErrPanic = errors.New("task panicked")
+
+// TaskFunc executes an upstream call and returns its result
+type TaskFunc func(ctx context.Context) (interface{}, error)
+
+// Task to be executed concurrently
+type Task struct {
+	// Name of the task, used for the results, errors and tracing
+	Name string
+	// Fn executes the task
+	Fn TaskFunc
+	// Budget is the fraction (0 < Budget <= 1) of the time remaining until
+	// the deadline of the parent context the task may take. 0 uses
+	// the parent deadline.
+	Budget float64
+	// Optional tasks don't fail Do, their errors are only part of the results
+	Optional bool
+}
+
+// Result of a task
+type Result struct {
+	Value    interface{}
+	Err      error
+	Duration time.Duration
+}
+
+// Results of the tasks by name
+type Results map[string]Result
+
+// TaskError is the error of a failed task
+type TaskError struct {
+	Task    string
+	Err     error
+	Timeout bool // the task exceeded its deadline
+}
+
+func (e *TaskError) Error() string {
+	if e.Timeout {
+		return e.Task + ": timeout: " + e.Err.Error()
+	}
+	return e.Task + ": " + e.Err.Error()
+}
+
+// Errors of the failed required tasks
+type Errors []*TaskError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Do executes the tasks concurrently and waits until all tasks are done.
+// If a required task fails, the contexts of the remaining tasks are canceled
+// and the errors of the failed required tasks are returned (Errors). The
+// results contain the results of all tasks, including the ones that failed.
+// All tasks are traced in a single span.
+func Do(ctx context.Context, tasks ...Task) (Results, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Fanout")
+	defer span.Finish()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(Results, len(tasks))
+		errs    Errors
+	)
+	start := time.Now()
+
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task Task) {
+			defer wg.Done()
+
+			res := run(ctx, start, task)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[task.Name] = res
+
+			fields := []olog.Field{
+				olog.String("task", task.Name),
+				olog.Float64("duration", float64(res.Duration)/float64(time.Millisecond)),
+			}
+			if res.Err != nil {
+				fields = append(fields, olog.Error(res.Err))
+				log.Ctx(ctx).Debug().Err(res.Err).Str("task", task.Name).
+					Bool("optional", task.Optional).Msg("Fanout task failed")
+
+				if !task.Optional {
+					errs = append(errs, res.Err.(*TaskError))
+					cancel()
+				}
+			}
+			span.LogFields(fields...)
+		}(task)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		span.LogFields(olog.Error(errs))
+		return results, errs
+	}
+	return results, nil
+}
+
+// run executes the task with the timeout derived from the parent deadline,
+// errors are returned as TaskError
+func run(ctx context.Context, start time.Time, task Task) (res Result) {
+	if deadline, ok := ctx.Deadline(); ok && task.Budget > 0 && task.Budget < 1 {
+		budget := time.Duration(float64(deadline.Sub(start)) * task.Budget)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	begin := time.Now()
+	completed := false
+	defer func() {
+		res.Duration = time.Since(begin)
+		if !completed {
+			res.Err = &TaskError{Task: task.Name, Err: ErrPanic}
+		}
+	}()
+	defer perrors.HandleWithCtx(ctx, "Fanout "+task.Name)
+
+	value, err := task.Fn(ctx)
+	completed = true
+	if err != nil {
+		return Result{Value: value, Err: &TaskError{
+			Task:    task.Name,
+			Err:     err,
+			Timeout: ctx.Err() == context.DeadlineExceeded,
+		}}
+	}
+	return Result{Value: value}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package fanout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func value(v interface{}) TaskFunc {
+	return func(ctx context.Context) (interface{}, error) { return v, nil }
+}
+
+func fail(err error) TaskFunc {
+	return func(ctx context.Context) (interface{}, error) { return nil, err }
+}
+
+func wait(ctx context.Context) (interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestDo(t *testing.T) {
+	results, err := Do(context.Background(),
+		Task{Name: "a", Fn: value(1)},
+		Task{Name: "b", Fn: value("b")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results["a"].Value != 1 || results["b"].Value != "b" {
+		t.Errorf("Unexpected results: %#v", results)
+	}
+}
+
+func TestDoOptionalFailure(t *testing.T) {
+	results, err := Do(context.Background(),
+		Task{Name: "a", Fn: value(1)},
+		Task{Name: "b", Fn: fail(errors.New("unavailable")), Optional: true},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error for failed optional task, got: %v", err)
+	}
+	if results["a"].Value != 1 {
+		t.Errorf("Expected partial result, got: %#v", results["a"])
+	}
+	if terr, ok := results["b"].Err.(*TaskError); !ok || terr.Task != "b" {
+		t.Errorf("Expected task error, got: %#v", results["b"].Err)
+	}
+}
+
+func TestDoRequiredFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results, err := Do(ctx,
+		Task{Name: "a", Fn: fail(errors.New("unavailable"))},
+		Task{Name: "b", Fn: wait, Optional: true},
+	)
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 || errs[0].Task != "a" {
+		t.Fatalf("Expected error of task a, got: %v", err)
+	}
+	// the remaining tasks are canceled
+	if results["b"].Err.(*TaskError).Err != context.Canceled {
+		t.Errorf("Expected task b to be canceled, got: %v", results["b"].Err)
+	}
+}
+
+func TestDoBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	results, err := Do(ctx,
+		Task{Name: "a", Fn: wait, Budget: 0.1, Optional: true},
+		Task{Name: "b", Fn: value(1)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	terr := results["a"].Err.(*TaskError)
+	if !terr.Timeout {
+		t.Errorf("Expected timeout, got: %v", terr)
+	}
+	if d := results["a"].Duration; d > 100*time.Millisecond {
+		t.Errorf("Expected task to be limited to its budget, took: %v", d)
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	results, err := Do(context.Background(),
+		Task{Name: "a", Fn: func(ctx context.Context) (interface{}, error) { panic("boom") }},
+	)
+	if err == nil || results["a"].Err.(*TaskError).Err != ErrPanic {
+		t.Errorf("Expected panic error, got: %v", err)
+	}
+}
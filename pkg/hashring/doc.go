@@ -0,0 +1,20 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package hashring routes keys (e.g. user ids) to the replicas of a
+// service using consistent hashing with virtual nodes, for services that
+// partition background processing across pods. If a replica is added or
+// removed, only the keys of that replica move to other replicas.
+//
+//	ring := hashring.New(0)
+//	w := &hashring.Watcher{
+//		Name:      "billing-workers",
+//		Ring:      ring,
+//		Discovery: hashring.DNSDiscovery("billing-workers-headless"),
+//	}
+//	go w.Run(ctx)
+//
+//	if ring.Owns(os.Getenv("POD_IP"), userID) {
+//		process(userID)
+//	}
+package hashring
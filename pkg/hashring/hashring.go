@@ -0,0 +1,200 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package hashring
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultVirtualNodes is the number of virtual nodes per member
+const DefaultVirtualNodes = 128
+
+// Ring is a consistent hash ring of members, it is safe for concurrent use
+type Ring struct {
+	vnodes int
+
+	mu      sync.RWMutex
+	members []string
+	hashes  []uint64          // sorted hashes of the virtual nodes
+	owners  map[uint64]string // member of the virtual node
+}
+
+// New returns an empty ring with vnodes virtual nodes per member,
+// 0 uses DefaultVirtualNodes. More virtual nodes distribute the
+// keys more evenly.
+func New(vnodes int) *Ring {
+	if vnodes <= 0 {
+		vnodes = DefaultVirtualNodes
+	}
+	return &Ring{vnodes: vnodes, owners: make(map[uint64]string)}
+}
+
+// Set replaces the members of the ring, it returns
+// true if the members changed
+func (r *Ring) Set(members ...string) bool {
+	members = normalize(members)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if equal(r.members, members) {
+		return false
+	}
+	r.build(members)
+	return true
+}
+
+// Add adds the member to the ring, it returns
+// true if it wasn't a member before
+func (r *Ring) Add(member string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if contains(r.members, member) {
+		return false
+	}
+	r.build(normalize(append(append([]string(nil), r.members...), member)))
+	return true
+}
+
+// Remove removes the member from the ring, it returns
+// true if it was a member
+func (r *Ring) Remove(member string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !contains(r.members, member) {
+		return false
+	}
+	members := make([]string, 0, len(r.members)-1)
+	for _, m := range r.members {
+		if m != member {
+			members = append(members, m)
+		}
+	}
+	r.build(members)
+	return true
+}
+
+// Members returns the sorted members of the ring
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.members...)
+}
+
+// Get returns the member the key is routed to,
+// false is returned if the ring is empty
+func (r *Ring) Get(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+	return r.owners[r.hashes[r.search(key)]], true
+}
+
+// GetN returns up to n distinct members for the key, the first
+// one equals Get. The further members are the successors on the
+// ring, e.g. for replicas or fallbacks if the first one fails.
+func (r *Ring) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n > len(r.members) {
+		n = len(r.members)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	result := make([]string, 0, n)
+	for i := r.search(key); len(result) < n; i = (i + 1) % len(r.hashes) {
+		m := r.owners[r.hashes[i]]
+		if !contains(result, m) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Owns returns true if the key is routed to member, e.g. to
+// check if the replica itself needs to process the key
+func (r *Ring) Owns(member, key string) bool {
+	m, ok := r.Get(key)
+	return ok && m == member
+}
+
+// search returns the index of the first virtual node
+// clockwise of the key, r.hashes must not be empty
+func (r *Ring) search(key string) int {
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return i
+}
+
+// build rebuilds the virtual nodes of the sorted members
+func (r *Ring) build(members []string) {
+	r.members = members
+	r.hashes = make([]uint64, 0, len(members)*r.vnodes)
+	r.owners = make(map[uint64]string, len(members)*r.vnodes)
+	for _, m := range members {
+		for i := 0; i < r.vnodes; i++ {
+			h := hash(m + "#" + strconv.Itoa(i))
+			// collisions are resolved by the order of the
+			// members, so all replicas build the same ring
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+			r.owners[h] = m
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// hash returns the first 8 bytes of the sha256 of key. Unlike crc32
+// and fnv, it distributes the virtual nodes of similar member names
+// (e.g. "10.0.0.1#0", "10.0.0.1#1") evenly over the ring.
+func hash(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// normalize returns the sorted members without
+// duplicates and empty members
+func normalize(members []string) []string {
+	result := make([]string, 0, len(members))
+	for _, m := range members {
+		if m != "" && !contains(result, m) {
+			result = append(result, m)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func contains(members []string, member string) bool {
+	for _, m := range members {
+		if m == member {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package hashring
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func distribute(r *Ring, n int) map[string]string {
+	owners := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		key := "user-" + strconv.Itoa(i)
+		owners[key], _ = r.Get(key)
+	}
+	return owners
+}
+
+func TestRing(t *testing.T) {
+	r := New(0)
+	_, ok := r.Get("user-1")
+	assert.False(t, ok)
+	assert.Nil(t, r.GetN("user-1", 2))
+
+	assert.True(t, r.Set("10.0.0.3", "10.0.0.1", "10.0.0.2", "10.0.0.1", ""))
+	assert.False(t, r.Set("10.0.0.2", "10.0.0.3", "10.0.0.1"))
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, r.Members())
+
+	// keys are distributed evenly
+	before := distribute(r, 3000)
+	counts := make(map[string]int)
+	for _, m := range before {
+		counts[m]++
+	}
+	for m, c := range counts {
+		assert.True(t, c > 600 && c < 1400, "%s owns %d keys", m, c)
+	}
+
+	// the order of the members doesn't matter
+	other := New(0)
+	other.Set("10.0.0.2", "10.0.0.3", "10.0.0.1")
+	assert.Equal(t, before, distribute(other, 3000))
+
+	// only the keys of the added member move
+	assert.True(t, r.Add("10.0.0.4"))
+	assert.False(t, r.Add("10.0.0.4"))
+	moved := 0
+	for key, m := range distribute(r, 3000) {
+		if m != before[key] {
+			assert.Equal(t, "10.0.0.4", m)
+			moved++
+		}
+	}
+	assert.True(t, moved > 300 && moved < 1200, "%d keys moved", moved)
+
+	// only the keys of the removed member move
+	assert.True(t, r.Remove("10.0.0.4"))
+	assert.False(t, r.Remove("10.0.0.4"))
+	assert.Equal(t, before, distribute(r, 3000))
+
+	owner, _ := r.Get("user-1")
+	assert.True(t, r.Owns(owner, "user-1"))
+	nodes := r.GetN("user-1", 5)
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, owner, nodes[0])
+	assert.ElementsMatch(t, r.Members(), nodes)
+}
+
+func TestRingSkew(t *testing.T) {
+	r := New(0)
+	r.Set("10.0.0.1:11211", "10.0.0.2:11211", "10.0.0.3:11211", "10.0.0.4:11211", "10.0.0.5:11211")
+
+	counts := make(map[string]int)
+	for _, m := range distribute(r, 100000) {
+		counts[m]++
+	}
+	min, max := 100000, 0
+	for _, c := range counts {
+		if c < min {
+			min = c
+		}
+		if c > max {
+			max = c
+		}
+	}
+	assert.True(t, float64(max)/float64(min) < 1.5, "members own between %d and %d keys", min, max)
+}
+
+func TestWatcher(t *testing.T) {
+	var members []string
+	var discoveryErr error
+	var changes [][]string
+
+	w := &Watcher{
+		Name: "test",
+		Ring: New(16),
+		Discovery: DiscoveryFunc(func(ctx context.Context) ([]string, error) {
+			return members, discoveryErr
+		}),
+		OnChange: func(m []string) { changes = append(changes, m) },
+	}
+	ctx := context.Background()
+
+	members = []string{"b", "a"}
+	assert.True(t, w.Update(ctx))
+	assert.False(t, w.Update(ctx))
+
+	// failed and empty discoveries keep the members
+	discoveryErr = errors.New("lookup failed")
+	assert.False(t, w.Update(ctx))
+	discoveryErr, members = nil, nil
+	assert.False(t, w.Update(ctx))
+	assert.Equal(t, []string{"a", "b"}, w.Ring.Members())
+
+	members = []string{"a"}
+	assert.True(t, w.Update(ctx))
+	assert.Equal(t, [][]string{{"a", "b"}, {"a"}}, changes)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, w.Run(ctx))
+
+	w.Discovery = Static("c")
+	assert.True(t, w.Update(context.Background()))
+	assert.Equal(t, []string{"c"}, w.Ring.Members())
+}
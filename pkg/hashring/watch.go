@@ -0,0 +1,137 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package hashring
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	paceHashringMembers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("hashring_members"),
+			Help: "Number of members of the ring",
+		},
+		[]string{"ring"},
+	)
+	paceHashringChangesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("hashring_changes_total"),
+			Help: "Collects stats about the number of membership changes of the ring",
+		},
+		[]string{"ring"},
+	)
+	paceHashringDiscoveryFailedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("hashring_discovery_failed_total"),
+			Help: "Collects stats about the number of failed discoveries of the members of the ring",
+		},
+		[]string{"ring"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceHashringMembers)
+	prometheus.MustRegister(paceHashringChangesTotal)
+	prometheus.MustRegister(paceHashringDiscoveryFailedTotal)
+}
+
+// Discovery returns the current members of a ring, e.g.
+// the addresses of the replicas of a service
+type Discovery interface {
+	Members(ctx context.Context) ([]string, error)
+}
+
+// DiscoveryFunc implements Discovery using a function
+type DiscoveryFunc func(ctx context.Context) ([]string, error)
+
+// Members calls f
+func (f DiscoveryFunc) Members(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// Static returns a discovery of fixed members
+func Static(members ...string) Discovery {
+	return DiscoveryFunc(func(ctx context.Context) ([]string, error) {
+		return members, nil
+	})
+}
+
+// DNSDiscovery returns a discovery of the addresses host resolves to,
+// e.g. the pod IPs of a headless kubernetes service. The replicas
+// identify themselves using the pod IP (downward API).
+func DNSDiscovery(host string) Discovery {
+	return DiscoveryFunc(func(ctx context.Context) ([]string, error) {
+		return net.DefaultResolver.LookupHost(ctx, host)
+	})
+}
+
+// Watcher updates the members of the ring in intervals
+type Watcher struct {
+	// Name of the ring, used as metric label and in the logs
+	Name string
+	// Ring whose members are updated
+	Ring *Ring
+	// Discovery of the members
+	Discovery Discovery
+	// Interval of the discoveries, defaults to 10s
+	Interval time.Duration
+	// OnChange is called with the new members after the members
+	// changed, e.g. to release the keys no longer owned
+	OnChange func(members []string)
+}
+
+// Run updates the members until ctx is done. If a discovery fails or
+// returns no members, the previous members are kept. Run returns
+// ctx.Err() once ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.Update(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update discovers the members once and updates the ring, it
+// returns true if the members changed
+func (w *Watcher) Update(ctx context.Context) bool {
+	members, err := w.Discovery.Members(ctx)
+	if err == nil && len(members) == 0 {
+		log.Ctx(ctx).Warn().Str("ring", w.Name).Msg("Discovery returned no members, keeping the previous members")
+		return false
+	}
+	if err != nil {
+		paceHashringDiscoveryFailedTotal.With(prometheus.Labels{"ring": w.Name}).Inc()
+		log.Ctx(ctx).Warn().Err(err).Str("ring", w.Name).Msg("Failed to discover members, keeping the previous members")
+		return false
+	}
+
+	if !w.Ring.Set(members...) {
+		return false
+	}
+	members = w.Ring.Members()
+	paceHashringMembers.With(prometheus.Labels{"ring": w.Name}).Set(float64(len(members)))
+	paceHashringChangesTotal.With(prometheus.Labels{"ring": w.Name}).Inc()
+	log.Ctx(ctx).Info().Str("ring", w.Name).Strs("members", members).Msg("Members of the ring changed")
+	if w.OnChange != nil {
+		w.OnChange(members)
+	}
+	return true
+}
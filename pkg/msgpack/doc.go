@@ -0,0 +1,11 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package msgpack encodes and decodes MessagePack (https://msgpack.org)
+// representations of generic values, as produced by decoding JSON into an
+// interface{}: nil, bool, numbers, strings, []interface{} and
+// map[string]interface{}. It is used to exchange JSON documents between
+// services in a more compact binary form. Structured documents are encoded
+// directly with an Encoder, decoded documents can be converted into JSON
+// in one pass with ToJSON. Arrays and maps are decoded up to MaxDepth.
+package msgpack
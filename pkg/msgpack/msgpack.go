@@ -0,0 +1,541 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrTruncated is returned if the data ends within a value
+var ErrTruncated = errors.New("msgpack: unexpected end of data")
+
+// ErrMaxDepth is returned if arrays and maps are nested deeper than MaxDepth
+var ErrMaxDepth = errors.New("msgpack: maximum nesting depth exceeded")
+
+// MaxDepth is the maximum nesting depth of arrays and maps that is decoded,
+// it protects the stack against malicious documents
+const MaxDepth = 100
+
+// Marshal returns the MessagePack encoding of v. Supported are nil, bool,
+// integers, floats, json.Number, string, []byte, []interface{},
+// map[string]interface{} and nested combinations of them.
+func Marshal(v interface{}) ([]byte, error) {
+	var e Encoder
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes the MessagePack encoded data. Maps are returned as
+// map[string]interface{}, arrays as []interface{}, integers as int64 (or
+// uint64 if they exceed the int64 range) and floats as float64.
+func Unmarshal(data []byte) (interface{}, error) {
+	d := decoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d bytes of trailing data", len(d.data)-d.pos)
+	}
+	return v, nil
+}
+
+// ToJSON converts the MessagePack encoded data into JSON in one pass,
+// without decoding it into generic values. Binary data is converted into
+// base64 strings like encoding/json does.
+func ToJSON(data []byte) ([]byte, error) {
+	d := decoder{data: data}
+	var buf bytes.Buffer
+	if err := d.json(&buf); err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d bytes of trailing data", len(d.data)-d.pos)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encoder encodes MessagePack values. Besides the values supported by
+// Marshal, structured documents can be encoded without converting them
+// into generic values first: MapHeader and ArrayHeader are followed by
+// the encoded keys and values or elements.
+type Encoder struct {
+	// Fallback converts values of unsupported types into supported ones,
+	// e.g. using their JSON representation. If nil, unsupported types
+	// are returned as error.
+	Fallback func(v interface{}) (interface{}, error)
+
+	buf bytes.Buffer
+}
+
+// Bytes returns the encoded values
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// MapHeader starts a map of n key value pairs
+func (e *Encoder) MapHeader(n int) {
+	encodeLength(&e.buf, n, 0x80, 16, 0, 0xde, 0xdf)
+}
+
+// ArrayHeader starts an array of n elements
+func (e *Encoder) ArrayHeader(n int) {
+	encodeLength(&e.buf, n, 0x90, 16, 0, 0xdc, 0xdd)
+}
+
+// String encodes the string
+func (e *Encoder) String(s string) {
+	encodeLength(&e.buf, len(s), 0xa0, 32, 0xd9, 0xda, 0xdb)
+	e.buf.WriteString(s)
+}
+
+// Encode encodes v, see Marshal for the supported types
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encode(v, e.Fallback)
+}
+
+func (e *Encoder) encode(v interface{}, fallback func(v interface{}) (interface{}, error)) error {
+	buf := &e.buf
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case int:
+		encodeInt(buf, int64(v))
+	case int8:
+		encodeInt(buf, int64(v))
+	case int16:
+		encodeInt(buf, int64(v))
+	case int32:
+		encodeInt(buf, int64(v))
+	case int64:
+		encodeInt(buf, v)
+	case uint:
+		encodeUint(buf, uint64(v))
+	case uint8:
+		encodeUint(buf, uint64(v))
+	case uint16:
+		encodeUint(buf, uint64(v))
+	case uint32:
+		encodeUint(buf, uint64(v))
+	case uint64:
+		encodeUint(buf, v)
+	case float32:
+		buf.WriteByte(0xca)
+		writeUint32(buf, math.Float32bits(v))
+	case float64:
+		buf.WriteByte(0xcb)
+		writeUint64(buf, math.Float64bits(v))
+	case json.Number:
+		// integers are encoded compact, all other numbers as float
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			encodeInt(buf, i)
+			return nil
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			encodeUint(buf, u)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("msgpack: invalid number %q: %v", v, err)
+		}
+		return e.encode(f, nil)
+	case string:
+		e.String(v)
+	case []byte:
+		encodeLength(buf, len(v), 0, 0, 0xc4, 0xc5, 0xc6)
+		buf.Write(v)
+	case []interface{}:
+		e.ArrayHeader(len(v))
+		for _, elem := range v {
+			if err := e.encode(elem, fallback); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		e.MapHeader(len(v))
+		for k, elem := range v {
+			e.String(k)
+			if err := e.encode(elem, fallback); err != nil {
+				return err
+			}
+		}
+	default:
+		if fallback == nil {
+			return fmt.Errorf("msgpack: unsupported type %T", v)
+		}
+		converted, err := fallback(v)
+		if err != nil {
+			return err
+		}
+		// the converted value needs to be supported
+		return e.encode(converted, nil)
+	}
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0:
+		encodeUint(buf, uint64(i))
+	case i >= -32:
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		writeUint16(buf, uint16(i))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		writeUint32(buf, uint32(i))
+	default:
+		buf.WriteByte(0xd3)
+		writeUint64(buf, uint64(i))
+	}
+}
+
+func encodeUint(buf *bytes.Buffer, u uint64) {
+	switch {
+	case u < 128:
+		buf.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		buf.WriteByte(0xcc)
+		buf.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		writeUint16(buf, uint16(u))
+	case u <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		writeUint32(buf, uint32(u))
+	default:
+		buf.WriteByte(0xcf)
+		writeUint64(buf, u)
+	}
+}
+
+// encodeLength writes the header of a string, binary, array or map.
+// The fix format (fix | n) is used for n < fixMax, formats that
+// don't exist for the type are passed as 0.
+func encodeLength(buf *bytes.Buffer, n int, fix byte, fixMax int, f8, f16, f32 byte) {
+	switch {
+	case n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(f8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(f16)
+		writeUint16(buf, uint16(n))
+	default:
+		buf.WriteByte(f32)
+		writeUint32(buf, uint32(n))
+	}
+}
+
+func writeUint16(buf *bytes.Buffer, u uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], u)
+	buf.Write(b[:])
+}
+
+func writeUint32(buf *bytes.Buffer, u uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], u)
+	buf.Write(b[:])
+}
+
+func writeUint64(buf *bytes.Buffer, u uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], u)
+	buf.Write(b[:])
+}
+
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, ErrTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *decoder) value() (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	t := b[0]
+
+	switch {
+	case t <= 0x7f:
+		return int64(t), nil
+	case t >= 0xe0:
+		return int64(int8(t)), nil
+	case t&0xf0 == 0x80:
+		return d.mapping(int(t & 0x0f))
+	case t&0xf0 == 0x90:
+		return d.array(int(t & 0x0f))
+	case t&0xe0 == 0xa0:
+		return d.str(int(t & 0x1f))
+	}
+
+	switch t {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (t - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		p, err := d.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), p...), nil
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (t - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0:
+		u, err := d.uint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := d.uint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := d.uint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := d.uint(8)
+		return int64(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (t - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (t - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (t - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapping(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: unsupported format 0x%x", t)
+}
+
+func (d *decoder) str(n int) (interface{}, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// enter checks the length of an array (elements = n) or map (elements
+// = 2n) and increases the depth, leave needs to be called afterwards
+func (d *decoder) enter(elements int) error {
+	// every element needs at least one byte
+	if elements > len(d.data)-d.pos {
+		return ErrTruncated
+	}
+	d.depth++
+	if d.depth > MaxDepth {
+		return ErrMaxDepth
+	}
+	return nil
+}
+
+func (d *decoder) leave() {
+	d.depth--
+}
+
+func (d *decoder) array(n int) (interface{}, error) {
+	if err := d.enter(n); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	a := make([]interface{}, n)
+	for i := range a {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func (d *decoder) mapping(n int) (interface{}, error) {
+	if err := d.enter(2 * n); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: unsupported map key of type %T", k)
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// container returns the number of elements of the array or map starting
+// at the current position and consumes its header. If there is no array or
+// map, nothing is consumed and ok is false.
+func (d *decoder) container() (isMap bool, n int, ok bool, err error) {
+	if d.pos >= len(d.data) {
+		return false, 0, false, ErrTruncated
+	}
+	t := d.data[d.pos]
+	switch {
+	case t&0xf0 == 0x80:
+		d.pos++
+		return true, int(t & 0x0f), true, nil
+	case t&0xf0 == 0x90:
+		d.pos++
+		return false, int(t & 0x0f), true, nil
+	case t == 0xdc, t == 0xdd, t == 0xde, t == 0xdf:
+		d.pos++
+		u, err := d.uint(2 << ((t - 0xdc) & 1))
+		return t >= 0xde, int(u), true, err
+	}
+	return false, 0, false, nil
+}
+
+// json writes the JSON representation of the current value to buf
+func (d *decoder) json(buf *bytes.Buffer) error {
+	isMap, n, ok, err := d.container()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		v, err := d.value()
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("msgpack: value can't be represented as JSON: %v", err)
+		}
+		buf.Write(data)
+		return nil
+	}
+
+	if !isMap {
+		if err := d.enter(n); err != nil {
+			return err
+		}
+		buf.WriteByte('[')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := d.json(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		d.leave()
+		return nil
+	}
+
+	if err := d.enter(2 * n); err != nil {
+		return err
+	}
+	buf.WriteByte('{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := d.value()
+		if err != nil {
+			return err
+		}
+		if _, ok := k.(string); !ok {
+			return fmt.Errorf("msgpack: unsupported map key of type %T", k)
+		}
+		data, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte(':')
+		if err := d.json(buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	d.leave()
+	return nil
+}
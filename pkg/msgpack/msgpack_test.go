@@ -0,0 +1,157 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package msgpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{7, []byte{0x07}},
+		{-3, []byte{0xfd}},
+		{200, []byte{0xcc, 0xc8}},
+		{-200, []byte{0xd1, 0xff, 0x38}},
+		{json.Number("65536"), []byte{0xce, 0x00, 0x01, 0x00, 0x00}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]interface{}{1, "a"}, []byte{0x92, 0x01, 0xa1, 'a'}},
+		{map[string]interface{}{"a": false}, []byte{0x81, 0xa1, 'a', 0xc2}},
+	}
+	for _, c := range cases {
+		data, err := Marshal(c.value)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, data, "%#v", c.value)
+	}
+
+	_, err := Marshal(struct{}{})
+	assert.Error(t, err)
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"id":   "1",
+			"type": "article",
+			"attributes": map[string]interface{}{
+				"title":  strings.Repeat("x", 300),
+				"tags":   make([]interface{}, 20),
+				"rating": 4.25,
+				"views":  int64(math.MaxInt64),
+				"delta":  int64(math.MinInt64),
+				"big":    uint64(math.MaxUint64),
+				"draft":  false,
+				"binary": bytes.Repeat([]byte{1}, 70000),
+			},
+		},
+	}
+	data, err := Marshal(doc)
+	assert.NoError(t, err)
+
+	v, err := Unmarshal(data)
+	assert.NoError(t, err)
+	attrs := v.(map[string]interface{})["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+	assert.Equal(t, strings.Repeat("x", 300), attrs["title"])
+	assert.Len(t, attrs["tags"], 20)
+	assert.Equal(t, 4.25, attrs["rating"])
+	assert.Equal(t, int64(math.MaxInt64), attrs["views"])
+	assert.Equal(t, int64(math.MinInt64), attrs["delta"])
+	assert.Equal(t, uint64(math.MaxUint64), attrs["big"])
+	assert.Equal(t, false, attrs["draft"])
+	assert.Len(t, attrs["binary"], 70000)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	data, err := Marshal([]interface{}{"abc", 1})
+	assert.NoError(t, err)
+
+	for i := 0; i < len(data); i++ {
+		_, err := Unmarshal(data[:i])
+		assert.Error(t, err, "truncated after %d bytes", i)
+	}
+	_, err = Unmarshal(append(data, 0xc0))
+	assert.Error(t, err)
+	_, err = Unmarshal([]byte{0xdd, 0xff, 0xff, 0xff, 0xff})
+	assert.Equal(t, ErrTruncated, err)
+	_, err = Unmarshal([]byte{0x81, 0x01, 0x01})
+	assert.Error(t, err)
+	_, err = Unmarshal([]byte{0xc1})
+	assert.Error(t, err)
+}
+
+func TestMaxDepth(t *testing.T) {
+	nested := func(depth int) []byte {
+		data := bytes.Repeat([]byte{0x91}, depth)
+		return append(data, 0xc0)
+	}
+
+	_, err := Unmarshal(nested(MaxDepth))
+	assert.NoError(t, err)
+	_, err = Unmarshal(nested(MaxDepth + 1))
+	assert.Equal(t, ErrMaxDepth, err)
+	_, err = ToJSON(nested(MaxDepth + 1))
+	assert.Equal(t, ErrMaxDepth, err)
+	_, err = Unmarshal(bytes.Repeat([]byte{0x81, 0xa1, 'a'}, 100000))
+	assert.Equal(t, ErrMaxDepth, err)
+}
+
+func TestToJSON(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"id": "1", "views": -3, "rating": 1.5, "draft": true, "note": nil},
+		},
+		"binary": []byte("abc"),
+		"quote":  "\"<>",
+	})
+	assert.NoError(t, err)
+
+	data, err = ToJSON(data)
+	assert.NoError(t, err)
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"id": "1", "views": -3.0, "rating": 1.5, "draft": true, "note": nil},
+		},
+		"binary": "YWJj",
+		"quote":  "\"<>",
+	}, doc)
+
+	_, err = ToJSON([]byte{0x92, 0x01})
+	assert.Equal(t, ErrTruncated, err)
+	_, err = ToJSON([]byte{0x81, 0x01, 0x01})
+	assert.Error(t, err)
+	_, err = ToJSON([]byte{0xcb, 0x7f, 0xf8, 0, 0, 0, 0, 0, 1})
+	assert.Error(t, err, "NaN can't be represented as JSON")
+}
+
+func TestEncoderFallback(t *testing.T) {
+	type point struct{ X, Y int }
+	e := Encoder{Fallback: func(v interface{}) (interface{}, error) {
+		p := v.(point)
+		return []interface{}{p.X, p.Y}, nil
+	}}
+	e.MapHeader(1)
+	e.String("p")
+	assert.NoError(t, e.Encode([]interface{}{point{1, 2}}))
+
+	v, err := Unmarshal(e.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"p": []interface{}{[]interface{}{int64(1), int64(2)}}}, v)
+
+	// the converted value needs to be supported
+	e = Encoder{Fallback: func(v interface{}) (interface{}, error) { return v, nil }}
+	assert.Error(t, e.Encode(point{}))
+}
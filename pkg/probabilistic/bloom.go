@@ -0,0 +1,240 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package probabilistic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/go-redis/redis"
+	"github.com/pace/bricks/backend/redis"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of the bloom filter operations, used as metric label
+const (
+	resultPresent = "present"
+	resultAbsent  = "absent"
+	resultError   = "error"
+)
+
+var (
+	paceProbabilisticBloomTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metric.Name("probabilistic_bloom_total"),
+			Help: "Collects stats about the number of bloom filter operations partitioned by filter, op and result",
+		},
+		[]string{"filter", "op", "result"},
+	)
+	paceProbabilisticBloomFallback = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metric.Name("probabilistic_bloom_fallback"),
+			Help: "Is 1 if the bloom filter uses the bitmap fallback because the RedisBloom module isn't loaded",
+		},
+		[]string{"filter"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(paceProbabilisticBloomTotal)
+	prometheus.MustRegister(paceProbabilisticBloomFallback)
+}
+
+// BloomMode is the implementation of a bloom filter
+type BloomMode int
+
+const (
+	// BloomAuto uses the RedisBloom module and falls back
+	// to BloomBitmap if it isn't loaded
+	BloomAuto BloomMode = iota
+	// BloomModule uses the RedisBloom module (BF.* commands)
+	BloomModule
+	// BloomBitmap uses a bitmap of plain redis (SETBIT/GETBIT)
+	BloomBitmap
+)
+
+// maxBits is the maximum size of redis bitmaps (512MB)
+const maxBits = 1 << 32
+
+// bloomModuleAddScript reserves the filter with the capacity ARGV[1] and
+// error rate ARGV[2], expiring after ARGV[3] ms, and adds ARGV[4]
+var bloomModuleAddScript = goredis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	redis.call("BF.RESERVE", KEYS[1], ARGV[2], ARGV[1])
+	if tonumber(ARGV[3]) > 0 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	end
+end
+return redis.call("BF.ADD", KEYS[1], ARGV[4])`)
+
+// bloomBitmapAddScript sets the bits ARGV[2..n], the bitmap expires after
+// ARGV[1] ms after its creation. Returns 1 if any bit wasn't set before.
+var bloomBitmapAddScript = goredis.NewScript(`
+local added = 0
+for i = 2, #ARGV do
+	if redis.call("SETBIT", KEYS[1], ARGV[i], 1) == 0 then
+		added = 1
+	end
+end
+if tonumber(ARGV[1]) > 0 and redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return added`)
+
+// bloomBitmapExistsScript returns 1 if all bits ARGV[1..n] are set
+var bloomBitmapExistsScript = goredis.NewScript(`
+for i = 1, #ARGV do
+	if redis.call("GETBIT", KEYS[1], ARGV[i]) == 0 then
+		return 0
+	end
+end
+return 1`)
+
+// BloomFilter is a bloom filter stored in redis. Items that were added
+// are always reported as present, items that weren't added are reported
+// as present with the false positive probability of the filter.
+type BloomFilter struct {
+	// Name of the filter, used as key and as metric label
+	Name string
+	// Capacity is the number of items for which the ErrorRate is
+	// guaranteed, the error rate grows with further items
+	Capacity int64
+	// ErrorRate is the false positive probability (0 < ErrorRate < 1)
+	ErrorRate float64
+	// TTL of the filter after its creation, 0 keeps it. Use it
+	// with names containing the time window (e.g. the day) to
+	// rotate the filters.
+	TTL time.Duration
+	// Mode defaults to BloomAuto
+	Mode BloomMode
+
+	client   *goredis.Client
+	fallback int32 // set once the module wasn't found
+}
+
+// NewBloomFilter returns a filter for capacity items with the false positive
+// probability errorRate, the filter is created by the first Add
+func NewBloomFilter(client *goredis.Client, name string, capacity int64, errorRate float64) *BloomFilter {
+	return &BloomFilter{
+		Name:      name,
+		Capacity:  capacity,
+		ErrorRate: errorRate,
+		client:    client,
+	}
+}
+
+// Add adds the item to the filter, it returns false if the
+// item was (probably) added before
+func (f *BloomFilter) Add(ctx context.Context, item string) (bool, error) {
+	added, err := f.do(ctx, func(c *goredis.Client, bitmap bool) (int64, error) {
+		if bitmap {
+			args := append([]interface{}{ttlMillis(f.TTL)}, f.positions(item)...)
+			return bloomBitmapAddScript.Run(c, []string{f.Name}, args...).Int64()
+		}
+		return bloomModuleAddScript.Run(c, []string{f.Name},
+			f.Capacity, f.ErrorRate, ttlMillis(f.TTL), item).Int64()
+	})
+	f.observe("add", err, added == 0)
+	return added == 1, err
+}
+
+// Exists returns true if the item was (probably) added to the filter
+func (f *BloomFilter) Exists(ctx context.Context, item string) (bool, error) {
+	exists, err := f.do(ctx, func(c *goredis.Client, bitmap bool) (int64, error) {
+		if bitmap {
+			return bloomBitmapExistsScript.Run(c, []string{f.Name}, f.positions(item)...).Int64()
+		}
+		return c.Do("BF.EXISTS", f.Name, item).Int64()
+	})
+	f.observe("exists", err, exists == 1)
+	return exists == 1, err
+}
+
+// do executes fn with the module or the bitmap, if the module
+// isn't loaded in auto mode fn is executed again with the bitmap
+func (f *BloomFilter) do(ctx context.Context, fn func(c *goredis.Client, bitmap bool) (int64, error)) (int64, error) {
+	if err := f.validate(); err != nil {
+		return 0, err
+	}
+	c := redis.WithContext(ctx, f.client)
+
+	bitmap := f.Mode == BloomBitmap || (f.Mode == BloomAuto && atomic.LoadInt32(&f.fallback) == 1)
+	res, err := fn(c, bitmap)
+	if err == nil || bitmap || f.Mode != BloomAuto || !isUnknownCommand(err) {
+		return res, err
+	}
+
+	if atomic.CompareAndSwapInt32(&f.fallback, 0, 1) {
+		paceProbabilisticBloomFallback.With(prometheus.Labels{"filter": f.Name}).Set(1)
+		log.Ctx(ctx).Info().Str("filter", f.Name).Msg("RedisBloom module not loaded, using bitmap bloom filter")
+	}
+	return fn(c, true)
+}
+
+func (f *BloomFilter) validate() error {
+	if f.Capacity <= 0 || f.ErrorRate <= 0 || f.ErrorRate >= 1 {
+		return fmt.Errorf("probabilistic: invalid bloom filter %q: capacity %d, error rate %v", f.Name, f.Capacity, f.ErrorRate)
+	}
+	return nil
+}
+
+func (f *BloomFilter) observe(op string, err error, present bool) {
+	result := resultAbsent
+	switch {
+	case err != nil:
+		result = resultError
+	case present:
+		result = resultPresent
+	}
+	paceProbabilisticBloomTotal.With(prometheus.Labels{"filter": f.Name, "op": op, "result": result}).Inc()
+}
+
+// positions returns the bits of the item in the bitmap using
+// double hashing of the two halves of the 64 bit hash
+func (f *BloomFilter) positions(item string) []interface{} {
+	bits, hashes := bloomParameters(f.Capacity, f.ErrorRate)
+
+	h := fnv.New64a()
+	h.Write([]byte(item)) // nolint: errcheck
+	sum := h.Sum64()
+	h1, h2 := sum&0xffffffff, sum>>32|1
+
+	positions := make([]interface{}, hashes)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % bits
+	}
+	return positions
+}
+
+// bloomParameters returns the optimal number of bits and hashes
+// of a filter for n items with the false positive probability p
+func bloomParameters(n int64, p float64) (bits uint64, hashes int) {
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	if m > maxBits {
+		m = maxBits
+	}
+	k := int(math.Round(m / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return uint64(m), k
+}
+
+// isUnknownCommand returns true for errors of commands of modules
+// that aren't loaded, called directly or from scripts
+func isUnknownCommand(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown command") || strings.Contains(msg, "unknown redis command")
+}
+
+func ttlMillis(ttl time.Duration) int64 {
+	return int64(ttl / time.Millisecond)
+}
@@ -0,0 +1,24 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package probabilistic implements probabilistic data structures shared
+// by the replicas of a service using redis. Bloom filters answer "have we
+// seen this token/nonce" in constant space with a configurable false
+// positive rate, e.g. for replay protection (see http/security/replay):
+//
+//	nonces := probabilistic.NewBloomFilter(client, "my-service:nonces", 1e6, 0.001)
+//	nonces.TTL = 24 * time.Hour
+//	added, err := nonces.Add(ctx, nonce)
+//	if err == nil && !added {
+//		// the nonce was (probably) used before
+//	}
+//
+// The filters use the RedisBloom module if it is loaded and fall back to
+// bitmaps of plain redis otherwise. HyperLogLogs count unique items
+// (e.g. users per day for analytics, see http/analytics) with a standard
+// error of 0.81%:
+//
+//	users := probabilistic.NewHyperLogLog(client, "my-service:users:"+day)
+//	_, err := users.Add(ctx, userID)
+//	count, err := users.Count(ctx)
+package probabilistic
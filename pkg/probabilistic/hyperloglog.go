@@ -0,0 +1,90 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package probabilistic
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/go-redis/redis"
+	"github.com/pace/bricks/backend/redis"
+	"github.com/pace/bricks/maintenance/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var paceProbabilisticHLLTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: metric.Name("probabilistic_hll_total"),
+		Help: "Collects stats about the number of HyperLogLog operations partitioned by op and result",
+	},
+	[]string{"op", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(paceProbabilisticHLLTotal)
+}
+
+// hllAddScript adds the items ARGV[2..n], the HyperLogLog expires after
+// ARGV[1] ms after its creation. Returns 1 if the estimated count changed.
+var hllAddScript = goredis.NewScript(`
+local changed = redis.call("PFADD", KEYS[1], unpack(ARGV, 2))
+if tonumber(ARGV[1]) > 0 and redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return changed`)
+
+// HyperLogLog counts the unique items added to it
+// with a standard error of 0.81%
+type HyperLogLog struct {
+	// Name of the HyperLogLog, used as key. Names often contain
+	// the time window (e.g. the day) of the count.
+	Name string
+	// TTL of the HyperLogLog after its creation, 0 keeps it
+	TTL time.Duration
+
+	client *goredis.Client
+}
+
+// NewHyperLogLog returns the HyperLogLog name, it is created by the first Add
+func NewHyperLogLog(client *goredis.Client, name string) *HyperLogLog {
+	return &HyperLogLog{Name: name, client: client}
+}
+
+// Add adds the items, it returns true if the estimated count changed
+func (h *HyperLogLog) Add(ctx context.Context, items ...string) (bool, error) {
+	args := make([]interface{}, 0, len(items)+1)
+	args = append(args, ttlMillis(h.TTL))
+	for _, item := range items {
+		args = append(args, item)
+	}
+
+	changed, err := hllAddScript.Run(redis.WithContext(ctx, h.client), []string{h.Name}, args...).Int64()
+	observeHLL("add", err)
+	return changed == 1, err
+}
+
+// Count returns the estimated number of unique items
+func (h *HyperLogLog) Count(ctx context.Context) (int64, error) {
+	return h.CountUnion(ctx)
+}
+
+// CountUnion returns the estimated number of unique items
+// of h and others, e.g. the unique users of a week
+func (h *HyperLogLog) CountUnion(ctx context.Context, others ...*HyperLogLog) (int64, error) {
+	keys := []string{h.Name}
+	for _, o := range others {
+		keys = append(keys, o.Name)
+	}
+	count, err := redis.WithContext(ctx, h.client).PFCount(keys...).Result()
+	observeHLL("count", err)
+	return count, err
+}
+
+func observeHLL(op string, err error) {
+	result := "ok"
+	if err != nil {
+		result = resultError
+	}
+	paceProbabilisticHLLTotal.With(prometheus.Labels{"op": op, "result": result}).Inc()
+}
@@ -0,0 +1,118 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package probabilistic
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pace/bricks/backend/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomParameters(t *testing.T) {
+	bits, hashes := bloomParameters(1000000, 0.01)
+	assert.Equal(t, uint64(9585059), bits)
+	assert.Equal(t, 7, hashes)
+
+	bits, hashes = bloomParameters(1<<40, 0.001)
+	assert.Equal(t, uint64(maxBits), bits)
+	assert.Equal(t, 1, hashes)
+
+	f := NewBloomFilter(nil, "test", 1000, 0.01)
+	positions := f.positions("nonce")
+	assert.Len(t, positions, 7)
+	assert.Equal(t, positions, f.positions("nonce"))
+	assert.NotEqual(t, positions, f.positions("other nonce"))
+	for _, p := range positions {
+		assert.True(t, p.(uint64) < 9586)
+	}
+
+	_, err := NewBloomFilter(nil, "test", 0, 0.01).Exists(context.Background(), "nonce")
+	assert.Error(t, err)
+	_, err = NewBloomFilter(nil, "test", 10, 1).Add(context.Background(), "nonce")
+	assert.Error(t, err)
+
+	assert.True(t, isUnknownCommand(errors.New("ERR unknown command `BF.EXISTS`, with args beginning with: ")))
+	assert.True(t, isUnknownCommand(errors.New("ERR Error running script: @user_script:3: Unknown Redis command called from Lua script")))
+	assert.False(t, isUnknownCommand(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")))
+}
+
+func TestBloomFilter(t *testing.T) {
+	client := redis.Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, mode := range []BloomMode{BloomAuto, BloomBitmap} {
+		f := NewBloomFilter(client, "TestBloomFilter:"+strconv.Itoa(int(mode))+":"+time.Now().String(), 1000, 0.01)
+		f.Mode = mode
+		f.TTL = time.Minute
+
+		added, err := f.Add(ctx, "nonce")
+		assert.NoError(t, err)
+		assert.True(t, added)
+		added, err = f.Add(ctx, "nonce")
+		assert.NoError(t, err)
+		assert.False(t, added)
+
+		exists, err := f.Exists(ctx, "nonce")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		falsePositives := 0
+		for i := 0; i < 1000; i++ {
+			exists, err := f.Exists(ctx, "other-"+strconv.Itoa(i))
+			assert.NoError(t, err)
+			if exists {
+				falsePositives++
+			}
+		}
+		assert.True(t, falsePositives < 20, "%d false positives", falsePositives)
+
+		ttl, err := client.PTTL(f.Name).Result()
+		assert.NoError(t, err)
+		assert.True(t, ttl > 0 && ttl <= time.Minute)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	client := redis.Client()
+	if err := client.Ping().Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	ctx := context.Background()
+	name := "TestHyperLogLog:" + time.Now().String()
+
+	monday := NewHyperLogLog(client, name+":monday")
+	monday.TTL = time.Minute
+	tuesday := NewHyperLogLog(client, name+":tuesday")
+
+	for i := 0; i < 1000; i++ {
+		_, err := monday.Add(ctx, "user-"+strconv.Itoa(i))
+		assert.NoError(t, err)
+	}
+	changed, err := tuesday.Add(ctx, "user-1", "user-1000")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = tuesday.Add(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	count, err := monday.Count(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1000, count, 25)
+
+	count, err = monday.CountUnion(ctx, tuesday)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1001, count, 25)
+
+	ttl, err := client.PTTL(monday.Name).Result()
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute)
+}
@@ -0,0 +1,8 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package reqcontext provides typed accessors for all values that bricks
+// stores in request contexts (request id, logger, tracing span, oauth2
+// token, locale, tenant and route). Services should use this package
+// instead of accessing the context values of the individual packages.
+package reqcontext
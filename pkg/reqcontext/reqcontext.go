@@ -0,0 +1,90 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package reqcontext
+
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/log"
+	"github.com/rs/zerolog"
+)
+
+type contextKey string
+
+const (
+	localeKey contextKey = "locale"
+	tenantKey contextKey = "tenant"
+	routeKey  contextKey = "route"
+)
+
+// RequestID returns the unique request id or an empty string if there is none
+func RequestID(ctx context.Context) string {
+	return log.RequestIDFromContext(ctx)
+}
+
+// Logger returns the request logger
+func Logger(ctx context.Context) *zerolog.Logger {
+	return log.Ctx(ctx)
+}
+
+// Span returns the current tracing span or nil if there is none
+func Span(ctx context.Context) opentracing.Span {
+	return opentracing.SpanFromContext(ctx)
+}
+
+// BearerToken returns the oauth2 bearer token of the request
+func BearerToken(ctx context.Context) (string, bool) {
+	return oauth2.BearerToken(ctx)
+}
+
+// ClientID returns the oauth2 client id of the request
+func ClientID(ctx context.Context) (string, bool) {
+	return oauth2.ClientID(ctx)
+}
+
+// UserID returns the oauth2 user id of the request
+func UserID(ctx context.Context) (string, bool) {
+	return oauth2.UserID(ctx)
+}
+
+// Scopes returns the oauth2 scopes of the request
+func Scopes(ctx context.Context) []string {
+	return oauth2.Scopes(ctx)
+}
+
+// WithLocale returns a new context with the given locale (e.g. "de-DE")
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// Locale returns the locale of the request
+func Locale(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey).(string)
+	return locale, ok
+}
+
+// WithTenant returns a new context with the given tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// Tenant returns the tenant of the request
+func Tenant(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey).(string)
+	return tenant, ok
+}
+
+// WithRoute returns a new context with the given route (name or
+// path template of the matched route)
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// Route returns the route of the request, it is set by the default router
+func Route(ctx context.Context) (string, bool) {
+	route, ok := ctx.Value(routeKey).(string)
+	return route, ok
+}
@@ -0,0 +1,77 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package reqcontext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pace/bricks/http/oauth2"
+	"github.com/pace/bricks/maintenance/log"
+)
+
+func TestEmptyContext(t *testing.T) {
+	ctx := context.Background()
+
+	if id := RequestID(ctx); id != "" {
+		t.Errorf("expected no request id, got: %q", id)
+	}
+	if span := Span(ctx); span != nil {
+		t.Errorf("expected no span, got: %v", span)
+	}
+	if _, ok := ClientID(ctx); ok {
+		t.Error("expected no client id")
+	}
+	if _, ok := Locale(ctx); ok {
+		t.Error("expected no locale")
+	}
+	if _, ok := Tenant(ctx); ok {
+		t.Error("expected no tenant")
+	}
+	if _, ok := Route(ctx); ok {
+		t.Error("expected no route")
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	var ctx context.Context
+	h := log.Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if id := RequestID(ctx); id == "" {
+		t.Error("expected request id")
+	}
+	if Logger(ctx) == nil {
+		t.Error("expected logger")
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "test")
+	defer span.Finish()
+	if Span(ctx) != span {
+		t.Error("expected span of the context")
+	}
+
+	ctx = oauth2.WithBearerToken(ctx, "token")
+	if token, _ := BearerToken(ctx); token != "token" {
+		t.Errorf("expected bearer token %q, got: %q", "token", token)
+	}
+
+	ctx = WithLocale(WithTenant(ctx, "pace"), "de-DE")
+	if locale, _ := Locale(ctx); locale != "de-DE" {
+		t.Errorf("expected locale %q, got: %q", "de-DE", locale)
+	}
+	if tenant, _ := Tenant(ctx); tenant != "pace" {
+		t.Errorf("expected tenant %q, got: %q", "pace", tenant)
+	}
+
+	ctx = WithRoute(ctx, "GetArticles")
+	if route, _ := Route(ctx); route != "GetArticles" {
+		t.Errorf("expected route %q, got: %q", "GetArticles", route)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+// Package validate implements a fluent validator for domain rules
+// that accumulates the errors as jsonapi error objects. The errors have
+// the same format as the errors of the generated request validation.
+//
+//	v := validate.New()
+//	v.String("/data/attributes/name", req.Name).Required().MaxLength(64)
+//	v.Number("/data/attributes/amount", req.Amount).Min(0)
+//	v.Check("/data/attributes/end", req.End.After(req.Start), "must be after start")
+//	v.When(req.Type == "company", func(v *validate.Validator) {
+//		v.String("/data/attributes/vatId", req.VatID).Required()
+//	})
+//	if !v.Write(w) {
+//		return
+//	}
+package validate
@@ -0,0 +1,187 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package validate
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/pace/bricks/http/jsonapi/runtime"
+)
+
+// Validator accumulates validation errors
+type Validator struct {
+	source string
+	errors runtime.Errors
+}
+
+// New creates a validator for the request document,
+// the paths of the fields are json pointers
+func New() *Validator {
+	return &Validator{source: "pointer"}
+}
+
+// NewParameters creates a validator for request parameters,
+// the paths of the fields are parameter names
+func NewParameters() *Validator {
+	return &Validator{source: "parameter"}
+}
+
+// Check adds an error for path with the passed detail if ok is false,
+// use it for cross-field and custom rules
+func (v *Validator) Check(path string, ok bool, detail string) *Validator {
+	if !ok {
+		v.addError(path, detail)
+	}
+	return v
+}
+
+// When executes the rules of fn only if cond is true
+func (v *Validator) When(cond bool, fn func(v *Validator)) *Validator {
+	if cond {
+		fn(v)
+	}
+	return v
+}
+
+// Valid returns true if no rule failed
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Err returns the validation errors (runtime.Errors) or nil
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return v.errors
+}
+
+// Write sends the validation errors to the client (422) and returns
+// false if any rule failed, returns true otherwise
+func (v *Validator) Write(w http.ResponseWriter) bool {
+	if v.Valid() {
+		return true
+	}
+	runtime.WriteError(w, http.StatusUnprocessableEntity, v.errors)
+	return false
+}
+
+func (v *Validator) addError(path, detail string) {
+	name := path[strings.LastIndex(path, "/")+1:]
+	v.errors = append(v.errors, runtime.NewValidationError(v.source, path, name, detail))
+}
+
+// field is the base for all field rules, only the first
+// failed rule of a field is reported
+type field struct {
+	v      *Validator
+	path   string
+	failed bool
+	empty  bool
+}
+
+func (f *field) check(ok bool, format string, args ...interface{}) {
+	if f.failed || ok {
+		return
+	}
+	f.failed = true
+	f.v.addError(f.path, fmt.Sprintf(format, args...))
+}
+
+// StringField contains the rules for string values
+type StringField struct {
+	field
+	value string
+}
+
+// String starts the rules for the string value at path
+func (v *Validator) String(path, value string) *StringField {
+	return &StringField{field: field{v: v, path: path, empty: value == ""}, value: value}
+}
+
+// Required fails if the value is empty
+func (f *StringField) Required() *StringField {
+	f.check(!f.empty, "is required")
+	return f
+}
+
+// MinLength fails if the value has less than n characters
+func (f *StringField) MinLength(n int) *StringField {
+	f.check(f.empty || utf8.RuneCountInString(f.value) >= n, "must have at least %d characters", n)
+	return f
+}
+
+// MaxLength fails if the value has more than n characters
+func (f *StringField) MaxLength(n int) *StringField {
+	f.check(utf8.RuneCountInString(f.value) <= n, "must have at most %d characters", n)
+	return f
+}
+
+// Matches fails if the value doesn't match the regular expression
+func (f *StringField) Matches(re *regexp.Regexp) *StringField {
+	f.check(f.empty || re.MatchString(f.value), "must match %s", re)
+	return f
+}
+
+// OneOf fails if the value is not one of the passed values
+func (f *StringField) OneOf(values ...string) *StringField {
+	ok := f.empty
+	for _, value := range values {
+		ok = ok || f.value == value
+	}
+	f.check(ok, "must be one of %s", strings.Join(values, ", "))
+	return f
+}
+
+// Email fails if the value is not an email address
+func (f *StringField) Email() *StringField {
+	f.check(f.empty || valid.IsEmail(f.value), "must be an email address")
+	return f
+}
+
+// UUID fails if the value is not an uuid
+func (f *StringField) UUID() *StringField {
+	f.check(f.empty || valid.IsUUID(f.value), "must be an uuid")
+	return f
+}
+
+// Func fails with the passed detail if fn returns false for the value
+func (f *StringField) Func(fn func(value string) bool, detail string) *StringField {
+	f.check(f.empty || fn(f.value), "%s", detail)
+	return f
+}
+
+// NumberField contains the rules for numeric values
+type NumberField struct {
+	field
+	value float64
+}
+
+// Number starts the rules for the numeric value at path
+func (v *Validator) Number(path string, value float64) *NumberField {
+	return &NumberField{field: field{v: v, path: path}, value: value}
+}
+
+// Min fails if the value is less than min
+func (f *NumberField) Min(min float64) *NumberField {
+	f.check(f.value >= min, "must be at least %v", min)
+	return f
+}
+
+// Max fails if the value is greater than max
+func (f *NumberField) Max(max float64) *NumberField {
+	f.check(f.value <= max, "must be at most %v", max)
+	return f
+}
+
+// Func fails with the passed detail if fn returns false for the value
+func (f *NumberField) Func(fn func(value float64) bool, detail string) *NumberField {
+	f.check(fn(f.value), "%s", detail)
+	return f
+}
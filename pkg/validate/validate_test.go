@@ -0,0 +1,74 @@
+// Copyright © 2026 by PACE Telematics GmbH. All rights reserved.
+// Created at 2026/10/15
+
+package validate
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/pace/bricks/http/jsonapi/runtime"
+)
+
+func TestValidator(t *testing.T) {
+	v := New()
+	v.String("/data/attributes/name", "").Required().MaxLength(3)
+	v.String("/data/attributes/code", "abcd").MaxLength(3).Matches(regexp.MustCompile("^[0-9]+$"))
+	v.String("/data/attributes/email", "").Email() // optional
+	v.String("/data/attributes/kind", "c").OneOf("a", "b")
+	v.Number("/data/attributes/amount", 5).Min(0).Max(10)
+	v.Check("/data/attributes/end", false, "must be after start")
+	v.When(false, func(v *Validator) {
+		v.String("/data/attributes/vatId", "").Required()
+	})
+
+	errs, ok := v.Err().(runtime.Errors)
+	if !ok {
+		t.Fatalf("Expected runtime errors, got: %v", v.Err())
+	}
+
+	expected := map[string]string{
+		"/data/attributes/name": "is required",
+		"/data/attributes/code": "must have at most 3 characters",
+		"/data/attributes/kind": "must be one of a, b",
+		"/data/attributes/end":  "must be after start",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got: %v", len(expected), errs)
+	}
+	for _, e := range errs {
+		pointer := (*e.Source)["pointer"].(string)
+		if detail, ok := expected[pointer]; !ok || detail != e.Detail {
+			t.Errorf("Unexpected error %q for %s", e.Detail, pointer)
+		}
+	}
+}
+
+func TestValidatorWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if !New().Write(rec) {
+		t.Error("Expected valid validator to write nothing")
+	}
+
+	v := NewParameters()
+	v.Number("limit", 1000).Max(100)
+	if v.Write(rec) {
+		t.Error("Expected invalid validator to write errors")
+	}
+	if rec.Code != 422 {
+		t.Errorf("Expected 422, got: %d", rec.Code)
+	}
+
+	var doc struct {
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	e := doc.Errors[0]
+	if e["title"] != "limit is invalid" || e["source"].(map[string]interface{})["parameter"] != "limit" {
+		t.Errorf("Expected parameter error, got: %v", e)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/caarlos0/env"
+	"github.com/pace/bricks/maintenance/metric"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -19,14 +20,14 @@ type config struct {
 var (
 	paceLivetestTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pace_livetest_total",
+			Name: metric.Name("livetest_total"),
 			Help: "Collects stats about the number of live tests made",
 		},
 		[]string{"service", "result"},
 	)
 	paceLivetestDurationSeconds = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pace_livetest_duration_seconds",
+			Name:    metric.Name("livetest_duration_seconds"),
 			Help:    "Collect performance metrics for each live test",
 			Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 60},
 		},
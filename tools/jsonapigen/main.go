@@ -5,6 +5,7 @@ package main
 
 import (
 	"flag"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -12,12 +13,13 @@ import (
 	"github.com/pace/bricks/maintenance/log"
 )
 
-var pkg, path, source string
+var pkg, path, source, dashboard string
 
 func main() {
 	flag.StringVar(&pkg, "pkg", pkg, "go package name")
 	flag.StringVar(&path, "path", path, "path for generated file")
 	flag.StringVar(&source, "source", source, "source OpenAPIv3 document")
+	flag.StringVar(&dashboard, "dashboard", dashboard, "path for generated grafana dashboard (optional)")
 	flag.Parse()
 
 	var g generator.Generator
@@ -36,4 +38,18 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	if dashboard == "" {
+		return
+	}
+
+	d, err := g.BuildDashboardSource(source, filepath.Base(pkg))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(dashboard, []byte(d), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
 }